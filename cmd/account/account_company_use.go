@@ -1,6 +1,7 @@
 package account
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
@@ -23,21 +24,7 @@ var accountCompanyUseCmd = &cobra.Command{
 
 		for _, membership := range services.AccountClient.GetMemberships() {
 			if membership.Company.Id == companyID {
-				if err := services.Conf.SetAccountCompanyId(companyID); err != nil {
-					return err
-				}
-
-				if err := services.Conf.Save(); err != nil {
-					return err
-				}
-
-				err = accountApi.InvalidateTokenCache()
-				if err != nil {
-					return fmt.Errorf("cannot invalidate token cache: %w", err)
-				}
-
-				logging.FromContext(cmd.Context()).Infof("Successfully changed your company to %s (%s)", membership.Company.Name, membership.Company.CustomerNumber)
-				return nil
+				return useCompany(cmd.Context(), membership)
 			}
 		}
 
@@ -45,6 +32,24 @@ var accountCompanyUseCmd = &cobra.Command{
 	},
 }
 
+func useCompany(ctx context.Context, membership accountApi.Membership) error {
+	if err := services.Conf.SetAccountCompanyId(membership.Company.Id); err != nil {
+		return err
+	}
+
+	if err := services.Conf.Save(); err != nil {
+		return err
+	}
+
+	if err := accountApi.InvalidateTokenCache(); err != nil {
+		return fmt.Errorf("cannot invalidate token cache: %w", err)
+	}
+
+	logging.FromContext(ctx).Infof("Successfully changed your company to %s (%s)", membership.Company.Name, membership.Company.CustomerNumber)
+
+	return nil
+}
+
 func init() {
 	accountCompanyRootCmd.AddCommand(accountCompanyUseCmd)
 }
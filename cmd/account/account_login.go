@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	accountApi "github.com/shopware/shopware-cli/internal/account-api"
+	"github.com/shopware/shopware-cli/internal/interaction"
 	"github.com/shopware/shopware-cli/logging"
 )
 
@@ -21,6 +22,10 @@ var loginCmd = &cobra.Command{
 		newCredentials := false
 
 		if len(email) == 0 || len(password) == 0 {
+			if err := interaction.RequireInput(cmd.Context(), "the account credentials", "set SHOPWARE_CLI_ACCOUNT_EMAIL and SHOPWARE_CLI_ACCOUNT_PASSWORD"); err != nil {
+				return err
+			}
+
 			var err error
 			email, password, err = askUserForEmailAndPassword()
 			if err != nil {
@@ -39,7 +44,7 @@ var loginCmd = &cobra.Command{
 			logging.FromContext(cmd.Context()).Infof("Using existing credentials. Use account:logout to logout")
 		}
 
-		client, err := accountApi.NewApi(cmd.Context(), accountApi.LoginRequest{Email: email, Password: password})
+		client, err := accountApi.NewApi(cmd.Context(), accountApi.LoginRequest{Email: email, Password: password, ApiUrl: services.Conf.GetAccountApiUrl()})
 		if err != nil {
 			return fmt.Errorf("login failed with error: %w", err)
 		}
@@ -17,7 +17,7 @@ var accountCompanyMerchantShopListCmd = &cobra.Command{
 		table := table.NewWriter(os.Stdout)
 		table.Header([]string{"ID", "Domain", "Usage"})
 
-		shops, err := services.AccountClient.Merchant().Shops(cmd.Context())
+		shops, err := services.AccountClient.Merchant().ListShops(cmd.Context(), paginationLimit(cmd))
 		if err != nil {
 			return err
 		}
@@ -38,4 +38,5 @@ var accountCompanyMerchantShopListCmd = &cobra.Command{
 
 func init() {
 	accountCompanyMerchantShopCmd.AddCommand(accountCompanyMerchantShopListCmd)
+	addPaginationFlags(accountCompanyMerchantShopListCmd)
 }
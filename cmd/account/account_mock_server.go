@@ -0,0 +1,39 @@
+package account
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/internal/account-api/accountmock"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var accountMockServerCmd = &cobra.Command{
+	Use:   "mock-server",
+	Short: "Starts a local mock of the Shopware Account API for testing",
+	Long: `Starts a local mock of the Shopware Account API which answers with recorded responses.
+
+Point the account commands to it with SHOPWARE_CLI_ACCOUNT_API_URL, for example:
+
+  SHOPWARE_CLI_ACCOUNT_API_URL=http://127.0.0.1:8787 shopware-cli account producer extension upload --dry-run MyExtension.zip`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		listen, _ := cmd.Flags().GetString("listen")
+
+		logging.FromContext(cmd.Context()).Infof("Account API mock is listening on http://%s", listen)
+
+		server := &http.Server{
+			Addr:              listen,
+			Handler:           accountmock.New(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		return server.ListenAndServe()
+	},
+}
+
+func init() {
+	accountRootCmd.AddCommand(accountMockServerCmd)
+	accountMockServerCmd.Flags().String("listen", "127.0.0.1:8787", "Address to listen on")
+}
@@ -0,0 +1,92 @@
+package account
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+	"github.com/shopware/shopware-cli/internal/color"
+	"github.com/shopware/shopware-cli/internal/table"
+)
+
+var accountCompanyProducerExtensionAuditCmd = &cobra.Command{
+	Use:   "audit [name]...",
+	Short: "Audits the store listings of your extensions and prints a to-do list per extension",
+	Long: `Checks the store listings for screenshots older than the last releases, missing locales,
+short descriptions and descriptions below the recommended length and categories the store no longer offers.
+Without names all extensions of the producer are audited.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := services.AccountClient.Producer(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("cannot get producer endpoint: %w", err)
+		}
+
+		releases, _ := cmd.Flags().GetInt("releases")
+		minDescriptionLength, _ := cmd.Flags().GetInt("min-description-length")
+
+		opts := storeAuditOptions{Releases: releases, MinDescriptionLength: minDescriptionLength}
+
+		general, err := p.GetExtensionGeneralInfo(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("cannot get store information: %w", err)
+		}
+
+		extensions, err := p.AllExtensions(cmd.Context(), account_api.ListExtensionCriteria{}, 0)
+		if err != nil {
+			return err
+		}
+
+		for _, listed := range extensions {
+			if listed.Status.Name == "deleted" || (len(args) > 0 && !slices.Contains(args, listed.Name)) {
+				continue
+			}
+
+			ext, err := p.GetExtensionById(cmd.Context(), listed.Id)
+			if err != nil {
+				return err
+			}
+
+			binaries, err := p.GetExtensionBinaries(cmd.Context(), ext.Id)
+			if err != nil {
+				return err
+			}
+
+			images, err := p.GetExtensionImages(cmd.Context(), ext.Id)
+			if err != nil {
+				return err
+			}
+
+			items := auditStoreListing(ext, binaries, images, general, opts)
+
+			if len(items) == 0 {
+				fmt.Printf("%s: %s\n\n", color.BoldText.Render(ext.Name), color.GreenText.Render("the store listing is up to date"))
+				continue
+			}
+
+			fmt.Printf("%s (%d to-dos)\n", color.BoldText.Render(ext.Name), len(items))
+
+			table := table.NewWriter(os.Stdout)
+			table.Header([]string{"#", "Priority", "To-do"})
+
+			for i, item := range items {
+				_ = table.Append([]string{strconv.Itoa(i + 1), item.Priority.String(), item.Message})
+			}
+
+			_ = table.Render()
+
+			fmt.Println()
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	accountCompanyProducerExtensionCmd.AddCommand(accountCompanyProducerExtensionAuditCmd)
+	accountCompanyProducerExtensionAuditCmd.Flags().Int("releases", 3, "Screenshots older than this amount of releases are outdated")
+	accountCompanyProducerExtensionAuditCmd.Flags().Int("min-description-length", 500, "Recommended length of the description text without markup")
+}
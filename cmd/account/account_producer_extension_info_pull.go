@@ -43,7 +43,7 @@ var accountCompanyProducerExtensionInfoPullCmd = &cobra.Command{
 			return fmt.Errorf("cannot get producer endpoint: %w", err)
 		}
 
-		storeExt, err := p.GetExtensionByName(cmd.Context(), zipName)
+		storeExt, err := resolveAccountExtension(cmd.Context(), cmd.OutOrStdout(), p, zipName)
 		if err != nil {
 			return fmt.Errorf("cannot get store extension: %w", err)
 		}
@@ -115,6 +115,58 @@ var accountCompanyProducerExtensionInfoPullCmd = &cobra.Command{
 			}
 		}
 
+		storePriceModels, err := p.GetExtensionPriceModels(cmd.Context(), storeExt.Id)
+		if err != nil {
+			return fmt.Errorf("cannot get extension price models: %w", err)
+		}
+
+		priceModels := make([]extension.ConfigStorePriceModel, 0, len(storePriceModels))
+
+		for _, model := range storePriceModels {
+			countryPrices := make([]extension.ConfigStoreCountryPrice, 0, len(model.CountryPrices))
+
+			for _, countryPrice := range model.CountryPrices {
+				countryPrices = append(countryPrices, extension.ConfigStoreCountryPrice{Country: countryPrice.Country, Price: countryPrice.Price})
+			}
+
+			priceModels = append(priceModels, extension.ConfigStorePriceModel{
+				Type:          model.Type,
+				Price:         model.Price,
+				TrialPhase:    model.TrialPhaseIncluded,
+				CountryPrices: countryPrices,
+			})
+		}
+
+		storeInAppFeatures, err := p.GetExtensionInAppFeatures(cmd.Context(), storeExt.Id)
+		if err != nil {
+			return fmt.Errorf("cannot get extension in-app features: %w", err)
+		}
+
+		inAppFeatures := make([]extension.ConfigStoreInAppFeature, 0, len(storeInAppFeatures))
+
+		for _, feature := range storeInAppFeatures {
+			configFeature := extension.ConfigStoreInAppFeature{
+				Identifier: feature.Identifier,
+				Type:       feature.Type,
+				Price:      feature.Price,
+				Disabled:   !feature.Active,
+			}
+
+			for _, info := range feature.Infos {
+				name, description := info.Name, info.Description
+
+				if strings.HasPrefix(info.Locale, "de") {
+					configFeature.Name.German = &name
+					configFeature.Description.German = &description
+				} else {
+					configFeature.Name.English = &name
+					configFeature.Description.English = &description
+				}
+			}
+
+			inAppFeatures = append(inAppFeatures, configFeature)
+		}
+
 		germanDescription := ""
 		englishDescription := ""
 		germanInstallationManual := ""
@@ -221,6 +273,15 @@ var accountCompanyProducerExtensionInfoPullCmd = &cobra.Command{
 		newCfg.Store.MetaDescription = extension.ConfigTranslated[string]{German: &germanMetaDescription, English: &englishMetaDescription}
 		newCfg.Store.Images = nil
 
+		// Free extensions get no price_models, so a later push keeps the price models of the account
+		if len(priceModels) > 0 {
+			newCfg.Store.PriceModels = &priceModels
+		}
+
+		if len(inAppFeatures) > 0 {
+			newCfg.Store.InAppFeatures = &inAppFeatures
+		}
+
 		if len(storeImages) > 0 {
 			imageDir := "src/Resources/store/images"
 			newCfg.Store.ImageDirectory = &imageDir
@@ -1,13 +1,11 @@
 package account
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"os"
 	"path"
 	"path/filepath"
-	"regexp"
 	"strconv"
 	"strings"
 
@@ -55,98 +53,114 @@ var accountCompanyProducerExtensionInfoPushCmd = &cobra.Command{
 			return fmt.Errorf("cannot get producer endpoint: %w", err)
 		}
 
-		storeExt, err := p.GetExtensionByName(cmd.Context(), zipName)
+		storeExt, err := resolveAccountExtension(cmd.Context(), cmd.OutOrStdout(), p, zipName)
 		if err != nil {
 			return fmt.Errorf("cannot get store extension: %w", err)
 		}
 
-		metadata := zipExt.GetMetaData()
+		if err := pushStoreInfo(cmd.Context(), p, storeExt, zipExt); err != nil {
+			return err
+		}
 
-		for _, info := range storeExt.Infos {
-			language := info.Locale.Name[0:2]
+		logging.FromContext(cmd.Context()).Infof("Store information has been updated")
 
-			if language == "de" {
-				info.Name = metadata.Label.German
-				info.ShortDescription = metadata.Description.German
-			} else {
-				info.Name = metadata.Label.English
-				info.ShortDescription = metadata.Description.English
-			}
+		return nil
+	},
+}
+
+// pushStoreInfo updates the store page, icon, images, price models and in-app features of the extension from the extension config
+func pushStoreInfo(ctx context.Context, p *accountApi.ProducerEndpoint, storeExt *accountApi.Extension, zipExt extension.Extension) error {
+	metadata := zipExt.GetMetaData()
+
+	for _, info := range storeExt.Infos {
+		language := info.Locale.Name[0:2]
+
+		if language == "de" {
+			info.Name = metadata.Label.German
+			info.ShortDescription = metadata.Description.German
+		} else {
+			info.Name = metadata.Label.English
+			info.ShortDescription = metadata.Description.English
 		}
+	}
 
-		info, err := p.GetExtensionGeneralInfo(cmd.Context())
-		if err != nil {
-			return fmt.Errorf("cannot get general info: %w", err)
+	info, err := p.GetExtensionGeneralInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot get general info: %w", err)
+	}
+
+	extCfg := zipExt.GetExtensionConfig()
+
+	if extCfg != nil {
+		if extCfg.Store.Icon != nil {
+			err := p.UpdateExtensionIcon(ctx, storeExt.Id, fmt.Sprintf("%s/%s", zipExt.GetPath(), *extCfg.Store.Icon))
+			if err != nil {
+				return fmt.Errorf("cannot update extension icon due error: %w", err)
+			}
 		}
 
-		extCfg := zipExt.GetExtensionConfig()
+		if extCfg.Store.Images != nil || extCfg.Store.ImageDirectory != nil {
+			images, err := p.GetExtensionImages(ctx, storeExt.Id)
+			if err != nil {
+				return fmt.Errorf("cannot get images from remote server: %w", err)
+			}
 
-		if extCfg != nil {
-			if extCfg.Store.Icon != nil {
-				err := p.UpdateExtensionIcon(cmd.Context(), storeExt.Id, fmt.Sprintf("%s/%s", zipExt.GetPath(), *extCfg.Store.Icon))
+			for _, image := range images {
+				err := p.DeleteExtensionImages(ctx, storeExt.Id, image.Id)
 				if err != nil {
-					return fmt.Errorf("cannot update extension icon due error: %w", err)
+					return fmt.Errorf("cannot extension image: %w", err)
 				}
 			}
 
-			if extCfg.Store.Images != nil || extCfg.Store.ImageDirectory != nil {
-				images, err := p.GetExtensionImages(cmd.Context(), storeExt.Id)
-				if err != nil {
-					return fmt.Errorf("cannot get images from remote server: %w", err)
+			if extCfg.Store.ImageDirectory != nil {
+				if err := uploadImagesByDirectory(ctx, storeExt.Id, path.Join(zipExt.GetPath(), *extCfg.Store.ImageDirectory), 0, p); err != nil {
+					return err
 				}
 
-				for _, image := range images {
-					err := p.DeleteExtensionImages(cmd.Context(), storeExt.Id, image.Id)
+				if err := uploadImagesByDirectory(ctx, storeExt.Id, path.Join(zipExt.GetPath(), *extCfg.Store.ImageDirectory), 1, p); err != nil {
+					return err
+				}
+			} else {
+				// manually specified images
+				for _, configImage := range *extCfg.Store.Images {
+					apiImage, err := p.AddExtensionImage(ctx, storeExt.Id, fmt.Sprintf("%s/%s", zipExt.GetPath(), configImage.File))
 					if err != nil {
-						return fmt.Errorf("cannot extension image: %w", err)
+						return fmt.Errorf("cannot upload image %s to extension: %w", configImage.File, err)
 					}
-				}
 
-				if extCfg.Store.ImageDirectory != nil {
-					if err := uploadImagesByDirectory(cmd.Context(), storeExt.Id, path.Join(zipExt.GetPath(), *extCfg.Store.ImageDirectory), 0, p); err != nil {
-						return err
-					}
+					apiImage.Priority = configImage.Priority
+					apiImage.Details[0].Activated = configImage.Activate.German
+					apiImage.Details[0].Preview = configImage.Preview.German
 
-					if err := uploadImagesByDirectory(cmd.Context(), storeExt.Id, path.Join(zipExt.GetPath(), *extCfg.Store.ImageDirectory), 1, p); err != nil {
-						return err
-					}
-				} else {
-					// manually specified images
-					for _, configImage := range *extCfg.Store.Images {
-						apiImage, err := p.AddExtensionImage(cmd.Context(), storeExt.Id, fmt.Sprintf("%s/%s", zipExt.GetPath(), configImage.File))
-						if err != nil {
-							return fmt.Errorf("cannot upload image %s to extension: %w", configImage.File, err)
-						}
-
-						apiImage.Priority = configImage.Priority
-						apiImage.Details[0].Activated = configImage.Activate.German
-						apiImage.Details[0].Preview = configImage.Preview.German
-
-						apiImage.Details[1].Activated = configImage.Activate.English
-						apiImage.Details[1].Preview = configImage.Preview.English
-
-						err = p.UpdateExtensionImage(cmd.Context(), storeExt.Id, apiImage)
-						if err != nil {
-							return fmt.Errorf("cannot update image information of extension: %w", err)
-						}
+					apiImage.Details[1].Activated = configImage.Activate.English
+					apiImage.Details[1].Preview = configImage.Preview.English
+
+					err = p.UpdateExtensionImage(ctx, storeExt.Id, apiImage)
+					if err != nil {
+						return fmt.Errorf("cannot update image information of extension: %w", err)
 					}
 				}
 			}
+		}
 
-			if err := updateStoreInfo(storeExt, zipExt, extCfg, info); err != nil {
-				return fmt.Errorf("cannot update store information: %w", err)
-			}
+		if err := updateStoreInfo(storeExt, zipExt, extCfg, info); err != nil {
+			return fmt.Errorf("cannot update store information: %w", err)
 		}
 
-		err = p.UpdateExtension(cmd.Context(), storeExt)
-		if err != nil {
-			return err
+		if extCfg.Store.PriceModels != nil {
+			if err := p.UpdateExtensionPriceModels(ctx, storeExt.Id, convertPriceModels(*extCfg.Store.PriceModels)); err != nil {
+				return fmt.Errorf("cannot update price models: %w", err)
+			}
 		}
 
-		logging.FromContext(cmd.Context()).Infof("Store information has been updated")
+		if extCfg.Store.InAppFeatures != nil {
+			if err := p.UpdateExtensionInAppFeatures(ctx, storeExt.Id, convertInAppFeatures(*extCfg.Store.InAppFeatures)); err != nil {
+				return fmt.Errorf("cannot update in-app features: %w", err)
+			}
+		}
+	}
 
-		return nil
-	},
+	return p.UpdateExtension(ctx, storeExt)
 }
 
 func updateStoreInfo(ext *accountApi.Extension, zipExt extension.Extension, cfg *extension.Config, info *accountApi.ExtensionGeneralInformation) error { //nolint:gocyclo
@@ -249,22 +263,24 @@ func updateStoreInfo(ext *accountApi.Extension, zipExt extension.Extension, cfg
 			info.Faqs = newFaq
 		}
 
-		var err error
-
 		storeDescription := getTranslation(language, cfg.Store.Description)
 		if storeDescription != nil {
-			info.Description, err = parseInlineablePath(*storeDescription, zipExt.GetPath())
+			description, err := extension.ReadStoreText(*storeDescription, zipExt.GetPath())
 			if err != nil {
 				return err
 			}
+
+			info.Description = description
 		}
 
 		storeManual := getTranslation(language, cfg.Store.InstallationManual)
 		if storeManual != nil {
-			info.InstallationManual, err = parseInlineablePath(*storeManual, zipExt.GetPath())
+			manual, err := extension.ReadStoreText(*storeManual, zipExt.GetPath())
 			if err != nil {
 				return err
 			}
+
+			info.InstallationManual = manual
 		}
 
 		storeMetaTitle := getTranslation(language, cfg.Store.MetaTitle)
@@ -281,46 +297,72 @@ func updateStoreInfo(ext *accountApi.Extension, zipExt extension.Extension, cfg
 	return nil
 }
 
-func getTranslation[T extension.Translatable](language string, config extension.ConfigTranslated[T]) *T {
-	switch language {
-	case "de":
-		return config.German
-	case "en":
-		return config.English
+func convertPriceModels(models []extension.ConfigStorePriceModel) []accountApi.ExtensionPriceModel {
+	apiModels := make([]accountApi.ExtensionPriceModel, 0, len(models))
+
+	for _, model := range models {
+		countryPrices := make([]accountApi.ExtensionPriceModelCountry, 0, len(model.CountryPrices))
+
+		for _, countryPrice := range model.CountryPrices {
+			countryPrices = append(countryPrices, accountApi.ExtensionPriceModelCountry{Country: countryPrice.Country, Price: countryPrice.Price})
+		}
+
+		apiModels = append(apiModels, accountApi.ExtensionPriceModel{
+			Type:               model.Type,
+			Price:              model.Price,
+			TrialPhaseIncluded: model.TrialPhase,
+			CountryPrices:      countryPrices,
+		})
 	}
 
-	return nil
+	return apiModels
 }
 
-func init() {
-	accountCompanyProducerExtensionInfoCmd.AddCommand(accountCompanyProducerExtensionInfoPushCmd)
-}
+func convertInAppFeatures(features []extension.ConfigStoreInAppFeature) []accountApi.ExtensionInAppFeature {
+	apiFeatures := make([]accountApi.ExtensionInAppFeature, 0, len(features))
 
-func parseInlineablePath(path, extensionDir string) (string, error) {
-	if !strings.HasPrefix(path, "file:") {
-		return path, nil
-	}
+	for _, feature := range features {
+		infos := make([]accountApi.ExtensionInAppFeatureInfo, 0, 2)
 
-	filePath := fmt.Sprintf("%s/%s", extensionDir, strings.TrimPrefix(path, "file:"))
+		for _, locale := range []string{"de_DE", "en_GB"} {
+			info := accountApi.ExtensionInAppFeatureInfo{Locale: locale}
 
-	content, err := os.ReadFile(filePath)
-	if err != nil {
-		return "", fmt.Errorf("error reading file at path %s with error: %v", filePath, err)
-	}
+			if name := getTranslation(locale[0:2], feature.Name); name != nil {
+				info.Name = *name
+			}
+
+			if description := getTranslation(locale[0:2], feature.Description); description != nil {
+				info.Description = *description
+			}
 
-	if filepath.Ext(filePath) != ".md" {
-		return string(content), nil
+			infos = append(infos, info)
+		}
+
+		apiFeatures = append(apiFeatures, accountApi.ExtensionInAppFeature{
+			Identifier: feature.Identifier,
+			Type:       feature.Type,
+			Price:      feature.Price,
+			Active:     !feature.Disabled,
+			Infos:      infos,
+		})
 	}
 
-	md := extension.GetConfiguredGoldMark()
+	return apiFeatures
+}
 
-	var buf bytes.Buffer
-	err = md.Convert(content, &buf)
-	if err != nil {
-		return "", fmt.Errorf("cannot convert file at path %s from markdown to html with error: %v", filePath, err)
+func getTranslation[T extension.Translatable](language string, config extension.ConfigTranslated[T]) *T {
+	switch language {
+	case "de":
+		return config.German
+	case "en":
+		return config.English
 	}
 
-	return buf.String(), nil
+	return nil
+}
+
+func init() {
+	accountCompanyProducerExtensionInfoCmd.AddCommand(accountCompanyProducerExtensionInfoPushCmd)
 }
 
 func uploadImagesByDirectory(ctx context.Context, extensionId int, directory string, index int, p *accountApi.ProducerEndpoint) error {
@@ -338,7 +380,6 @@ func uploadImagesByDirectory(ctx context.Context, extensionId int, directory str
 	}
 
 	imagesLen := len(images) - 1
-	re := regexp.MustCompile(`^(\d+)([_-][a-zA-Z0-9-_]+)?$`)
 
 	for i, image := range images {
 		if image.IsDir() {
@@ -353,7 +394,7 @@ func uploadImagesByDirectory(ctx context.Context, extensionId int, directory str
 			return fmt.Errorf("cannot upload image %s to extension: %w", image.Name(), err)
 		}
 
-		matches := re.FindStringSubmatch(fileName)
+		matches := storeImageNameRegExp.FindStringSubmatch(fileName)
 
 		if matches == nil {
 			logging.FromContext(ctx).Warnf("Invalid image name %s, skipping", image.Name())
@@ -20,9 +20,7 @@ var accountCompanyProducerExtensionListCmd = &cobra.Command{
 			return fmt.Errorf("cannot get producer endpoint: %w", err)
 		}
 
-		criteria := account_api.ListExtensionCriteria{
-			Limit: 100,
-		}
+		criteria := account_api.ListExtensionCriteria{}
 
 		if len(listExtensionSearch) > 0 {
 			criteria.Search = listExtensionSearch
@@ -30,7 +28,7 @@ var accountCompanyProducerExtensionListCmd = &cobra.Command{
 			criteria.OrderSequence = "asc"
 		}
 
-		extensions, err := p.Extensions(cmd.Context(), &criteria)
+		extensions, err := p.AllExtensions(cmd.Context(), criteria, paginationLimit(cmd))
 		if err != nil {
 			return err
 		}
@@ -69,4 +67,5 @@ var listExtensionSearch string
 func init() {
 	accountCompanyProducerExtensionCmd.AddCommand(accountCompanyProducerExtensionListCmd)
 	accountCompanyProducerExtensionListCmd.Flags().StringVar(&listExtensionSearch, "search", "", "Filter for name")
+	addPaginationFlags(accountCompanyProducerExtensionListCmd)
 }
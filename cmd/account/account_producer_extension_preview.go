@@ -0,0 +1,76 @@
+package account
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var accountCompanyProducerExtensionPreviewCmd = &cobra.Command{
+	Use:   "preview [zip or path]",
+	Short: "Render a local HTML preview of the store listing from the extension config",
+	Long: `Renders the name, short description, images, highlights, features, description and installation manual
+from the extension config into a single HTML file, so the listing can be reviewed before running info push.
+The texts are sanitized like the Shopware Store does. No login is required.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		absolutePath, err := filepath.Abs(args[0])
+		if err != nil {
+			return fmt.Errorf("cannot open file: %w", err)
+		}
+
+		stat, err := os.Stat(absolutePath)
+		if err != nil {
+			return fmt.Errorf("cannot open file: %w", err)
+		}
+
+		var ext extension.Extension
+
+		if stat.IsDir() {
+			ext, err = extension.GetExtensionByFolder(absolutePath)
+		} else {
+			ext, err = extension.GetExtensionByZip(absolutePath)
+		}
+
+		if err != nil {
+			return fmt.Errorf("cannot open extension: %w", err)
+		}
+
+		preview, err := buildStorePreview(ext)
+		if err != nil {
+			return fmt.Errorf("cannot build preview: %w", err)
+		}
+
+		output, _ := cmd.Flags().GetString("output")
+		if output == "" {
+			output = preview.Name + "-store-preview.html"
+		}
+
+		file, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("cannot create preview: %w", err)
+		}
+
+		defer func() {
+			_ = file.Close()
+		}()
+
+		if err := renderStorePreview(file, preview); err != nil {
+			return fmt.Errorf("cannot render preview: %w", err)
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Store preview written to %s", output)
+
+		return nil
+	},
+}
+
+func init() {
+	accountCompanyProducerExtensionCmd.AddCommand(accountCompanyProducerExtensionPreviewCmd)
+	accountCompanyProducerExtensionPreviewCmd.Flags().String("output", "", "File of the HTML preview, defaults to <name>-store-preview.html")
+}
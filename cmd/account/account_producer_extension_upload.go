@@ -2,20 +2,19 @@ package account
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/spf13/cobra"
 
-	"github.com/shopware/shopware-cli/extension"
-	account_api "github.com/shopware/shopware-cli/internal/account-api"
 	"github.com/shopware/shopware-cli/logging"
 )
 
 var accountCompanyProducerExtensionUploadCmd = &cobra.Command{
 	Use:   "upload [zip]",
 	Short: "Uploads a new extension version",
+	Long:  "Uploads a new extension version. Before anything is changed in the account, a preflight checks the credentials, the extension, the version, the changelog and the compatible Shopware versions and prints a checklist of all problems.",
 	Args:  cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		path, err := filepath.Abs(args[0])
@@ -23,165 +22,50 @@ var accountCompanyProducerExtensionUploadCmd = &cobra.Command{
 			return fmt.Errorf("validate: %w", err)
 		}
 
-		p, err := services.AccountClient.Producer(cmd.Context())
+		release, err := PrepareStoreRelease(cmd.Context(), services.AccountClient, path, uploadOnConflict, os.Stdout)
 		if err != nil {
 			return err
 		}
 
-		zipExt, err := extension.GetExtensionByZip(path)
-		if err != nil {
-			return err
-		}
-
-		extName, err := zipExt.GetName()
-		if err != nil {
-			return err
-		}
-
-		ext, err := p.GetExtensionByName(cmd.Context(), extName)
-		if err != nil {
-			return err
-		}
-
-		binaries, err := p.GetExtensionBinaries(cmd.Context(), ext.Id)
-		if err != nil {
-			return err
-		}
-
-		zipVersion, err := zipExt.GetVersion()
-		if err != nil {
-			return err
-		}
-
-		var foundBinary *account_api.ExtensionBinary
-
-		for _, binary := range binaries {
-			if binary.Version == zipVersion.String() {
-				foundBinary = binary
-				break
-			}
-		}
-
-		changelog, err := zipExt.GetChangelog()
-		if err != nil {
-			return err
-		}
+		defer release.Close()
 
-		avaiableVersions, err := p.GetSoftwareVersions(cmd.Context(), zipExt.GetType())
-		if err != nil {
-			return err
+		if release.Skipped() {
+			logging.FromContext(cmd.Context()).Infof("Version %s exists already in the account. Skipping upload", release.Version)
+			return nil
 		}
 
-		constraint, err := zipExt.GetShopwareVersionConstraint()
-		if err != nil {
+		if err := release.ApplyVersionBump(cmd.Context(), uploadDryRun); err != nil {
 			return err
 		}
 
-		if foundBinary == nil {
-			create := account_api.ExtensionCreate{
-				Version:          zipVersion.String(),
-				SoftwareVersions: avaiableVersions.FilterOnVersionStringList(constraint),
-				Changelogs: []account_api.ExtensionUpdateChangelog{
-					{Locale: "de_DE", Text: changelog.German},
-					{Locale: "en_GB", Text: changelog.English},
-				},
+		if uploadDryRun {
+			if release.Binary == nil {
+				logging.FromContext(cmd.Context()).Infof("Dry run: would create a new binary with version %s", release.Version)
+			} else {
+				logging.FromContext(cmd.Context()).Infof("Dry run: would update the existing binary with version %s", release.Version)
 			}
 
-			foundBinary, err = p.CreateExtensionBinary(cmd.Context(), ext.Id, create)
-			if err != nil {
-				return fmt.Errorf("create extension binary: %w", err)
-			}
+			logging.FromContext(cmd.Context()).Infof("Dry run: compatible Shopware versions: %s", strings.Join(release.SoftwareVersions(), ", "))
+			logging.FromContext(cmd.Context()).Infof("Dry run: would upload %s and request a code review", filepath.Base(release.ZipPath))
 
-			logging.FromContext(cmd.Context()).Infof("Created new binary with version %s", zipVersion)
-		} else {
-			logging.FromContext(cmd.Context()).Infof("Found a zip with version %s already. Updating it", zipVersion)
+			return nil
 		}
 
-		update := account_api.ExtensionUpdate{
-			Id:               foundBinary.Id,
-			SoftwareVersions: avaiableVersions.FilterOnVersionStringList(constraint),
-			Changelogs: []account_api.ExtensionUpdateChangelog{
-				{Locale: "de_DE", Text: changelog.German},
-				{Locale: "en_GB", Text: changelog.English},
-			},
-		}
-
-		err = p.UpdateExtensionBinaryInfo(cmd.Context(), ext.Id, update)
-		if err != nil {
-			return err
-		}
-
-		logging.FromContext(cmd.Context()).Infof("Updated changelog. Uploading now the zip to remote")
-
-		err = p.UpdateExtensionBinaryFile(cmd.Context(), ext.Id, foundBinary.Id, path)
-		if err != nil {
-			if strings.Contains(err.Error(), "BinariesException-40") {
-				logging.FromContext(cmd.Context()).Infof("Binary version is already published. Skipping upload")
-				return nil
-			}
-		}
-
-		logging.FromContext(cmd.Context()).Infof("Submitting code review request")
-
-		beforeReviews, err := p.GetBinaryReviewResults(cmd.Context(), ext.Id, foundBinary.Id)
-		if err != nil {
-			return err
-		}
-
-		err = p.TriggerCodeReview(cmd.Context(), ext.Id)
-		if err != nil {
-			return err
-		}
-
-		if !skipWaitingForCodereviewResult {
-			logging.FromContext(cmd.Context()).Infof("Waiting for code review result")
-
-			time.Sleep(10 * time.Second)
-
-			maxTries := 10
-			tried := 0
-			for {
-				reviews, err := p.GetBinaryReviewResults(cmd.Context(), ext.Id, foundBinary.Id)
-				if err != nil {
-					return err
-				}
-
-				// Review has been updated
-				if len(reviews) != len(beforeReviews) {
-					lastReview := reviews[len(reviews)-1]
-
-					if !lastReview.IsPending() {
-						if lastReview.HasPassed() {
-							if lastReview.HasWarnings() {
-								logging.FromContext(cmd.Context()).Infof("Code review has been passed but with warnings")
-								logging.FromContext(cmd.Context()).Infof(lastReview.GetSummary())
-							} else {
-								logging.FromContext(cmd.Context()).Infof("Code review has been passed without warnings")
-							}
-
-							break
-						}
-
-						logging.FromContext(cmd.Context()).Fatalln("Code review has not passed", lastReview.GetSummary())
-					}
-				}
-
-				time.Sleep(15 * time.Second)
-				tried++
-
-				if maxTries == tried {
-					logging.FromContext(cmd.Context()).Infof("Skipping waiting for code review result as it took too long")
-				}
-			}
-		}
-
-		return nil
+		return publishStoreRelease(cmd.Context(), release, uploadForce, skipWaitingForCodereviewResult)
 	},
 }
 
-var skipWaitingForCodereviewResult bool
+var (
+	skipWaitingForCodereviewResult bool
+	uploadDryRun                   bool
+	uploadForce                    bool
+	uploadOnConflict               string
+)
 
 func init() {
 	accountCompanyProducerExtensionCmd.AddCommand(accountCompanyProducerExtensionUploadCmd)
 	accountCompanyProducerExtensionUploadCmd.Flags().BoolVar(&skipWaitingForCodereviewResult, "skip-for-review-result", false, "Skips waiting for Code review result")
+	accountCompanyProducerExtensionUploadCmd.Flags().BoolVar(&uploadDryRun, "dry-run", false, "Only shows what would be uploaded without changing anything in the account")
+	accountCompanyProducerExtensionUploadCmd.Flags().StringVar(&uploadOnConflict, "on-conflict", "", "What to do when the version exists already: skip, replace-if-not-reviewed, bump-patch or fail. Defaults to store.upload_on_conflict of the extension config or replace-if-not-reviewed")
+	accountCompanyProducerExtensionUploadCmd.Flags().BoolVar(&uploadForce, "force", false, "Uploads the zip even when the same content was already uploaded for this version")
 }
@@ -0,0 +1,48 @@
+package account
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/internal/table"
+)
+
+var accountProducerListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "Lists all producers of the companies you are a member of",
+	Aliases: []string{"ls"},
+	Long:    ``,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		producers, err := services.AccountClient.ProducerMemberships(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		table := table.NewWriter(os.Stdout)
+		table.Header([]string{"ID", "Name", "Prefix", "Company ID", "Company", "Active"})
+
+		for _, producer := range producers {
+			active := ""
+			if producer.Membership.Company.Id == services.AccountClient.GetActiveCompanyID() {
+				active = "yes"
+			}
+
+			_ = table.Append([]string{
+				strconv.Itoa(producer.Producer.Id),
+				producer.Producer.Name,
+				producer.Producer.Prefix,
+				strconv.Itoa(producer.Membership.Company.Id),
+				producer.Membership.Company.Name,
+				active,
+			})
+		}
+
+		return table.Render()
+	},
+}
+
+func init() {
+	accountCompanyProducerCmd.AddCommand(accountProducerListCmd)
+}
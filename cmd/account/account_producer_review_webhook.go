@@ -0,0 +1,76 @@
+package account
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var accountProducerReviewWebhookCmd = &cobra.Command{
+	Use:   "review-webhook [name]...",
+	Short: "Posts review status changes of your extensions to a webhook",
+	Long: `Polls the automatic code review, the binary status and the extension status of your extensions
+and posts every change as JSON to the webhook URL. With a secret the body is signed with HMAC-SHA256 in the
+X-Shopware-Cli-Signature header. The last posted statuses are kept in a state file, so a run with --once can be
+scheduled by cron. Without names all extensions of the producer are watched.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		url, _ := cmd.Flags().GetString("url")
+		secret, _ := cmd.Flags().GetString("secret")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		once, _ := cmd.Flags().GetBool("once")
+		statePath, _ := cmd.Flags().GetString("state")
+
+		if url == "" {
+			return fmt.Errorf("--url is required")
+		}
+
+		if secret == "" {
+			secret = os.Getenv("SHOPWARE_CLI_REVIEW_WEBHOOK_SECRET")
+		}
+
+		if statePath == "" {
+			statePath = reviewWebhookStatePath()
+		}
+
+		p, err := services.AccountClient.Producer(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("cannot get producer endpoint: %w", err)
+		}
+
+		state, err := readReviewWebhookState(statePath)
+		if err != nil {
+			return err
+		}
+
+		for {
+			err := pollReviewWebhook(cmd.Context(), p, args, state, url, secret)
+
+			if once {
+				return err
+			}
+
+			if err != nil {
+				logging.FromContext(cmd.Context()).Warnf("Cannot sync review status: %v", err)
+			}
+
+			select {
+			case <-cmd.Context().Done():
+				return nil
+			case <-time.After(interval):
+			}
+		}
+	},
+}
+
+func init() {
+	accountCompanyProducerCmd.AddCommand(accountProducerReviewWebhookCmd)
+	accountProducerReviewWebhookCmd.Flags().String("url", "", "Webhook URL the status changes are posted to")
+	accountProducerReviewWebhookCmd.Flags().String("secret", "", "Secret to sign the body with, defaults to SHOPWARE_CLI_REVIEW_WEBHOOK_SECRET")
+	accountProducerReviewWebhookCmd.Flags().Duration("interval", 5*time.Minute, "Time between two polls")
+	accountProducerReviewWebhookCmd.Flags().Bool("once", false, "Poll once and exit, for scheduling with cron")
+	accountProducerReviewWebhookCmd.Flags().String("state", "", "File with the last posted statuses, defaults to the cache directory")
+}
@@ -0,0 +1,38 @@
+package account
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+var accountProducerUseCmd = &cobra.Command{
+	Use:   "use [producerId]",
+	Short: "Use another producer by switching to the company it belongs to",
+	Args:  cobra.ExactArgs(1),
+	Long:  ``,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		producerID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return err
+		}
+
+		producers, err := services.AccountClient.ProducerMemberships(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		for _, producer := range producers {
+			if producer.Producer.Id == producerID {
+				return useCompany(cmd.Context(), producer.Membership)
+			}
+		}
+
+		return fmt.Errorf("producer with ID \"%d\" not found, run \"account producer list\" to see the available producers", producerID)
+	},
+}
+
+func init() {
+	accountCompanyProducerCmd.AddCommand(accountProducerUseCmd)
+}
@@ -0,0 +1,62 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/charmbracelet/huh"
+
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+	"github.com/shopware/shopware-cli/internal/interaction"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// resolveAccountExtension finds the extension by name. When the name matches no extension exactly, the user picks one of the
+// similar extensions. Without a terminal the candidates are written as JSON to out, so scripts can pick the right name.
+func resolveAccountExtension(ctx context.Context, out io.Writer, p *account_api.ProducerEndpoint, name string) (*account_api.Extension, error) {
+	ext, err := p.GetExtensionByName(ctx, name)
+
+	var ambiguous *account_api.AmbiguousExtensionError
+	if !errors.As(err, &ambiguous) || len(ambiguous.Candidates) == 0 {
+		return ext, err
+	}
+
+	if !interaction.IsInteractive(ctx) {
+		content, jsonErr := json.Marshal(ambiguous)
+		if jsonErr == nil {
+			_, _ = fmt.Fprintln(out, string(content))
+		}
+
+		return nil, err
+	}
+
+	options := make([]huh.Option[string], 0, len(ambiguous.Candidates))
+	for _, candidate := range ambiguous.Candidates {
+		options = append(options, huh.NewOption(fmt.Sprintf("%s (%d)", candidate.Name, candidate.Id), strconv.Itoa(candidate.Id)))
+	}
+
+	var selected string
+
+	if err := huh.NewSelect[string]().
+		Title(fmt.Sprintf("There is no extension %s in the account, which one did you mean?", name)).
+		Options(options...).
+		Value(&selected).
+		Run(); err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.Atoi(selected)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.RememberExtensionName(name, id); err != nil {
+		logging.FromContext(ctx).Debugf("Cannot cache extension id of %s: %v", name, err)
+	}
+
+	return p.GetExtensionById(ctx, id)
+}
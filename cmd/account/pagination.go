@@ -0,0 +1,25 @@
+package account
+
+import (
+	"github.com/spf13/cobra"
+
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+)
+
+// addPaginationFlags adds --limit and --all to listing commands, the account API returns the entries page by page
+func addPaginationFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("limit", account_api.DefaultPageSize, "Maximum amount of entries to list")
+	cmd.Flags().Bool("all", false, "List all entries, the pages are fetched one after another")
+	cmd.MarkFlagsMutuallyExclusive("limit", "all")
+}
+
+// paginationLimit returns the limit for account_api.FetchPages, zero fetches all entries
+func paginationLimit(cmd *cobra.Command) int {
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		return 0
+	}
+
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	return max(limit, 0)
+}
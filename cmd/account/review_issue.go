@@ -0,0 +1,58 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/shopware/shopware-cli/extension"
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+	"github.com/shopware/shopware-cli/internal/ci"
+)
+
+// openReviewIssue opens an issue for the failed code review in the tracker of store.review_issue and returns its URL.
+// Without the config nothing is opened and the URL is empty.
+func openReviewIssue(ctx context.Context, cfg *extension.Config, extensionName, version string, review *account_api.BinaryReviewResult) (string, error) {
+	if cfg == nil || cfg.Store.ReviewIssue == nil {
+		return "", nil
+	}
+
+	issueCfg := cfg.Store.ReviewIssue
+
+	tracker, err := ci.NewIssueTracker(issueCfg.Provider, issueCfg.Project, issueCfg.Url)
+	if err != nil {
+		return "", err
+	}
+
+	issue := buildReviewIssue(extensionName, version, review, ci.JobURL())
+	issue.Labels = issueCfg.Labels
+	issue.Assignees = issueCfg.Assignees
+
+	return tracker.CreateIssue(ctx, issue)
+}
+
+// buildReviewIssue describes the failed review, the title contains the version so each version gets one issue
+func buildReviewIssue(extensionName, version string, review *account_api.BinaryReviewResult, jobURL string) ci.Issue {
+	var body strings.Builder
+
+	fmt.Fprintf(&body, "The automatic code review of **%s** version **%s** in the Shopware Store failed.\n\n", extensionName, version)
+	fmt.Fprintf(&body, "- Extension: %s\n", extensionName)
+	fmt.Fprintf(&body, "- Version: %s\n", version)
+
+	if review.CreationDate != "" {
+		fmt.Fprintf(&body, "- Reviewed at: %s\n", review.CreationDate)
+	}
+
+	if jobURL != "" {
+		fmt.Fprintf(&body, "- CI job: %s\n", jobURL)
+	}
+
+	if summary := strings.TrimSpace(review.GetSummary()); summary != "" {
+		fmt.Fprintf(&body, "\n## Review result\n\n```\n%s\n```\n", summary)
+	}
+
+	return ci.Issue{
+		Title: fmt.Sprintf("Code review of %s %s failed", extensionName, version),
+		Body:  body.String(),
+	}
+}
@@ -0,0 +1,34 @@
+package account
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+)
+
+func TestBuildReviewIssue(t *testing.T) {
+	review := &account_api.BinaryReviewResult{CreationDate: "2024-05-02 10:00:00"}
+	review.SubCheckResults = append(review.SubCheckResults, struct {
+		SubCheck    string `json:"subCheck"`
+		Status      string `json:"status"`
+		Passed      bool   `json:"passed"`
+		Message     string `json:"message"`
+		HasWarnings bool   `json:"hasWarnings"`
+	}{SubCheck: "phpstan", Message: "Call to undefined method"})
+
+	issue := buildReviewIssue("FroshTools", "1.1.0", review, "https://github.com/acme/plugin/actions/runs/42")
+
+	assert.Equal(t, "Code review of FroshTools 1.1.0 failed", issue.Title)
+	assert.Contains(t, issue.Body, "- Version: 1.1.0\n")
+	assert.Contains(t, issue.Body, "- Reviewed at: 2024-05-02 10:00:00\n")
+	assert.Contains(t, issue.Body, "- CI job: https://github.com/acme/plugin/actions/runs/42\n")
+	assert.Contains(t, issue.Body, "=== phpstan ===\nCall to undefined method")
+}
+
+func TestOpenReviewIssueWithoutConfig(t *testing.T) {
+	issueURL, err := openReviewIssue(t.Context(), nil, "FroshTools", "1.1.0", &account_api.BinaryReviewResult{})
+	assert.NoError(t, err)
+	assert.Empty(t, issueURL)
+}
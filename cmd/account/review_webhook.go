@@ -0,0 +1,241 @@
+package account
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+	"github.com/shopware/shopware-cli/internal/system"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// The stages an extension passes in the store, in the order of a release
+const (
+	reviewStageCodeReview = "code-review"
+	reviewStageBinary     = "binary"
+	reviewStageExtension  = "extension"
+)
+
+const reviewWebhookSignatureHeader = "X-Shopware-Cli-Signature"
+
+type reviewWebhookProducer interface {
+	AllExtensions(ctx context.Context, criteria account_api.ListExtensionCriteria, limit int) ([]account_api.Extension, error)
+	GetExtensionBinaries(ctx context.Context, extensionId int) ([]*account_api.ExtensionBinary, error)
+	GetBinaryReviewResults(ctx context.Context, extensionId, binaryId int) ([]account_api.BinaryReviewResult, error)
+}
+
+// reviewWebhookEvent is the body posted to the webhook when a stage of an extension changed its status
+type reviewWebhookEvent struct {
+	Extension      string    `json:"extension"`
+	ExtensionId    int       `json:"extensionId"`
+	Version        string    `json:"version,omitempty"`
+	BinaryId       int       `json:"binaryId,omitempty"`
+	Stage          string    `json:"stage"`
+	Status         string    `json:"status"`
+	PreviousStatus string    `json:"previousStatus,omitempty"`
+	Summary        string    `json:"summary,omitempty"`
+	ChangedAt      time.Time `json:"changedAt"`
+
+	key string
+}
+
+// reviewWebhookState remembers the last posted status per extension, binary and stage between polls and runs
+type reviewWebhookState struct {
+	path     string
+	Statuses map[string]string `json:"statuses"`
+}
+
+func reviewWebhookStatePath() string {
+	return filepath.Join(system.GetShopwareCliCacheDir(), "review-webhook-state.json")
+}
+
+func readReviewWebhookState(path string) (*reviewWebhookState, error) {
+	state := &reviewWebhookState{path: path, Statuses: map[string]string{}}
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, state); err != nil {
+		return nil, fmt.Errorf("cannot parse review webhook state %s: %w", path, err)
+	}
+
+	if state.Statuses == nil {
+		state.Statuses = map[string]string{}
+	}
+
+	return state, nil
+}
+
+func (s *reviewWebhookState) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), os.ModePerm); err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path, content, 0o600)
+}
+
+// collectReviewEvents compares the current review state of the extensions with the state. With an empty state the statuses
+// are only recorded, so the first poll does not post the whole history. Without names all extensions are watched.
+func collectReviewEvents(ctx context.Context, producer reviewWebhookProducer, names []string, state *reviewWebhookState) ([]reviewWebhookEvent, error) {
+	extensions, err := producer.AllExtensions(ctx, account_api.ListExtensionCriteria{}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]reviewWebhookEvent, 0)
+	initial := len(state.Statuses) == 0
+	now := time.Now().UTC()
+
+	for _, ext := range extensions {
+		if ext.Status.Name == "deleted" || (len(names) > 0 && !slices.Contains(names, ext.Name)) {
+			continue
+		}
+
+		current := make([]reviewWebhookEvent, 0, 3)
+
+		binaries, err := producer.GetExtensionBinaries(ctx, ext.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		if binary := latestBinary(binaries); binary != nil {
+			reviews, err := producer.GetBinaryReviewResults(ctx, ext.Id, binary.Id)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(reviews) > 0 {
+				review := reviews[len(reviews)-1]
+
+				current = append(current, reviewWebhookEvent{
+					Version:  binary.Version,
+					BinaryId: binary.Id,
+					Stage:    reviewStageCodeReview,
+					Status:   review.Type.Name,
+					Summary:  review.GetSummary(),
+				})
+			}
+
+			current = append(current, reviewWebhookEvent{
+				Version:  binary.Version,
+				BinaryId: binary.Id,
+				Stage:    reviewStageBinary,
+				Status:   binary.Status.Name,
+			})
+		}
+
+		current = append(current, reviewWebhookEvent{Stage: reviewStageExtension, Status: ext.Status.Name})
+
+		for _, event := range current {
+			event.Extension = ext.Name
+			event.ExtensionId = ext.Id
+			event.ChangedAt = now
+			event.key = fmt.Sprintf("%d/%d/%s", ext.Id, event.BinaryId, event.Stage)
+
+			previous, seen := state.Statuses[event.key]
+
+			if !seen && initial {
+				state.Statuses[event.key] = event.Status
+				continue
+			}
+
+			if seen && previous == event.Status {
+				continue
+			}
+
+			event.PreviousStatus = previous
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+func latestBinary(binaries []*account_api.ExtensionBinary) *account_api.ExtensionBinary {
+	var latest *account_api.ExtensionBinary
+
+	for _, binary := range binaries {
+		if latest == nil || binary.Id > latest.Id {
+			latest = binary
+		}
+	}
+
+	return latest
+}
+
+// postReviewWebhook sends the event as JSON, with a secret the body is signed with HMAC-SHA256
+func postReviewWebhook(ctx context.Context, url, secret string, event reviewWebhookEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	r.Header.Set("Content-Type", "application/json")
+
+	if secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+
+		r.Header.Set(reviewWebhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// pollReviewWebhook posts the changes since the last poll. A status is only stored after it was posted, so failed posts are retried.
+func pollReviewWebhook(ctx context.Context, producer reviewWebhookProducer, names []string, state *reviewWebhookState, url, secret string) error {
+	events, err := collectReviewEvents(ctx, producer, names, state)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		logging.FromContext(ctx).Infof("%s %s: %s -> %s", event.Extension, event.Stage, event.PreviousStatus, event.Status)
+
+		if err := postReviewWebhook(ctx, url, secret, event); err != nil {
+			_ = state.Save()
+			return fmt.Errorf("post %s %s: %w", event.Extension, event.Stage, err)
+		}
+
+		state.Statuses[event.key] = event.Status
+	}
+
+	return state.Save()
+}
@@ -0,0 +1,158 @@
+package account
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+)
+
+type fakeReviewWebhookProducer struct {
+	extensions []account_api.Extension
+	binaries   []*account_api.ExtensionBinary
+	reviews    []account_api.BinaryReviewResult
+}
+
+func (f *fakeReviewWebhookProducer) AllExtensions(_ context.Context, _ account_api.ListExtensionCriteria, _ int) ([]account_api.Extension, error) {
+	return f.extensions, nil
+}
+
+func (f *fakeReviewWebhookProducer) GetExtensionBinaries(_ context.Context, _ int) ([]*account_api.ExtensionBinary, error) {
+	return f.binaries, nil
+}
+
+func (f *fakeReviewWebhookProducer) GetBinaryReviewResults(_ context.Context, _, _ int) ([]account_api.BinaryReviewResult, error) {
+	return f.reviews, nil
+}
+
+func newFakeReviewWebhookProducer(binaryStatus, reviewType string) *fakeReviewWebhookProducer {
+	ext := account_api.Extension{Id: 1, Name: "FroshTools"}
+	ext.Status.Name = "instore"
+
+	binary := &account_api.ExtensionBinary{Id: 7, Version: "1.1.0"}
+	binary.Status.Name = binaryStatus
+
+	review := account_api.BinaryReviewResult{Id: 1}
+	review.Type.Name = reviewType
+
+	return &fakeReviewWebhookProducer{
+		extensions: []account_api.Extension{ext},
+		binaries:   []*account_api.ExtensionBinary{binary},
+		reviews:    []account_api.BinaryReviewResult{review},
+	}
+}
+
+func TestCollectReviewEventsRecordsFirstPoll(t *testing.T) {
+	state := &reviewWebhookState{Statuses: map[string]string{}}
+
+	events, err := collectReviewEvents(t.Context(), newFakeReviewWebhookProducer("codereviewpending", "automaticcodereviewpending"), nil, state)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+	assert.Equal(t, "automaticcodereviewpending", state.Statuses["1/7/code-review"])
+	assert.Equal(t, "instore", state.Statuses["1/0/extension"])
+}
+
+func TestCollectReviewEventsReportsChanges(t *testing.T) {
+	state := &reviewWebhookState{Statuses: map[string]string{}}
+
+	_, err := collectReviewEvents(t.Context(), newFakeReviewWebhookProducer("codereviewpending", "automaticcodereviewpending"), nil, state)
+	require.NoError(t, err)
+
+	events, err := collectReviewEvents(t.Context(), newFakeReviewWebhookProducer("codereviewsucceeded", "automaticcodereviewsucceeded"), nil, state)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	assert.Equal(t, reviewStageCodeReview, events[0].Stage)
+	assert.Equal(t, "automaticcodereviewpending", events[0].PreviousStatus)
+	assert.Equal(t, "automaticcodereviewsucceeded", events[0].Status)
+	assert.Equal(t, "1.1.0", events[0].Version)
+	assert.Equal(t, reviewStageBinary, events[1].Stage)
+	assert.Equal(t, "codereviewsucceeded", events[1].Status)
+}
+
+func TestCollectReviewEventsReportsNewBinary(t *testing.T) {
+	state := &reviewWebhookState{Statuses: map[string]string{}}
+
+	_, err := collectReviewEvents(t.Context(), newFakeReviewWebhookProducer("codereviewsucceeded", "automaticcodereviewsucceeded"), nil, state)
+	require.NoError(t, err)
+
+	producer := newFakeReviewWebhookProducer("codereviewpending", "automaticcodereviewpending")
+	producer.binaries[0].Id = 8
+	producer.binaries[0].Version = "1.2.0"
+
+	events, err := collectReviewEvents(t.Context(), producer, nil, state)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	assert.Equal(t, "1.2.0", events[0].Version)
+	assert.Equal(t, "automaticcodereviewpending", events[0].Status)
+	assert.Empty(t, events[0].PreviousStatus)
+}
+
+func TestCollectReviewEventsFiltersByName(t *testing.T) {
+	state := &reviewWebhookState{Statuses: map[string]string{}}
+
+	_, err := collectReviewEvents(t.Context(), newFakeReviewWebhookProducer("codereviewpending", "automaticcodereviewpending"), []string{"FroshPlatformAdminer"}, state)
+	require.NoError(t, err)
+	assert.Empty(t, state.Statuses)
+}
+
+func TestPollReviewWebhookPostsSignedEvents(t *testing.T) {
+	var received []reviewWebhookEvent
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write(body)
+		assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), r.Header.Get(reviewWebhookSignatureHeader))
+
+		var event reviewWebhookEvent
+		assert.NoError(t, json.Unmarshal(body, &event))
+		received = append(received, event)
+	}))
+	defer server.Close()
+
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	state, err := readReviewWebhookState(statePath)
+	require.NoError(t, err)
+
+	require.NoError(t, pollReviewWebhook(t.Context(), newFakeReviewWebhookProducer("codereviewpending", "automaticcodereviewpending"), nil, state, server.URL, "secret"))
+	assert.Empty(t, received)
+
+	require.NoError(t, pollReviewWebhook(t.Context(), newFakeReviewWebhookProducer("codereviewpending", "automaticcodereviewfailed"), nil, state, server.URL, "secret"))
+	require.Len(t, received, 1)
+	assert.Equal(t, "FroshTools", received[0].Extension)
+	assert.Equal(t, "automaticcodereviewfailed", received[0].Status)
+
+	saved, err := readReviewWebhookState(statePath)
+	require.NoError(t, err)
+	assert.Equal(t, "automaticcodereviewfailed", saved.Statuses["1/7/code-review"])
+}
+
+func TestPollReviewWebhookRetriesFailedPosts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	state, err := readReviewWebhookState(filepath.Join(t.TempDir(), "state.json"))
+	require.NoError(t, err)
+
+	require.NoError(t, pollReviewWebhook(t.Context(), newFakeReviewWebhookProducer("codereviewpending", "automaticcodereviewpending"), nil, state, server.URL, ""))
+
+	err = pollReviewWebhook(t.Context(), newFakeReviewWebhookProducer("codereviewpending", "automaticcodereviewfailed"), nil, state, server.URL, "")
+	assert.ErrorContains(t, err, "status 502")
+	assert.Equal(t, "automaticcodereviewpending", state.Statuses["1/7/code-review"])
+}
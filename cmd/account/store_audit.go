@@ -0,0 +1,177 @@
+package account
+
+import (
+	"cmp"
+	"fmt"
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+)
+
+type storeAuditPriority int
+
+const (
+	storeAuditLow storeAuditPriority = iota
+	storeAuditMedium
+	storeAuditHigh
+)
+
+func (p storeAuditPriority) String() string {
+	switch p {
+	case storeAuditHigh:
+		return "high"
+	case storeAuditMedium:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// storeAuditItem is one to-do of the store listing
+type storeAuditItem struct {
+	Priority storeAuditPriority
+	Message  string
+}
+
+type storeAuditOptions struct {
+	// Releases is the amount of releases after which screenshots count as outdated
+	Releases int
+	// MinDescriptionLength is the recommended length of the description text without markup
+	MinDescriptionLength int
+}
+
+// The store shows the short description in listings and requires this length
+const (
+	minShortDescriptionLength = 150
+	maxShortDescriptionLength = 185
+)
+
+var htmlTagRegExp = regexp.MustCompile(`<[^>]*>`)
+
+// auditStoreListing returns the to-do list of the store listing, sorted by priority
+func auditStoreListing(ext *account_api.Extension, binaries []*account_api.ExtensionBinary, images []*account_api.ExtensionImage, general *account_api.ExtensionGeneralInformation, opts storeAuditOptions) []storeAuditItem {
+	var items []storeAuditItem
+
+	items = append(items, auditScreenshots(binaries, images, opts.Releases)...)
+	items = append(items, auditLocales(ext, general.Locales, opts.MinDescriptionLength)...)
+	items = append(items, auditCategories(ext, general)...)
+
+	slices.SortStableFunc(items, func(a, b storeAuditItem) int {
+		return cmp.Compare(b.Priority, a.Priority)
+	})
+
+	return items
+}
+
+func auditScreenshots(binaries []*account_api.ExtensionBinary, images []*account_api.ExtensionImage, releases int) []storeAuditItem {
+	if len(images) == 0 {
+		return []storeAuditItem{{Priority: storeAuditHigh, Message: "Add screenshots, the listing has none"}}
+	}
+
+	releaseDates := make([]time.Time, 0, len(binaries))
+
+	for _, binary := range binaries {
+		if date, err := account_api.ParseDate(binary.CreationDate); err == nil {
+			releaseDates = append(releaseDates, date)
+		}
+	}
+
+	// Without enough releases no screenshot can be older than them
+	if releases <= 0 || len(releaseDates) < releases {
+		return nil
+	}
+
+	slices.SortFunc(releaseDates, func(a, b time.Time) int {
+		return b.Compare(a)
+	})
+
+	threshold := releaseDates[releases-1]
+	outdated := 0
+
+	for _, image := range images {
+		if date, err := account_api.ParseDate(image.CreationDate); err == nil && date.Before(threshold) {
+			outdated++
+		}
+	}
+
+	switch {
+	case outdated == 0:
+		return nil
+	case outdated == len(images):
+		return []storeAuditItem{{Priority: storeAuditMedium, Message: fmt.Sprintf("All %d screenshots are older than the last %d releases, update them to show the current version", outdated, releases)}}
+	default:
+		return []storeAuditItem{{Priority: storeAuditLow, Message: fmt.Sprintf("%d of %d screenshots are older than the last %d releases", outdated, len(images), releases)}}
+	}
+}
+
+func auditLocales(ext *account_api.Extension, locales []account_api.Locale, minDescriptionLength int) []storeAuditItem {
+	var items []storeAuditItem
+
+	for _, locale := range locales {
+		var info *account_api.ExtensionInfo
+
+		for _, i := range ext.Infos {
+			if i.Locale.Name == locale.Name {
+				info = i
+				break
+			}
+		}
+
+		if info == nil || (strings.TrimSpace(info.Description) == "" && strings.TrimSpace(info.ShortDescription) == "") {
+			items = append(items, storeAuditItem{Priority: storeAuditHigh, Message: fmt.Sprintf("Add the %s texts, the listing has none", locale.Name)})
+			continue
+		}
+
+		if length := len([]rune(strings.TrimSpace(info.ShortDescription))); length < minShortDescriptionLength || length > maxShortDescriptionLength {
+			items = append(items, storeAuditItem{Priority: storeAuditMedium, Message: fmt.Sprintf("The %s short description has %d characters, the store requires %d to %d", locale.Name, length, minShortDescriptionLength, maxShortDescriptionLength)})
+		}
+
+		if length := len([]rune(plainText(info.Description))); length < minDescriptionLength {
+			items = append(items, storeAuditItem{Priority: storeAuditMedium, Message: fmt.Sprintf("The %s description has %d characters, at least %d are recommended", locale.Name, length, minDescriptionLength)})
+		}
+
+		if strings.TrimSpace(info.InstallationManual) == "" {
+			items = append(items, storeAuditItem{Priority: storeAuditLow, Message: fmt.Sprintf("Add a %s installation manual", locale.Name)})
+		}
+	}
+
+	return items
+}
+
+func auditCategories(ext *account_api.Extension, general *account_api.ExtensionGeneralInformation) []storeAuditItem {
+	var items []storeAuditItem
+
+	for _, category := range ext.Categories {
+		if !slices.ContainsFunc(general.Categories, func(c account_api.StoreCategory) bool { return c.Id == category.Id }) {
+			items = append(items, storeAuditItem{Priority: storeAuditHigh, Message: fmt.Sprintf("The category %s is no longer offered by the store, choose another one", categoryName(category))})
+		}
+	}
+
+	if len(general.FutureCategories) == 0 {
+		return items
+	}
+
+	if ext.Category == nil {
+		items = append(items, storeAuditItem{Priority: storeAuditMedium, Message: "Select a category of the new store category tree"})
+	} else if !slices.ContainsFunc(general.FutureCategories, func(c account_api.StoreCategory) bool { return c.Id == ext.Category.Id }) {
+		items = append(items, storeAuditItem{Priority: storeAuditHigh, Message: fmt.Sprintf("The category %s is no longer offered by the store, choose another one", categoryName(*ext.Category))})
+	}
+
+	return items
+}
+
+func categoryName(category account_api.StoreCategory) string {
+	if category.Description != "" {
+		return category.Description
+	}
+
+	return category.Name
+}
+
+func plainText(content string) string {
+	return strings.TrimSpace(html.UnescapeString(htmlTagRegExp.ReplaceAllString(content, "")))
+}
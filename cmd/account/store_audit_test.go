@@ -0,0 +1,88 @@
+package account
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+)
+
+func storeAuditFixture() (*account_api.Extension, *account_api.ExtensionGeneralInformation) {
+	ext := &account_api.Extension{
+		Name: "FroshTools",
+		Infos: []*account_api.ExtensionInfo{
+			{
+				Locale:             account_api.Locale{Name: "en_GB"},
+				ShortDescription:   strings.Repeat("a", 160),
+				Description:        "<p>" + strings.Repeat("b", 600) + "</p>",
+				InstallationManual: "Install it",
+			},
+		},
+		Categories: []account_api.StoreCategory{{Id: 1, Name: "Administration"}, {Id: 2, Name: "Legacy", Description: "Legacy category"}},
+	}
+
+	general := &account_api.ExtensionGeneralInformation{
+		Locales:    []account_api.Locale{{Name: "de_DE"}, {Name: "en_GB"}},
+		Categories: []account_api.StoreCategory{{Id: 1, Name: "Administration"}},
+	}
+
+	return ext, general
+}
+
+func TestAuditStoreListing(t *testing.T) {
+	ext, general := storeAuditFixture()
+
+	binaries := []*account_api.ExtensionBinary{
+		{CreationDate: "2024-01-01 10:00:00"},
+		{CreationDate: "2024-03-01 10:00:00"},
+		{CreationDate: "2024-06-01T10:00:00+00:00"},
+	}
+
+	images := []*account_api.ExtensionImage{
+		{CreationDate: "2023-06-01 10:00:00"},
+		{CreationDate: "2024-05-01 10:00:00"},
+	}
+
+	items := auditStoreListing(ext, binaries, images, general, storeAuditOptions{Releases: 2, MinDescriptionLength: 500})
+
+	assert.Equal(t, []storeAuditItem{
+		{Priority: storeAuditHigh, Message: "Add the de_DE texts, the listing has none"},
+		{Priority: storeAuditHigh, Message: "The category Legacy category is no longer offered by the store, choose another one"},
+		{Priority: storeAuditLow, Message: "1 of 2 screenshots are older than the last 2 releases"},
+	}, items)
+}
+
+func TestAuditStoreListingTexts(t *testing.T) {
+	ext, general := storeAuditFixture()
+	general.Locales = []account_api.Locale{{Name: "en_GB"}}
+	general.Categories = append(general.Categories, account_api.StoreCategory{Id: 2})
+	general.FutureCategories = []account_api.StoreCategory{{Id: 10}}
+
+	ext.Infos[0].ShortDescription = "Too short"
+	ext.Infos[0].Description = "<p>Short &amp; sweet</p>"
+	ext.Infos[0].InstallationManual = ""
+
+	items := auditStoreListing(ext, nil, nil, general, storeAuditOptions{Releases: 3, MinDescriptionLength: 500})
+
+	assert.Equal(t, []storeAuditItem{
+		{Priority: storeAuditHigh, Message: "Add screenshots, the listing has none"},
+		{Priority: storeAuditMedium, Message: "The en_GB short description has 9 characters, the store requires 150 to 185"},
+		{Priority: storeAuditMedium, Message: "The en_GB description has 13 characters, at least 500 are recommended"},
+		{Priority: storeAuditMedium, Message: "Select a category of the new store category tree"},
+		{Priority: storeAuditLow, Message: "Add a en_GB installation manual"},
+	}, items)
+}
+
+func TestAuditStoreListingUpToDate(t *testing.T) {
+	ext, general := storeAuditFixture()
+	general.Locales = []account_api.Locale{{Name: "en_GB"}}
+	ext.Categories = ext.Categories[:1]
+
+	// Less releases than configured, so no screenshot is outdated
+	binaries := []*account_api.ExtensionBinary{{CreationDate: "2024-06-01 10:00:00"}}
+	images := []*account_api.ExtensionImage{{CreationDate: "2020-01-01 10:00:00"}}
+
+	assert.Empty(t, auditStoreListing(ext, binaries, images, general, storeAuditOptions{Releases: 3, MinDescriptionLength: 500}))
+}
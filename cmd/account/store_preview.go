@@ -0,0 +1,223 @@
+package account
+
+import (
+	_ "embed"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/shopware/shopware-cli/extension"
+	accountApi "github.com/shopware/shopware-cli/internal/account-api"
+)
+
+//go:embed static/store-preview.html
+var storePreviewTemplate string
+
+// storeImageNameRegExp matches the names of the images in the image directory, the number is the priority
+var storeImageNameRegExp = regexp.MustCompile(`^(\d+)([_-][a-zA-Z0-9-_]+)?$`)
+
+type storePreview struct {
+	Name      string
+	Icon      template.URL
+	Languages []storePreviewLanguage
+}
+
+type storePreviewLanguage struct {
+	Code               string
+	Title              string
+	Label              string
+	ShortDescription   string
+	Description        template.HTML
+	InstallationManual template.HTML
+	MetaTitle          string
+	MetaDescription    string
+	Highlights         []string
+	Features           []string
+	Tags               []string
+	Videos             []string
+	Faq                []extension.ConfigStoreFaq
+	Images             []storePreviewImage
+}
+
+type storePreviewImage struct {
+	Name     string
+	Src      template.URL
+	Priority int
+	Preview  bool
+}
+
+// buildStorePreview collects the listing like info push would send it, the texts are sanitized like the store does
+func buildStorePreview(ext extension.Extension) (*storePreview, error) {
+	name, err := ext.GetName()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := ext.GetExtensionConfig()
+	metadata := ext.GetMetaData()
+
+	preview := &storePreview{Name: name}
+
+	iconPath := ext.GetIconPath()
+	if cfg.Store.Icon != nil {
+		iconPath = path.Join(ext.GetPath(), *cfg.Store.Icon)
+	}
+
+	if iconPath != "" {
+		if icon, err := imageDataURL(iconPath); err == nil {
+			preview.Icon = icon
+		}
+	}
+
+	for _, language := range []struct{ code, title, label, description string }{
+		{code: "de", title: "Deutsch (de_DE)", label: metadata.Label.German, description: metadata.Description.German},
+		{code: "en", title: "English (en_GB)", label: metadata.Label.English, description: metadata.Description.English},
+	} {
+		previewLanguage := storePreviewLanguage{
+			Code:             language.code,
+			Title:            language.title,
+			Label:            language.label,
+			ShortDescription: language.description,
+			Highlights:       translationOrEmpty(language.code, cfg.Store.Highlights),
+			Features:         translationOrEmpty(language.code, cfg.Store.Features),
+			Tags:             translationOrEmpty(language.code, cfg.Store.Tags),
+			Videos:           translationOrEmpty(language.code, cfg.Store.Videos),
+			Faq:              translationOrEmpty(language.code, cfg.Store.Faq),
+			MetaTitle:        translationOrEmpty(language.code, cfg.Store.MetaTitle),
+			MetaDescription:  translationOrEmpty(language.code, cfg.Store.MetaDescription),
+		}
+
+		if previewLanguage.Description, err = storePreviewHTML(ext, language.code, cfg.Store.Description); err != nil {
+			return nil, err
+		}
+
+		if previewLanguage.InstallationManual, err = storePreviewHTML(ext, language.code, cfg.Store.InstallationManual); err != nil {
+			return nil, err
+		}
+
+		if previewLanguage.Images, err = storePreviewImages(ext, cfg, language.code); err != nil {
+			return nil, err
+		}
+
+		preview.Languages = append(preview.Languages, previewLanguage)
+	}
+
+	return preview, nil
+}
+
+func renderStorePreview(w io.Writer, preview *storePreview) error {
+	tpl, err := template.New("store-preview").Parse(storePreviewTemplate)
+	if err != nil {
+		return err
+	}
+
+	return tpl.Execute(w, preview)
+}
+
+func translationOrEmpty[T extension.Translatable](language string, config extension.ConfigTranslated[T]) T {
+	var empty T
+
+	if value := getTranslation(language, config); value != nil {
+		return *value
+	}
+
+	return empty
+}
+
+func storePreviewHTML(ext extension.Extension, language string, config extension.ConfigTranslated[string]) (template.HTML, error) {
+	value := getTranslation(language, config)
+	if value == nil || *value == "" {
+		return "", nil
+	}
+
+	content, err := extension.ReadStoreText(*value, ext.GetPath())
+	if err != nil {
+		return "", err
+	}
+
+	// The content is sanitized with the preview policy, so it is safe to embed
+	return template.HTML(accountApi.SanitizeStoreHTML(content)), nil //nolint:gosec
+}
+
+// storePreviewImages returns the images shown in the language in the order of the store
+func storePreviewImages(ext extension.Extension, cfg *extension.Config, language string) ([]storePreviewImage, error) {
+	var images []storePreviewImage
+
+	switch {
+	case cfg.Store.ImageDirectory != nil:
+		directory := path.Join(ext.GetPath(), *cfg.Store.ImageDirectory, language)
+
+		entries, err := os.ReadDir(directory)
+		if err != nil {
+			return nil, nil //nolint:nilerr
+		}
+
+		files := slices.DeleteFunc(entries, func(entry os.DirEntry) bool { return entry.IsDir() })
+
+		for i, file := range files {
+			matches := storeImageNameRegExp.FindStringSubmatch(strings.TrimSuffix(file.Name(), filepath.Ext(file.Name())))
+			if matches == nil {
+				continue
+			}
+
+			priority, _ := strconv.Atoi(matches[1])
+
+			src, err := imageDataURL(path.Join(directory, file.Name()))
+			if err != nil {
+				return nil, err
+			}
+
+			// Like info push, the last image of the directory is the preview
+			images = append(images, storePreviewImage{Name: file.Name(), Src: src, Priority: priority, Preview: i == len(files)-1})
+		}
+	case cfg.Store.Images != nil:
+		for _, configImage := range *cfg.Store.Images {
+			activated, preview := configImage.Activate.German, configImage.Preview.German
+			if language == "en" {
+				activated, preview = configImage.Activate.English, configImage.Preview.English
+			}
+
+			if !activated {
+				continue
+			}
+
+			src, err := imageDataURL(path.Join(ext.GetPath(), configImage.File))
+			if err != nil {
+				return nil, err
+			}
+
+			images = append(images, storePreviewImage{Name: path.Base(configImage.File), Src: src, Priority: configImage.Priority, Preview: preview})
+		}
+	}
+
+	slices.SortStableFunc(images, func(a, b storePreviewImage) int {
+		return a.Priority - b.Priority
+	})
+
+	return images, nil
+}
+
+// imageDataURL embeds the image, so the preview is a single file which can be shared
+func imageDataURL(file string) (template.URL, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("cannot read image: %w", err)
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(file))
+	if contentType == "" {
+		contentType = http.DetectContentType(content)
+	}
+
+	return template.URL("data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(content)), nil //nolint:gosec
+}
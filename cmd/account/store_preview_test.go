@@ -0,0 +1,81 @@
+package account
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/extension"
+)
+
+func createPreviewPlugin(t *testing.T, config string, images ...string) extension.Extension {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"composer.json":           `{"name": "frosh/tools", "version": "1.1.0", "type": "shopware-platform-plugin", "require": {"shopware/core": "~6.6.0"}, "extra": {"shopware-plugin-class": "Frosh\\Tools\\FroshTools", "label": {"de-DE": "Tools", "en-GB": "Tools"}}}`,
+		".shopware-extension.yml": config,
+	}
+
+	for _, image := range images {
+		files[image] = "\x89PNG\r\n\x1a\n"
+	}
+
+	for file, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, file)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(content), 0o644))
+	}
+
+	ext, err := extension.GetExtensionByFolder(dir)
+	require.NoError(t, err)
+
+	return ext
+}
+
+func TestStorePreviewImagesOfDirectoryAreOrderedByPriority(t *testing.T) {
+	ext := createPreviewPlugin(t, "store:\n  image_directory: images\n",
+		"images/en/2-detail.png",
+		"images/en/1-overview.png",
+		"images/en/3_preview.png",
+	)
+
+	images, err := storePreviewImages(ext, ext.GetExtensionConfig(), "en")
+	require.NoError(t, err)
+
+	require.Len(t, images, 3)
+	assert.Equal(t, "1-overview.png", images[0].Name)
+	assert.Equal(t, "2-detail.png", images[1].Name)
+	assert.Equal(t, "3_preview.png", images[2].Name)
+	assert.False(t, images[1].Preview)
+	assert.True(t, images[2].Preview)
+	assert.Contains(t, string(images[0].Src), "data:image/png;base64,")
+
+	german, err := storePreviewImages(ext, ext.GetExtensionConfig(), "de")
+	require.NoError(t, err)
+	assert.Empty(t, german)
+}
+
+func TestStorePreviewRendersSanitizedDescription(t *testing.T) {
+	ext := createPreviewPlugin(t, `store:
+  description:
+    en: "<p>Great tool</p><script>alert(1)</script>"
+  highlights:
+    en:
+      - Fast cache clearing
+`)
+
+	preview, err := buildStorePreview(ext)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, renderStorePreview(&buf, preview))
+
+	assert.Contains(t, buf.String(), "<p>Great tool</p>")
+	assert.NotContains(t, buf.String(), "<script>alert(1)</script>")
+	assert.Contains(t, buf.String(), "Fast cache clearing")
+}
@@ -0,0 +1,327 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/extension"
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+	"github.com/shopware/shopware-cli/internal/doctor"
+	"github.com/shopware/shopware-cli/internal/metrics"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+const (
+	reviewFirstPollDelay = 10 * time.Second
+	reviewPollInterval   = 15 * time.Second
+	reviewMaxPolls       = 10
+)
+
+// StoreRelease publishes a zip in the Shopware Account step by step.
+// It is shared by account producer extension upload and extension release.
+type StoreRelease struct {
+	producer   *account_api.ProducerEndpoint
+	zipExt     extension.Extension
+	preflight  *uploadPreflight
+	onConflict string
+
+	// ZipPath is the uploaded file, a bumped version is written into a new zip
+	ZipPath string
+	Version *version.Version
+	// Binary is the binary of the version in the account, nil until it exists
+	Binary *account_api.ExtensionBinary
+
+	reviewsBefore int
+	labels        metrics.Labels
+	// cleanup removes the zip of a bumped version
+	cleanup func()
+}
+
+// PrepareStoreRelease runs the preflight for the zip and prints its checklist to out. It fails when the preflight found problems.
+func PrepareStoreRelease(ctx context.Context, client *account_api.Client, zipPath, onConflictFlag string, out io.Writer) (*StoreRelease, error) {
+	zipExt, err := extension.GetExtensionByZip(zipPath)
+	if err != nil {
+		return nil, err
+	}
+
+	onConflict, err := resolveUploadConflictStrategy(onConflictFlag, zipExt.GetExtensionConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	p, producerErr := client.Producer(ctx)
+
+	preflight := runUploadPreflight(ctx, zipExt, p, producerErr, onConflict)
+
+	if problems := doctor.Print(out, preflight.Results); problems > 0 {
+		return nil, fmt.Errorf("preflight found %d problem(s), nothing has been uploaded", problems)
+	}
+
+	return &StoreRelease{
+		producer:   p,
+		zipExt:     zipExt,
+		preflight:  preflight,
+		onConflict: onConflict,
+		ZipPath:    zipPath,
+		Version:    preflight.Version,
+		Binary:     preflight.Binary,
+		labels:     metrics.Labels{"extension": preflight.Extension.Name, "version": preflight.Version.String()},
+	}, nil
+}
+
+// Extension returns the extension in the account
+func (r *StoreRelease) Extension() *account_api.Extension {
+	return r.preflight.Extension
+}
+
+func (r *StoreRelease) Changelog() *extension.ExtensionChangelog {
+	return r.preflight.Changelog
+}
+
+func (r *StoreRelease) SoftwareVersions() []string {
+	return r.preflight.SoftwareVersions
+}
+
+// Skipped returns true when the version exists already and the conflict strategy is skip
+func (r *StoreRelease) Skipped() bool {
+	return r.Binary != nil && r.onConflict == uploadConflictSkip
+}
+
+// ApplyVersionBump switches to the next free patch version when the version exists and the conflict strategy is bump-patch.
+// Without dryRun the zip is rewritten with the new version.
+func (r *StoreRelease) ApplyVersionBump(ctx context.Context, dryRun bool) error {
+	if r.Binary == nil || r.onConflict != uploadConflictBumpPatch {
+		return nil
+	}
+
+	if !dryRun {
+		zipPath, cleanup, err := bumpZipVersion(r.zipExt, r.Version, r.preflight.BumpedVersion)
+		if err != nil {
+			return fmt.Errorf("bump version: %w", err)
+		}
+
+		r.ZipPath = zipPath
+		r.cleanup = cleanup
+	}
+
+	logging.FromContext(ctx).Infof("Version %s exists already in the account. Uploading as version %s", r.Version, r.preflight.BumpedVersion)
+
+	r.Version = r.preflight.BumpedVersion
+	r.labels["version"] = r.Version.String()
+	r.Binary = nil
+
+	return nil
+}
+
+// Close removes the zip written for a bumped version, the zip of the release is kept
+func (r *StoreRelease) Close() {
+	if r.cleanup != nil {
+		r.cleanup()
+		r.cleanup = nil
+	}
+}
+
+// RunBeforeUploadHooks runs the store.before_upload_hooks of the extension config with the zip in the environment
+func (r *StoreRelease) RunBeforeUploadHooks() error {
+	hooks := r.zipExt.GetExtensionConfig().Store.BeforeUploadHooks
+
+	if err := extension.RunHooks(hooks, "", extension.ArtifactHookEnv(r.ZipPath, r.Extension().Name, r.Version.String())); err != nil {
+		return fmt.Errorf("before upload hooks: %w", err)
+	}
+
+	return nil
+}
+
+// SaveBinary creates the binary of the version or updates the changelog and the compatible Shopware versions of the existing one
+func (r *StoreRelease) SaveBinary(ctx context.Context) error {
+	changelogs := []account_api.ExtensionUpdateChangelog{
+		{Locale: "de_DE", Text: r.Changelog().German},
+		{Locale: "en_GB", Text: r.Changelog().English},
+	}
+
+	if r.Binary == nil {
+		binary, err := r.producer.CreateExtensionBinary(ctx, r.Extension().Id, account_api.ExtensionCreate{
+			Version:          r.Version.String(),
+			SoftwareVersions: r.SoftwareVersions(),
+			Changelogs:       changelogs,
+		})
+		if err != nil {
+			return fmt.Errorf("create extension binary: %w", err)
+		}
+
+		r.Binary = binary
+
+		logging.FromContext(ctx).Infof("Created new binary with version %s", r.Version)
+	} else {
+		logging.FromContext(ctx).Infof("Found a zip with version %s already. Updating it", r.Version)
+	}
+
+	return r.producer.UpdateExtensionBinaryInfo(ctx, r.Extension().Id, account_api.ExtensionUpdate{
+		Id:               r.Binary.Id,
+		SoftwareVersions: r.SoftwareVersions(),
+		Changelogs:       changelogs,
+	})
+}
+
+// UploadZip uploads the zip into the binary. It returns false when the same content was uploaded already,
+// unless force is set, or when the version is published already.
+func (r *StoreRelease) UploadZip(ctx context.Context, force bool) (bool, error) {
+	if !force && isZipUploaded(ctx, r.producer, r.Extension().Id, r.Binary.Id, r.ZipPath) {
+		logging.FromContext(ctx).Infof("The zip for version %s was already uploaded with the same content. Skipping upload and code review, use --force to upload it anyway", r.Version)
+		return false, nil
+	}
+
+	logging.FromContext(ctx).Infof("Uploading now the zip to remote")
+
+	uploadStart := time.Now()
+
+	if err := r.producer.UpdateExtensionBinaryFile(ctx, r.Extension().Id, r.Binary.Id, r.ZipPath); err != nil {
+		if strings.Contains(err.Error(), "BinariesException-40") {
+			logging.FromContext(ctx).Infof("Binary version is already published. Skipping upload")
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	metrics.EmitDuration(ctx, metrics.UploadDuration, uploadStart, r.labels)
+
+	return true, nil
+}
+
+// SyncStoreInfo updates the store page, icon and images of the extension from the extension config of the zip
+func (r *StoreRelease) SyncStoreInfo(ctx context.Context) error {
+	return pushStoreInfo(ctx, r.producer, r.Extension(), r.zipExt)
+}
+
+// TriggerReview requests the automatic code review of the uploaded binary
+func (r *StoreRelease) TriggerReview(ctx context.Context) error {
+	logging.FromContext(ctx).Infof("Submitting code review request")
+
+	beforeReviews, err := r.producer.GetBinaryReviewResults(ctx, r.Extension().Id, r.Binary.Id)
+	if err != nil {
+		return err
+	}
+
+	r.reviewsBefore = len(beforeReviews)
+
+	return r.producer.TriggerCodeReview(ctx, r.Extension().Id)
+}
+
+// WaitForReview polls the triggered code review until it finished, finished is false when it took too long
+func (r *StoreRelease) WaitForReview(ctx context.Context) (review *account_api.BinaryReviewResult, finished bool, err error) {
+	logging.FromContext(ctx).Infof("Waiting for code review result")
+
+	reviewStart := time.Now()
+
+	time.Sleep(reviewFirstPollDelay)
+
+	for try := 0; try < reviewMaxPolls; try++ {
+		reviews, err := r.producer.GetBinaryReviewResults(ctx, r.Extension().Id, r.Binary.Id)
+		if err != nil {
+			return nil, false, err
+		}
+
+		// Review has been updated
+		if len(reviews) != r.reviewsBefore {
+			lastReview := reviews[len(reviews)-1]
+
+			if !lastReview.IsPending() {
+				metrics.EmitDuration(ctx, metrics.ReviewWaitDuration, reviewStart, r.labels)
+
+				return &lastReview, true, nil
+			}
+		}
+
+		time.Sleep(reviewPollInterval)
+	}
+
+	logging.FromContext(ctx).Infof("Skipping waiting for code review result as it took too long")
+
+	return nil, false, nil
+}
+
+// ReportFailedReview opens an issue for the failed review when store.review_issue is configured and returns its URL.
+// A failure to open the issue is only logged, as the failed review is the error to report.
+func (r *StoreRelease) ReportFailedReview(ctx context.Context, review *account_api.BinaryReviewResult) string {
+	issueURL, err := openReviewIssue(ctx, r.zipExt.GetExtensionConfig(), r.Extension().Name, r.Version.String(), review)
+	if err != nil {
+		logging.FromContext(ctx).Warnf("Cannot open an issue for the failed code review: %v", err)
+		return ""
+	}
+
+	if issueURL != "" {
+		logging.FromContext(ctx).Infof("Opened %s for the failed code review", issueURL)
+	}
+
+	return issueURL
+}
+
+// storeReleaseSteps are the account steps of an upload, StoreRelease implements them
+type storeReleaseSteps interface {
+	RunBeforeUploadHooks() error
+	SaveBinary(ctx context.Context) error
+	UploadZip(ctx context.Context, force bool) (bool, error)
+	TriggerReview(ctx context.Context) error
+	WaitForReview(ctx context.Context) (*account_api.BinaryReviewResult, bool, error)
+	ReportFailedReview(ctx context.Context, review *account_api.BinaryReviewResult) string
+}
+
+// publishStoreRelease uploads the zip into the binary and requests the code review. Without skipWait it fails when the
+// review has not passed. It stops at the first failed step, the review is only requested when a new zip was uploaded.
+func publishStoreRelease(ctx context.Context, release storeReleaseSteps, force, skipWait bool) error {
+	if err := release.RunBeforeUploadHooks(); err != nil {
+		return err
+	}
+
+	if err := release.SaveBinary(ctx); err != nil {
+		return err
+	}
+
+	uploaded, err := release.UploadZip(ctx, force)
+	if err != nil || !uploaded {
+		return err
+	}
+
+	if err := release.TriggerReview(ctx); err != nil {
+		return err
+	}
+
+	if skipWait {
+		return nil
+	}
+
+	review, finished, err := release.WaitForReview(ctx)
+	if err != nil || !finished {
+		return err
+	}
+
+	if err := EvaluateReview(ctx, review); err != nil {
+		release.ReportFailedReview(ctx, review)
+
+		return err
+	}
+
+	return nil
+}
+
+// EvaluateReview returns an error when the code review has not passed and logs the result of a passed one
+func EvaluateReview(ctx context.Context, review *account_api.BinaryReviewResult) error {
+	if !review.HasPassed() {
+		return fmt.Errorf("code review has not passed: %s", review.GetSummary())
+	}
+
+	if review.HasWarnings() {
+		logging.FromContext(ctx).Infof("Code review has been passed but with warnings")
+		logging.FromContext(ctx).Infof(review.GetSummary())
+	} else {
+		logging.FromContext(ctx).Infof("Code review has been passed without warnings")
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/shyim/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+)
+
+// fakeStoreRelease records the called steps, errors fails the step with the given name
+type fakeStoreRelease struct {
+	called   []string
+	errors   map[string]error
+	uploaded bool
+	review   *account_api.BinaryReviewResult
+	reported bool
+}
+
+func (f *fakeStoreRelease) step(name string) error {
+	f.called = append(f.called, name)
+
+	return f.errors[name]
+}
+
+func (f *fakeStoreRelease) RunBeforeUploadHooks() error { return f.step("hooks") }
+
+func (f *fakeStoreRelease) SaveBinary(context.Context) error { return f.step("binary") }
+
+func (f *fakeStoreRelease) UploadZip(context.Context, bool) (bool, error) {
+	return f.uploaded, f.step("upload")
+}
+
+func (f *fakeStoreRelease) TriggerReview(context.Context) error { return f.step("review") }
+
+func (f *fakeStoreRelease) WaitForReview(context.Context) (*account_api.BinaryReviewResult, bool, error) {
+	return f.review, f.review != nil, f.step("wait")
+}
+
+func (f *fakeStoreRelease) ReportFailedReview(context.Context, *account_api.BinaryReviewResult) string {
+	f.reported = true
+
+	return ""
+}
+
+func TestPublishStoreReleaseRunsAllSteps(t *testing.T) {
+	review := &account_api.BinaryReviewResult{}
+	review.Type.Id = 3
+
+	release := &fakeStoreRelease{uploaded: true, review: review}
+
+	require.NoError(t, publishStoreRelease(t.Context(), release, false, false))
+	assert.Equal(t, []string{"hooks", "binary", "upload", "review", "wait"}, release.called)
+	assert.False(t, release.reported)
+}
+
+func TestPublishStoreReleaseSkipsWaiting(t *testing.T) {
+	release := &fakeStoreRelease{uploaded: true}
+
+	require.NoError(t, publishStoreRelease(t.Context(), release, false, true))
+	assert.Equal(t, []string{"hooks", "binary", "upload", "review"}, release.called)
+}
+
+func TestPublishStoreReleaseSkipsReviewWithoutUpload(t *testing.T) {
+	release := &fakeStoreRelease{uploaded: false}
+
+	require.NoError(t, publishStoreRelease(t.Context(), release, false, false))
+	assert.Equal(t, []string{"hooks", "binary", "upload"}, release.called)
+}
+
+func TestPublishStoreReleaseStopsAtFailedStep(t *testing.T) {
+	for _, failing := range []string{"hooks", "binary", "upload", "review", "wait"} {
+		t.Run(failing, func(t *testing.T) {
+			stepErr := errors.New("failed")
+			release := &fakeStoreRelease{uploaded: true, errors: map[string]error{failing: stepErr}}
+
+			assert.ErrorIs(t, publishStoreRelease(t.Context(), release, false, false), stepErr)
+			assert.Equal(t, failing, release.called[len(release.called)-1])
+		})
+	}
+}
+
+func TestPublishStoreReleaseFailsOnFailedReview(t *testing.T) {
+	release := &fakeStoreRelease{uploaded: true, review: &account_api.BinaryReviewResult{}}
+
+	err := publishStoreRelease(t.Context(), release, false, false)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "code review has not passed")
+	assert.True(t, release.reported)
+}
+
+func TestStoreReleaseCloseRemovesBumpedZip(t *testing.T) {
+	ext := createPreflightPlugin(t, map[string]string{
+		"CHANGELOG_en-GB.md": "# 1.1.0\n- Added a feature\n",
+	})
+
+	zipPath, cleanup, err := bumpZipVersion(ext, version.Must(version.NewVersion("1.1.0")), version.Must(version.NewVersion("1.1.1")))
+	require.NoError(t, err)
+	assert.FileExists(t, zipPath)
+
+	release := &StoreRelease{ZipPath: zipPath, cleanup: cleanup}
+	release.Close()
+
+	assert.NoDirExists(t, filepath.Dir(zipPath))
+}
@@ -0,0 +1,90 @@
+package account
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/extension"
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+)
+
+// Strategies when the version of the zip exists already as binary in the account
+const (
+	uploadConflictSkip                 = "skip"
+	uploadConflictReplaceIfNotReviewed = "replace-if-not-reviewed"
+	uploadConflictBumpPatch            = "bump-patch"
+	uploadConflictFail                 = "fail"
+)
+
+var uploadConflictStrategies = []string{uploadConflictSkip, uploadConflictReplaceIfNotReviewed, uploadConflictBumpPatch, uploadConflictFail}
+
+// resolveUploadConflictStrategy prefers the flag over the extension config and defaults to replace-if-not-reviewed
+func resolveUploadConflictStrategy(flag string, cfg *extension.Config) (string, error) {
+	strategy := flag
+
+	if strategy == "" && cfg != nil && cfg.Store.UploadOnConflict != nil {
+		strategy = *cfg.Store.UploadOnConflict
+	}
+
+	if strategy == "" {
+		return uploadConflictReplaceIfNotReviewed, nil
+	}
+
+	if !slices.Contains(uploadConflictStrategies, strategy) {
+		return "", fmt.Errorf("invalid conflict strategy %q, must be one of %v", strategy, uploadConflictStrategies)
+	}
+
+	return strategy, nil
+}
+
+// nextFreePatchVersion increases the patch version until no binary exists with it
+func nextFreePatchVersion(current *version.Version, binaries []*account_api.ExtensionBinary) *version.Version {
+	segments := current.Segments()
+
+	for patch := segments[2] + 1; ; patch++ {
+		candidate := version.Must(version.NewVersion(fmt.Sprintf("%d.%d.%d", segments[0], segments[1], patch)))
+
+		if !slices.ContainsFunc(binaries, func(binary *account_api.ExtensionBinary) bool { return binary.Version == candidate.String() }) {
+			return candidate
+		}
+	}
+}
+
+// bumpZipVersion writes the extracted extension with the new version and a copied changelog entry into a new zip.
+// The zip is created in a temporary folder, which the returned cleanup removes.
+func bumpZipVersion(zipExt extension.Extension, oldVersion, newVersion *version.Version) (string, func(), error) {
+	if err := extension.BuildModifier(zipExt, zipExt.GetPath(), extension.BuildModifierConfig{Version: newVersion.String()}); err != nil {
+		return "", nil, err
+	}
+
+	if err := extension.CopyChangelogEntry(zipExt.GetPath(), oldVersion.String(), newVersion.String()); err != nil {
+		return "", nil, err
+	}
+
+	name, err := zipExt.GetName()
+	if err != nil {
+		return "", nil, err
+	}
+
+	outputDir, err := os.MkdirTemp("", "extension-upload")
+	if err != nil {
+		return "", nil, err
+	}
+
+	cleanup := func() {
+		_ = os.RemoveAll(outputDir)
+	}
+
+	zipFile := filepath.Join(outputDir, fmt.Sprintf("%s-%s.zip", name, newVersion.String()))
+
+	if err := extension.CreateZip(filepath.Dir(zipExt.GetPath()), zipFile); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("create zip file: %w", err)
+	}
+
+	return zipFile, cleanup, nil
+}
@@ -0,0 +1,75 @@
+package account
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// binaryFileDownloader downloads the current file of a binary, the producer endpoint implements it
+type binaryFileDownloader interface {
+	DownloadExtensionBinaryFile(ctx context.Context, extensionId, binaryId int, target string) error
+}
+
+// isZipUploaded reports whether the binary in the account contains the same file as the zip, so a repeated pipeline
+// run does not upload the same file again and re-trigger the code review. The account is asked instead of a local
+// state, which would be missing on fresh CI runners. The binary metadata has neither a hash nor the size of the file,
+// so the file is downloaded and its size compared before the SHA-256.
+func isZipUploaded(ctx context.Context, downloader binaryFileDownloader, extensionId, binaryId int, zipPath string) bool {
+	zipInfo, err := os.Stat(zipPath)
+	if err != nil {
+		return false
+	}
+
+	tmpDir, err := os.MkdirTemp("", "shopware-cli-binary-*")
+	if err != nil {
+		return false
+	}
+
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	uploadedZip := filepath.Join(tmpDir, "binary.zip")
+
+	// Binaries without file answer with an error
+	if err := downloader.DownloadExtensionBinaryFile(ctx, extensionId, binaryId, uploadedZip); err != nil {
+		logging.FromContext(ctx).Debugf("Cannot download the current file of binary %d: %v", binaryId, err)
+		return false
+	}
+
+	if uploadedInfo, err := os.Stat(uploadedZip); err != nil || uploadedInfo.Size() != zipInfo.Size() {
+		return false
+	}
+
+	zipHash, err := hashZipFile(zipPath)
+	if err != nil {
+		return false
+	}
+
+	uploadedHash, err := hashZipFile(uploadedZip)
+	if err != nil {
+		return false
+	}
+
+	return uploadedHash == zipHash
+}
+
+func hashZipFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
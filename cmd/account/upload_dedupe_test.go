@@ -0,0 +1,53 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBinaryFileDownloader struct {
+	files map[int]string
+}
+
+func (f fakeBinaryFileDownloader) DownloadExtensionBinaryFile(_ context.Context, _, binaryId int, target string) error {
+	content, ok := f.files[binaryId]
+	if !ok {
+		return errors.New("DownloadExtensionBinaryFile: not found")
+	}
+
+	return os.WriteFile(target, []byte(content), 0o644)
+}
+
+func TestIsZipUploaded(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "FroshTools.zip")
+	require.NoError(t, os.WriteFile(zipPath, []byte("zip content"), 0o644))
+
+	downloader := fakeBinaryFileDownloader{files: map[int]string{
+		1: "zip content",
+		2: "other content",
+		4: "zip contenT",
+	}}
+
+	assert.True(t, isZipUploaded(t.Context(), downloader, 10, 1, zipPath))
+	assert.False(t, isZipUploaded(t.Context(), downloader, 10, 2, zipPath), "a zip with another size is uploaded again")
+	assert.False(t, isZipUploaded(t.Context(), downloader, 10, 4, zipPath), "a changed zip with the same size is uploaded again")
+	assert.False(t, isZipUploaded(t.Context(), downloader, 10, 3, zipPath), "a binary without file gets the upload")
+}
+
+func TestHashZipFile(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "FroshTools.zip")
+	require.NoError(t, os.WriteFile(zipPath, []byte("zip content"), 0o644))
+
+	hash, err := hashZipFile(zipPath)
+	require.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	_, err = hashZipFile(filepath.Join(t.TempDir(), "missing.zip"))
+	assert.Error(t, err)
+}
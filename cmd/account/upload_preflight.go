@@ -0,0 +1,294 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/extension"
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+	"github.com/shopware/shopware-cli/internal/doctor"
+	"github.com/shopware/shopware-cli/internal/shopwareversion"
+)
+
+type uploadPreflightProducer interface {
+	GetExtensionByName(ctx context.Context, name string) (*account_api.Extension, error)
+	GetExtensionBinaries(ctx context.Context, extensionId int) ([]*account_api.ExtensionBinary, error)
+	GetSoftwareVersions(ctx context.Context, generation string) (*account_api.SoftwareVersionList, error)
+	GetBinaryReviewResults(ctx context.Context, extensionId, binaryId int) ([]account_api.BinaryReviewResult, error)
+}
+
+// uploadPreflight contains the results of all checks before an upload and the data the upload needs.
+// All checks run, also when an earlier one fails, so the user sees every problem at once.
+type uploadPreflight struct {
+	Results []doctor.Result
+
+	Extension *account_api.Extension
+	// Binary is the existing binary with the version of the zip
+	Binary *account_api.ExtensionBinary
+	// BumpedVersion is the next free patch version, when the version exists and the conflict strategy is bump-patch
+	BumpedVersion    *version.Version
+	Version          *version.Version
+	Changelog        *extension.ExtensionChangelog
+	SoftwareVersions []string
+}
+
+func (p *uploadPreflight) Failed() bool {
+	for _, result := range p.Results {
+		if result.Status == doctor.StatusError {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *uploadPreflight) add(result doctor.Result) {
+	p.Results = append(p.Results, result)
+}
+
+func skippedResult(name, reason string) doctor.Result {
+	return doctor.Result{Name: name, Status: doctor.StatusWarning, Message: fmt.Sprintf("Skipped, %s", reason)}
+}
+
+// runUploadPreflight checks the zip and the account. The producer is nil when the login failed with producerErr.
+func runUploadPreflight(ctx context.Context, zipExt extension.Extension, producer uploadPreflightProducer, producerErr error, onConflict string) *uploadPreflight {
+	preflight := &uploadPreflight{}
+
+	if producerErr != nil {
+		producer = nil
+
+		preflight.add(doctor.Result{
+			Name:    "Account credentials",
+			Status:  doctor.StatusError,
+			Message: fmt.Sprintf("Cannot load the producer: %s", producerErr.Error()),
+			Fix:     "Run shopware-cli account login and make sure the active company has a producer account",
+		})
+	} else {
+		preflight.add(doctor.Result{Name: "Account credentials", Message: "Credentials are valid"})
+	}
+
+	extName, nameErr := zipExt.GetName()
+	zipVersion, versionErr := zipExt.GetVersion()
+
+	preflight.Version = zipVersion
+
+	preflight.checkExtension(ctx, producer, extName, nameErr)
+	preflight.checkVersion(ctx, producer, versionErr, onConflict)
+	preflight.checkChangelog(zipExt, versionErr)
+	preflight.checkSoftwareVersions(ctx, producer, zipExt)
+
+	return preflight
+}
+
+func (p *uploadPreflight) checkExtension(ctx context.Context, producer uploadPreflightProducer, extName string, nameErr error) {
+	const name = "Extension in account"
+
+	if nameErr != nil {
+		p.add(doctor.Result{Name: name, Status: doctor.StatusError, Message: fmt.Sprintf("Cannot read the extension name of the zip: %s", nameErr.Error())})
+		return
+	}
+
+	if producer == nil {
+		p.add(skippedResult(name, "no valid credentials"))
+		return
+	}
+
+	ext, err := producer.GetExtensionByName(ctx, extName)
+	if err != nil {
+		p.add(doctor.Result{
+			Name:    name,
+			Status:  doctor.StatusError,
+			Message: err.Error(),
+			Fix:     fmt.Sprintf("Create the extension %s in the Shopware Account or switch the company with shopware-cli account company use", extName),
+		})
+
+		return
+	}
+
+	p.Extension = ext
+	p.add(doctor.Result{Name: name, Message: fmt.Sprintf("Found %s", extName)})
+}
+
+func (p *uploadPreflight) checkVersion(ctx context.Context, producer uploadPreflightProducer, versionErr error, onConflict string) {
+	const name = "Version"
+
+	if versionErr != nil {
+		p.add(doctor.Result{Name: name, Status: doctor.StatusError, Message: fmt.Sprintf("Cannot read the version of the zip: %s", versionErr.Error())})
+		return
+	}
+
+	if producer == nil || p.Extension == nil {
+		p.add(skippedResult(name, "the extension was not found in the account"))
+		return
+	}
+
+	binaries, err := producer.GetExtensionBinaries(ctx, p.Extension.Id)
+	if err != nil {
+		p.add(doctor.Result{Name: name, Status: doctor.StatusError, Message: fmt.Sprintf("Cannot load the uploaded versions: %s", err.Error())})
+		return
+	}
+
+	for _, binary := range binaries {
+		if binary.Version == p.Version.String() {
+			p.Binary = binary
+			break
+		}
+	}
+
+	if p.Binary == nil {
+		p.add(doctor.Result{Name: name, Message: fmt.Sprintf("%s is not uploaded yet", p.Version.String())})
+		return
+	}
+
+	p.add(p.resolveVersionConflict(ctx, producer, binaries, onConflict))
+}
+
+// resolveVersionConflict decides with the conflict strategy what happens with the existing binary of the version
+func (p *uploadPreflight) resolveVersionConflict(ctx context.Context, producer uploadPreflightProducer, binaries []*account_api.ExtensionBinary, onConflict string) doctor.Result {
+	result := doctor.Result{Name: "Version", Status: doctor.StatusWarning}
+	exists := fmt.Sprintf("%s exists already in the account (status %s)", p.Version.String(), p.Binary.Status.Name)
+
+	switch onConflict {
+	case uploadConflictFail:
+		result.Status = doctor.StatusError
+		result.Message = exists
+		result.Fix = "Increase the version in the composer.json or manifest.xml or choose another strategy with --on-conflict"
+	case uploadConflictSkip:
+		result.Message = exists + ", the upload will be skipped"
+	case uploadConflictBumpPatch:
+		p.BumpedVersion = nextFreePatchVersion(p.Version, binaries)
+		result.Message = fmt.Sprintf("%s, the zip will be uploaded as %s", exists, p.BumpedVersion.String())
+	default:
+		reviews, err := producer.GetBinaryReviewResults(ctx, p.Extension.Id, p.Binary.Id)
+		if err != nil {
+			result.Status = doctor.StatusError
+			result.Message = fmt.Sprintf("Cannot load the code reviews of %s: %s", p.Version.String(), err.Error())
+
+			return result
+		}
+
+		for _, review := range reviews {
+			if review.HasPassed() {
+				result.Status = doctor.StatusError
+				result.Message = exists + " and passed the code review already, it cannot be replaced"
+				result.Fix = "Increase the version in the composer.json or manifest.xml or use --on-conflict skip or bump-patch"
+
+				return result
+			}
+		}
+
+		result.Message = exists + " and is not reviewed yet, it will be replaced"
+	}
+
+	return result
+}
+
+func (p *uploadPreflight) checkChangelog(zipExt extension.Extension, versionErr error) {
+	const name = "Changelog"
+
+	if versionErr != nil {
+		p.add(skippedResult(name, "the version of the zip is unknown"))
+		return
+	}
+
+	changelog, err := zipExt.GetChangelog()
+	if err != nil {
+		p.add(doctor.Result{
+			Name:    name,
+			Status:  doctor.StatusError,
+			Message: err.Error(),
+			Fix:     fmt.Sprintf("Add a section for %s to the CHANGELOG.md and CHANGELOG_de-DE.md", p.Version.String()),
+		})
+
+		return
+	}
+
+	missing := []string{}
+
+	if strings.TrimSpace(changelog.English) == "" {
+		missing = append(missing, "en-GB")
+	}
+
+	if strings.TrimSpace(changelog.German) == "" {
+		missing = append(missing, "de-DE")
+	}
+
+	if len(missing) > 0 {
+		p.add(doctor.Result{
+			Name:    name,
+			Status:  doctor.StatusError,
+			Message: fmt.Sprintf("The changelog of %s is empty for %s", p.Version.String(), strings.Join(missing, ", ")),
+			Fix:     "Describe the changes of the version in the changelog of every locale",
+		})
+
+		return
+	}
+
+	p.Changelog = changelog
+	p.add(doctor.Result{Name: name, Message: fmt.Sprintf("Found changelog of %s for %d locale(s)", p.Version.String(), max(len(changelog.Changelogs), 1))})
+}
+
+func (p *uploadPreflight) checkSoftwareVersions(ctx context.Context, producer uploadPreflightProducer, zipExt extension.Extension) {
+	const name = "Compatible Shopware versions"
+
+	constraint, err := zipExt.GetShopwareVersionConstraint()
+	if err != nil {
+		p.add(doctor.Result{Name: name, Status: doctor.StatusError, Message: fmt.Sprintf("Cannot read the Shopware version constraint: %s", err.Error())})
+		return
+	}
+
+	if producer == nil {
+		p.checkReleasedSoftwareVersions(ctx, constraint)
+		return
+	}
+
+	availableVersions, err := producer.GetSoftwareVersions(ctx, zipExt.GetType())
+	if err != nil {
+		p.add(doctor.Result{Name: name, Status: doctor.StatusError, Message: fmt.Sprintf("Cannot load the Shopware versions of the account: %s", err.Error())})
+		return
+	}
+
+	p.SoftwareVersions = availableVersions.FilterOnVersionStringList(constraint)
+
+	if len(p.SoftwareVersions) == 0 {
+		p.add(doctor.Result{
+			Name:    name,
+			Status:  doctor.StatusError,
+			Message: fmt.Sprintf("The constraint %s matches no selectable Shopware version", constraint.String()),
+			Fix:     "Adjust the shopware/core requirement or build.shopwareVersionConstraint in the extension config",
+		})
+
+		return
+	}
+
+	p.add(doctor.Result{Name: name, Message: strings.Join(p.SoftwareVersions, ", ")})
+}
+
+// checkReleasedSoftwareVersions matches the constraint against the released Shopware versions, as the selectable versions of the account are unknown without login
+func (p *uploadPreflight) checkReleasedSoftwareVersions(ctx context.Context, constraint *version.Constraints) {
+	const name = "Compatible Shopware versions"
+
+	metadata, err := shopwareversion.Load(ctx)
+	if err != nil {
+		p.add(skippedResult(name, "no valid credentials"))
+		return
+	}
+
+	p.SoftwareVersions = account_api.NewSoftwareVersionList(metadata.Versions()).FilterOnVersionStringList(constraint)
+
+	if len(p.SoftwareVersions) == 0 {
+		p.add(doctor.Result{
+			Name:    name,
+			Status:  doctor.StatusError,
+			Message: fmt.Sprintf("The constraint %s matches no released Shopware version", constraint.String()),
+			Fix:     "Adjust the shopware/core requirement or build.shopwareVersionConstraint in the extension config",
+		})
+
+		return
+	}
+
+	p.add(doctor.Result{Name: name, Status: doctor.StatusWarning, Message: fmt.Sprintf("No valid credentials, the released versions are %s", strings.Join(p.SoftwareVersions, ", "))})
+}
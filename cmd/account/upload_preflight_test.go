@@ -0,0 +1,193 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/extension"
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+	"github.com/shopware/shopware-cli/internal/doctor"
+)
+
+type fakePreflightProducer struct {
+	extension *account_api.Extension
+	binaries  []*account_api.ExtensionBinary
+	versions  account_api.SoftwareVersionList
+	reviews   []account_api.BinaryReviewResult
+}
+
+func (f fakePreflightProducer) GetExtensionByName(_ context.Context, name string) (*account_api.Extension, error) {
+	if f.extension == nil {
+		return nil, errors.New("cannot find Extension by name " + name)
+	}
+
+	return f.extension, nil
+}
+
+func (f fakePreflightProducer) GetExtensionBinaries(_ context.Context, _ int) ([]*account_api.ExtensionBinary, error) {
+	return f.binaries, nil
+}
+
+func (f fakePreflightProducer) GetSoftwareVersions(_ context.Context, _ string) (*account_api.SoftwareVersionList, error) {
+	return &f.versions, nil
+}
+
+func (f fakePreflightProducer) GetBinaryReviewResults(_ context.Context, _, _ int) ([]account_api.BinaryReviewResult, error) {
+	return f.reviews, nil
+}
+
+func createPreflightPlugin(t *testing.T, changelogs map[string]string) extension.Extension {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"composer.json": `{"name": "frosh/tools", "version": "1.1.0", "type": "shopware-platform-plugin", "require": {"shopware/core": "~6.6.0"}, "extra": {"shopware-plugin-class": "Frosh\\Tools\\FroshTools"}}`,
+	}
+
+	for file, content := range changelogs {
+		files[file] = content
+	}
+
+	for file, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(content), 0o644))
+	}
+
+	ext, err := extension.GetExtensionByFolder(dir)
+	require.NoError(t, err)
+
+	return ext
+}
+
+func resultStatuses(preflight *uploadPreflight) map[string]doctor.Status {
+	statuses := map[string]doctor.Status{}
+
+	for _, result := range preflight.Results {
+		statuses[result.Name] = result.Status
+	}
+
+	return statuses
+}
+
+func TestUploadPreflightPasses(t *testing.T) {
+	ext := createPreflightPlugin(t, map[string]string{
+		"CHANGELOG_en-GB.md": "# 1.1.0\n- Added a feature\n",
+		"CHANGELOG_de-DE.md": "# 1.1.0\n- Neue Funktion\n",
+	})
+
+	producer := fakePreflightProducer{
+		extension: &account_api.Extension{Id: 5},
+		binaries:  []*account_api.ExtensionBinary{{Id: 1, Version: "1.0.0"}},
+		versions: account_api.SoftwareVersionList{
+			{Name: "6.5.8.0", Selectable: true},
+			{Name: "6.6.0.0", Selectable: true},
+			{Name: "6.6.1.0", Selectable: false},
+		},
+	}
+
+	preflight := runUploadPreflight(t.Context(), ext, producer, nil, uploadConflictReplaceIfNotReviewed)
+
+	assert.False(t, preflight.Failed())
+	assert.Len(t, preflight.Results, 5)
+	assert.Nil(t, preflight.Binary)
+	assert.Equal(t, 5, preflight.Extension.Id)
+	assert.Contains(t, preflight.Changelog.English, "Added a feature")
+	assert.Equal(t, []string{"6.6.0.0"}, preflight.SoftwareVersions)
+
+	producer.binaries = append(producer.binaries, &account_api.ExtensionBinary{Id: 2, Version: "1.1.0"})
+
+	preflight = runUploadPreflight(t.Context(), ext, producer, nil, uploadConflictReplaceIfNotReviewed)
+
+	assert.False(t, preflight.Failed())
+	assert.Equal(t, 2, preflight.Binary.Id)
+	assert.Equal(t, doctor.StatusWarning, resultStatuses(preflight)["Version"])
+}
+
+func TestUploadPreflightReportsAllProblems(t *testing.T) {
+	ext := createPreflightPlugin(t, map[string]string{
+		"CHANGELOG_en-GB.md": "# 1.0.0\n- Old version\n",
+	})
+
+	preflight := runUploadPreflight(t.Context(), ext, fakePreflightProducer{}, nil, uploadConflictReplaceIfNotReviewed)
+
+	assert.True(t, preflight.Failed())
+	assert.Equal(t, map[string]doctor.Status{
+		"Account credentials":          doctor.StatusOK,
+		"Extension in account":         doctor.StatusError,
+		"Version":                      doctor.StatusWarning,
+		"Changelog":                    doctor.StatusError,
+		"Compatible Shopware versions": doctor.StatusError,
+	}, resultStatuses(preflight))
+}
+
+func TestUploadPreflightWithoutCredentials(t *testing.T) {
+	ext := createPreflightPlugin(t, nil)
+
+	var producer *account_api.ProducerEndpoint
+
+	preflight := runUploadPreflight(t.Context(), ext, producer, errors.New("login failed"), uploadConflictReplaceIfNotReviewed)
+
+	assert.True(t, preflight.Failed())
+	assert.Equal(t, doctor.StatusError, preflight.Results[0].Status)
+	assert.Equal(t, "Skipped, no valid credentials", preflight.Results[1].Message)
+	assert.Equal(t, doctor.StatusWarning, resultStatuses(preflight)["Compatible Shopware versions"])
+	assert.Contains(t, preflight.SoftwareVersions, "6.6.0.0")
+}
+
+func TestUploadPreflightVersionConflict(t *testing.T) {
+	ext := createPreflightPlugin(t, map[string]string{
+		"CHANGELOG_en-GB.md": "# 1.1.0\n- Added a feature\n",
+	})
+
+	producer := fakePreflightProducer{
+		extension: &account_api.Extension{Id: 5},
+		binaries:  []*account_api.ExtensionBinary{{Id: 1, Version: "1.1.0"}, {Id: 2, Version: "1.1.1"}},
+		versions:  account_api.SoftwareVersionList{{Name: "6.6.0.0", Selectable: true}},
+	}
+
+	preflight := runUploadPreflight(t.Context(), ext, producer, nil, uploadConflictFail)
+	assert.Equal(t, doctor.StatusError, resultStatuses(preflight)["Version"])
+
+	preflight = runUploadPreflight(t.Context(), ext, producer, nil, uploadConflictSkip)
+	assert.Equal(t, doctor.StatusWarning, resultStatuses(preflight)["Version"])
+
+	preflight = runUploadPreflight(t.Context(), ext, producer, nil, uploadConflictBumpPatch)
+	assert.False(t, preflight.Failed())
+	assert.Equal(t, "1.1.2", preflight.BumpedVersion.String())
+
+	preflight = runUploadPreflight(t.Context(), ext, producer, nil, uploadConflictReplaceIfNotReviewed)
+	assert.False(t, preflight.Failed())
+
+	producer.reviews = []account_api.BinaryReviewResult{{}}
+	producer.reviews[0].Type.Id = 3
+
+	preflight = runUploadPreflight(t.Context(), ext, producer, nil, uploadConflictReplaceIfNotReviewed)
+	assert.True(t, preflight.Failed())
+}
+
+func TestResolveUploadConflictStrategy(t *testing.T) {
+	strategy, err := resolveUploadConflictStrategy("", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uploadConflictReplaceIfNotReviewed, strategy)
+
+	cfg := &extension.Config{}
+	configured := uploadConflictSkip
+	cfg.Store.UploadOnConflict = &configured
+
+	strategy, err = resolveUploadConflictStrategy("", cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, uploadConflictSkip, strategy)
+
+	strategy, err = resolveUploadConflictStrategy(uploadConflictFail, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, uploadConflictFail, strategy)
+
+	_, err = resolveUploadConflictStrategy("overwrite", cfg)
+	assert.Error(t, err)
+}
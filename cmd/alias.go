@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/internal/config"
+)
+
+// expandAliases rewrites the arguments using the aliases of the user config and registers
+// the aliases as commands, so they show up in the help and in shell completions.
+func expandAliases(args []string) ([]string, error) {
+	if err := config.InitConfig(configFileFromArgs(args)); err != nil {
+		return args, nil
+	}
+
+	aliases := config.Config{}.GetAliases()
+
+	expanded, err := config.ExpandAlias(args, aliases, isBuiltinCommand)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, definition := range aliases {
+		if isBuiltinCommand(name) {
+			continue
+		}
+
+		rootCmd.AddCommand(&cobra.Command{
+			Use:                name,
+			Short:              fmt.Sprintf("Alias for %q", definition),
+			DisableFlagParsing: true,
+		})
+	}
+
+	return expanded, nil
+}
+
+func isBuiltinCommand(name string) bool {
+	if name == "help" || name == "completion" {
+		return true
+	}
+
+	cmd, _, err := rootCmd.Find([]string{name})
+
+	return err == nil && cmd != rootCmd
+}
+
+func configFileFromArgs(args []string) string {
+	for i, arg := range args {
+		if arg == "--config" && i+1 < len(args) {
+			return args[i+1]
+		}
+
+		if value, ok := strings.CutPrefix(arg, "--config="); ok {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	accountApi "github.com/shopware/shopware-cli/internal/account-api"
+	"github.com/shopware/shopware-cli/internal/config"
+	"github.com/shopware/shopware-cli/internal/doctor"
+	"github.com/shopware/shopware-cli/internal/shopwareversion"
+	"github.com/shopware/shopware-cli/internal/system"
+	"github.com/shopware/shopware-cli/shop"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Checks your local environment and shows how to fix common problems",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		projectConfig, _ := cmd.Flags().GetString("project-config")
+
+		checks, err := doctorChecks(cmd.Context(), projectConfig)
+		if err != nil {
+			return err
+		}
+
+		results := doctor.Run(cmd.Context(), checks)
+
+		problems := doctor.Print(os.Stdout, results)
+
+		if problems > 0 {
+			return fmt.Errorf("doctor found %d problem(s)", problems)
+		}
+
+		return nil
+	},
+}
+
+func doctorChecks(ctx context.Context, projectConfigPath string) ([]doctor.Check, error) {
+	versions, err := shopwareversion.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	phpVersion, nodeVersion, err := versions.MinimumRequirements(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	conf := config.Config{}
+	httpClient := &http.Client{}
+
+	accountUrl := conf.GetAccountApiUrl()
+	if accountUrl == "" {
+		accountUrl = accountApi.DefaultApiUrl
+	}
+
+	checks := []doctor.Check{
+		doctor.PHPCheck(phpVersion),
+		doctor.ComposerCheck(),
+		doctor.NodeCheck(nodeVersion),
+		doctor.DockerCheck(),
+		doctor.CacheDirCheck(system.GetShopwareCliCacheDir()),
+		doctor.ReachabilityCheck("Shopware Account API", accountUrl, httpClient),
+		doctor.ReachabilityCheck("Shopware Packages", "https://packages.shopware.com", httpClient),
+		doctor.CredentialsCheck(
+			"Shopware Account credentials",
+			conf.GetAccountEmail() != "" && conf.GetAccountPassword() != "",
+			func(ctx context.Context) error {
+				// The cached token stays valid after the password changed, so the credentials are checked with a new login
+				_, err := accountApi.Login(ctx, conf)
+				return err
+			},
+			"Run shopware-cli account login or set SHOPWARE_CLI_ACCOUNT_EMAIL and SHOPWARE_CLI_ACCOUNT_PASSWORD",
+		),
+	}
+
+	if _, err := os.Stat(projectConfigPath); err != nil {
+		return checks, nil
+	}
+
+	cfg, err := shop.ReadConfig(projectConfigPath, false)
+	if err != nil || cfg.URL == "" {
+		return checks, nil
+	}
+
+	checks = append(checks,
+		doctor.ReachabilityCheck("Shop", strings.TrimSuffix(cfg.URL, "/")+"/api/_info/version", httpClient),
+		doctor.CredentialsCheck(
+			"Shop Admin API credentials",
+			cfg.IsAdminAPIConfigured(),
+			func(ctx context.Context) error {
+				_, err := shop.NewShopClient(ctx, cfg)
+				return err
+			},
+			"Configure admin_api in "+projectConfigPath+" or set SHOPWARE_CLI_API_CLIENT_ID and SHOPWARE_CLI_API_CLIENT_SECRET",
+		),
+	)
+
+	return checks, nil
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().String("project-config", ".shopware-project.yml", "Path to the project config to check the shop connection")
+}
@@ -1,6 +1,7 @@
 package extension
 
 import (
+	"context"
 	_ "embed"
 	"encoding/json"
 	"fmt"
@@ -70,302 +71,322 @@ var extensionAdminWatchCmd = &cobra.Command{
 			sources = append(sources, extension.ConvertExtensionsToSources(cmd.Context(), []extension.Extension{ext})...)
 		}
 
-		cfgs := extension.BuildAssetConfigFromExtensions(cmd.Context(), sources, extension.AssetBuildConfig{}).FilterByAdmin()
-
-		if len(cfgs) == 0 {
-			return fmt.Errorf("found nothing to compile")
-		}
-
-		if _, err := extension.InstallNodeModulesOfConfigs(cmd.Context(), cfgs, false); err != nil {
-			return err
-		}
-
-		esbuildInstances := make(map[string]adminWatchExtension)
+		return runAdminWatch(cmd, sources, args[len(args)-1])
+	},
+}
 
-		for name, entry := range cfgs {
-			options := esbuild.NewAssetCompileOptionsAdmin(name, entry.BasePath)
-			options.ProductionMode = false
-			options.DisableSass = entry.DisableSass
+// runAdminWatch compiles the administration assets of the sources with esbuild and serves the shop through a proxy injecting them
+func runAdminWatch(cmd *cobra.Command, sources []asset.Source, shopURL string) error {
+	cfgs := extension.BuildAssetConfigFromExtensions(cmd.Context(), sources, extension.AssetBuildConfig{}).FilterByAdmin()
 
-			esbuildContext, err := esbuild.Context(cmd.Context(), options)
-			if err != nil {
-				return err
-			}
+	if len(cfgs) == 0 {
+		return fmt.Errorf("found nothing to compile")
+	}
 
-			if err := esbuildContext.Watch(api.WatchOptions{}); err != nil {
-				return err
-			}
+	if _, err := extension.InstallNodeModulesOfConfigs(cmd.Context(), cfgs, false); err != nil {
+		return err
+	}
 
-			watchServer, contextError := esbuildContext.Serve(api.ServeOptions{
-				Host: "127.0.0.1",
-			})
+	esbuildInstances := make(map[string]adminWatchExtension)
 
-			if contextError != nil {
-				return err
-			}
+	for name, entry := range cfgs {
+		options := esbuild.NewAssetCompileOptionsAdmin(name, entry.BasePath)
+		options.ProductionMode = false
+		options.DisableSass = entry.DisableSass
 
-			esbuildInstances[entry.TechnicalName] = adminWatchExtension{
-				name:        name,
-				assetName:   entry.TechnicalName,
-				context:     esbuildContext,
-				watchServer: watchServer,
-				staticDir:   path.Join(entry.BasePath, "Resources", "app", "static"),
-			}
+		esbuildContext, err := esbuild.Context(cmd.Context(), options)
+		if err != nil {
+			return err
 		}
 
-		listenSplit := strings.Split(adminWatchListen, ":")
-
-		if len(listenSplit) != 2 {
-			return fmt.Errorf("listen should contain a colon")
+		if err := esbuildContext.Watch(api.WatchOptions{}); err != nil {
+			return err
 		}
 
-		if len(adminWatchURL) == 0 {
-			adminWatchURL = "http://localhost:" + listenSplit[1]
-		}
+		watchServer, contextError := esbuildContext.Serve(api.ServeOptions{
+			Host: "127.0.0.1",
+		})
 
-		browserUrl, err := url.Parse(adminWatchURL)
-		if err != nil {
+		if contextError != nil {
 			return err
 		}
 
-		targetShopUrl, err := url.Parse(strings.TrimSuffix(args[len(args)-1], "/"))
-		if err != nil {
-			return err
+		esbuildInstances[entry.TechnicalName] = adminWatchExtension{
+			name:        name,
+			assetName:   entry.TechnicalName,
+			context:     esbuildContext,
+			watchServer: watchServer,
+			staticDir:   path.Join(entry.BasePath, "Resources", "app", "static"),
 		}
+	}
 
-		browserPort := browserUrl.Port()
+	browserUrl, targetShopUrl, err := resolveAdminWatchURLs(adminWatchListen, adminWatchURL, shopURL)
+	if err != nil {
+		return err
+	}
 
-		if len(browserPort) == 0 {
-			if browserUrl.Scheme == "https" {
-				browserPort = "443"
-			} else {
-				browserPort = "80"
-			}
+	fwd := forward.New(true)
+
+	redirect := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		logging.FromContext(cmd.Context()).Debugf("Got request %s %s", req.Method, req.URL.Path)
+
+		// Our custom live reload script
+		if req.URL.Path == "/__internal-admin-proxy/live-reload.js" {
+			w.Header().Set("content-type", "application/javascript")
+			_, _ = w.Write(liveReloadJS)
+
+			return
 		}
 
-		fwd := forward.New(true)
+		assetMatching := extensionAssetRegExp.FindAllString(req.URL.Path, -1)
 
-		redirect := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-			logging.FromContext(cmd.Context()).Debugf("Got request %s %s", req.Method, req.URL.Path)
+		if len(assetMatching) > 0 {
+			if ext, ok := esbuildInstances[assetMatching[0]]; ok {
+				assetPrefix := fmt.Sprintf(targetShopUrl.Path+"/bundles/%s/static/", ext.name)
 
-			// Our custom live reload script
-			if req.URL.Path == "/__internal-admin-proxy/live-reload.js" {
-				w.Header().Set("content-type", "application/javascript")
-				_, _ = w.Write(liveReloadJS)
+				http.ServeFile(w, req, path.Join(ext.staticDir, assetPrefix))
+				return
+			}
+		}
 
+		// Modify admin url index page to load anything from our watcher
+		if req.URL.Path == targetShopUrl.Path+"/admin" {
+			resp, err := http.Get(fmt.Sprintf("%s/admin", targetShopUrl.Scheme+schemeHostSeparator+targetShopUrl.Host))
+			if err != nil {
+				logging.FromContext(cmd.Context()).Errorf("proxy failed %v", err)
+				w.WriteHeader(http.StatusInternalServerError)
 				return
 			}
 
-			assetMatching := extensionAssetRegExp.FindAllString(req.URL.Path, -1)
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				logging.FromContext(cmd.Context()).Errorf("proxy reading failed %v", err)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
 
-			if len(assetMatching) > 0 {
-				if ext, ok := esbuildInstances[assetMatching[0]]; ok {
-					assetPrefix := fmt.Sprintf(targetShopUrl.Path+"/bundles/%s/static/", ext.name)
+			w.Header().Set("content-type", "text/html")
 
-					http.ServeFile(w, req, path.Join(ext.staticDir, assetPrefix))
-					return
-				}
+			if err := rewriteAdminIndex(cmd.Context(), w, body, browserUrl, targetShopUrl); err != nil {
+				logging.FromContext(cmd.Context()).Errorf("could not rewrite admin %v", err)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
 			}
+			logging.FromContext(cmd.Context()).Debugf("Served modified admin")
+			return
+		}
 
-			// Modify admin url index page to load anything from our watcher
-			if req.URL.Path == targetShopUrl.Path+"/admin" {
-				resp, err := http.Get(fmt.Sprintf("%s/admin", targetShopUrl.Scheme+schemeHostSeparator+targetShopUrl.Host))
-				if err != nil {
-					logging.FromContext(cmd.Context()).Errorf("proxy failed %v", err)
-					w.WriteHeader(http.StatusInternalServerError)
-					return
-				}
+		// Inject our custom extension JS
+		if req.URL.Path == targetShopUrl.Path+"/api/_info/config" {
+			logging.FromContext(cmd.Context()).Debugf("intercept plugins call")
 
-				body, err := io.ReadAll(resp.Body)
-				if err != nil {
-					logging.FromContext(cmd.Context()).Errorf("proxy reading failed %v", err)
-					w.WriteHeader(http.StatusInternalServerError)
-					return
-				}
+			proxyReq, _ := http.NewRequest("GET", targetShopUrl.Scheme+schemeHostSeparator+targetShopUrl.Host+req.URL.Path, nil)
 
-				bodyStr := string(body)
+			proxyReq.Header.Set("Authorization", req.Header.Get("Authorization"))
 
-				bodyStr = hostRegExp.ReplaceAllString(bodyStr, "host: '"+browserUrl.Host+"',")
-				bodyStr = portRegExp.ReplaceAllString(bodyStr, "port: "+browserPort+",")
-				bodyStr = schemeRegExp.ReplaceAllString(bodyStr, "scheme: '"+browserUrl.Scheme+"',")
-				bodyStr = schemeAndHttpHostRegExp.ReplaceAllString(bodyStr, "schemeAndHttpHost: '"+browserUrl.Scheme+schemeHostSeparator+browserUrl.Host+"',")
-				bodyStr = uriRegExp.ReplaceAllString(bodyStr, "uri: '"+browserUrl.Scheme+schemeHostSeparator+browserUrl.Host+targetShopUrl.Path+"/admin',")
-				bodyStr = assetPathRegExp.ReplaceAllString(bodyStr, "assetPath: '"+browserUrl.Scheme+schemeHostSeparator+browserUrl.Host+targetShopUrl.Path+"'")
+			resp, err := http.DefaultClient.Do(proxyReq)
+			if err != nil {
+				logging.FromContext(cmd.Context()).Errorf("proxy failed %v", err)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
 
-				parsed, err := html.Parse(strings.NewReader(bodyStr))
-				if err != nil {
-					logging.FromContext(cmd.Context()).Errorf("could not parse html %v", err)
-					w.WriteHeader(http.StatusInternalServerError)
-					return
-				}
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				logging.FromContext(cmd.Context()).Errorf("proxy reading failed %v", err)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
 
-				var f func(*html.Node)
-				f = func(n *html.Node) {
-					if n.Type == html.ElementNode && (n.Data == "script" || n.Data == "link" || n.Data == "meta") {
-						for i, attr := range n.Attr {
-							if attr.Key == "src" || attr.Key == "href" || attr.Key == "content" {
-								if !strings.HasPrefix(attr.Val, "http") {
-									continue
-								}
-
-								parsedUrl, err := url.Parse(attr.Val)
-								if err != nil {
-									logging.FromContext(cmd.Context()).Infof("cannot parse url: %s, err: %s", attr.Val, err.Error())
-									continue
-								}
-
-								if parsedUrl.Host == targetShopUrl.Host {
-									parsedUrl.Host = browserUrl.Host
-									parsedUrl.Scheme = browserUrl.Scheme
-								}
-
-								n.Attr[i].Val = parsedUrl.String()
-
-								break
-							}
-						}
-					}
+			newJson, err := rewriteAdminBundles(body, esbuildInstances, browserUrl, targetShopUrl)
+			if err != nil {
+				logging.FromContext(cmd.Context()).Errorf("cannot inject bundles: %v", err)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
 
-					for c := n.FirstChild; c != nil; c = c.NextSibling {
-						f(c)
-					}
-				}
+			w.Header().Set("content-type", "application/json")
+			if _, err := w.Write(newJson); err != nil {
+				logging.FromContext(cmd.Context()).Error(err)
+			}
+
+			return
+		}
 
-				f(parsed)
+		esbuildMatch := extensionEsbuildRegExp.FindStringSubmatch(req.URL.Path)
 
-				w.Header().Set("content-type", "text/html")
+		if len(esbuildMatch) > 0 {
+			if ext, ok := esbuildInstances[esbuildMatch[1]]; ok {
+				req.URL = &url.URL{Scheme: "http", Host: fmt.Sprintf("%s:%d", ext.watchServer.Hosts[0], ext.watchServer.Port), Path: "/" + esbuildMatch[2]}
+				req.Host = req.URL.Host
+				req.RequestURI = req.URL.Path
 
-				if err := htmlprinter.Render(w, parsed); err != nil {
-					logging.FromContext(cmd.Context()).Errorf("could not render html %v", err)
-					w.WriteHeader(http.StatusInternalServerError)
-					return
-				}
-				logging.FromContext(cmd.Context()).Debugf("Served modified admin")
+				fwd.ServeHTTP(w, req)
 				return
 			}
+		}
 
-			// Inject our custom extension JS
-			if req.URL.Path == targetShopUrl.Path+"/api/_info/config" {
-				logging.FromContext(cmd.Context()).Debugf("intercept plugins call")
+		// let us forward this request to another server
+		req.URL = targetShopUrl
+		fwd.ServeHTTP(w, req)
+	})
 
-				proxyReq, _ := http.NewRequest("GET", targetShopUrl.Scheme+schemeHostSeparator+targetShopUrl.Host+req.URL.Path, nil)
+	wrapper, _ := gziphandler.GzipHandlerWithOpts(gziphandler.ContentTypes([]string{"application/vnd.api+json", "application/json ", "text/html", "text/javascript", "text/css", "image/png"}))
 
-				proxyReq.Header.Set("Authorization", req.Header.Get("Authorization"))
+	s := &http.Server{
+		Addr:              adminWatchListen,
+		Handler:           wrapper(redirect),
+		ReadHeaderTimeout: time.Second,
+	}
+	logging.FromContext(cmd.Context()).Infof("Admin Watcher started at %s%s/admin", browserUrl.String(), targetShopUrl.Path)
+	if err := s.ListenAndServe(); err != nil {
+		return err
+	}
 
-				resp, err := http.DefaultClient.Do(proxyReq)
-				if err != nil {
-					logging.FromContext(cmd.Context()).Errorf("proxy failed %v", err)
-					w.WriteHeader(http.StatusInternalServerError)
-					return
-				}
+	return nil
+}
 
-				body, err := io.ReadAll(resp.Body)
-				if err != nil {
-					logging.FromContext(cmd.Context()).Errorf("proxy reading failed %v", err)
-					w.WriteHeader(http.StatusInternalServerError)
-					return
-				}
+// resolveAdminWatchURLs returns the URL the browser opens and the URL of the shop behind the proxy.
+// Without external URL the browser uses localhost with the port of listen.
+func resolveAdminWatchURLs(listen, externalURL, shopURL string) (*url.URL, *url.URL, error) {
+	listenSplit := strings.Split(listen, ":")
 
-				var bundleInfo adminBundlesInfo
-				if err := json.Unmarshal(body, &bundleInfo); err != nil {
-					logging.FromContext(cmd.Context()).Errorf("could not decode bundle info %v", err)
-					w.WriteHeader(http.StatusInternalServerError)
-					return
-				}
+	if len(listenSplit) != 2 {
+		return nil, nil, fmt.Errorf("listen should contain a colon")
+	}
 
-				if bundleInfo.Bundles == nil {
-					logging.FromContext(cmd.Context()).Errorf("cannot inject bundles. got invalid response %s", body)
-					w.WriteHeader(http.StatusInternalServerError)
-					return
-				}
+	if len(externalURL) == 0 {
+		externalURL = "http://localhost:" + listenSplit[1]
+	}
 
-				for name, bundle := range bundleInfo.Bundles {
-					newCss := []string{}
+	browserUrl, err := url.Parse(externalURL)
+	if err != nil {
+		return nil, nil, err
+	}
 
-					for _, assetUrl := range bundle.Css {
-						parsedUrl, _ := url.Parse(assetUrl)
+	targetShopUrl, err := url.Parse(strings.TrimSuffix(shopURL, "/"))
+	if err != nil {
+		return nil, nil, err
+	}
 
-						if parsedUrl.Host == targetShopUrl.Host {
-							parsedUrl.Host = browserUrl.Host
-							parsedUrl.Scheme = browserUrl.Scheme
-						}
+	return browserUrl, targetShopUrl, nil
+}
 
-						newCss = append(newCss, parsedUrl.String())
-					}
+// rewriteAdminIndex points the API and asset URLs of the admin index page of the shop to the proxy
+func rewriteAdminIndex(ctx context.Context, w io.Writer, body []byte, browserUrl, targetShopUrl *url.URL) error {
+	browserPort := browserUrl.Port()
 
-					newJS := []string{}
+	if len(browserPort) == 0 {
+		if browserUrl.Scheme == "https" {
+			browserPort = "443"
+		} else {
+			browserPort = "80"
+		}
+	}
+
+	bodyStr := string(body)
+
+	bodyStr = hostRegExp.ReplaceAllString(bodyStr, "host: '"+browserUrl.Host+"',")
+	bodyStr = portRegExp.ReplaceAllString(bodyStr, "port: "+browserPort+",")
+	bodyStr = schemeRegExp.ReplaceAllString(bodyStr, "scheme: '"+browserUrl.Scheme+"',")
+	bodyStr = schemeAndHttpHostRegExp.ReplaceAllString(bodyStr, "schemeAndHttpHost: '"+browserUrl.Scheme+schemeHostSeparator+browserUrl.Host+"',")
+	bodyStr = uriRegExp.ReplaceAllString(bodyStr, "uri: '"+browserUrl.Scheme+schemeHostSeparator+browserUrl.Host+targetShopUrl.Path+"/admin',")
+	bodyStr = assetPathRegExp.ReplaceAllString(bodyStr, "assetPath: '"+browserUrl.Scheme+schemeHostSeparator+browserUrl.Host+targetShopUrl.Path+"'")
+
+	parsed, err := html.Parse(strings.NewReader(bodyStr))
+	if err != nil {
+		return fmt.Errorf("could not parse html: %w", err)
+	}
+
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		if n.Type == html.ElementNode && (n.Data == "script" || n.Data == "link" || n.Data == "meta") {
+			for i, attr := range n.Attr {
+				if attr.Key == "src" || attr.Key == "href" || attr.Key == "content" {
+					if !strings.HasPrefix(attr.Val, "http") {
+						continue
+					}
 
-					for _, assetUrl := range bundle.Js {
-						parsedUrl, _ := url.Parse(assetUrl)
-						if parsedUrl.Host == targetShopUrl.Host {
-							parsedUrl.Host = browserUrl.Host
-							parsedUrl.Scheme = browserUrl.Scheme
-						}
+					parsedUrl, err := url.Parse(attr.Val)
+					if err != nil {
+						logging.FromContext(ctx).Infof("cannot parse url: %s, err: %s", attr.Val, err.Error())
+						continue
+					}
 
-						newJS = append(newJS, parsedUrl.String())
+					if parsedUrl.Host == targetShopUrl.Host {
+						parsedUrl.Host = browserUrl.Host
+						parsedUrl.Scheme = browserUrl.Scheme
 					}
 
-					bundleInfo.Bundles[name] = adminBundlesInfoAsset{Css: newCss, Js: newJS}
-				}
+					n.Attr[i].Val = parsedUrl.String()
 
-				for _, ext := range esbuildInstances {
-					bundleInfo.Bundles[ext.name] = adminBundlesInfoAsset{
-						Css:        []string{fmt.Sprintf("%s/.shopware-cli/%s/extension.css", browserUrl.String(), ext.assetName)},
-						Js:         []string{fmt.Sprintf("%s/.shopware-cli/%s/extension.js", browserUrl.String(), ext.assetName)},
-						LiveReload: true,
-						Name:       ext.assetName,
-					}
+					break
 				}
+			}
+		}
 
-				bundleInfo.Bundles["ShopwareCLI"] = adminBundlesInfoAsset{Css: []string{}, Js: []string{browserUrl.String() + "/__internal-admin-proxy/live-reload.js"}}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
 
-				newJson, err := json.Marshal(bundleInfo)
-				if err != nil {
-					logging.FromContext(cmd.Context()).Errorf("could not encode bundle info %v", err)
-					w.WriteHeader(http.StatusInternalServerError)
-					return
-				}
+	f(parsed)
 
-				w.Header().Set("content-type", "application/json")
-				if _, err := w.Write(newJson); err != nil {
-					logging.FromContext(cmd.Context()).Error(err)
-				}
+	return htmlprinter.Render(w, parsed)
+}
 
-				return
-			}
+// rewriteAdminBundles points the bundles of the shop to the proxy and replaces the watched extensions with their esbuild output
+func rewriteAdminBundles(body []byte, esbuildInstances map[string]adminWatchExtension, browserUrl, targetShopUrl *url.URL) ([]byte, error) {
+	var bundleInfo adminBundlesInfo
+	if err := json.Unmarshal(body, &bundleInfo); err != nil {
+		return nil, fmt.Errorf("could not decode bundle info: %w", err)
+	}
 
-			esbuildMatch := extensionEsbuildRegExp.FindStringSubmatch(req.URL.Path)
+	if bundleInfo.Bundles == nil {
+		return nil, fmt.Errorf("got invalid response %s", body)
+	}
 
-			if len(esbuildMatch) > 0 {
-				if ext, ok := esbuildInstances[esbuildMatch[1]]; ok {
-					req.URL = &url.URL{Scheme: "http", Host: fmt.Sprintf("%s:%d", ext.watchServer.Hosts[0], ext.watchServer.Port), Path: "/" + esbuildMatch[2]}
-					req.Host = req.URL.Host
-					req.RequestURI = req.URL.Path
+	for name, bundle := range bundleInfo.Bundles {
+		newCss := []string{}
 
-					fwd.ServeHTTP(w, req)
-					return
-				}
+		for _, assetUrl := range bundle.Css {
+			parsedUrl, _ := url.Parse(assetUrl)
+
+			if parsedUrl.Host == targetShopUrl.Host {
+				parsedUrl.Host = browserUrl.Host
+				parsedUrl.Scheme = browserUrl.Scheme
 			}
 
-			// let us forward this request to another server
-			req.URL = targetShopUrl
-			fwd.ServeHTTP(w, req)
-		})
+			newCss = append(newCss, parsedUrl.String())
+		}
+
+		newJS := []string{}
 
-		wrapper, _ := gziphandler.GzipHandlerWithOpts(gziphandler.ContentTypes([]string{"application/vnd.api+json", "application/json ", "text/html", "text/javascript", "text/css", "image/png"}))
+		for _, assetUrl := range bundle.Js {
+			parsedUrl, _ := url.Parse(assetUrl)
+			if parsedUrl.Host == targetShopUrl.Host {
+				parsedUrl.Host = browserUrl.Host
+				parsedUrl.Scheme = browserUrl.Scheme
+			}
 
-		s := &http.Server{
-			Addr:              adminWatchListen,
-			Handler:           wrapper(redirect),
-			ReadHeaderTimeout: time.Second,
+			newJS = append(newJS, parsedUrl.String())
 		}
-		logging.FromContext(cmd.Context()).Infof("Admin Watcher started at %s%s/admin", browserUrl.String(), targetShopUrl.Path)
-		if err := s.ListenAndServe(); err != nil {
-			return err
+
+		bundleInfo.Bundles[name] = adminBundlesInfoAsset{Css: newCss, Js: newJS}
+	}
+
+	for _, ext := range esbuildInstances {
+		bundleInfo.Bundles[ext.name] = adminBundlesInfoAsset{
+			Css:        []string{fmt.Sprintf("%s/.shopware-cli/%s/extension.css", browserUrl.String(), ext.assetName)},
+			Js:         []string{fmt.Sprintf("%s/.shopware-cli/%s/extension.js", browserUrl.String(), ext.assetName)},
+			LiveReload: true,
+			Name:       ext.assetName,
 		}
+	}
 
-		return nil
-	},
+	bundleInfo.Bundles["ShopwareCLI"] = adminBundlesInfoAsset{Css: []string{}, Js: []string{browserUrl.String() + "/__internal-admin-proxy/live-reload.js"}}
+
+	return json.Marshal(bundleInfo)
 }
 
 func init() {
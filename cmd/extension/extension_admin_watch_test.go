@@ -0,0 +1,124 @@
+package extension
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAdminIndex = `<html>
+<head>
+<meta name="shop" content="http://shop.test/shop/admin">
+<script src="http://shop.test/shop/bundles/administration/administration.js"></script>
+<script src="https://cdn.test/polyfill.js"></script>
+</head>
+<body>
+<script>
+Shopware.Application.start({
+    apiContext: {
+        host: 'shop.test',
+        port: 80,
+        scheme: 'http',
+        schemeAndHttpHost: 'http://shop.test',
+        uri: 'http://shop.test/shop/admin',
+        assetPath: 'http://shop.test/shop'
+    }
+});
+</script>
+</body>
+</html>`
+
+func TestResolveAdminWatchURLs(t *testing.T) {
+	browserURL, shopURL, err := resolveAdminWatchURLs(":8080", "", "http://shop.test/shop/")
+	require.NoError(t, err)
+
+	assert.Equal(t, "http://localhost:8080", browserURL.String())
+	assert.Equal(t, "http://shop.test/shop", shopURL.String())
+
+	browserURL, _, err = resolveAdminWatchURLs(":8080", "https://proxy.test", "http://shop.test")
+	require.NoError(t, err)
+	assert.Equal(t, "https://proxy.test", browserURL.String())
+
+	_, _, err = resolveAdminWatchURLs("8080", "", "http://shop.test")
+	assert.EqualError(t, err, "listen should contain a colon")
+}
+
+func TestRewriteAdminIndex(t *testing.T) {
+	browserURL, shopURL, err := resolveAdminWatchURLs(":8080", "", "http://shop.test/shop")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, rewriteAdminIndex(t.Context(), &out, []byte(testAdminIndex), browserURL, shopURL))
+
+	index := out.String()
+
+	assert.Contains(t, index, "host: 'localhost:8080',")
+	assert.Contains(t, index, "port: 8080,")
+	assert.Contains(t, index, "scheme: 'http',")
+	assert.Contains(t, index, "schemeAndHttpHost: 'http://localhost:8080',")
+	assert.Contains(t, index, "uri: 'http://localhost:8080/shop/admin',")
+	assert.Contains(t, index, "assetPath: 'http://localhost:8080/shop'")
+	assert.Contains(t, index, `src="http://localhost:8080/shop/bundles/administration/administration.js"`)
+	assert.Contains(t, index, `content="http://localhost:8080/shop/admin"`)
+	assert.Contains(t, index, `src="https://cdn.test/polyfill.js"`, "assets of other hosts are kept")
+	assert.NotContains(t, index, "shop.test")
+}
+
+func TestRewriteAdminIndexUsesDefaultPortOfScheme(t *testing.T) {
+	browserURL, shopURL, err := resolveAdminWatchURLs(":8080", "https://proxy.test", "http://shop.test/shop")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, rewriteAdminIndex(t.Context(), &out, []byte(testAdminIndex), browserURL, shopURL))
+
+	assert.Contains(t, out.String(), "port: 443,")
+	assert.Contains(t, out.String(), "scheme: 'https',")
+}
+
+func TestRewriteAdminBundles(t *testing.T) {
+	browserURL, shopURL, err := resolveAdminWatchURLs(":8080", "", "http://shop.test")
+	require.NoError(t, err)
+
+	body := `{"version": "6.6.0.0", "bundles": {
+		"Storefront": {"css": ["http://shop.test/bundles/storefront/administration/storefront.css"], "js": ["https://cdn.test/storefront.js"]},
+		"FroshTools": {"css": [], "js": ["http://shop.test/bundles/froshtools/administration/frosh-tools.js"]}
+	}}`
+
+	instances := map[string]adminWatchExtension{
+		"frosh-tools": {name: "FroshTools", assetName: "frosh-tools"},
+	}
+
+	rewritten, err := rewriteAdminBundles([]byte(body), instances, browserURL, shopURL)
+	require.NoError(t, err)
+
+	var info adminBundlesInfo
+	require.NoError(t, json.Unmarshal(rewritten, &info))
+
+	assert.Equal(t, "6.6.0.0", info.Version)
+	assert.Equal(t, []string{"http://localhost:8080/bundles/storefront/administration/storefront.css"}, info.Bundles["Storefront"].Css)
+	assert.Equal(t, []string{"https://cdn.test/storefront.js"}, info.Bundles["Storefront"].Js)
+
+	// The watched extension is served by esbuild with live reload
+	assert.Equal(t, adminBundlesInfoAsset{
+		Css:        []string{"http://localhost:8080/.shopware-cli/frosh-tools/extension.css"},
+		Js:         []string{"http://localhost:8080/.shopware-cli/frosh-tools/extension.js"},
+		LiveReload: true,
+		Name:       "frosh-tools",
+	}, info.Bundles["FroshTools"])
+
+	assert.Equal(t, []string{"http://localhost:8080/__internal-admin-proxy/live-reload.js"}, info.Bundles["ShopwareCLI"].Js)
+}
+
+func TestRewriteAdminBundlesRejectsInvalidResponses(t *testing.T) {
+	browserURL, shopURL, err := resolveAdminWatchURLs(":8080", "", "http://shop.test")
+	require.NoError(t, err)
+
+	_, err = rewriteAdminBundles([]byte(`<html>`), nil, browserURL, shopURL)
+	assert.Error(t, err)
+
+	_, err = rewriteAdminBundles([]byte(`{"errors": []}`), nil, browserURL, shopURL)
+	assert.ErrorContains(t, err, "got invalid response")
+}
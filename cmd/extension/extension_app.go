@@ -0,0 +1,185 @@
+package extension
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var extensionAppGroupCmd = &cobra.Command{
+	Use:   "app",
+	Short: "Develop the backend of apps",
+}
+
+var extensionAppSecretCmd = &cobra.Command{
+	Use:   "secret [path]",
+	Short: "Generates an app secret for the registration handshake",
+	Long: `Prints a random secret for the handshake between Shopware and the app backend.
+With --write the secret is written into the setup of the manifest.xml, which is only allowed for local development.
+The store generates the secret of published apps.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		secret, err := extension.GenerateAppSecret()
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(secret)
+
+		if write, _ := cmd.Flags().GetBool("write"); !write {
+			return nil
+		}
+
+		ext, err := getAppExtension(args)
+		if err != nil {
+			return err
+		}
+
+		file, err := extension.SetAppSecret(ext, secret)
+		if err != nil {
+			return fmt.Errorf("cannot write the secret: %w", err)
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Wrote the secret into %s, remove it before uploading the app to the store", file)
+
+		return nil
+	},
+}
+
+var extensionAppServerCmd = &cobra.Command{
+	Use:   "server [folder] [path]",
+	Short: "Scaffolds an app backend answering the registration handshake",
+	Long: `Creates a minimal backend in the given folder, which answers the registration and confirmation requests of
+shops installing the app. The name and secret are taken from the manifest.xml of the app at path, a missing secret
+is generated. They are written into the .env file of the backend.`,
+	Example: "  shopware-cli extension app server ./backend ./MyApp --language php",
+	Args:    cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ext, err := getAppExtension(args[1:])
+		if err != nil {
+			return err
+		}
+
+		appName, err := ext.GetName()
+		if err != nil {
+			return err
+		}
+
+		secret, _ := cmd.Flags().GetString("secret")
+		if setup := ext.GetSetup(); secret == "" && setup != nil {
+			secret = setup.Secret
+		}
+
+		if secret == "" {
+			if secret, err = extension.GenerateAppSecret(); err != nil {
+				return err
+			}
+
+			logging.FromContext(cmd.Context()).Infof("Generated a new app secret, write it into the manifest.xml with <secret>%s</secret> for local development", secret)
+		}
+
+		language, _ := cmd.Flags().GetString("language")
+
+		files, err := extension.ScaffoldAppServer(args[0], extension.AppServerOptions{Language: language, AppName: appName, Secret: secret})
+		if err != nil {
+			return err
+		}
+
+		for _, file := range files {
+			fmt.Println(filepath.Join(args[0], file))
+		}
+
+		return nil
+	},
+}
+
+var extensionAppVerifyHandshakeCmd = &cobra.Command{
+	Use:   "verify-handshake [path]",
+	Short: "Simulates the registration of a shop against the app backend",
+	Long: `Sends the registration request like a Shopware installing the app, checks the proof the backend answers with
+and confirms the registration with generated API credentials. The registration url, name and secret are taken from the
+manifest.xml of the app at path, the flags override them.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := extension.HandshakeOptions{}
+
+		opts.RegistrationURL, _ = cmd.Flags().GetString("registration-url")
+		opts.AppName, _ = cmd.Flags().GetString("app-name")
+		opts.AppSecret, _ = cmd.Flags().GetString("secret")
+		opts.ShopURL, _ = cmd.Flags().GetString("shop-url")
+		opts.ShopwareVersion, _ = cmd.Flags().GetString("shopware-version")
+
+		if opts.RegistrationURL == "" || opts.AppName == "" || opts.AppSecret == "" {
+			ext, err := getAppExtension(args)
+			if err != nil {
+				return err
+			}
+
+			if opts.AppName == "" {
+				opts.AppName, _ = ext.GetName()
+			}
+
+			if setup := ext.GetSetup(); setup != nil {
+				if opts.RegistrationURL == "" {
+					opts.RegistrationURL = strings.TrimSpace(setup.RegistrationUrl)
+				}
+
+				if opts.AppSecret == "" {
+					opts.AppSecret = strings.TrimSpace(setup.Secret)
+				}
+			}
+		}
+
+		result, err := extension.VerifyHandshake(cmd.Context(), opts)
+		if err != nil {
+			return err
+		}
+
+		logging.FromContext(cmd.Context()).Infof("The app backend registered the shop %s and accepted the confirmation at %s", result.ShopID, result.ConfirmationURL)
+
+		return nil
+	},
+}
+
+// getAppExtension opens the app at the first argument or the current folder
+func getAppExtension(args []string) (*extension.App, error) {
+	extPath := "."
+	if len(args) > 0 {
+		extPath = args[0]
+	}
+
+	ext, err := extension.GetExtensionByFolder(extPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open extension: %w", err)
+	}
+
+	app, ok := ext.(*extension.App)
+	if !ok {
+		return nil, fmt.Errorf("the extension at %s is a %s, the command requires an app", extPath, ext.GetType())
+	}
+
+	return app, nil
+}
+
+func init() {
+	extensionRootCmd.AddCommand(extensionAppGroupCmd)
+	extensionAppGroupCmd.AddCommand(extensionAppSecretCmd)
+	extensionAppGroupCmd.AddCommand(extensionAppServerCmd)
+	extensionAppGroupCmd.AddCommand(extensionAppVerifyHandshakeCmd)
+
+	extensionAppSecretCmd.Flags().Bool("write", false, "Write the secret into the manifest.xml for local development")
+
+	extensionAppServerCmd.Flags().String("language", extension.AppServerGo, fmt.Sprintf("Language of the backend (%s)", strings.Join(extension.AppServerLanguages, ", ")))
+	extensionAppServerCmd.Flags().String("secret", "", "App secret, defaults to the secret of the manifest.xml or a generated one")
+
+	extensionAppVerifyHandshakeCmd.Flags().String("registration-url", "", "Registration url of the app backend, defaults to the registrationUrl of the manifest.xml")
+	extensionAppVerifyHandshakeCmd.Flags().String("app-name", "", "Name of the app, defaults to the name of the manifest.xml")
+	extensionAppVerifyHandshakeCmd.Flags().String("secret", "", "App secret, defaults to the secret of the manifest.xml")
+	extensionAppVerifyHandshakeCmd.Flags().String("shop-url", "http://localhost:8000", "URL of the simulated shop")
+	extensionAppVerifyHandshakeCmd.Flags().String("shopware-version", "6.6.0.0", "Shopware version sent by the simulated shop")
+}
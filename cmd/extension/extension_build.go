@@ -13,11 +13,29 @@ import (
 var extensionAssetBundleCmd = &cobra.Command{
 	Use:   "build [path]",
 	Short: "Builds assets for extensions",
-	Args:  cobra.MinimumNArgs(1),
+	Args:  workspaceArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		assetCfg := extension.AssetBuildConfig{
 			ShopwareRoot: os.Getenv("SHOPWARE_PROJECT_ROOT"),
 		}
+
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			paths, err := workspaceExtensionPaths(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			if len(paths) == 0 {
+				return nil
+			}
+
+			args = paths
+		}
+
+		// The extensions of a workspace share the cache, as they often use the same dependencies
+		if useCache, _ := cmd.Flags().GetBool("asset-cache"); useCache || workspace != nil {
+			assetCfg.Cache = newAssetCache()
+		}
 		validatedExtensions := make([]extension.Extension, 0)
 
 		for _, arg := range args {
@@ -49,14 +67,19 @@ var extensionAssetBundleCmd = &cobra.Command{
 			assetCfg.ShopwareVersion = constraint
 		}
 
+		// The source maps stay for local builds, they are only removed in the zip
+		assetCfg.SourceMaps = bundleReportRequested(cmd, validatedExtensions)
+
 		if err := extension.BuildAssetsForExtensions(cmd.Context(), extension.ConvertExtensionsToSources(cmd.Context(), validatedExtensions), assetCfg); err != nil {
 			return fmt.Errorf("cannot build assets: %w", err)
 		}
 
-		return nil
+		return reportBundleSizes(cmd, validatedExtensions, false)
 	},
 }
 
 func init() {
 	extensionRootCmd.AddCommand(extensionAssetBundleCmd)
+	extensionAssetBundleCmd.Flags().Bool("asset-cache", false, "Reuse node_modules and compiled assets when the sources did not change, SHOPWARE_CLI_ASSET_CACHE_URL adds a remote cache")
+	addBundleReportFlag(extensionAssetBundleCmd)
 }
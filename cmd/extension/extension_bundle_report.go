@@ -0,0 +1,85 @@
+package extension
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+func addBundleReportFlag(cmd *cobra.Command) {
+	cmd.Flags().String("bundle-report", "", "Write the sizes and largest modules of the compiled JavaScript files as JSON into this file")
+}
+
+// bundleReportRequested returns true when the bundle report is written or a budget is configured. The Administration is
+// built with source maps then, the report uses them for the largest modules.
+func bundleReportRequested(cmd *cobra.Command, exts []extension.Extension) bool {
+	if output, _ := cmd.Flags().GetString("bundle-report"); output != "" {
+		return true
+	}
+
+	for _, ext := range exts {
+		if budget := ext.GetExtensionConfig().Build.Zip.Assets.Budget; budget.Administration != "" || budget.Storefront != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reportBundleSizes logs the size of the compiled JavaScript of the extensions, writes the report and checks the budgets.
+// With removeSourceMaps the source maps generated for the report are deleted afterwards.
+func reportBundleSizes(cmd *cobra.Command, exts []extension.Extension, removeSourceMaps bool) error {
+	report := make(map[string][]extension.BundleFile, len(exts))
+
+	var budgetErrors []error
+
+	for _, ext := range exts {
+		name, err := ext.GetName()
+		if err != nil {
+			return err
+		}
+
+		files, err := extension.AnalyzeBundles(ext.GetRootDir())
+		if err != nil {
+			return err
+		}
+
+		for _, file := range files {
+			logging.FromContext(cmd.Context()).Infof("%s: %s %s (gzip %s)", name, file.Path, humanize.Bytes(uint64(file.Size)), humanize.Bytes(uint64(file.GzipSize)))
+		}
+
+		report[name] = files
+
+		if removeSourceMaps {
+			if err := extension.RemoveBundleSourceMaps(ext.GetRootDir()); err != nil {
+				return err
+			}
+		}
+
+		if err := extension.CheckBundleBudget(files, ext.GetExtensionConfig().Build.Zip.Assets.Budget); err != nil {
+			budgetErrors = append(budgetErrors, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	if output, _ := cmd.Flags().GetString("bundle-report"); output != "" {
+		content, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(output, content, 0o644); err != nil {
+			return err
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Wrote the bundle report to %s", output)
+	}
+
+	return errors.Join(budgetErrors...)
+}
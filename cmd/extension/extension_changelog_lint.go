@@ -0,0 +1,57 @@
+package extension
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var extensionChangelogGroupCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "Manage the CHANGELOG*.md files",
+}
+
+var extensionChangelogLintCmd = &cobra.Command{
+	Use:   "lint [path]",
+	Short: "Check the format of the CHANGELOG*.md files",
+	Long: `Checks the CHANGELOG*.md files of the extension. A changelog either has one heading per version like # 1.0.0,
+or uses the keep a changelog format with sections like ## [1.0.0] - 2024-05-01, an optional ## [Unreleased] section
+and types of changes like ### Added. The entries of the current version are uploaded to the store.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := "."
+		if len(args) > 0 {
+			dir = args[0]
+		}
+
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			return fmt.Errorf("cannot find path: %w", err)
+		}
+
+		problems, err := extension.LintChangelogs(dir)
+		if err != nil {
+			return err
+		}
+
+		if len(problems) == 0 {
+			logging.FromContext(cmd.Context()).Infof("The changelogs are valid")
+			return nil
+		}
+
+		for _, problem := range problems {
+			fmt.Println(problem.String())
+		}
+
+		return fmt.Errorf("the changelogs contain %d problems", len(problems))
+	},
+}
+
+func init() {
+	extensionRootCmd.AddCommand(extensionChangelogGroupCmd)
+	extensionChangelogGroupCmd.AddCommand(extensionChangelogLintCmd)
+}
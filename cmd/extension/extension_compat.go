@@ -0,0 +1,87 @@
+package extension
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var extensionCompatGroupCmd = &cobra.Command{
+	Use:   "compat",
+	Short: "Manage the Shopware versions the extension is compatible with",
+}
+
+var extensionCompatDetectCmd = &cobra.Command{
+	Use:   "detect [path]",
+	Short: "Suggests the Shopware version constraint matching the APIs used in the code",
+	Long: `Scans the PHP, XML and Administration code for Shopware APIs which were added or removed in a release
+and suggests a shopware/core constraint containing the released Shopware versions allowed by the current constraint that have them.
+Only some of the APIs added in a release are known, so the lower bound of the suggestion is not proven and should be tested.
+Versions allowed by the current constraint which miss a used API are reported, --write updates the constraint.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		extPath := "."
+		if len(args) > 0 {
+			extPath = args[0]
+		}
+
+		extPath, err := filepath.Abs(extPath)
+		if err != nil {
+			return fmt.Errorf("cannot find path: %w", err)
+		}
+
+		ext, err := extension.GetExtensionByFolder(extPath)
+		if err != nil {
+			return fmt.Errorf("cannot open extension: %w", err)
+		}
+
+		report, err := extension.DetectCompatibility(cmd.Context(), ext, nil)
+		if err != nil {
+			return err
+		}
+
+		for _, requirement := range report.Requirements {
+			switch {
+			case requirement.Added != "" && requirement.Removed != "":
+				fmt.Printf("%s: the %s %s exists from Shopware %s until %s\n", requirement.Location, requirement.Kind, requirement.Name, requirement.Added, requirement.Removed)
+			case requirement.Added != "":
+				fmt.Printf("%s: the %s %s was added in Shopware %s\n", requirement.Location, requirement.Kind, requirement.Name, requirement.Added)
+			default:
+				fmt.Printf("%s: the %s %s was removed in Shopware %s\n", requirement.Location, requirement.Kind, requirement.Name, requirement.Removed)
+			}
+		}
+
+		logger := logging.FromContext(cmd.Context())
+
+		if len(report.Unsupported) > 0 {
+			logger.Warnf("The constraint %s allows %d Shopware versions the code does not work with: %s to %s", report.Current, len(report.Unsupported), report.Unsupported[0], report.Unsupported[len(report.Unsupported)-1])
+		}
+
+		logger.Infof("Current constraint: %s", report.Current)
+		logger.Infof("Suggested constraint: %s", report.Suggested)
+		logger.Warnf("Only some APIs added in Shopware releases are known, the lower bound is not proven: test the extension with Shopware %s", report.Oldest)
+
+		if write, _ := cmd.Flags().GetBool("write"); !write {
+			return nil
+		}
+
+		file, err := extension.SetShopwareVersionConstraint(ext, report.Suggested)
+		if err != nil {
+			return fmt.Errorf("cannot write the constraint: %w", err)
+		}
+
+		logger.Infof("Wrote the constraint %s into %s", report.Suggested, file)
+
+		return nil
+	},
+}
+
+func init() {
+	extensionRootCmd.AddCommand(extensionCompatGroupCmd)
+	extensionCompatGroupCmd.AddCommand(extensionCompatDetectCmd)
+	extensionCompatDetectCmd.Flags().Bool("write", false, "Write the suggested constraint into the composer.json, the manifest.xml or the extension config")
+}
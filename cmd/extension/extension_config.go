@@ -0,0 +1,12 @@
+package extension
+
+import "github.com/spf13/cobra"
+
+var extensionConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the .shopware-extension.yml",
+}
+
+func init() {
+	extensionRootCmd.AddCommand(extensionConfigCmd)
+}
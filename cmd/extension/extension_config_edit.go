@@ -0,0 +1,273 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+	"github.com/shopware/shopware-cli/internal/config"
+	"github.com/shopware/shopware-cli/internal/interaction"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var extensionConfigEditCmd = &cobra.Command{
+	Use:   "edit [path]",
+	Short: "Edit the .shopware-extension.yml",
+	Long: `Opens the .shopware-extension.yml in $EDITOR and validates it afterwards.
+
+With --interactive a form walks through the store metadata instead: availabilities, locales, categories, type and
+the meta texts. When logged in to the Shopware Account the choices are loaded from the store, otherwise the values of
+the config schema are offered. Only the edited settings are changed, comments and other settings are kept.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := "."
+		if len(args) > 0 {
+			dir = args[0]
+		}
+
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			return fmt.Errorf("cannot find path: %w", err)
+		}
+
+		if interactive, _ := cmd.Flags().GetBool("interactive"); interactive {
+			return editStoreMetadata(cmd.Context(), dir)
+		}
+
+		return editExtensionConfigFile(cmd.Context(), dir)
+	},
+}
+
+// storeChoices are the values the store accepts for the settings with a fixed list
+type storeChoices struct {
+	availabilities []string
+	locales        []string
+	categories     []string
+}
+
+// loadStoreChoices asks the store for the current taxonomy and falls back to the values of the config schema
+func loadStoreChoices(ctx context.Context) storeChoices {
+	choices := storeChoices{
+		availabilities: extension.ConfigStoreChoices("availabilities"),
+		locales:        extension.ConfigStoreChoices("localizations"),
+		categories:     extension.ConfigStoreChoices("categories"),
+	}
+
+	client, err := account_api.NewApi(ctx, config.Config{})
+	if err != nil {
+		logging.FromContext(ctx).Infof("Not logged in to the Shopware Account, offering the values of the config schema")
+		return choices
+	}
+
+	producer, err := client.Producer(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Warnf("Cannot load the store categories: %v", err)
+		return choices
+	}
+
+	info, err := producer.GetExtensionGeneralInfo(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Warnf("Cannot load the store categories: %v", err)
+		return choices
+	}
+
+	if len(info.StoreAvailabilities) > 0 {
+		choices.availabilities = nil
+
+		for _, availability := range info.StoreAvailabilities {
+			choices.availabilities = append(choices.availabilities, availability.Name)
+		}
+	}
+
+	if len(info.Localizations) > 0 {
+		choices.locales = nil
+
+		for _, locale := range info.Localizations {
+			choices.locales = append(choices.locales, locale.Name)
+		}
+	}
+
+	if len(info.Categories) > 0 {
+		choices.categories = nil
+
+		for _, category := range info.Categories {
+			if category.Active && category.Applicable {
+				choices.categories = append(choices.categories, category.Name)
+			}
+		}
+	}
+
+	return choices
+}
+
+func editStoreMetadata(ctx context.Context, dir string) error {
+	if err := interaction.RequireInput(ctx, "the store metadata", "edit the .shopware-extension.yml without --interactive"); err != nil {
+		return err
+	}
+
+	metadata, err := extension.ReadStoreMetadata(dir)
+	if err != nil {
+		return err
+	}
+
+	choices := loadStoreChoices(ctx)
+
+	if metadata.Type == "" {
+		metadata.Type = "extension"
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Stores the extension is available in").
+				Options(storeOptions(choices.availabilities, metadata.Availabilities)...).
+				Value(&metadata.Availabilities).
+				Validate(func(values []string) error {
+					if len(values) == 0 {
+						return fmt.Errorf("select at least one store")
+					}
+
+					return nil
+				}),
+			huh.NewSelect[string]().
+				Title("Type").
+				Options(huh.NewOptions(extension.ConfigStoreChoices("type")...)...).
+				Value(&metadata.Type),
+		),
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Languages the extension is translated to").
+				Options(storeOptions(choices.locales, metadata.Localizations)...).
+				Value(&metadata.Localizations),
+			huh.NewSelect[string]().
+				Title("Default locale").
+				OptionsFunc(func() []huh.Option[string] {
+					return huh.NewOptions(defaultLocaleChoices(metadata.Localizations)...)
+				}, &metadata.Localizations).
+				Value(&metadata.DefaultLocale),
+		),
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Categories").
+				Options(storeOptions(choices.categories, metadata.Categories)...).
+				Value(&metadata.Categories).
+				Filterable(true),
+		),
+		huh.NewGroup(
+			storeTextInput("Meta title (German)", extension.StoreMetaTitleMaxLength, &metadata.MetaTitleGerman),
+			storeTextInput("Meta title (English)", extension.StoreMetaTitleMaxLength, &metadata.MetaTitleEnglish),
+			storeTextInput("Meta description (German)", extension.StoreMetaDescriptionMaxLength, &metadata.MetaDescriptionGerman),
+			storeTextInput("Meta description (English)", extension.StoreMetaDescriptionMaxLength, &metadata.MetaDescriptionEnglish),
+		),
+	)
+
+	if err := form.RunWithContext(ctx); err != nil {
+		return err
+	}
+
+	if problems := metadata.Validate(); len(problems) > 0 {
+		return fmt.Errorf("the store metadata is invalid: %s", strings.Join(problems, ", "))
+	}
+
+	file, err := extension.WriteStoreMetadata(dir, metadata)
+	if err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Infof("Wrote the store metadata into %s", file)
+
+	return nil
+}
+
+// storeOptions returns the choices as options, values of the config the store does not offer are kept
+func storeOptions(choices, selected []string) []huh.Option[string] {
+	for _, value := range selected {
+		if !slices.Contains(choices, value) {
+			choices = append(choices, value)
+		}
+	}
+
+	options := make([]huh.Option[string], 0, len(choices))
+
+	for _, choice := range choices {
+		options = append(options, huh.NewOption(choice, choice).Selected(slices.Contains(selected, choice)))
+	}
+
+	return options
+}
+
+// defaultLocaleChoices offers the selected localizations, the store knows only de_DE and en_GB without them
+func defaultLocaleChoices(localizations []string) []string {
+	if len(localizations) == 0 {
+		return extension.ConfigStoreChoices("default_locale")
+	}
+
+	return localizations
+}
+
+func storeTextInput(title string, maxLength int, value *string) *huh.Input {
+	return huh.NewInput().
+		Title(title).
+		Description(fmt.Sprintf("At most %d characters", maxLength)).
+		CharLimit(maxLength).
+		Value(value).
+		Validate(func(text string) error {
+			return extension.ValidateStoreText(text, maxLength)
+		})
+}
+
+// editExtensionConfigFile opens the config in $EDITOR and reports the problems of the saved file
+func editExtensionConfigFile(ctx context.Context, dir string) error {
+	if err := interaction.RequireInput(ctx, "the edited config", "use extension config validate to check a config"); err != nil {
+		return err
+	}
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+
+	file := extension.ExtensionConfigFile(dir)
+
+	editorCmd := exec.CommandContext(ctx, editor, file)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+
+	if err := editorCmd.Run(); err != nil {
+		return fmt.Errorf("cannot run %s: %w", editor, err)
+	}
+
+	if _, err := os.Stat(file); err != nil {
+		return nil
+	}
+
+	problems, err := extension.ValidateExtensionConfigFile(file)
+	if err != nil {
+		return err
+	}
+
+	for _, problem := range problems {
+		fmt.Printf("%s:%s\n", filepath.Base(file), problem.String())
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s contains %d problems", filepath.Base(file), len(problems))
+	}
+
+	return nil
+}
+
+func init() {
+	extensionConfigCmd.AddCommand(extensionConfigEditCmd)
+	extensionConfigEditCmd.Flags().Bool("interactive", false, "Walk through the store metadata in a form instead of opening $EDITOR")
+}
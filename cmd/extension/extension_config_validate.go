@@ -0,0 +1,76 @@
+package extension
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var extensionConfigValidateCmd = &cobra.Command{
+	Use:   "validate [path]",
+	Short: "Validate the .shopware-extension.yml against its JSON schema",
+	Long: `Validate the .shopware-extension.yml of the extension against its JSON schema. Unknown keys, wrong types
+and options which exclude each other are reported with their line.
+
+With --schema-output the schema is written to a file, so IDEs can autocomplete the config by adding
+# yaml-language-server: $schema=<file> as first line of the .shopware-extension.yml.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := "."
+		if len(args) > 0 {
+			dir = args[0]
+		}
+
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			return fmt.Errorf("cannot find path: %w", err)
+		}
+
+		if schemaOutput, _ := cmd.Flags().GetString("schema-output"); schemaOutput != "" {
+			if err := os.WriteFile(schemaOutput, extension.ExtensionConfigSchema(), 0o644); err != nil {
+				return fmt.Errorf("cannot write schema: %w", err)
+			}
+
+			logging.FromContext(cmd.Context()).Infof("Wrote the schema to %s", schemaOutput)
+		}
+
+		configFile := ""
+		for _, name := range []string{".shopware-extension.yml", ".shopware-extension.yaml"} {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				configFile = filepath.Join(dir, name)
+				break
+			}
+		}
+
+		if configFile == "" {
+			logging.FromContext(cmd.Context()).Infof("No .shopware-extension.yml found in %s", dir)
+			return nil
+		}
+
+		problems, err := extension.ValidateExtensionConfigFile(configFile)
+		if err != nil {
+			return err
+		}
+
+		if len(problems) == 0 {
+			logging.FromContext(cmd.Context()).Infof("%s is valid", filepath.Base(configFile))
+			return nil
+		}
+
+		for _, problem := range problems {
+			fmt.Printf("%s:%s\n", filepath.Base(configFile), problem.String())
+		}
+
+		return fmt.Errorf("%s contains %d problems", filepath.Base(configFile), len(problems))
+	},
+}
+
+func init() {
+	extensionConfigCmd.AddCommand(extensionConfigValidateCmd)
+	extensionConfigValidateCmd.Flags().String("schema-output", "", "Write the JSON schema of the config to this file")
+}
@@ -0,0 +1,99 @@
+package extension
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/internal/interaction"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var extensionCreateCmd = &cobra.Command{
+	Use:   "create [plugin|app|theme] [name]",
+	Short: "Create a new extension with everything required to pass the validation",
+	Long: `Creates the folder <name> with the composer.json or manifest.xml, the plugin class, a config.xml, snippet files,
+the Administration entry point, changelogs and a GitHub Actions workflow running the validation.
+
+The namespace, supported Shopware versions and license are asked for, unless they are passed as flags.`,
+	Example: `  shopware-cli extension create plugin FroshTools --namespace 'Frosh\Tools' --shopware-version '~6.6.0' --license MIT`,
+	Args:    cobra.ExactArgs(2),
+	ValidArgs: []string{
+		extension.ScaffoldTypePlugin,
+		extension.ScaffoldTypeApp,
+		extension.ScaffoldTypeTheme,
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := extension.ScaffoldOptions{Type: args[0], Name: args[1]}
+
+		opts.Namespace, _ = cmd.Flags().GetString("namespace")
+		opts.ComposerName, _ = cmd.Flags().GetString("composer-name")
+		opts.ShopwareVersion, _ = cmd.Flags().GetString("shopware-version")
+		opts.License, _ = cmd.Flags().GetString("license")
+		opts.Author, _ = cmd.Flags().GetString("author")
+		opts.Label, _ = cmd.Flags().GetString("label")
+		opts.ManufacturerLink, _ = cmd.Flags().GetString("manufacturer-link")
+
+		if err := askScaffoldOptions(cmd, &opts); err != nil {
+			return err
+		}
+
+		outputDir, _ := cmd.Flags().GetString("output-dir")
+
+		files, err := extension.Scaffold(outputDir, opts)
+		if err != nil {
+			return err
+		}
+
+		for _, file := range files {
+			fmt.Println(filepath.Join(outputDir, opts.Name, file))
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Created %s, replace the placeholder description, links and icon before releasing it", opts.Name)
+
+		return nil
+	},
+}
+
+// askScaffoldOptions prompts for the values which were not passed as flags, apps have no PHP namespace
+func askScaffoldOptions(cmd *cobra.Command, opts *extension.ScaffoldOptions) error {
+	if !interaction.IsInteractive(cmd.Context()) {
+		return nil
+	}
+
+	var fields []huh.Field
+
+	if opts.Type != extension.ScaffoldTypeApp && !cmd.Flags().Changed("namespace") {
+		opts.Namespace = opts.Name
+		fields = append(fields, huh.NewInput().Title("PHP namespace").Value(&opts.Namespace))
+	}
+
+	if !cmd.Flags().Changed("shopware-version") {
+		fields = append(fields, huh.NewInput().Title("Supported Shopware versions as composer constraint").Value(&opts.ShopwareVersion))
+	}
+
+	if !cmd.Flags().Changed("license") {
+		fields = append(fields, huh.NewInput().Title("License as SPDX identifier or proprietary").Value(&opts.License))
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return huh.NewForm(huh.NewGroup(fields...)).Run()
+}
+
+func init() {
+	extensionRootCmd.AddCommand(extensionCreateCmd)
+	extensionCreateCmd.Flags().String("namespace", "", "PHP namespace of plugins and themes, defaults to the name")
+	extensionCreateCmd.Flags().String("composer-name", "", "Composer package name, defaults to the kebab case of the namespace")
+	extensionCreateCmd.Flags().String("shopware-version", "~6.6.0", "Supported Shopware versions as composer constraint")
+	extensionCreateCmd.Flags().String("license", "MIT", "License as SPDX identifier or proprietary")
+	extensionCreateCmd.Flags().String("author", "", "Author, defaults to the name")
+	extensionCreateCmd.Flags().String("label", "", "Label shown in the Administration, defaults to the name")
+	extensionCreateCmd.Flags().String("manufacturer-link", "", "Link to the manufacturer, also used as support link")
+	extensionCreateCmd.Flags().String("output-dir", ".", "Folder in which the extension folder is created")
+}
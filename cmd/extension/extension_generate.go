@@ -0,0 +1,70 @@
+package extension
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+)
+
+var extensionGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate boilerplate for common Shopware constructs into a plugin",
+	Long: `Generates the PHP, JavaScript and Twig files of the construct into an existing plugin.
+Services are registered in src/Resources/config/services.xml and Administration code is imported in main.js.
+Existing files are never overwritten.`,
+}
+
+var extensionGenerators = map[string]struct {
+	short   string
+	example string
+}{
+	extension.GenerateEntity:          {"Generate an entity definition, entity, collection and the migration creating its table", "Bundle"},
+	extension.GenerateCommand:         {"Generate a console command", "ExportProducts"},
+	extension.GenerateScheduledTask:   {"Generate a scheduled task with its handler", "Cleanup"},
+	extension.GenerateEventSubscriber: {"Generate an event subscriber", "Product"},
+	extension.GenerateAdminModule:     {"Generate an Administration module with a list page and snippets", "Overview"},
+	extension.GenerateCmsElement:      {"Generate a CMS element for the Administration and the Storefront", "Video"},
+	extension.GenerateMigration:       {"Generate a migration with a timestamp no other migration of the plugin uses", "AddColorToBundle"},
+}
+
+func newExtensionGenerateKindCmd(kind string) *cobra.Command {
+	return &cobra.Command{
+		Use:     kind + " [name] [path]",
+		Short:   extensionGenerators[kind].short,
+		Example: fmt.Sprintf("  shopware-cli extension generate %s %s ./FroshTools", kind, extensionGenerators[kind].example),
+		Args:    cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			extPath := "."
+			if len(args) > 1 {
+				extPath = args[1]
+			}
+
+			ext, err := extension.GetExtensionByFolder(extPath)
+			if err != nil {
+				return fmt.Errorf("detect extension type: %w", err)
+			}
+
+			files, err := extension.Generate(ext, extension.GenerateOptions{Kind: kind, Name: args[0]})
+			if err != nil {
+				return err
+			}
+
+			for _, file := range files {
+				fmt.Println(filepath.Join(extPath, file))
+			}
+
+			return nil
+		},
+	}
+}
+
+func init() {
+	extensionRootCmd.AddCommand(extensionGenerateCmd)
+
+	for _, kind := range extension.GenerateKinds {
+		extensionGenerateCmd.AddCommand(newExtensionGenerateKindCmd(kind))
+	}
+}
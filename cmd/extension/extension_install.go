@@ -0,0 +1,108 @@
+package extension
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	adminSdk "github.com/friendsofshopware/go-shopware-admin-api-sdk"
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/logging"
+	"github.com/shopware/shopware-cli/shop"
+)
+
+var extensionInstallCmd = &cobra.Command{
+	Use:   "install [path]",
+	Short: "Zip the extension and install it into a shop over the Admin API",
+	Long: `Builds the zip like extension zip, uploads it with the Extension API of the shop configured in the project config,
+installs, activates or updates it and clears the cache. The uncommitted state of the folder is used, pass --git to zip the last commit.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		extPath, err := filepath.Abs(args[0])
+		if err != nil {
+			return err
+		}
+
+		ext, err := extension.GetExtensionByFolder(extPath)
+		if err != nil {
+			return fmt.Errorf("detect extension type: %w", err)
+		}
+
+		name, err := ext.GetName()
+		if err != nil {
+			return fmt.Errorf("get name: %w", err)
+		}
+
+		shopConfig, _ := cmd.Flags().GetString("shop")
+
+		cfg, err := shop.ReadConfig(shopConfig, false)
+		if err != nil {
+			return err
+		}
+
+		client, err := shop.NewShopClient(cmd.Context(), cfg)
+		if err != nil {
+			return err
+		}
+
+		useGit, _ := cmd.Flags().GetBool("git")
+		disableGit = !useGit
+
+		// Without an output directory the zip is only kept until it is uploaded
+		if outputDir, _ := cmd.Flags().GetString("output-directory"); outputDir == "" {
+			tempDir, err := os.MkdirTemp("", "extension-install-*")
+			if err != nil {
+				return fmt.Errorf("create temp directory: %w", err)
+			}
+
+			defer func() { _ = os.RemoveAll(tempDir) }()
+
+			if err := cmd.Flags().Set("output-directory", tempDir); err != nil {
+				return err
+			}
+		}
+
+		zipPath, err := zipExtension(cmd, ext, extPath, "", nil)
+		if err != nil {
+			return err
+		}
+
+		zipFile, err := os.Open(zipPath)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = zipFile.Close() }()
+
+		adminCtx := adminSdk.NewApiContext(cmd.Context())
+
+		if err := shop.UploadExtensionZip(adminCtx, client, name, zipFile); err != nil {
+			return err
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Uploaded %s to %s", name, cfg.URL)
+
+		if err := shop.ActivateUploadedExtension(adminCtx, client, name); err != nil {
+			return err
+		}
+
+		if _, err := client.CacheManager.Clear(adminCtx); err != nil {
+			return fmt.Errorf("clear cache: %w", err)
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Cleared cache")
+
+		return nil
+	},
+}
+
+func init() {
+	extensionRootCmd.AddCommand(extensionInstallCmd)
+	extensionInstallCmd.Flags().String("shop", shop.DefaultConfigFileName(), "Project config with the URL and Admin API credentials of the shop")
+	extensionInstallCmd.Flags().Bool("git", false, "Zip the last commit instead of the folder as it is")
+	extensionInstallCmd.Flags().String("git-commit", "", "Commit Hash / Tag to use together with --git")
+	extensionInstallCmd.Flags().Bool("asset-cache", false, "Reuse node_modules and compiled assets when the sources did not change, SHOPWARE_CLI_ASSET_CACHE_URL adds a remote cache")
+	extensionInstallCmd.Flags().String("output-directory", "", "Keep the uploaded zip in this directory")
+}
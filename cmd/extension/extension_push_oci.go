@@ -0,0 +1,135 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/internal/artifactstore"
+	"github.com/shopware/shopware-cli/internal/git"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var extensionPushOCICmd = &cobra.Command{
+	Use:   "push-oci [reference] [zip]",
+	Short: "Push a extension zip as OCI artifact to a container registry",
+	Long: `Push a extension zip as OCI artifact, e.g. to ghcr.io/acme/myplugin:1.2.3. Without a tag the version of the extension is used.
+
+The manifest is annotated with the version, the compatible Shopware versions and the provenance of the build,
+so the artifact can be signed and inspected with container tooling like cosign and oras.
+The registry credentials are read from OCI_USERNAME and OCI_PASSWORD.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		zipFile, err := filepath.Abs(args[1])
+		if err != nil {
+			return fmt.Errorf("cannot find zip: %w", err)
+		}
+
+		ext, err := extension.GetExtensionByZip(zipFile)
+		if err != nil {
+			return fmt.Errorf("cannot open extension zip: %w", err)
+		}
+
+		annotations, err := extensionOCIAnnotations(cmd.Context(), ext)
+		if err != nil {
+			return err
+		}
+
+		extraAnnotations, _ := cmd.Flags().GetStringArray("annotation")
+
+		for _, annotation := range extraAnnotations {
+			key, value, ok := strings.Cut(annotation, "=")
+			if !ok || key == "" {
+				return fmt.Errorf("invalid annotation %q, use key=value", annotation)
+			}
+
+			annotations[key] = value
+		}
+
+		extVersion, err := ext.GetVersion()
+		if err != nil {
+			return fmt.Errorf("cannot read extension version: %w", err)
+		}
+
+		artifact, err := artifactstore.NewArtifact(zipFile, extVersion.String())
+		if err != nil {
+			return err
+		}
+
+		store, err := artifactstore.NewOCI(args[0], annotations)
+		if err != nil {
+			return err
+		}
+
+		locations, err := store.Put(cmd.Context(), []*artifactstore.Artifact{artifact})
+		if err != nil {
+			return fmt.Errorf("push to registry: %w", err)
+		}
+
+		for _, location := range locations {
+			logging.FromContext(cmd.Context()).Infof("Pushed %s", strings.TrimPrefix(location, "oci://"))
+		}
+
+		return nil
+	},
+}
+
+// extensionOCIAnnotations describes the extension with the annotations of the OCI image spec and shopware specific ones
+func extensionOCIAnnotations(ctx context.Context, ext extension.Extension) (map[string]string, error) {
+	name, err := ext.GetName()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read extension name: %w", err)
+	}
+
+	extVersion, err := ext.GetVersion()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read extension version: %w", err)
+	}
+
+	constraint, err := ext.GetShopwareVersionConstraint()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read shopware version constraint: %w", err)
+	}
+
+	annotations := map[string]string{
+		"org.opencontainers.image.title":       name,
+		"org.opencontainers.image.version":     extVersion.String(),
+		"com.shopware.extension.name":          name,
+		"com.shopware.extension.type":          ext.GetType(),
+		"com.shopware.extension.compatibility": constraint.String(),
+	}
+
+	if license, err := ext.GetLicense(); err == nil && license != "" {
+		annotations["org.opencontainers.image.licenses"] = license
+	}
+
+	if description := ext.GetMetaData().Description.English; description != "" {
+		annotations["org.opencontainers.image.description"] = description
+	}
+
+	// The zip has no git history, the provenance comes from the CI or the repository of the current directory
+	provenance := git.GetProvenance(ctx, ".")
+
+	if provenance.Source != "" {
+		annotations["org.opencontainers.image.source"] = provenance.Source
+	}
+
+	if provenance.Revision != "" {
+		annotations["org.opencontainers.image.revision"] = provenance.Revision
+	}
+
+	if provenance.BuildURL != "" {
+		annotations["com.shopware.extension.build-url"] = provenance.BuildURL
+	}
+
+	return annotations, nil
+}
+
+func init() {
+	extensionRootCmd.AddCommand(extensionPushOCICmd)
+	extensionPushOCICmd.Flags().StringArray("annotation", []string{}, "Additional manifest annotation as key=value, can be repeated")
+}
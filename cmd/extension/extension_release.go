@@ -0,0 +1,276 @@
+package extension
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/cmd/account"
+	"github.com/shopware/shopware-cli/extension"
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+	"github.com/shopware/shopware-cli/internal/ci"
+	"github.com/shopware/shopware-cli/internal/config"
+	"github.com/shopware/shopware-cli/internal/verifier"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var extensionReleaseCmd = &cobra.Command{
+	Use:   "release [path]",
+	Short: "Validates, builds, zips and uploads an extension to the Shopware Store",
+	Long: `Publishes an extension folder in one step: validate, build the assets, zip, extract the changelog,
+create or update the binary in the Shopware Account, upload the zip, sync the store page with icon and images,
+trigger the code review and wait for its result. --github additionally publishes the zip in a GitHub or GitLab release. Every step can be skipped, --summary writes the result of all steps as JSON.`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		mode, _ := cmd.Flags().GetString("check-against")
+		if mode != "highest" && mode != "lowest" {
+			return fmt.Errorf("invalid mode: %s. Must be either 'highest' or 'lowest'", mode)
+		}
+
+		if full, _ := cmd.Flags().GetBool("full"); !full || releaseSkipValidate {
+			return nil
+		}
+
+		return verifier.SetupTools(cmd.Context(), cmd.Root().Version)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		extensionReleaseMode = true
+
+		extPath, err := filepath.Abs(args[0])
+		if err != nil {
+			return err
+		}
+
+		ext, err := extension.GetExtensionByFolder(extPath)
+		if err != nil {
+			return fmt.Errorf("detect extension type: %w", err)
+		}
+
+		name, err := ext.GetName()
+		if err != nil {
+			return fmt.Errorf("get name: %w", err)
+		}
+
+		summary := &releaseSummary{Extension: name, Steps: []releaseStep{}}
+
+		force, _ := cmd.Flags().GetBool("force")
+
+		opts := releaseOptions{
+			SkipValidate:      releaseSkipValidate,
+			SkipBuild:         releaseSkipBuild,
+			BuildWhileZipping: ext.GetExtensionConfig().Build.Zip.Assets.Enabled,
+			Github:            releaseGithub,
+			SkipStoreInfo:     releaseSkipStoreInfo,
+			SkipReview:        releaseSkipReview,
+			SkipWait:          releaseSkipWait,
+			Force:             force,
+		}
+
+		steps := &cliReleaseSteps{cmd: cmd, ext: ext, extPath: extPath}
+		defer steps.Close()
+
+		err = runReleasePipeline(cmd.Context(), steps, opts, summary)
+		summary.Success = err == nil
+
+		if output, _ := cmd.Flags().GetString("summary"); output != "" {
+			if writeErr := summary.write(output, os.Stdout); writeErr != nil {
+				return errors.Join(err, fmt.Errorf("write summary: %w", writeErr))
+			}
+		}
+
+		return err
+	},
+}
+
+// cliReleaseSteps runs the release steps with the flags of the command, the account steps are the ones of the
+// store release once the zip passed the preflight
+type cliReleaseSteps struct {
+	*account.StoreRelease
+
+	cmd     *cobra.Command
+	ext     extension.Extension
+	extPath string
+}
+
+// Close removes the temporary files of the store release, the steps before the preflight have none
+func (s *cliReleaseSteps) Close() {
+	if s.StoreRelease != nil {
+		s.StoreRelease.Close()
+	}
+}
+
+func (s *cliReleaseSteps) Validate(ctx context.Context) error {
+	return validateExtension(s.cmd, s.extPath, func(result *verifier.Check, rootDir string) error {
+		if err := verifier.DoCheckReport(ctx, result, verifier.DetectDefaultReporter(), rootDir); err != nil {
+			return err
+		}
+
+		if result.HasErrors() {
+			return fmt.Errorf("the extension has validation errors")
+		}
+
+		return nil
+	})
+}
+
+func (s *cliReleaseSteps) Build(_ context.Context) error {
+	return buildReleaseAssets(s.cmd, s.ext)
+}
+
+func (s *cliReleaseSteps) Zip(_ context.Context) (string, error) {
+	return zipExtension(s.cmd, s.ext, s.extPath, "", nil)
+}
+
+func (s *cliReleaseSteps) Prepare(ctx context.Context, zipPath string) (preparedRelease, error) {
+	client, err := account_api.NewApi(ctx, config.Config{})
+	if err != nil {
+		return preparedRelease{}, err
+	}
+
+	onConflict, _ := s.cmd.Flags().GetString("on-conflict")
+
+	// The preflight checklist goes to stderr, so --summary - keeps stdout parseable
+	release, err := account.PrepareStoreRelease(ctx, client, zipPath, onConflict, os.Stderr)
+	if err != nil {
+		return preparedRelease{}, err
+	}
+
+	if err := release.ApplyVersionBump(ctx, false); err != nil {
+		return preparedRelease{}, err
+	}
+
+	s.StoreRelease = release
+
+	return preparedRelease{Version: release.Version.String(), ZipPath: release.ZipPath, Skipped: release.Skipped()}, nil
+}
+
+// SaveBinary runs the before upload hooks first like account producer extension upload does
+func (s *cliReleaseSteps) SaveBinary(ctx context.Context) error {
+	if err := s.RunBeforeUploadHooks(); err != nil {
+		return err
+	}
+
+	return s.StoreRelease.SaveBinary(ctx)
+}
+
+func (s *cliReleaseSteps) Upload(ctx context.Context, force bool) (bool, error) {
+	return s.UploadZip(ctx, force)
+}
+
+func (s *cliReleaseSteps) PublishRepositoryRelease(ctx context.Context) (string, error) {
+	return publishRepositoryRelease(ctx, s.ext, s.StoreRelease)
+}
+
+// buildReleaseAssets builds the assets in the extension folder like extension build
+func buildReleaseAssets(cmd *cobra.Command, ext extension.Extension) error {
+	assetCfg := extension.AssetBuildConfig{
+		ShopwareRoot: os.Getenv("SHOPWARE_PROJECT_ROOT"),
+	}
+
+	if useCache, _ := cmd.Flags().GetBool("asset-cache"); useCache {
+		assetCfg.Cache = newAssetCache()
+	}
+
+	if assetCfg.ShopwareRoot != "" {
+		constraint, err := extension.GetShopwareProjectConstraint(assetCfg.ShopwareRoot)
+		if err != nil {
+			return fmt.Errorf("cannot get shopware version constraint from project %s: %w", assetCfg.ShopwareRoot, err)
+		}
+		assetCfg.ShopwareVersion = constraint
+	} else {
+		constraint, err := ext.GetShopwareVersionConstraint()
+		if err != nil {
+			return fmt.Errorf("cannot get shopware version constraint: %w", err)
+		}
+
+		assetCfg.ShopwareVersion = constraint
+	}
+
+	assetCfg.SourceMaps = bundleReportRequested(cmd, []extension.Extension{ext})
+
+	if err := extension.BuildAssetsForExtensions(cmd.Context(), extension.ConvertExtensionsToSources(cmd.Context(), []extension.Extension{ext}), assetCfg); err != nil {
+		return fmt.Errorf("cannot build assets: %w", err)
+	}
+
+	return reportBundleSizes(cmd, []extension.Extension{ext}, assetCfg.SourceMaps)
+}
+
+// publishRepositoryRelease creates the release configured in the release section of the extension config with the zip,
+// its checksum and the changelog of the store
+func publishRepositoryRelease(ctx context.Context, ext extension.Extension, release *account.StoreRelease) (string, error) {
+	cfg := extension.ConfigRelease{}
+	if extCfg := ext.GetExtensionConfig(); extCfg != nil {
+		cfg = extCfg.Release
+	}
+
+	publisher, err := ci.NewReleasePublisher(cfg.Provider, cfg.Repository, cfg.Url)
+	if err != nil {
+		return "", err
+	}
+
+	checksumFile, err := writeChecksumFile(release.ZipPath)
+	if err != nil {
+		return "", err
+	}
+
+	version := release.Version.String()
+	tag := strings.ReplaceAll(cmp.Or(cfg.Tag, "{version}"), "{version}", version)
+
+	body := release.Changelog().English
+	if body == "" {
+		body = release.Changelog().German
+	}
+
+	releaseURL, err := publisher.PublishRelease(ctx, ci.Release{
+		Tag:        tag,
+		Name:       strings.ReplaceAll(cmp.Or(cfg.Name, tag), "{version}", version),
+		Body:       account_api.SanitizeStoreHTML(body),
+		Target:     cfg.Target,
+		Draft:      cfg.Draft,
+		Prerelease: cfg.Prerelease,
+		Assets:     []string{release.ZipPath, checksumFile},
+		Package:    release.Extension().Name,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	logging.FromContext(ctx).Infof("Published release %s", releaseURL)
+
+	return releaseURL, nil
+}
+
+var (
+	releaseGithub        bool
+	releaseSkipValidate  bool
+	releaseSkipBuild     bool
+	releaseSkipStoreInfo bool
+	releaseSkipReview    bool
+	releaseSkipWait      bool
+)
+
+func init() {
+	extensionRootCmd.AddCommand(extensionReleaseCmd)
+	extensionReleaseCmd.Flags().BoolVar(&releaseGithub, "github", false, "Create a GitHub or GitLab release with the zip, its checksum and the changelog after the upload, configured in the release section of the extension config")
+	extensionReleaseCmd.Flags().BoolVar(&releaseSkipValidate, "skip-validate", false, "Skip the validation of the extension")
+	extensionReleaseCmd.Flags().BoolVar(&releaseSkipBuild, "skip-build", false, "Skip building the assets in the extension folder")
+	extensionReleaseCmd.Flags().BoolVar(&releaseSkipStoreInfo, "skip-store-info", false, "Skip syncing the store page, icon and images from the extension config")
+	extensionReleaseCmd.Flags().BoolVar(&releaseSkipReview, "skip-review", false, "Skip triggering the code review")
+	extensionReleaseCmd.Flags().BoolVar(&releaseSkipWait, "skip-wait", false, "Skip waiting for the code review result")
+	extensionReleaseCmd.Flags().Bool("full", false, "Run full validation including PHPStan, ESLint and Stylelint")
+	extensionReleaseCmd.Flags().String("check-against", "highest", "Check against Shopware Version (highest, lowest)")
+	extensionReleaseCmd.Flags().BoolVar(&disableGit, "disable-git", false, "Use the source folder as it is")
+	extensionReleaseCmd.Flags().String("git-commit", "", "Commit Hash / Tag to use")
+	extensionReleaseCmd.Flags().String("output-directory", "", "Output directory for the zip file")
+	extensionReleaseCmd.Flags().Bool("asset-cache", false, "Reuse node_modules and compiled assets when the sources did not change, SHOPWARE_CLI_ASSET_CACHE_URL adds a remote cache")
+	addBundleReportFlag(extensionReleaseCmd)
+	extensionReleaseCmd.Flags().String("on-conflict", "", "What to do when the version exists already: skip, replace-if-not-reviewed, bump-patch or fail. Defaults to store.upload_on_conflict of the extension config or replace-if-not-reviewed")
+	extensionReleaseCmd.Flags().Bool("force", false, "Uploads the zip even when the same content was already uploaded for this version")
+	extensionReleaseCmd.Flags().String("summary", "", "Write the result of all steps as JSON into this file, - writes it to stdout")
+}
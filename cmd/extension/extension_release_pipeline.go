@@ -0,0 +1,264 @@
+package extension
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/shopware/shopware-cli/cmd/account"
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+const (
+	releaseStepDone    = "done"
+	releaseStepSkipped = "skipped"
+	releaseStepFailed  = "failed"
+)
+
+type releaseStep struct {
+	Name            string  `json:"name"`
+	Status          string  `json:"status"`
+	Message         string  `json:"message,omitempty"`
+	DurationSeconds float64 `json:"durationSeconds"`
+}
+
+type releaseReview struct {
+	Passed      bool   `json:"passed"`
+	HasWarnings bool   `json:"hasWarnings"`
+	Summary     string `json:"summary,omitempty"`
+	Issue       string `json:"issue,omitempty"`
+}
+
+// releaseSummary is the machine-readable result of extension release
+type releaseSummary struct {
+	Extension string         `json:"extension"`
+	Version   string         `json:"version,omitempty"`
+	Zip       string         `json:"zip,omitempty"`
+	Release   string         `json:"release,omitempty"`
+	Steps     []releaseStep  `json:"steps"`
+	Review    *releaseReview `json:"review,omitempty"`
+	Success   bool           `json:"success"`
+}
+
+// run executes a step and records its outcome, a failed step is returned wrapped with its name
+func (s *releaseSummary) run(ctx context.Context, name string, fn func() error) error {
+	logging.FromContext(ctx).Infof("Release step: %s", name)
+
+	start := time.Now()
+	err := fn()
+
+	step := releaseStep{Name: name, Status: releaseStepDone, DurationSeconds: time.Since(start).Seconds()}
+
+	if err != nil {
+		step.Status = releaseStepFailed
+		step.Message = err.Error()
+	}
+
+	s.Steps = append(s.Steps, step)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (s *releaseSummary) skip(ctx context.Context, name, reason string) {
+	logging.FromContext(ctx).Infof("Release step: %s skipped, %s", name, reason)
+
+	s.Steps = append(s.Steps, releaseStep{Name: name, Status: releaseStepSkipped, Message: reason})
+}
+
+// write stores the summary in the output file, - writes it to stdout
+func (s *releaseSummary) write(output string, stdout io.Writer) error {
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if output == "-" {
+		_, err = fmt.Fprintln(stdout, string(content))
+		return err
+	}
+
+	return os.WriteFile(output, content, 0o644)
+}
+
+// releaseOptions are the skip flags of extension release
+type releaseOptions struct {
+	SkipValidate bool
+	SkipBuild    bool
+	// BuildWhileZipping is set when build.zip.assets.enabled builds the assets in the zip step
+	BuildWhileZipping bool
+	Github            bool
+	SkipStoreInfo     bool
+	SkipReview        bool
+	SkipWait          bool
+	Force             bool
+}
+
+// preparedRelease is the version in the account after the preflight
+type preparedRelease struct {
+	Version string
+	ZipPath string
+	// Skipped is set when the version exists already and the conflict strategy is skip
+	Skipped bool
+}
+
+// releaseSteps are the single steps of extension release, runReleasePipeline decides which of them run
+type releaseSteps interface {
+	Validate(ctx context.Context) error
+	Build(ctx context.Context) error
+	Zip(ctx context.Context) (string, error)
+	// Prepare runs the preflight of the zip in the account and applies a version bump
+	Prepare(ctx context.Context, zipPath string) (preparedRelease, error)
+	// SaveBinary runs the before upload hooks and creates or updates the binary in the account
+	SaveBinary(ctx context.Context) error
+	// Upload uploads the zip, it returns false when nothing has been uploaded
+	Upload(ctx context.Context, force bool) (bool, error)
+	PublishRepositoryRelease(ctx context.Context) (string, error)
+	SyncStoreInfo(ctx context.Context) error
+	TriggerReview(ctx context.Context) error
+	WaitForReview(ctx context.Context) (*account_api.BinaryReviewResult, bool, error)
+	ReportFailedReview(ctx context.Context, review *account_api.BinaryReviewResult) string
+}
+
+// runReleasePipeline runs the steps in order and records each of them in the summary. It stops at the first failed step.
+func runReleasePipeline(ctx context.Context, steps releaseSteps, opts releaseOptions, summary *releaseSummary) error {
+	if opts.SkipValidate {
+		summary.skip(ctx, "validate", "--skip-validate is set")
+	} else if err := summary.run(ctx, "validate", func() error {
+		return steps.Validate(ctx)
+	}); err != nil {
+		return err
+	}
+
+	if opts.SkipBuild {
+		summary.skip(ctx, "build", "--skip-build is set")
+	} else if opts.BuildWhileZipping {
+		summary.skip(ctx, "build", "the assets are built while zipping (build.zip.assets.enabled)")
+	} else if err := summary.run(ctx, "build", func() error {
+		return steps.Build(ctx)
+	}); err != nil {
+		return err
+	}
+
+	if err := summary.run(ctx, "zip", func() error {
+		zipPath, err := steps.Zip(ctx)
+		summary.Zip = zipPath
+
+		return err
+	}); err != nil {
+		return err
+	}
+
+	var release preparedRelease
+
+	if err := summary.run(ctx, "changelog", func() error {
+		var err error
+		release, err = steps.Prepare(ctx, summary.Zip)
+		if err != nil {
+			return err
+		}
+
+		summary.Version = release.Version
+		summary.Zip = release.ZipPath
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if release.Skipped {
+		for _, name := range []string{"binary", "upload", "github-release", "store-info", "review", "wait"} {
+			summary.skip(ctx, name, fmt.Sprintf("version %s exists already in the account", release.Version))
+		}
+
+		return nil
+	}
+
+	if err := summary.run(ctx, "binary", func() error {
+		return steps.SaveBinary(ctx)
+	}); err != nil {
+		return err
+	}
+
+	uploaded := false
+
+	if err := summary.run(ctx, "upload", func() error {
+		var err error
+		uploaded, err = steps.Upload(ctx, opts.Force)
+
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if !opts.Github {
+		summary.skip(ctx, "github-release", "--github is not set")
+	} else if err := summary.run(ctx, "github-release", func() error {
+		releaseURL, err := steps.PublishRepositoryRelease(ctx)
+		summary.Release = releaseURL
+
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if opts.SkipStoreInfo {
+		summary.skip(ctx, "store-info", "--skip-store-info is set")
+	} else if err := summary.run(ctx, "store-info", func() error {
+		return steps.SyncStoreInfo(ctx)
+	}); err != nil {
+		return err
+	}
+
+	switch {
+	case !uploaded:
+		summary.skip(ctx, "review", "no new zip has been uploaded")
+		summary.skip(ctx, "wait", "no new zip has been uploaded")
+
+		return nil
+	case opts.SkipReview:
+		summary.skip(ctx, "review", "--skip-review is set")
+		summary.skip(ctx, "wait", "--skip-review is set")
+
+		return nil
+	}
+
+	if err := summary.run(ctx, "review", func() error {
+		return steps.TriggerReview(ctx)
+	}); err != nil {
+		return err
+	}
+
+	if opts.SkipWait {
+		summary.skip(ctx, "wait", "--skip-wait is set")
+		return nil
+	}
+
+	return summary.run(ctx, "wait", func() error {
+		review, finished, err := steps.WaitForReview(ctx)
+		if err != nil {
+			return err
+		}
+
+		if !finished {
+			return fmt.Errorf("the code review did not finish in time")
+		}
+
+		summary.Review = &releaseReview{Passed: review.HasPassed(), HasWarnings: review.HasWarnings(), Summary: review.GetSummary()}
+
+		if err := account.EvaluateReview(ctx, review); err != nil {
+			summary.Review.Issue = steps.ReportFailedReview(ctx, review)
+
+			return err
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,224 @@
+package extension
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+)
+
+// fakeReleaseSteps records the called steps, errors fails the step with the given name
+type fakeReleaseSteps struct {
+	called   []string
+	errors   map[string]error
+	skipped  bool
+	uploaded bool
+	review   *account_api.BinaryReviewResult
+	force    bool
+}
+
+func (f *fakeReleaseSteps) step(name string) error {
+	f.called = append(f.called, name)
+
+	return f.errors[name]
+}
+
+func (f *fakeReleaseSteps) Validate(context.Context) error { return f.step("validate") }
+
+func (f *fakeReleaseSteps) Build(context.Context) error { return f.step("build") }
+
+func (f *fakeReleaseSteps) Zip(context.Context) (string, error) {
+	return "/tmp/FroshTools.zip", f.step("zip")
+}
+
+func (f *fakeReleaseSteps) Prepare(_ context.Context, zipPath string) (preparedRelease, error) {
+	if err := f.step("changelog"); err != nil {
+		return preparedRelease{}, err
+	}
+
+	return preparedRelease{Version: "1.0.1", ZipPath: zipPath, Skipped: f.skipped}, nil
+}
+
+func (f *fakeReleaseSteps) SaveBinary(context.Context) error { return f.step("binary") }
+
+func (f *fakeReleaseSteps) Upload(_ context.Context, force bool) (bool, error) {
+	f.force = force
+
+	return f.uploaded, f.step("upload")
+}
+
+func (f *fakeReleaseSteps) PublishRepositoryRelease(context.Context) (string, error) {
+	return "https://github.com/FriendsOfShopware/FroshTools/releases/tag/1.0.1", f.step("github-release")
+}
+
+func (f *fakeReleaseSteps) SyncStoreInfo(context.Context) error { return f.step("store-info") }
+
+func (f *fakeReleaseSteps) TriggerReview(context.Context) error { return f.step("review") }
+
+func (f *fakeReleaseSteps) WaitForReview(context.Context) (*account_api.BinaryReviewResult, bool, error) {
+	return f.review, f.review != nil, f.step("wait")
+}
+
+func (f *fakeReleaseSteps) ReportFailedReview(context.Context, *account_api.BinaryReviewResult) string {
+	return "https://github.com/FriendsOfShopware/FroshTools/issues/1"
+}
+
+func passedReview() *account_api.BinaryReviewResult {
+	review := &account_api.BinaryReviewResult{}
+	review.Type.Id = 3
+
+	return review
+}
+
+func stepStatuses(summary *releaseSummary) map[string]string {
+	statuses := map[string]string{}
+
+	for _, step := range summary.Steps {
+		statuses[step.Name] = step.Status
+	}
+
+	return statuses
+}
+
+func TestReleasePipelineRunsAllSteps(t *testing.T) {
+	steps := &fakeReleaseSteps{uploaded: true, review: passedReview()}
+	summary := &releaseSummary{Extension: "FroshTools"}
+
+	require.NoError(t, runReleasePipeline(t.Context(), steps, releaseOptions{Github: true, Force: true}, summary))
+
+	assert.Equal(t, []string{"validate", "build", "zip", "changelog", "binary", "upload", "github-release", "store-info", "review", "wait"}, steps.called)
+	assert.True(t, steps.force)
+	assert.Equal(t, "1.0.1", summary.Version)
+	assert.Equal(t, "/tmp/FroshTools.zip", summary.Zip)
+	assert.Equal(t, "https://github.com/FriendsOfShopware/FroshTools/releases/tag/1.0.1", summary.Release)
+	require.NotNil(t, summary.Review)
+	assert.True(t, summary.Review.Passed)
+
+	for _, step := range summary.Steps {
+		assert.Equal(t, releaseStepDone, step.Status, step.Name)
+	}
+}
+
+func TestReleasePipelineSkipFlags(t *testing.T) {
+	cases := []struct {
+		name    string
+		opts    releaseOptions
+		skipped []string
+	}{
+		{name: "validate", opts: releaseOptions{SkipValidate: true}, skipped: []string{"validate"}},
+		{name: "build", opts: releaseOptions{SkipBuild: true}, skipped: []string{"build"}},
+		{name: "build while zipping", opts: releaseOptions{BuildWhileZipping: true}, skipped: []string{"build"}},
+		{name: "store info", opts: releaseOptions{SkipStoreInfo: true}, skipped: []string{"store-info"}},
+		{name: "review", opts: releaseOptions{SkipReview: true}, skipped: []string{"review", "wait"}},
+		{name: "wait", opts: releaseOptions{SkipWait: true}, skipped: []string{"wait"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			steps := &fakeReleaseSteps{uploaded: true, review: passedReview()}
+			summary := &releaseSummary{}
+
+			require.NoError(t, runReleasePipeline(t.Context(), steps, tc.opts, summary))
+
+			statuses := stepStatuses(summary)
+
+			for _, name := range tc.skipped {
+				assert.Equal(t, releaseStepSkipped, statuses[name], name)
+				assert.NotContains(t, steps.called, name)
+			}
+
+			// Without --github the repository release is always skipped
+			assert.Equal(t, releaseStepSkipped, statuses["github-release"])
+			assert.Len(t, summary.Steps, 10)
+		})
+	}
+}
+
+func TestReleasePipelineSkipsExistingVersion(t *testing.T) {
+	steps := &fakeReleaseSteps{skipped: true}
+	summary := &releaseSummary{}
+
+	require.NoError(t, runReleasePipeline(t.Context(), steps, releaseOptions{}, summary))
+
+	assert.Equal(t, []string{"validate", "build", "zip", "changelog"}, steps.called)
+
+	statuses := stepStatuses(summary)
+	for _, name := range []string{"binary", "upload", "github-release", "store-info", "review", "wait"} {
+		assert.Equal(t, releaseStepSkipped, statuses[name], name)
+	}
+}
+
+func TestReleasePipelineSkipsReviewWithoutUpload(t *testing.T) {
+	steps := &fakeReleaseSteps{uploaded: false}
+	summary := &releaseSummary{}
+
+	require.NoError(t, runReleasePipeline(t.Context(), steps, releaseOptions{}, summary))
+
+	assert.NotContains(t, steps.called, "review")
+	assert.Equal(t, releaseStepSkipped, stepStatuses(summary)["review"])
+	assert.Equal(t, releaseStepSkipped, stepStatuses(summary)["wait"])
+}
+
+func TestReleasePipelineStopsAtFailedStep(t *testing.T) {
+	uploadErr := errors.New("connection reset")
+	steps := &fakeReleaseSteps{uploaded: true, errors: map[string]error{"upload": uploadErr}}
+	summary := &releaseSummary{}
+
+	err := runReleasePipeline(t.Context(), steps, releaseOptions{}, summary)
+
+	require.ErrorIs(t, err, uploadErr)
+	assert.EqualError(t, err, "upload: connection reset")
+	assert.Equal(t, []string{"validate", "build", "zip", "changelog", "binary", "upload"}, steps.called)
+
+	last := summary.Steps[len(summary.Steps)-1]
+	assert.Equal(t, "upload", last.Name)
+	assert.Equal(t, releaseStepFailed, last.Status)
+	assert.Equal(t, "connection reset", last.Message)
+}
+
+func TestReleasePipelineFailsOnFailedReview(t *testing.T) {
+	steps := &fakeReleaseSteps{uploaded: true, review: &account_api.BinaryReviewResult{}}
+	summary := &releaseSummary{}
+
+	err := runReleasePipeline(t.Context(), steps, releaseOptions{}, summary)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "wait: code review has not passed")
+	require.NotNil(t, summary.Review)
+	assert.False(t, summary.Review.Passed)
+	assert.Equal(t, "https://github.com/FriendsOfShopware/FroshTools/issues/1", summary.Review.Issue)
+}
+
+func TestReleasePipelineFailsWhenReviewDoesNotFinish(t *testing.T) {
+	steps := &fakeReleaseSteps{uploaded: true}
+	summary := &releaseSummary{}
+
+	assert.EqualError(t, runReleasePipeline(t.Context(), steps, releaseOptions{}, summary), "wait: the code review did not finish in time")
+	assert.Nil(t, summary.Review)
+}
+
+func TestReleaseSummaryWrite(t *testing.T) {
+	summary := &releaseSummary{Extension: "FroshTools", Version: "1.0.1", Steps: []releaseStep{{Name: "zip", Status: releaseStepDone}}, Success: true}
+
+	var stdout bytes.Buffer
+	require.NoError(t, summary.write("-", &stdout))
+
+	var written releaseSummary
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &written))
+	assert.Equal(t, *summary, written)
+
+	output := filepath.Join(t.TempDir(), "summary.json")
+	require.NoError(t, summary.write(output, &stdout))
+
+	content, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.JSONEq(t, stdout.String(), string(content))
+}
@@ -0,0 +1,12 @@
+package extension
+
+import "github.com/spf13/cobra"
+
+var extensionStoreCmd = &cobra.Command{
+	Use:   "store",
+	Short: "Shopware Store commands",
+}
+
+func init() {
+	extensionRootCmd.AddCommand(extensionStoreCmd)
+}
@@ -0,0 +1,132 @@
+package extension
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+	"github.com/shopware/shopware-cli/internal/color"
+	"github.com/shopware/shopware-cli/internal/coretemplate"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// storePreviewText is a text shown in the Shopware Store
+type storePreviewText struct {
+	name    string
+	content string
+}
+
+var extensionStorePreviewCmd = &cobra.Command{
+	Use:   "preview [path]",
+	Short: "Show the markup of the store descriptions and changelogs which the store preview does not render",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := filepath.Abs(args[0])
+		if err != nil {
+			return fmt.Errorf("cannot find path: %w", err)
+		}
+
+		stat, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("cannot find path: %w", err)
+		}
+
+		var ext extension.Extension
+
+		if stat.IsDir() {
+			ext, err = extension.GetExtensionByFolder(path)
+		} else {
+			ext, err = extension.GetExtensionByZip(path)
+		}
+
+		if err != nil {
+			return fmt.Errorf("cannot open extension: %w", err)
+		}
+
+		texts, err := collectStorePreviewTexts(ext)
+		if err != nil {
+			return err
+		}
+
+		if changelog, err := ext.GetChangelog(); err != nil {
+			logging.FromContext(cmd.Context()).Warnf("Skipping the changelog: %v", err)
+		} else {
+			texts = append(texts, storePreviewText{name: "Changelog (de)", content: changelog.German}, storePreviewText{name: "Changelog (en)", content: changelog.English})
+		}
+
+		changed := 0
+
+		for _, text := range texts {
+			diff := coretemplate.Diff(text.content, account_api.SanitizeStoreHTML(text.content))
+
+			if !coretemplate.HasChanges(diff) {
+				fmt.Printf("%s %s\n", color.GreenText.Render("✓"), text.name)
+				continue
+			}
+
+			changed++
+
+			fmt.Printf("%s %s\n", color.RedText.Render("✗"), color.BoldText.Render(text.name))
+
+			for _, line := range diff {
+				switch line.Type {
+				case diffmatchpatch.DiffInsert:
+					fmt.Println(color.GreenText.Render(line.String()))
+				case diffmatchpatch.DiffDelete:
+					fmt.Println(color.RedText.Render(line.String()))
+				}
+			}
+		}
+
+		if changed > 0 {
+			return fmt.Errorf("%d texts contain markup which the store preview does not render", changed)
+		}
+
+		return nil
+	},
+}
+
+// collectStorePreviewTexts returns the configured descriptions and installation manuals in the order of the languages
+func collectStorePreviewTexts(ext extension.Extension) ([]storePreviewText, error) {
+	cfg := ext.GetExtensionConfig()
+	texts := []storePreviewText{}
+
+	options := []struct {
+		name  string
+		value extension.ConfigTranslated[string]
+	}{
+		{name: "Description", value: cfg.Store.Description},
+		{name: "Installation manual", value: cfg.Store.InstallationManual},
+	}
+
+	for _, option := range options {
+		for _, language := range []string{"de", "en"} {
+			value := option.value.German
+			if language == "en" {
+				value = option.value.English
+			}
+
+			if value == nil || *value == "" {
+				continue
+			}
+
+			content, err := extension.ReadStoreText(*value, ext.GetPath())
+			if err != nil {
+				return nil, err
+			}
+
+			texts = append(texts, storePreviewText{name: fmt.Sprintf("%s (%s)", option.name, language), content: content})
+		}
+	}
+
+	return texts, nil
+}
+
+func init() {
+	extensionStoreCmd.AddCommand(extensionStorePreviewCmd)
+}
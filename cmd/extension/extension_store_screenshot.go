@@ -0,0 +1,81 @@
+package extension
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/internal/screenshot"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var extensionStoreScreenshotCmd = &cobra.Command{
+	Use:   "screenshot [path]",
+	Short: "Capture the pages of store.screenshots from a demo shop into the store image directory",
+	Long: `Opens the German and English URL of every page in store.screenshots with a headless Chromium and saves the screenshots
+into the de and en folders of store.image_directory. account producer extension info push uploads them in the configured order.
+Chromium or Chrome is searched in the PATH, set CHROME_PATH to use another installation.`,
+	Example: "  shopware-cli extension store screenshot --shop-url http://localhost:8000 ./FroshTools",
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		extPath := "."
+		if len(args) > 0 {
+			extPath = args[0]
+		}
+
+		ext, err := extension.GetExtensionByFolder(extPath)
+		if err != nil {
+			return fmt.Errorf("detect extension type: %w", err)
+		}
+
+		shopUrl, _ := cmd.Flags().GetString("shop-url")
+
+		screenshots, err := extension.StoreScreenshots(ext, shopUrl)
+		if err != nil {
+			return err
+		}
+
+		executable, err := screenshot.FindChromium()
+		if err != nil {
+			return err
+		}
+
+		width, height := ext.GetExtensionConfig().Store.Screenshots.Size()
+
+		browser, err := screenshot.Launch(cmd.Context(), executable, width, height)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = browser.Close() }()
+
+		for _, page := range screenshots {
+			logging.FromContext(cmd.Context()).Infof("Capturing %s", page.Url)
+
+			png, err := browser.Capture(cmd.Context(), page.Url, page.Selector, page.WaitFor)
+			if err != nil {
+				return err
+			}
+
+			if err := os.MkdirAll(filepath.Dir(page.File), os.ModePerm); err != nil {
+				return err
+			}
+
+			if err := os.WriteFile(page.File, png, os.ModePerm); err != nil {
+				return err
+			}
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Captured %d screenshots in %dx%d, upload them with account producer extension info push", len(screenshots), width, height)
+
+		return nil
+	},
+}
+
+func init() {
+	extensionStoreCmd.AddCommand(extensionStoreScreenshotCmd)
+	extensionStoreScreenshotCmd.Flags().String("shop-url", "", "URL of the demo shop, defaults to store.screenshots.shop_url")
+}
@@ -0,0 +1,122 @@
+package extension
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/internal/devsync"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var extensionSyncCmd = &cobra.Command{
+	Use:   "sync [path]",
+	Short: "Watch an extension and copy changed files into a remote dev shop",
+	Long: `Copies the PHP, Twig and config files of the extension into the custom/plugins (or custom/apps) folder of a remote shop
+and keeps copying them whenever they change. The shop is reached with rsync over SSH, or with the sftp client for sftp:// targets.
+
+Deleted files are not removed from the remote shop.`,
+	Example: `  shopware-cli extension sync . --to dev@shop.example.com:/var/www/shop
+  shopware-cli extension sync . --to sftp://dev@shop.example.com:2222/var/www/shop --cache-clear`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		extPath, err := filepath.Abs(args[0])
+		if err != nil {
+			return err
+		}
+
+		ext, err := extension.GetExtensionByFolder(extPath)
+		if err != nil {
+			return fmt.Errorf("detect extension type: %w", err)
+		}
+
+		name, err := ext.GetName()
+		if err != nil {
+			return fmt.Errorf("get name: %w", err)
+		}
+
+		to, _ := cmd.Flags().GetString("to")
+
+		target, err := devsync.ParseTarget(to)
+		if err != nil {
+			return err
+		}
+
+		patterns, _ := cmd.Flags().GetStringSlice("pattern")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		once, _ := cmd.Flags().GetBool("once")
+		cacheClear, _ := cmd.Flags().GetBool("cache-clear")
+		cacheClearCommand, _ := cmd.Flags().GetString("cache-clear-command")
+
+		folder := "plugins"
+		if ext.GetType() == extension.TypePlatformApp {
+			folder = "apps"
+		}
+
+		remoteDir := path.Join(target.Root, "custom", folder, name)
+
+		syncFiles := func(files []string) error {
+			if len(files) == 0 {
+				return nil
+			}
+
+			if err := devsync.Upload(cmd.Context(), target, extPath, remoteDir, files); err != nil {
+				return err
+			}
+
+			logging.FromContext(cmd.Context()).Infof("Synced %d files to %s:%s", len(files), target.Host, remoteDir)
+
+			if cacheClear {
+				if err := devsync.RunCommand(cmd.Context(), target, cacheClearCommand); err != nil {
+					return fmt.Errorf("clear cache: %w", err)
+				}
+			}
+
+			return nil
+		}
+
+		snapshot, err := devsync.TakeSnapshot(extPath, patterns)
+		if err != nil {
+			return err
+		}
+
+		files := make([]string, 0, len(snapshot))
+		for file := range snapshot {
+			files = append(files, file)
+		}
+
+		if err := syncFiles(files); err != nil {
+			return err
+		}
+
+		if once {
+			return nil
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Watching %s for changes of %s", name, strings.Join(patterns, ", "))
+
+		return devsync.Watch(cmd.Context(), extPath, patterns, interval, snapshot, func(changed, removed []string) error {
+			for _, file := range removed {
+				logging.FromContext(cmd.Context()).Warnf("%s was deleted locally, remove it from the remote shop by hand", file)
+			}
+
+			return syncFiles(changed)
+		})
+	},
+}
+
+func init() {
+	extensionRootCmd.AddCommand(extensionSyncCmd)
+	extensionSyncCmd.Flags().String("to", "", "Remote shop as user@host:/path/to/shop, ssh://user@host:port/path/to/shop or sftp://user@host:port/path/to/shop")
+	extensionSyncCmd.Flags().StringSlice("pattern", devsync.DefaultPatterns, "File name patterns to sync")
+	extensionSyncCmd.Flags().Duration("interval", 500*time.Millisecond, "Interval to check for changed files")
+	extensionSyncCmd.Flags().Bool("once", false, "Sync once and exit without watching")
+	extensionSyncCmd.Flags().Bool("cache-clear", false, "Clear the cache of the remote shop over SSH after each sync")
+	extensionSyncCmd.Flags().String("cache-clear-command", "bin/console cache:clear", "Command to clear the cache, run in the shop folder")
+	_ = extensionSyncCmd.MarkFlagRequired("to")
+}
@@ -0,0 +1,74 @@
+package extension
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/internal/table"
+	"github.com/shopware/shopware-cli/internal/translation"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var extensionTranslateCmd = &cobra.Command{
+	Use:   "translate [path]",
+	Short: "Fill missing snippets and store texts with machine translations",
+	Long: `Translates the snippet keys missing in the target locales from en-GB and the store texts missing in German or English.
+DeepL requires DEEPL_API_KEY, OpenAI requires OPENAI_API_KEY. Existing translations are never changed.
+The translated entries are listed in ` + extension.MachineTranslationsFileName + ` for review, the file is not added to the zip.
+extension validate warns about every entry until it is removed from the file.`,
+	Example: "  shopware-cli extension translate --provider deepl --target de-DE,fr-FR ./FroshTools",
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		extPath := "."
+		if len(args) > 0 {
+			extPath = args[0]
+		}
+
+		ext, err := extension.GetExtensionByFolder(extPath)
+		if err != nil {
+			return fmt.Errorf("detect extension type: %w", err)
+		}
+
+		provider, _ := cmd.Flags().GetString("provider")
+		model, _ := cmd.Flags().GetString("model")
+		targets, _ := cmd.Flags().GetStringSlice("target")
+
+		translator, err := translation.NewTranslator(provider, model)
+		if err != nil {
+			return err
+		}
+
+		translations, err := extension.Translate(cmd.Context(), ext, extension.TranslateOptions{Targets: targets, Translator: translator, Provider: provider})
+		if err != nil {
+			return err
+		}
+
+		if len(translations) == 0 {
+			logging.FromContext(cmd.Context()).Infof("Nothing to translate")
+			return nil
+		}
+
+		w := table.NewWriter(os.Stdout)
+		w.Header([]string{"File", "Key", "Locale"})
+
+		for _, entry := range translations {
+			_ = w.Append([]string{entry.File, entry.Key, entry.Locale})
+		}
+
+		_ = w.Render()
+
+		logging.FromContext(cmd.Context()).Infof("Translated %d entries, review them and remove them from %s afterwards", len(translations), extension.MachineTranslationsFileName)
+
+		return nil
+	},
+}
+
+func init() {
+	extensionRootCmd.AddCommand(extensionTranslateCmd)
+	extensionTranslateCmd.Flags().String("provider", translation.ProviderDeepL, "Translation provider, deepl or openai")
+	extensionTranslateCmd.Flags().StringSlice("target", []string{"de-DE"}, "Locales to translate into like de-DE,fr-FR")
+	extensionTranslateCmd.Flags().String("model", "gpt-4o-mini", "Model used by the openai provider")
+}
@@ -0,0 +1,96 @@
+package extension
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/internal/color"
+	"github.com/shopware/shopware-cli/internal/coretemplate"
+	"github.com/shopware/shopware-cli/internal/verifier"
+)
+
+var extensionTwigDiffCmd = &cobra.Command{
+	Use:   "twig-diff [template] [path]",
+	Short: "Show the differences between an overridden template and the Shopware core template",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		extensionPath := "."
+		if len(args) > 1 {
+			extensionPath = args[1]
+		}
+
+		ext, err := extension.GetExtensionByFolder(extensionPath)
+		if err != nil {
+			return fmt.Errorf("cannot open extension: %w", err)
+		}
+
+		shopwareVersion, _ := cmd.Flags().GetString("shopware-version")
+
+		if shopwareVersion == "" {
+			toolCfg, err := verifier.ConvertExtensionToToolConfig(ext)
+			if err != nil {
+				return err
+			}
+
+			shopwareVersion = toolCfg.MaxShopwareVersion
+
+			if checkAgainst, _ := cmd.Flags().GetString("check-against"); checkAgainst == "lowest" {
+				shopwareVersion = toolCfg.MinShopwareVersion
+			}
+		}
+
+		template := coretemplate.TemplateName(args[0])
+
+		var overrideContent []byte
+
+		for _, sourceDir := range ext.GetSourceDirs() {
+			overrideContent, err = os.ReadFile(path.Join(sourceDir, "Resources", "views", template))
+			if err == nil {
+				break
+			}
+		}
+
+		if overrideContent == nil {
+			return fmt.Errorf("template %s is not overridden by the extension", template)
+		}
+
+		checkout, err := coretemplate.StorefrontCheckout(cmd.Context(), shopwareVersion)
+		if err != nil {
+			return err
+		}
+
+		coreContent, err := os.ReadFile(coretemplate.TemplatePath(checkout, template))
+		if err != nil {
+			return fmt.Errorf("template %s does not exist in Shopware %s", template, shopwareVersion)
+		}
+
+		out := cmd.OutOrStdout()
+
+		fmt.Fprintf(out, "--- @Storefront/%s (Shopware %s)\n", template, shopwareVersion)
+		fmt.Fprintf(out, "+++ %s\n", template)
+
+		for _, line := range coretemplate.Diff(string(coreContent), string(overrideContent)) {
+			switch line.Type {
+			case diffmatchpatch.DiffInsert:
+				fmt.Fprintln(out, color.GreenText.Render(line.String()))
+			case diffmatchpatch.DiffDelete:
+				fmt.Fprintln(out, color.RedText.Render(line.String()))
+			default:
+				fmt.Fprintln(out, line.String())
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	extensionRootCmd.AddCommand(extensionTwigDiffCmd)
+	extensionTwigDiffCmd.Flags().String("shopware-version", "", "Shopware version to compare against, defaults to the version range of the extension")
+	extensionTwigDiffCmd.Flags().String("check-against", "highest", "Pick the Shopware version from the extension constraint (highest, lowest)")
+}
@@ -0,0 +1,52 @@
+package extension
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtensionTwigDiff(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheDir)
+
+	// A cached checkout avoids cloning the storefront
+	checkout := filepath.Join(cacheDir, "shopware-cli", "core-templates", "storefront", "6.6.0.0")
+	extDir := t.TempDir()
+
+	files := map[string]string{
+		filepath.Join(checkout, "Resources", "views", "storefront", "base.html.twig"):      "{% block base %}\n<body>\n{% endblock %}\n",
+		filepath.Join(extDir, "composer.json"):                                             `{"name": "frosh/tools", "version": "1.0.0", "type": "shopware-platform-plugin", "require": {"shopware/core": "~6.6.0"}, "autoload": {"psr-4": {"Frosh\\Tools\\": "src/"}}, "extra": {"shopware-plugin-class": "Frosh\\Tools\\FroshTools"}}`,
+		filepath.Join(extDir, "src", "Resources", "views", "storefront", "base.html.twig"): "{% block base %}\n<body class=\"frosh\">\n{% endblock %}\n",
+	}
+
+	for file, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), os.ModePerm))
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o644))
+	}
+
+	require.NoError(t, extensionTwigDiffCmd.Flags().Set("shopware-version", "6.6"))
+	t.Cleanup(func() {
+		_ = extensionTwigDiffCmd.Flags().Set("shopware-version", "")
+	})
+
+	var out bytes.Buffer
+	extensionTwigDiffCmd.SetOut(&out)
+	extensionTwigDiffCmd.SetContext(t.Context())
+	t.Cleanup(func() {
+		extensionTwigDiffCmd.SetOut(nil)
+	})
+
+	require.NoError(t, extensionTwigDiffCmd.RunE(extensionTwigDiffCmd, []string{"@Storefront/storefront/base.html.twig", extDir}))
+
+	assert.Contains(t, out.String(), "--- @Storefront/storefront/base.html.twig (Shopware 6.6)\n")
+	assert.Contains(t, out.String(), "-<body>")
+	assert.Contains(t, out.String(), "+<body class=\"frosh\">")
+
+	err := extensionTwigDiffCmd.RunE(extensionTwigDiffCmd, []string{"storefront/page/content/index.html.twig", extDir})
+	assert.EqualError(t, err, "template storefront/page/content/index.html.twig is not overridden by the extension")
+}
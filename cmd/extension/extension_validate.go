@@ -1,129 +1,316 @@
 package extension
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"unicode/utf8"
 
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sergi/go-diff/diffmatchpatch"
 	"github.com/spf13/cobra"
-	"golang.org/x/sync/errgroup"
 
 	"github.com/shopware/shopware-cli/extension"
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+	"github.com/shopware/shopware-cli/internal/color"
+	"github.com/shopware/shopware-cli/internal/config"
+	"github.com/shopware/shopware-cli/internal/coretemplate"
+	"github.com/shopware/shopware-cli/internal/metrics"
 	"github.com/shopware/shopware-cli/internal/system"
 	"github.com/shopware/shopware-cli/internal/verifier"
 	"github.com/shopware/shopware-cli/logging"
 )
 
 var extensionValidateCmd = &cobra.Command{
-	Use:   "validate [path]",
+	Use:   "validate [path|url]",
 	Short: "Validate a Extension",
-	Args:  cobra.MinimumNArgs(1),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		isFull, _ := cmd.Flags().GetBool("full")
-		reportingFormat, _ := cmd.Flags().GetString("reporter")
-		checkAgainst, _ := cmd.Flags().GetString("check-against")
-		tmpDir, err := os.MkdirTemp(os.TempDir(), "analyse-extension-*")
-		only, _ := cmd.Flags().GetString("only")
-
-		// If the user does not want to run full validation, only run shopware-cli
-		if !isFull {
-			only = "sw-cli"
+	Long: `Validates an extension folder or zip. The zip can also be downloaded from an HTTP(S) URL,
+or with --store-binary <extension>:<version> from the Shopware Account, to validate exactly what customers receive.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if listRules, _ := cmd.Flags().GetBool("list-rules"); listRules {
+			return nil
 		}
 
-		if reportingFormat == "" {
-			reportingFormat = verifier.DetectDefaultReporter()
+		if storeBinary, _ := cmd.Flags().GetString("store-binary"); storeBinary != "" {
+			if all, _ := cmd.Flags().GetBool("all"); all {
+				return fmt.Errorf("--store-binary cannot be used together with --all")
+			}
+
+			return cobra.NoArgs(cmd, args)
 		}
 
-		if err != nil {
-			return fmt.Errorf("cannot create temporary directory: %w", err)
+		return workspaceArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if listRules, _ := cmd.Flags().GetBool("list-rules"); listRules {
+			return printRules(getReportingFormat(cmd))
 		}
 
-		path, err := filepath.Abs(args[0])
-		if err != nil {
-			return fmt.Errorf("cannot find path: %w", err)
+		all, _ := cmd.Flags().GetBool("all")
+		if !all {
+			source, cleanup, err := resolveValidateSource(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			defer cleanup()
+
+			return validateExtension(cmd, source, func(result *verifier.Check, rootDir string) error {
+				return verifier.DoCheckReport(cmd.Context(), result, getValidateReportingFormat(cmd), rootDir)
+			})
 		}
 
-		stat, err := os.Stat(path)
+		paths, err := workspaceExtensionPaths(cmd, args)
 		if err != nil {
-			return fmt.Errorf("cannot find path: %w", err)
+			return err
 		}
-		var toolCfg *verifier.ToolConfig
 
-		if stat.IsDir() {
-			if isFull {
-				if err := system.CopyFiles(args[0], tmpDir); err != nil {
-					return err
-				}
+		// The findings of all extensions are reported together, with paths relative to the workspace
+		combined := verifier.NewCheck()
 
-				defer func() {
-					if err := os.RemoveAll(tmpDir); err != nil {
-						logging.FromContext(cmd.Context()).Error("Failed to remove temporary directory:", err)
-					}
-				}()
-			} else {
-				tmpDir = args[0]
-			}
+		for _, path := range paths {
+			logging.FromContext(cmd.Context()).Infof("Validating %s", path)
 
-			ext, err := extension.GetExtensionByFolder(tmpDir)
+			relPath, err := filepath.Rel(workspace.Root, path)
 			if err != nil {
 				return err
 			}
 
-			toolCfg, err = verifier.ConvertExtensionToToolConfig(ext)
-			if err != nil {
-				return err
-			}
+			err = validateExtension(cmd, path, func(result *verifier.Check, _ string) error {
+				for _, finding := range result.Results {
+					if finding.Path != "" && !filepath.IsAbs(finding.Path) {
+						finding.Path = filepath.ToSlash(filepath.Join(relPath, finding.Path))
+					}
 
-			toolCfg.InputWasDirectory = true
-		} else {
-			ext, err := extension.GetExtensionByZip(args[0])
+					combined.AddResult(finding)
+				}
+
+				return nil
+			})
 			if err != nil {
-				return err
+				return fmt.Errorf("%s: %w", relPath, err)
 			}
+		}
 
-			toolCfg, err = verifier.ConvertExtensionToToolConfig(ext)
-			if err != nil {
+		return verifier.DoCheckReport(cmd.Context(), combined, getValidateReportingFormat(cmd), workspace.Root)
+	},
+}
+
+// getValidateReportingFormat returns the reporter of the flags, --store-rules defaults to the store report
+func getValidateReportingFormat(cmd *cobra.Command) string {
+	reportingFormat := getReportingFormat(cmd)
+
+	if storeRules, _ := cmd.Flags().GetBool("store-rules"); reportingFormat == "" && storeRules {
+		return "store"
+	}
+
+	if reportingFormat == "" {
+		return verifier.DetectDefaultReporter()
+	}
+
+	return reportingFormat
+}
+
+// validateExtension runs the configured checks for an extension folder or zip and passes the result to report
+func validateExtension(cmd *cobra.Command, arg string, report func(result *verifier.Check, rootDir string) error) error {
+	isFull, _ := cmd.Flags().GetBool("full")
+	checkAgainst, _ := cmd.Flags().GetString("check-against")
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "analyse-extension-*")
+	only, _ := cmd.Flags().GetString("only")
+	noCache, _ := cmd.Flags().GetBool("no-cache")
+	storeRules, _ := cmd.Flags().GetBool("store-rules")
+	fix, _ := cmd.Flags().GetBool("fix")
+	generateBaseline, _ := cmd.Flags().GetBool("generate-baseline")
+	checkAccount, _ := cmd.Flags().GetBool("check-account")
+	compileContainer, _ := cmd.Flags().GetBool("compile-container")
+	containerImage, _ := cmd.Flags().GetString("container-image")
+	allowExternalValidators, _ := cmd.Flags().GetBool("allow-external-validators")
+
+	// If the user does not want to run full validation, only run shopware-cli
+	if !isFull {
+		only = "sw-cli"
+	}
+
+	if err != nil {
+		return fmt.Errorf("cannot create temporary directory: %w", err)
+	}
+
+	path, err := filepath.Abs(arg)
+	if err != nil {
+		return fmt.Errorf("cannot find path: %w", err)
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("cannot find path: %w", err)
+	}
+	var toolCfg *verifier.ToolConfig
+
+	if fix && !stat.IsDir() {
+		return fmt.Errorf("--fix can only be used with an extension folder")
+	}
+
+	if generateBaseline && !stat.IsDir() {
+		return fmt.Errorf("--generate-baseline can only be used with an extension folder")
+	}
+
+	if fix {
+		ext, err := extension.GetExtensionByFolder(path)
+		if err != nil {
+			return err
+		}
+
+		results, err := extension.RunFixers(cmd.Context(), ext, extension.GetFixers())
+		if err != nil {
+			return err
+		}
+
+		printFixSummary(results, path)
+	}
+
+	if stat.IsDir() {
+		if isFull {
+			if err := system.CopyFiles(arg, tmpDir); err != nil {
 				return err
 			}
+
+			defer func() {
+				if err := os.RemoveAll(tmpDir); err != nil {
+					logging.FromContext(cmd.Context()).Error("Failed to remove temporary directory:", err)
+				}
+			}()
+		} else {
+			tmpDir = arg
 		}
 
-		toolCfg.CheckAgainst = checkAgainst
-		result := verifier.NewCheck()
+		ext, err := extension.GetExtensionByFolder(tmpDir)
+		if err != nil {
+			return err
+		}
 
-		var gr errgroup.Group
+		toolCfg, err = verifier.ConvertExtensionToToolConfig(ext)
+		if err != nil {
+			return err
+		}
 
-		tools := verifier.GetTools()
+		toolCfg.InputWasDirectory = true
 
-		tools, err = tools.Only(only)
+		// The full validation runs on a copy, the baseline belongs to the original folder
+		if relBaseline, err := filepath.Rel(toolCfg.RootDir, toolCfg.BaselineFile); err == nil {
+			toolCfg.BaselineFile = filepath.Join(path, relBaseline)
+		}
+	} else {
+		ext, err := extension.GetExtensionByZip(arg)
 		if err != nil {
 			return err
 		}
 
-		for _, tool := range tools {
-			tool := tool
-			gr.Go(func() error {
-				return tool.Check(cmd.Context(), result, *toolCfg)
-			})
+		toolCfg, err = verifier.ConvertExtensionToToolConfig(ext)
+		if err != nil {
+			return err
 		}
+	}
+
+	// Ignores need a reason like the findings of the baseline
+	if err := verifier.ValidateIgnoreReasons(toolCfg.ValidationIgnores); err != nil {
+		return fmt.Errorf("validation.ignore of the extension config: %w", err)
+	}
+
+	toolCfg.CheckAgainst = checkAgainst
+	// The commands of validation.external come with the extension, zips and downloads are not trusted to run them
+	toolCfg.RunExternalValidators = stat.IsDir() || allowExternalValidators
+
+	tools := verifier.GetTools()
+
+	tools, err = tools.Only(only)
+	if err != nil {
+		return err
+	}
+
+	if storeRules {
+		tools = append(tools, verifier.StoreRules{})
+	}
+
+	// The full validation runs PHPStan already, otherwise use the pinned phar when the extension enabled it
+	if !isFull && verifier.IsPHPStanPharEnabled(toolCfg.Extension) {
+		tools = append(tools, verifier.PhpStanPhar{})
+	}
+
+	if compileContainer {
+		tools = append(tools, verifier.ContainerCompile{Image: containerImage})
+	}
 
-		if err := gr.Wait(); err != nil {
+	var cache *verifier.ResultCache
+
+	if !noCache {
+		cache, err = verifier.NewResultCache(filepath.Join(system.GetShopwareCliCacheDir(), "validation"), cmd.Root().Version, *toolCfg)
+		if err != nil {
 			return err
 		}
+	}
 
-		return verifier.DoCheckReport(result.RemoveByIdentifier(toolCfg.ValidationIgnores), reportingFormat)
-	},
+	result, err := tools.Run(cmd.Context(), *toolCfg, cache)
+	if err != nil {
+		return err
+	}
+
+	if checkAccount {
+		if err := checkVersionInAccount(cmd.Context(), toolCfg.Extension, result); err != nil {
+			return err
+		}
+	}
+
+	result = result.RemoveByIdentifier(toolCfg.ValidationIgnores).ApplyRuleSeverities(toolCfg.RuleSeverities)
+
+	baseline, err := verifier.ReadBaseline(toolCfg.BaselineFile)
+	if err != nil {
+		return err
+	}
+
+	if generateBaseline {
+		if err := verifier.NewBaseline(result, baseline).Write(toolCfg.BaselineFile); err != nil {
+			return fmt.Errorf("cannot write baseline: %w", err)
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Wrote %d findings to %s, add a reason to the new ones", len(result.Results), toolCfg.BaselineFile)
+
+		return nil
+	}
+
+	if err := baseline.Validate(); err != nil {
+		return fmt.Errorf("%s: %w", toolCfg.BaselineFile, err)
+	}
+
+	result = result.RemoveBaselined(baseline)
+
+	emitValidationMetrics(cmd.Context(), toolCfg.Extension, result)
+
+	return report(result, toolCfg.RootDir)
 }
 
 func init() {
 	extensionRootCmd.AddCommand(extensionValidateCmd)
 	extensionValidateCmd.PersistentFlags().Bool("full", false, "Run full validation including PHPStan, ESLint and Stylelint")
-	extensionValidateCmd.PersistentFlags().String("reporter", "", "Reporting format (summary, json, github, junit, markdown)")
+	extensionValidateCmd.PersistentFlags().String("reporter", "", "Reporting format (summary, json, sarif, github, junit, markdown, store, pr-comment)")
+	extensionValidateCmd.PersistentFlags().String("format", "", "Output format, same as --reporter (summary, json, sarif, github, junit, markdown, store, pr-comment)")
 	extensionValidateCmd.PersistentFlags().String("check-against", "highest", "Check against Shopware Version (highest, lowest)")
 	extensionValidateCmd.PersistentFlags().String("only", "", "Run only specific tools by name (comma-separated, e.g. phpstan,eslint)")
+	extensionValidateCmd.PersistentFlags().Bool("no-cache", false, "Do not use cached results of a previous run")
+	extensionValidateCmd.PersistentFlags().Bool("fix", false, "Apply automatic fixes like composer.json normalization, psr-4 separators, license identifiers, icon resizing, changelog formatting and snippet sorting before validating")
+	extensionValidateCmd.PersistentFlags().Bool("list-rules", false, "List all rules with their default severity, category and description")
+	extensionValidateCmd.PersistentFlags().Bool("generate-baseline", false, "Write all current findings to the baseline file, so only new findings are reported")
+	extensionValidateCmd.PersistentFlags().Bool("store-rules", false, "Run the checks of the automatic code review of the Shopware Store and report them grouped by sub check")
+	extensionValidateCmd.PersistentFlags().Bool("check-account", false, "Check with the Shopware Account credentials that the current version is not uploaded yet")
+	extensionValidateCmd.PersistentFlags().Bool("compile-container", false, "Compile the DI container of the plugin with a minimal Shopware kernel in a Docker container")
+	extensionValidateCmd.PersistentFlags().String("store-binary", "", "Download and validate an uploaded version from the Shopware Account, as <extension>:<version>")
+	extensionValidateCmd.PersistentFlags().Bool("allow-external-validators", false, "Run the validation.external commands of the .shopware-extension.yml also for zips, they run always for extension folders")
+	extensionValidateCmd.PersistentFlags().String("container-image", verifier.DefaultContainerCompileImage, "Docker image with PHP used by --compile-container")
 	extensionValidateCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
-		reporter, _ := cmd.Flags().GetString("reporter")
-		if reporter != "summary" && reporter != "json" && reporter != "github" && reporter != "junit" && reporter != "markdown" && reporter != "" {
-			return fmt.Errorf("invalid reporter format: %s. Must be either 'summary', 'json', 'github', 'junit' or 'markdown'", reporter)
+		reporter := getReportingFormat(cmd)
+		if reporter != "summary" && reporter != "json" && reporter != "sarif" && reporter != "github" && reporter != "junit" && reporter != "markdown" && reporter != "store" && reporter != "pr-comment" && reporter != "" {
+			return fmt.Errorf("invalid reporter format: %s. Must be either 'summary', 'json', 'sarif', 'github', 'junit', 'markdown', 'store' or 'pr-comment'", reporter)
 		}
 
 		mode, _ := cmd.Flags().GetString("check-against")
@@ -139,4 +326,160 @@ func init() {
 
 		return verifier.SetupTools(cmd.Context(), cmd.Root().Version)
 	}
+	addWorkspaceFlags(extensionValidateCmd)
+}
+
+// checkVersionInAccount reports when the current version exists already as binary in the Shopware Account
+func checkVersionInAccount(ctx context.Context, ext extension.Extension, check *verifier.Check) error {
+	if ext == nil {
+		return nil
+	}
+
+	name, err := ext.GetName()
+	if err != nil {
+		return err
+	}
+
+	extVersion, err := ext.GetVersion()
+	if err != nil {
+		return err
+	}
+
+	client, err := account_api.NewApi(ctx, config.Config{})
+	if err != nil {
+		return fmt.Errorf("login to the Shopware Account: %w", err)
+	}
+
+	producer, err := client.Producer(ctx)
+	if err != nil {
+		return err
+	}
+
+	accountExt, err := producer.GetExtensionByName(ctx, name)
+	if err != nil {
+		logging.FromContext(ctx).Warnf("Cannot check the uploaded versions: %v", err)
+		return nil
+	}
+
+	binaries, err := producer.GetExtensionBinaries(ctx, accountExt.Id)
+	if err != nil {
+		return err
+	}
+
+	for _, binary := range binaries {
+		if binary.Version == extVersion.String() {
+			check.AddResult(verifier.CheckResult{
+				Message:    fmt.Sprintf("Version %s of %s is already uploaded to the Shopware Account (status %s), increase the version", extVersion.String(), name, binary.Status.Name),
+				Severity:   verifier.CheckSeverityError,
+				Identifier: "changelog.version_uploaded",
+			})
+		}
+	}
+
+	return nil
+}
+
+// emitValidationMetrics sends the amount of findings by severity to the configured metric sinks
+func emitValidationMetrics(ctx context.Context, ext extension.Extension, result *verifier.Check) {
+	if ext == nil {
+		return
+	}
+
+	name, _ := ext.GetName()
+
+	extVersion := ""
+	if v, err := ext.GetVersion(); err == nil {
+		extVersion = v.String()
+	}
+
+	counts := map[string]int{verifier.CheckSeverityError: 0, verifier.CheckSeverityWarn: 0}
+
+	for _, r := range result.Results {
+		if _, ok := counts[r.Severity]; ok {
+			counts[r.Severity]++
+		}
+	}
+
+	for severity, count := range counts {
+		metrics.Emit(ctx, metrics.ValidationFindings, float64(count), metrics.Labels{"extension": name, "version": extVersion, "severity": severity})
+	}
+}
+
+// getReportingFormat returns the value of --format, which takes precedence over --reporter
+func getReportingFormat(cmd *cobra.Command) string {
+	if format, _ := cmd.Flags().GetString("format"); format != "" {
+		return format
+	}
+
+	reporter, _ := cmd.Flags().GetString("reporter")
+
+	return reporter
+}
+
+var ruleDescriptionStyle = lipgloss.NewStyle().Width(82).PaddingLeft(6)
+
+// printRules prints the documentation of all rules grouped by category, or as JSON with the json reporter
+func printRules(reportingFormat string) error {
+	rules, err := verifier.GetRules()
+	if err != nil {
+		return err
+	}
+
+	if reportingFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(rules)
+	}
+
+	category := ""
+
+	for _, rule := range rules {
+		if rule.Category != category {
+			category = rule.Category
+			fmt.Printf("\n%s\n", color.BoldText.Render(category))
+		}
+
+		severity := color.YellowText.Render(rule.Severity)
+		if rule.Severity == verifier.CheckSeverityError {
+			severity = color.RedText.Render(rule.Severity)
+		}
+
+		fmt.Printf("  %s  %s\n", rule.ID, severity)
+		fmt.Println(ruleDescriptionStyle.Render(rule.Description))
+	}
+
+	return nil
+}
+
+// printFixSummary prints the applied fixes with the changed lines to stderr, so the report on stdout stays parsable
+func printFixSummary(results []extension.FixResult, rootDir string) {
+	if len(results) == 0 {
+		fmt.Fprintln(os.Stderr, "No fixes applied")
+		return
+	}
+
+	for _, result := range results {
+		relPath, err := filepath.Rel(rootDir, result.Change.Path)
+		if err != nil {
+			relPath = result.Change.Path
+		}
+
+		fmt.Fprintf(os.Stderr, "%s: %s (%s)\n", result.Fixer, color.BoldText.Render(relPath), result.Change.Description)
+
+		if !utf8.Valid(result.Change.Before) || !utf8.Valid(result.Change.After) {
+			continue
+		}
+
+		for _, line := range coretemplate.Diff(string(result.Change.Before), string(result.Change.After)) {
+			switch line.Type {
+			case diffmatchpatch.DiffInsert:
+				fmt.Fprintln(os.Stderr, color.GreenText.Render(line.String()))
+			case diffmatchpatch.DiffDelete:
+				fmt.Fprintln(os.Stderr, color.RedText.Render(line.String()))
+			}
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Applied %d fixes\n", len(results))
 }
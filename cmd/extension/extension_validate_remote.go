@@ -0,0 +1,127 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+	"github.com/shopware/shopware-cli/internal/config"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// resolveValidateSource returns the local path to validate, URLs and --store-binary are downloaded into a temporary zip first
+func resolveValidateSource(cmd *cobra.Command, args []string) (string, func(), error) {
+	storeBinary, _ := cmd.Flags().GetString("store-binary")
+
+	if storeBinary == "" && !isRemoteZip(args[0]) {
+		return args[0], func() {}, nil
+	}
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "validate-remote-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot create temporary directory: %w", err)
+	}
+
+	cleanup := func() {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			logging.FromContext(cmd.Context()).Error("Failed to remove temporary directory:", err)
+		}
+	}
+
+	zipPath := filepath.Join(tmpDir, "extension.zip")
+
+	if storeBinary != "" {
+		err = downloadStoreBinary(cmd.Context(), storeBinary, zipPath)
+	} else {
+		logging.FromContext(cmd.Context()).Infof("Downloading %s", args[0])
+		err = downloadValidateZip(cmd.Context(), args[0], zipPath)
+	}
+
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	return zipPath, cleanup, nil
+}
+
+func isRemoteZip(arg string) bool {
+	return strings.HasPrefix(arg, "http://") || strings.HasPrefix(arg, "https://")
+}
+
+// downloadStoreBinary downloads the uploaded zip of <extension>:<version> from the Shopware Account
+func downloadStoreBinary(ctx context.Context, storeBinary, target string) error {
+	name, version, found := strings.Cut(storeBinary, ":")
+	if !found || name == "" || version == "" {
+		return fmt.Errorf("--store-binary must be <extension>:<version>, got %s", storeBinary)
+	}
+
+	client, err := account_api.NewApi(ctx, config.Config{})
+	if err != nil {
+		return fmt.Errorf("login to the Shopware Account: %w", err)
+	}
+
+	producer, err := client.Producer(ctx)
+	if err != nil {
+		return err
+	}
+
+	accountExt, err := producer.GetExtensionByName(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	binaries, err := producer.GetExtensionBinaries(ctx, accountExt.Id)
+	if err != nil {
+		return err
+	}
+
+	for _, binary := range binaries {
+		if binary.Version != version {
+			continue
+		}
+
+		logging.FromContext(ctx).Infof("Downloading version %s of %s from the Shopware Account", version, name)
+
+		return producer.DownloadExtensionBinaryFile(ctx, accountExt.Id, binary.Id, target)
+	}
+
+	return fmt.Errorf("version %s of %s is not uploaded to the Shopware Account", version, name)
+}
+
+func downloadValidateZip(ctx context.Context, url, target string) error {
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		return fmt.Errorf("download %s: %w", url, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("got status code %d from %s", resp.StatusCode, url)
+	}
+
+	file, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
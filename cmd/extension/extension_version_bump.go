@@ -0,0 +1,87 @@
+package extension
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/internal/git"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var extensionVersionRootCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Manage the version of an extension",
+}
+
+var extensionVersionBumpCmd = &cobra.Command{
+	Use:   "bump [patch|minor|major|version] [path]",
+	Short: "Increases the version of the extension and adds a changelog entry for it",
+	Long: `Writes the next version into the composer.json or the manifest.xml of an app and adds an empty entry
+for it to all changelog files. Only the new version is printed to stdout, so it can be used in CI.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		extPath := "."
+		if len(args) == 2 {
+			extPath = args[1]
+		}
+
+		extPath, err := filepath.Abs(extPath)
+		if err != nil {
+			return fmt.Errorf("cannot find path: %w", err)
+		}
+
+		ext, err := extension.GetExtensionByFolder(extPath)
+		if err != nil {
+			return fmt.Errorf("cannot open extension: %w", err)
+		}
+
+		current, err := ext.GetVersion()
+		if err != nil {
+			return fmt.Errorf("cannot read the current version: %w", err)
+		}
+
+		next, err := extension.NextVersion(current, args[0])
+		if err != nil {
+			return err
+		}
+
+		versionFile, err := extension.SetExtensionVersion(ext, next.String())
+		if err != nil {
+			return fmt.Errorf("cannot write version: %w", err)
+		}
+
+		changelogFiles, err := extension.AddChangelogVersion(extPath, next.String())
+		if err != nil {
+			return fmt.Errorf("cannot add changelog entry: %w", err)
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Bumped version from %s to %s", current, next)
+
+		if len(changelogFiles) > 0 {
+			logging.FromContext(cmd.Context()).Infof("Added a changelog entry for %s, describe the changes before releasing", next)
+		}
+
+		if tag, _ := cmd.Flags().GetBool("git-tag"); tag {
+			files := append([]string{versionFile}, changelogFiles...)
+
+			if err := git.CommitAndTag(cmd.Context(), extPath, files, fmt.Sprintf("Release %s", next), next.String()); err != nil {
+				return fmt.Errorf("cannot create git tag: %w", err)
+			}
+
+			logging.FromContext(cmd.Context()).Infof("Committed the changes and created the tag %s", next)
+		}
+
+		fmt.Println(next.String())
+
+		return nil
+	},
+}
+
+func init() {
+	extensionRootCmd.AddCommand(extensionVersionRootCmd)
+	extensionVersionRootCmd.AddCommand(extensionVersionBumpCmd)
+	extensionVersionBumpCmd.Flags().Bool("git-tag", false, "Commit the changed files and tag the commit with the new version")
+}
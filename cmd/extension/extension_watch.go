@@ -0,0 +1,172 @@
+package extension
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/internal/phpexec"
+	"github.com/shopware/shopware-cli/logging"
+	"github.com/shopware/shopware-cli/shop"
+)
+
+var extensionWatchCmd = &cobra.Command{
+	Use:   "watch [path]",
+	Short: "Watch the Administration and Storefront assets of an extension with hot reload against a local shop",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		extPath, err := filepath.Abs(args[0])
+		if err != nil {
+			return err
+		}
+
+		ext, err := extension.GetExtensionByFolder(extPath)
+		if err != nil {
+			return fmt.Errorf("detect extension type: %w", err)
+		}
+
+		name, err := ext.GetName()
+		if err != nil {
+			return fmt.Errorf("get name: %w", err)
+		}
+
+		projectRoot, _ := cmd.Flags().GetString("project-root")
+		shopURL, _ := cmd.Flags().GetString("shop-url")
+
+		projectRoot, shopURL, err = resolveWatchShop(projectRoot, shopURL)
+		if err != nil {
+			return err
+		}
+
+		sources := extension.ConvertExtensionsToSources(cmd.Context(), []extension.Extension{ext})
+		cfgs := extension.BuildAssetConfigFromExtensions(cmd.Context(), sources, extension.AssetBuildConfig{})
+
+		watchAdmin := cfgs.RequiresAdminBuild()
+		watchStorefront := cfgs.RequiresStorefrontBuild()
+
+		if watchStorefront && projectRoot == "" {
+			logging.FromContext(cmd.Context()).Warnf("Skipping the Storefront of %s, the Storefront watcher requires --project-root", name)
+			watchStorefront = false
+		}
+
+		if !watchAdmin && !watchStorefront {
+			return fmt.Errorf("found nothing to watch in %s", name)
+		}
+
+		// Both watchers run until they are interrupted, the first failing one stops the command
+		errs := make(chan error, 2)
+
+		if watchAdmin {
+			go func() {
+				errs <- runAdminWatch(cmd, sources, shopURL)
+			}()
+		}
+
+		if watchStorefront {
+			go func() {
+				errs <- runStorefrontWatch(cmd.Context(), projectRoot, name)
+			}()
+		}
+
+		return <-errs
+	},
+}
+
+// resolveWatchShop returns the project root and the URL of the shop to watch against. The project root defaults to
+// SHOPWARE_PROJECT_ROOT and the shop URL to the APP_URL of the project.
+func resolveWatchShop(projectRoot, shopURL string) (string, string, error) {
+	if projectRoot == "" {
+		projectRoot = os.Getenv("SHOPWARE_PROJECT_ROOT")
+	}
+
+	if projectRoot != "" {
+		if err := extension.LoadSymfonyEnvFile(projectRoot); err != nil {
+			return "", "", err
+		}
+
+		if shopURL == "" {
+			shopURL = os.Getenv("APP_URL")
+		}
+	}
+
+	if shopURL == "" {
+		return "", "", fmt.Errorf("cannot detect the shop, pass --shop-url or --project-root")
+	}
+
+	return projectRoot, shopURL, nil
+}
+
+// runStorefrontWatch starts the hot proxy of the Storefront with only this extension in the plugins.json of the project
+func runStorefrontWatch(ctx context.Context, projectRoot, name string) error {
+	shopCfg, err := shop.ReadConfig(filepath.Join(projectRoot, shop.DefaultConfigFileName()), true)
+	if err != nil {
+		return err
+	}
+
+	sources, err := extension.DumpAndLoadAssetSourcesOfProject(ctx, projectRoot, shopCfg)
+	if err != nil {
+		return err
+	}
+
+	cfgs := extension.BuildAssetConfigFromExtensions(ctx, sources, extension.AssetBuildConfig{}).Only([]string{name})
+
+	if !cfgs.Has(name) {
+		return fmt.Errorf("the extension %s is not installed in %s, install it to watch the Storefront", name, projectRoot)
+	}
+
+	if _, err := extension.InstallNodeModulesOfConfigs(ctx, cfgs, false); err != nil {
+		return err
+	}
+
+	pluginJson, err := json.MarshalIndent(cfgs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(projectRoot, "var", "plugins.json"), pluginJson, os.ModePerm); err != nil {
+		return err
+	}
+
+	// The hot proxy reads the theme variables and files of the active theme
+	themeDump := phpexec.ConsoleCommand(ctx, "theme:dump")
+	themeDump.Dir = projectRoot
+	themeDump.Stdout = os.Stdout
+	themeDump.Stderr = os.Stderr
+
+	if err := themeDump.Run(); err != nil {
+		return fmt.Errorf("theme:dump: %w", err)
+	}
+
+	storefrontRoot := extension.PlatformPath(projectRoot, "Storefront", "")
+	storefrontApp := extension.PlatformPath(projectRoot, "Storefront", "Resources/app/storefront")
+
+	if _, err := os.Stat(filepath.Join(storefrontApp, "node_modules", "webpack-dev-server")); os.IsNotExist(err) {
+		if err := extension.InstallNPMDependencies(storefrontApp, extension.NpmPackage{Dependencies: map[string]string{"not-empty": "not-empty"}}); err != nil {
+			return err
+		}
+	}
+
+	hotProxy := exec.CommandContext(ctx, "npm", "run-script", "hot-proxy")
+	hotProxy.Dir = storefrontApp
+	hotProxy.Stdout = os.Stdout
+	hotProxy.Stderr = os.Stderr
+	hotProxy.Env = append(os.Environ(), "PROJECT_ROOT="+projectRoot, "STOREFRONT_ROOT="+storefrontRoot)
+
+	logging.FromContext(ctx).Infof("Starting the Storefront hot proxy for %s", name)
+
+	return hotProxy.Run()
+}
+
+func init() {
+	extensionRootCmd.AddCommand(extensionWatchCmd)
+	extensionWatchCmd.Flags().String("shop-url", "", "URL of the local shop, defaults to the APP_URL of the project")
+	extensionWatchCmd.Flags().String("project-root", "", "Shopware project with the extension installed, required for the Storefront. Defaults to SHOPWARE_PROJECT_ROOT")
+	extensionWatchCmd.Flags().StringVar(&adminWatchListen, "listen", ":8080", "Listen (default :8080)")
+	extensionWatchCmd.Flags().StringVar(&adminWatchURL, "external-url", "", "External reachable url for admin watcher. Needed for reverse proxy setups")
+}
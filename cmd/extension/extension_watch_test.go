@@ -0,0 +1,54 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveWatchShop(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	t.Setenv("APP_URL", "")
+	t.Setenv("SHOPWARE_PROJECT_ROOT", "")
+
+	projectRoot, shopURL, err := resolveWatchShop("", "http://shop.test")
+	require.NoError(t, err)
+	assert.Empty(t, projectRoot)
+	assert.Equal(t, "http://shop.test", shopURL)
+
+	_, _, err = resolveWatchShop("", "")
+	assert.EqualError(t, err, "cannot detect the shop, pass --shop-url or --project-root")
+}
+
+func TestResolveWatchShopUsesAppURLOfProject(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	t.Setenv("APP_URL", "")
+
+	project := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(project, ".env"), []byte("APP_URL=http://shop.test\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(project, ".env.local"), []byte("APP_URL=http://local.shop.test\n"), 0o644))
+
+	t.Setenv("SHOPWARE_PROJECT_ROOT", project)
+
+	projectRoot, shopURL, err := resolveWatchShop("", "")
+	require.NoError(t, err)
+	assert.Equal(t, project, projectRoot)
+	assert.Equal(t, "http://local.shop.test", shopURL)
+}
+
+func TestResolveWatchShopPrefersFlags(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	t.Setenv("APP_URL", "")
+	t.Setenv("SHOPWARE_PROJECT_ROOT", t.TempDir())
+
+	project := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(project, ".env"), []byte("APP_URL=http://shop.test\n"), 0o644))
+
+	projectRoot, shopURL, err := resolveWatchShop(project, "http://other.test")
+	require.NoError(t, err)
+	assert.Equal(t, project, projectRoot)
+	assert.Equal(t, "http://other.test", shopURL)
+}
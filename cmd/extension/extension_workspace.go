@@ -0,0 +1,88 @@
+package extension
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/internal/assetcache"
+	"github.com/shopware/shopware-cli/internal/system"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// workspace is set while a command runs with --all, the extensions share its caches
+var workspace *extension.Workspace
+
+// addWorkspaceFlags adds --all and --changed-since, which run the command for the extensions of the .shopware-workspace.yml
+func addWorkspaceFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("all", false, "Run for all extensions listed in the .shopware-workspace.yml of the given folder, the current folder or their parents")
+	cmd.Flags().String("changed-since", "", "Together with --all only use the extensions with changes since the merge base of this Git ref, e.g. origin/main")
+}
+
+// workspaceArgs accepts the extension paths as usual, with --all only an optional folder to look for the workspace config
+func workspaceArgs(minArgs int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			return cobra.MaximumNArgs(1)(cmd, args)
+		}
+
+		if cmd.Flags().Changed("changed-since") {
+			return fmt.Errorf("--changed-since can only be used together with --all")
+		}
+
+		return cobra.MinimumNArgs(minArgs)(cmd, args)
+	}
+}
+
+// workspaceExtensionPaths returns the extension folders of the workspace, an empty list when --changed-since matches nothing
+func workspaceExtensionPaths(cmd *cobra.Command, args []string) ([]string, error) {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	ws, err := extension.FindWorkspace(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := ws.ExtensionPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	if ref, _ := cmd.Flags().GetString("changed-since"); ref != "" {
+		paths, err = extension.ChangedExtensionPaths(cmd.Context(), ws.Root, ref, paths)
+		if err != nil {
+			return nil, fmt.Errorf("detect changed extensions: %w", err)
+		}
+
+		logging.FromContext(cmd.Context()).Infof("%d extensions changed since %s", len(paths), ref)
+	}
+
+	workspace = ws
+
+	return paths, nil
+}
+
+// newAssetCache uses the cache folder of the workspace, unless SHOPWARE_CLI_ASSET_CACHE_DIR is set
+func newAssetCache() *assetcache.Cache {
+	cache := assetcache.NewFromEnv()
+
+	if workspace != nil && workspace.CacheDirectory() != "" && os.Getenv("SHOPWARE_CLI_ASSET_CACHE_DIR") == "" {
+		return cache.WithDir(filepath.Join(workspace.CacheDirectory(), "assets"))
+	}
+
+	return cache
+}
+
+func vendorLayerCacheDir() string {
+	if workspace != nil && workspace.CacheDirectory() != "" {
+		return filepath.Join(workspace.CacheDirectory(), "zip-vendor-layers")
+	}
+
+	return filepath.Join(system.GetShopwareCliCacheDir(), "zip-vendor-layers")
+}
@@ -1,29 +1,55 @@
 package extension
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"path"
 	"path/filepath"
+	"strings"
+	"time"
 
 	cp "github.com/otiai10/copy"
 	"github.com/spf13/cobra"
 
 	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/internal/artifactstore"
+	"github.com/shopware/shopware-cli/internal/metrics"
+	"github.com/shopware/shopware-cli/internal/signing"
 	"github.com/shopware/shopware-cli/logging"
 )
 
 var (
 	disableGit           = false
 	extensionReleaseMode = false
+	vendorCache          = false
 )
 
 var extensionZipCmd = &cobra.Command{
 	Use:   "zip [path] [branch]",
 	Short: "Zip a Extension",
-	Args:  cobra.MinimumNArgs(1),
+	Args:  workspaceArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			if cmd.Flags().Changed("filename") {
+				return fmt.Errorf("--filename cannot be used with --all, as every extension gets its own zip")
+			}
+
+			paths, err := workspaceExtensionPaths(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			for _, extPath := range paths {
+				if err := zipExtensionFolder(cmd, extPath, ""); err != nil {
+					return fmt.Errorf("%s: %w", filepath.Base(extPath), err)
+				}
+			}
+
+			return nil
+		}
+
 		extPath, err := filepath.Abs(args[0])
 		if err != nil {
 			return err
@@ -34,233 +60,520 @@ var extensionZipCmd = &cobra.Command{
 			branch = args[1]
 		}
 
-		ext, err := extension.GetExtensionByFolder(extPath)
+		return zipExtensionFolder(cmd, extPath, branch)
+	},
+}
+
+// zipExtensionFolder removes previous zips of the extension and packs it once or for each target of the matrix
+func zipExtensionFolder(cmd *cobra.Command, extPath, branch string) error {
+	ext, err := extension.GetExtensionByFolder(extPath)
+	if err != nil {
+		return fmt.Errorf("detect extension type: %w", err)
+	}
+
+	name, err := ext.GetName()
+	if err != nil {
+		return fmt.Errorf("get name: %w", err)
+	}
+
+	// Clear previous zips
+	existingFiles, err := filepath.Glob(fmt.Sprintf("%s-*.zip", name))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range existingFiles {
+		err = os.Remove(file)
 		if err != nil {
-			return fmt.Errorf("detect extension type: %w", err)
+			return fmt.Errorf("remove existing file: %w", err)
 		}
+	}
+
+	if matrix, _ := cmd.Flags().GetBool("matrix"); !matrix && !ext.GetExtensionConfig().Build.Zip.Matrix {
+		_, err := zipExtension(cmd, ext, extPath, branch, nil)
+		return err
+	}
+
+	constraint, err := ext.GetShopwareVersionConstraint()
+	if err != nil {
+		return fmt.Errorf("get shopware version constraint: %w", err)
+	}
+
+	targets, err := extension.GetShopwareMatrixTargets(cmd.Context(), constraint)
+	if err != nil {
+		return fmt.Errorf("build matrix: %w", err)
+	}
 
-		extCfg := ext.GetExtensionConfig()
+	for _, target := range targets {
+		logging.FromContext(cmd.Context()).Infof("Building %s for Shopware %s", name, target.Version)
 
-		name, err := ext.GetName()
+		if _, err := zipExtension(cmd, ext, extPath, branch, &target); err != nil {
+			return fmt.Errorf("shopware %s: %w", target.Major, err)
+		}
+	}
+
+	return nil
+}
+
+// zipExtension packs the extension into a zip and returns its path, with a matrix target the assets are built for its
+// Shopware version and the file name gets the major version as suffix
+func zipExtension(cmd *cobra.Command, ext extension.Extension, extPath, branch string, target *extension.MatrixTarget) (string, error) {
+	start := time.Now()
+
+	extCfg := ext.GetExtensionConfig()
+
+	name, err := ext.GetName()
+	if err != nil {
+		return "", fmt.Errorf("get name: %w", err)
+	}
+
+	// Create temp dir
+	tempDir, err := os.MkdirTemp("", "extension")
+	if err != nil {
+		return "", fmt.Errorf("create temp directory: %w", err)
+	}
+
+	extName, err := ext.GetName()
+	if err != nil {
+		return "", fmt.Errorf("get extension name: %w", err)
+	}
+
+	extDir := fmt.Sprintf("%s/%s/", tempDir, extName)
+
+	err = os.Mkdir(extDir, os.ModePerm)
+	if err != nil {
+		return "", fmt.Errorf("create temp directory: %w", err)
+	}
+
+	tempDir += "/"
+
+	defer func(path string) {
+		_ = os.RemoveAll(path)
+	}(tempDir)
+
+	var tag string
+
+	// Extract files using strategy
+	if disableGit {
+		err = cp.Copy(extPath, extDir, copyOptions(extPath, extCfg.Build.Zip.Pack.Excludes.Paths))
 		if err != nil {
-			return fmt.Errorf("get name: %w", err)
+			return "", fmt.Errorf("copy files: %w", err)
 		}
+	} else {
+		gitCommit, _ := cmd.Flags().GetString("git-commit")
 
-		// Clear previous zips
-		existingFiles, err := filepath.Glob(fmt.Sprintf("%s-*.zip", name))
+		tag, err = extension.GitCopyFolder(extPath, extDir, gitCommit)
 		if err != nil {
-			return err
+			return "", fmt.Errorf("copy via git: %w", err)
 		}
 
-		for _, file := range existingFiles {
-			err = os.Remove(file)
-			if err != nil {
-				return fmt.Errorf("remove existing file: %w", err)
-			}
+		logging.FromContext(cmd.Context()).Infof("Checking out %s using Git", tag)
+	}
+
+	// User input wins
+	if len(branch) > 0 {
+		tag = branch
+	}
+
+	if extCfg.Build.Zip.Composer.Enabled {
+		if err := executeHooks(ext, extCfg.Build.Zip.Composer.BeforeHooks, extDir); err != nil {
+			return "", fmt.Errorf("before hooks composer: %w", err)
 		}
 
-		// Create temp dir
-		tempDir, err := os.MkdirTemp("", "extension")
-		if err != nil {
-			return fmt.Errorf("create temp directory: %w", err)
+		if err := extension.PrepareFolderForZipping(cmd.Context(), extDir, ext, extCfg); err != nil {
+			return "", fmt.Errorf("prepare package: %w", err)
 		}
 
-		extName, err := ext.GetName()
-		if err != nil {
-			return fmt.Errorf("get extension name: %w", err)
+		if err := executeHooks(ext, extCfg.Build.Zip.Composer.AfterHooks, extDir); err != nil {
+			return "", fmt.Errorf("after hooks composer: %w", err)
 		}
+	}
+	var tempExt extension.Extension
+	if tempExt, err = extension.GetExtensionByFolder(extDir); err != nil {
+		return "", err
+	}
 
-		extDir := fmt.Sprintf("%s/%s/", tempDir, extName)
+	if extCfg.Build.Zip.Composer.Enabled && extCfg.Build.Zip.Composer.Scoper.Enabled {
+		if err := extension.ScopeComposerDependencies(cmd.Context(), extDir, name, extCfg.Build.Zip.Composer.Scoper); err != nil {
+			return "", fmt.Errorf("scope composer dependencies: %w", err)
+		}
+	}
+
+	if extCfg.Build.Zip.Assets.Enabled {
+		if err := executeHooks(ext, extCfg.Build.Zip.Assets.BeforeHooks, extDir); err != nil {
+			return "", fmt.Errorf("before hooks assets: %w", err)
+		}
 
-		err = os.Mkdir(extDir, os.ModePerm)
+		shopwareConstraint, err := tempExt.GetShopwareVersionConstraint()
 		if err != nil {
-			return fmt.Errorf("create temp directory: %w", err)
+			return "", fmt.Errorf("get shopware version constraint: %w", err)
 		}
 
-		tempDir += "/"
+		if target != nil {
+			shopwareConstraint = target.Constraint()
+		}
 
-		defer func(path string) {
-			_ = os.RemoveAll(path)
-		}(tempDir)
+		assetBuildConfig := extension.AssetBuildConfig{
+			CleanupNodeModules: true,
+			ShopwareRoot:       os.Getenv("SHOPWARE_PROJECT_ROOT"),
+			ShopwareVersion:    shopwareConstraint,
+			SourceMaps:         bundleReportRequested(cmd, []extension.Extension{tempExt}),
+		}
 
-		var tag string
+		if useCache, _ := cmd.Flags().GetBool("asset-cache"); useCache || workspace != nil {
+			assetBuildConfig.Cache = newAssetCache()
+		}
 
-		// Extract files using strategy
-		if disableGit {
-			err = cp.Copy(extPath, extDir, copyOptions())
-			if err != nil {
-				return fmt.Errorf("copy files: %w", err)
-			}
-		} else {
-			gitCommit, _ := cmd.Flags().GetString("git-commit")
+		if err := extension.BuildAssetsForExtensions(cmd.Context(), extension.ConvertExtensionsToSources(cmd.Context(), []extension.Extension{tempExt}), assetBuildConfig); err != nil {
+			return "", fmt.Errorf("building assets: %w", err)
+		}
 
-			tag, err = extension.GitCopyFolder(extPath, extDir, gitCommit)
-			if err != nil {
-				return fmt.Errorf("copy via git: %w", err)
-			}
+		if err := reportBundleSizes(cmd, []extension.Extension{tempExt}, assetBuildConfig.SourceMaps); err != nil {
+			return "", err
+		}
 
-			logging.FromContext(cmd.Context()).Infof("Checking out %s using Git", tag)
+		if err := executeHooks(ext, extCfg.Build.Zip.Assets.AfterHooks, extDir); err != nil {
+			return "", fmt.Errorf("after hooks assets: %w", err)
 		}
+	}
 
-		// User input wins
-		if len(branch) > 0 {
-			tag = branch
+	if cmd.Flags().Changed("overwrite-app-backend-secret") {
+		extCfg.Validation.Ignore = append(extCfg.Validation.Ignore, extension.ConfigValidationIgnoreItem{Identifier: "metadata.setup", Reason: "The app secret is set with --overwrite-app-backend-secret"})
+		if err := extCfg.Dump(extDir); err != nil {
+			return "", fmt.Errorf("dump extension config: %w", err)
 		}
+	}
 
-		if extCfg.Build.Zip.Composer.Enabled {
-			if err := executeHooks(ext, extCfg.Build.Zip.Composer.BeforeHooks, extDir); err != nil {
-				return fmt.Errorf("before hooks composer: %w", err)
-			}
+	// Cleanup not wanted files
+	if err := extension.RemoveZipIgnoredFiles(extDir, extCfg.Build.Zip.Pack.Excludes.Patterns); err != nil {
+		return "", fmt.Errorf("apply zip ignore patterns: %w", err)
+	}
 
-			if err := extension.PrepareFolderForZipping(cmd.Context(), extDir, ext, extCfg); err != nil {
-				return fmt.Errorf("prepare package: %w", err)
-			}
+	if err := extension.CleanupExtensionFolder(extDir, extCfg.Build.Zip.Pack.Excludes.Paths); err != nil {
+		return "", fmt.Errorf("cleanup package: %w", err)
+	}
 
-			if err := executeHooks(ext, extCfg.Build.Zip.Composer.AfterHooks, extDir); err != nil {
-				return fmt.Errorf("after hooks composer: %w", err)
-			}
+	if extensionReleaseMode {
+		if err := extension.PrepareExtensionForRelease(cmd.Context(), extPath, extDir, ext); err != nil {
+			return "", fmt.Errorf("prepare for release: %w", err)
 		}
-		var tempExt extension.Extension
-		if tempExt, err = extension.GetExtensionByFolder(extDir); err != nil {
-			return err
-		}
-
-		if extCfg.Build.Zip.Assets.Enabled {
-			if err := executeHooks(ext, extCfg.Build.Zip.Assets.BeforeHooks, extDir); err != nil {
-				return fmt.Errorf("before hooks assets: %w", err)
-			}
+	}
 
-			shopwareConstraint, err := tempExt.GetShopwareVersionConstraint()
-			if err != nil {
-				return fmt.Errorf("get shopware version constraint: %w", err)
-			}
+	if err := extension.ResizeExtensionIcon(cmd.Context(), tempExt); err != nil {
+		return "", fmt.Errorf("resize extension icon: %w", err)
+	}
 
-			assetBuildConfig := extension.AssetBuildConfig{
-				CleanupNodeModules: true,
-				ShopwareRoot:       os.Getenv("SHOPWARE_PROJECT_ROOT"),
-				ShopwareVersion:    shopwareConstraint,
-			}
+	if err := extension.BuildModifier(ext, extDir, extension.BuildModifierConfig{
+		AppBackendUrl:    getStringOnStringError(cmd.Flags().GetString("overwrite-app-backend-url")),
+		AppBackendSecret: getStringOnStringError(cmd.Flags().GetString("overwrite-app-backend-secret")),
+		Version:          getStringOnStringError(cmd.Flags().GetString("overwrite-version")),
+	}); err != nil {
+		return "", fmt.Errorf("build modifier: %w", err)
+	}
 
-			if err := extension.BuildAssetsForExtensions(cmd.Context(), extension.ConvertExtensionsToSources(cmd.Context(), []extension.Extension{tempExt}), assetBuildConfig); err != nil {
-				return fmt.Errorf("building assets: %w", err)
-			}
+	fileName, _ := cmd.Flags().GetString("filename")
 
-			if err := executeHooks(ext, extCfg.Build.Zip.Assets.AfterHooks, extDir); err != nil {
-				return fmt.Errorf("after hooks assets: %w", err)
-			}
+	if len(fileName) == 0 {
+		fileName = fmt.Sprintf("%s-%s.zip", name, tag)
+		if len(tag) == 0 {
+			fileName = fmt.Sprintf("%s.zip", name)
 		}
+	}
 
-		if cmd.Flags().Changed("overwrite-app-backend-secret") {
-			extCfg.Validation.Ignore = append(extCfg.Validation.Ignore, extension.ConfigValidationIgnoreItem{Identifier: "metadata.setup"})
-			if err := extCfg.Dump(extDir); err != nil {
-				return fmt.Errorf("dump extension config: %w", err)
-			}
-		}
+	if target != nil {
+		fileName = strings.TrimSuffix(fileName, ".zip") + "-sw" + target.Major + ".zip"
+	}
 
-		// Cleanup not wanted files
-		if err := extension.CleanupExtensionFolder(extDir, extCfg.Build.Zip.Pack.Excludes.Paths); err != nil {
-			return fmt.Errorf("cleanup package: %w", err)
-		}
+	outputDir, _ := cmd.Flags().GetString("output-directory")
 
-		if extensionReleaseMode {
-			if err := extension.PrepareExtensionForRelease(cmd.Context(), extPath, extDir, ext); err != nil {
-				return fmt.Errorf("prepare for release: %w", err)
+	if len(outputDir) > 0 {
+		if _, err := os.Stat(outputDir); os.IsNotExist(err) {
+			if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
+				return "", fmt.Errorf("create output directory: %w", err)
 			}
 		}
 
-		if err := extension.ResizeExtensionIcon(cmd.Context(), tempExt); err != nil {
-			return fmt.Errorf("resize extension icon: %w", err)
+		fileName = path.Join(outputDir, fileName)
+	}
+
+	artifactEnv := extension.ArtifactHookEnv(fileName, name, packedExtensionVersion(extDir))
+
+	if err := executeHooks(ext, extCfg.Build.Zip.Pack.BeforeHooks, extDir, artifactEnv...); err != nil {
+		return "", fmt.Errorf("before hooks pack: %w", err)
+	}
+
+	if err := scanForSecrets(cmd, extDir, extCfg.Build.Zip.Secrets); err != nil {
+		return "", err
+	}
+
+	// Generate checksums.json file before creating the zip
+	if err := extension.GenerateChecksumJSON(cmd.Context(), extDir, ext); err != nil {
+		return "", fmt.Errorf("generate checksum.json: %w", err)
+	}
+
+	if showFiles, _ := cmd.Flags().GetBool("show-files"); showFiles {
+		files, err := extension.ListZipFiles(tempDir)
+		if err != nil {
+			return "", fmt.Errorf("list files: %w", err)
 		}
 
-		if err := extension.BuildModifier(ext, extDir, extension.BuildModifierConfig{
-			AppBackendUrl:    getStringOnStringError(cmd.Flags().GetString("overwrite-app-backend-url")),
-			AppBackendSecret: getStringOnStringError(cmd.Flags().GetString("overwrite-app-backend-secret")),
-			Version:          getStringOnStringError(cmd.Flags().GetString("overwrite-version")),
-		}); err != nil {
-			return fmt.Errorf("build modifier: %w", err)
+		for _, file := range files {
+			fmt.Println(file)
 		}
 
-		fileName, _ := cmd.Flags().GetString("filename")
+		return "", nil
+	}
 
-		if len(fileName) == 0 {
-			fileName = fmt.Sprintf("%s-%s.zip", name, tag)
-			if len(tag) == 0 {
-				fileName = fmt.Sprintf("%s.zip", name)
-			}
-		}
+	if extCfg.Build.Zip.Pack.VendorCache || vendorCache || workspace != nil {
+		err = extension.CreateZipWithVendorLayer(cmd.Context(), tempDir, fileName, extName, vendorLayerCacheDir())
+	} else {
+		err = extension.CreateZip(tempDir, fileName)
+	}
 
-		outputDir, _ := cmd.Flags().GetString("output-directory")
+	if err != nil {
+		return "", fmt.Errorf("create zip file: %w", err)
+	}
 
-		if len(outputDir) > 0 {
-			if _, err := os.Stat(outputDir); os.IsNotExist(err) {
-				if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
-					return fmt.Errorf("create output directory: %w", err)
-				}
-			}
+	logging.FromContext(cmd.Context()).Infof("Created file %s", fileName)
 
-			fileName = path.Join(outputDir, fileName)
+	if outputChecksum, _ := cmd.Flags().GetBool("output-checksum"); outputChecksum {
+		checksumFile, err := writeChecksumFile(fileName)
+		if err != nil {
+			return "", fmt.Errorf("write checksum: %w", err)
 		}
 
-		if err := executeHooks(ext, extCfg.Build.Zip.Pack.BeforeHooks, extDir); err != nil {
-			return fmt.Errorf("before hooks pack: %w", err)
-		}
+		logging.FromContext(cmd.Context()).Infof("Created file %s", checksumFile)
+	}
+
+	labels := metrics.Labels{"extension": name, "version": packedExtensionVersion(extDir)}
+	if target != nil {
+		labels["shopware"] = target.Major
+	}
+
+	metrics.EmitDuration(cmd.Context(), metrics.BuildDuration, start, labels)
+
+	if stat, err := os.Stat(fileName); err == nil {
+		metrics.Emit(cmd.Context(), metrics.ZipSize, float64(stat.Size()), labels)
+	}
 
-		// Generate checksums.json file before creating the zip
-		if err := extension.GenerateChecksumJSON(cmd.Context(), extDir, ext); err != nil {
-			return fmt.Errorf("generate checksum.json: %w", err)
+	createdFiles := []string{fileName}
+
+	if generateSbom, _ := cmd.Flags().GetBool("sbom"); generateSbom {
+		sbomFile := strings.TrimSuffix(fileName, ".zip") + ".spdx.json"
+
+		if err := writeSBOM(tempExt, sbomFile); err != nil {
+			return "", fmt.Errorf("generate sbom: %w", err)
 		}
 
-		if err := extension.CreateZip(tempDir, fileName); err != nil {
-			return fmt.Errorf("create zip file: %w", err)
+		logging.FromContext(cmd.Context()).Infof("Created file %s", sbomFile)
+
+		createdFiles = append(createdFiles, sbomFile)
+	}
+
+	if sign, _ := cmd.Flags().GetBool("sign"); sign {
+		signatureFiles, err := signArtifacts(cmd.Context(), createdFiles)
+		if err != nil {
+			return "", fmt.Errorf("sign zip: %w", err)
 		}
 
-		logging.FromContext(cmd.Context()).Infof("Created file %s", fileName)
+		createdFiles = append(createdFiles, signatureFiles...)
+	}
 
-		return nil
-	},
+	if err := executeHooks(ext, extCfg.Build.Zip.Pack.AfterHooks, extDir, artifactEnv...); err != nil {
+		return "", fmt.Errorf("after hooks pack: %w", err)
+	}
+
+	if output, _ := cmd.Flags().GetString("output"); output != "" {
+		if err := storeArtifacts(cmd.Context(), output, extDir, createdFiles); err != nil {
+			return "", fmt.Errorf("store zip: %w", err)
+		}
+	}
+
+	return fileName, nil
 }
 
 func init() {
 	extensionRootCmd.AddCommand(extensionZipCmd)
 	extensionZipCmd.Flags().BoolVar(&disableGit, "disable-git", false, "Use the source folder as it is")
 	extensionZipCmd.Flags().BoolVar(&extensionReleaseMode, "release", false, "Release mode (remove app secrets)")
+	extensionZipCmd.Flags().BoolVar(&vendorCache, "vendor-cache", false, "Reuse the compressed vendor folder until composer.lock changes")
 	extensionZipCmd.Flags().String("overwrite-app-backend-url", "", "Change all URLs in manifest.xml to this URL")
 	extensionZipCmd.Flags().String("overwrite-app-backend-secret", "", "Change the secret to this value")
 	extensionZipCmd.Flags().String("overwrite-version", "", "Change the extension version to this value")
 	extensionZipCmd.Flags().String("output-directory", "", "Output directory for the zip file")
 	extensionZipCmd.Flags().String("git-commit", "", "Commit Hash / Tag to use")
 	extensionZipCmd.Flags().String("filename", "", "Name of the zip file, if not set it will be generated from the extension name and tag")
+	extensionZipCmd.Flags().Bool("asset-cache", false, "Reuse node_modules and compiled assets when the sources did not change, SHOPWARE_CLI_ASSET_CACHE_URL adds a remote cache")
+	extensionZipCmd.Flags().Bool("matrix", false, "Build a zip for each Shopware major version allowed by the composer constraint, the file names get the version as suffix")
+	extensionZipCmd.Flags().Bool("output-checksum", false, "Write the SHA-256 of the zip file into a .sha256 file next to it")
+	extensionZipCmd.Flags().Bool("sign", false, "Write a SHA256SUMS manifest next to the zip and sign it with GPG, the key is read from SHOPWARE_CLI_GPG_PRIVATE_KEY or the default keyring")
+	extensionZipCmd.Flags().Bool("show-files", false, "List the files which would be packed instead of creating the zip")
+	extensionZipCmd.Flags().Bool("sbom", false, "Write a SPDX software bill of materials of the bundled dependencies next to the zip file")
+	addBundleReportFlag(extensionZipCmd)
+	extensionZipCmd.Flags().String("output", "", "Store the zip with its checksum in a directory, s3://bucket/prefix, gs://bucket/prefix or oci://registry/repository[:tag]")
+	addWorkspaceFlags(extensionZipCmd)
 }
 
-func getStringOnStringError(val string, _ error) string {
-	return val
-}
-
-func executeHooks(ext extension.Extension, hooks []string, extDir string) error {
-	env := []string{
-		fmt.Sprintf("EXTENSION_DIR=%s", extDir),
-		fmt.Sprintf("ORIGINAL_EXTENSION_DIR=%s", ext.GetPath()),
+// storeArtifacts copies or uploads the created files to the storage of the output URI
+func storeArtifacts(ctx context.Context, output, extDir string, files []string) error {
+	store, err := artifactstore.New(output)
+	if err != nil {
+		return err
 	}
 
-	for _, hook := range hooks {
-		hookCmd := exec.Command("sh", "-c", hook)
-		hookCmd.Stdout = os.Stdout
-		hookCmd.Stderr = os.Stderr
-		hookCmd.Dir = extDir
-		hookCmd.Env = append(os.Environ(), env...)
-		err := hookCmd.Run()
+	extVersion := packedExtensionVersion(extDir)
+
+	artifacts := make([]*artifactstore.Artifact, 0, len(files))
+
+	for _, file := range files {
+		artifact, err := artifactstore.NewArtifact(file, extVersion)
 		if err != nil {
 			return err
 		}
+
+		artifacts = append(artifacts, artifact)
 	}
 
+	locations, err := store.Put(ctx, artifacts)
+	if err != nil {
+		return err
+	}
+
+	for _, location := range locations {
+		logging.FromContext(ctx).Infof("Stored %s", location)
+	}
+
+	logging.FromContext(ctx).Infof("SHA-256 of %s: %s", artifacts[0].Name, artifacts[0].SHA256)
+
 	return nil
 }
 
-func copyOptions() cp.Options {
+// signArtifacts adds the files to the SHA256SUMS manifest next to them and signs it with the GPG key of the environment or agent
+func signArtifacts(ctx context.Context, files []string) ([]string, error) {
+	manifest, err := signing.WriteChecksums(files)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := signing.Sign(ctx, manifest, signing.KeyFromEnv())
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Infof("Signed %s with %s", manifest, signature)
+
+	return []string{manifest, signature}, nil
+}
+
+// writeChecksumFile writes the SHA-256 of the file next to it in the format of sha256sum, so it can be verified with sha256sum -c
+func writeChecksumFile(file string) (string, error) {
+	artifact, err := artifactstore.NewArtifact(file, "")
+	if err != nil {
+		return "", err
+	}
+
+	checksumFile := file + ".sha256"
+
+	if err := os.WriteFile(checksumFile, fmt.Appendf(nil, "%s  %s\n", artifact.SHA256, artifact.Name), 0o644); err != nil {
+		return "", err
+	}
+
+	return checksumFile, nil
+}
+
+// packedExtensionVersion reads the version from the packed folder, as it can be overwritten while zipping
+func packedExtensionVersion(extDir string) string {
+	packedExt, err := extension.GetExtensionByFolder(extDir)
+	if err != nil {
+		return ""
+	}
+
+	v, err := packedExt.GetVersion()
+	if err != nil {
+		return ""
+	}
+
+	return v.String()
+}
+
+// writeSBOM writes the SPDX document of the packed extension folder
+func writeSBOM(ext extension.Extension, file string) error {
+	dependencies, err := extension.FindBundledDependencies(ext)
+	if err != nil {
+		return err
+	}
+
+	document, err := extension.GenerateSPDX(ext, dependencies, time.Now())
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, content, os.ModePerm)
+}
+
+// scanForSecrets reports credentials in the files going into the zip and fails when a finding reaches the configured severity
+func scanForSecrets(cmd *cobra.Command, extDir string, cfg extension.ConfigBuildZipSecrets) error {
+	findings, err := extension.ScanForSecrets(extDir, cfg.Ignore)
+	if err != nil {
+		return fmt.Errorf("scan for secrets: %w", err)
+	}
+
+	for _, finding := range findings {
+		logging.FromContext(cmd.Context()).Warnf("Secret scan: %s", finding.String())
+	}
+
+	if extension.SecretFindingsFailBuild(findings, cfg.FailOn) {
+		return fmt.Errorf("found %d possible secrets in the zip content, remove them or configure build.zip.secrets in the extension config", len(findings))
+	}
+
+	return nil
+}
+
+func getStringOnStringError(val string, _ error) string {
+	return val
+}
+
+func executeHooks(ext extension.Extension, hooks []string, extDir string, env ...string) error {
+	env = append([]string{
+		fmt.Sprintf("EXTENSION_DIR=%s", extDir),
+		fmt.Sprintf("ORIGINAL_EXTENSION_DIR=%s", ext.GetPath()),
+	}, env...)
+
+	return extension.RunHooks(hooks, extDir, env)
+}
+
+// copyOptions prunes the git metadata and configured excludes while copying, as they are removed from the zip later anyway.
+func copyOptions(source string, excludes []string) cp.Options {
 	return cp.Options{
 		OnSymlink: func(string) cp.SymlinkAction {
 			return cp.Skip
 		},
+		Skip: func(_ os.FileInfo, src, _ string) (bool, error) {
+			rel, err := filepath.Rel(source, src)
+			if err != nil {
+				return false, err
+			}
+
+			rel = filepath.ToSlash(rel)
+
+			if rel == ".git" {
+				return true, nil
+			}
+
+			for _, excluded := range excludes {
+				excluded = strings.Trim(excluded, "/")
+
+				if excluded != "" && (rel == excluded || strings.HasPrefix(rel, excluded+"/")) {
+					return true, nil
+				}
+			}
+
+			return false, nil
+		},
 	}
 }
@@ -0,0 +1,114 @@
+package extension
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/internal/color"
+)
+
+var extensionZipDiffCmd = &cobra.Command{
+	Use:   "diff [old-zip] [new-zip]",
+	Short: "Show the differences between two zips of an extension",
+	Long: `Reports the added, removed and changed files, the changed composer requirements and bundled packages
+and the size changes of the compiled JavaScript and CSS files, for example between the previous store binary and a new release.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		diff, err := extension.DiffZips(args[0], args[1])
+		if err != nil {
+			return err
+		}
+
+		if outputAsJson, _ := cmd.Flags().GetBool("json"); outputAsJson {
+			content, err := json.MarshalIndent(diff, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(content))
+
+			return nil
+		}
+
+		printZipDiff(diff)
+
+		return nil
+	},
+}
+
+func printZipDiff(diff *extension.ZipDiff) {
+	if !diff.HasChanges() {
+		fmt.Println("Both zips contain the same files")
+		return
+	}
+
+	printZipDiffSection(fmt.Sprintf("Added files (%d)", len(diff.Added)), len(diff.Added))
+	for _, file := range diff.Added {
+		fmt.Printf("  %s %s (%s)\n", color.GreenText.Render("+"), file.Path, humanize.Bytes(uint64(file.NewSize)))
+	}
+
+	printZipDiffSection(fmt.Sprintf("Removed files (%d)", len(diff.Removed)), len(diff.Removed))
+	for _, file := range diff.Removed {
+		fmt.Printf("  %s %s (%s)\n", color.RedText.Render("-"), file.Path, humanize.Bytes(uint64(file.OldSize)))
+	}
+
+	printZipDiffSection(fmt.Sprintf("Changed files (%d)", len(diff.Changed)), len(diff.Changed))
+	for _, file := range diff.Changed {
+		fmt.Printf("  %s %s (%s)\n", color.YellowText.Render("~"), file.Path, formatSizeChange(file.OldSize, file.NewSize))
+	}
+
+	printZipDiffSection("Composer requirements", len(diff.Requires))
+	for _, pkg := range diff.Requires {
+		fmt.Printf("  %s %s\n", pkg.Name, formatVersionChange(pkg))
+	}
+
+	printZipDiffSection("Bundled packages", len(diff.Packages))
+	for _, pkg := range diff.Packages {
+		fmt.Printf("  %s %s\n", pkg.Name, formatVersionChange(pkg))
+	}
+
+	printZipDiffSection(fmt.Sprintf("Compiled assets (%s)", formatSizeDelta(diff.AssetsSizeDelta)), len(diff.Assets))
+	for _, file := range diff.Assets {
+		fmt.Printf("  %s (%s)\n", file.Path, formatSizeChange(file.OldSize, file.NewSize))
+	}
+}
+
+func printZipDiffSection(title string, count int) {
+	if count == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s\n", color.BoldText.Render(title))
+}
+
+func formatVersionChange(pkg extension.ZipDiffPackage) string {
+	switch {
+	case pkg.OldVersion == "":
+		return color.GreenText.Render("added " + pkg.NewVersion)
+	case pkg.NewVersion == "":
+		return color.RedText.Render("removed " + pkg.OldVersion)
+	}
+
+	return fmt.Sprintf("%s → %s", pkg.OldVersion, pkg.NewVersion)
+}
+
+func formatSizeChange(oldSize, newSize int64) string {
+	return fmt.Sprintf("%s → %s, %s", humanize.Bytes(uint64(oldSize)), humanize.Bytes(uint64(newSize)), formatSizeDelta(newSize-oldSize))
+}
+
+func formatSizeDelta(delta int64) string {
+	if delta < 0 {
+		return color.GreenText.Render("-" + humanize.Bytes(uint64(-delta)))
+	}
+
+	return color.RedText.Render("+" + humanize.Bytes(uint64(delta)))
+}
+
+func init() {
+	extensionZipCmd.AddCommand(extensionZipDiffCmd)
+	extensionZipDiffCmd.Flags().Bool("json", false, "Output as json")
+}
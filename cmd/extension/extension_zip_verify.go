@@ -0,0 +1,85 @@
+package extension
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/internal/color"
+	"github.com/shopware/shopware-cli/internal/signing"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var extensionZipVerifyCmd = &cobra.Command{
+	Use:   "verify [path]",
+	Short: "Verify the GPG signature of a SHA256SUMS manifest and the checksums of the listed zips",
+	Long: `Verifies the detached signature written by extension zip --sign and compares the files listed in the manifest.
+The path can be the manifest or the folder containing it. Without --public-key the default GPG keyring is used.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manifest := signing.ChecksumsFileName
+
+		if len(args) > 0 {
+			manifest = args[0]
+		}
+
+		if stat, err := os.Stat(manifest); err == nil && stat.IsDir() {
+			manifest = filepath.Join(manifest, signing.ChecksumsFileName)
+		}
+
+		signature, _ := cmd.Flags().GetString("signature")
+		if signature == "" {
+			signature = manifest + ".asc"
+		}
+
+		var publicKey string
+
+		if publicKeyFile, _ := cmd.Flags().GetString("public-key"); publicKeyFile != "" {
+			content, err := os.ReadFile(publicKeyFile)
+			if err != nil {
+				return fmt.Errorf("read public key: %w", err)
+			}
+
+			publicKey = string(content)
+		}
+
+		fingerprint, err := signing.VerifySignature(cmd.Context(), manifest, signature, publicKey)
+		if err != nil {
+			return err
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Good signature of %s by %s", manifest, fingerprint)
+
+		results, err := signing.VerifyChecksums(manifest)
+		if err != nil {
+			return err
+		}
+
+		failed := 0
+
+		for _, result := range results {
+			if result.Err != nil {
+				failed++
+				fmt.Printf("%s %s: %v\n", color.RedText.Render("FAILED"), result.Name, result.Err)
+
+				continue
+			}
+
+			fmt.Printf("%s %s\n", color.GreenText.Render("OK"), result.Name)
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d files do not match the signed checksums", failed, len(results))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	extensionZipCmd.AddCommand(extensionZipVerifyCmd)
+	extensionZipVerifyCmd.Flags().String("signature", "", "Detached signature of the manifest, defaults to the manifest with .asc suffix")
+	extensionZipVerifyCmd.Flags().String("public-key", "", "Armored public key file, the signature is only accepted from this key")
+}
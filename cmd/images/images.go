@@ -0,0 +1,14 @@
+package images
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var imagesRootCmd = &cobra.Command{
+	Use:   "images",
+	Short: "Manage the Docker images used for testing against Shopware versions",
+}
+
+func Register(rootCmd *cobra.Command) {
+	rootCmd.AddCommand(imagesRootCmd)
+}
@@ -0,0 +1,86 @@
+package images
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/internal/devcontainer"
+	"github.com/shopware/shopware-cli/internal/table"
+	"github.com/shopware/shopware-cli/internal/verifier"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var imagesPullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Pull the Shopware images of several versions into Docker and a shared cache",
+	Long: `Pulls the Shopware images used by platform up and the PHP image of validate --compile-container.
+Versions like 6.6 resolve to the newest release of the version.
+
+With --cache-dir the images are saved as verified tar files, so matrix jobs sharing the folder (for example with actions/cache)
+load them from disk instead of pulling them from the registry again.`,
+	Example: `  shopware-cli images pull --versions 6.5,6.6,6.7
+  shopware-cli images pull --versions 6.6.10.0 --cache-dir ~/.cache/shopware-images`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		versions, _ := cmd.Flags().GetStringSlice("versions")
+		image, _ := cmd.Flags().GetString("image")
+		cacheDir, _ := cmd.Flags().GetString("cache-dir")
+
+		if _, err := exec.LookPath("docker"); err != nil {
+			return fmt.Errorf("images pull requires Docker: %w", err)
+		}
+
+		released, err := extension.GetShopwareVersions(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("get Shopware versions: %w", err)
+		}
+
+		versions, err = devcontainer.ResolveVersions(versions, released)
+		if err != nil {
+			return err
+		}
+
+		images := []string{verifier.DefaultContainerCompileImage}
+
+		for _, version := range versions {
+			name := devcontainer.Options{Image: image, Version: version}.ImageName()
+
+			if !slices.Contains(images, name) {
+				images = append(images, name)
+			}
+		}
+
+		var cache *devcontainer.ImageCache
+		if cacheDir != "" {
+			cache = &devcontainer.ImageCache{Dir: cacheDir}
+		}
+
+		out := table.NewWriter(os.Stdout)
+		out.Header([]string{"Image", "Source"})
+
+		for _, name := range images {
+			logging.FromContext(cmd.Context()).Infof("Pulling %s", name)
+
+			source, err := devcontainer.PullImage(cmd.Context(), name, cache)
+			if err != nil {
+				return fmt.Errorf("pull %s: %w", name, err)
+			}
+
+			_ = out.Append([]string{name, source})
+		}
+
+		return out.Render()
+	},
+}
+
+func init() {
+	imagesRootCmd.AddCommand(imagesPullCmd)
+	imagesPullCmd.Flags().StringSlice("versions", []string{"latest"}, "Shopware versions like 6.6 or 6.6.10.0, separated by comma")
+	imagesPullCmd.Flags().String("image", devcontainer.DefaultImage, "Docker image of the Shopware versions")
+	imagesPullCmd.Flags().String("cache-dir", os.Getenv("SHOPWARE_CLI_IMAGE_CACHE_DIR"), "Folder to save the images to and load them from, defaults to SHOPWARE_CLI_IMAGE_CACHE_DIR")
+}
@@ -0,0 +1,14 @@
+package platform
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var platformRootCmd = &cobra.Command{
+	Use:   "platform",
+	Short: "Run a local Shopware for extension development",
+}
+
+func Register(rootCmd *cobra.Command) {
+	rootCmd.AddCommand(platformRootCmd)
+}
@@ -0,0 +1,137 @@
+package platform
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/internal/devcontainer"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var platformUpCmd = &cobra.Command{
+	Use:   "up [path]",
+	Short: "Start a local Shopware in Docker with the extension installed",
+	Long: `Starts a Shopware of the chosen version in Docker, mounts the extension into custom/plugins (or custom/apps)
+and installs and activates it. Running the command again reuses the container, remove it with docker rm -f to start over.
+
+The Storefront is configured for http://localhost, so keep the default port unless you only need the Administration.`,
+	Example: `  shopware-cli platform up
+  shopware-cli platform up ./FroshTools --shopware-version 6.6.10.0 --demo-data --xdebug`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		extPath := "."
+		if len(args) > 0 {
+			extPath = args[0]
+		}
+
+		extPath, err := filepath.Abs(extPath)
+		if err != nil {
+			return err
+		}
+
+		ext, err := extension.GetExtensionByFolder(extPath)
+		if err != nil {
+			return fmt.Errorf("detect extension type: %w", err)
+		}
+
+		name, err := ext.GetName()
+		if err != nil {
+			return fmt.Errorf("get name: %w", err)
+		}
+
+		if ext.GetType() == extension.TypeShopwareBundle {
+			return fmt.Errorf("%s is a bundle, mount it into a project instead", name)
+		}
+
+		if _, err := exec.LookPath("docker"); err != nil {
+			return fmt.Errorf("platform up requires Docker: %w", err)
+		}
+
+		folder := "custom/plugins/"
+		if ext.GetType() == extension.TypePlatformApp {
+			folder = "custom/apps/"
+		}
+
+		opts := devcontainer.Options{
+			Name:   "shopware-" + strings.ToLower(name),
+			Mounts: []devcontainer.Mount{{Source: extPath, Target: folder + name}},
+		}
+
+		opts.Image, _ = cmd.Flags().GetString("image")
+		opts.Version, _ = cmd.Flags().GetString("shopware-version")
+		opts.Port, _ = cmd.Flags().GetInt("port")
+		opts.Xdebug, _ = cmd.Flags().GetBool("xdebug")
+
+		if containerName, _ := cmd.Flags().GetString("name"); containerName != "" {
+			opts.Name = containerName
+		}
+
+		demoData, _ := cmd.Flags().GetBool("demo-data")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		if err := devcontainer.Up(cmd.Context(), opts); err != nil {
+			return fmt.Errorf("start container: %w", err)
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Waiting for the shop at %s", opts.URL())
+
+		if err := devcontainer.WaitForShop(cmd.Context(), opts.URL(), timeout); err != nil {
+			return err
+		}
+
+		if demoData {
+			logging.FromContext(cmd.Context()).Infof("Generating demo data")
+
+			if err := devcontainer.Console(cmd.Context(), opts.Name, "framework:demodata"); err != nil {
+				return err
+			}
+
+			if err := devcontainer.Console(cmd.Context(), opts.Name, "dal:refresh:index"); err != nil {
+				return err
+			}
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Installing %s", name)
+
+		if ext.GetType() == extension.TypePlatformApp {
+			err = devcontainer.Console(cmd.Context(), opts.Name, "app:install", "--activate", name)
+		} else {
+			if err := devcontainer.Console(cmd.Context(), opts.Name, "plugin:refresh"); err != nil {
+				return err
+			}
+
+			err = devcontainer.Console(cmd.Context(), opts.Name, "plugin:install", "--activate", name)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := devcontainer.Console(cmd.Context(), opts.Name, "cache:clear"); err != nil {
+			return err
+		}
+
+		fmt.Printf("Shop:           %s\n", opts.URL())
+		fmt.Printf("Administration: %s/admin (admin / shopware)\n", opts.URL())
+		fmt.Printf("Stop:           docker stop %s\n", opts.Name)
+
+		return nil
+	},
+}
+
+func init() {
+	platformRootCmd.AddCommand(platformUpCmd)
+	platformUpCmd.Flags().String("shopware-version", "latest", "Shopware version, used as tag of the image")
+	platformUpCmd.Flags().String("image", devcontainer.DefaultImage, "Docker image with an installed Shopware")
+	platformUpCmd.Flags().String("name", "", "Name of the container, defaults to shopware-<extension name>")
+	platformUpCmd.Flags().Int("port", 80, "Port on the host for the shop")
+	platformUpCmd.Flags().Bool("demo-data", false, "Generate demo products, customers and orders")
+	platformUpCmd.Flags().Bool("xdebug", false, "Enable Xdebug in the container")
+	platformUpCmd.Flags().Duration("timeout", 5*time.Minute, "Maximum time to wait for the shop to start")
+}
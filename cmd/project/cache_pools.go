@@ -0,0 +1,189 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/shopware/shopware-cli/shop"
+)
+
+// cacheScanCount is the amount of keys requested per SCAN call and measured per pipeline
+const cacheScanCount = 1000
+
+type cachePool struct {
+	Name   string
+	Prefix string
+	client *redis.Client
+}
+
+type cachePoolStats struct {
+	Name     string `json:"name"`
+	Database string `json:"database"`
+	Keys     int64  `json:"keys"`
+	Memory   int64  `json:"memory"`
+}
+
+// newCachePools connects to the configured pools sorted by name, with a name only this pool is returned
+func newCachePools(cfg *shop.ConfigCache, name string) ([]cachePool, error) {
+	if cfg == nil || len(cfg.Pools) == 0 {
+		return nil, errors.New("no cache pools configured, add them to cache.pools of the project config")
+	}
+
+	names := make([]string, 0, len(cfg.Pools))
+
+	for poolName := range cfg.Pools {
+		names = append(names, poolName)
+	}
+
+	slices.Sort(names)
+
+	if name != "" {
+		if _, ok := cfg.Pools[name]; !ok {
+			return nil, fmt.Errorf("unknown cache pool %s, configured are %s", name, strings.Join(names, ", "))
+		}
+
+		names = []string{name}
+	}
+
+	pools := make([]cachePool, 0, len(names))
+
+	for _, poolName := range names {
+		poolCfg := cfg.Pools[poolName]
+
+		opts, err := redis.ParseURL(poolCfg.URL)
+		if err != nil {
+			return nil, fmt.Errorf("cache pool %s: %w", poolName, err)
+		}
+
+		pools = append(pools, cachePool{Name: poolName, Prefix: poolCfg.Prefix, client: redis.NewClient(opts)})
+	}
+
+	return pools, nil
+}
+
+func (p cachePool) Close() error {
+	return p.client.Close()
+}
+
+func (p cachePool) database() string {
+	opts := p.client.Options()
+
+	return fmt.Sprintf("%s/%d", opts.Addr, opts.DB)
+}
+
+// Stats counts the keys of the pool and sums their memory usage reported by Redis
+func (p cachePool) Stats(ctx context.Context) (cachePoolStats, error) {
+	stats := cachePoolStats{Name: p.Name, Database: p.database()}
+
+	err := p.scan(ctx, func(keys []string) error {
+		pipe := p.client.Pipeline()
+
+		usages := make([]*redis.IntCmd, 0, len(keys))
+
+		for _, key := range keys {
+			usages = append(usages, pipe.MemoryUsage(ctx, key))
+		}
+
+		// Keys expiring between SCAN and MEMORY USAGE return nil
+		if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+
+		for _, usage := range usages {
+			if usage.Err() == nil {
+				stats.Keys++
+				stats.Memory += usage.Val()
+			}
+		}
+
+		return nil
+	})
+
+	return stats, err
+}
+
+// Flush deletes the keys of the pool and returns their amount, a pool without prefix owns the whole database
+func (p cachePool) Flush(ctx context.Context) (int64, error) {
+	if p.Prefix == "" {
+		size, err := p.client.DBSize(ctx).Result()
+		if err != nil {
+			return 0, err
+		}
+
+		return size, p.client.FlushDBAsync(ctx).Err()
+	}
+
+	var deleted int64
+
+	err := p.scan(ctx, func(keys []string) error {
+		count, err := p.client.Unlink(ctx, keys...).Result()
+		deleted += count
+
+		return err
+	})
+
+	return deleted, err
+}
+
+func (p cachePool) scan(ctx context.Context, fn func(keys []string) error) error {
+	var cursor uint64
+
+	for {
+		keys, next, err := p.client.Scan(ctx, cursor, cacheKeyPattern(p.Prefix), cacheScanCount).Result()
+		if err != nil {
+			return fmt.Errorf("scan %s: %w", p.database(), err)
+		}
+
+		if len(keys) > 0 {
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+
+		cursor = next
+	}
+}
+
+// cacheKeyPattern escapes the glob characters of the prefix for SCAN MATCH
+func cacheKeyPattern(prefix string) string {
+	var pattern strings.Builder
+
+	for _, c := range prefix {
+		if strings.ContainsRune(`*?[]\`, c) {
+			pattern.WriteRune('\\')
+		}
+
+		pattern.WriteRune(c)
+	}
+
+	pattern.WriteRune('*')
+
+	return pattern.String()
+}
+
+// formatCacheMemory prints the bytes with binary units like redis-cli
+func formatCacheMemory(bytes int64) string {
+	const unit = 1024
+
+	if bytes < unit {
+		return fmt.Sprintf("%dB", bytes)
+	}
+
+	div, exp := int64(unit), 0
+
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.2f%c", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,53 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/shop"
+)
+
+func TestCacheKeyPattern(t *testing.T) {
+	assert.Equal(t, "*", cacheKeyPattern(""))
+	assert.Equal(t, "sf_s*", cacheKeyPattern("sf_s"))
+	assert.Equal(t, `http\*\[0\]\?:*`, cacheKeyPattern("http*[0]?:"))
+}
+
+func TestFormatCacheMemory(t *testing.T) {
+	assert.Equal(t, "512B", formatCacheMemory(512))
+	assert.Equal(t, "1.50K", formatCacheMemory(1536))
+	assert.Equal(t, "2.00M", formatCacheMemory(2*1024*1024))
+	assert.Equal(t, "1.00G", formatCacheMemory(1024*1024*1024))
+}
+
+func TestNewCachePools(t *testing.T) {
+	cfg := &shop.ConfigCache{Pools: map[string]shop.ConfigCachePool{
+		"session": {URL: "redis://localhost:6379/2", Prefix: "sf_s"},
+		"http":    {URL: "redis://localhost:6379/1"},
+		"object":  {URL: "redis://:secret@redis:6380/0"},
+	}}
+
+	pools, err := newCachePools(cfg, "")
+	require.NoError(t, err)
+
+	require.Len(t, pools, 3)
+	assert.Equal(t, "http", pools[0].Name)
+	assert.Equal(t, "localhost:6379/1", pools[0].database())
+	assert.Equal(t, "object", pools[1].Name)
+	assert.Equal(t, "redis:6380/0", pools[1].database())
+	assert.Equal(t, "session", pools[2].Name)
+	assert.Equal(t, "sf_s", pools[2].Prefix)
+
+	pools, err = newCachePools(cfg, "session")
+	require.NoError(t, err)
+	require.Len(t, pools, 1)
+	assert.Equal(t, "session", pools[0].Name)
+
+	_, err = newCachePools(cfg, "unknown")
+	assert.ErrorContains(t, err, "configured are http, object, session")
+
+	_, err = newCachePools(nil, "")
+	assert.ErrorContains(t, err, "cache.pools")
+}
@@ -0,0 +1,133 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// liveVersionID is the version of all entities outside of drafts, it must never be deleted
+const liveVersionID = "0FA91CE3E96A4BC2BE4BD9CE752C3425"
+
+// cleanupTarget selects the rows of a table to delete, they are removed in batches to keep the locks short
+type cleanupTarget struct {
+	Table string
+	Where string
+	Args  []any
+}
+
+func (t cleanupTarget) countQuery() string {
+	return fmt.Sprintf("SELECT COUNT(*) FROM `%s` WHERE %s", t.Table, t.Where)
+}
+
+func (t cleanupTarget) deleteQuery(batchSize int) string {
+	return fmt.Sprintf("DELETE FROM `%s` WHERE %s LIMIT %d", t.Table, t.Where, batchSize)
+}
+
+// parseOlderThan accepts the units of time.ParseDuration and additionally days like 30d and weeks like 2w
+func parseOlderThan(value string) (time.Duration, error) {
+	var duration time.Duration
+
+	switch {
+	case strings.HasSuffix(value, "d"), strings.HasSuffix(value, "w"):
+		amount, err := strconv.Atoi(value[:len(value)-1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q, use for example 30d, 2w or 12h", value)
+		}
+
+		duration = time.Duration(amount) * 24 * time.Hour
+
+		if strings.HasSuffix(value, "w") {
+			duration *= 7
+		}
+	default:
+		var err error
+
+		duration, err = time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q, use for example 30d, 2w or 12h", value)
+		}
+	}
+
+	if duration <= 0 {
+		return 0, fmt.Errorf("the duration %q must be positive", value)
+	}
+
+	return duration, nil
+}
+
+// cleanupCutoff returns the date in the format of the DATETIME(3) columns of Shopware, which are stored in UTC
+func cleanupCutoff(now time.Time, olderThan time.Duration) string {
+	return now.UTC().Add(-olderThan).Format("2006-01-02 15:04:05.000")
+}
+
+// runCleanup deletes the rows of the targets in order, with dryRun the rows are only counted
+func runCleanup(ctx context.Context, db *projectDatabase, targets []cleanupTarget, dryRun bool, batchSize int) error {
+	if batchSize <= 0 {
+		return fmt.Errorf("the batch size must be positive")
+	}
+
+	for _, target := range targets {
+		exists, err := tableExists(ctx, db, target.Table)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			logging.FromContext(ctx).Infof("Skipping %s, the table does not exist", target.Table)
+			continue
+		}
+
+		if dryRun {
+			var count int64
+
+			if err := db.QueryRowContext(ctx, target.countQuery(), target.Args...).Scan(&count); err != nil {
+				return fmt.Errorf("count %s: %w", target.Table, err)
+			}
+
+			logging.FromContext(ctx).Infof("Would delete %d rows of %s", count, target.Table)
+
+			continue
+		}
+
+		var deleted int64
+
+		for {
+			result, err := db.ExecContext(ctx, target.deleteQuery(batchSize), target.Args...)
+			if err != nil {
+				return fmt.Errorf("delete from %s: %w", target.Table, err)
+			}
+
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+
+			deleted += affected
+
+			if affected < int64(batchSize) {
+				break
+			}
+
+			logging.FromContext(ctx).Debugf("Deleted %d rows of %s so far", deleted, target.Table)
+		}
+
+		logging.FromContext(ctx).Infof("Deleted %d rows of %s", deleted, target.Table)
+	}
+
+	return nil
+}
+
+func tableExists(ctx context.Context, db *projectDatabase, table string) (bool, error) {
+	var count int
+
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?", table).Scan(&count); err != nil {
+		return false, fmt.Errorf("check table %s: %w", table, err)
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,64 @@
+package project
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOlderThan(t *testing.T) {
+	cases := map[string]time.Duration{
+		"30d": 30 * 24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+		"12h": 12 * time.Hour,
+		"90m": 90 * time.Minute,
+	}
+
+	for value, expected := range cases {
+		duration, err := parseOlderThan(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, duration, value)
+	}
+
+	for _, value := range []string{"", "d", "abc", "0d", "-5d"} {
+		_, err := parseOlderThan(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestCartCleanupTargets(t *testing.T) {
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	targets := cartCleanupTargets(now, 30*24*time.Hour)
+
+	require.Len(t, targets, 1)
+	assert.Equal(t, "DELETE FROM `cart` WHERE COALESCE(`updated_at`, `created_at`) < ? LIMIT 500", targets[0].deleteQuery(500))
+	assert.Equal(t, []any{"2024-03-01 10:00:00.000"}, targets[0].Args)
+}
+
+func TestSessionCleanupTargets(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	expired := sessionCleanupTargets(now, 0)
+	require.Len(t, expired, 1)
+	assert.Equal(t, "SELECT COUNT(*) FROM `sessions` WHERE `sess_lifetime` < ?", expired[0].countQuery())
+	assert.Equal(t, []any{int64(1700000000)}, expired[0].Args)
+
+	inactive := sessionCleanupTargets(now, time.Hour)
+	assert.Equal(t, "`sess_lifetime` < ? OR `sess_time` < ?", inactive[0].Where)
+	assert.Equal(t, []any{int64(1700000000), int64(1699996400)}, inactive[0].Args)
+}
+
+func TestVersionCleanupTargetsKeepLiveVersion(t *testing.T) {
+	targets := versionCleanupTargets(time.Now(), 24*time.Hour)
+
+	tables := make([]string, 0, len(targets))
+	for _, target := range targets {
+		tables = append(tables, target.Table)
+	}
+
+	assert.Equal(t, []string{"version_commit_data", "version_commit", "version"}, tables)
+	assert.Contains(t, targets[2].Where, "UNHEX('0FA91CE3E96A4BC2BE4BD9CE752C3425')")
+}
@@ -0,0 +1,127 @@
+package project
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/logging"
+	"github.com/shopware/shopware-cli/shop"
+)
+
+// projectDatabase is a connection to the primary or the read replica of the project. The connections to the replica are
+// read only sessions, the statement checks only fail earlier with a clearer message.
+type projectDatabase struct {
+	*sql.DB
+	Replica bool
+}
+
+var readOnlyStatementRegExp = regexp.MustCompile(`(?is)^\s*(SELECT|SHOW|DESCRIBE|DESC|EXPLAIN|WITH)\b`)
+
+// modifyingStatementRegExp catches data modifying statements of a WITH clause like WITH x AS (...) DELETE
+var modifyingStatementRegExp = regexp.MustCompile(`(?i)\b(INSERT|UPDATE|DELETE|REPLACE|TRUNCATE|DROP|ALTER|CREATE)\b`)
+
+// checkReadOnlyStatement refuses everything besides plain reads, writes on a replica break the replication
+func checkReadOnlyStatement(query string) error {
+	if !readOnlyStatementRegExp.MatchString(query) || modifyingStatementRegExp.MatchString(query) {
+		return fmt.Errorf("refusing to run a modifying statement on the read replica: %s", query)
+	}
+
+	return nil
+}
+
+// ExecContext refuses modifying statements on the read replica
+func (d *projectDatabase) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if d.Replica {
+		if err := checkReadOnlyStatement(query); err != nil {
+			return nil, err
+		}
+	}
+
+	return d.DB.ExecContext(ctx, query, args...)
+}
+
+// QueryContext refuses modifying statements on the read replica
+func (d *projectDatabase) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	if d.Replica {
+		if err := checkReadOnlyStatement(query); err != nil {
+			return nil, err
+		}
+	}
+
+	return d.DB.QueryContext(ctx, query, args...)
+}
+
+// connectProjectDatabase connects to the read replica of the project config for read-only commands.
+// The primary is used for all other commands, with --primary or when the host is passed explicitly.
+func connectProjectDatabase(cmd *cobra.Command, readOnly bool) (*projectDatabase, error) {
+	mysqlConfig, err := assembleConnectionURI(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	replica := false
+
+	if readOnly && !cmd.Flags().Changed("host") {
+		primary, _ := cmd.Flags().GetBool("primary")
+
+		projectCfg, err := shop.ReadConfig(projectConfigPath, true)
+		if err != nil {
+			return nil, err
+		}
+
+		if !primary && projectCfg.Database != nil && projectCfg.Database.ReadReplica != "" {
+			if err := applyDatabaseURL(mysqlConfig, projectCfg.Database.ReadReplica); err != nil {
+				return nil, fmt.Errorf("could not parse database.read_replica: %w", err)
+			}
+
+			replica = true
+
+			logging.FromContext(cmd.Context()).Infof("Using the read replica %s, pass --primary to use the primary", mysqlConfig.Addr)
+		}
+	}
+
+	connector, err := mysql.NewConnector(mysqlConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if replica {
+		connector = readOnlyConnector{connector}
+	}
+
+	return &projectDatabase{DB: sql.OpenDB(connector), Replica: replica}, nil
+}
+
+// readOnlyConnector makes every session read only, so also the raw connection used by the dumper cannot write to the replica
+type readOnlyConnector struct {
+	driver.Connector
+}
+
+func (c readOnlyConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	execer, ok := conn.(driver.ExecerContext)
+	if !ok {
+		_ = conn.Close()
+
+		return nil, errors.New("the database driver cannot make the session read only")
+	}
+
+	if _, err := execer.ExecContext(ctx, "SET SESSION TRANSACTION READ ONLY", nil); err != nil {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("could not make the session read only: %w", err)
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,106 @@
+package project
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// dumpBufferSize bounds how much of the dump is held in memory before it is handed to the next writer.
+// Writes block until the buffer is flushed, so a slow disk or pipe slows down reading from the database.
+const dumpBufferSize = 1 << 20
+
+// dumpWriter chains the buffer, the optional compressor and the destination.
+// Close flushes and closes them in order, so no data is lost at the end of the dump.
+type dumpWriter struct {
+	buffer  *bufio.Writer
+	closers []io.Closer
+	written int64
+}
+
+func (w *dumpWriter) Write(p []byte) (int, error) {
+	n, err := w.buffer.Write(p)
+	w.written += int64(n)
+
+	return n, err
+}
+
+// Written returns the amount of uncompressed bytes written to the dump
+func (w *dumpWriter) Written() int64 {
+	return w.written
+}
+
+func (w *dumpWriter) Close() error {
+	var errs []error
+
+	if w.buffer != nil {
+		errs = append(errs, w.buffer.Flush())
+	}
+
+	for _, c := range w.closers {
+		errs = append(errs, c.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// newDumpWriter opens the dump destination. An output of "-" writes to stdout, otherwise the
+// compression extension is appended to the file name. The final output name is returned.
+func newDumpWriter(output, compression string, stdout io.Writer) (*dumpWriter, string, error) {
+	w := &dumpWriter{}
+
+	var dest io.Writer
+
+	if output == "-" {
+		dest = stdout
+	} else {
+		switch compression {
+		case CompressionGzip:
+			output += ".gz"
+		case CompressionZstd:
+			output += ".zst"
+		}
+
+		f, err := os.Create(output)
+		if err != nil {
+			return nil, "", err
+		}
+
+		dest = f
+		w.closers = append(w.closers, f)
+	}
+
+	switch compression {
+	case "":
+	case CompressionGzip:
+		gz := gzip.NewWriter(dest)
+		w.closers = append([]io.Closer{gz}, w.closers...)
+		dest = gz
+	case CompressionZstd:
+		// A single encoder with lower memory keeps the usage independent of the CPU count
+		zw, err := zstd.NewWriter(dest,
+			zstd.WithEncoderLevel(zstd.SpeedBestCompression),
+			zstd.WithEncoderConcurrency(1),
+			zstd.WithLowerEncoderMem(true),
+		)
+		if err != nil {
+			_ = w.Close()
+			return nil, "", err
+		}
+
+		w.closers = append([]io.Closer{zw}, w.closers...)
+		dest = zw
+	default:
+		_ = w.Close()
+		return nil, "", fmt.Errorf("unsupported compression %q, use %s or %s", compression, CompressionGzip, CompressionZstd)
+	}
+
+	w.buffer = bufio.NewWriterSize(dest, dumpBufferSize)
+
+	return w, output, nil
+}
@@ -0,0 +1,87 @@
+package project
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeDump(t *testing.T, w io.Writer) string {
+	t.Helper()
+
+	// Larger than the buffer to make sure it is flushed multiple times
+	content := strings.Repeat("INSERT INTO `product` VALUES (1);\n", dumpBufferSize/10)
+
+	_, err := io.Copy(w, strings.NewReader(content))
+	require.NoError(t, err)
+
+	return content
+}
+
+func TestDumpWriterStdout(t *testing.T) {
+	var stdout bytes.Buffer
+
+	w, output, err := newDumpWriter("-", "", &stdout)
+	require.NoError(t, err)
+	assert.Equal(t, "-", output)
+
+	content := writeDump(t, w)
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, content, stdout.String())
+	assert.Equal(t, int64(len(content)), w.Written())
+}
+
+func TestDumpWriterGzip(t *testing.T) {
+	w, output, err := newDumpWriter(filepath.Join(t.TempDir(), "dump.sql"), CompressionGzip, nil)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(output, "dump.sql.gz"))
+
+	content := writeDump(t, w)
+	require.NoError(t, w.Close())
+
+	f, err := os.Open(output)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	r, err := gzip.NewReader(f)
+	require.NoError(t, err)
+
+	decompressed, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(decompressed))
+}
+
+func TestDumpWriterZstd(t *testing.T) {
+	w, output, err := newDumpWriter(filepath.Join(t.TempDir(), "dump.sql"), CompressionZstd, nil)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(output, "dump.sql.zst"))
+
+	content := writeDump(t, w)
+	require.NoError(t, w.Close())
+
+	f, err := os.Open(output)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	r, err := zstd.NewReader(f)
+	require.NoError(t, err)
+	defer r.Close()
+
+	decompressed, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(decompressed))
+}
+
+func TestDumpWriterUnknownCompression(t *testing.T) {
+	_, _, err := newDumpWriter("-", "brotli", &bytes.Buffer{})
+	assert.ErrorContains(t, err, "unsupported compression")
+}
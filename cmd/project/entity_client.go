@@ -0,0 +1,347 @@
+package project
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	clientLangGo = "go"
+	clientLangTS = "ts"
+)
+
+// entitySchemaProperty is a field of an entity in /api/_info/entity-schema.json
+type entitySchemaProperty struct {
+	Type     string `json:"type"`
+	Relation string `json:"relation"`
+	// Entity is the referenced entity of an association
+	Entity string `json:"entity"`
+}
+
+type entitySchemaDefinition struct {
+	Entity     string                          `json:"entity"`
+	Properties map[string]entitySchemaProperty `json:"properties"`
+}
+
+type entitySchema map[string]entitySchemaDefinition
+
+var (
+	entityNameConstRegExp = regexp.MustCompile(`ENTITY_NAME\s*=\s*['"]([a-z0-9_]+)['"]`)
+	entitiesXMLRegExp     = regexp.MustCompile(`<entity\s+name="([a-z0-9_]+)"`)
+)
+
+// extensionEntityFolders contain the extensions of the project, the entities of Shopware and composer packages are not generated
+var extensionEntityFolders = []string{"custom/plugins", "custom/static-plugins", "custom/apps"}
+
+// findExtensionEntities collects the entities defined by the extensions of the project, by the ENTITY_NAME of their
+// entity definitions and the custom entities of entities.xml
+func findExtensionEntities(projectRoot string) ([]string, error) {
+	var entities []string
+
+	for _, folder := range extensionEntityFolders {
+		root := filepath.Join(projectRoot, folder)
+
+		if _, err := os.Stat(root); err != nil {
+			continue
+		}
+
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() {
+				if d.Name() == "node_modules" || d.Name() == "vendor" {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			var pattern *regexp.Regexp
+
+			switch {
+			case strings.HasSuffix(d.Name(), "Definition.php"):
+				pattern = entityNameConstRegExp
+			case d.Name() == "entities.xml":
+				pattern = entitiesXMLRegExp
+			default:
+				return nil
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			for _, match := range pattern.FindAllSubmatch(content, -1) {
+				entities = append(entities, string(match[1]))
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	slices.Sort(entities)
+
+	return slices.Compact(entities), nil
+}
+
+func parseEntitySchema(content []byte) (entitySchema, error) {
+	var schema entitySchema
+
+	if err := json.Unmarshal(content, &schema); err != nil {
+		return nil, fmt.Errorf("cannot parse the entity schema: %w", err)
+	}
+
+	return schema, nil
+}
+
+// selectEntities returns the entities existing in the schema, unknown ones are returned separately
+func (s entitySchema) selectEntities(names []string) ([]string, []string) {
+	var found, missing []string
+
+	for _, name := range names {
+		if _, ok := s[name]; ok {
+			found = append(found, name)
+		} else {
+			missing = append(missing, name)
+		}
+	}
+
+	slices.Sort(found)
+
+	return found, missing
+}
+
+// entityTypeName converts swag_example_translation into SwagExampleTranslation
+func entityTypeName(entity string) string {
+	var name strings.Builder
+
+	for _, part := range strings.Split(entity, "_") {
+		if part == "" {
+			continue
+		}
+
+		name.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	return name.String()
+}
+
+func sortedProperties(definition entitySchemaDefinition) []string {
+	names := make([]string, 0, len(definition.Properties))
+
+	for name := range definition.Properties {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+func isToManyRelation(relation string) bool {
+	return relation == "one_to_many" || relation == "many_to_many"
+}
+
+func goPropertyType(property entitySchemaProperty, generated []string) string {
+	if property.Type == "association" {
+		target := "map[string]any"
+
+		if slices.Contains(generated, property.Entity) {
+			target = entityTypeName(property.Entity)
+		}
+
+		if isToManyRelation(property.Relation) {
+			return "[]" + target
+		}
+
+		if target == "map[string]any" {
+			return target
+		}
+
+		return "*" + target
+	}
+
+	switch property.Type {
+	case "uuid", "string", "text", "password", "email", "remote_address":
+		return "string"
+	case "int":
+		return "int64"
+	case "float":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "date", "date_interval":
+		return "*time.Time"
+	case "json_list", "list":
+		return "[]any"
+	}
+
+	return "any"
+}
+
+func tsPropertyType(property entitySchemaProperty, generated []string) string {
+	if property.Type == "association" {
+		target := "Record<string, unknown>"
+
+		if slices.Contains(generated, property.Entity) {
+			target = entityTypeName(property.Entity)
+		}
+
+		if isToManyRelation(property.Relation) {
+			return target + "[]"
+		}
+
+		return target
+	}
+
+	switch property.Type {
+	case "uuid", "string", "text", "password", "email", "remote_address", "date", "date_interval":
+		return "string"
+	case "int", "float":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "json_list", "list":
+		return "unknown[]"
+	}
+
+	return "unknown"
+}
+
+// generateGoClient writes structs and repositories based on the GenericRepository of the Admin API SDK
+func generateGoClient(schema entitySchema, entities []string, pkg string) ([]byte, error) {
+	var out bytes.Buffer
+
+	out.WriteString("// Code generated by shopware-cli project generate client. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", pkg)
+
+	usesTime := false
+
+	for _, entity := range entities {
+		for _, property := range schema[entity].Properties {
+			if goPropertyType(property, entities) == "*time.Time" {
+				usesTime = true
+			}
+		}
+	}
+
+	out.WriteString("import (\n\t\"net/http\"\n")
+
+	if usesTime {
+		out.WriteString("\t\"time\"\n")
+	}
+
+	out.WriteString("\n\tadminSdk \"github.com/friendsofshopware/go-shopware-admin-api-sdk\"\n)\n")
+
+	for _, entity := range entities {
+		typeName := entityTypeName(entity)
+
+		fmt.Fprintf(&out, "\n// %sEntity is the name of the entity in the Admin API\nconst %sEntity = %q\n", typeName, typeName, entity)
+
+		fmt.Fprintf(&out, "\ntype %s struct {\n", typeName)
+
+		for _, name := range sortedProperties(schema[entity]) {
+			fmt.Fprintf(&out, "\t%s %s `json:\"%s,omitempty\"`\n", entityTypeName(name), goPropertyType(schema[entity].Properties[name], entities), name)
+		}
+
+		out.WriteString("}\n")
+
+		fmt.Fprintf(&out, `
+type %[1]sRepository struct {
+	*adminSdk.GenericRepository[%[1]s]
+}
+
+func New%[1]sRepository(client *adminSdk.Client) *%[1]sRepository {
+	return &%[1]sRepository{GenericRepository: adminSdk.NewGenericRepository[%[1]s](client)}
+}
+
+func (r *%[1]sRepository) Search(ctx adminSdk.ApiContext, criteria adminSdk.Criteria) (*adminSdk.EntityCollection[%[1]s], *http.Response, error) {
+	return r.GenericRepository.Search(ctx, criteria, %[2]q)
+}
+
+func (r *%[1]sRepository) SearchIds(ctx adminSdk.ApiContext, criteria adminSdk.Criteria) (*adminSdk.SearchIdsResponse, *http.Response, error) {
+	return r.GenericRepository.SearchIds(ctx, criteria, %[2]q)
+}
+
+func (r *%[1]sRepository) Upsert(ctx adminSdk.ApiContext, entities []%[1]s) (*http.Response, error) {
+	return r.GenericRepository.Upsert(ctx, entities, %[1]sEntity)
+}
+
+func (r *%[1]sRepository) Delete(ctx adminSdk.ApiContext, ids []string) (*http.Response, error) {
+	return r.GenericRepository.Delete(ctx, ids, %[1]sEntity)
+}
+`, typeName, strings.ReplaceAll(entity, "_", "-"))
+	}
+
+	return format.Source(out.Bytes())
+}
+
+// generateTSClient writes interfaces and a typed search and upsert for a fetch function authenticated against the Admin API
+func generateTSClient(schema entitySchema, entities []string) []byte {
+	var out bytes.Buffer
+
+	out.WriteString("// Code generated by shopware-cli project generate client. DO NOT EDIT.\n")
+
+	for _, entity := range entities {
+		fmt.Fprintf(&out, "\nexport interface %s {\n", entityTypeName(entity))
+
+		for _, name := range sortedProperties(schema[entity]) {
+			fmt.Fprintf(&out, "  %s?: %s;\n", name, tsPropertyType(schema[entity].Properties[name], entities))
+		}
+
+		out.WriteString("}\n")
+	}
+
+	out.WriteString("\nexport interface Entities {\n")
+
+	for _, entity := range entities {
+		fmt.Fprintf(&out, "  %s: %s;\n", entity, entityTypeName(entity))
+	}
+
+	out.WriteString(`}
+
+export type AdminApiFetch = (path: string, init: RequestInit) => Promise<Response>;
+
+export async function search<E extends keyof Entities>(fetch: AdminApiFetch, entity: E, criteria: object = {}): Promise<Entities[E][]> {
+  const response = await fetch(` + "`/api/search/${entity.replaceAll('_', '-')}`" + `, {
+    method: 'POST',
+    headers: { 'Accept': 'application/json', 'Content-Type': 'application/json' },
+    body: JSON.stringify(criteria),
+  });
+
+  if (!response.ok) {
+    throw new Error(` + "`Search of ${entity} failed with ${response.status}`" + `);
+  }
+
+  return (await response.json()).data;
+}
+
+export async function upsert<E extends keyof Entities>(fetch: AdminApiFetch, entity: E, payload: Entities[E][]): Promise<void> {
+  const response = await fetch('/api/_action/sync', {
+    method: 'POST',
+    headers: { 'Accept': 'application/json', 'Content-Type': 'application/json' },
+    body: JSON.stringify([{ action: 'upsert', entity, payload }]),
+  });
+
+  if (!response.ok) {
+    throw new Error(` + "`Upsert of ${entity} failed with ${response.status}`" + `);
+  }
+}
+`)
+
+	return out.Bytes()
+}
@@ -0,0 +1,108 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testEntitySchema = `{
+	"swag_example": {
+		"entity": "swag_example",
+		"properties": {
+			"id": {"type": "uuid", "flags": {"primary_key": true, "required": true}},
+			"name": {"type": "string", "flags": []},
+			"position": {"type": "int", "flags": []},
+			"active": {"type": "boolean", "flags": []},
+			"createdAt": {"type": "date", "flags": {"required": true}},
+			"customFields": {"type": "json_object", "flags": []},
+			"productId": {"type": "uuid", "flags": []},
+			"product": {"type": "association", "relation": "many_to_one", "entity": "product", "flags": []},
+			"items": {"type": "association", "relation": "one_to_many", "entity": "swag_example_item", "flags": []}
+		}
+	},
+	"swag_example_item": {
+		"entity": "swag_example_item",
+		"properties": {
+			"id": {"type": "uuid", "flags": []},
+			"price": {"type": "float", "flags": []},
+			"example": {"type": "association", "relation": "many_to_one", "entity": "swag_example", "flags": []}
+		}
+	},
+	"product": {"entity": "product", "properties": {"id": {"type": "uuid", "flags": []}}}
+}`
+
+func TestFindExtensionEntities(t *testing.T) {
+	root := t.TempDir()
+
+	files := map[string]string{
+		"custom/plugins/SwagExample/src/Core/ExampleDefinition.php":            "<?php\nclass ExampleDefinition { public const ENTITY_NAME = 'swag_example'; }",
+		"custom/plugins/SwagExample/src/Core/Item/ExampleItemDefinition.php":   "<?php\nclass ExampleItemDefinition { final public const ENTITY_NAME = \"swag_example_item\"; }",
+		"custom/plugins/SwagExample/vendor/foo/bar/src/OtherDefinition.php":    "<?php\nconst ENTITY_NAME = 'vendor_entity';",
+		"custom/apps/MyApp/Resources/entities.xml":                             `<entities><entity name="custom_entity_bundle"></entity><entity name="ce_blog"/></entities>`,
+		"custom/static-plugins/Other/src/Core/ExampleDefinition.php":           "<?php\nclass ExampleDefinition { public const ENTITY_NAME = 'swag_example'; }",
+		"vendor/shopware/core/Content/Product/ProductDefinition.php":           "<?php\nconst ENTITY_NAME = 'product';",
+		"custom/plugins/SwagExample/src/Resources/app/administration/index.js": "ENTITY_NAME = 'js_entity'",
+	}
+
+	for file, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, file)), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(root, file), []byte(content), os.ModePerm))
+	}
+
+	entities, err := findExtensionEntities(root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ce_blog", "custom_entity_bundle", "swag_example", "swag_example_item"}, entities)
+}
+
+func TestSelectEntities(t *testing.T) {
+	schema, err := parseEntitySchema([]byte(testEntitySchema))
+	require.NoError(t, err)
+
+	found, missing := schema.selectEntities([]string{"swag_example_item", "ce_blog", "swag_example"})
+	assert.Equal(t, []string{"swag_example", "swag_example_item"}, found)
+	assert.Equal(t, []string{"ce_blog"}, missing)
+}
+
+func TestGenerateGoClient(t *testing.T) {
+	schema, err := parseEntitySchema([]byte(testEntitySchema))
+	require.NoError(t, err)
+
+	generated, err := generateGoClient(schema, []string{"swag_example", "swag_example_item"}, "client")
+	require.NoError(t, err)
+
+	content := string(generated)
+
+	assert.Contains(t, content, "package client")
+	assert.Contains(t, content, "\t\"time\"\n")
+	assert.Contains(t, content, "const SwagExampleEntity = \"swag_example\"")
+	assert.Contains(t, content, "Position     int64             `json:\"position,omitempty\"`")
+	assert.Contains(t, content, "CreatedAt    *time.Time        `json:\"createdAt,omitempty\"`")
+	assert.Contains(t, content, "CustomFields any               `json:\"customFields,omitempty\"`")
+	assert.Contains(t, content, "Items        []SwagExampleItem `json:\"items,omitempty\"`")
+	assert.Contains(t, content, "Product      map[string]any    `json:\"product,omitempty\"`")
+	assert.Contains(t, content, "Example *SwagExample `json:\"example,omitempty\"`")
+	assert.Contains(t, content, `return r.GenericRepository.Search(ctx, criteria, "swag-example-item")`)
+	assert.Contains(t, content, "return r.GenericRepository.Upsert(ctx, entities, SwagExampleItemEntity)")
+}
+
+func TestGenerateTSClient(t *testing.T) {
+	schema, err := parseEntitySchema([]byte(testEntitySchema))
+	require.NoError(t, err)
+
+	content := string(generateTSClient(schema, []string{"swag_example", "swag_example_item"}))
+
+	assert.Contains(t, content, "export interface SwagExample {\n  active?: boolean;\n  createdAt?: string;\n  customFields?: unknown;\n  id?: string;\n  items?: SwagExampleItem[];\n")
+	assert.Contains(t, content, "  product?: Record<string, unknown>;\n")
+	assert.Contains(t, content, "  example?: SwagExample;\n  id?: string;\n  price?: number;\n}")
+	assert.Contains(t, content, "export interface Entities {\n  swag_example: SwagExample;\n  swag_example_item: SwagExampleItem;\n}")
+}
+
+func TestEntityTypeName(t *testing.T) {
+	assert.Equal(t, "SwagExampleTranslation", entityTypeName("swag_example_translation"))
+	assert.Equal(t, "ProductId", entityTypeName("productId"))
+	assert.Equal(t, "Id", entityTypeName("id"))
+}
@@ -0,0 +1,170 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shopware/shopware-cli/shop"
+)
+
+const (
+	defaultExportCursorFile = ".shopware-export-cursor.json"
+	exportPageSize          = 500
+	// exportSinceLastRun continues after the changes stored in the cursor file
+	exportSinceLastRun = "last-run"
+	// exportCursorOverlap is subtracted from the start of the run, changes of transactions which were open while the
+	// export started are exported again by the next run
+	exportCursorOverlap = time.Minute
+)
+
+// exportCursor stores the start time of the last export per entity
+type exportCursor map[string]time.Time
+
+// exportedChange is a line of the JSONL output
+type exportedChange struct {
+	Entity string         `json:"entity"`
+	ID     any            `json:"id"`
+	Data   map[string]any `json:"data"`
+}
+
+// exportSearcher returns the records of a search criteria, it is the Admin API in production
+type exportSearcher func(ctx context.Context, entity string, criteria map[string]any) ([]map[string]any, error)
+
+func readExportCursor(file string) (exportCursor, error) {
+	cursor := exportCursor{}
+
+	content, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return cursor, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, &cursor); err != nil {
+		return nil, fmt.Errorf("cannot parse the cursor file %s: %w", file, err)
+	}
+
+	return cursor, nil
+}
+
+func writeExportCursor(file string, cursor exportCursor) error {
+	content, err := json.MarshalIndent(cursor, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, append(content, '\n'), 0o644) //nolint:gosec
+}
+
+// resolveExportSince returns the start of the export of the entity, the zero time exports all records.
+// The value is last-run, a date like 2024-01-31 or a duration like 24h or 7d.
+func resolveExportSince(value string, cursor exportCursor, entity string, now time.Time) (time.Time, error) {
+	if value == exportSinceLastRun {
+		return cursor[entity], nil
+	}
+
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, nil
+	}
+
+	duration, err := parseOlderThan(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q, use last-run, a date like 2024-01-31 or a duration like 24h or 7d", value)
+	}
+
+	return now.Add(-duration), nil
+}
+
+// exportChangesCriteria selects records created or updated since the time. Shopware leaves updatedAt empty on creation,
+// so both fields are checked. The range includes the time itself, records at the boundary may be exported twice.
+func exportChangesCriteria(entity shop.ConfigExportEntity, since time.Time, page int) map[string]any {
+	criteria := map[string]any{
+		"page":             page,
+		"limit":            exportPageSize,
+		"total-count-mode": 0,
+		"sort":             []map[string]any{{"field": "id", "order": "ASC"}},
+	}
+
+	if !since.IsZero() {
+		gte := since.UTC().Format("2006-01-02T15:04:05.000Z")
+
+		criteria["filter"] = []map[string]any{{
+			"type":     "multi",
+			"operator": "or",
+			"queries": []map[string]any{
+				{"type": "range", "field": "updatedAt", "parameters": map[string]any{"gte": gte}},
+				{"type": "range", "field": "createdAt", "parameters": map[string]any{"gte": gte}},
+			},
+		}}
+	}
+
+	if len(entity.Fields) > 0 {
+		// The id and the timestamps are part of every exported record
+		criteria["includes"] = map[string][]string{
+			entity.Entity: append([]string{"id", "createdAt", "updatedAt"}, entity.Fields...),
+		}
+	}
+
+	return criteria
+}
+
+// exportChanges writes the changed records of the entities as JSONL and advances the cursor to the start of the run.
+// The records are paged by id, so the newest change seen is no cursor: a record changed during the export on a page
+// which was read already would be skipped by the next run. startedAt is the time of the shop when the export started.
+func exportChanges(ctx context.Context, search exportSearcher, entities []shop.ConfigExportEntity, since string, cursor exportCursor, startedAt time.Time, w io.Writer) (map[string]int, error) {
+	encoder := json.NewEncoder(w)
+	counts := make(map[string]int, len(entities))
+
+	for _, entity := range entities {
+		start, err := resolveExportSince(since, cursor, entity.Entity, startedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		for page := 1; ; page++ {
+			records, err := search(ctx, entity.Entity, exportChangesCriteria(entity, start, page))
+			if err != nil {
+				return nil, fmt.Errorf("search %s: %w", entity.Entity, err)
+			}
+
+			for _, record := range records {
+				if err := encoder.Encode(exportedChange{Entity: entity.Entity, ID: record["id"], Data: record}); err != nil {
+					return nil, err
+				}
+			}
+
+			counts[entity.Entity] += len(records)
+
+			if len(records) < exportPageSize {
+				break
+			}
+		}
+
+		cursor[entity.Entity] = startedAt.Add(-exportCursorOverlap)
+	}
+
+	return counts, nil
+}
+
+// exportEntityNames is used in the log output
+func exportEntityNames(entities []shop.ConfigExportEntity) string {
+	names := make([]string, 0, len(entities))
+
+	for _, entity := range entities {
+		names = append(names, entity.Entity)
+	}
+
+	return strings.Join(names, ", ")
+}
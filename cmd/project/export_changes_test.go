@@ -0,0 +1,120 @@
+package project
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/shop"
+)
+
+func TestResolveExportSince(t *testing.T) {
+	now := time.Date(2024, 2, 10, 12, 0, 0, 0, time.UTC)
+	last := time.Date(2024, 2, 9, 8, 30, 0, 0, time.UTC)
+	cursor := exportCursor{"product": last}
+
+	since, err := resolveExportSince("last-run", cursor, "product", now)
+	require.NoError(t, err)
+	assert.Equal(t, last, since)
+
+	since, err = resolveExportSince("last-run", cursor, "order", now)
+	require.NoError(t, err)
+	assert.True(t, since.IsZero())
+
+	since, err = resolveExportSince("2024-01-31", cursor, "product", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), since)
+
+	since, err = resolveExportSince("7d", cursor, "product", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 2, 3, 12, 0, 0, 0, time.UTC), since)
+
+	_, err = resolveExportSince("yesterday", cursor, "product", now)
+	assert.Error(t, err)
+}
+
+func TestExportChangesCriteria(t *testing.T) {
+	criteria := exportChangesCriteria(shop.ConfigExportEntity{Entity: "product"}, time.Time{}, 1)
+	assert.NotContains(t, criteria, "filter")
+	assert.NotContains(t, criteria, "includes")
+
+	since := time.Date(2024, 2, 9, 8, 30, 0, 0, time.FixedZone("CET", 3600))
+	criteria = exportChangesCriteria(shop.ConfigExportEntity{Entity: "product", Fields: []string{"productNumber"}}, since, 2)
+
+	payload, err := json.Marshal(criteria)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(payload), `{"field":"updatedAt","parameters":{"gte":"2024-02-09T07:30:00.000Z"},"type":"range"}`)
+	assert.Contains(t, string(payload), `{"field":"createdAt","parameters":{"gte":"2024-02-09T07:30:00.000Z"},"type":"range"}`)
+	assert.Contains(t, string(payload), `"includes":{"product":["id","createdAt","updatedAt","productNumber"]}`)
+	assert.Equal(t, 2, criteria["page"])
+}
+
+func TestExportChangesPaginatesAndAdvancesCursor(t *testing.T) {
+	records := make([]map[string]any, 0, exportPageSize+1)
+
+	for i := range exportPageSize + 1 {
+		records = append(records, map[string]any{
+			"id":        fmt.Sprintf("id-%d", i),
+			"createdAt": time.Date(2024, 1, 1, 0, 0, i, 0, time.UTC).Format(time.RFC3339Nano),
+		})
+	}
+
+	var pages []int
+
+	search := func(_ context.Context, entity string, criteria map[string]any) ([]map[string]any, error) {
+		if entity == "order" {
+			return nil, nil
+		}
+
+		page := criteria["page"].(int)
+		pages = append(pages, page)
+
+		end := min(page*exportPageSize, len(records))
+
+		return records[(page-1)*exportPageSize : end], nil
+	}
+
+	cursor := exportCursor{"order": time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)}
+
+	var out bytes.Buffer
+
+	startedAt := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	counts, err := exportChanges(t.Context(), search, []shop.ConfigExportEntity{{Entity: "product"}, {Entity: "order"}}, "last-run", cursor, startedAt, &out)
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{1, 2}, pages)
+	assert.Equal(t, map[string]int{"product": exportPageSize + 1, "order": 0}, counts)
+
+	// The cursor is the start of the run, records changed on pages which were read already are exported by the next run
+	assert.Equal(t, startedAt.Add(-exportCursorOverlap), cursor["product"])
+	assert.Equal(t, startedAt.Add(-exportCursorOverlap), cursor["order"])
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, exportPageSize+1)
+	assert.JSONEq(t, `{"entity":"product","id":"id-0","data":{"id":"id-0","createdAt":"2024-01-01T00:00:00Z"}}`, lines[0])
+}
+
+func TestExportCursorRoundTrip(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "cursor.json")
+
+	cursor, err := readExportCursor(file)
+	require.NoError(t, err)
+	assert.Empty(t, cursor)
+
+	cursor["product"] = time.Date(2024, 2, 9, 8, 30, 0, 123000000, time.UTC)
+	require.NoError(t, writeExportCursor(file, cursor))
+
+	read, err := readExportCursor(file)
+	require.NoError(t, err)
+	assert.Equal(t, cursor["product"], read["product"].UTC())
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/internal/interaction"
 	"github.com/shopware/shopware-cli/internal/packagist"
 	"github.com/shopware/shopware-cli/logging"
 )
@@ -30,13 +31,19 @@ var projectAutofixComposerCmd = &cobra.Command{
 			return err
 		}
 
-		var token string
+		token, _ := cmd.Flags().GetString("token")
 
-		if err := huh.NewInput().
-			Title("Please enter the Shopware Packagist Token").
-			Value(&token).
-			Run(); err != nil {
-			return err
+		if token == "" {
+			if err := interaction.RequireInput(cmd.Context(), "the Shopware Packagist Token", "pass it with --token"); err != nil {
+				return err
+			}
+
+			if err := huh.NewInput().
+				Title("Please enter the Shopware Packagist Token").
+				Value(&token).
+				Run(); err != nil {
+				return err
+			}
 		}
 
 		if token == "" {
@@ -135,4 +142,5 @@ var projectAutofixComposerCmd = &cobra.Command{
 
 func init() {
 	projectAutofixCmd.AddCommand(projectAutofixComposerCmd)
+	projectAutofixComposerCmd.Flags().String("token", "", "Shopware Packagist Token")
 }
@@ -5,11 +5,11 @@ import (
 	"os"
 	"path"
 
-	"github.com/charmbracelet/huh"
 	"github.com/spf13/cobra"
 
 	"github.com/shopware/shopware-cli/internal/color"
 	"github.com/shopware/shopware-cli/internal/flexmigrator"
+	"github.com/shopware/shopware-cli/internal/interaction"
 )
 
 var projectAutofixFlexCmd = &cobra.Command{
@@ -21,12 +21,12 @@ var projectAutofixFlexCmd = &cobra.Command{
 			return err
 		}
 
-		var confirmed bool
-		if err := huh.NewConfirm().
-			Title("Are you sure you want to autofix this project to Symfony Flex?").
-			Description("This will modify your composer.json and .env files. Make sure to commit your changes before running this command.").
-			Value(&confirmed).
-			Run(); err != nil {
+		confirmed, err := interaction.Confirm(
+			cmd.Context(),
+			"Are you sure you want to autofix this project to Symfony Flex?",
+			"This will modify your composer.json and .env files. Make sure to commit your changes before running this command.",
+		)
+		if err != nil {
 			return err
 		}
 
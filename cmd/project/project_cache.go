@@ -0,0 +1,14 @@
+package project
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var projectCacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and flush the cache pools of the project",
+}
+
+func init() {
+	projectRootCmd.AddCommand(projectCacheCmd)
+}
@@ -0,0 +1,107 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/internal/interaction"
+	"github.com/shopware/shopware-cli/internal/table"
+	"github.com/shopware/shopware-cli/logging"
+	"github.com/shopware/shopware-cli/shop"
+)
+
+var projectCacheInspectCmd = &cobra.Command{
+	Use:   "inspect [pool]",
+	Short: "Show the key count and memory usage of the cache pools, --flush clears a single pool",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := shop.ReadConfig(projectConfigPath, true)
+		if err != nil {
+			return err
+		}
+
+		var poolName string
+		if len(args) > 0 {
+			poolName = args[0]
+		}
+
+		flush, _ := cmd.Flags().GetBool("flush")
+		if flush && poolName == "" {
+			return fmt.Errorf("--flush requires the name of the pool to flush")
+		}
+
+		pools, err := newCachePools(cfg.Cache, poolName)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			for _, pool := range pools {
+				_ = pool.Close()
+			}
+		}()
+
+		if flush {
+			pool := pools[0]
+
+			confirmed, err := interaction.Confirm(cmd.Context(), fmt.Sprintf("Flush the cache pool %s in %s?", pool.Name, pool.database()), "")
+			if err != nil {
+				return err
+			}
+
+			if !confirmed {
+				return nil
+			}
+
+			deleted, err := pool.Flush(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("flush %s: %w", pool.Name, err)
+			}
+
+			logging.FromContext(cmd.Context()).Infof("Deleted %d keys of the cache pool %s", deleted, pool.Name)
+
+			return nil
+		}
+
+		stats := make([]cachePoolStats, 0, len(pools))
+
+		for _, pool := range pools {
+			poolStats, err := pool.Stats(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("inspect %s: %w", pool.Name, err)
+			}
+
+			stats = append(stats, poolStats)
+		}
+
+		if outputAsJson, _ := cmd.Flags().GetBool("json"); outputAsJson {
+			content, err := json.Marshal(stats)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(content))
+
+			return nil
+		}
+
+		table := table.NewWriter(os.Stdout)
+		table.Header([]string{"Pool", "Database", "Keys", "Memory"})
+
+		for _, poolStats := range stats {
+			_ = table.Append([]string{poolStats.Name, poolStats.Database, strconv.FormatInt(poolStats.Keys, 10), formatCacheMemory(poolStats.Memory)})
+		}
+
+		return table.Render()
+	},
+}
+
+func init() {
+	projectCacheCmd.AddCommand(projectCacheInspectCmd)
+	projectCacheInspectCmd.Flags().Bool("flush", false, "Delete all keys of the given pool, other pools are kept")
+	projectCacheInspectCmd.Flags().Bool("json", false, "Output as json")
+}
@@ -0,0 +1,138 @@
+package project
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/internal/table"
+	"github.com/shopware/shopware-cli/internal/vendorpatch"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var projectCheckCorePatchesCmd = &cobra.Command{
+	Use:   "check-core-patches [path]",
+	Short: "Detect modified files in vendor/shopware, which get lost on the next composer install",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var projectRoot string
+		var err error
+
+		if len(args) == 1 {
+			projectRoot, err = filepath.Abs(args[0])
+			if err != nil {
+				return err
+			}
+		} else if projectRoot, err = findClosestShopwareProject(); err != nil {
+			return err
+		}
+
+		exportDir, _ := cmd.Flags().GetString("export-patches")
+
+		packages, err := vendorpatch.ShopwarePackages(projectRoot)
+		if err != nil {
+			return fmt.Errorf("cannot read installed packages: %w", err)
+		}
+
+		rows := make([][]string, 0)
+		patches := make(map[string]string)
+
+		for _, pkg := range packages {
+			pristineDir, err := vendorpatch.PristinePackage(cmd.Context(), projectRoot, pkg)
+			if err != nil {
+				return err
+			}
+
+			installedDir := path.Join(projectRoot, "vendor", pkg.Name)
+
+			changes, err := vendorpatch.Compare(pkg.Name, installedDir, pristineDir)
+			if err != nil {
+				return fmt.Errorf("cannot compare %s: %w", pkg.Name, err)
+			}
+
+			for _, change := range changes {
+				rows = append(rows, []string{change.Package, change.Path, change.Status})
+			}
+
+			if exportDir == "" || len(changes) == 0 {
+				continue
+			}
+
+			patch, skipped, err := vendorpatch.CreatePatch(installedDir, pristineDir, changes)
+			if err != nil {
+				return fmt.Errorf("cannot create patch for %s: %w", pkg.Name, err)
+			}
+
+			for _, file := range skipped {
+				logging.FromContext(cmd.Context()).Warnf("Skipping binary file %s of %s, it cannot be part of a patch", file, pkg.Name)
+			}
+
+			if patch != "" {
+				patches[pkg.Name] = patch
+			}
+		}
+
+		if len(rows) == 0 {
+			logging.FromContext(cmd.Context()).Infof("No modified files found in vendor/shopware")
+			return nil
+		}
+
+		if err := table.RenderTable(cmd.OutOrStdout(), []string{"Package", "File", "Status"}, rows); err != nil {
+			return err
+		}
+
+		if exportDir == "" {
+			return fmt.Errorf("found %d modified files in vendor/shopware, export them with --export-patches to keep them on the next composer install", len(rows))
+		}
+
+		return writeCorePatches(cmd, projectRoot, exportDir, patches)
+	},
+}
+
+// writeCorePatches writes a patch per package and prints the configuration for cweagans/composer-patches
+func writeCorePatches(cmd *cobra.Command, projectRoot, exportDir string, patches map[string]string) error {
+	if !filepath.IsAbs(exportDir) {
+		exportDir = filepath.Join(projectRoot, exportDir)
+	}
+
+	if len(patches) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(exportDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	entries := make([]string, 0, len(patches))
+
+	for _, pkg := range slices.Sorted(maps.Keys(patches)) {
+		file := filepath.Join(exportDir, strings.ReplaceAll(pkg, "/", "-")+".patch")
+
+		if err := os.WriteFile(file, []byte(patches[pkg]), os.ModePerm); err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(projectRoot, file)
+		if err != nil {
+			relPath = file
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Wrote patch for %s to %s", pkg, relPath)
+
+		entries = append(entries, fmt.Sprintf("            %q: {\n                \"Local changes\": %q\n            }", pkg, filepath.ToSlash(relPath)))
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "\nRequire cweagans/composer-patches and add the patches to the composer.json:\n\n    \"extra\": {\n        \"patches\": {\n%s\n        }\n    }\n", strings.Join(entries, ",\n"))
+
+	return nil
+}
+
+func init() {
+	projectRootCmd.AddCommand(projectCheckCorePatchesCmd)
+	projectCheckCorePatchesCmd.Flags().String("export-patches", "", "Folder to write the modifications as composer patches to, for example patches")
+}
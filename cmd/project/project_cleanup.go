@@ -0,0 +1,38 @@
+package project
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var projectCleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Delete outdated rows of tables growing on busy shops like cart and version_commit_data",
+}
+
+// runCleanupCommand connects with the flags or DATABASE_URL of the project and deletes the rows of the targets
+func runCleanupCommand(cmd *cobra.Command, targets []cleanupTarget) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	batchSize, _ := cmd.Flags().GetInt("batch-size")
+
+	// A dry run only counts rows, so it can be answered by the read replica
+	db, err := connectProjectDatabase(cmd, dryRun)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = db.Close() }()
+
+	return runCleanup(cmd.Context(), db, targets, dryRun, batchSize)
+}
+
+func init() {
+	projectRootCmd.AddCommand(projectCleanupCmd)
+	projectCleanupCmd.PersistentFlags().String("host", "", "hostname")
+	projectCleanupCmd.PersistentFlags().String("database", "", "database name")
+	projectCleanupCmd.PersistentFlags().StringP("username", "u", "", "mysql user")
+	projectCleanupCmd.PersistentFlags().StringP("password", "p", "", "mysql password")
+	projectCleanupCmd.PersistentFlags().String("port", "", "mysql port")
+	projectCleanupCmd.PersistentFlags().Bool("dry-run", false, "Only count the rows which would be deleted")
+	projectCleanupCmd.PersistentFlags().Bool("primary", false, "Count the rows of a dry run on the primary instead of the read replica")
+	projectCleanupCmd.PersistentFlags().Int("batch-size", 1000, "Amount of rows deleted per query, smaller batches keep the table locks shorter")
+}
@@ -0,0 +1,33 @@
+package project
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var projectCleanupCartsCmd = &cobra.Command{
+	Use:   "carts",
+	Short: "Delete carts which were not changed for a while",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		olderThanValue, _ := cmd.Flags().GetString("older-than")
+
+		olderThan, err := parseOlderThan(olderThanValue)
+		if err != nil {
+			return err
+		}
+
+		return runCleanupCommand(cmd, cartCleanupTargets(time.Now(), olderThan))
+	},
+}
+
+func cartCleanupTargets(now time.Time, olderThan time.Duration) []cleanupTarget {
+	return []cleanupTarget{
+		{Table: "cart", Where: "COALESCE(`updated_at`, `created_at`) < ?", Args: []any{cleanupCutoff(now, olderThan)}},
+	}
+}
+
+func init() {
+	projectCleanupCmd.AddCommand(projectCleanupCartsCmd)
+	projectCleanupCartsCmd.Flags().String("older-than", "30d", "Delete carts not changed for this duration, like 30d, 2w or 12h")
+}
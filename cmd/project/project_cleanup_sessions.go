@@ -0,0 +1,43 @@
+package project
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var projectCleanupSessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "Delete expired sessions of the database session handler",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		var olderThan time.Duration
+
+		if olderThanValue, _ := cmd.Flags().GetString("older-than"); olderThanValue != "" {
+			var err error
+
+			olderThan, err = parseOlderThan(olderThanValue)
+			if err != nil {
+				return err
+			}
+		}
+
+		return runCleanupCommand(cmd, sessionCleanupTargets(time.Now(), olderThan))
+	},
+}
+
+// sessionCleanupTargets uses the sessions table of the Symfony PdoSessionHandler, sess_lifetime holds the expiry and sess_time the last write
+func sessionCleanupTargets(now time.Time, olderThan time.Duration) []cleanupTarget {
+	target := cleanupTarget{Table: "sessions", Where: "`sess_lifetime` < ?", Args: []any{now.Unix()}}
+
+	if olderThan > 0 {
+		target.Where += " OR `sess_time` < ?"
+		target.Args = append(target.Args, now.Add(-olderThan).Unix())
+	}
+
+	return []cleanupTarget{target}
+}
+
+func init() {
+	projectCleanupCmd.AddCommand(projectCleanupSessionsCmd)
+	projectCleanupSessionsCmd.Flags().String("older-than", "", "Delete also sessions not written for this duration, like 30d, 2w or 12h. Expired sessions are always deleted")
+}
@@ -0,0 +1,38 @@
+package project
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var projectCleanupVersionsCmd = &cobra.Command{
+	Use:   "versions",
+	Short: "Delete old version commits and abandoned draft versions",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		olderThanValue, _ := cmd.Flags().GetString("older-than")
+
+		olderThan, err := parseOlderThan(olderThanValue)
+		if err != nil {
+			return err
+		}
+
+		return runCleanupCommand(cmd, versionCleanupTargets(time.Now(), olderThan))
+	},
+}
+
+// versionCleanupTargets deletes the commit data first, so the cascades of the foreign keys do not delete millions of rows in one query
+func versionCleanupTargets(now time.Time, olderThan time.Duration) []cleanupTarget {
+	cutoff := cleanupCutoff(now, olderThan)
+
+	return []cleanupTarget{
+		{Table: "version_commit_data", Where: "`created_at` < ?", Args: []any{cutoff}},
+		{Table: "version_commit", Where: "`created_at` < ?", Args: []any{cutoff}},
+		{Table: "version", Where: "`id` != UNHEX('" + liveVersionID + "') AND `created_at` < ?", Args: []any{cutoff}},
+	}
+}
+
+func init() {
+	projectCleanupCmd.AddCommand(projectCleanupVersionsCmd)
+	projectCleanupVersionsCmd.Flags().String("older-than", "30d", "Delete versions and commits older than this duration, like 30d, 2w or 12h")
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 
+	"github.com/shopware/shopware-cli/internal/interaction"
 	"github.com/shopware/shopware-cli/logging"
 	"github.com/shopware/shopware-cli/shop"
 )
@@ -20,6 +21,10 @@ var projectConfigInitCmd = &cobra.Command{
 		var content []byte
 		var err error
 
+		if err := interaction.RequireInput(cmd.Context(), "the project configuration", "create the .shopware-project.yml manually"); err != nil {
+			return err
+		}
+
 		// Create URL input form
 		urlForm := huh.NewForm(
 			huh.NewGroup(
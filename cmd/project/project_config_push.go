@@ -3,10 +3,10 @@ package project
 import (
 	"encoding/json"
 
-	"github.com/charmbracelet/huh"
 	adminSdk "github.com/friendsofshopware/go-shopware-admin-api-sdk"
 	"github.com/spf13/cobra"
 
+	"github.com/shopware/shopware-cli/internal/interaction"
 	"github.com/shopware/shopware-cli/logging"
 	"github.com/shopware/shopware-cli/shop"
 )
@@ -101,17 +101,8 @@ var projectConfigPushCmd = &cobra.Command{
 		}
 
 		if !autoApprove {
-			var confirm bool
-
-			confirmForm := huh.NewForm(
-				huh.NewGroup(
-					huh.NewConfirm().
-						Title("You want to apply these changes to your Shop?").
-						Value(&confirm),
-				),
-			)
-
-			if err := confirmForm.Run(); err != nil {
+			confirm, err := interaction.Confirm(cmd.Context(), "You want to apply these changes to your Shop?", "")
+			if err != nil {
 				return err
 			}
 
@@ -19,6 +19,7 @@ import (
 	"github.com/shyim/go-version"
 	"github.com/spf13/cobra"
 
+	"github.com/shopware/shopware-cli/internal/interaction"
 	"github.com/shopware/shopware-cli/logging"
 )
 
@@ -64,6 +65,10 @@ var projectCreateCmd = &cobra.Command{
 		if len(args) == 2 {
 			result = args[1]
 		} else {
+			if err := interaction.RequireInput(cmd.Context(), "the Shopware version", "pass it as second argument, for example: project create "+projectFolder+" latest"); err != nil {
+				return err
+			}
+
 			options := make([]huh.Option[string], 0)
 			for _, v := range filteredVersions {
 				versionStr := v.String()
@@ -0,0 +1,12 @@
+package project
+
+import "github.com/spf13/cobra"
+
+var projectDepsCmd = &cobra.Command{
+	Use:   "deps",
+	Short: "Inspect the composer dependencies of the project",
+}
+
+func init() {
+	projectRootCmd.AddCommand(projectDepsCmd)
+}
@@ -0,0 +1,221 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+	"github.com/shopware/shopware-cli/internal/packagist"
+	"github.com/shopware/shopware-cli/internal/table"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+const storePackagePrefix = "store.shopware.com/"
+
+type dependencyFinding struct {
+	Package string `json:"package"`
+	Version string `json:"version"`
+	License string `json:"license"`
+	Issue   string `json:"issue"`
+}
+
+// storeExtensionLookup returns the store listing of an extension, it is the Shopware Store API in production
+type storeExtensionLookup func(ctx context.Context, name string) (*account_api.StoreExtensionDetail, error)
+
+type dependencyReportOptions struct {
+	majorLag        int
+	maxReleaseAge   time.Duration
+	allowedLicenses []string
+	shopwareVersion string
+	lookupStore     storeExtensionLookup
+}
+
+var projectDepsReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Report abandoned, outdated and unmaintained dependencies of the composer.lock",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		projectRoot, err := findClosestShopwareProject()
+		if err != nil {
+			return err
+		}
+
+		outputAsJson, _ := cmd.Flags().GetBool("json")
+		noDev, _ := cmd.Flags().GetBool("no-dev")
+		majorLag, _ := cmd.Flags().GetInt("major-lag")
+		maxReleaseAge, _ := cmd.Flags().GetInt("max-release-age")
+		allowedLicenses, _ := cmd.Flags().GetStringSlice("allowed-licenses")
+
+		lock, err := packagist.ReadComposerLock(path.Join(projectRoot, "composer.lock"))
+		if err != nil {
+			return fmt.Errorf("failed to read composer.lock: %w", err)
+		}
+
+		options := dependencyReportOptions{
+			majorLag:        majorLag,
+			maxReleaseAge:   time.Duration(maxReleaseAge) * 30 * 24 * time.Hour,
+			allowedLicenses: allowedLicenses,
+			// Without the Shopware version of the project, extensions which do not support it are found too
+			lookupStore: func(ctx context.Context, name string) (*account_api.StoreExtensionDetail, error) {
+				return account_api.GetStoreExtensionDetail(ctx, "", name)
+			},
+		}
+
+		if core := lock.GetPackage("shopware/core"); core != nil {
+			options.shopwareVersion = strings.TrimPrefix(core.Version, "v")
+		}
+
+		packages := lock.Packages
+		if !noDev {
+			packages = append(packages, lock.PackagesDev...)
+		}
+
+		findings := make([]dependencyFinding, 0)
+
+		var mu sync.Mutex
+		var gr errgroup.Group
+		gr.SetLimit(8)
+
+		for _, pkg := range packages {
+			gr.Go(func() error {
+				packageFindings := inspectDependency(cmd.Context(), pkg, options)
+
+				mu.Lock()
+				findings = append(findings, packageFindings...)
+				mu.Unlock()
+
+				return nil
+			})
+		}
+
+		_ = gr.Wait()
+
+		slices.SortStableFunc(findings, func(a, b dependencyFinding) int {
+			return strings.Compare(a.Package, b.Package)
+		})
+
+		if outputAsJson {
+			content, err := json.Marshal(findings)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), string(content))
+
+			return nil
+		}
+
+		if len(findings) == 0 {
+			logging.FromContext(cmd.Context()).Infof("No abandoned, outdated or unmaintained dependencies found")
+			return nil
+		}
+
+		rows := make([][]string, 0, len(findings))
+		for _, finding := range findings {
+			rows = append(rows, []string{finding.Package, finding.Version, finding.License, finding.Issue})
+		}
+
+		return table.RenderTable(cmd.OutOrStdout(), []string{"Package", "Version", "License", "Issue"}, rows)
+	},
+}
+
+// inspectDependency looks up the package on packagist.org or, for store extensions, in the Shopware Store
+func inspectDependency(ctx context.Context, pkg packagist.ComposerLockPackage, options dependencyReportOptions) []dependencyFinding {
+	newFinding := func(issue string) dependencyFinding {
+		return dependencyFinding{Package: pkg.Name, Version: pkg.Version, License: strings.Join(pkg.License, ", "), Issue: issue}
+	}
+
+	findings := make([]dependencyFinding, 0)
+
+	if len(options.allowedLicenses) > 0 && !hasAllowedLicense(pkg.License, options.allowedLicenses) {
+		findings = append(findings, newFinding("license not allowed"))
+	}
+
+	if strings.HasPrefix(pkg.Name, storePackagePrefix) {
+		name := strings.TrimPrefix(pkg.Name, storePackagePrefix)
+
+		detail, err := options.lookupStore(ctx, name)
+		if err != nil {
+			logging.FromContext(ctx).Warnf("Cannot look up %s in the Shopware Store: %v", name, err)
+			return findings
+		}
+
+		if detail == nil {
+			return append(findings, newFinding("not available in the Shopware Store anymore"))
+		}
+
+		if options.shopwareVersion != "" && !detail.HasBinaryForShopwareVersion(options.shopwareVersion) {
+			findings = append(findings, newFinding(fmt.Sprintf("no release compatible with Shopware %s", options.shopwareVersion)))
+		}
+
+		if lastRelease := detail.LastRelease(); options.maxReleaseAge > 0 && !lastRelease.IsZero() && time.Since(lastRelease) > options.maxReleaseAge {
+			findings = append(findings, newFinding(fmt.Sprintf("no store release since %s", lastRelease.Format(time.DateOnly))))
+		}
+
+		return findings
+	}
+
+	abandoned := pkg.Abandoned
+
+	versions, err := packagist.GetPackagistVersions(ctx, pkg.Name)
+	if err != nil && !errors.Is(err, packagist.ErrPackageNotFound) {
+		logging.FromContext(ctx).Warnf("Cannot look up %s on packagist.org: %v", pkg.Name, err)
+	}
+
+	// The newest release carries the current abandoned state, the composer.lock only knows the state at the time of locking
+	if len(versions) > 0 && versions[0].Abandoned != nil {
+		abandoned = versions[0].Abandoned
+	}
+
+	if abandoned != nil && abandoned.Abandoned {
+		issue := "abandoned"
+		if abandoned.Replacement != "" {
+			issue = fmt.Sprintf("abandoned, use %s instead", abandoned.Replacement)
+		}
+
+		findings = append(findings, newFinding(issue))
+	}
+
+	tags := make([]string, 0, len(versions))
+	for _, v := range versions {
+		tags = append(tags, v.Version)
+	}
+
+	latest := packagist.LatestStableVersion(tags)
+
+	if lag := packagist.MajorVersionLag(pkg.Version, latest); options.majorLag > 0 && lag >= options.majorLag {
+		findings = append(findings, newFinding(fmt.Sprintf("%d major versions behind %s", lag, latest.String())))
+	}
+
+	return findings
+}
+
+func hasAllowedLicense(licenses []string, allowed []string) bool {
+	for _, license := range licenses {
+		for _, allowedLicense := range allowed {
+			if strings.EqualFold(license, allowedLicense) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func init() {
+	projectDepsCmd.AddCommand(projectDepsReportCmd)
+	projectDepsReportCmd.Flags().Bool("json", false, "Output as json")
+	projectDepsReportCmd.Flags().Bool("no-dev", false, "Skip the packages-dev of the composer.lock")
+	projectDepsReportCmd.Flags().Int("major-lag", 2, "Report packages which are at least this many major versions behind the latest release, 0 disables the check")
+	projectDepsReportCmd.Flags().Int("max-release-age", 12, "Report store extensions without release in this many months, 0 disables the check")
+	projectDepsReportCmd.Flags().StringSlice("allowed-licenses", []string{}, "Report packages which have none of these licenses")
+}
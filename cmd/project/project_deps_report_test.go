@@ -0,0 +1,77 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+	"github.com/shopware/shopware-cli/internal/packagist"
+)
+
+func storeLookup(detail *account_api.StoreExtensionDetail, err error) storeExtensionLookup {
+	return func(context.Context, string) (*account_api.StoreExtensionDetail, error) {
+		return detail, err
+	}
+}
+
+func storeIssues(findings []dependencyFinding) []string {
+	issues := make([]string, 0, len(findings))
+
+	for _, finding := range findings {
+		issues = append(issues, finding.Issue)
+	}
+
+	return issues
+}
+
+func TestInspectStoreDependency(t *testing.T) {
+	pkg := packagist.ComposerLockPackage{Name: "store.shopware.com/froshtools", Version: "2.0.0"}
+	recent := time.Now().AddDate(0, -1, 0).Format(time.DateTime)
+	old := time.Now().AddDate(-2, 0, 0)
+
+	compatible := account_api.StoreExtensionBinary{Version: "2.0.0", CompatibleSoftwareVersions: account_api.SoftwareVersionList{{Name: "6.6.0.0"}}}
+
+	cases := []struct {
+		name   string
+		lookup storeExtensionLookup
+		issues []string
+	}{
+		{
+			name:   "maintained",
+			lookup: storeLookup(&account_api.StoreExtensionDetail{Changelog: []account_api.StoreExtensionRelease{{CreationDate: recent}}, Binaries: []account_api.StoreExtensionBinary{compatible}}, nil),
+			issues: []string{},
+		},
+		{
+			name:   "removed from the store",
+			lookup: storeLookup(nil, nil),
+			issues: []string{"not available in the Shopware Store anymore"},
+		},
+		{
+			name:   "incompatible",
+			lookup: storeLookup(&account_api.StoreExtensionDetail{Changelog: []account_api.StoreExtensionRelease{{CreationDate: recent}}}, nil),
+			issues: []string{"no release compatible with Shopware 6.6.0.0"},
+		},
+		{
+			name:   "unmaintained",
+			lookup: storeLookup(&account_api.StoreExtensionDetail{Changelog: []account_api.StoreExtensionRelease{{CreationDate: old.Format(time.DateTime)}}, Binaries: []account_api.StoreExtensionBinary{compatible}}, nil),
+			issues: []string{"no store release since " + old.Format(time.DateOnly)},
+		},
+		{
+			name:   "lookup failed",
+			lookup: storeLookup(nil, errors.New("timeout")),
+			issues: []string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			options := dependencyReportOptions{maxReleaseAge: 365 * 24 * time.Hour, shopwareVersion: "6.6.0.0", lookupStore: tc.lookup}
+
+			assert.Equal(t, tc.issues, storeIssues(inspectDependency(t.Context(), pkg, options)))
+		})
+	}
+}
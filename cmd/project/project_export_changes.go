@@ -0,0 +1,152 @@
+package project
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	adminSdk "github.com/friendsofshopware/go-shopware-admin-api-sdk"
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/logging"
+	"github.com/shopware/shopware-cli/shop"
+)
+
+var projectExportChangesCmd = &cobra.Command{
+	Use:   "export-changes",
+	Short: "Export the records of the configured entities changed since the last run as JSONL",
+	Long: `Exports the records of export.entities of the project config created or updated since the last run.
+Each line of the output contains the entity, the id and the record. The time of the shop when the export started
+is stored per entity in the cursor file, the next run continues from there. Records changed around the start of a
+run can be exported twice.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		cfg, err := shop.ReadConfig(projectConfigPath, false)
+		if err != nil {
+			return err
+		}
+
+		if cfg.Export == nil || len(cfg.Export.Entities) == 0 {
+			return fmt.Errorf("no entities configured, add them to export.entities of the project config")
+		}
+
+		since, _ := cmd.Flags().GetString("since")
+		output, _ := cmd.Flags().GetString("output")
+		compression, _ := cmd.Flags().GetString("compression")
+
+		cursorFile := cfg.Export.CursorFile
+		if cursorFile == "" {
+			cursorFile = defaultExportCursorFile
+		}
+
+		cursor, err := readExportCursor(cursorFile)
+		if err != nil {
+			return err
+		}
+
+		client, err := shop.NewShopClient(cmd.Context(), cfg)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+
+		startedAt, err := shopTime(cmd.Context(), client)
+		if err != nil {
+			logging.FromContext(cmd.Context()).Warnf("Cannot read the time of the shop, using the local time for the cursor: %v", err)
+
+			startedAt = now
+		}
+
+		if output == "" {
+			output = fmt.Sprintf("changes-%s.jsonl", now.Format("20060102-150405"))
+		}
+
+		w, output, err := newDumpWriter(output, compression, cmd.OutOrStdout())
+		if err != nil {
+			return err
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Exporting the changes of %s", exportEntityNames(cfg.Export.Entities))
+
+		counts, err := exportChanges(cmd.Context(), adminApiSearcher(client), cfg.Export.Entities, since, cursor, startedAt, w)
+		if err != nil {
+			_ = w.Close()
+
+			return err
+		}
+
+		if err := w.Close(); err != nil {
+			return err
+		}
+
+		// The cursor only advances after the output is complete, a failed run is repeated entirely
+		if err := writeExportCursor(cursorFile, cursor); err != nil {
+			return err
+		}
+
+		for _, entity := range cfg.Export.Entities {
+			logging.FromContext(cmd.Context()).Infof("Exported %d changed records of %s", counts[entity.Entity], entity.Entity)
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Wrote the changes to %s", output)
+
+		return nil
+	},
+}
+
+// adminApiSearcher searches the records with the Admin API, the plain JSON format is requested instead of JSON:API
+func adminApiSearcher(client *adminSdk.Client) exportSearcher {
+	return func(ctx context.Context, entity string, criteria map[string]any) ([]map[string]any, error) {
+		payload, err := json.Marshal(criteria)
+		if err != nil {
+			return nil, err
+		}
+
+		apiContext := adminSdk.NewApiContext(ctx)
+
+		r, err := client.NewRequest(apiContext, "POST", fmt.Sprintf("/api/search/%s", entity), bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+
+		r.Header.Set("Accept", "application/json")
+		r.Header.Set("Content-Type", "application/json")
+
+		var res struct {
+			Data []map[string]any `json:"data"`
+		}
+
+		if _, err := client.Do(ctx, r, &res); err != nil {
+			return nil, err
+		}
+
+		return res.Data, nil
+	}
+}
+
+// shopTime returns the time of the shop from the Date header of a response, so the cursor does not depend on the local clock
+func shopTime(ctx context.Context, client *adminSdk.Client) (time.Time, error) {
+	r, err := client.NewRequest(adminSdk.NewApiContext(ctx), http.MethodGet, "/api/_info/version", nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	resp, err := client.BareDo(ctx, r)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	_ = resp.Body.Close()
+
+	return http.ParseTime(resp.Header.Get("Date"))
+}
+
+func init() {
+	projectRootCmd.AddCommand(projectExportChangesCmd)
+	projectExportChangesCmd.Flags().String("since", exportSinceLastRun, "Export changes since last-run, a date like 2024-01-31 or a duration like 24h or 7d")
+	projectExportChangesCmd.Flags().String("output", "", "File or - (for stdout), defaults to changes-<date>.jsonl")
+	projectExportChangesCmd.Flags().String("compression", "", "Compress the output (gzip, zstd)")
+}
@@ -1,26 +1,42 @@
 package project
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"os"
+	"regexp"
+	"strings"
 
 	adminSdk "github.com/friendsofshopware/go-shopware-admin-api-sdk"
+	"github.com/shyim/go-version"
 	"github.com/spf13/cobra"
 
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
 	"github.com/shopware/shopware-cli/internal/table"
 	"github.com/shopware/shopware-cli/logging"
 	"github.com/shopware/shopware-cli/shop"
 )
 
+var htmlTagRegExp = regexp.MustCompile(`<[^>]*>`)
+
+type outdatedExtension struct {
+	*adminSdk.ExtensionDetail
+	Changelog []account_api.StoreExtensionRelease `json:"changelog"`
+	// Compatible is nil when the store does not know the compatibility of the latest version
+	Compatible *bool `json:"compatible"`
+}
+
 var projectExtensionOutdatedCmd = &cobra.Command{
 	Use:   "outdated",
-	Short: "List all outdated extensions",
+	Short: "List all outdated extensions with the changelog of the store updates",
 	RunE: func(cmd *cobra.Command, _ []string) error {
 		var cfg *shop.Config
 		var err error
 
 		outputAsJson, _ := cmd.PersistentFlags().GetBool("json")
+		noChangelog, _ := cmd.PersistentFlags().GetBool("no-changelog")
 
 		if cfg, err = shop.ReadConfig(projectConfigPath, false); err != nil {
 			return err
@@ -42,8 +58,27 @@ var projectExtensionOutdatedCmd = &cobra.Command{
 			return err
 		}
 
+		shopwareVersion := ""
+		if client.ShopwareVersion != nil {
+			shopwareVersion = client.ShopwareVersion.String()
+		}
+
+		outdated := make([]outdatedExtension, 0, len(extensions))
+
+		for _, extension := range extensions {
+			entry := outdatedExtension{ExtensionDetail: extension}
+
+			if !noChangelog && extension.UpdateSource == "store" {
+				if err := addStoreUpdateInformation(cmd.Context(), &entry, shopwareVersion); err != nil {
+					logging.FromContext(cmd.Context()).Warnf("Cannot fetch the changelog of %s: %v", extension.Name, err)
+				}
+			}
+
+			outdated = append(outdated, entry)
+		}
+
 		if outputAsJson {
-			content, err := json.Marshal(extensions)
+			content, err := json.Marshal(outdated)
 			if err != nil {
 				return err
 			}
@@ -59,19 +94,94 @@ var projectExtensionOutdatedCmd = &cobra.Command{
 		}
 
 		table := table.NewWriter(os.Stdout)
-		table.Header([]string{"Name", "Current Version", "Latest Version", "Update Source"})
+		table.Header([]string{"Name", "Current Version", "Latest Version", "Update Source", "Compatible"})
+
+		for _, extension := range outdated {
+			compatible := "unknown"
+			if extension.Compatible != nil && *extension.Compatible {
+				compatible = "yes"
+			} else if extension.Compatible != nil {
+				compatible = fmt.Sprintf("no, drops Shopware %s", shopwareVersion)
+			}
 
-		for _, extension := range extensions {
-			_ = table.Append([]string{extension.Name, extension.Version, extension.LatestVersion, extension.UpdateSource})
+			_ = table.Append([]string{extension.Name, extension.Version, extension.LatestVersion, extension.UpdateSource, compatible})
 		}
 
 		_ = table.Render()
 
+		for _, extension := range outdated {
+			if len(extension.Changelog) == 0 {
+				continue
+			}
+
+			fmt.Printf("\nChangelog of %s\n", extension.Name)
+
+			for _, release := range extension.Changelog {
+				fmt.Printf("  %s: %s\n", release.Version, changelogPreview(release.Text))
+			}
+		}
+
 		return fmt.Errorf("there are %d outdated extensions", len(extensions))
 	},
 }
 
+// addStoreUpdateInformation adds the changelog entries between the installed and the latest version and the compatibility of the latest version
+func addStoreUpdateInformation(ctx context.Context, entry *outdatedExtension, shopwareVersion string) error {
+	detail, err := account_api.GetStoreExtensionDetail(ctx, shopwareVersion, entry.Name)
+	if err != nil || detail == nil {
+		return err
+	}
+
+	entry.Changelog = changelogBetween(detail.Changelog, entry.Version, entry.LatestVersion)
+
+	if shopwareVersion == "" {
+		return nil
+	}
+
+	if supported, known := detail.SupportsShopwareVersion(entry.LatestVersion, shopwareVersion); known {
+		entry.Compatible = &supported
+	}
+
+	return nil
+}
+
+// changelogBetween returns the releases newer than the installed version up to the latest version
+func changelogBetween(releases []account_api.StoreExtensionRelease, installed, latest string) []account_api.StoreExtensionRelease {
+	installedVersion, err := version.NewVersion(installed)
+	if err != nil {
+		return nil
+	}
+
+	latestVersion, err := version.NewVersion(latest)
+	if err != nil {
+		return nil
+	}
+
+	result := make([]account_api.StoreExtensionRelease, 0)
+
+	for _, release := range releases {
+		releaseVersion, err := version.NewVersion(release.Version)
+		if err != nil {
+			continue
+		}
+
+		if releaseVersion.GreaterThan(installedVersion) && !releaseVersion.GreaterThan(latestVersion) {
+			result = append(result, release)
+		}
+	}
+
+	return result
+}
+
+// changelogPreview converts the HTML changelog of the store into a single line
+func changelogPreview(text string) string {
+	text = html.UnescapeString(htmlTagRegExp.ReplaceAllString(text, " "))
+
+	return strings.Join(strings.Fields(text), " ")
+}
+
 func init() {
 	projectExtensionCmd.AddCommand(projectExtensionOutdatedCmd)
 	projectExtensionOutdatedCmd.PersistentFlags().Bool("json", false, "Output as json")
+	projectExtensionOutdatedCmd.PersistentFlags().Bool("no-changelog", false, "Do not fetch the changelogs and compatibility of store updates")
 }
@@ -0,0 +1,27 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+)
+
+func TestChangelogBetween(t *testing.T) {
+	releases := []account_api.StoreExtensionRelease{
+		{Version: "2.1.0", Text: "<ul><li>Support &amp; fixes</li></ul>"},
+		{Version: "2.0.0", Text: "Breaking changes"},
+		{Version: "1.5.0", Text: "Installed"},
+		{Version: "3.0.0-beta", Text: "Preview"},
+	}
+
+	changelog := changelogBetween(releases, "1.5.0", "2.1.0")
+
+	assert.Len(t, changelog, 2)
+	assert.Equal(t, "2.1.0", changelog[0].Version)
+	assert.Equal(t, "2.0.0", changelog[1].Version)
+	assert.Equal(t, "Support & fixes", changelogPreview(changelog[0].Text))
+
+	assert.Nil(t, changelogBetween(releases, "invalid", "2.1.0"))
+}
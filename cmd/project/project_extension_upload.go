@@ -112,6 +112,10 @@ var projectExtensionUploadCmd = &cobra.Command{
 			}
 
 			// Cleanup not wanted files
+			if err := extension.RemoveZipIgnoredFiles(ext.GetPath(), extCfg.Build.Zip.Pack.Excludes.Patterns); err != nil {
+				return fmt.Errorf("apply zip ignore patterns: %w", err)
+			}
+
 			if err := extension.CleanupExtensionFolder(ext.GetPath(), extCfg.Build.Zip.Pack.Excludes.Paths); err != nil {
 				return fmt.Errorf("cleanup package: %w", err)
 			}
@@ -146,78 +150,15 @@ var projectExtensionUploadCmd = &cobra.Command{
 			return err
 		}
 
-		shopInfo, _, err := client.Info.Info(adminCtx)
-		if err != nil {
-			return fmt.Errorf("cannot get shop info: %w", err)
-		}
-
-		extensions, _, err := client.ExtensionManager.ListAvailableExtensions(adminCtx)
-		if err != nil {
+		if err := shop.UploadExtensionZip(adminCtx, client, name, &buf); err != nil {
 			return err
 		}
 
-		if !shopInfo.IsCloudShop() || extensions.GetByName(name) == nil {
-			if uploadResponse, err := client.ExtensionManager.UploadExtension(adminCtx, &buf); err != nil {
-				return fmt.Errorf("cannot upload extension: %w", err)
-			} else if uploadResponse.StatusCode != 204 {
-				str, err := io.ReadAll(uploadResponse.Body)
-				if err != nil {
-					return fmt.Errorf("cannot upload extension update: %w", err)
-				}
-
-				return fmt.Errorf("cannot upload extension update: %s", string(str))
-			}
-
-			extensions, _, err = client.ExtensionManager.ListAvailableExtensions(adminCtx)
-			if err != nil {
-				return err
-			}
-		} else {
-			if uploadResponse, err := client.ExtensionManager.UploadExtensionUpdateToCloud(adminCtx, name, &buf); err != nil {
-				return fmt.Errorf("cannot upload extension update: %w", err)
-			} else if uploadResponse.StatusCode != 204 {
-				str, err := io.ReadAll(uploadResponse.Body)
-				if err != nil {
-					return fmt.Errorf("cannot upload extension update: %w", err)
-				}
-
-				return fmt.Errorf("cannot upload extension update: %s", string(str))
-			}
-		}
-
 		logging.FromContext(cmd.Context()).Infof("Uploaded extension %s with version %s", name, version.String())
 
-		if _, err := client.ExtensionManager.Refresh(adminCtx); err != nil {
-			return fmt.Errorf("cannot refresh extension list: %w", err)
-		}
-
-		logging.FromContext(cmd.Context()).Infof("Refreshed extension list")
-
 		if doLifecycleEvents {
-			remoteExtension := extensions.GetByName(name)
-
-			if remoteExtension.InstalledAt == nil {
-				if _, err := client.ExtensionManager.InstallExtension(adminCtx, remoteExtension.Type, remoteExtension.Name); err != nil {
-					return fmt.Errorf("cannot install extension: %w", err)
-				}
-
-				logging.FromContext(cmd.Context()).Infof("Installed %s", name)
-			}
-
-			if !remoteExtension.Active {
-				if _, err := client.ExtensionManager.ActivateExtension(adminCtx, remoteExtension.Type, remoteExtension.Name); err != nil {
-					return fmt.Errorf("cannot activate extension: %w", err)
-				}
-
-				logging.FromContext(cmd.Context()).Infof("Activated %s", name)
-			}
-
-			if remoteExtension.IsUpdateAble() {
-				if _, err := client.ExtensionManager.UpdateExtension(adminCtx, remoteExtension.Type, remoteExtension.Name); err != nil {
-					return fmt.Errorf("cannot update extension: %w", err)
-				}
-
-				logging.FromContext(cmd.Context()).Infof("Updated %s from %s to %s", name, remoteExtension.Version, remoteExtension.LatestVersion)
+			if err := shop.ActivateUploadedExtension(adminCtx, client, name); err != nil {
+				return err
 			}
 		}
 
@@ -0,0 +1,116 @@
+package project
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	adminSdk "github.com/friendsofshopware/go-shopware-admin-api-sdk"
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/logging"
+	"github.com/shopware/shopware-cli/shop"
+)
+
+var projectGenerateClientCmd = &cobra.Command{
+	Use:   "client",
+	Short: "Generate typed Admin API clients for the entities of the project extensions",
+	Long: `Reads the entity schema of the shop and generates types and repository helpers for the entities
+defined by the extensions in custom/plugins, custom/static-plugins and custom/apps. Pass --entity to
+generate further entities. Go clients use the GenericRepository of go-shopware-admin-api-sdk.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		lang, _ := cmd.Flags().GetString("lang")
+		output, _ := cmd.Flags().GetString("output")
+		pkg, _ := cmd.Flags().GetString("package")
+		extraEntities, _ := cmd.Flags().GetStringSlice("entity")
+
+		if lang != clientLangGo && lang != clientLangTS {
+			return fmt.Errorf("unsupported language %q, use go or ts", lang)
+		}
+
+		projectRoot, err := findClosestShopwareProject()
+		if err != nil {
+			return err
+		}
+
+		entities, err := findExtensionEntities(projectRoot)
+		if err != nil {
+			return err
+		}
+
+		entities = append(entities, extraEntities...)
+
+		if len(entities) == 0 {
+			return fmt.Errorf("the extensions of the project define no entities, pass them with --entity")
+		}
+
+		cfg, err := shop.ReadConfig(projectConfigPath, false)
+		if err != nil {
+			return err
+		}
+
+		client, err := shop.NewShopClient(cmd.Context(), cfg)
+		if err != nil {
+			return err
+		}
+
+		r, err := client.NewRequest(adminSdk.NewApiContext(cmd.Context()), "GET", "/api/_info/entity-schema.json", nil)
+		if err != nil {
+			return err
+		}
+
+		var content bytes.Buffer
+
+		if _, err := client.Do(cmd.Context(), r, &content); err != nil {
+			return fmt.Errorf("fetch entity schema: %w", err)
+		}
+
+		schema, err := parseEntitySchema(content.Bytes())
+		if err != nil {
+			return err
+		}
+
+		entities, missing := schema.selectEntities(entities)
+
+		if len(missing) > 0 {
+			logging.FromContext(cmd.Context()).Warnf("Skipping entities unknown to the shop, maybe the extension is not installed: %s", strings.Join(missing, ", "))
+		}
+
+		if len(entities) == 0 {
+			return fmt.Errorf("none of the entities is known to the shop")
+		}
+
+		var generated []byte
+
+		if lang == clientLangGo {
+			if generated, err = generateGoClient(schema, entities, pkg); err != nil {
+				return err
+			}
+		} else {
+			generated = generateTSClient(schema, entities)
+		}
+
+		if output == "" {
+			fmt.Print(string(generated))
+
+			return nil
+		}
+
+		if err := os.WriteFile(output, generated, 0o644); err != nil {
+			return err
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Generated %d entities into %s", len(entities), output)
+
+		return nil
+	},
+}
+
+func init() {
+	projectGenerateCmd.AddCommand(projectGenerateClientCmd)
+	projectGenerateClientCmd.Flags().String("lang", clientLangGo, "Language of the client: go or ts")
+	projectGenerateClientCmd.Flags().String("output", "", "Write the client into this file instead of printing it")
+	projectGenerateClientCmd.Flags().String("package", "shopware", "Package name of the Go client")
+	projectGenerateClientCmd.Flags().StringSlice("entity", nil, "Additional entities to generate, like product")
+}
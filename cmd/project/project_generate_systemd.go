@@ -0,0 +1,95 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/logging"
+	"github.com/shopware/shopware-cli/shop"
+)
+
+var projectGenerateSystemdCmd = &cobra.Command{
+	Use:   "systemd",
+	Short: "Generate systemd units for the message consumers and the scheduled task runner",
+	Long: `Generates hardened systemd units for the message consumers and a timer running the scheduled tasks every minute.
+Worker count, queues, memory and time limits are read from the worker section of the project config.
+With --supervisor a supervisor config is generated instead. Without --output the files are printed.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		projectRoot, err := findClosestShopwareProject()
+		if err != nil {
+			return err
+		}
+
+		if projectRoot, err = filepath.Abs(projectRoot); err != nil {
+			return err
+		}
+
+		shopCfg, err := shop.ReadConfig(projectConfigPath, true)
+		if err != nil {
+			return err
+		}
+
+		name, _ := cmd.Flags().GetString("name")
+		php, _ := cmd.Flags().GetString("php")
+		output, _ := cmd.Flags().GetString("output")
+		supervisor, _ := cmd.Flags().GetBool("supervisor")
+
+		if php == "" {
+			if php, err = exec.LookPath("php"); err != nil {
+				php = "/usr/bin/php"
+			}
+		}
+
+		opts := newWorkerUnitOptions(name, projectRoot, php, shopCfg.Worker, defaultConsumerQueues(projectRoot))
+
+		var files []workerUnitFile
+
+		if supervisor {
+			files, err = generateSupervisorConfig(opts)
+		} else {
+			files, err = generateSystemdUnits(opts)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if output == "" {
+			for _, file := range files {
+				fmt.Printf("# %s\n%s\n", file.Name, file.Content)
+			}
+
+			return nil
+		}
+
+		if err := os.MkdirAll(output, os.ModePerm); err != nil {
+			return err
+		}
+
+		for _, file := range files {
+			if err := os.WriteFile(filepath.Join(output, file.Name), []byte(file.Content), 0o644); err != nil {
+				return err
+			}
+
+			logging.FromContext(cmd.Context()).Infof("Created file %s", filepath.Join(output, file.Name))
+		}
+
+		if !supervisor {
+			logging.FromContext(cmd.Context()).Infof("Copy the files to /etc/systemd/system and run: systemctl daemon-reload && systemctl enable --now %s-consumer.target %s-scheduled-task.timer", name, name)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	projectGenerateCmd.AddCommand(projectGenerateSystemdCmd)
+	projectGenerateSystemdCmd.Flags().String("name", "shopware", "Prefix of the unit names")
+	projectGenerateSystemdCmd.Flags().String("php", "", "Path to the PHP binary, defaults to the php found in PATH")
+	projectGenerateSystemdCmd.Flags().String("output", "", "Write the files into this directory instead of printing them")
+	projectGenerateSystemdCmd.Flags().Bool("supervisor", false, "Generate a supervisor config instead of systemd units")
+}
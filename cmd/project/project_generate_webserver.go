@@ -0,0 +1,74 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/logging"
+	"github.com/shopware/shopware-cli/shop"
+)
+
+var projectGenerateWebserverCmd = &cobra.Command{
+	Use:   "webserver",
+	Short: "Generate a nginx or Caddy vhost for the project",
+	Long: `Generates a vhost with the rewrites of Shopware, denied access to dotfiles and PHP files in the media folders
+and cache headers for static files. The host names are taken from the url of the project config,
+PHP-FPM address, aliases, upload size, ESI and the gRPC proxy from the webserver section.
+Without --output the vhost is printed.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		projectRoot, err := findClosestShopwareProject()
+		if err != nil {
+			return err
+		}
+
+		if projectRoot, err = filepath.Abs(projectRoot); err != nil {
+			return err
+		}
+
+		shopCfg, err := shop.ReadConfig(projectConfigPath, true)
+		if err != nil {
+			return err
+		}
+
+		server, _ := cmd.Flags().GetString("server")
+		output, _ := cmd.Flags().GetString("output")
+
+		opts, err := newWebserverOptions(shopCfg.URL, projectRoot, shopCfg.Webserver)
+		if err != nil {
+			return err
+		}
+
+		if phpFPM, _ := cmd.Flags().GetString("php-fpm"); phpFPM != "" {
+			opts.PHPFPM = phpFPM
+		}
+
+		content, err := generateWebserverConfig(server, opts)
+		if err != nil {
+			return err
+		}
+
+		if output == "" {
+			fmt.Print(content)
+
+			return nil
+		}
+
+		if err := os.WriteFile(output, []byte(content), 0o644); err != nil {
+			return err
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Created file %s", output)
+
+		return nil
+	},
+}
+
+func init() {
+	projectGenerateCmd.AddCommand(projectGenerateWebserverCmd)
+	projectGenerateWebserverCmd.Flags().String("server", webserverNginx, "Webserver of the vhost: nginx or caddy")
+	projectGenerateWebserverCmd.Flags().String("php-fpm", "", "Address of PHP-FPM, overrides webserver.php_fpm of the project config")
+	projectGenerateWebserverCmd.Flags().String("output", "", "Write the vhost into this file instead of printing it")
+}
@@ -0,0 +1,12 @@
+package project
+
+import "github.com/spf13/cobra"
+
+var projectLicensesCmd = &cobra.Command{
+	Use:   "licenses",
+	Short: "Inspect the Shopware Store licenses of the shop",
+}
+
+func init() {
+	projectRootCmd.AddCommand(projectLicensesCmd)
+}
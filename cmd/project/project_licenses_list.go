@@ -0,0 +1,210 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	adminSdk "github.com/friendsofshopware/go-shopware-admin-api-sdk"
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/internal/table"
+	"github.com/shopware/shopware-cli/logging"
+	"github.com/shopware/shopware-cli/shop"
+)
+
+// storeLicense is the license the shop fetched from the Shopware Store for an extension
+type storeLicense struct {
+	Id              int    `json:"id"`
+	Variant         string `json:"variant"`
+	PaymentText     string `json:"paymentText"`
+	CreationDate    string `json:"creationDate"`
+	NextBookingDate string `json:"nextBookingDate"`
+	ExpirationDate  string `json:"expirationDate"`
+}
+
+type extensionLicenseStatus struct {
+	Name      string        `json:"name"`
+	Version   string        `json:"version"`
+	Installed bool          `json:"installed"`
+	License   *storeLicense `json:"license"`
+	Status    string        `json:"status"`
+	Problem   bool          `json:"problem"`
+}
+
+var projectLicensesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the store licenses of the shop domain, extensions without license and upcoming rent expirations",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		var cfg *shop.Config
+		var err error
+
+		outputAsJson, _ := cmd.Flags().GetBool("json")
+		expiringWithin, _ := cmd.Flags().GetInt("expiring-within")
+
+		if cfg, err = shop.ReadConfig(projectConfigPath, false); err != nil {
+			return err
+		}
+
+		client, err := shop.NewShopClient(cmd.Context(), cfg)
+		if err != nil {
+			return err
+		}
+
+		apiCtx := adminSdk.NewApiContext(cmd.Context())
+
+		// The refresh fetches the licenses of the shop domain from the Shopware Store
+		if _, err := client.ExtensionManager.Refresh(apiCtx); err != nil {
+			return err
+		}
+
+		extensions, _, err := client.ExtensionManager.ListAvailableExtensions(apiCtx)
+		if err != nil {
+			return err
+		}
+
+		statuses := make([]extensionLicenseStatus, 0, len(extensions))
+		licensed := 0
+
+		for _, ext := range extensions {
+			status, ok := getExtensionLicenseStatus(ext, time.Now(), time.Duration(expiringWithin)*24*time.Hour)
+			if !ok {
+				continue
+			}
+
+			if status.License != nil {
+				licensed++
+			}
+
+			statuses = append(statuses, status)
+		}
+
+		if outputAsJson {
+			content, err := json.Marshal(statuses)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), string(content))
+
+			return licenseProblemsError(statuses)
+		}
+
+		if config, err := readSystemConfig(apiCtx, client, nil); err == nil {
+			for _, row := range config.Data {
+				if row.ConfigurationKey == "core.store.licenseHost" {
+					logging.FromContext(cmd.Context()).Infof("Store licenses of the domain %v", row.ConfigurationValue)
+				}
+			}
+		}
+
+		if licensed == 0 {
+			logging.FromContext(cmd.Context()).Warnf("No store licenses found, make sure the shop is connected to a Shopware Account")
+		}
+
+		rows := make([][]string, 0, len(statuses))
+
+		for _, status := range statuses {
+			variant := "-"
+			if status.License != nil {
+				variant = status.License.Variant
+			}
+
+			rows = append(rows, []string{status.Name, status.Version, variant, status.Status})
+		}
+
+		if err := table.RenderTable(cmd.OutOrStdout(), []string{"Name", "Version", "License", "Status"}, rows); err != nil {
+			return err
+		}
+
+		return licenseProblemsError(statuses)
+	},
+}
+
+// licenseProblemsError fails the command for CI when extensions need attention, for the table and the JSON output alike
+func licenseProblemsError(statuses []extensionLicenseStatus) error {
+	problems := 0
+
+	for _, status := range statuses {
+		if status.Problem {
+			problems++
+		}
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("%d extensions need attention", problems)
+	}
+
+	return nil
+}
+
+// getExtensionLicenseStatus returns the license status of a store extension, extensions not distributed by the store are skipped
+func getExtensionLicenseStatus(ext *adminSdk.ExtensionDetail, now time.Time, expiringWithin time.Duration) (extensionLicenseStatus, bool) {
+	status := extensionLicenseStatus{Name: ext.Name, Version: ext.Version, Installed: ext.InstalledAt != nil}
+
+	if ext.StoreLicense != nil {
+		content, err := json.Marshal(ext.StoreLicense)
+		if err == nil {
+			var license storeLicense
+			if err := json.Unmarshal(content, &license); err == nil {
+				status.License = &license
+			}
+		}
+	}
+
+	if status.License == nil {
+		if ext.StoreExtension == nil || !status.Installed {
+			return status, false
+		}
+
+		status.Status = "installed without license"
+		status.Problem = true
+
+		return status, true
+	}
+
+	license := status.License
+
+	expiration, hasExpiration := parseLicenseDate(license.ExpirationDate)
+	nextBooking, hasNextBooking := parseLicenseDate(license.NextBookingDate)
+
+	switch {
+	case hasExpiration && expiration.Before(now):
+		status.Status = fmt.Sprintf("expired on %s", expiration.Format(time.DateOnly))
+		status.Problem = true
+	case hasExpiration && expiration.Sub(now) <= expiringWithin:
+		status.Status = fmt.Sprintf("expires on %s", expiration.Format(time.DateOnly))
+		status.Problem = true
+	case hasExpiration:
+		status.Status = fmt.Sprintf("active until %s", expiration.Format(time.DateOnly))
+	case hasNextBooking:
+		status.Status = fmt.Sprintf("active, next booking on %s", nextBooking.Format(time.DateOnly))
+	default:
+		status.Status = "active"
+	}
+
+	if !status.Installed {
+		status.Status += ", not installed"
+	}
+
+	return status, true
+}
+
+func parseLicenseDate(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return date, true
+}
+
+func init() {
+	projectLicensesCmd.AddCommand(projectLicensesListCmd)
+	projectLicensesListCmd.Flags().Bool("json", false, "Output as json")
+	projectLicensesListCmd.Flags().Int("expiring-within", 30, "Report licenses which expire within this many days")
+}
@@ -0,0 +1,64 @@
+package project
+
+import (
+	"testing"
+	"time"
+
+	adminSdk "github.com/friendsofshopware/go-shopware-admin-api-sdk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetExtensionLicenseStatus(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	installedAt := &struct {
+		Date         string `json:"date"`
+		TimezoneType int    `json:"timezone_type"`
+		Timezone     string `json:"timezone"`
+	}{Date: "2024-01-01 00:00:00"}
+
+	t.Run("private extension is skipped", func(t *testing.T) {
+		_, ok := getExtensionLicenseStatus(&adminSdk.ExtensionDetail{Name: "MyPlugin", InstalledAt: installedAt}, now, 30*24*time.Hour)
+		assert.False(t, ok)
+	})
+
+	t.Run("store extension without license", func(t *testing.T) {
+		status, ok := getExtensionLicenseStatus(&adminSdk.ExtensionDetail{Name: "SwagPlugin", InstalledAt: installedAt, StoreExtension: map[string]any{"id": 1}}, now, 30*24*time.Hour)
+		assert.True(t, ok)
+		assert.True(t, status.Problem)
+		assert.Equal(t, "installed without license", status.Status)
+	})
+
+	t.Run("rent expires soon", func(t *testing.T) {
+		license := map[string]any{"id": 1, "variant": "rent", "expirationDate": "2025-06-10T00:00:00+00:00"}
+
+		status, ok := getExtensionLicenseStatus(&adminSdk.ExtensionDetail{Name: "SwagPlugin", InstalledAt: installedAt, StoreLicense: license}, now, 30*24*time.Hour)
+		assert.True(t, ok)
+		assert.True(t, status.Problem)
+		assert.Equal(t, "rent", status.License.Variant)
+		assert.Equal(t, "expires on 2025-06-10", status.Status)
+	})
+
+	t.Run("rent with next booking", func(t *testing.T) {
+		license := map[string]any{"id": 1, "variant": "rent", "nextBookingDate": "2025-08-01T00:00:00.000+00:00"}
+
+		status, ok := getExtensionLicenseStatus(&adminSdk.ExtensionDetail{Name: "SwagPlugin", StoreLicense: license}, now, 30*24*time.Hour)
+		assert.True(t, ok)
+		assert.False(t, status.Problem)
+		assert.Equal(t, "active, next booking on 2025-08-01, not installed", status.Status)
+	})
+
+	t.Run("expired license", func(t *testing.T) {
+		license := map[string]any{"id": 1, "variant": "test", "expirationDate": "2025-05-01T00:00:00+00:00"}
+
+		status, _ := getExtensionLicenseStatus(&adminSdk.ExtensionDetail{Name: "SwagPlugin", InstalledAt: installedAt, StoreLicense: license}, now, 30*24*time.Hour)
+		assert.True(t, status.Problem)
+		assert.Equal(t, "expired on 2025-05-01", status.Status)
+	})
+}
+
+func TestLicenseProblemsError(t *testing.T) {
+	assert.NoError(t, licenseProblemsError([]extensionLicenseStatus{{Name: "SwagPlugin"}}))
+
+	err := licenseProblemsError([]extensionLicenseStatus{{Name: "SwagPlugin", Problem: true}, {Name: "SwagOther"}})
+	assert.EqualError(t, err, "1 extensions need attention")
+}
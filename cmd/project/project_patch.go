@@ -0,0 +1,12 @@
+package project
+
+import "github.com/spf13/cobra"
+
+var projectPatchCmd = &cobra.Command{
+	Use:   "patch",
+	Short: "Manage composer patches of vendor packages",
+}
+
+func init() {
+	projectRootCmd.AddCommand(projectPatchCmd)
+}
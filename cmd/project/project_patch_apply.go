@@ -0,0 +1,148 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/internal/system"
+	"github.com/shopware/shopware-cli/internal/table"
+	"github.com/shopware/shopware-cli/internal/vendorpatch"
+)
+
+var projectPatchApplyCmd = &cobra.Command{
+	Use:   "apply [package]",
+	Short: "Apply the composer patches to the vendor folder or check that they apply to the locked versions",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectRoot, err := findClosestShopwareProject()
+		if err != nil {
+			return err
+		}
+
+		check, _ := cmd.Flags().GetBool("check")
+
+		config, err := vendorpatch.ReadPatchConfig(projectRoot)
+		if err != nil {
+			return err
+		}
+
+		packages := []string{}
+
+		for _, patch := range config.Patches {
+			if len(args) == 1 && patch.Package != args[0] {
+				continue
+			}
+
+			if !slices.Contains(packages, patch.Package) {
+				packages = append(packages, patch.Package)
+			}
+		}
+
+		if len(packages) == 0 {
+			return fmt.Errorf("no patches configured")
+		}
+
+		rows := make([][]string, 0)
+		failed := 0
+
+		for _, packageName := range packages {
+			packageRows, packageFailed := applyPackagePatches(cmd, projectRoot, config, packageName, check)
+
+			rows = append(rows, packageRows...)
+			failed += packageFailed
+		}
+
+		if err := table.RenderTable(cmd.OutOrStdout(), []string{"Package", "Description", "Status"}, rows); err != nil {
+			return err
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d patches do not apply", failed)
+		}
+
+		return nil
+	},
+}
+
+// applyPackagePatches applies the patches of the package in their configured order and returns a table row per patch
+func applyPackagePatches(cmd *cobra.Command, projectRoot string, config *vendorpatch.PatchConfig, packageName string, check bool) ([][]string, int) {
+	rows := make([][]string, 0)
+	failed := 0
+
+	dir := path.Join(projectRoot, "vendor", packageName)
+
+	var prepareErr error
+
+	if check {
+		dir, prepareErr = prepareLockedPackage(cmd, projectRoot, packageName)
+
+		defer func() { _ = os.RemoveAll(dir) }()
+	}
+
+	for _, entry := range config.PackagePatches(packageName) {
+		status, err := applyPatchEntry(cmd, config, entry, dir, check, prepareErr)
+		if err != nil {
+			status = err.Error()
+			failed++
+		}
+
+		rows = append(rows, []string{entry.Package, entry.Description, status})
+	}
+
+	return rows, failed
+}
+
+// prepareLockedPackage copies the released version of the locked package into a temporary folder
+func prepareLockedPackage(cmd *cobra.Command, projectRoot, packageName string) (string, error) {
+	pkg, err := vendorpatch.LockedPackage(projectRoot, packageName)
+	if err != nil {
+		return "", err
+	}
+
+	pristineDir, err := vendorpatch.PristinePackage(cmd.Context(), projectRoot, *pkg)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := os.MkdirTemp("", "shopware-cli-patch-*")
+	if err != nil {
+		return "", err
+	}
+
+	return dir, system.CopyFiles(pristineDir, dir)
+}
+
+// applyPatchEntry applies the patch and returns its status, in check mode the patches are applied to a copy of the locked version
+func applyPatchEntry(cmd *cobra.Command, config *vendorpatch.PatchConfig, entry vendorpatch.PatchEntry, dir string, check bool, prepareErr error) (string, error) {
+	if prepareErr != nil {
+		return "", prepareErr
+	}
+
+	patches, err := config.ParseEntry(cmd.Context(), entry)
+	if err != nil {
+		return "", err
+	}
+
+	if !check && vendorpatch.Check(dir, vendorpatch.Reverse(patches)) == nil {
+		return "already applied", nil
+	}
+
+	if err := vendorpatch.Apply(dir, patches); err != nil {
+		return "", err
+	}
+
+	if check {
+		return "applies", nil
+	}
+
+	return "applied", nil
+}
+
+func init() {
+	projectPatchCmd.AddCommand(projectPatchApplyCmd)
+	projectPatchApplyCmd.Flags().Bool("check", false, "Only check that the patches apply to the versions of the composer.lock, without changing the vendor folder")
+}
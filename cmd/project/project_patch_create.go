@@ -0,0 +1,128 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/internal/packagist"
+	"github.com/shopware/shopware-cli/internal/vendorpatch"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var projectPatchCreateCmd = &cobra.Command{
+	Use:   "create <package>",
+	Short: "Create a composer patch from the changes made in the vendor folder of a package",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectRoot, err := findClosestShopwareProject()
+		if err != nil {
+			return err
+		}
+
+		description, _ := cmd.Flags().GetString("description")
+		outputDir, _ := cmd.Flags().GetString("output")
+
+		config, err := vendorpatch.ReadPatchConfig(projectRoot)
+		if err != nil {
+			return err
+		}
+
+		pkg, err := vendorpatch.LockedPackage(projectRoot, args[0])
+		if err != nil {
+			return err
+		}
+
+		// Diff against the released package with the existing patches applied, so the new patch only contains the new changes
+		baselineDir, err := os.MkdirTemp("", "shopware-cli-patch-*")
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = os.RemoveAll(baselineDir) }()
+
+		if err := config.PrepareBaseline(cmd.Context(), *pkg, baselineDir); err != nil {
+			return err
+		}
+
+		installedDir := path.Join(projectRoot, "vendor", pkg.Name)
+
+		changes, err := vendorpatch.Compare(pkg.Name, installedDir, baselineDir)
+		if err != nil {
+			return fmt.Errorf("cannot compare %s: %w", pkg.Name, err)
+		}
+
+		if len(changes) == 0 {
+			logging.FromContext(cmd.Context()).Infof("No changes found in vendor/%s", pkg.Name)
+			return nil
+		}
+
+		patch, skipped, err := vendorpatch.CreatePatch(installedDir, baselineDir, changes)
+		if err != nil {
+			return fmt.Errorf("cannot create patch for %s: %w", pkg.Name, err)
+		}
+
+		for _, file := range skipped {
+			logging.FromContext(cmd.Context()).Warnf("Skipping binary file %s, it cannot be part of a patch", file)
+		}
+
+		if patch == "" {
+			return fmt.Errorf("the changes of %s contain only binary files", pkg.Name)
+		}
+
+		if !filepath.IsAbs(outputDir) {
+			outputDir = filepath.Join(projectRoot, outputDir)
+		}
+
+		if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
+			return err
+		}
+
+		patchFile := uniquePatchFile(outputDir, strings.ReplaceAll(pkg.Name, "/", "-"))
+
+		if err := os.WriteFile(patchFile, []byte(patch), os.ModePerm); err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(projectRoot, patchFile)
+		if err != nil {
+			relPath = patchFile
+		}
+
+		if err := config.Add(vendorpatch.PatchEntry{Package: pkg.Name, Description: description, Source: filepath.ToSlash(relPath)}); err != nil {
+			_ = os.Remove(patchFile)
+			return err
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Created %s with %d changed files and added it to %s", relPath, len(changes)-len(skipped), filepath.Base(config.File))
+
+		if composerJson, err := packagist.ReadComposerJson(path.Join(projectRoot, "composer.json")); err == nil && !composerJson.HasPackage("cweagans/composer-patches") {
+			logging.FromContext(cmd.Context()).Warnf("Require cweagans/composer-patches, otherwise composer install does not apply the patch")
+		}
+
+		return nil
+	},
+}
+
+// uniquePatchFile returns a file name which is not used yet, packages can have multiple patches
+func uniquePatchFile(dir, name string) string {
+	file := filepath.Join(dir, name+".patch")
+
+	for i := 2; ; i++ {
+		if _, err := os.Stat(file); os.IsNotExist(err) {
+			return file
+		}
+
+		file = filepath.Join(dir, fmt.Sprintf("%s-%d.patch", name, i))
+	}
+}
+
+func init() {
+	projectPatchCmd.AddCommand(projectPatchCreateCmd)
+	projectPatchCreateCmd.Flags().String("description", "Local changes", "Description of the patch in the composer.json")
+	projectPatchCreateCmd.Flags().String("output", "patches", "Folder to write the patch to")
+}
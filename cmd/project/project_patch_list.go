@@ -0,0 +1,42 @@
+package project
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/internal/table"
+	"github.com/shopware/shopware-cli/internal/vendorpatch"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var projectPatchListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the composer patches of the project",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		projectRoot, err := findClosestShopwareProject()
+		if err != nil {
+			return err
+		}
+
+		config, err := vendorpatch.ReadPatchConfig(projectRoot)
+		if err != nil {
+			return err
+		}
+
+		if len(config.Patches) == 0 {
+			logging.FromContext(cmd.Context()).Infof("No patches configured")
+			return nil
+		}
+
+		rows := make([][]string, 0, len(config.Patches))
+
+		for _, patch := range config.Patches {
+			rows = append(rows, []string{patch.Package, patch.Description, patch.Source})
+		}
+
+		return table.RenderTable(cmd.OutOrStdout(), []string{"Package", "Description", "Patch"}, rows)
+	},
+}
+
+func init() {
+	projectPatchCmd.AddCommand(projectPatchListCmd)
+}
@@ -0,0 +1,115 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/internal/coretemplate"
+	"github.com/shopware/shopware-cli/internal/table"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var projectTemplateDriftCmd = &cobra.Command{
+	Use:   "template-drift [path]",
+	Short: "List overridden storefront templates which changed between two Shopware versions",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var projectRoot string
+		var err error
+
+		if len(args) == 1 {
+			projectRoot, err = filepath.Abs(args[0])
+			if err != nil {
+				return err
+			}
+		} else if projectRoot, err = findClosestShopwareProject(); err != nil {
+			return err
+		}
+
+		fromVersion, _ := cmd.Flags().GetString("from")
+		toVersion, _ := cmd.Flags().GetString("to")
+
+		overrides := make(map[string][]string)
+
+		for _, ext := range extension.FindExtensionsFromProject(cmd.Context(), projectRoot) {
+			name, err := ext.GetName()
+			if err != nil {
+				continue
+			}
+
+			for _, sourceDir := range ext.GetSourceDirs() {
+				viewsDir := path.Join(sourceDir, "Resources", "views")
+
+				if _, err := os.Stat(viewsDir); os.IsNotExist(err) {
+					continue
+				}
+
+				templates, err := coretemplate.ListTemplates(viewsDir)
+				if err != nil {
+					return fmt.Errorf("cannot list templates of %s: %w", name, err)
+				}
+
+				for _, template := range templates {
+					overrides[template] = append(overrides[template], name)
+				}
+			}
+		}
+
+		for template := range overrides {
+			sort.Strings(overrides[template])
+		}
+
+		if len(overrides) == 0 {
+			logging.FromContext(cmd.Context()).Infof("No installed extension overrides storefront templates")
+			return nil
+		}
+
+		fromCheckout, err := coretemplate.StorefrontCheckout(cmd.Context(), fromVersion)
+		if err != nil {
+			return err
+		}
+
+		toCheckout, err := coretemplate.StorefrontCheckout(cmd.Context(), toVersion)
+		if err != nil {
+			return err
+		}
+
+		entries, err := coretemplate.CalculateDrift(fromCheckout, toCheckout, overrides)
+		if err != nil {
+			return err
+		}
+
+		if len(entries) == 0 {
+			logging.FromContext(cmd.Context()).Infof("None of the overridden templates changed between %s and %s", fromVersion, toVersion)
+			return nil
+		}
+
+		rows := make([][]string, 0, len(entries))
+
+		for _, entry := range entries {
+			changedLines := "-"
+			if entry.Status == coretemplate.DriftStatusChanged {
+				changedLines = strconv.Itoa(entry.ChangedLines)
+			}
+
+			rows = append(rows, []string{entry.Template, entry.Status, changedLines, strings.Join(entry.OverriddenBy, ", ")})
+		}
+
+		return table.RenderTable(cmd.OutOrStdout(), []string{"Template", "Status", "Changed lines", "Overridden by"}, rows)
+	},
+}
+
+func init() {
+	projectRootCmd.AddCommand(projectTemplateDriftCmd)
+	projectTemplateDriftCmd.Flags().String("from", "", "Shopware version before the update")
+	projectTemplateDriftCmd.Flags().String("to", "", "Shopware version after the update")
+	_ = projectTemplateDriftCmd.MarkFlagRequired("from")
+	_ = projectTemplateDriftCmd.MarkFlagRequired("to")
+}
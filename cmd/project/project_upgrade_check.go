@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/huh"
@@ -15,14 +16,16 @@ import (
 
 	"github.com/shopware/shopware-cli/extension"
 	account_api "github.com/shopware/shopware-cli/internal/account-api"
+	"github.com/shopware/shopware-cli/internal/interaction"
 	"github.com/shopware/shopware-cli/internal/packagist"
 	"github.com/shopware/shopware-cli/logging"
 	"github.com/shopware/shopware-cli/shop"
 )
 
 var projectUpgradeCheckCmd = &cobra.Command{
-	Use:   "upgrade-check",
+	Use:   "upgrade-check [version]",
 	Short: "Check that installed extensions are compatible with a future Shopware version",
+	Args:  cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var cfg *shop.Config
 		var err error
@@ -93,16 +96,28 @@ var projectUpgradeCheckCmd = &cobra.Command{
 
 		var selectedVersion string
 
-		prompt := huh.NewSelect[string]().
-			Height(10).
-			Title("Select a Shopware version to check compatibility").
-			Options(
-				huh.NewOptions(possibleVersions...)...,
-			).
-			Value(&selectedVersion)
+		if len(args) == 1 {
+			if !slices.Contains(possibleVersions, args[0]) {
+				return fmt.Errorf("version %s is not available, choose one of: %s", args[0], strings.Join(possibleVersions, ", "))
+			}
 
-		if err := prompt.Run(); err != nil {
-			return err
+			selectedVersion = args[0]
+		} else {
+			if err := interaction.RequireInput(cmd.Context(), "the Shopware version", "pass one of these versions as argument: "+strings.Join(possibleVersions, ", ")); err != nil {
+				return err
+			}
+
+			prompt := huh.NewSelect[string]().
+				Height(10).
+				Title("Select a Shopware version to check compatibility").
+				Options(
+					huh.NewOptions(possibleVersions...)...,
+				).
+				Value(&selectedVersion)
+
+			if err := prompt.Run(); err != nil {
+				return err
+			}
 		}
 
 		if selectedVersion == "" {
@@ -6,7 +6,6 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/cobra"
-	"golang.org/x/sync/errgroup"
 
 	"github.com/shopware/shopware-cli/internal/system"
 	"github.com/shopware/shopware-cli/internal/verifier"
@@ -67,10 +66,6 @@ var projectValidateCmd = &cobra.Command{
 			return err
 		}
 
-		result := verifier.NewCheck()
-
-		var gr errgroup.Group
-
 		tools := verifier.GetTools()
 
 		tools, err = tools.Only(only)
@@ -78,24 +73,18 @@ var projectValidateCmd = &cobra.Command{
 			return err
 		}
 
-		for _, tool := range tools {
-			tool := tool
-			gr.Go(func() error {
-				return tool.Check(cmd.Context(), result, *toolCfg)
-			})
-		}
-
-		if err := gr.Wait(); err != nil {
+		result, err := tools.Run(cmd.Context(), *toolCfg, nil)
+		if err != nil {
 			return err
 		}
 
-		return verifier.DoCheckReport(result.RemoveByIdentifier(toolCfg.ValidationIgnores), reportingFormat)
+		return verifier.DoCheckReport(cmd.Context(), result.RemoveByIdentifier(toolCfg.ValidationIgnores), reportingFormat, toolCfg.RootDir)
 	},
 }
 
 func init() {
 	projectRootCmd.AddCommand(projectValidateCmd)
-	projectValidateCmd.PersistentFlags().String("reporter", "", "Reporting format (summary, json, github, junit, markdown)")
+	projectValidateCmd.PersistentFlags().String("reporter", "", "Reporting format (summary, json, sarif, github, junit, markdown, pr-comment)")
 	projectValidateCmd.PersistentFlags().String("only", "", "Run only specific tools by name (comma-separated, e.g. phpstan,eslint)")
 	projectValidateCmd.PersistentFlags().Bool("no-copy", false, "Do not copy project files to temporary directory")
 }
@@ -68,11 +68,7 @@ var projectWorkerCmd = &cobra.Command{
 		}
 
 		if queuesToConsume == "" {
-			if is, _ := shop.IsShopwareVersion(projectRoot, ">=6.5.7"); is {
-				consumeArgs = append(consumeArgs, "async", "failed", "low_priority")
-			} else if is, _ := shop.IsShopwareVersion(projectRoot, ">=6.5"); is {
-				consumeArgs = append(consumeArgs, "async", "failed")
-			}
+			consumeArgs = append(consumeArgs, defaultConsumerQueues(projectRoot)...)
 		} else {
 			consumeArgs = append(consumeArgs, strings.Split(queuesToConsume, ",")...)
 		}
@@ -140,6 +136,19 @@ func init() {
 	projectWorkerCmd.PersistentFlags().Uint("limit", 0, "Messages Limit")
 }
 
+// defaultConsumerQueues returns the queues of the Shopware version of the project
+func defaultConsumerQueues(projectRoot string) []string {
+	if is, _ := shop.IsShopwareVersion(projectRoot, ">=6.5.7"); is {
+		return []string{"async", "failed", "low_priority"}
+	}
+
+	if is, _ := shop.IsShopwareVersion(projectRoot, ">=6.5"); is {
+		return []string{"async", "failed"}
+	}
+
+	return nil
+}
+
 func cancelOnTermination(ctx context.Context, cancel context.CancelFunc) {
 	logging.FromContext(ctx).Infof("setting up a signal handler")
 	s := make(chan os.Signal, 1)
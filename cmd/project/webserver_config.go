@@ -0,0 +1,320 @@
+package project
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/shopware/shopware-cli/shop"
+)
+
+const (
+	webserverNginx = "nginx"
+	webserverCaddy = "caddy"
+)
+
+// webserverOptions are the values of the generated vhost, the defaults are applied by newWebserverOptions
+type webserverOptions struct {
+	Hosts        []string
+	HTTPS        bool
+	DocumentRoot string
+	PHPFPM       string
+	MaxBodySize  int
+	ESI          bool
+	GRPC         *shop.ConfigWebserverGRPC
+}
+
+func newWebserverOptions(shopURL, projectRoot string, cfg *shop.ConfigWebserver) (webserverOptions, error) {
+	if shopURL == "" {
+		return webserverOptions{}, errors.New("the url of the project config is required to generate the vhost")
+	}
+
+	u, err := url.Parse(shopURL)
+	if err != nil {
+		return webserverOptions{}, fmt.Errorf("cannot parse url %s: %w", shopURL, err)
+	}
+
+	if u.Hostname() == "" {
+		return webserverOptions{}, fmt.Errorf("the url %s has no host", shopURL)
+	}
+
+	opts := webserverOptions{
+		Hosts:        []string{u.Hostname()},
+		HTTPS:        u.Scheme == "https",
+		DocumentRoot: filepath.Join(projectRoot, "public"),
+		PHPFPM:       "unix:/run/php/php-fpm.sock",
+		MaxBodySize:  128,
+	}
+
+	if cfg == nil {
+		return opts, nil
+	}
+
+	opts.Hosts = append(opts.Hosts, cfg.Aliases...)
+
+	if cfg.PHPFPM != "" {
+		opts.PHPFPM = cfg.PHPFPM
+	}
+
+	if cfg.MaxBodySize > 0 {
+		opts.MaxBodySize = cfg.MaxBodySize
+	}
+
+	opts.ESI = cfg.ESI
+
+	if cfg.GRPC != nil {
+		if cfg.GRPC.Path == "" || cfg.GRPC.Address == "" {
+			return webserverOptions{}, errors.New("webserver.grpc requires a path and an address")
+		}
+
+		if !strings.HasPrefix(cfg.GRPC.Path, "/") {
+			return webserverOptions{}, fmt.Errorf("the gRPC path %s has to start with a slash", cfg.GRPC.Path)
+		}
+
+		opts.GRPC = cfg.GRPC
+	}
+
+	return opts, nil
+}
+
+// CaddyPHPFPM converts the nginx notation of unix sockets to the one of Caddy
+func (o webserverOptions) CaddyPHPFPM() string {
+	if socket, ok := strings.CutPrefix(o.PHPFPM, "unix:"); ok {
+		return "unix/" + socket
+	}
+
+	return o.PHPFPM
+}
+
+func (o webserverOptions) CaddyAddresses() string {
+	addresses := make([]string, 0, len(o.Hosts))
+
+	for _, host := range o.Hosts {
+		// Caddy enables HTTPS for every host name, the scheme keeps plain HTTP setups behind a proxy working
+		if !o.HTTPS {
+			host = "http://" + host
+		}
+
+		addresses = append(addresses, host)
+	}
+
+	return strings.Join(addresses, ", ")
+}
+
+// The locations follow the Shopware documentation: PHP is only executed through index.php and the installer,
+// dotfiles and PHP files in the public media folders are denied and static files are cached by the browser.
+// With ESI the Surrogate-Capability header tells Shopware to render ESI includes for the proxy in front of the vhost,
+// gRPC requests are proxied over HTTP/2 which is also enabled for plain HTTP then
+const nginxTemplate = `{{- if .HTTPS -}}
+server {
+    listen 80;
+    listen [::]:80;
+    server_name {{ join .Hosts " " }};
+
+    return 301 https://$host$request_uri;
+}
+
+{{ end -}}
+server {
+{{- if .HTTPS }}
+    listen 443 ssl;
+    listen [::]:443 ssl;
+    http2 on;
+
+    ssl_certificate /etc/letsencrypt/live/{{ index .Hosts 0 }}/fullchain.pem;
+    ssl_certificate_key /etc/letsencrypt/live/{{ index .Hosts 0 }}/privkey.pem;
+{{- else }}
+    listen 80;
+    listen [::]:80;
+{{- if .GRPC }}
+    http2 on;
+{{- end }}
+{{- end }}
+    server_name {{ join .Hosts " " }};
+
+    root {{ .DocumentRoot }};
+    index index.php;
+
+    client_max_body_size {{ .MaxBodySize }}M;
+
+    # Certificate challenges stay reachable
+    location ^~ /.well-known/ {
+        try_files $uri =404;
+    }
+{{- if .GRPC }}
+
+    location ^~ {{ .GRPC.Path }} {
+        grpc_pass grpc://{{ .GRPC.Address }};
+    }
+{{- end }}
+
+    # Deny access to dotfiles like .env and .htaccess
+    location ~ /\. {
+        deny all;
+    }
+
+    # Uploaded files are never executed
+    location ~ ^/(media|thumbnail|theme|bundles|sitemap)/.*\.(php|phtml|phar)$ {
+        deny all;
+    }
+
+    location /shopware-installer.phar.php {
+        try_files $uri /shopware-installer.phar.php$is_args$args;
+    }
+
+    location ~ ^/shopware-installer\.phar\.php/.+\.(?:css|js|png|svg|woff)$ {
+        try_files $uri /shopware-installer.phar.php$is_args$args;
+    }
+
+    location ~ ^/(theme|media|thumbnail|bundles|css|fonts|js|recovery|sitemap)/ {
+        expires 1y;
+        add_header Cache-Control "public, must-revalidate, proxy-revalidate";
+        access_log off;
+        log_not_found off;
+        tcp_nodelay off;
+        open_file_cache max=3000 inactive=120s;
+        open_file_cache_valid 45s;
+        open_file_cache_min_uses 2;
+        open_file_cache_errors off;
+
+        location ~* ^.+\.svg$ {
+            add_header Content-Security-Policy "script-src 'none'";
+            add_header Cache-Control "public, must-revalidate, proxy-revalidate";
+        }
+
+        try_files $uri /index.php$is_args$args;
+    }
+
+    location ~* ^.+\.(?:css|cur|js|jpe?g|gif|ico|png|svg|webp|avif|woff|woff2|xml)$ {
+        expires 1y;
+        add_header Cache-Control "public, must-revalidate, proxy-revalidate";
+        access_log off;
+        log_not_found off;
+
+        try_files $uri /index.php$is_args$args;
+    }
+
+    location / {
+        try_files $uri /index.php$is_args$args;
+    }
+
+    location ~ ^/(index|shopware-installer\.phar)\.php(/|$) {
+        fastcgi_split_path_info ^(.+\.php)(/.+)$;
+        include fastcgi.conf;
+        fastcgi_param HTTP_PROXY "";
+{{- if .ESI }}
+        fastcgi_param HTTP_SURROGATE_CAPABILITY 'shopware="ESI/1.0"';
+{{- end }}
+        fastcgi_buffers 8 16k;
+        fastcgi_buffer_size 32k;
+        fastcgi_read_timeout 300s;
+        client_body_buffer_size 128k;
+        fastcgi_pass {{ .PHPFPM }};
+    }
+
+    # All other PHP files are not reachable
+    location ~ \.php$ {
+        return 404;
+    }
+}
+`
+
+const caddyTemplate = `{{- if and .GRPC (not .HTTPS) -}}
+{
+    servers {
+        protocols h1 h2 h2c
+    }
+}
+
+{{ end -}}
+{{ .CaddyAddresses }} {
+    root * {{ .DocumentRoot }}
+
+    request_body {
+        max_size {{ .MaxBodySize }}MB
+    }
+
+    # Deny access to dotfiles like .env, certificate challenges stay reachable
+    @dotfiles {
+        path_regexp /\.
+        not path /.well-known/*
+    }
+    respond @dotfiles 403
+
+    # Uploaded files are never executed
+    @uploadedPhp path_regexp ^/(media|thumbnail|theme|bundles|sitemap)/.*\.(php|phtml|phar)$
+    respond @uploadedPhp 403
+
+    # PHP is only executed through index.php and the installer
+    @otherPhp {
+        path_regexp \.(php|phtml|phar)(/|$)
+        not path /index.php /index.php/* /shopware-installer.phar.php /shopware-installer.phar.php/*
+    }
+    respond @otherPhp 404
+{{- if .GRPC }}
+
+    handle {{ .GRPC.Path }}* {
+        reverse_proxy h2c://{{ .GRPC.Address }}
+    }
+{{- end }}
+
+    @svg {
+        file
+        path *.svg
+    }
+    header @svg Content-Security-Policy "script-src 'none'"
+
+    @static {
+        file
+        path /theme/* /media/* /thumbnail/* /bundles/* /css/* /fonts/* /js/* /recovery/* /sitemap/*
+    }
+    header @static Cache-Control "public, max-age=31536000, must-revalidate, proxy-revalidate"
+
+    # The storefront is rendered by index.php, directories are never executed or listed
+    rewrite / /index.php?{query}
+
+    encode zstd gzip
+{{- if .ESI }}
+
+    request_header Surrogate-Capability "shopware=\"ESI/1.0\""
+{{- end }}
+
+    php_fastcgi {{ .CaddyPHPFPM }} {
+        index off
+        try_files {path} /index.php?{query}
+    }
+
+    file_server
+}
+`
+
+// generateWebserverConfig renders the vhost for nginx or Caddy
+func generateWebserverConfig(server string, opts webserverOptions) (string, error) {
+	var text string
+
+	switch server {
+	case webserverNginx:
+		text = nginxTemplate
+	case webserverCaddy:
+		text = caddyTemplate
+	default:
+		return "", fmt.Errorf("unsupported webserver %s, use %s or %s", server, webserverNginx, webserverCaddy)
+	}
+
+	tpl, err := template.New(server).Funcs(template.FuncMap{"join": strings.Join}).Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+
+	if err := tpl.Execute(&buf, opts); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
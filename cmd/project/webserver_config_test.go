@@ -0,0 +1,96 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/shop"
+)
+
+func TestNewWebserverOptions(t *testing.T) {
+	opts, err := newWebserverOptions("https://shop.example.com:8443/de", "/var/www/shop", &shop.ConfigWebserver{
+		Aliases:     []string{"www.shop.example.com"},
+		PHPFPM:      "127.0.0.1:9000",
+		MaxBodySize: 64,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"shop.example.com", "www.shop.example.com"}, opts.Hosts)
+	assert.True(t, opts.HTTPS)
+	assert.Equal(t, "/var/www/shop/public", opts.DocumentRoot)
+	assert.Equal(t, "127.0.0.1:9000", opts.PHPFPM)
+	assert.Equal(t, 64, opts.MaxBodySize)
+
+	_, err = newWebserverOptions("", "/var/www/shop", nil)
+	assert.ErrorContains(t, err, "url of the project config is required")
+}
+
+func TestGenerateNginxConfig(t *testing.T) {
+	opts, err := newWebserverOptions("https://shop.example.com", "/var/www/shop", nil)
+	require.NoError(t, err)
+
+	content, err := generateWebserverConfig(webserverNginx, opts)
+	require.NoError(t, err)
+
+	assert.Contains(t, content, "return 301 https://$host$request_uri;")
+	assert.Contains(t, content, "ssl_certificate /etc/letsencrypt/live/shop.example.com/fullchain.pem;")
+	assert.Contains(t, content, "root /var/www/shop/public;")
+	assert.Contains(t, content, "client_max_body_size 128M;")
+	assert.Contains(t, content, "location ~ ^/(media|thumbnail|theme|bundles|sitemap)/.*\\.(php|phtml|phar)$ {\n        deny all;")
+	assert.Contains(t, content, "fastcgi_pass unix:/run/php/php-fpm.sock;")
+
+	opts.HTTPS = false
+
+	content, err = generateWebserverConfig(webserverNginx, opts)
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^server \{\n`, content)
+	assert.NotContains(t, content, "ssl_certificate")
+}
+
+func TestGenerateCaddyConfig(t *testing.T) {
+	opts, err := newWebserverOptions("http://shop.test", "/var/www/shop", &shop.ConfigWebserver{Aliases: []string{"www.shop.test"}})
+	require.NoError(t, err)
+
+	content, err := generateWebserverConfig(webserverCaddy, opts)
+	require.NoError(t, err)
+
+	assert.Contains(t, content, "http://shop.test, http://www.shop.test {\n")
+	assert.Contains(t, content, "php_fastcgi unix//run/php/php-fpm.sock {")
+	assert.Contains(t, content, "max_size 128MB")
+	assert.Contains(t, content, "not path /index.php /index.php/* /shopware-installer.phar.php /shopware-installer.phar.php/*\n    }\n    respond @otherPhp 404")
+	assert.NotContains(t, content, "Surrogate-Capability")
+	assert.NotContains(t, content, "h2c")
+}
+
+func TestGenerateWebserverConfigESIAndGRPC(t *testing.T) {
+	opts, err := newWebserverOptions("http://shop.test", "/var/www/shop", &shop.ConfigWebserver{
+		ESI:  true,
+		GRPC: &shop.ConfigWebserverGRPC{Path: "/my.package.Service/", Address: "127.0.0.1:50051"},
+	})
+	require.NoError(t, err)
+
+	content, err := generateWebserverConfig(webserverNginx, opts)
+	require.NoError(t, err)
+
+	assert.Contains(t, content, "listen 80;\n    listen [::]:80;\n    http2 on;\n")
+	assert.Contains(t, content, "location ^~ /my.package.Service/ {\n        grpc_pass grpc://127.0.0.1:50051;")
+	assert.Contains(t, content, `fastcgi_param HTTP_SURROGATE_CAPABILITY 'shopware="ESI/1.0"';`)
+
+	content, err = generateWebserverConfig(webserverCaddy, opts)
+	require.NoError(t, err)
+
+	assert.Contains(t, content, "protocols h1 h2 h2c")
+	assert.Contains(t, content, "handle /my.package.Service/* {\n        reverse_proxy h2c://127.0.0.1:50051")
+	assert.Contains(t, content, `request_header Surrogate-Capability "shopware=\"ESI/1.0\""`)
+
+	_, err = newWebserverOptions("http://shop.test", "/var/www/shop", &shop.ConfigWebserver{GRPC: &shop.ConfigWebserverGRPC{Path: "grpc"}})
+	assert.ErrorContains(t, err, "webserver.grpc requires a path and an address")
+}
+
+func TestGenerateWebserverConfigUnsupported(t *testing.T) {
+	_, err := generateWebserverConfig("apache", webserverOptions{})
+	assert.ErrorContains(t, err, "unsupported webserver apache")
+}
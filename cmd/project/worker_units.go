@@ -0,0 +1,256 @@
+package project
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/shopware/shopware-cli/shop"
+)
+
+// workerUnitOptions are the values of the generated unit files, the defaults are applied by newWorkerUnitOptions
+type workerUnitOptions struct {
+	Name        string
+	ProjectRoot string
+	PHP         string
+	Count       int
+	Queues      []string
+	MemoryLimit string
+	TimeLimit   int
+	MemoryMax   string
+	User        string
+}
+
+type workerUnitFile struct {
+	Name    string
+	Content string
+}
+
+func newWorkerUnitOptions(name, projectRoot, php string, cfg *shop.ConfigWorker, defaultQueues []string) workerUnitOptions {
+	opts := workerUnitOptions{
+		Name:        name,
+		ProjectRoot: strings.TrimSuffix(projectRoot, "/"),
+		PHP:         php,
+		Count:       1,
+		Queues:      defaultQueues,
+		MemoryLimit: "512M",
+		TimeLimit:   120,
+		User:        "www-data",
+	}
+
+	if cfg == nil {
+		return opts
+	}
+
+	if cfg.Count > 0 {
+		opts.Count = cfg.Count
+	}
+
+	if len(cfg.Queues) > 0 {
+		opts.Queues = cfg.Queues
+	}
+
+	if cfg.MemoryLimit != "" {
+		opts.MemoryLimit = cfg.MemoryLimit
+	}
+
+	if cfg.TimeLimit > 0 {
+		opts.TimeLimit = cfg.TimeLimit
+	}
+
+	if cfg.User != "" {
+		opts.User = cfg.User
+	}
+
+	opts.MemoryMax = cfg.MemoryMax
+
+	return opts
+}
+
+func (o workerUnitOptions) ConsumerCommand() string {
+	args := []string{
+		o.PHP,
+		o.ProjectRoot + "/bin/console",
+		"messenger:consume",
+		fmt.Sprintf("--memory-limit=%s", o.MemoryLimit),
+		fmt.Sprintf("--time-limit=%d", o.TimeLimit),
+		"--failure-limit=5",
+	}
+
+	return strings.Join(append(args, o.Queues...), " ")
+}
+
+// ScheduledTaskCommand runs the due tasks once, the systemd timer starts it every minute
+func (o workerUnitOptions) ScheduledTaskCommand() string {
+	return fmt.Sprintf("%s %s/bin/console scheduled-task:run --no-wait --memory-limit=%s --time-limit=%d", o.PHP, o.ProjectRoot, o.MemoryLimit, o.TimeLimit)
+}
+
+// ScheduledTaskDaemonCommand keeps running and waits for the next due task, as supervisor has no timers
+func (o workerUnitOptions) ScheduledTaskDaemonCommand() string {
+	return fmt.Sprintf("%s %s/bin/console scheduled-task:run --memory-limit=%s --time-limit=%d", o.PHP, o.ProjectRoot, o.MemoryLimit, o.TimeLimit)
+}
+
+func (o workerUnitOptions) ConsumerInstances() string {
+	instances := make([]string, 0, o.Count)
+
+	for i := 1; i <= o.Count; i++ {
+		instances = append(instances, fmt.Sprintf("%s-consumer@%d.service", o.Name, i))
+	}
+
+	return strings.Join(instances, " ")
+}
+
+// systemdHardening restricts the services to what PHP needs, the project folder stays writable
+const systemdHardening = `NoNewPrivileges=true
+PrivateTmp=true
+PrivateDevices=true
+ProtectSystem=full
+ProtectKernelTunables=true
+ProtectKernelModules=true
+ProtectKernelLogs=true
+ProtectControlGroups=true
+ProtectClock=true
+ProtectHostname=true
+RestrictSUIDSGID=true
+RestrictRealtime=true
+RestrictNamespaces=true
+LockPersonality=true
+SystemCallArchitectures=native`
+
+var systemdUnitTemplates = []struct {
+	name     string
+	template string
+}{
+	{
+		name: "{{ .Name }}-consumer.target",
+		template: `[Unit]
+Description=Shopware message consumers
+Wants={{ .ConsumerInstances }}
+
+[Install]
+WantedBy=multi-user.target
+`,
+	},
+	{
+		name: "{{ .Name }}-consumer@.service",
+		template: `[Unit]
+Description=Shopware message consumer %i
+PartOf={{ .Name }}-consumer.target
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=simple
+User={{ .User }}
+WorkingDirectory={{ .ProjectRoot }}
+Environment=MESSENGER_CONSUMER_NAME={{ .Name }}-consumer-%i
+ExecStart={{ .ConsumerCommand }}
+Restart=always
+RestartSec=5
+{{- if .MemoryMax }}
+MemoryMax={{ .MemoryMax }}
+{{- end }}
+` + systemdHardening + `
+`,
+	},
+	{
+		name: "{{ .Name }}-scheduled-task.service",
+		template: `[Unit]
+Description=Shopware scheduled task runner
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=oneshot
+User={{ .User }}
+WorkingDirectory={{ .ProjectRoot }}
+ExecStart={{ .ScheduledTaskCommand }}
+{{- if .MemoryMax }}
+MemoryMax={{ .MemoryMax }}
+{{- end }}
+` + systemdHardening + `
+`,
+	},
+	{
+		name: "{{ .Name }}-scheduled-task.timer",
+		template: `[Unit]
+Description=Run the Shopware scheduled tasks every minute
+
+[Timer]
+OnCalendar=minutely
+AccuracySec=1s
+Unit={{ .Name }}-scheduled-task.service
+
+[Install]
+WantedBy=timers.target
+`,
+	},
+}
+
+const supervisorTemplate = `[program:{{ .Name }}-consumer]
+command={{ .ConsumerCommand }}
+directory={{ .ProjectRoot }}
+user={{ .User }}
+numprocs={{ .Count }}
+process_name=%(program_name)s_%(process_num)02d
+environment=MESSENGER_CONSUMER_NAME="{{ .Name }}-consumer-%(process_num)02d"
+autostart=true
+autorestart=true
+startsecs=0
+stopwaitsecs={{ .TimeLimit }}
+
+[program:{{ .Name }}-scheduled-task]
+command={{ .ScheduledTaskDaemonCommand }}
+directory={{ .ProjectRoot }}
+user={{ .User }}
+autostart=true
+autorestart=true
+startsecs=0
+stopwaitsecs={{ .TimeLimit }}
+`
+
+// generateSystemdUnits returns a template unit for the consumers started by a target and a timer for the scheduled tasks
+func generateSystemdUnits(opts workerUnitOptions) ([]workerUnitFile, error) {
+	files := make([]workerUnitFile, 0, len(systemdUnitTemplates))
+
+	for _, unit := range systemdUnitTemplates {
+		name, err := renderWorkerUnit(unit.name, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := renderWorkerUnit(unit.template, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, workerUnitFile{Name: name, Content: content})
+	}
+
+	return files, nil
+}
+
+func generateSupervisorConfig(opts workerUnitOptions) ([]workerUnitFile, error) {
+	content, err := renderWorkerUnit(supervisorTemplate, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return []workerUnitFile{{Name: opts.Name + ".conf", Content: content}}, nil
+}
+
+func renderWorkerUnit(text string, opts workerUnitOptions) (string, error) {
+	tpl, err := template.New("unit").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+
+	if err := tpl.Execute(&buf, opts); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
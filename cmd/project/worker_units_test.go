@@ -0,0 +1,62 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/shop"
+)
+
+func TestNewWorkerUnitOptionsDefaults(t *testing.T) {
+	opts := newWorkerUnitOptions("shopware", "/var/www/shop/", "/usr/bin/php", nil, []string{"async", "failed"})
+
+	assert.Equal(t, "/var/www/shop", opts.ProjectRoot)
+	assert.Equal(t, 1, opts.Count)
+	assert.Equal(t, "512M", opts.MemoryLimit)
+	assert.Equal(t, 120, opts.TimeLimit)
+	assert.Equal(t, "www-data", opts.User)
+	assert.Equal(t, "/usr/bin/php /var/www/shop/bin/console messenger:consume --memory-limit=512M --time-limit=120 --failure-limit=5 async failed", opts.ConsumerCommand())
+}
+
+func TestGenerateSystemdUnits(t *testing.T) {
+	opts := newWorkerUnitOptions("shop", "/var/www/shop", "/usr/bin/php", &shop.ConfigWorker{
+		Count:     3,
+		Queues:    []string{"async"},
+		TimeLimit: 300,
+		MemoryMax: "1G",
+		User:      "shop",
+	}, []string{"async", "failed"})
+
+	files, err := generateSystemdUnits(opts)
+	require.NoError(t, err)
+
+	require.Len(t, files, 4)
+	assert.Equal(t, "shop-consumer.target", files[0].Name)
+	assert.Contains(t, files[0].Content, "Wants=shop-consumer@1.service shop-consumer@2.service shop-consumer@3.service\n")
+
+	assert.Equal(t, "shop-consumer@.service", files[1].Name)
+	assert.Contains(t, files[1].Content, "ExecStart=/usr/bin/php /var/www/shop/bin/console messenger:consume --memory-limit=512M --time-limit=300 --failure-limit=5 async\n")
+	assert.Contains(t, files[1].Content, "User=shop\n")
+	assert.Contains(t, files[1].Content, "RestartSec=5\nMemoryMax=1G\nNoNewPrivileges=true\n")
+
+	assert.Equal(t, "shop-scheduled-task.service", files[2].Name)
+	assert.Contains(t, files[2].Content, "scheduled-task:run --no-wait --memory-limit=512M --time-limit=300\n")
+
+	assert.Equal(t, "shop-scheduled-task.timer", files[3].Name)
+	assert.Contains(t, files[3].Content, "Unit=shop-scheduled-task.service\n")
+}
+
+func TestGenerateSupervisorConfig(t *testing.T) {
+	opts := newWorkerUnitOptions("shopware", "/var/www/shop", "/usr/bin/php", &shop.ConfigWorker{Count: 2}, nil)
+
+	files, err := generateSupervisorConfig(opts)
+	require.NoError(t, err)
+
+	require.Len(t, files, 1)
+	assert.Equal(t, "shopware.conf", files[0].Name)
+	assert.Contains(t, files[0].Content, "[program:shopware-consumer]\n")
+	assert.Contains(t, files[0].Content, "numprocs=2\n")
+	assert.Contains(t, files[0].Content, "command=/usr/bin/php /var/www/shop/bin/console scheduled-task:run --memory-limit=512M --time-limit=120\n")
+}
@@ -9,15 +9,21 @@ import (
 
 	"github.com/shopware/shopware-cli/cmd/account"
 	"github.com/shopware/shopware-cli/cmd/extension"
+	"github.com/shopware/shopware-cli/cmd/images"
+	"github.com/shopware/shopware-cli/cmd/platform"
 	"github.com/shopware/shopware-cli/cmd/project"
 	accountApi "github.com/shopware/shopware-cli/internal/account-api"
 	"github.com/shopware/shopware-cli/internal/config"
+	"github.com/shopware/shopware-cli/internal/interaction"
 	"github.com/shopware/shopware-cli/logging"
 )
 
 var (
 	cfgFile string
+	// version, commit and date are set with -ldflags by the release build
 	version = "dev"
+	commit  = ""
+	date    = ""
 )
 
 var rootCmd = &cobra.Command{
@@ -28,8 +34,22 @@ var rootCmd = &cobra.Command{
 }
 
 func Execute(ctx context.Context) {
-	ctx = logging.WithLogger(ctx, logging.NewLogger(slices.Contains(os.Args, "--verbose")))
+	args, aliasErr := expandAliases(os.Args[1:])
+
+	ctx = logging.WithLogger(ctx, logging.NewLogger(slices.Contains(args, "--verbose")))
+
+	if aliasErr != nil {
+		logging.FromContext(ctx).Fatalln(aliasErr)
+	}
+
+	ctx = interaction.WithMode(ctx, interaction.Mode{
+		NonInteractive: slices.Contains(args, "--"+interaction.NonInteractiveFlag),
+		AssumeYes:      slices.Contains(args, "--"+interaction.YesFlag) || slices.Contains(args, "-y"),
+	})
 	accountApi.SetUserAgent("shopware-cli/" + version)
+	accountApi.SetHTTPTrace(slices.Contains(args, "--verbose-http") || os.Getenv("SHOPWARE_CLI_HTTP_DEBUG") == "1" || os.Getenv("SHOPWARE_CLI_HTTP_DEBUG") == "true")
+
+	rootCmd.SetArgs(args)
 
 	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		logging.FromContext(ctx).Fatalln(err)
@@ -45,16 +65,21 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.shopware-cli.yaml)")
 	rootCmd.PersistentFlags().Bool("verbose", false, "show debug output")
+	rootCmd.PersistentFlags().Bool(interaction.NonInteractiveFlag, false, "never prompt, fail with instructions when input is required (also enabled by SHOPWARE_CLI_NON_INTERACTIVE or CI)")
+	rootCmd.PersistentFlags().BoolP(interaction.YesFlag, "y", false, "accept all confirmations, implies --non-interactive")
+	rootCmd.PersistentFlags().Bool("verbose-http", false, "log every Shopware Account API request (also enabled by SHOPWARE_CLI_HTTP_DEBUG)")
 
 	project.Register(rootCmd)
 	extension.Register(rootCmd)
+	platform.Register(rootCmd)
+	images.Register(rootCmd)
 	account.Register(rootCmd, func(commandName string) (*account.ServiceContainer, error) {
 		err := config.InitConfig(cfgFile)
 		if err != nil {
 			return nil, err
 		}
 		conf := config.Config{}
-		if commandName == "login" || commandName == "logout" {
+		if commandName == "login" || commandName == "logout" || commandName == "mock-server" || commandName == "preview" {
 			return &account.ServiceContainer{
 				Conf:          conf,
 				AccountClient: nil,
@@ -1 +1,85 @@
 package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/internal/buildinfo"
+	"github.com/shopware/shopware-cli/internal/color"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show the version, commit and build date of shopware-cli",
+	Long: `Shows the version, commit, build date, Go version and platform of this binary.
+With --check the newest release is fetched from GitHub, --json prints everything for inventory tooling.`,
+	Example: `  shopware-cli version
+  shopware-cli version --json --check`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		info := buildinfo.Read(version, commit, date)
+
+		check, _ := cmd.Flags().GetBool("check")
+		outputAsJson, _ := cmd.Flags().GetBool("json")
+
+		if check {
+			client := &http.Client{Timeout: 10 * time.Second}
+
+			if err := info.CheckLatest(cmd.Context(), client, buildinfo.LatestReleaseURL); err != nil {
+				if !outputAsJson {
+					return err
+				}
+
+				logging.FromContext(cmd.Context()).Warnf("Could not check for a newer release: %v", err)
+			}
+		}
+
+		if outputAsJson {
+			content, err := json.MarshalIndent(info, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(content))
+
+			return nil
+		}
+
+		fmt.Printf("shopware-cli %s\n", info.Version)
+		fmt.Printf("Commit:     %s\n", valueOrUnknown(info.Commit))
+		fmt.Printf("Build date: %s\n", valueOrUnknown(info.Date))
+		fmt.Printf("Go version: %s\n", info.GoVersion)
+		fmt.Printf("Platform:   %s\n", info.Platform)
+
+		if info.Latest != "" {
+			status := color.GreenText.Render("up to date")
+			if info.UpdateAvailable {
+				status = color.YellowText.Render("update available")
+			}
+
+			fmt.Printf("Latest:     %s (%s)\n", info.Latest, status)
+		}
+
+		return nil
+	},
+}
+
+func valueOrUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+
+	return value
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+	rootCmd.Version = buildinfo.Read(version, commit, date).Version
+	versionCmd.Flags().Bool("json", false, "Output as json")
+	versionCmd.Flags().Bool("check", false, "Fetch the newest release from GitHub and report whether an update is available")
+}
@@ -0,0 +1,186 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/deprecation"
+)
+
+// adminComponentCallRegExp matches Component.register('name'), Component.extend('name', 'base') and Component.override('name')
+var adminComponentCallRegExp = regexp.MustCompile("\\bComponent\\.(register|extend|override)\\(\\s*['\"`]([\\w-]+)['\"`](?:\\s*,\\s*['\"`]([\\w-]+)['\"`])?")
+
+// adminComponentCall is a registration, extension or override of an administration component
+type adminComponentCall struct {
+	method   string
+	name     string
+	base     string
+	location fileLocation
+}
+
+// validateAdminComponents reports administration components which are registered multiple times and overrides of components
+// which were removed in the Shopware versions allowed by the composer constraint, both break the administration
+func validateAdminComponents(ctx context.Context, vc *ValidationContext) {
+	calls := findAdminComponentCalls(vc.Extension)
+	if len(calls) == 0 {
+		return
+	}
+
+	reportAdminComponentDuplicates(vc, calls)
+
+	if _, err := vc.Extension.GetShopwareVersionConstraint(); err != nil {
+		return
+	}
+
+	// A broken database is already reported by validateDeprecatedAPIUsage
+	db, err := deprecation.NewDatabase()
+	if err != nil {
+		return
+	}
+
+	maxVersion := getMaxSupportedShopwareVersion(ctx, vc.Extension, db)
+	if maxVersion == nil {
+		return
+	}
+
+	reportAdminComponentOverrides(vc, calls, db, maxVersion)
+}
+
+// findAdminComponentCalls returns the component calls of the administration sources. Extensions shipped without the sources
+// are checked with the compiled files instead.
+func findAdminComponentCalls(ext Extension) []adminComponentCall {
+	rootDir := ext.GetRootDir()
+	calls := []adminComponentCall{}
+
+	for _, resourcesDir := range ext.GetResourcesDirs() {
+		dir := path.Join(resourcesDir, "app", "administration", "src")
+		if _, err := os.Stat(dir); err != nil {
+			dir = path.Join(resourcesDir, "public", "administration")
+		}
+
+		_ = filepath.WalkDir(dir, func(file string, d os.DirEntry, err error) error {
+			if err != nil {
+				return nil //nolint:nilerr
+			}
+
+			if d.IsDir() {
+				if d.Name() == "node_modules" {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			if ext := filepath.Ext(file); ext != ".js" && ext != ".ts" {
+				return nil
+			}
+
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return nil //nolint:nilerr
+			}
+
+			relPath := strings.TrimPrefix(file, rootDir+"/")
+
+			for i, line := range strings.Split(string(content), "\n") {
+				for _, match := range adminComponentCallRegExp.FindAllStringSubmatch(line, -1) {
+					calls = append(calls, adminComponentCall{
+						method:   match[1],
+						name:     match[2],
+						base:     match[3],
+						location: fileLocation{relPath, i + 1},
+					})
+				}
+			}
+
+			return nil
+		})
+	}
+
+	return calls
+}
+
+// adminComponentOverrideTargets returns the components overridden or extended in the line
+func adminComponentOverrideTargets(line string) []string {
+	var targets []string
+
+	for _, match := range adminComponentCallRegExp.FindAllStringSubmatch(line, -1) {
+		switch {
+		case match[1] == "override":
+			targets = append(targets, match[2])
+		case match[1] == "extend" && match[3] != "":
+			targets = append(targets, match[3])
+		}
+	}
+
+	return targets
+}
+
+func reportAdminComponentDuplicates(vc *ValidationContext, calls []adminComponentCall) {
+	registered := map[string]fileLocation{}
+
+	for _, call := range calls {
+		if call.method == "override" {
+			continue
+		}
+
+		if first, ok := registered[call.name]; ok {
+			vc.AddErrorAt("admin.component_duplicate", call.location, fmt.Sprintf("the administration component %s is already registered in %s, only one of them is used", call.name, first))
+			continue
+		}
+
+		registered[call.name] = call.location
+
+		if strings.HasPrefix(call.name, "sw-") {
+			vc.AddWarningAt("admin.component_core_name", call.location, fmt.Sprintf("the administration component %s uses the sw- prefix of Shopware and can replace a core component, use Component.override to change a core component or prefix the name with the extension", call.name))
+		}
+	}
+}
+
+func reportAdminComponentOverrides(vc *ValidationContext, calls []adminComponentCall, db *deprecation.Database, maxVersion *version.Version) {
+	for _, call := range calls {
+		var target, subject string
+
+		switch {
+		case call.method == "override":
+			target = call.name
+			subject = fmt.Sprintf("overrides the administration component %s", target)
+		case call.method == "extend" && call.base != "":
+			target = call.base
+			subject = fmt.Sprintf("the administration component %s extends %s", call.name, target)
+		default:
+			continue
+		}
+
+		entry, ok := db.Lookup(deprecation.KindComponent, target)
+		if !ok {
+			continue
+		}
+
+		replacement := ""
+		if entry.Replacement != "" {
+			replacement = fmt.Sprintf(", use %s instead", entry.Replacement)
+		}
+
+		if entry.Removed != "" && maxVersion.GreaterThanOrEqual(version.Must(version.NewVersion(entry.Removed))) {
+			vc.AddErrorAt("admin.override_removed", call.location, fmt.Sprintf("%s, which was removed in Shopware %s, but the extension claims to support Shopware %s%s", subject, entry.Removed, maxVersion.String(), replacement))
+			continue
+		}
+
+		if entry.Deprecated != "" && maxVersion.GreaterThanOrEqual(version.Must(version.NewVersion(entry.Deprecated))) {
+			removal := ""
+			if entry.Removed != "" {
+				removal = fmt.Sprintf(" and will be removed in Shopware %s", entry.Removed)
+			}
+
+			vc.AddWarningAt("admin.override_deprecated", call.location, fmt.Sprintf("%s, which is deprecated since Shopware %s%s%s", subject, entry.Deprecated, removal, replacement))
+		}
+	}
+}
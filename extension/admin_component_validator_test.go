@@ -0,0 +1,109 @@
+package extension
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/shyim/go-version"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shopware/shopware-cli/internal/deprecation"
+)
+
+func newAdminComponentTestPlugin(t *testing.T, files map[string]string) PlatformPlugin {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+
+	for file, content := range files {
+		file = path.Join(tmpDir, "src", "Resources", file)
+
+		assert.NoError(t, os.MkdirAll(path.Dir(file), os.ModePerm))
+		assert.NoError(t, os.WriteFile(file, []byte(content), os.ModePerm))
+	}
+
+	ext := PlatformPlugin{path: tmpDir, config: &Config{}}
+	ext.Composer.Autoload.Psr4 = map[string]string{"MyPlugin\\": "src/"}
+
+	return ext
+}
+
+func TestAdminComponentDuplicates(t *testing.T) {
+	ext := newAdminComponentTestPlugin(t, map[string]string{
+		"app/administration/src/component/my-list/index.js": `Shopware.Component.register('my-list', {
+    template,
+});
+`,
+		"app/administration/src/component/my-grid/index.js": `const { Component } = Shopware;
+
+Component.extend('my-list', 'sw-grid', {});
+Component.register("sw-custom-field", {});
+Component.override('my-list', {});
+`,
+		"public/administration/js/my-plugin.js": `Shopware.Component.register("my-list",{})`,
+	})
+
+	calls := findAdminComponentCalls(ext)
+	assert.Len(t, calls, 4)
+
+	vc := newValidationContext(ext)
+	reportAdminComponentDuplicates(vc, calls)
+
+	assert.Len(t, vc.Errors(), 1)
+	assert.Equal(t, "admin.component_duplicate", vc.Errors()[0].Identifier)
+	assert.Contains(t, vc.Errors()[0].Message, "the administration component my-list is already registered in ")
+
+	assert.Len(t, vc.Warnings(), 1)
+	assert.Equal(t, "Resources/app/administration/src/component/my-grid/index.js:4: the administration component sw-custom-field uses the sw- prefix of Shopware and can replace a core component, use Component.override to change a core component or prefix the name with the extension", vc.Warnings()[0].Message)
+}
+
+func TestAdminComponentCallsOfCompiledFiles(t *testing.T) {
+	ext := newAdminComponentTestPlugin(t, map[string]string{
+		"public/administration/js/my-plugin.js": `(()=>{Shopware.Component.register("my-list",{});Shopware.Component.override("sw-button",{})})();`,
+	})
+
+	calls := findAdminComponentCalls(ext)
+	assert.Equal(t, []adminComponentCall{
+		{method: "register", name: "my-list", location: fileLocation{"Resources/public/administration/js/my-plugin.js", 1}},
+		{method: "override", name: "sw-button", location: fileLocation{"Resources/public/administration/js/my-plugin.js", 1}},
+	}, calls)
+}
+
+func TestAdminComponentOverrides(t *testing.T) {
+	ext := newAdminComponentTestPlugin(t, map[string]string{
+		"app/administration/src/main.js": `Shopware.Component.override('sw-button', {});
+Shopware.Component.extend('my-button', 'sw-button', {});
+Shopware.Component.override('sw-page', {});
+`,
+	})
+
+	db, err := deprecation.NewDatabase()
+	assert.NoError(t, err)
+
+	calls := findAdminComponentCalls(ext)
+
+	t.Run("removed in supported version", func(t *testing.T) {
+		vc := newValidationContext(ext)
+		reportAdminComponentOverrides(vc, calls, db, version.Must(version.NewVersion("6.7.0.0")))
+
+		assert.Len(t, vc.Errors(), 2)
+		assert.Equal(t, "admin.override_removed", vc.Errors()[0].Identifier)
+		assert.Equal(t, "Resources/app/administration/src/main.js:1: overrides the administration component sw-button, which was removed in Shopware 6.7.0.0, but the extension claims to support Shopware 6.7.0.0, use mt-button instead", vc.Errors()[0].Message)
+		assert.Contains(t, vc.Errors()[1].Message, "main.js:2: the administration component my-button extends sw-button, which was removed")
+	})
+
+	t.Run("deprecated in supported version", func(t *testing.T) {
+		vc := newValidationContext(ext)
+		reportAdminComponentOverrides(vc, calls, db, version.Must(version.NewVersion("6.6.4.0")))
+
+		assert.Empty(t, vc.Errors())
+		assert.Len(t, vc.Warnings(), 2)
+		assert.Equal(t, "admin.override_deprecated", vc.Warnings()[0].Identifier)
+		assert.Contains(t, vc.Warnings()[0].Message, "which is deprecated since Shopware 6.6.0.0 and will be removed in Shopware 6.7.0.0, use mt-button instead")
+	})
+
+	t.Run("not reported twice as deprecated API usage", func(t *testing.T) {
+		assert.Empty(t, findDeprecatedAPIUsages(ext, db))
+	})
+}
@@ -73,6 +73,11 @@ func (a App) GetName() (string, error) {
 	return a.manifest.Meta.Name, nil
 }
 
+// GetSetup returns the registration of the app backend, nil for apps without backend
+func (a App) GetSetup() *Setup {
+	return a.manifest.Setup
+}
+
 func (a App) GetVersion() (*version.Version, error) {
 	return version.NewVersion(a.manifest.Meta.Version)
 }
@@ -150,9 +155,13 @@ func (a App) GetMetaData() *extensionMetadata {
 	}
 }
 
-func (a App) Validate(_ context.Context, ctx *ValidationContext) {
+func (a App) Validate(c context.Context, ctx *ValidationContext) {
 	validateTheme(ctx)
 
+	validateAppManifest(c, ctx)
+
+	validateAppScripts(c, ctx)
+
 	validateExtensionIcon(ctx)
 
 	allowedTwigLocations := []string{filepath.Join(a.GetRootDir(), "Resources", "views"), filepath.Join(a.GetRootDir(), "Resources", "scripts")}
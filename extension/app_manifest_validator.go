@@ -0,0 +1,507 @@
+package extension
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// manifestURLTimeout limits the reachability check of a single endpoint
+const manifestURLTimeout = 5 * time.Second
+
+var (
+	manifestEntityRegExp      = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	manifestEntityEventRegExp = regexp.MustCompile(`^([a-z][a-z0-9_]*)\.(written|deleted)$`)
+)
+
+// manifestSchemaElement describes an element of the manifest-2.0.xsd. Elements without children are not checked deeper.
+type manifestSchemaElement struct {
+	// since is the first Shopware version supporting the element
+	since    string
+	children map[string]manifestSchemaElement
+}
+
+var manifestSchema = manifestSchemaElement{
+	children: map[string]manifestSchemaElement{
+		"meta": {children: map[string]manifestSchemaElement{
+			"name": {}, "label": {}, "description": {}, "author": {}, "copyright": {}, "version": {}, "icon": {},
+			"license": {}, "compatibility": {}, "privacy": {}, "privacyPolicyExtensions": {},
+		}},
+		"setup": {children: map[string]manifestSchemaElement{
+			"registrationUrl": {}, "secret": {},
+		}},
+		"admin": {children: map[string]manifestSchemaElement{
+			"action-button": {}, "module": {}, "main-module": {}, "base-app-url": {},
+		}},
+		"storefront": {children: map[string]manifestSchemaElement{
+			"template-load-priority": {},
+		}},
+		"permissions": {children: map[string]manifestSchemaElement{
+			"read": {}, "create": {}, "update": {}, "delete": {}, "permission": {},
+		}},
+		"allowed-hosts": {children: map[string]manifestSchemaElement{
+			"host": {},
+		}},
+		"custom-fields": {},
+		"webhooks": {children: map[string]manifestSchemaElement{
+			"webhook": {},
+		}},
+		"cookies": {},
+		"payments": {since: "6.4.1.0", children: map[string]manifestSchemaElement{
+			"payment-method": {children: map[string]manifestSchemaElement{
+				"identifier": {}, "name": {}, "description": {}, "pay-url": {}, "finalize-url": {}, "validate-url": {},
+				"capture-url": {}, "refund-url": {}, "recurring-url": {}, "icon": {},
+			}},
+		}},
+		"rule-conditions":  {since: "6.4.12.0"},
+		"shipping-methods": {since: "6.5.7.0"},
+		"tax": {since: "6.5.0.0", children: map[string]manifestSchemaElement{
+			"tax-provider": {children: map[string]manifestSchemaElement{
+				"identifier": {}, "name": {}, "priority": {}, "process-url": {},
+			}},
+		}},
+		"gateways": {since: "6.6.3.0", children: map[string]manifestSchemaElement{
+			"checkout": {},
+		}},
+	},
+}
+
+// manifestRequiredElements must exist in every manifest.xml, author, copyright and license are checked by the app validation already
+var manifestRequiredElements = []string{"meta/name", "meta/label", "meta/version"}
+
+// manifestURLElements contain endpoints of the app server, as path of the element and the attribute or empty for the text
+var manifestURLElements = []struct {
+	path      string
+	attribute string
+}{
+	{path: "setup/registrationUrl"},
+	{path: "admin/action-button", attribute: "url"},
+	{path: "admin/module", attribute: "source"},
+	{path: "admin/main-module", attribute: "source"},
+	{path: "admin/base-app-url"},
+	{path: "webhooks/webhook", attribute: "url"},
+	{path: "payments/payment-method/pay-url"},
+	{path: "payments/payment-method/finalize-url"},
+	{path: "payments/payment-method/validate-url"},
+	{path: "payments/payment-method/capture-url"},
+	{path: "payments/payment-method/refund-url"},
+	{path: "payments/payment-method/recurring-url"},
+	{path: "tax/tax-provider/process-url"},
+	{path: "gateways/checkout"},
+}
+
+// manifestElement is an element of the manifest.xml with its slash separated path below the manifest element
+type manifestElement struct {
+	path     string
+	line     int
+	attrs    map[string]string
+	text     string
+	children []*manifestElement
+}
+
+type manifestURL struct {
+	element *manifestElement
+	url     string
+}
+
+// validateAppManifest validates the manifest.xml of an app against the schema of the supported Shopware versions,
+// checks the permissions and the endpoints of webhooks, payment methods and tax providers
+func validateAppManifest(ctx context.Context, vc *ValidationContext) {
+	file := filepath.Join(vc.Extension.GetPath(), "manifest.xml")
+
+	relPath, err := filepath.Rel(vc.Extension.GetRootDir(), file)
+	if err != nil {
+		relPath = file
+	}
+
+	elements, line, err := readManifestElements(file)
+	if err != nil {
+		vc.AddErrorAt("xml.syntax", fileLocation{relPath, line}, err.Error())
+		return
+	}
+
+	validateManifestSchema(vc, relPath, elements, getMinManifestVersion(ctx, vc.Extension))
+	validateManifestPermissions(vc, relPath, elements)
+	validateManifestProviders(vc, relPath, elements)
+
+	urls := validateManifestURLs(vc, relPath, elements)
+
+	if cfg := vc.Extension.GetExtensionConfig(); cfg != nil && cfg.Validation.App.CheckUrls {
+		checkManifestURLsReachable(ctx, vc, relPath, urls)
+	}
+}
+
+// getMinManifestVersion returns the lowest Shopware version the app supports, or nil when it cannot be determined
+func getMinManifestVersion(ctx context.Context, ext Extension) *version.Version {
+	constraint, err := ext.GetShopwareVersionConstraint()
+	if err != nil {
+		return nil
+	}
+
+	versions, err := GetShopwareVersions(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Debugf("Could not fetch Shopware versions, skipping the version check of the manifest.xml: %v", err)
+		return nil
+	}
+
+	var minVersion *version.Version
+
+	for _, r := range versions {
+		v, err := version.NewVersion(r)
+		if err != nil || v.Prerelease() != "" || !constraint.Check(v) {
+			continue
+		}
+
+		if minVersion == nil || v.LessThan(minVersion) {
+			minVersion = v
+		}
+	}
+
+	return minVersion
+}
+
+func validateManifestSchema(vc *ValidationContext, relPath string, elements []*manifestElement, minVersion *version.Version) {
+	found := map[string]bool{}
+
+	for _, element := range elements {
+		found[element.path] = true
+
+		schema, known, checked := lookupManifestSchema(element.path)
+		if !checked {
+			continue
+		}
+
+		// The schema is maintained by hand and newer Shopware versions may allow more elements, Shopware rejects the others on install
+		if !known {
+			vc.AddWarningAt("manifest.schema_unknown", fileLocation{relPath, element.line}, fmt.Sprintf("the element %s is not known in %s, check the name against the manifest schema", filepath.Base(element.path), manifestParentName(element.path)))
+			continue
+		}
+
+		if schema.since == "" || minVersion == nil {
+			continue
+		}
+
+		if since := version.Must(version.NewVersion(schema.since)); minVersion.LessThan(since) {
+			vc.AddErrorAt("manifest.schema", fileLocation{relPath, element.line}, fmt.Sprintf("the element %s requires Shopware %s, but the app supports Shopware %s. Raise the compatibility in the manifest.xml", element.path, schema.since, minVersion.String()))
+		}
+	}
+
+	for _, required := range manifestRequiredElements {
+		if !found[required] {
+			vc.AddErrorAt("manifest.schema", fileLocation{relPath, 0}, fmt.Sprintf("the required element %s is missing", required))
+		}
+	}
+}
+
+// lookupManifestSchema returns the schema of the element. checked is false when the content of a parent is not described.
+func lookupManifestSchema(elementPath string) (schema manifestSchemaElement, known bool, checked bool) {
+	schema = manifestSchema
+
+	for _, name := range strings.Split(elementPath, "/") {
+		if schema.children == nil {
+			return schema, true, false
+		}
+
+		child, ok := schema.children[name]
+		if !ok {
+			return schema, false, true
+		}
+
+		schema = child
+	}
+
+	return schema, true, true
+}
+
+func manifestParentName(elementPath string) string {
+	if !strings.Contains(elementPath, "/") {
+		return "manifest"
+	}
+
+	return filepath.Base(filepath.Dir(elementPath))
+}
+
+func validateManifestPermissions(vc *ValidationContext, relPath string, elements []*manifestElement) {
+	granted := map[string]map[string]int{}
+
+	for _, element := range elements {
+		privilege, ok := strings.CutPrefix(element.path, "permissions/")
+		if !ok || privilege == "permission" {
+			continue
+		}
+
+		entity := strings.TrimSpace(element.text)
+
+		if !manifestEntityRegExp.MatchString(entity) {
+			vc.AddErrorAt("manifest.permission", fileLocation{relPath, element.line}, fmt.Sprintf("%q is not a valid entity name for the %s permission", entity, privilege))
+			continue
+		}
+
+		if granted[privilege] == nil {
+			granted[privilege] = map[string]int{}
+		}
+
+		if firstLine, ok := granted[privilege][entity]; ok {
+			vc.AddWarningAt("manifest.permission", fileLocation{relPath, element.line}, fmt.Sprintf("the %s permission for %s is already granted in line %d", privilege, entity, firstLine))
+			continue
+		}
+
+		granted[privilege][entity] = element.line
+	}
+
+	for _, element := range elements {
+		privilege, ok := strings.CutPrefix(element.path, "permissions/")
+		entity := strings.TrimSpace(element.text)
+
+		if !ok || privilege == "read" || privilege == "permission" || granted[privilege][entity] != element.line {
+			continue
+		}
+
+		if _, ok := granted["read"][entity]; !ok {
+			vc.AddWarningAt("manifest.permission", fileLocation{relPath, element.line}, fmt.Sprintf("the app may %s %s, but has no read permission for it", privilege, entity))
+		}
+	}
+
+	for _, element := range elements {
+		if element.path != "webhooks/webhook" {
+			continue
+		}
+
+		match := manifestEntityEventRegExp.FindStringSubmatch(element.attrs["event"])
+		if match == nil {
+			continue
+		}
+
+		if _, ok := granted["read"][match[1]]; !ok {
+			vc.AddErrorAt("manifest.permission", fileLocation{relPath, element.line}, fmt.Sprintf("the webhook %s listens to %s, which requires the read permission for %s", element.attrs["name"], match[0], match[1]))
+		}
+	}
+}
+
+// validateManifestProviders checks the definitions of payment methods and tax providers
+func validateManifestProviders(vc *ValidationContext, relPath string, elements []*manifestElement) {
+	paymentIdentifiers := map[string]int{}
+	taxIdentifiers := map[string]int{}
+
+	for _, element := range elements {
+		switch element.path {
+		case "payments/payment-method":
+			identifier := element.childText("identifier")
+
+			validateManifestProviderIdentifier(vc, "manifest.payment", "payment method", relPath, element, identifier, paymentIdentifiers)
+
+			if element.childText("name") == "" {
+				vc.AddErrorAt("manifest.payment", fileLocation{relPath, element.line}, fmt.Sprintf("the payment method %s has no name", identifier))
+			}
+
+			if element.childText("finalize-url") != "" && element.childText("pay-url") == "" {
+				vc.AddErrorAt("manifest.payment", fileLocation{relPath, element.line}, fmt.Sprintf("the payment method %s has a finalize-url without pay-url, Shopware only calls the finalize-url of asynchronous payments", identifier))
+			}
+		case "tax/tax-provider":
+			identifier := element.childText("identifier")
+
+			validateManifestProviderIdentifier(vc, "manifest.tax", "tax provider", relPath, element, identifier, taxIdentifiers)
+
+			if element.childText("process-url") == "" {
+				vc.AddErrorAt("manifest.tax", fileLocation{relPath, element.line}, fmt.Sprintf("the tax provider %s has no process-url", identifier))
+			}
+		}
+	}
+}
+
+func validateManifestProviderIdentifier(vc *ValidationContext, identifier, kind, relPath string, element *manifestElement, name string, seen map[string]int) {
+	if name == "" {
+		vc.AddErrorAt(identifier, fileLocation{relPath, element.line}, fmt.Sprintf("the %s has no identifier", kind))
+		return
+	}
+
+	if firstLine, ok := seen[name]; ok {
+		vc.AddErrorAt(identifier, fileLocation{relPath, element.line}, fmt.Sprintf("the %s identifier %s is already used in line %d", kind, name, firstLine))
+		return
+	}
+
+	seen[name] = element.line
+}
+
+// validateManifestURLs checks that all endpoints are absolute HTTPS URLs and returns them for the reachability check
+func validateManifestURLs(vc *ValidationContext, relPath string, elements []*manifestElement) []manifestURL {
+	urls := []manifestURL{}
+
+	for _, element := range elements {
+		for _, urlElement := range manifestURLElements {
+			if element.path != urlElement.path {
+				continue
+			}
+
+			value := strings.TrimSpace(element.text)
+			if urlElement.attribute != "" {
+				value = strings.TrimSpace(element.attrs[urlElement.attribute])
+			}
+
+			if value == "" {
+				continue
+			}
+
+			parsed, err := url.Parse(value)
+			if err != nil || parsed.Host == "" || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+				vc.AddErrorAt("manifest.url", fileLocation{relPath, element.line}, fmt.Sprintf("%s is not an absolute URL", value))
+				continue
+			}
+
+			if parsed.Scheme != "https" {
+				vc.AddErrorAt("manifest.url", fileLocation{relPath, element.line}, fmt.Sprintf("%s must use HTTPS", value))
+			}
+
+			if parsed.User != nil {
+				vc.AddErrorAt("manifest.url", fileLocation{relPath, element.line}, fmt.Sprintf("%s must not contain credentials", parsed.Redacted()))
+				continue
+			}
+
+			urls = append(urls, manifestURL{element: element, url: value})
+		}
+	}
+
+	return urls
+}
+
+// checkManifestURLsReachable sends a HEAD request to every endpoint. Every HTTP response counts as reachable,
+// as most endpoints only accept signed POST requests.
+func checkManifestURLsReachable(ctx context.Context, vc *ValidationContext, relPath string, urls []manifestURL) {
+	client := &http.Client{
+		Timeout: manifestURLTimeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	checked := map[string]error{}
+
+	for _, endpoint := range urls {
+		err, ok := checked[endpoint.url]
+		if !ok {
+			err = checkManifestURLReachable(ctx, client, endpoint.url)
+			checked[endpoint.url] = err
+		}
+
+		if err != nil {
+			vc.AddWarningAt("manifest.url_unreachable", fileLocation{relPath, endpoint.element.line}, fmt.Sprintf("%s is not reachable: %s", endpoint.url, err.Error()))
+		}
+	}
+}
+
+func checkManifestURLReachable(ctx context.Context, client *http.Client, endpoint string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, endpoint, http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		var dnsErr *net.DNSError
+
+		if errors.As(err, &dnsErr) {
+			return fmt.Errorf("cannot resolve host %s", dnsErr.Name)
+		}
+
+		if errors.As(err, &urlErr) {
+			return urlErr.Err
+		}
+
+		return err
+	}
+
+	_ = resp.Body.Close()
+
+	return nil
+}
+
+func (e *manifestElement) childText(name string) string {
+	for _, child := range e.children {
+		if filepath.Base(child.path) == name {
+			return strings.TrimSpace(child.text)
+		}
+	}
+
+	return ""
+}
+
+// readManifestElements returns all elements below the manifest element in document order
+func readManifestElements(file string) ([]*manifestElement, int, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	decoder := xml.NewDecoder(strings.NewReader(string(content)))
+
+	elements := []*manifestElement{}
+	stack := []*manifestElement{}
+	depth := 0
+
+	for {
+		line, _ := decoder.InputPos()
+
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			line, _ = decoder.InputPos()
+			return nil, line, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+
+			// The manifest element itself
+			if depth == 1 {
+				continue
+			}
+
+			attrs := map[string]string{}
+			for _, attr := range t.Attr {
+				attrs[attr.Name.Local] = attr.Value
+			}
+
+			element := &manifestElement{path: t.Name.Local, line: line, attrs: attrs}
+
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				element.path = parent.path + "/" + t.Name.Local
+				parent.children = append(parent.children, element)
+			}
+
+			elements = append(elements, element)
+			stack = append(stack, element)
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		case xml.EndElement:
+			depth--
+
+			if len(stack) > 0 && depth > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	return elements, 0, nil
+}
@@ -0,0 +1,232 @@
+package extension
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/shyim/go-version"
+)
+
+const (
+	// appScriptIncludeFolder contains macros shared between the scripts, it is no hook
+	appScriptIncludeFolder = "include"
+	appScriptExtension     = ".twig"
+)
+
+//go:generate go run ../scripts/app-script-hooks -output app-script-hooks.json
+
+// appScriptData contains the hooks and script services with the first Shopware version providing them. It is generated
+// from the hook classes and service factories of Shopware with go generate, do not edit it by hand.
+//
+//go:embed app-script-hooks.json
+var appScriptData []byte
+
+type appScriptDataFile struct {
+	// Hooks ending with {hook} are custom endpoints, the rest of the folder name is the route
+	Hooks    map[string]string `json:"hooks"`
+	Services map[string]string `json:"services"`
+}
+
+const appScriptEndpointPlaceholder = "{hook}"
+
+var (
+	// appScriptHooks are the hooks apps can register scripts for with the first Shopware version providing them
+	appScriptHooks = map[string]string{}
+	// appScriptEndpoints are the folder prefixes of custom endpoints with the first Shopware version providing them
+	appScriptEndpoints = map[string]string{}
+	appScriptServices  = map[string]appScriptService{}
+)
+
+// appScriptAllowedTags are the tags of the script sandbox, templating tags like block or include are not available
+var appScriptAllowedTags = []string{
+	"if", "elseif", "else", "endif", "for", "endfor", "set", "endset", "do", "return",
+	"macro", "endmacro", "import", "from", "apply", "endapply", "with", "endwith",
+}
+
+// appScriptService is a service of the services variable of a script
+type appScriptService struct {
+	since string
+	// availableIn restricts the service to some hooks, nil allows all hooks
+	availableIn func(hook string) bool
+}
+
+// appScriptServiceScopes restricts services to the hooks passing them to the script, the factories decide this at runtime
+var appScriptServiceScopes = map[string]func(hook string) bool{
+	"cart":     isAppScriptHook("cart"),
+	"price":    isAppScriptHook("cart", "product-pricing"),
+	"writer":   isAppScriptAdminHook,
+	"acl":      isAppScriptAdminHook,
+	"response": isAppScriptEndpoint,
+}
+
+var (
+	appScriptServiceRegExp = regexp.MustCompile(`\bservices\.([a-zA-Z_]+)`)
+	appScriptTagRegExp     = regexp.MustCompile(`\{%[-~]?\s*([a-z_]+)`)
+	appScriptCommentRegExp = regexp.MustCompile(`(?s)\{#.*?#\}`)
+)
+
+func init() {
+	var data appScriptDataFile
+
+	if err := json.Unmarshal(appScriptData, &data); err != nil {
+		panic(fmt.Sprintf("cannot parse app script hooks: %s", err.Error()))
+	}
+
+	for hook, since := range data.Hooks {
+		if prefix, ok := strings.CutSuffix(hook, appScriptEndpointPlaceholder); ok {
+			appScriptEndpoints[prefix] = since
+			continue
+		}
+
+		appScriptHooks[hook] = since
+	}
+
+	for name, since := range data.Services {
+		appScriptServices[name] = appScriptService{since: since, availableIn: appScriptServiceScopes[name]}
+	}
+}
+
+func isAppScriptHook(hooks ...string) func(string) bool {
+	return func(hook string) bool {
+		return slices.Contains(hooks, hook)
+	}
+}
+
+func isAppScriptEndpoint(hook string) bool {
+	_, ok := appScriptEndpointSince(hook)
+
+	return ok
+}
+
+// appScriptEndpointSince returns the first Shopware version providing the custom endpoint of the folder
+func appScriptEndpointSince(hook string) (string, bool) {
+	for prefix, since := range appScriptEndpoints {
+		if strings.HasPrefix(hook, prefix) && len(hook) > len(prefix) {
+			return since, true
+		}
+	}
+
+	return "", false
+}
+
+// isAppScriptAdminHook matches the hooks running with the permissions of the app, not of a customer
+func isAppScriptAdminHook(hook string) bool {
+	return strings.HasPrefix(hook, "api-") || strings.HasPrefix(hook, "app-")
+}
+
+// validateAppScripts validates the app scripts in Resources/scripts against the hooks and services of the supported Shopware versions
+func validateAppScripts(ctx context.Context, vc *ValidationContext) {
+	scriptsDir := filepath.Join(vc.Extension.GetRootDir(), "Resources", "scripts")
+
+	if _, err := os.Stat(scriptsDir); err != nil {
+		return
+	}
+
+	validateAppScriptsByPath(vc, scriptsDir, getMinManifestVersion(ctx, vc.Extension))
+}
+
+func validateAppScriptsByPath(vc *ValidationContext, scriptsDir string, minVersion *version.Version) {
+	hooks, err := os.ReadDir(scriptsDir)
+	if err != nil {
+		vc.AddError("app.script", fmt.Sprintf("Could not read the scripts folder: %s", err.Error()))
+		return
+	}
+
+	for _, hook := range hooks {
+		relHook := filepath.ToSlash(filepath.Join("Resources", "scripts", hook.Name()))
+
+		if !hook.IsDir() {
+			vc.AddErrorAt("app.script.hook", fileLocation{relHook, 0}, "scripts must be placed in a folder named like the hook")
+			continue
+		}
+
+		if hook.Name() != appScriptIncludeFolder {
+			validateAppScriptHook(vc, relHook, hook.Name(), minVersion)
+		}
+
+		_ = filepath.WalkDir(filepath.Join(scriptsDir, hook.Name()), func(file string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(d.Name(), appScriptExtension) {
+				return nil //nolint:nilerr
+			}
+
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return nil //nolint:nilerr
+			}
+
+			rel, _ := filepath.Rel(scriptsDir, file)
+			validateAppScript(vc, filepath.ToSlash(filepath.Join("Resources", "scripts", rel)), hook.Name(), string(content), minVersion)
+
+			return nil
+		})
+	}
+}
+
+func validateAppScriptHook(vc *ValidationContext, relHook, hook string, minVersion *version.Version) {
+	since, known := appScriptHooks[hook]
+
+	if !known {
+		since, known = appScriptEndpointSince(hook)
+	}
+
+	// Newer Shopware versions may add hooks, which are not known yet
+	if !known {
+		vc.AddWarningAt("app.script.hook_unknown", fileLocation{relHook, 0}, fmt.Sprintf("the hook %s is not known, check the name or update shopware-cli", hook))
+		return
+	}
+
+	if minVersion != nil && minVersion.LessThan(version.Must(version.NewVersion(since))) {
+		vc.AddErrorAt("app.script.hook", fileLocation{relHook, 0}, fmt.Sprintf("the hook %s requires Shopware %s, but the app supports Shopware %s", hook, since, minVersion.String()))
+	}
+}
+
+// validateAppScript checks the Twig syntax, the tags of the script sandbox and the services used by the script
+func validateAppScript(vc *ValidationContext, relPath, hook, content string, minVersion *version.Version) {
+	for _, syntaxErr := range scanTwigTemplate(content).errors {
+		vc.AddErrorAt("app.script.syntax", fileLocation{relPath, syntaxErr.line}, syntaxErr.message)
+	}
+
+	// Comments may mention anything, keep their line breaks for the line numbers
+	code := appScriptCommentRegExp.ReplaceAllStringFunc(content, func(comment string) string {
+		return strings.Repeat("\n", strings.Count(comment, "\n"))
+	})
+
+	for _, match := range appScriptTagRegExp.FindAllStringSubmatchIndex(code, -1) {
+		tag := code[match[2]:match[3]]
+
+		if !slices.Contains(appScriptAllowedTags, tag) {
+			vc.AddErrorAt("app.script.syntax", fileLocation{relPath, appScriptLine(code, match[0])}, fmt.Sprintf("the tag %s is not available in app scripts", tag))
+		}
+	}
+
+	// Macros of the include folder get the services passed by the calling script
+	if hook == appScriptIncludeFolder {
+		return
+	}
+
+	for _, match := range appScriptServiceRegExp.FindAllStringSubmatchIndex(code, -1) {
+		name := code[match[2]:match[3]]
+		line := appScriptLine(code, match[0])
+		service, known := appScriptServices[name]
+
+		switch {
+		case !known:
+			vc.AddErrorAt("app.script.service", fileLocation{relPath, line}, fmt.Sprintf("the service %s does not exist", name))
+		case service.availableIn != nil && !service.availableIn(hook):
+			vc.AddErrorAt("app.script.service", fileLocation{relPath, line}, fmt.Sprintf("the service %s is not available in the hook %s", name, hook))
+		case minVersion != nil && minVersion.LessThan(version.Must(version.NewVersion(service.since))):
+			vc.AddErrorAt("app.script.service", fileLocation{relPath, line}, fmt.Sprintf("the service %s requires Shopware %s, but the app supports Shopware %s", name, service.since, minVersion.String()))
+		}
+	}
+}
+
+func appScriptLine(content string, offset int) int {
+	return strings.Count(content[:offset], "\n") + 1
+}
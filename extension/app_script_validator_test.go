@@ -0,0 +1,84 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shyim/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestAppScript(t *testing.T, scriptsDir, file, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(scriptsDir, file)), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(scriptsDir, file), []byte(content), os.ModePerm))
+}
+
+func TestValidateAppScripts(t *testing.T) {
+	scriptsDir := t.TempDir()
+
+	writeTestAppScript(t, scriptsDir, "cart/add-fee.twig", `{# services.unknown in a comment is fine #}
+{% if services.cart.items.count > 0 %}
+    {% do services.cart.items.add(services.price.create({})) %}
+{% endif %}`)
+	writeTestAppScript(t, scriptsDir, "product-page-loaded/load.twig", `{% set products = services.repository.search('product', {}) %}
+{% do services.writer.upsert('product', []) %}
+{% include 'other.twig' %}
+{% for product in products %}`)
+	writeTestAppScript(t, scriptsDir, "api-my-endpoint/response.twig", `{% set response = services.response.json({}) %}
+{% do services.mailer.send() %}
+{% do hook.setResponse(response) %}`)
+	writeTestAppScript(t, scriptsDir, "product-pricing/pricing.twig", `{% do services.price.create({}) %}`)
+	writeTestAppScript(t, scriptsDir, "unknown-hook/script.twig", `{% return %}`)
+	writeTestAppScript(t, scriptsDir, "include/macros.twig", `{% macro add(services) %}{% do services.cart.add() %}{% endmacro %}`)
+
+	check := newValidationContext(App{})
+	validateAppScriptsByPath(check, scriptsDir, version.Must(version.NewVersion("6.4.20.0")))
+
+	assert.ElementsMatch(t, []string{
+		"Resources/scripts/api-my-endpoint/response.twig:2: the service mailer does not exist",
+		"Resources/scripts/product-page-loaded/load.twig:2: the service writer is not available in the hook product-page-loaded",
+		"Resources/scripts/product-page-loaded/load.twig:3: the tag include is not available in app scripts",
+		"Resources/scripts/product-page-loaded/load.twig:4: unclosed {% for %}, missing {% endfor %}",
+		"Resources/scripts/product-pricing: the hook product-pricing requires Shopware 6.5.0.0, but the app supports Shopware 6.4.20.0",
+	}, validationMessages(check.Errors()))
+
+	assert.Equal(t, []string{
+		"Resources/scripts/unknown-hook: the hook unknown-hook is not known, check the name or update shopware-cli",
+	}, validationMessages(check.Warnings()))
+}
+
+func TestAppScriptDataIsLoaded(t *testing.T) {
+	assert.Equal(t, "6.5.0.0", appScriptHooks["product-pricing"])
+	assert.Equal(t, "6.4.8.0", appScriptServices["repository"].since)
+	assert.NotNil(t, appScriptServices["response"].availableIn)
+
+	since, ok := appScriptEndpointSince("store-api-my-endpoint")
+	assert.True(t, ok)
+	assert.Equal(t, "6.4.9.0", since)
+
+	_, ok = appScriptEndpointSince("store-api-")
+	assert.False(t, ok)
+}
+
+func TestValidateAppScriptsServiceVersion(t *testing.T) {
+	scriptsDir := t.TempDir()
+
+	writeTestAppScript(t, scriptsDir, "app-installed/install.twig", `{% do services.config.get('MyApp.config.enabled') %}`)
+
+	check := newValidationContext(App{})
+	validateAppScriptsByPath(check, scriptsDir, version.Must(version.NewVersion("6.4.8.0")))
+
+	assert.Equal(t, []string{
+		"Resources/scripts/app-installed: the hook app-installed requires Shopware 6.4.9.0, but the app supports Shopware 6.4.8.0",
+		"Resources/scripts/app-installed/install.twig:1: the service config requires Shopware 6.4.9.0, but the app supports Shopware 6.4.8.0",
+	}, validationMessages(check.Errors()))
+
+	check = newValidationContext(App{})
+	validateAppScriptsByPath(check, scriptsDir, nil)
+
+	assert.Empty(t, check.Errors())
+}
@@ -0,0 +1,339 @@
+package extension
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopware/shopware-cli/internal/esbuild"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+const (
+	AppServerGo  = "go"
+	AppServerPHP = "php"
+)
+
+// AppServerLanguages are the languages ScaffoldAppServer has templates for
+var AppServerLanguages = []string{AppServerGo, AppServerPHP}
+
+//go:embed all:app-server
+var appServerFiles embed.FS
+
+var (
+	manifestSetupSecretRegExp = regexp.MustCompile(`(?s)(<setup>.*?<secret>)\s*[^<]*?\s*(</secret>)`)
+	manifestSetupEndRegExp    = regexp.MustCompile(`(?s)<setup>.*?([ \t]*)</setup>`)
+)
+
+// GenerateAppSecret returns a random secret for the handshake between the shop and the app backend
+func GenerateAppSecret() (string, error) {
+	secret := make([]byte, 32)
+
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secret), nil
+}
+
+// SetAppSecret writes the secret into the setup of the manifest.xml. Shopware uses it instead of the secret of the store
+// for local development, so it has to be removed before uploading.
+func SetAppSecret(ext Extension, secret string) (string, error) {
+	if ext.GetType() != TypePlatformApp {
+		return "", fmt.Errorf("only apps have an app secret, %s is not supported", ext.GetType())
+	}
+
+	manifestFile := filepath.Join(ext.GetPath(), "manifest.xml")
+
+	content, err := os.ReadFile(manifestFile)
+	if err != nil {
+		return "", err
+	}
+
+	var updated []byte
+
+	if loc := manifestSetupSecretRegExp.FindSubmatchIndex(content); loc != nil {
+		updated = append(updated, content[:loc[3]]...)
+		updated = append(updated, secret...)
+		updated = append(updated, content[loc[4]:]...)
+	} else if loc := manifestSetupEndRegExp.FindSubmatchIndex(content); loc != nil {
+		indent := string(content[loc[2]:loc[3]])
+
+		updated = append(updated, content[:loc[3]]...)
+		updated = append(updated, "    <secret>"+secret+"</secret>\n"+indent...)
+		updated = append(updated, content[loc[3]:]...)
+	} else {
+		return "", fmt.Errorf("the manifest.xml has no setup element, add the registrationUrl first")
+	}
+
+	return manifestFile, os.WriteFile(manifestFile, updated, os.ModePerm)
+}
+
+type AppServerOptions struct {
+	Language string
+	AppName  string
+	Secret   string
+}
+
+type appServerData struct {
+	AppServerOptions
+	KebabName string
+}
+
+// ScaffoldAppServer writes a backend answering the registration handshake into dir and returns the created files relative
+// to it. The app name and secret are written into a .env file.
+func ScaffoldAppServer(dir string, opts AppServerOptions) ([]string, error) {
+	if !slices.Contains(AppServerLanguages, opts.Language) {
+		return nil, fmt.Errorf("unknown language %s, use one of %s", opts.Language, strings.Join(AppServerLanguages, ", "))
+	}
+
+	if opts.AppName == "" || opts.Secret == "" {
+		return nil, fmt.Errorf("the app name and secret are required")
+	}
+
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("the folder %s exists already and is not empty", dir)
+	}
+
+	data := appServerData{AppServerOptions: opts, KebabName: esbuild.ToKebabCase(opts.AppName)}
+
+	var created []string
+
+	for _, layer := range []string{"shared", opts.Language} {
+		layerRoot := path.Join("app-server", layer)
+
+		err := fs.WalkDir(appServerFiles, layerRoot, func(file string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+
+			content, err := appServerFiles.ReadFile(file)
+			if err != nil {
+				return err
+			}
+
+			rendered, err := renderScaffoldTemplate(file, content, data)
+			if err != nil {
+				return err
+			}
+
+			relPath := strings.TrimSuffix(strings.TrimPrefix(file, layerRoot+"/"), ".tmpl")
+			targetFile := filepath.Join(dir, filepath.FromSlash(relPath))
+
+			if err := os.MkdirAll(filepath.Dir(targetFile), os.ModePerm); err != nil {
+				return err
+			}
+
+			if err := os.WriteFile(targetFile, rendered, os.ModePerm); err != nil {
+				return err
+			}
+
+			created = append(created, relPath)
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	slices.Sort(created)
+
+	return created, nil
+}
+
+type HandshakeOptions struct {
+	RegistrationURL string
+	AppName         string
+	AppSecret       string
+	// ShopURL and ShopID identify the simulated shop, ShopID defaults to a random id
+	ShopURL         string
+	ShopID          string
+	ShopwareVersion string
+	Client          *http.Client
+}
+
+// HandshakeResult is what the app backend answered during the registration
+type HandshakeResult struct {
+	ShopID          string
+	ShopSecret      string
+	ConfirmationURL string
+}
+
+type registrationResponse struct {
+	Proof           string `json:"proof"`
+	Secret          string `json:"secret"`
+	ConfirmationURL string `json:"confirmation_url"`
+}
+
+// VerifyHandshake sends the registration request like a Shopware installing the app, checks the proof of the app backend
+// and confirms the registration with generated API credentials signed with the secret of the shop
+func VerifyHandshake(ctx context.Context, opts HandshakeOptions) (*HandshakeResult, error) {
+	if opts.RegistrationURL == "" || opts.AppName == "" || opts.AppSecret == "" {
+		return nil, fmt.Errorf("the registration url, app name and app secret are required")
+	}
+
+	if opts.ShopURL == "" {
+		opts.ShopURL = "http://localhost:8000"
+	}
+
+	if opts.ShopwareVersion == "" {
+		opts.ShopwareVersion = "6.6.0.0"
+	}
+
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	if opts.ShopID == "" {
+		shopID, err := randomAlphanumeric(16)
+		if err != nil {
+			return nil, err
+		}
+
+		opts.ShopID = shopID
+	}
+
+	registrationURL, err := url.Parse(opts.RegistrationURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registration url: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	// Shopware appends the parameters to the query of the registration url and signs the whole query
+	query := fmt.Sprintf("shop-id=%s&shop-url=%s&timestamp=%s", url.QueryEscape(opts.ShopID), url.QueryEscape(opts.ShopURL), timestamp)
+	if registrationURL.RawQuery != "" {
+		query = registrationURL.RawQuery + "&" + query
+	}
+
+	registrationURL.RawQuery = query
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, registrationURL.String(), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("shopware-app-signature", signHandshake(query, opts.AppSecret))
+	req.Header.Set("sw-version", opts.ShopwareVersion)
+
+	body, err := doHandshakeRequest(ctx, opts.Client, req)
+	if err != nil {
+		return nil, fmt.Errorf("registration request failed: %w", err)
+	}
+
+	var registration registrationResponse
+	if err := json.Unmarshal(body, &registration); err != nil {
+		return nil, fmt.Errorf("the registration response is not valid JSON: %w", err)
+	}
+
+	if registration.Secret == "" || registration.ConfirmationURL == "" {
+		return nil, fmt.Errorf("the registration response must contain proof, secret and confirmation_url")
+	}
+
+	if !hmac.Equal([]byte(registration.Proof), []byte(signHandshake(opts.ShopID+opts.ShopURL+opts.AppName, opts.AppSecret))) {
+		return nil, fmt.Errorf("the proof of the registration response is invalid, check the app name and secret of the backend")
+	}
+
+	logging.FromContext(ctx).Debugf("Registration of shop %s accepted, confirming at %s", opts.ShopID, registration.ConfirmationURL)
+
+	apiKey, err := randomAlphanumeric(26)
+	if err != nil {
+		return nil, err
+	}
+
+	secretKey, err := randomAlphanumeric(55)
+	if err != nil {
+		return nil, err
+	}
+
+	confirmation, err := json.Marshal(map[string]string{
+		"apiKey":    "SWIA" + strings.ToUpper(apiKey),
+		"secretKey": secretKey,
+		"timestamp": timestamp,
+		"shopUrl":   opts.ShopURL,
+		"shopId":    opts.ShopID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, registration.ConfirmationURL, bytes.NewReader(confirmation))
+	if err != nil {
+		return nil, fmt.Errorf("invalid confirmation url: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("shopware-shop-signature", signHandshake(string(confirmation), registration.Secret))
+	req.Header.Set("sw-version", opts.ShopwareVersion)
+
+	if _, err := doHandshakeRequest(ctx, opts.Client, req); err != nil {
+		return nil, fmt.Errorf("confirmation request failed: %w", err)
+	}
+
+	return &HandshakeResult{ShopID: opts.ShopID, ShopSecret: registration.Secret, ConfirmationURL: registration.ConfirmationURL}, nil
+}
+
+func doHandshakeRequest(ctx context.Context, client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logging.FromContext(ctx).Errorf("Cannot close response body: %v", err)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s %s returned %s: %s", req.Method, req.URL.Redacted(), resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return body, nil
+}
+
+func signHandshake(message, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(message))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func randomAlphanumeric(length int) (string, error) {
+	const alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+	random := make([]byte, length)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	for i, b := range random {
+		random[i] = alphabet[int(b)%len(alphabet)]
+	}
+
+	return string(random), nil
+}
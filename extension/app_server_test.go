@@ -0,0 +1,138 @@
+package extension
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const appServerTestManifest = `<?xml version="1.0" encoding="UTF-8"?>
+<manifest>
+    <meta>
+        <name>MyApp</name>
+        <version>1.0.0</version>
+    </meta>
+    <setup>
+        <registrationUrl>http://localhost:8080/app/register</registrationUrl>
+    </setup>
+</manifest>
+`
+
+func TestSetAppSecret(t *testing.T) {
+	tmpDir := t.TempDir()
+	manifestFile := filepath.Join(tmpDir, "manifest.xml")
+	require.NoError(t, os.WriteFile(manifestFile, []byte(appServerTestManifest), os.ModePerm))
+
+	ext := App{path: tmpDir}
+
+	_, err := SetAppSecret(ext, "first")
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(manifestFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "        <registrationUrl>http://localhost:8080/app/register</registrationUrl>\n        <secret>first</secret>\n    </setup>")
+
+	_, err = SetAppSecret(ext, "second")
+	require.NoError(t, err)
+
+	content, err = os.ReadFile(manifestFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "<secret>second</secret>")
+	assert.NotContains(t, string(content), "first")
+}
+
+func TestScaffoldAppServer(t *testing.T) {
+	secret, err := GenerateAppSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 64)
+
+	dir := filepath.Join(t.TempDir(), "backend")
+
+	files, err := ScaffoldAppServer(dir, AppServerOptions{Language: AppServerPHP, AppName: "MyApp", Secret: secret})
+	require.NoError(t, err)
+	assert.Equal(t, []string{".env", ".gitignore", "README.md", "public/index.php"}, files)
+
+	env, err := os.ReadFile(filepath.Join(dir, ".env"))
+	require.NoError(t, err)
+	assert.Equal(t, "APP_NAME=MyApp\nAPP_SECRET="+secret+"\n", string(env))
+
+	_, err = ScaffoldAppServer(dir, AppServerOptions{Language: AppServerGo, AppName: "MyApp", Secret: secret})
+	assert.ErrorContains(t, err, "is not empty")
+
+	_, err = ScaffoldAppServer(t.TempDir(), AppServerOptions{Language: "rust", AppName: "MyApp", Secret: secret})
+	assert.ErrorContains(t, err, "unknown language rust")
+}
+
+// newHandshakeTestServer answers the handshake like the scaffolded backends
+func newHandshakeTestServer(t *testing.T, appName, appSecret string) (*httptest.Server, *map[string]string) {
+	t.Helper()
+
+	confirmed := map[string]string{}
+	shopSecret := "shop-secret"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /app/register", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("shopware-app-signature") != signHandshake(r.URL.RawQuery, appSecret) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		query := r.URL.Query()
+
+		_ = json.NewEncoder(w).Encode(registrationResponse{
+			Proof:           signHandshake(query.Get("shop-id")+query.Get("shop-url")+appName, appSecret),
+			Secret:          shopSecret,
+			ConfirmationURL: "http://" + r.Host + "/app/confirm",
+		})
+	})
+	mux.HandleFunc("POST /app/confirm", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		if r.Header.Get("shopware-shop-signature") != signHandshake(string(body), shopSecret) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_ = json.Unmarshal(body, &confirmed)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server, &confirmed
+}
+
+func TestVerifyHandshake(t *testing.T) {
+	server, confirmed := newHandshakeTestServer(t, "MyApp", "app-secret")
+
+	result, err := VerifyHandshake(t.Context(), HandshakeOptions{RegistrationURL: server.URL + "/app/register", AppName: "MyApp", AppSecret: "app-secret", ShopID: "shop1"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "shop1", result.ShopID)
+	assert.Equal(t, "shop-secret", result.ShopSecret)
+	assert.Equal(t, "shop1", (*confirmed)["shopId"])
+	assert.Equal(t, "http://localhost:8000", (*confirmed)["shopUrl"])
+	assert.Regexp(t, `^SWIA[A-Z0-9]{26}$`, (*confirmed)["apiKey"])
+}
+
+func TestVerifyHandshakeWithWrongSecret(t *testing.T) {
+	server, _ := newHandshakeTestServer(t, "MyApp", "app-secret")
+
+	_, err := VerifyHandshake(t.Context(), HandshakeOptions{RegistrationURL: server.URL + "/app/register", AppName: "MyApp", AppSecret: "wrong"})
+	assert.ErrorContains(t, err, "401 Unauthorized")
+}
+
+func TestVerifyHandshakeWithWrongAppName(t *testing.T) {
+	server, _ := newHandshakeTestServer(t, "OtherApp", "app-secret")
+
+	_, err := VerifyHandshake(t.Context(), HandshakeOptions{RegistrationURL: server.URL + "/app/register", AppName: "MyApp", AppSecret: "app-secret"})
+	assert.ErrorContains(t, err, "the proof of the registration response is invalid")
+}
@@ -0,0 +1,117 @@
+package extension
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+
+	"github.com/shopware/shopware-cli/internal/assetcache"
+	"github.com/shopware/shopware-cli/internal/system"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// assetCacheVersion is part of every key, increase it when the cached content changes
+const assetCacheVersion = "1"
+
+// assetCacheOutputs are the folders written by the administration and storefront builds
+var assetCacheOutputs = []string{
+	"Resources/public/administration",
+	"Resources/app/storefront/dist",
+}
+
+// assetCacheKey hashes everything the compiled assets of the extension depend on: the sources and lockfiles in
+// Resources/app, the build options and the Shopware version the assets are built for
+func assetCacheKey(entry ExtensionAssetConfigEntry, assetConfig AssetBuildConfig, minVersion string) (string, error) {
+	h := sha256.New()
+
+	_, _ = fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%t\x00%t\x00%t\x00%t\x00%t\x00%t\n",
+		assetCacheVersion,
+		minVersion,
+		entry.TechnicalName,
+		assetConfig.Browserslist,
+		assetConfig.DisableAdminBuild,
+		assetConfig.DisableStorefrontBuild,
+		entry.EnableESBuildForAdmin,
+		entry.EnableESBuildForStorefront,
+		entry.DisableSass,
+		assetConfig.SourceMaps,
+	)
+
+	if err := assetcache.HashFiles(h, path.Join(entry.BasePath, "Resources", "app"), "node_modules", "dist"); err != nil {
+		return "", err
+	}
+
+	return "assets-" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// restoreCachedAssets restores the compiled assets of all extensions found in the cache. It returns the extensions
+// which still have to be built together with their cache keys.
+func restoreCachedAssets(ctx context.Context, cfgs ExtensionAssetConfig, assetConfig AssetBuildConfig, minVersion string) (ExtensionAssetConfig, map[string]string, error) {
+	remaining := make(ExtensionAssetConfig)
+	missed := make(map[string]string)
+
+	for name, entry := range cfgs {
+		single := ExtensionAssetConfig{name: entry}
+
+		if !single.RequiresAdminBuild() && !single.RequiresStorefrontBuild() {
+			remaining[name] = entry
+			continue
+		}
+
+		key, err := assetCacheKey(entry, assetConfig, minVersion)
+		if err != nil {
+			return nil, nil, fmt.Errorf("asset cache key of %s: %w", name, err)
+		}
+
+		restored, err := assetConfig.Cache.Restore(ctx, key, strings.TrimSuffix(entry.BasePath, "/"))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if restored {
+			logging.FromContext(ctx).Infof("Restored assets of %s from cache", name)
+			continue
+		}
+
+		remaining[name] = entry
+		missed[name] = key
+	}
+
+	return remaining, missed, nil
+}
+
+// saveCachedAssets stores the compiled assets of the built extensions, a failing cache does not fail the build
+func saveCachedAssets(ctx context.Context, cfgs ExtensionAssetConfig, missed map[string]string, cache *assetcache.Cache) {
+	for name, key := range missed {
+		if err := cache.Save(ctx, key, strings.TrimSuffix(cfgs[name].BasePath, "/"), assetCacheOutputs); err != nil {
+			logging.FromContext(ctx).Warnf("Cannot cache assets of %s: %v", name, err)
+		}
+	}
+}
+
+// nodeModulesCacheKey hashes the lockfile of the npm folder, node_modules without a lockfile are not reproducible and not cached.
+// Node version and platform are part of the key, as packages can contain native binaries.
+func nodeModulesCacheKey(job npmInstallJob) (string, error) {
+	if _, err := os.Stat(path.Join(job.npmPath, "package-lock.json")); err != nil {
+		return "", nil
+	}
+
+	h := sha256.New()
+
+	nodeVersion, _ := system.GetInstalledNodeVersion()
+
+	_, _ = fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%s\n", assetCacheVersion, runtime.GOOS, runtime.GOARCH, nodeVersion, strings.Join(job.additionalNpmParams, " "))
+
+	for _, file := range []string{"package.json", "package-lock.json"} {
+		if err := assetcache.HashFile(h, path.Join(job.npmPath, file), file); err != nil {
+			return "", err
+		}
+	}
+
+	return "node-modules-" + hex.EncodeToString(h.Sum(nil)), nil
+}
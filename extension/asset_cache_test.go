@@ -0,0 +1,51 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/asset"
+	"github.com/shopware/shopware-cli/internal/assetcache"
+)
+
+func TestRestoreCachedAssets(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "Resources", "app", "administration", "src"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Resources", "app", "administration", "src", "main.js"), []byte("console.log(1)"), os.ModePerm))
+
+	assetConfig := AssetBuildConfig{Cache: assetcache.New(t.TempDir(), "", "")}
+	cfgs := BuildAssetConfigFromExtensions(getTestContext(), []asset.Source{{Name: "FroshTools", Path: dir}}, assetConfig)
+
+	remaining, missed, err := restoreCachedAssets(getTestContext(), cfgs, assetConfig, "6.6.0.0")
+	require.NoError(t, err)
+	assert.True(t, remaining.Has("FroshTools"))
+	assert.Contains(t, missed, "FroshTools")
+
+	// Simulate the build and store its output
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "Resources", "public", "administration", "js"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Resources", "public", "administration", "js", "frosh-tools.js"), []byte("built"), os.ModePerm))
+	saveCachedAssets(getTestContext(), cfgs, missed, assetConfig.Cache)
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "Resources", "public")))
+
+	remaining, missed, err = restoreCachedAssets(getTestContext(), cfgs, assetConfig, "6.6.0.0")
+	require.NoError(t, err)
+	assert.False(t, remaining.RequiresAdminBuild())
+	assert.Empty(t, missed)
+	assert.FileExists(t, filepath.Join(dir, "Resources", "public", "administration", "js", "frosh-tools.js"))
+
+	// Another Shopware version or changed sources miss the cache
+	_, missed, err = restoreCachedAssets(getTestContext(), cfgs, assetConfig, "6.7.0.0")
+	require.NoError(t, err)
+	assert.Contains(t, missed, "FroshTools")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Resources", "app", "administration", "src", "main.js"), []byte("console.log(2)"), os.ModePerm))
+
+	_, missed, err = restoreCachedAssets(getTestContext(), cfgs, assetConfig, "6.6.0.0")
+	require.NoError(t, err)
+	assert.Contains(t, missed, "FroshTools")
+}
@@ -0,0 +1,81 @@
+package extension
+
+import (
+	"context"
+	"os"
+	"path"
+
+	"github.com/shopware/shopware-cli/internal/asset"
+	"github.com/shopware/shopware-cli/internal/nodejs"
+	"github.com/shopware/shopware-cli/internal/shopwareversion"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// detectNodeRequirement reads the Node.js version of the extensions from .nvmrc, .node-version or the engines of package.json.
+// Without any, at least the version supported by the build tooling of the Shopware version is required.
+func detectNodeRequirement(ctx context.Context, sources []asset.Source, minVersion string) (nodejs.Requirement, error) {
+	var detected *nodejs.Requirement
+
+	for _, source := range sources {
+		requirement, err := nodejs.DetectRequirement(
+			source.Path,
+			path.Join(source.Path, "Resources", "app", "administration"),
+			path.Join(source.Path, "Resources", "app", "storefront"),
+			path.Join(source.Path, "Resources", "app"),
+		)
+		if err != nil {
+			return nodejs.Requirement{}, err
+		}
+
+		if requirement == nil {
+			continue
+		}
+
+		// Only one Node.js can be used for all extensions of the build
+		if detected != nil && detected.Constraint != requirement.Constraint {
+			logging.FromContext(ctx).Warnf("Ignoring Node.js requirement %s, using %s", requirement, detected)
+			continue
+		}
+
+		detected = requirement
+	}
+
+	if detected != nil {
+		return *detected, nil
+	}
+
+	metadata, err := shopwareversion.Load(ctx)
+	if err != nil {
+		return nodejs.Requirement{}, err
+	}
+
+	return nodejs.ShopwareRequirement(metadata.Lines, minVersion), nil
+}
+
+// ensureNodeVersion puts a private copy of the required Node.js into PATH when the installed one does not satisfy the extensions.
+// When no copy can be provided, e.g. offline, the build continues with the installed one.
+func ensureNodeVersion(ctx context.Context, sources []asset.Source, minVersion string) error {
+	if os.Getenv("SHOPWARE_CLI_DISABLE_NODE_DOWNLOAD") == "1" {
+		return nil
+	}
+
+	requirement, err := detectNodeRequirement(ctx, sources, minVersion)
+	if err != nil {
+		return err
+	}
+
+	binDir, err := nodejs.Ensure(ctx, requirement)
+	if err != nil {
+		logging.FromContext(ctx).Warnf("Could not provide Node.js %s, continuing with the installed one: %v", requirement, err)
+
+		return nil
+	}
+
+	if binDir == "" {
+		return nil
+	}
+
+	logging.FromContext(ctx).Infof("Using Node.js from %s for %s", binDir, requirement)
+
+	return nodejs.Activate(binDir)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/shyim/go-version"
 
 	"github.com/shopware/shopware-cli/internal/asset"
+	"github.com/shopware/shopware-cli/internal/assetcache"
 	"github.com/shopware/shopware-cli/internal/ci"
 	"github.com/shopware/shopware-cli/internal/esbuild"
 	"github.com/shopware/shopware-cli/logging"
@@ -45,6 +46,10 @@ type AssetBuildConfig struct {
 	ContributeProject            bool
 	ForceExtensionBuild          []string
 	KeepNodeModules              []string
+	// When set, node_modules installs and compiled assets are restored from the cache if nothing changed
+	Cache *assetcache.Cache
+	// SourceMaps keeps the source maps of the Administration, the bundle report needs them for the sizes of the modules
+	SourceMaps bool
 }
 
 func BuildAssetsForExtensions(ctx context.Context, sources []asset.Source, assetConfig AssetBuildConfig) error { // nolint:gocyclo
@@ -64,8 +69,26 @@ func BuildAssetsForExtensions(ctx context.Context, sources []asset.Source, asset
 		return err
 	}
 
+	var missedCacheKeys map[string]string
+
+	if assetConfig.Cache != nil {
+		cfgs, missedCacheKeys, err = restoreCachedAssets(ctx, cfgs, assetConfig, minVersion)
+		if err != nil {
+			return err
+		}
+
+		if !cfgs.RequiresAdminBuild() && !cfgs.RequiresStorefrontBuild() {
+			logging.FromContext(ctx).Infof("Building assets has been skipped as all assets were restored from cache")
+			return nil
+		}
+	}
+
 	requiresShopwareSources := cfgs.RequiresShopwareRepository()
 
+	if err := ensureNodeVersion(ctx, sources, minVersion); err != nil {
+		return err
+	}
+
 	shopwareRoot := assetConfig.ShopwareRoot
 	if shopwareRoot == "" && requiresShopwareSources {
 		shopwareRoot, err = setupShopwareInTemp(ctx, minVersion)
@@ -78,7 +101,7 @@ func BuildAssetsForExtensions(ctx context.Context, sources []asset.Source, asset
 
 	nodeInstallSection := ci.Default.Section(ctx, "Installing node_modules for extensions")
 
-	paths, err := InstallNodeModulesOfConfigs(ctx, cfgs, assetConfig.NPMForceInstall)
+	paths, err := installNodeModulesOfConfigs(ctx, cfgs, assetConfig.NPMForceInstall, assetConfig.Cache)
 	if err != nil {
 		return err
 	}
@@ -117,6 +140,13 @@ func BuildAssetsForExtensions(ctx context.Context, sources []asset.Source, asset
 			logging.FromContext(ctx).Infof("Building administration assets for %s using ESBuild", name)
 		}
 
+		// Extensions with their own vite config are built standalone, without the platform administration
+		for name, entry := range cfgs.FilterByAdminAndVite() {
+			if err := runViteBuild(ctx, name, entry, *entry.Administration.Vite, administrationViteOutDir, minVersion, assetConfig.SourceMaps); err != nil {
+				return err
+			}
+		}
+
 		nonCompatibleExtensions := cfgs.FilterByAdminAndEsBuild(false)
 
 		if len(nonCompatibleExtensions) != 0 {
@@ -146,7 +176,11 @@ func BuildAssetsForExtensions(ctx context.Context, sources []asset.Source, asset
 			envList := []string{fmt.Sprintf("PROJECT_ROOT=%s", shopwareRoot), fmt.Sprintf("ADMIN_ROOT=%s", PlatformPath(shopwareRoot, "Administration", ""))}
 
 			if !assetConfig.ContributeProject {
-				envList = append(envList, "SHOPWARE_ADMIN_BUILD_ONLY_EXTENSIONS=1", "SHOPWARE_ADMIN_SKIP_SOURCEMAP_GENERATION=1")
+				envList = append(envList, "SHOPWARE_ADMIN_BUILD_ONLY_EXTENSIONS=1")
+
+				if !assetConfig.SourceMaps {
+					envList = append(envList, "SHOPWARE_ADMIN_SKIP_SOURCEMAP_GENERATION=1")
+				}
 			}
 
 			err = npmRunBuild(
@@ -192,6 +226,12 @@ func BuildAssetsForExtensions(ctx context.Context, sources []asset.Source, asset
 			logging.FromContext(ctx).Infof("Building storefront assets for %s using ESBuild", name)
 		}
 
+		for name, entry := range cfgs.FilterByStorefrontAndVite() {
+			if err := runViteBuild(ctx, name, entry, *entry.Storefront.Vite, storefrontViteOutDir, minVersion, assetConfig.SourceMaps); err != nil {
+				return err
+			}
+		}
+
 		nonCompatibleExtensions := cfgs.FilterByStorefrontAndEsBuild(false)
 
 		if len(nonCompatibleExtensions) != 0 {
@@ -303,6 +343,10 @@ func BuildAssetsForExtensions(ctx context.Context, sources []asset.Source, asset
 		storefrontSection.End(ctx)
 	}
 
+	if assetConfig.Cache != nil {
+		saveCachedAssets(ctx, cfgs, missedCacheKeys, assetConfig.Cache)
+	}
+
 	return nil
 }
 
@@ -318,6 +362,7 @@ type npmInstallJob struct {
 	npmPath             string
 	additionalNpmParams []string
 	additionalText      string
+	cache               *assetcache.Cache
 }
 
 type npmInstallResult struct {
@@ -326,6 +371,10 @@ type npmInstallResult struct {
 }
 
 func InstallNodeModulesOfConfigs(ctx context.Context, cfgs ExtensionAssetConfig, force bool) ([]string, error) {
+	return installNodeModulesOfConfigs(ctx, cfgs, force, nil)
+}
+
+func installNodeModulesOfConfigs(ctx context.Context, cfgs ExtensionAssetConfig, force bool, cache *assetcache.Cache) ([]string, error) {
 	// Collect all npm install jobs
 	jobs := make([]npmInstallJob, 0)
 
@@ -337,11 +386,11 @@ func InstallNodeModulesOfConfigs(ctx context.Context, cfgs ExtensionAssetConfig,
 		}
 
 		// only try administration and storefront node_modules folder when we have an entry file
-		if entry.Administration.EntryFilePath != nil {
+		if entry.Administration.EntryFilePath != nil || entry.Administration.Vite != nil {
 			possibleNodePaths = append(possibleNodePaths, path.Join(entry.BasePath, "Resources", "app", "administration", "package.json"), path.Join(entry.BasePath, "Resources", "app", "administration", "src", "package.json"))
 		}
 
-		if entry.Storefront.EntryFilePath != nil {
+		if entry.Storefront.EntryFilePath != nil || entry.Storefront.Vite != nil {
 			possibleNodePaths = append(possibleNodePaths, path.Join(entry.BasePath, "Resources", "app", "storefront", "package.json"), path.Join(entry.BasePath, "Resources", "app", "storefront", "src", "package.json"))
 		}
 
@@ -368,6 +417,7 @@ func InstallNodeModulesOfConfigs(ctx context.Context, cfgs ExtensionAssetConfig,
 					npmPath:             npmPath,
 					additionalNpmParams: additionalNpmParameters,
 					additionalText:      additionalText,
+					cache:               cache,
 				})
 			}
 		}
@@ -427,12 +477,41 @@ func processNpmInstallJob(ctx context.Context, job npmInstallJob) npmInstallResu
 		return npmInstallResult{err: err}
 	}
 
+	var cacheKey string
+
+	if job.cache != nil {
+		if cacheKey, err = nodeModulesCacheKey(job); err != nil {
+			return npmInstallResult{err: err}
+		}
+
+		if cacheKey != "" {
+			restored, err := job.cache.Restore(ctx, cacheKey, job.npmPath)
+			if err != nil {
+				return npmInstallResult{err: err}
+			}
+
+			if restored {
+				logging.FromContext(ctx).Infof("Restored npm dependencies in %s from cache", job.npmPath)
+
+				return npmInstallResult{
+					nodeModulesPath: path.Join(job.npmPath, "node_modules"),
+				}
+			}
+		}
+	}
+
 	logging.FromContext(ctx).Infof("Installing npm dependencies in %s %s\n", job.npmPath, job.additionalText)
 
 	if err := InstallNPMDependencies(job.npmPath, npmPackage, job.additionalNpmParams...); err != nil {
 		return npmInstallResult{err: err}
 	}
 
+	if cacheKey != "" {
+		if err := job.cache.Save(ctx, cacheKey, job.npmPath, []string{"node_modules"}); err != nil {
+			logging.FromContext(ctx).Warnf("Cannot cache npm dependencies of %s: %v", job.npmPath, err)
+		}
+	}
+
 	return npmInstallResult{
 		nodeModulesPath: path.Join(job.npmPath, "node_modules"),
 	}
@@ -577,6 +656,7 @@ func BuildAssetConfigFromExtensions(ctx context.Context, sources []asset.Source,
 				// clear out the entrypoint, so the admin does not build it
 				sourceConfig.Administration.EntryFilePath = nil
 				sourceConfig.Administration.Webpack = nil
+				sourceConfig.Administration.Vite = nil
 
 				logging.FromContext(ctx).Infof("Skipping building administration assets for \"%s\" as compiled files are present", source.Name)
 			}
@@ -585,6 +665,7 @@ func BuildAssetConfigFromExtensions(ctx context.Context, sources []asset.Source,
 				// clear out the entrypoint, so the storefront does not build it
 				sourceConfig.Storefront.EntryFilePath = nil
 				sourceConfig.Storefront.Webpack = nil
+				sourceConfig.Storefront.Vite = nil
 
 				logging.FromContext(ctx).Infof("Skipping building storefront assets for \"%s\" as compiled files are present", source.Name)
 			}
@@ -656,11 +737,13 @@ func createConfigFromPath(entryPointName string, extensionRoot string) Extension
 			Path:          "Resources/app/administration/src",
 			EntryFilePath: entryFilePathAdmin,
 			Webpack:       webpackFileAdmin,
+			Vite:          findViteConfig(extensionRoot, AdministrationViteConfigs),
 		},
 		Storefront: ExtensionAssetConfigStorefront{
 			Path:          "Resources/app/storefront/src",
 			EntryFilePath: entryFilePathStorefront,
 			Webpack:       webpackFileStorefront,
+			Vite:          findViteConfig(extensionRoot, StorefrontViteConfigs),
 			StyleFiles:    storefrontStyles,
 		},
 	}
@@ -702,11 +785,11 @@ func (c ExtensionAssetConfig) Has(name string) bool {
 
 func (c ExtensionAssetConfig) RequiresShopwareRepository() bool {
 	for _, entry := range c {
-		if entry.Administration.EntryFilePath != nil && !entry.EnableESBuildForAdmin {
+		if entry.Administration.EntryFilePath != nil && !entry.EnableESBuildForAdmin && !entry.buildsAdminWithVite() {
 			return true
 		}
 
-		if entry.Storefront.EntryFilePath != nil && !entry.EnableESBuildForStorefront {
+		if entry.Storefront.EntryFilePath != nil && !entry.EnableESBuildForStorefront && !entry.buildsStorefrontWithVite() {
 			return true
 		}
 	}
@@ -716,7 +799,7 @@ func (c ExtensionAssetConfig) RequiresShopwareRepository() bool {
 
 func (c ExtensionAssetConfig) RequiresAdminBuild() bool {
 	for _, entry := range c {
-		if entry.Administration.EntryFilePath != nil {
+		if entry.Administration.EntryFilePath != nil || entry.buildsAdminWithVite() {
 			return true
 		}
 	}
@@ -726,7 +809,7 @@ func (c ExtensionAssetConfig) RequiresAdminBuild() bool {
 
 func (c ExtensionAssetConfig) RequiresStorefrontBuild() bool {
 	for _, entry := range c {
-		if entry.Storefront.EntryFilePath != nil {
+		if entry.Storefront.EntryFilePath != nil || entry.buildsStorefrontWithVite() {
 			return true
 		}
 	}
@@ -750,7 +833,19 @@ func (c ExtensionAssetConfig) FilterByAdminAndEsBuild(esbuildEnabled bool) Exten
 	filtered := make(ExtensionAssetConfig)
 
 	for name, entry := range c {
-		if entry.Administration.EntryFilePath != nil && entry.EnableESBuildForAdmin == esbuildEnabled {
+		if entry.Administration.EntryFilePath != nil && entry.EnableESBuildForAdmin == esbuildEnabled && !entry.buildsAdminWithVite() {
+			filtered[name] = entry
+		}
+	}
+
+	return filtered
+}
+
+func (c ExtensionAssetConfig) FilterByAdminAndVite() ExtensionAssetConfig {
+	filtered := make(ExtensionAssetConfig)
+
+	for name, entry := range c {
+		if entry.buildsAdminWithVite() {
 			filtered[name] = entry
 		}
 	}
@@ -762,7 +857,19 @@ func (c ExtensionAssetConfig) FilterByStorefrontAndEsBuild(esbuildEnabled bool)
 	filtered := make(ExtensionAssetConfig)
 
 	for name, entry := range c {
-		if entry.Storefront.EntryFilePath != nil && entry.EnableESBuildForStorefront == esbuildEnabled {
+		if entry.Storefront.EntryFilePath != nil && entry.EnableESBuildForStorefront == esbuildEnabled && !entry.buildsStorefrontWithVite() {
+			filtered[name] = entry
+		}
+	}
+
+	return filtered
+}
+
+func (c ExtensionAssetConfig) FilterByStorefrontAndVite() ExtensionAssetConfig {
+	filtered := make(ExtensionAssetConfig)
+
+	for name, entry := range c {
+		if entry.buildsStorefrontWithVite() {
 			filtered[name] = entry
 		}
 	}
@@ -806,16 +913,27 @@ type ExtensionAssetConfigEntry struct {
 	NpmStrict                  bool
 }
 
+// buildsAdminWithVite reports whether the extension ships its own vite config, the builtin esbuild still takes precedence when enabled
+func (e ExtensionAssetConfigEntry) buildsAdminWithVite() bool {
+	return e.Administration.Vite != nil && !e.EnableESBuildForAdmin
+}
+
+func (e ExtensionAssetConfigEntry) buildsStorefrontWithVite() bool {
+	return e.Storefront.Vite != nil && !e.EnableESBuildForStorefront
+}
+
 type ExtensionAssetConfigAdmin struct {
 	Path          string  `json:"path"`
 	EntryFilePath *string `json:"entryFilePath"`
 	Webpack       *string `json:"webpack"`
+	Vite          *string `json:"-"`
 }
 
 type ExtensionAssetConfigStorefront struct {
 	Path          string   `json:"path"`
 	EntryFilePath *string  `json:"entryFilePath"`
 	Webpack       *string  `json:"webpack"`
+	Vite          *string  `json:"-"`
 	StyleFiles    []string `json:"styleFiles"`
 }
 
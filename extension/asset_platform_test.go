@@ -146,3 +146,45 @@ func TestSkipFilterOnAssetConfig(t *testing.T) {
 	assert.Len(t, filtered, 1)
 	assert.Contains(t, filtered, "FroshTest")
 }
+
+func TestGenerateConfigWithViteConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "Resources", "app", "administration", "src"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "Resources", "app", "administration", "src", "main.ts"), []byte("test"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "Resources", "app", "administration", "vite.config.mts"), []byte("test"), os.ModePerm))
+
+	config := BuildAssetConfigFromExtensions(getTestContext(), []asset.Source{{Name: "FroshTools", Path: dir}}, AssetBuildConfig{})
+
+	assert.Equal(t, "Resources/app/administration/vite.config.mts", *config["FroshTools"].Administration.Vite)
+	assert.Nil(t, config["FroshTools"].Storefront.Vite)
+	assert.True(t, config.RequiresAdminBuild())
+	assert.False(t, config.RequiresStorefrontBuild())
+	assert.False(t, config.RequiresShopwareRepository())
+	assert.True(t, config.FilterByAdminAndVite().Has("FroshTools"))
+	assert.False(t, config.FilterByAdminAndEsBuild(false).Has("FroshTools"))
+
+	// esbuild takes precedence when enabled in the extension config
+	config = BuildAssetConfigFromExtensions(getTestContext(), []asset.Source{{Name: "FroshTools", Path: dir, AdminEsbuildCompatible: true}}, AssetBuildConfig{})
+
+	assert.False(t, config.FilterByAdminAndVite().Has("FroshTools"))
+	assert.True(t, config.FilterByAdminAndEsBuild(true).Has("FroshTools"))
+}
+
+func TestViteBuildEnv(t *testing.T) {
+	envList, err := viteBuildEnv("FroshTools", ExtensionAssetConfigEntry{TechnicalName: "frosh-tools"}, "/ext/Resources/public/administration", "6.6.0.0", false)
+
+	assert.NoError(t, err)
+	assert.Contains(t, envList, "SHOPWARE_VERSION=6.6.0.0")
+	assert.Contains(t, envList, `SHOPWARE_FEATURES={"ADMIN_VITE":true}`)
+	assert.Contains(t, envList, "ADMIN_VITE=1")
+	assert.Contains(t, envList, "SHOPWARE_EXTENSION_TECHNICAL_NAME=frosh-tools")
+	assert.Contains(t, envList, "SHOPWARE_ASSET_OUT_DIR=/ext/Resources/public/administration")
+	assert.Contains(t, envList, "SHOPWARE_ADMIN_SKIP_SOURCEMAP_GENERATION=1")
+
+	// The bundle report needs the source maps
+	envList, err = viteBuildEnv("FroshTools", ExtensionAssetConfigEntry{TechnicalName: "frosh-tools"}, "/ext/Resources/public/administration", "6.6.0.0", true)
+
+	assert.NoError(t, err)
+	assert.NotContains(t, envList, "SHOPWARE_ADMIN_SKIP_SOURCEMAP_GENERATION=1")
+}
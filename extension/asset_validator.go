@@ -0,0 +1,137 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/shopware/shopware-cli/internal/asset"
+	"github.com/shopware/shopware-cli/internal/esbuild"
+	"github.com/shopware/shopware-cli/internal/system"
+)
+
+// assetModTimeTolerance ignores small differences, zip files store the modification time with a precision of two seconds
+const assetModTimeTolerance = 2 * time.Second
+
+// builtAssetsCheck describes where the sources of an administration or storefront build and its output are located
+type builtAssetsCheck struct {
+	area        string
+	entrypoints []string
+	sourceDir   string
+	// compiled are the files of which at least one must exist, a build writes only one of them depending on the Shopware version
+	compiled []string
+	// outputDir contains all files written by the build
+	outputDir string
+}
+
+func builtAssetsChecks(source asset.Source) []builtAssetsCheck {
+	technicalName := esbuild.ToKebabCase(source.Name)
+
+	return []builtAssetsCheck{
+		{
+			area:        "administration",
+			entrypoints: []string{AdministrationEntrypointJS, AdministrationEntrypointTS},
+			sourceDir:   "Resources/app/administration/src",
+			compiled: []string{
+				path.Join("Resources/public/administration/js", technicalName+".js"),
+				"Resources/public/administration/.vite/manifest.json",
+			},
+			outputDir: "Resources/public/administration",
+		},
+		{
+			area:        "storefront",
+			entrypoints: []string{StorefrontEntrypointJS, StorefrontEntrypointTS},
+			sourceDir:   "Resources/app/storefront/src",
+			compiled: []string{
+				path.Join("Resources/app/storefront/dist/storefront/js", technicalName, technicalName+".js"),
+				path.Join("Resources/app/storefront/dist/storefront/js", technicalName+".js"),
+			},
+			outputDir: "Resources/app/storefront/dist/storefront/js",
+		},
+	}
+}
+
+// validateBuiltAssets checks that an extension shipping administration or storefront JavaScript contains the compiled files
+// and that they were built after the last change of the sources
+func validateBuiltAssets(ctx context.Context, vc *ValidationContext) {
+	for _, source := range ConvertExtensionsToSources(ctx, []Extension{vc.Extension}) {
+		for _, check := range builtAssetsChecks(source) {
+			validateBuiltAssetsOfSource(vc, source, check)
+		}
+	}
+}
+
+func validateBuiltAssetsOfSource(vc *ValidationContext, source asset.Source, check builtAssetsCheck) {
+	entrypoint := ""
+
+	for _, file := range check.entrypoints {
+		if _, err := os.Stat(path.Join(source.Path, file)); err == nil {
+			entrypoint = file
+		}
+	}
+
+	if entrypoint == "" {
+		return
+	}
+
+	relSourcePath := strings.TrimPrefix(strings.TrimPrefix(source.Path, vc.Extension.GetRootDir()), "/")
+
+	compiledFound := false
+
+	for _, file := range check.compiled {
+		if _, err := os.Stat(path.Join(source.Path, file)); err == nil {
+			compiledFound = true
+			break
+		}
+	}
+
+	if !compiledFound {
+		vc.AddError("assets.not_built", fmt.Sprintf("%s ships %s JavaScript with %s, but the compiled files are missing in %s. Build the assets before creating the zip", source.Name, check.area, path.Join(relSourcePath, entrypoint), path.Join(relSourcePath, check.outputDir)))
+		return
+	}
+
+	newestSource, newestSourceTime := newestFileIn(path.Join(source.Path, check.sourceDir))
+	_, newestCompiledTime := newestFileIn(path.Join(source.Path, check.outputDir))
+
+	if newestSource == "" || !newestSourceTime.After(newestCompiledTime.Add(assetModTimeTolerance)) {
+		return
+	}
+
+	vc.AddWarning("assets.outdated", fmt.Sprintf("%s was changed after the %s assets of %s were built, the compiled files in %s may be outdated", path.Join(relSourcePath, check.sourceDir, newestSource), check.area, source.Name, path.Join(relSourcePath, check.outputDir)))
+}
+
+// newestFileIn returns the relative path and modification time of the most recently modified file in the directory
+func newestFileIn(dir string) (string, time.Time) {
+	var newestPath string
+	var newestTime time.Time
+
+	if _, err := os.Stat(dir); err != nil {
+		return newestPath, newestTime
+	}
+
+	entries, err := system.Walk(dir, system.WalkOptions{ExcludeDirs: []string{"node_modules"}})
+	if err != nil {
+		return newestPath, newestTime
+	}
+
+	for _, entry := range entries {
+		if entry.Entry.IsDir() {
+			continue
+		}
+
+		info, err := entry.Entry.Info()
+		if err != nil {
+			continue
+		}
+
+		if info.ModTime().After(newestTime) {
+			newestPath = entry.Path
+			newestTime = info.ModTime()
+		}
+	}
+
+	return newestPath, newestTime
+}
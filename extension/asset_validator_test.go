@@ -0,0 +1,93 @@
+package extension
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newAssetTestPlugin(t *testing.T, files map[string]time.Time) PlatformPlugin {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+
+	for file, modTime := range files {
+		file = path.Join(tmpDir, "src", file)
+
+		assert.NoError(t, os.MkdirAll(path.Dir(file), os.ModePerm))
+		assert.NoError(t, os.WriteFile(file, []byte("console.log(1)"), os.ModePerm))
+		assert.NoError(t, os.Chtimes(file, modTime, modTime))
+	}
+
+	ext := PlatformPlugin{path: tmpDir, config: &Config{}}
+	ext.Composer.Extra.ShopwarePluginClass = "FroshTools\\FroshTools"
+
+	return ext
+}
+
+func TestValidateBuiltAssetsWithoutJavaScript(t *testing.T) {
+	ext := newAssetTestPlugin(t, map[string]time.Time{
+		"Resources/config/services.xml": time.Now(),
+	})
+
+	check := newValidationContext(ext)
+	validateBuiltAssets(t.Context(), check)
+
+	assert.Empty(t, check.Errors())
+	assert.Empty(t, check.Warnings())
+}
+
+func TestValidateBuiltAssetsMissing(t *testing.T) {
+	ext := newAssetTestPlugin(t, map[string]time.Time{
+		"Resources/app/administration/src/main.js": time.Now(),
+		"Resources/app/storefront/src/main.ts":     time.Now(),
+	})
+
+	check := newValidationContext(ext)
+	validateBuiltAssets(t.Context(), check)
+
+	assert.Len(t, check.Errors(), 2)
+	assert.Equal(t, "assets.not_built", check.Errors()[0].Identifier)
+	assert.Equal(t, "FroshTools ships administration JavaScript with Resources/app/administration/src/main.js, but the compiled files are missing in Resources/public/administration. Build the assets before creating the zip", check.Errors()[0].Message)
+	assert.Contains(t, check.Errors()[1].Message, "Resources/app/storefront/dist/storefront/js")
+}
+
+func TestValidateBuiltAssetsUpToDate(t *testing.T) {
+	built := time.Now()
+
+	ext := newAssetTestPlugin(t, map[string]time.Time{
+		"Resources/app/administration/src/main.js":                               built.Add(-time.Hour),
+		"Resources/public/administration/.vite/manifest.json":                    built,
+		"Resources/app/storefront/src/main.js":                                   built.Add(-time.Hour),
+		"Resources/app/storefront/dist/storefront/js/frosh-tools/frosh-tools.js": built,
+	})
+
+	check := newValidationContext(ext)
+	validateBuiltAssets(t.Context(), check)
+
+	assert.Empty(t, check.Errors())
+	assert.Empty(t, check.Warnings())
+}
+
+func TestValidateBuiltAssetsOutdated(t *testing.T) {
+	built := time.Now().Add(-time.Hour)
+
+	ext := newAssetTestPlugin(t, map[string]time.Time{
+		"Resources/app/administration/src/main.js":                   built.Add(-time.Hour),
+		"Resources/app/administration/src/component/index.js":        time.Now(),
+		"Resources/public/administration/js/frosh-tools.js":          built,
+		"Resources/app/storefront/src/main.js":                       built.Add(time.Second),
+		"Resources/app/storefront/dist/storefront/js/frosh-tools.js": built,
+	})
+
+	check := newValidationContext(ext)
+	validateBuiltAssets(t.Context(), check)
+
+	assert.Empty(t, check.Errors())
+	assert.Len(t, check.Warnings(), 1)
+	assert.Equal(t, "assets.outdated", check.Warnings()[0].Identifier)
+	assert.Equal(t, "Resources/app/administration/src/component/index.js was changed after the administration assets of FroshTools were built, the compiled files in Resources/public/administration may be outdated", check.Warnings()[0].Message)
+}
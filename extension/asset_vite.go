@@ -0,0 +1,125 @@
+package extension
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path"
+	"slices"
+
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var (
+	AdministrationViteConfigs = []string{
+		"Resources/app/administration/vite.config.js",
+		"Resources/app/administration/vite.config.mjs",
+		"Resources/app/administration/vite.config.ts",
+		"Resources/app/administration/vite.config.mts",
+	}
+	StorefrontViteConfigs = []string{
+		"Resources/app/storefront/vite.config.js",
+		"Resources/app/storefront/vite.config.mjs",
+		"Resources/app/storefront/vite.config.ts",
+		"Resources/app/storefront/vite.config.mts",
+	}
+)
+
+const (
+	administrationViteOutDir = "Resources/public/administration"
+	storefrontViteOutDir     = "Resources/app/storefront/dist/storefront"
+)
+
+// viteFeatureFlags are the feature flags the platform enables for Vite builds, extensions get them as environment variables like in Shopware
+var viteFeatureFlags = map[string]bool{
+	"ADMIN_VITE": true,
+}
+
+func findViteConfig(extensionRoot string, candidates []string) *string {
+	for _, candidate := range candidates {
+		if _, err := os.Stat(path.Join(extensionRoot, candidate)); err == nil {
+			val := candidate
+			return &val
+		}
+	}
+
+	return nil
+}
+
+// viteBuildEnv returns the environment of a standalone Vite build, so the extension does not need a platform checkout
+func viteBuildEnv(name string, entry ExtensionAssetConfigEntry, outDir string, shopwareVersion string, sourceMaps bool) ([]string, error) {
+	features, err := json.Marshal(viteFeatureFlags)
+	if err != nil {
+		return nil, err
+	}
+
+	envList := []string{
+		"NODE_ENV=production",
+		fmt.Sprintf("SHOPWARE_VERSION=%s", shopwareVersion),
+		fmt.Sprintf("SHOPWARE_FEATURES=%s", features),
+		fmt.Sprintf("SHOPWARE_EXTENSION_NAME=%s", name),
+		fmt.Sprintf("SHOPWARE_EXTENSION_TECHNICAL_NAME=%s", entry.TechnicalName),
+		fmt.Sprintf("SHOPWARE_ASSET_OUT_DIR=%s", outDir),
+		"SHOPWARE_ADMIN_BUILD_ONLY_EXTENSIONS=1",
+	}
+
+	if !sourceMaps {
+		envList = append(envList, "SHOPWARE_ADMIN_SKIP_SOURCEMAP_GENERATION=1")
+	}
+
+	for _, flag := range slices.Sorted(maps.Keys(viteFeatureFlags)) {
+		if viteFeatureFlags[flag] {
+			envList = append(envList, fmt.Sprintf("%s=1", flag))
+		}
+	}
+
+	return envList, nil
+}
+
+// findViteBinary looks for vite in the node_modules next to the config and in the shared Resources/app folder
+func findViteBinary(entry ExtensionAssetConfigEntry, configDir string) (string, error) {
+	candidates := []string{
+		path.Join(entry.BasePath, configDir, "node_modules", ".bin", "vite"),
+		path.Join(entry.BasePath, "Resources", "app", "node_modules", ".bin", "vite"),
+	}
+
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("vite is not installed in %s, add it to the devDependencies of the package.json", path.Join(entry.BasePath, configDir))
+}
+
+func runViteBuild(ctx context.Context, name string, entry ExtensionAssetConfigEntry, viteConfig string, outDir string, shopwareVersion string, sourceMaps bool) error {
+	configDir := path.Dir(viteConfig)
+	absoluteOutDir := path.Join(entry.BasePath, outDir)
+
+	viteBinary, err := findViteBinary(entry, configDir)
+	if err != nil {
+		return err
+	}
+
+	envList, err := viteBuildEnv(name, entry, absoluteOutDir, shopwareVersion, sourceMaps)
+	if err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Infof("Building assets for %s using Vite", name)
+
+	viteCmd := exec.CommandContext(ctx, "node", viteBinary, "build", "--config", path.Base(viteConfig), "--outDir", absoluteOutDir, "--manifest") //nolint:gosec
+	viteCmd.Dir = path.Join(entry.BasePath, configDir)
+	viteCmd.Env = append(os.Environ(), envList...)
+	viteCmd.Stdout = os.Stdout
+	viteCmd.Stderr = os.Stderr
+
+	if err := viteCmd.Run(); err != nil {
+		return fmt.Errorf("vite build of %s failed: %w", name, err)
+	}
+
+	return nil
+}
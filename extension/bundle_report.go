@@ -0,0 +1,331 @@
+package extension
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+)
+
+// bundleReportModules is the amount of the largest modules listed per file
+const bundleReportModules = 10
+
+// unmappedModule collects the bytes of a file without a source, like the runtime of webpack
+const unmappedModule = "[unmapped]"
+
+// bundleOutputDirs are the folders of the compiled JavaScript, relative to the root dir of the extension
+var bundleOutputDirs = []struct {
+	Area string
+	Dir  string
+}{
+	{Area: "administration", Dir: "Resources/public/administration"},
+	{Area: "storefront", Dir: "Resources/app/storefront/dist/storefront"},
+}
+
+// BundleFile is a compiled JavaScript file of the Administration or the Storefront
+type BundleFile struct {
+	Area     string `json:"area"`
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	GzipSize int64  `json:"gzipSize"`
+	// Modules are the largest sources of the file, only available with a source map next to it
+	Modules []BundleModule `json:"modules,omitempty"`
+}
+
+type BundleModule struct {
+	Source string `json:"source"`
+	Size   int64  `json:"size"`
+}
+
+// AnalyzeBundles measures the compiled JavaScript files of the extension sorted by area and path
+func AnalyzeBundles(rootDir string) ([]BundleFile, error) {
+	var files []BundleFile
+
+	for _, output := range bundleOutputDirs {
+		dir := filepath.Join(rootDir, output.Dir)
+
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() || !strings.HasSuffix(path, ".js") {
+				return nil
+			}
+
+			file, err := analyzeBundleFile(path)
+			if err != nil {
+				return fmt.Errorf("analyze %s: %w", path, err)
+			}
+
+			file.Area = output.Area
+
+			if file.Path, err = filepath.Rel(rootDir, path); err != nil {
+				return err
+			}
+
+			file.Path = filepath.ToSlash(file.Path)
+			files = append(files, file)
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+func analyzeBundleFile(path string) (BundleFile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return BundleFile{}, err
+	}
+
+	var compressed bytes.Buffer
+
+	gz, err := gzip.NewWriterLevel(&compressed, gzip.BestCompression)
+	if err != nil {
+		return BundleFile{}, err
+	}
+
+	if _, err := gz.Write(content); err != nil {
+		return BundleFile{}, err
+	}
+
+	if err := gz.Close(); err != nil {
+		return BundleFile{}, err
+	}
+
+	file := BundleFile{Size: int64(len(content)), GzipSize: int64(compressed.Len())}
+
+	sourceMap, err := os.ReadFile(path + ".map")
+	if errors.Is(err, os.ErrNotExist) {
+		return file, nil
+	}
+
+	if err != nil {
+		return file, err
+	}
+
+	sizes, err := sourceMapModuleSizes(content, sourceMap)
+	if err != nil {
+		return file, err
+	}
+
+	for source, size := range sizes {
+		file.Modules = append(file.Modules, BundleModule{Source: source, Size: size})
+	}
+
+	slices.SortFunc(file.Modules, func(a, b BundleModule) int {
+		if a.Size != b.Size {
+			return int(b.Size - a.Size)
+		}
+
+		return strings.Compare(a.Source, b.Source)
+	})
+
+	if len(file.Modules) > bundleReportModules {
+		file.Modules = file.Modules[:bundleReportModules]
+	}
+
+	return file, nil
+}
+
+// sourceMapModuleSizes attributes each byte of the generated file to the source of the mapping segment covering it.
+// The columns of source maps count UTF-16 units, for the mostly ASCII bundles they are treated as bytes.
+func sourceMapModuleSizes(content, sourceMap []byte) (map[string]int64, error) {
+	var parsed struct {
+		Sources  []string `json:"sources"`
+		Mappings string   `json:"mappings"`
+	}
+
+	if err := json.Unmarshal(sourceMap, &parsed); err != nil {
+		return nil, fmt.Errorf("cannot parse source map: %w", err)
+	}
+
+	sizes := make(map[string]int64)
+	lines := bytes.Split(content, []byte("\n"))
+	sourceIndex := 0
+
+	for lineIndex, mapping := range strings.Split(parsed.Mappings, ";") {
+		if lineIndex >= len(lines) {
+			break
+		}
+
+		lineLength := len(lines[lineIndex])
+		column := 0
+		covered := 0
+		currentSource := -1
+
+		attribute := func(until int) {
+			until = min(until, lineLength)
+
+			if until <= covered {
+				return
+			}
+
+			name := unmappedModule
+			if currentSource >= 0 && currentSource < len(parsed.Sources) {
+				name = parsed.Sources[currentSource]
+			}
+
+			sizes[name] += int64(until - covered)
+			covered = until
+		}
+
+		for _, segment := range strings.Split(mapping, ",") {
+			if segment == "" {
+				continue
+			}
+
+			values, err := decodeVLQ(segment)
+			if err != nil {
+				return nil, err
+			}
+
+			column += values[0]
+			attribute(column)
+
+			currentSource = -1
+
+			if len(values) >= 4 {
+				sourceIndex += values[1]
+				currentSource = sourceIndex
+			}
+		}
+
+		attribute(lineLength)
+	}
+
+	// Lines without mappings like a trailing license comment
+	for lineIndex := len(strings.Split(parsed.Mappings, ";")); lineIndex < len(lines); lineIndex++ {
+		if len(lines[lineIndex]) > 0 {
+			sizes[unmappedModule] += int64(len(lines[lineIndex]))
+		}
+	}
+
+	return sizes, nil
+}
+
+const base64VLQChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// decodeVLQ decodes a segment of the source map mappings into its relative values
+func decodeVLQ(segment string) ([]int, error) {
+	var values []int
+
+	value, shift := 0, 0
+
+	for _, c := range segment {
+		digit := strings.IndexRune(base64VLQChars, c)
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid character %q in source map mappings", c)
+		}
+
+		value += (digit & 31) << shift
+
+		if digit&32 != 0 {
+			shift += 5
+			continue
+		}
+
+		if value&1 != 0 {
+			values = append(values, -(value >> 1))
+		} else {
+			values = append(values, value>>1)
+		}
+
+		value, shift = 0, 0
+	}
+
+	if shift != 0 || len(values) == 0 {
+		return nil, fmt.Errorf("invalid source map segment %q", segment)
+	}
+
+	return values, nil
+}
+
+// RemoveBundleSourceMaps deletes the source maps of the Administration generated for the report and their references,
+// so the output matches a build without the report
+func RemoveBundleSourceMaps(rootDir string) error {
+	dir := filepath.Join(rootDir, bundleOutputDirs[0].Dir)
+
+	if _, err := os.Stat(dir); err != nil {
+		return nil //nolint:nilerr
+	}
+
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || !strings.HasSuffix(path, ".js.map") {
+			return nil
+		}
+
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+
+		jsFile := strings.TrimSuffix(path, ".map")
+
+		content, err := os.ReadFile(jsFile)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		content = bytes.ReplaceAll(content, []byte("//# sourceMappingURL="+filepath.Base(path)), nil)
+
+		return os.WriteFile(jsFile, content, 0o644)
+	})
+}
+
+// CheckBundleBudget returns an error listing all files larger than the budget of their area
+func CheckBundleBudget(files []BundleFile, budget ConfigBuildZipAssetsBudget) error {
+	limits := map[string]string{"administration": budget.Administration, "storefront": budget.Storefront}
+
+	var exceeded []string
+
+	for _, file := range files {
+		if limits[file.Area] == "" {
+			continue
+		}
+
+		limit, err := humanize.ParseBytes(limits[file.Area])
+		if err != nil {
+			return fmt.Errorf("invalid %s budget %q: %w", file.Area, limits[file.Area], err)
+		}
+
+		size := file.Size
+		if budget.Gzip {
+			size = file.GzipSize
+		}
+
+		if uint64(size) > limit {
+			exceeded = append(exceeded, fmt.Sprintf("%s is %s, the budget is %s", file.Path, humanize.Bytes(uint64(size)), limits[file.Area]))
+		}
+	}
+
+	if len(exceeded) > 0 {
+		return fmt.Errorf("the asset size budget is exceeded:\n%s", strings.Join(exceeded, "\n"))
+	}
+
+	return nil
+}
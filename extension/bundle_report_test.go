@@ -0,0 +1,98 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeVLQ(t *testing.T) {
+	values, err := decodeVLQ("AAgBC")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 0, 16, 1}, values)
+
+	values, err = decodeVLQ("D")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{-1}, values)
+
+	_, err = decodeVLQ("g")
+	assert.Error(t, err)
+
+	_, err = decodeVLQ("A!")
+	assert.Error(t, err)
+}
+
+func TestSourceMapModuleSizes(t *testing.T) {
+	content := []byte("var a=1;var b=2;\n/*! license */")
+	sourceMap := []byte(`{"version":3,"sources":["a.js","b.js"],"mappings":"AAAA,QCAA"}`)
+
+	sizes, err := sourceMapModuleSizes(content, sourceMap)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"a.js": 8, "b.js": 8, unmappedModule: 14}, sizes)
+}
+
+func TestAnalyzeBundles(t *testing.T) {
+	root := t.TempDir()
+	adminDir := filepath.Join(root, "Resources", "public", "administration", "js")
+	storefrontDir := filepath.Join(root, "Resources", "app", "storefront", "dist", "storefront", "js")
+
+	assert.NoError(t, os.MkdirAll(adminDir, os.ModePerm))
+	assert.NoError(t, os.MkdirAll(storefrontDir, os.ModePerm))
+
+	assert.NoError(t, os.WriteFile(filepath.Join(adminDir, "app.js"), []byte("var a=1;var b=2;"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(adminDir, "app.js.map"), []byte(`{"sources":["a.js","b.js"],"mappings":"AAAA,QCAA"}`), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(adminDir, "app.css"), []byte("body{}"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(storefrontDir, "plugin.js"), []byte("console.log(1)"), os.ModePerm))
+
+	files, err := AnalyzeBundles(root)
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	assert.Equal(t, "administration", files[0].Area)
+	assert.Equal(t, "Resources/public/administration/js/app.js", files[0].Path)
+	assert.Equal(t, int64(16), files[0].Size)
+	assert.Greater(t, files[0].GzipSize, int64(0))
+	assert.Equal(t, []BundleModule{{Source: "a.js", Size: 8}, {Source: "b.js", Size: 8}}, files[0].Modules)
+
+	assert.Equal(t, "storefront", files[1].Area)
+	assert.Equal(t, "Resources/app/storefront/dist/storefront/js/plugin.js", files[1].Path)
+	assert.Empty(t, files[1].Modules)
+}
+
+func TestRemoveBundleSourceMaps(t *testing.T) {
+	root := t.TempDir()
+	adminDir := filepath.Join(root, "Resources", "public", "administration", "js")
+
+	assert.NoError(t, os.MkdirAll(adminDir, os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(adminDir, "app.js"), []byte("var a=1;\n//# sourceMappingURL=app.js.map"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(adminDir, "app.js.map"), []byte(`{}`), os.ModePerm))
+
+	assert.NoError(t, RemoveBundleSourceMaps(root))
+
+	assert.NoFileExists(t, filepath.Join(adminDir, "app.js.map"))
+
+	content, err := os.ReadFile(filepath.Join(adminDir, "app.js"))
+	assert.NoError(t, err)
+	assert.Equal(t, "var a=1;\n", string(content))
+
+	// Extensions without Administration are fine
+	assert.NoError(t, RemoveBundleSourceMaps(t.TempDir()))
+}
+
+func TestCheckBundleBudget(t *testing.T) {
+	files := []BundleFile{
+		{Area: "administration", Path: "admin.js", Size: 3000, GzipSize: 1000},
+		{Area: "storefront", Path: "storefront.js", Size: 500, GzipSize: 200},
+	}
+
+	assert.NoError(t, CheckBundleBudget(files, ConfigBuildZipAssetsBudget{}))
+	assert.NoError(t, CheckBundleBudget(files, ConfigBuildZipAssetsBudget{Administration: "2KB", Gzip: true}))
+
+	err := CheckBundleBudget(files, ConfigBuildZipAssetsBudget{Administration: "2KB", Storefront: "1KB"})
+	assert.ErrorContains(t, err, "admin.js is 3.0 kB, the budget is 2KB")
+	assert.NotContains(t, err.Error(), "storefront.js")
+
+	assert.ErrorContains(t, CheckBundleBudget(files, ConfigBuildZipAssetsBudget{Storefront: "a lot"}), "invalid storefront budget")
+}
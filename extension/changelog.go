@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/yuin/goldmark"
 	goldmarkExtension "github.com/yuin/goldmark/extension"
@@ -13,6 +15,15 @@ import (
 	"github.com/yuin/goldmark/renderer/html"
 )
 
+// changelogUnreleased is the version of the changes in ## [Unreleased], which are not released yet
+const changelogUnreleased = "Unreleased"
+
+var (
+	keepAChangelogHeadingRegExp = regexp.MustCompile(`^##\s+\[([^\]]+)\](?:\s+-\s+(.*?))?$`)
+	keepAChangelogTypeRegExp    = regexp.MustCompile(`^###\s+(.+)$`)
+	keepAChangelogLinkRegExp    = regexp.MustCompile(`^\[[^\]]+\]:\s+\S+`)
+)
+
 func parseMarkdownChangelogInPath(path string) (map[string]map[string]string, error) {
 	files, err := filepath.Glob(fmt.Sprintf("%s/CHANGELOG*.md", path))
 	if err != nil {
@@ -44,6 +55,10 @@ func parseMarkdownChangelogInPath(path string) (map[string]map[string]string, er
 }
 
 func parseMarkdownChangelog(content string) (map[string]string, error) {
+	if isKeepAChangelog(content) {
+		return renderMarkdownChangelog(splitKeepAChangelog(content))
+	}
+
 	versions := make(map[string]string)
 	currentVersion := ""
 	versionText := ""
@@ -63,6 +78,82 @@ func parseMarkdownChangelog(content string) (map[string]string, error) {
 
 	versions[currentVersion] = versionText
 
+	return renderMarkdownChangelog(versions)
+}
+
+// isKeepAChangelog reports whether the versions are sections like ## [1.0.0] - 2024-05-01 of https://keepachangelog.com
+func isKeepAChangelog(content string) bool {
+	inCodeBlock := false
+
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+		}
+
+		if !inCodeBlock && keepAChangelogHeadingRegExp.MatchString(strings.TrimRight(line, " \t\r")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitKeepAChangelog returns the markdown of the sections by version, the entries of ## [Unreleased] as Unreleased.
+// Headings of the types of changes like ### Added become bold text, the title, introduction and link references are skipped.
+func splitKeepAChangelog(content string) map[string]string {
+	versions := make(map[string]string)
+	currentVersion := ""
+	lines := []string{}
+	inCodeBlock := false
+
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+		}
+
+		if !inCodeBlock {
+			trimmed := strings.TrimRight(line, " \t")
+
+			if matches := keepAChangelogHeadingRegExp.FindStringSubmatch(trimmed); matches != nil {
+				if currentVersion != "" {
+					versions[currentVersion] = strings.TrimSpace(strings.Join(lines, "\n"))
+				}
+
+				currentVersion = keepAChangelogVersion(matches[1])
+				lines = []string{}
+
+				continue
+			}
+
+			if keepAChangelogLinkRegExp.MatchString(trimmed) {
+				continue
+			}
+
+			if matches := keepAChangelogTypeRegExp.FindStringSubmatch(trimmed); matches != nil {
+				// Blank lines end a preceding list, so the type is not appended to its last item
+				line = "\n**" + matches[1] + "**\n"
+			}
+		}
+
+		lines = append(lines, line)
+	}
+
+	if currentVersion != "" {
+		versions[currentVersion] = strings.TrimSpace(strings.Join(lines, "\n"))
+	}
+
+	return versions
+}
+
+func keepAChangelogVersion(name string) string {
+	if strings.EqualFold(name, changelogUnreleased) {
+		return changelogUnreleased
+	}
+
+	return strings.TrimPrefix(strings.TrimPrefix(name, "v"), "V")
+}
+
+func renderMarkdownChangelog(versions map[string]string) (map[string]string, error) {
 	for key, changelog := range versions {
 		var buf bytes.Buffer
 
@@ -122,6 +213,65 @@ func parseExtensionMarkdownChangelog(ext Extension) (*ExtensionChangelog, error)
 	return &ExtensionChangelog{German: changelogDeVersion, English: changelogEnVersion, Changelogs: allChangelogsInVersion}, nil
 }
 
+// CopyChangelogEntry adds the entry of fromVersion as entry of toVersion above the newest version of every changelog file
+func CopyChangelogEntry(extPath, fromVersion, toVersion string) error {
+	files, err := filepath.Glob(fmt.Sprintf("%s/CHANGELOG*.md", extPath))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+		first, start, end := -1, -1, len(lines)
+
+		for i, line := range lines {
+			matches := changelogVersionHeadingRegExp.FindStringSubmatch(strings.TrimRight(line, " \t"))
+			if matches == nil {
+				continue
+			}
+
+			if first == -1 {
+				first = i
+			}
+
+			if start != -1 {
+				end = i
+				break
+			}
+
+			if matches[1] == fromVersion {
+				start = i
+			}
+		}
+
+		if start == -1 {
+			continue
+		}
+
+		entry := strings.TrimRight(strings.Join(lines[start+1:end], "\n"), "\n")
+		heading := "# " + toVersion
+
+		if isKeepAChangelog(string(content)) {
+			heading = fmt.Sprintf("## [%s] - %s", toVersion, time.Now().Format(time.DateOnly))
+		}
+
+		newLines := append([]string{}, lines[:first]...)
+		newLines = append(newLines, heading, entry, "")
+		newLines = append(newLines, lines[first:]...)
+
+		if err := os.WriteFile(file, []byte(strings.Join(newLines, "\n")), os.ModePerm); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func GetConfiguredGoldMark() goldmark.Markdown {
 	return goldmark.New(
 		goldmark.WithExtensions(goldmarkExtension.GFM),
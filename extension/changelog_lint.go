@@ -0,0 +1,139 @@
+package extension
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shyim/go-version"
+)
+
+// changelogTypes are the types of changes of keep a changelog, the German ones are used in CHANGELOG_de-DE.md
+var changelogTypes = []string{
+	"Added", "Changed", "Deprecated", "Removed", "Fixed", "Security",
+	"Hinzugefügt", "Geändert", "Veraltet", "Entfernt", "Behoben", "Sicherheit",
+}
+
+type ChangelogProblem struct {
+	// File is the name of the changelog file like CHANGELOG_de-DE.md
+	File    string
+	Line    int
+	Message string
+}
+
+func (p ChangelogProblem) String() string {
+	return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+}
+
+// LintChangelogs reports format errors of the CHANGELOG*.md files in the folder, which would lose or misplace entries in the store
+func LintChangelogs(dir string) ([]ChangelogProblem, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "CHANGELOG*.md"))
+	if err != nil {
+		return nil, err
+	}
+
+	problems := []ChangelogProblem{}
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		problems = append(problems, lintChangelog(filepath.Base(file), strings.ReplaceAll(string(content), "\r\n", "\n"))...)
+	}
+
+	return problems, nil
+}
+
+type changelogSection struct {
+	line    int
+	version string
+	entries int
+}
+
+func lintChangelog(file, content string) []ChangelogProblem {
+	keepAChangelog := isKeepAChangelog(content)
+	problems := []ChangelogProblem{}
+	sections := []*changelogSection{}
+	inCodeBlock := false
+
+	add := func(line int, message string, args ...any) {
+		problems = append(problems, ChangelogProblem{File: file, Line: line, Message: fmt.Sprintf(message, args...)})
+	}
+
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, " \t")
+		lineNumber := i + 1
+
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+		}
+
+		if inCodeBlock || !strings.HasPrefix(line, "#") {
+			if len(sections) > 0 && strings.TrimSpace(line) != "" && !keepAChangelogLinkRegExp.MatchString(line) {
+				sections[len(sections)-1].entries++
+			}
+
+			continue
+		}
+
+		if !keepAChangelog {
+			if matches := changelogVersionHeadingRegExp.FindStringSubmatch(line); matches != nil {
+				sections = append(sections, &changelogSection{line: lineNumber, version: matches[1]})
+			} else if len(sections) > 0 {
+				add(lineNumber, "the heading %q ends the entry of version %s, use only version headings or the keep a changelog format", line, sections[len(sections)-1].version)
+			}
+
+			continue
+		}
+
+		if matches := keepAChangelogHeadingRegExp.FindStringSubmatch(line); matches != nil {
+			section := &changelogSection{line: lineNumber, version: keepAChangelogVersion(matches[1])}
+
+			if section.version == changelogUnreleased {
+				if len(sections) > 0 {
+					add(lineNumber, "the Unreleased section must be the first section")
+				}
+			} else if _, err := version.NewVersion(section.version); err != nil {
+				add(lineNumber, "the section %s is neither Unreleased nor a version", matches[1])
+			} else if _, err := time.Parse(time.DateOnly, matches[2]); err != nil {
+				add(lineNumber, "the release date of %s must be given like ## [%s] - 2024-05-01", section.version, matches[1])
+			}
+
+			sections = append(sections, section)
+
+			continue
+		}
+
+		if matches := keepAChangelogTypeRegExp.FindStringSubmatch(line); matches != nil && len(sections) > 0 {
+			if !slices.Contains(changelogTypes, matches[1]) {
+				add(lineNumber, "unknown type of change %q, use Added, Changed, Deprecated, Removed, Fixed or Security", matches[1])
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(line, "# ") && len(sections) == 0 {
+			// The title of the changelog
+			continue
+		}
+
+		add(lineNumber, "the heading %q is not a section like ## [1.0.0] - 2024-05-01 or a type of change like ### Added", line)
+	}
+
+	for _, section := range sections {
+		if section.entries == 0 && section.version != changelogUnreleased {
+			add(section.line, "version %s has no entries", section.version)
+		}
+	}
+
+	slices.SortStableFunc(problems, func(a, b ChangelogProblem) int {
+		return a.Line - b.Line
+	})
+
+	return problems
+}
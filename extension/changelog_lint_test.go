@@ -0,0 +1,60 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintKeepAChangelog(t *testing.T) {
+	problems := lintChangelog("CHANGELOG.md", `# Changelog
+
+## [1.1.0] - 2024-05-01
+### Added
+- Import
+### Improved
+- Speed
+
+## [Unreleased]
+
+## [next] - 2024-06-01
+- Something
+
+## [1.0.0] - 2024-13-01
+### Added
+
+# 0.9.0
+`)
+
+	assert.Equal(t, []ChangelogProblem{
+		{File: "CHANGELOG.md", Line: 6, Message: `unknown type of change "Improved", use Added, Changed, Deprecated, Removed, Fixed or Security`},
+		{File: "CHANGELOG.md", Line: 9, Message: "the Unreleased section must be the first section"},
+		{File: "CHANGELOG.md", Line: 11, Message: "the section next is neither Unreleased nor a version"},
+		{File: "CHANGELOG.md", Line: 14, Message: "the release date of 1.0.0 must be given like ## [1.0.0] - 2024-05-01"},
+		{File: "CHANGELOG.md", Line: 14, Message: "version 1.0.0 has no entries"},
+		{File: "CHANGELOG.md", Line: 17, Message: `the heading "# 0.9.0" is not a section like ## [1.0.0] - 2024-05-01 or a type of change like ### Added`},
+	}, problems)
+}
+
+func TestLintMarkdownChangelog(t *testing.T) {
+	problems := lintChangelog("CHANGELOG_de-DE.md", "# Changelog\n\n# 1.1.0\n## Behoben\n- Rundung\n\n# 1.0.0\n")
+
+	assert.Equal(t, []ChangelogProblem{
+		{File: "CHANGELOG_de-DE.md", Line: 4, Message: `the heading "## Behoben" ends the entry of version 1.1.0, use only version headings or the keep a changelog format`},
+		{File: "CHANGELOG_de-DE.md", Line: 7, Message: "version 1.0.0 has no entries"},
+	}, problems)
+}
+
+func TestLintChangelogs(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "CHANGELOG.md"), []byte("# 1.0.0\n- Initial\n"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "CHANGELOG_de-DE.md"), []byte("## [Unreleased]\n\n## [1.0.0] - 2024-01-01\n### Hinzugefügt\n- Erste Version\n"), os.ModePerm))
+
+	problems, err := LintChangelogs(dir)
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+}
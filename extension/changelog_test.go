@@ -1,6 +1,8 @@
 package extension
 
 import (
+	"os"
+	"path"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -21,3 +23,63 @@ func TestChangelogParsingWhitespaces(t *testing.T) {
 	assert.Equal(t, "<ul>\n<li>Test</li>\n<li>Test2</li>\n</ul>\n", content["1.0.0"])
 	assert.Equal(t, "<ul>\n<li>Test3</li>\n<li>Test4</li>\n</ul>\n", content["2.0.0"])
 }
+
+func TestCopyChangelogEntry(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.NoError(t, os.WriteFile(path.Join(dir, "CHANGELOG.md"), []byte("# 1.2.0\n- Feature\n\n# 1.1.0\n- Fix\n"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(path.Join(dir, "CHANGELOG_de-DE.md"), []byte("# 1.1.0\n- Fehlerbehebung\n"), os.ModePerm))
+
+	assert.NoError(t, CopyChangelogEntry(dir, "1.2.0", "1.2.1"))
+
+	content, err := os.ReadFile(path.Join(dir, "CHANGELOG.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "# 1.2.1\n- Feature\n\n# 1.2.0\n- Feature\n\n# 1.1.0\n- Fix\n", string(content))
+
+	content, err = os.ReadFile(path.Join(dir, "CHANGELOG_de-DE.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "# 1.1.0\n- Fehlerbehebung\n", string(content))
+}
+
+func TestKeepAChangelogParsing(t *testing.T) {
+	content, err := parseMarkdownChangelog(`# Changelog
+
+All notable changes to this project will be documented in this file.
+
+## [Unreleased]
+
+### Added
+- Export
+
+## [1.1.0] - 2024-05-01
+
+### Added
+- Import
+### Fixed
+- Rounding
+
+## [v1.0.0] - 2024-01-01
+- Initial
+
+[Unreleased]: https://github.com/frosh/tools/compare/v1.1.0...HEAD
+[1.1.0]: https://github.com/frosh/tools/compare/v1.0.0...v1.1.0
+`)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "<p><strong>Added</strong></p>\n<ul>\n<li>Export</li>\n</ul>\n", content["Unreleased"])
+	assert.Equal(t, "<p><strong>Added</strong></p>\n<ul>\n<li>Import</li>\n</ul>\n<p><strong>Fixed</strong></p>\n<ul>\n<li>Rounding</li>\n</ul>\n", content["1.1.0"])
+	assert.Equal(t, "<ul>\n<li>Initial</li>\n</ul>\n", content["1.0.0"])
+	assert.Len(t, content, 3)
+}
+
+func TestCopyKeepAChangelogEntry(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.NoError(t, os.WriteFile(path.Join(dir, "CHANGELOG.md"), []byte("# Changelog\n\n## [Unreleased]\n\n## [1.2.0] - 2024-05-01\n### Added\n- Feature\n"), os.ModePerm))
+
+	assert.NoError(t, CopyChangelogEntry(dir, "1.2.0", "1.2.1"))
+
+	content, err := os.ReadFile(path.Join(dir, "CHANGELOG.md"))
+	assert.NoError(t, err)
+	assert.Regexp(t, `^# Changelog\n\n## \[Unreleased\]\n\n## \[1\.2\.1\] - \d{4}-\d{2}-\d{2}\n### Added\n- Feature\n\n## \[1\.2\.0\] - 2024-05-01\n`, string(content))
+}
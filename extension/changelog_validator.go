@@ -0,0 +1,167 @@
+package extension
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shyim/go-version"
+)
+
+// changelogRequiredLocales must have an entry for the current version, all other locales only when a changelog file for them exists
+var changelogRequiredLocales = []string{"en-GB"}
+
+type changelogHeading struct {
+	line    int
+	version *version.Version
+}
+
+// validateChangelog checks that every changelog has an entry for the current version and lists the versions from newest to oldest
+func validateChangelog(vc *ValidationContext) {
+	ext := vc.Extension
+
+	// The changelog is generated from the commits while zipping
+	if ext.GetExtensionConfig().Changelog.Enabled {
+		return
+	}
+
+	currentVersion, err := ext.GetVersion()
+	if err != nil {
+		return
+	}
+
+	files, err := filepath.Glob(fmt.Sprintf("%s/CHANGELOG*.md", ext.GetPath()))
+	if err != nil {
+		return
+	}
+
+	if len(files) == 0 {
+		// Bundles are not uploaded to the store and need no changelog
+		if ext.GetType() != TypeShopwareBundle {
+			vc.AddError("changelog.missing", fmt.Sprintf("The extension has no CHANGELOG.md, add one with an entry for version %s", currentVersion.String()))
+		}
+
+		return
+	}
+
+	changelogs, err := parseMarkdownChangelogInPath(ext.GetPath())
+	if err != nil {
+		vc.AddError("changelog.missing", fmt.Sprintf("Cannot parse the changelog: %s", err.Error()))
+		return
+	}
+
+	locales := slices.Clone(changelogRequiredLocales)
+
+	for locale := range changelogs {
+		if !slices.Contains(locales, locale) {
+			locales = append(locales, locale)
+		}
+	}
+
+	slices.Sort(locales)
+
+	for _, locale := range locales {
+		text, ok := changelogs[locale][currentVersion.String()]
+
+		if ok && strings.TrimSpace(text) != "" {
+			continue
+		}
+
+		if strings.TrimSpace(changelogs[locale][changelogUnreleased]) != "" {
+			vc.AddError("changelog.missing", fmt.Sprintf("The %s changelog has no entry for the current version %s, move the entries of Unreleased into a section ## [%s] - %s", locale, currentVersion.String(), currentVersion.String(), time.Now().Format(time.DateOnly)))
+		} else {
+			vc.AddError("changelog.missing", fmt.Sprintf("The %s changelog has no entry for the current version %s", locale, currentVersion.String()))
+		}
+	}
+
+	for _, file := range files {
+		validateChangelogVersionOrder(vc, file, currentVersion)
+	}
+
+	problems, err := LintChangelogs(ext.GetPath())
+	if err != nil {
+		return
+	}
+
+	for _, problem := range problems {
+		vc.AddWarningAt("changelog.format", fileLocation{problem.File, problem.Line}, problem.Message)
+	}
+}
+
+func validateChangelogVersionOrder(vc *ValidationContext, file string, currentVersion *version.Version) {
+	headings, err := readChangelogHeadings(file)
+	if err != nil {
+		vc.AddError("changelog.missing", fmt.Sprintf("Cannot read %s: %s", filepath.Base(file), err.Error()))
+		return
+	}
+
+	relPath, err := filepath.Rel(vc.Extension.GetRootDir(), file)
+	if err != nil {
+		relPath = file
+	}
+
+	for i, heading := range headings {
+		if i == 0 && heading.version.GreaterThan(currentVersion) {
+			vc.AddErrorAt("changelog.version_order", fileLocation{relPath, heading.line}, fmt.Sprintf("version %s is newer than the current extension version %s", heading.version.String(), currentVersion.String()))
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		previous := headings[i-1]
+
+		if heading.version.Equal(previous.version) {
+			vc.AddErrorAt("changelog.version_order", fileLocation{relPath, heading.line}, fmt.Sprintf("version %s is listed twice, first in line %d", heading.version.String(), previous.line))
+		} else if heading.version.GreaterThan(previous.version) {
+			vc.AddErrorAt("changelog.version_order", fileLocation{relPath, heading.line}, fmt.Sprintf("version %s is newer than version %s above, the versions must be ordered from newest to oldest", heading.version.String(), previous.version.String()))
+		}
+	}
+}
+
+// readChangelogHeadings returns the version headings of a changelog file in the order of the file
+func readChangelogHeadings(file string) ([]changelogHeading, error) {
+	handle, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+
+	defer handle.Close()
+
+	headings := []changelogHeading{}
+	scanner := bufio.NewScanner(handle)
+	line := 0
+	inCodeBlock := false
+
+	for scanner.Scan() {
+		line++
+
+		text := strings.TrimRight(scanner.Text(), " \t\r")
+
+		if strings.HasPrefix(strings.TrimSpace(text), "```") {
+			inCodeBlock = !inCodeBlock
+		}
+
+		if inCodeBlock {
+			continue
+		}
+
+		matches := changelogVersionHeadingRegExp.FindStringSubmatch(text)
+		if matches == nil {
+			continue
+		}
+
+		v, err := version.NewVersion(matches[1])
+		if err != nil {
+			continue
+		}
+
+		headings = append(headings, changelogHeading{line: line, version: v})
+	}
+
+	return headings, scanner.Err()
+}
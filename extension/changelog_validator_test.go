@@ -0,0 +1,97 @@
+package extension
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newChangelogTestPlugin(t *testing.T, files map[string]string) PlatformPlugin {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+
+	for file, content := range files {
+		assert.NoError(t, os.WriteFile(path.Join(tmpDir, file), []byte(content), os.ModePerm))
+	}
+
+	ext := PlatformPlugin{path: tmpDir, config: &Config{}}
+	ext.Composer.Version = "1.2.0"
+
+	return ext
+}
+
+func TestValidateChangelog(t *testing.T) {
+	ext := newChangelogTestPlugin(t, map[string]string{
+		"CHANGELOG.md":       "# 1.2.0\n- Feature\n\n# 1.1.0\n- Fix\n",
+		"CHANGELOG_de-DE.md": "# 1.2.0\n- Funktion\n",
+	})
+
+	check := newValidationContext(ext)
+	validateChangelog(check)
+
+	assert.Empty(t, check.Errors())
+}
+
+func TestValidateChangelogMissingEntries(t *testing.T) {
+	ext := newChangelogTestPlugin(t, map[string]string{
+		"CHANGELOG.md":       "# 1.1.0\n- Fix\n",
+		"CHANGELOG_de-DE.md": "# 1.2.0\n- Funktion\n",
+	})
+
+	check := newValidationContext(ext)
+	validateChangelog(check)
+
+	assert.Len(t, check.Errors(), 1)
+	assert.Equal(t, "changelog.missing", check.Errors()[0].Identifier)
+	assert.Equal(t, "The en-GB changelog has no entry for the current version 1.2.0", check.Errors()[0].Message)
+
+	check = newValidationContext(newChangelogTestPlugin(t, nil))
+	validateChangelog(check)
+
+	assert.Len(t, check.Errors(), 1)
+	assert.Equal(t, "changelog.missing", check.Errors()[0].Identifier)
+}
+
+func TestValidateChangelogVersionOrder(t *testing.T) {
+	ext := newChangelogTestPlugin(t, map[string]string{
+		"CHANGELOG.md": "# 1.3.0\n- Next\n\n# 1.2.0\n- Feature\n\n# 1.0.0\n- Initial\n\n# 1.1.0\n- Fix\n\n# 1.1.0\n- Fix\n",
+	})
+
+	check := newValidationContext(ext)
+	validateChangelog(check)
+
+	assert.Equal(t, []ValidationMessage{
+		{Identifier: "changelog.version_order", Message: "../CHANGELOG.md:1: version 1.3.0 is newer than the current extension version 1.2.0", File: "../CHANGELOG.md", Line: 1},
+		{Identifier: "changelog.version_order", Message: "../CHANGELOG.md:10: version 1.1.0 is newer than version 1.0.0 above, the versions must be ordered from newest to oldest", File: "../CHANGELOG.md", Line: 10},
+		{Identifier: "changelog.version_order", Message: "../CHANGELOG.md:13: version 1.1.0 is listed twice, first in line 10", File: "../CHANGELOG.md", Line: 13},
+	}, check.Errors())
+}
+
+func TestValidateChangelogSkipsGeneratedChangelog(t *testing.T) {
+	ext := newChangelogTestPlugin(t, nil)
+	ext.config.Changelog.Enabled = true
+
+	check := newValidationContext(ext)
+	validateChangelog(check)
+
+	assert.Empty(t, check.Errors())
+}
+
+func TestValidateKeepAChangelog(t *testing.T) {
+	ext := newChangelogTestPlugin(t, map[string]string{
+		"CHANGELOG.md": "# Changelog\n\n## [Unreleased]\n### Added\n- Export\n\n## [1.1.0] - 2024-05-01\n### Fixed\n- Fix\n\n## [1.0.0]\n### Added\n- Initial\n",
+	})
+
+	check := newValidationContext(ext)
+	validateChangelog(check)
+
+	assert.Len(t, check.Errors(), 1)
+	assert.Contains(t, check.Errors()[0].Message, "The en-GB changelog has no entry for the current version 1.2.0, move the entries of Unreleased into a section ## [1.2.0] - ")
+
+	assert.Equal(t, []ValidationMessage{
+		{Identifier: "changelog.format", Message: "CHANGELOG.md:11: the release date of 1.0.0 must be given like ## [1.0.0] - 2024-05-01", File: "CHANGELOG.md", Line: 11},
+	}, check.Warnings())
+}
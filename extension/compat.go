@@ -0,0 +1,275 @@
+package extension
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/shyim/go-version"
+	"gopkg.in/yaml.v3"
+
+	"github.com/shopware/shopware-cli/internal/deprecation"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var (
+	manifestMetaCompatibilityRegExp = regexp.MustCompile(`(?s)(<meta>.*?<compatibility>)\s*[^<]*?\s*(</compatibility>)`)
+	manifestMetaVersionEndRegExp    = regexp.MustCompile(`(?s)<meta>.*?</version>`)
+)
+
+// CompatibilityRequirement is a Shopware API used by the extension which exists only in some Shopware versions
+type CompatibilityRequirement struct {
+	Kind     string
+	Name     string
+	Location string
+	// Added is the first Shopware version with the API, Removed the first one without it
+	Added   string
+	Removed string
+}
+
+// CompatibilityReport compares the Shopware version constraint of the extension with the versions its code works with
+type CompatibilityReport struct {
+	Current string
+	// Suggested contains all released Shopware versions allowed by Current which have the used APIs. Only some added
+	// APIs are known, so Oldest, the first version of Suggested, is not proven to work.
+	Suggested    string
+	Oldest       string
+	Requirements []CompatibilityRequirement
+	// Unsupported are the released Shopware versions allowed by Current which miss a used API
+	Unsupported []string
+}
+
+// DetectCompatibility finds the Shopware APIs of the PHP, XML and Administration code which were added or removed in a release
+// and suggests the constraint matching them. versions are the released Shopware versions, when empty they are fetched from Packagist.
+func DetectCompatibility(ctx context.Context, ext Extension, versions []string) (*CompatibilityReport, error) {
+	constraint, err := ext.GetShopwareVersionConstraint()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the Shopware version constraint: %w", err)
+	}
+
+	db, err := deprecation.NewDatabase()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(versions) == 0 {
+		if versions, err = GetShopwareVersions(ctx); err != nil {
+			logging.FromContext(ctx).Warnf("Could not fetch Shopware versions, using the releases of the deprecation database: %v", err)
+			versions = db.Releases()
+		}
+	}
+
+	report := &CompatibilityReport{Current: constraint.String(), Requirements: []CompatibilityRequirement{}, Unsupported: []string{}}
+
+	var minVersion, removedVersion *version.Version
+
+	for _, usage := range findDeprecatedAPIUsages(ext, db) {
+		entry := usage.entry
+		if entry.Added == "" && entry.Removed == "" {
+			continue
+		}
+
+		report.Requirements = append(report.Requirements, CompatibilityRequirement{
+			Kind:     deprecationKindLabels[entry.Kind],
+			Name:     entry.Name,
+			Location: usage.location.String(),
+			Added:    entry.Added,
+			Removed:  entry.Removed,
+		})
+
+		if entry.Added != "" {
+			if added := version.Must(version.NewVersion(entry.Added)); minVersion == nil || added.GreaterThan(minVersion) {
+				minVersion = added
+			}
+		}
+
+		if entry.Removed != "" {
+			if removed := version.Must(version.NewVersion(entry.Removed)); removedVersion == nil || removed.LessThan(removedVersion) {
+				removedVersion = removed
+			}
+		}
+	}
+
+	var allowed, compatible []*version.Version
+
+	for _, v := range parseShopwareReleases(versions) {
+		if !constraint.Check(v) {
+			continue
+		}
+
+		allowed = append(allowed, v)
+
+		if (minVersion == nil || v.GreaterThanOrEqual(minVersion)) && (removedVersion == nil || v.LessThan(removedVersion)) {
+			compatible = append(compatible, v)
+		} else {
+			report.Unsupported = append(report.Unsupported, v.NormalizedString())
+		}
+	}
+
+	if len(allowed) == 0 {
+		return nil, fmt.Errorf("no released Shopware version matches the constraint %s", report.Current)
+	}
+
+	if len(compatible) == 0 {
+		return nil, fmt.Errorf("none of the Shopware versions allowed by %s has all used APIs, %s", report.Current, describeCompatibilityRange(minVersion, removedVersion))
+	}
+
+	report.Suggested = suggestShopwareConstraint(compatible, removedVersion)
+	report.Oldest = compatible[0].NormalizedString()
+
+	return report, nil
+}
+
+// parseShopwareReleases returns the versions without pre-releases sorted ascending
+func parseShopwareReleases(versions []string) []*version.Version {
+	releases := []*version.Version{}
+
+	for _, r := range versions {
+		v, err := version.NewVersion(r)
+		if err != nil || v.Prerelease() != "" {
+			continue
+		}
+
+		releases = append(releases, v)
+	}
+
+	slices.SortFunc(releases, func(a, b *version.Version) int {
+		return a.Compare(b)
+	})
+
+	return slices.CompactFunc(releases, func(a, b *version.Version) bool {
+		return a.Equal(b)
+	})
+}
+
+// suggestShopwareConstraint starts at the oldest compatible release and ends before the next minor version of the newest one,
+// as compatibility with unreleased Shopware versions cannot be known
+func suggestShopwareConstraint(compatible []*version.Version, removed *version.Version) string {
+	newest := compatible[len(compatible)-1]
+
+	upper := fmt.Sprintf("%d.%d.0.0", newest.Major(), newest.Minor()+1)
+	if removed != nil && removed.LessThan(version.Must(version.NewVersion(upper))) {
+		upper = removed.NormalizedString()
+	}
+
+	return fmt.Sprintf(">=%s <%s", compatible[0].NormalizedString(), upper)
+}
+
+func describeCompatibilityRange(minVersion, removed *version.Version) string {
+	switch {
+	case minVersion != nil && removed != nil:
+		return fmt.Sprintf("the code requires at least Shopware %s and uses APIs removed in Shopware %s", minVersion, removed)
+	case minVersion != nil:
+		return fmt.Sprintf("the code requires at least Shopware %s", minVersion)
+	default:
+		return fmt.Sprintf("the code uses APIs removed in Shopware %s", removed)
+	}
+}
+
+// setConfigShopwareVersionConstraint replaces build.shopwareVersionConstraint of the extension config, the other settings
+// and comments of the file are kept
+func setConfigShopwareVersionConstraint(file, constraint string) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return fmt.Errorf("cannot parse %s: %w", file, err)
+	}
+
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("%s must contain a mapping", file)
+	}
+
+	if err := setConfigValue(setConfigMappingNode(document.Content[0], "build"), "shopwareVersionConstraint", constraint); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(detectYAMLIndent(content))
+
+	if err := encoder.Encode(&document); err != nil {
+		return err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, buf.Bytes(), 0o644)
+}
+
+// SetShopwareVersionConstraint writes the constraint where GetShopwareVersionConstraint reads it from: build.shopwareVersionConstraint
+// of the extension config, the compatibility of the manifest.xml or require.shopware/core of the composer.json. It returns the changed file.
+func SetShopwareVersionConstraint(ext Extension, constraint string) (string, error) {
+	if cfg := ext.GetExtensionConfig(); cfg != nil && cfg.Build.ShopwareVersionConstraint != "" {
+		cfg.Build.ShopwareVersionConstraint = constraint
+		configFile := filepath.Join(ext.GetPath(), cfg.FileName)
+
+		return configFile, setConfigShopwareVersionConstraint(configFile, constraint)
+	}
+
+	if ext.GetType() == TypePlatformApp {
+		manifestFile := filepath.Join(ext.GetPath(), "manifest.xml")
+
+		content, err := os.ReadFile(manifestFile)
+		if err != nil {
+			return "", err
+		}
+
+		var updated []byte
+
+		if loc := manifestMetaCompatibilityRegExp.FindSubmatchIndex(content); loc != nil {
+			updated = append(updated, content[:loc[3]]...)
+			updated = append(updated, constraint...)
+			updated = append(updated, content[loc[4]:]...)
+		} else if loc := manifestMetaVersionEndRegExp.FindIndex(content); loc != nil {
+			line := string(content[strings.LastIndex(string(content[:loc[1]]), "\n")+1 : loc[1]])
+			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+
+			updated = append(updated, content[:loc[1]]...)
+			updated = append(updated, "\n"+indent+"<compatibility>"+constraint+"</compatibility>"...)
+			updated = append(updated, content[loc[1]:]...)
+		} else {
+			return "", fmt.Errorf("cannot find the version in %s", manifestFile)
+		}
+
+		return manifestFile, os.WriteFile(manifestFile, updated, os.ModePerm)
+	}
+
+	composerFile := filepath.Join(ext.GetPath(), "composer.json")
+
+	content, err := os.ReadFile(composerFile)
+	if err != nil {
+		return "", err
+	}
+
+	parsed, err := parseOrderedJSON(content)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse %s: %w", composerFile, err)
+	}
+
+	composer, ok := parsed.(*orderedObject)
+	if !ok {
+		return "", fmt.Errorf("%s does not contain an object", composerFile)
+	}
+
+	require, ok := composer.values["require"].(*orderedObject)
+	if !ok {
+		require = &orderedObject{values: map[string]any{}}
+		composer.set("require", require)
+	}
+
+	require.set("shopware/core", constraint)
+
+	return composerFile, os.WriteFile(composerFile, encodeOrderedJSON(composer, detectJSONIndent(content)), os.ModePerm)
+}
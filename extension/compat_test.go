@@ -0,0 +1,149 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var compatTestVersions = []string{"6.4.20.2", "6.5.0.0", "6.5.8.18", "6.6.0.0-rc1", "6.6.0.0", "v6.6.10.3", "6.7.0.0"}
+
+func newCompatTestPlugin(t *testing.T, constraint, component string) PlatformPlugin {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	componentDir := filepath.Join(tmpDir, "src", "Resources", "app", "administration", "src", "component")
+	require.NoError(t, os.MkdirAll(componentDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(componentDir, "my-component.html.twig"), []byte("<div>\n    <"+component+">Save</"+component+">\n</div>\n"), os.ModePerm))
+
+	ext := PlatformPlugin{path: tmpDir, config: &Config{}}
+	ext.Composer.Autoload.Psr4 = map[string]string{"MyPlugin\\": "src/"}
+	ext.Composer.Require = map[string]string{"shopware/core": constraint}
+
+	return ext
+}
+
+func TestDetectCompatibilityRaisesLowerBound(t *testing.T) {
+	ext := newCompatTestPlugin(t, "~6.5.0 || ~6.6.0", "mt-button")
+
+	report, err := DetectCompatibility(getTestContext(), ext, compatTestVersions)
+	require.NoError(t, err)
+
+	assert.Equal(t, ">=6.6.0.0 <6.7.0.0", report.Suggested)
+	assert.Equal(t, "6.6.0.0", report.Oldest)
+	assert.Equal(t, []string{"6.5.0.0", "6.5.8.18"}, report.Unsupported)
+	assert.Len(t, report.Requirements, 1)
+	assert.Equal(t, "mt-button", report.Requirements[0].Name)
+	assert.Equal(t, "6.6.0.0", report.Requirements[0].Added)
+	assert.Equal(t, "Resources/app/administration/src/component/my-component.html.twig:2", report.Requirements[0].Location)
+}
+
+func TestDetectCompatibilityLowersUpperBoundBeforeRemoval(t *testing.T) {
+	ext := newCompatTestPlugin(t, ">=6.5", "sw-button")
+
+	report, err := DetectCompatibility(getTestContext(), ext, compatTestVersions)
+	require.NoError(t, err)
+
+	assert.Equal(t, ">=6.5.0.0 <6.7.0.0", report.Suggested)
+	assert.Equal(t, []string{"6.7.0.0"}, report.Unsupported)
+}
+
+func TestDetectCompatibilityWithoutTrackedAPIs(t *testing.T) {
+	ext := newCompatTestPlugin(t, "~6.6.0", "sw-page")
+
+	report, err := DetectCompatibility(getTestContext(), ext, compatTestVersions)
+	require.NoError(t, err)
+
+	assert.Empty(t, report.Requirements)
+	assert.Empty(t, report.Unsupported)
+	assert.Equal(t, ">=6.6.0.0 <6.7.0.0", report.Suggested)
+}
+
+func TestDetectCompatibilityWithoutCompatibleVersion(t *testing.T) {
+	ext := newCompatTestPlugin(t, "~6.5.0", "mt-button")
+
+	_, err := DetectCompatibility(getTestContext(), ext, compatTestVersions)
+	assert.ErrorContains(t, err, "the code requires at least Shopware 6.6.0.0")
+}
+
+func TestSetShopwareVersionConstraint(t *testing.T) {
+	ext := newGenerateTestPlugin(t)
+
+	file, err := SetShopwareVersionConstraint(ext, ">=6.6.0.0 <6.7.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(ext.GetPath(), "composer.json"), file)
+
+	assert.Contains(t, readGeneratedFile(t, ext, "composer.json"), `"shopware/core": ">=6.6.0.0 <6.7.0.0"`)
+
+	ext, err = GetExtensionByFolder(ext.GetPath())
+	require.NoError(t, err)
+
+	_, err = ext.GetShopwareVersionConstraint()
+	assert.NoError(t, err)
+}
+
+func TestSetShopwareVersionConstraintOfExtensionConfig(t *testing.T) {
+	ext := newGenerateTestPlugin(t)
+	configFile := filepath.Join(ext.GetPath(), ".shopware-extension.yml")
+
+	require.NoError(t, os.WriteFile(configFile, []byte(`# Build settings
+build:
+    # Checked by the store
+    shopwareVersionConstraint: ~6.5.0
+    zip:
+        assets:
+            enabled: true
+`), os.ModePerm))
+
+	ext, err := GetExtensionByFolder(ext.GetPath())
+	require.NoError(t, err)
+
+	file, err := SetShopwareVersionConstraint(ext, ">=6.6.0.0 <6.7.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, configFile, file)
+
+	content, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, `# Build settings
+build:
+    # Checked by the store
+    shopwareVersionConstraint: '>=6.6.0.0 <6.7.0.0'
+    zip:
+        assets:
+            enabled: true
+`, string(content))
+}
+
+func TestSetShopwareVersionConstraintOfApp(t *testing.T) {
+	tmpDir := t.TempDir()
+	manifestFile := filepath.Join(tmpDir, "manifest.xml")
+
+	require.NoError(t, os.WriteFile(manifestFile, []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<manifest>
+    <meta>
+        <name>MyApp</name>
+        <version>1.0.0</version>
+    </meta>
+</manifest>
+`), os.ModePerm))
+
+	ext := App{path: tmpDir}
+
+	_, err := SetShopwareVersionConstraint(ext, "~6.6.0")
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(manifestFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "        <version>1.0.0</version>\n        <compatibility>~6.6.0</compatibility>\n    </meta>")
+
+	_, err = SetShopwareVersionConstraint(ext, "~6.7.0")
+	require.NoError(t, err)
+
+	content, err = os.ReadFile(manifestFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "<compatibility>~6.7.0</compatibility>")
+	assert.NotContains(t, string(content), "~6.6.0")
+}
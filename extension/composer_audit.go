@@ -0,0 +1,131 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/shopware/shopware-cli/internal/packagist"
+)
+
+// auditSeverities are the severities of the advisories database from low to critical
+var auditSeverities = []string{"low", "medium", "high", "critical"}
+
+const defaultAuditSeverity = "medium"
+
+// validateComposerAudit checks the locked composer dependencies against the security advisories of packagist.org like
+// composer audit, as the store review does not check them. Shopware and the packages it requires are provided by the shop.
+func validateComposerAudit(ctx context.Context, vc *ValidationContext) {
+	config := vc.Extension.GetExtensionConfig()
+	if config != nil && config.Validation.Audit.Disabled {
+		return
+	}
+
+	lock, err := packagist.ReadComposerLock(filepath.Join(vc.Extension.GetPath(), "composer.lock"))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			vc.AddWarning("composer.audit_failed", fmt.Sprintf("composer.lock: cannot audit the dependencies: %s", err.Error()))
+		}
+
+		return
+	}
+
+	var excluded []string
+	if config != nil {
+		excluded = config.Build.Zip.Composer.ExcludedPackages
+	}
+
+	packages := shippedComposerPackages(lock, excluded)
+	if len(packages) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		names = append(names, pkg.Name)
+	}
+
+	advisories, err := packagist.GetSecurityAdvisories(ctx, names)
+	if err != nil {
+		vc.AddWarning("composer.audit_failed", fmt.Sprintf("composer.lock: cannot fetch the security advisories: %s", err.Error()))
+		return
+	}
+
+	threshold := defaultAuditSeverity
+	if config != nil && slices.Contains(auditSeverities, config.Validation.Audit.Severity) {
+		threshold = config.Validation.Audit.Severity
+	}
+
+	for _, pkg := range packages {
+		for _, advisory := range advisories[pkg.Name] {
+			if !advisory.Affects(pkg.Version) {
+				continue
+			}
+
+			severity := advisory.Severity
+			if !slices.Contains(auditSeverities, severity) {
+				// Advisories without rating are treated like the default, so they are not silently downgraded
+				severity = defaultAuditSeverity
+			}
+
+			title := advisory.Title
+			if advisory.CVE != "" {
+				title = fmt.Sprintf("%s (%s)", title, advisory.CVE)
+			}
+
+			message := fmt.Sprintf("composer.lock: the package %s %s is affected by the %s severity advisory %s, see %s", pkg.Name, pkg.Version, severity, title, advisory.Link)
+
+			if slices.Index(auditSeverities, severity) >= slices.Index(auditSeverities, threshold) {
+				vc.AddError("composer.audit", message)
+			} else {
+				vc.AddWarning("composer.audit", message)
+			}
+		}
+	}
+}
+
+// shippedComposerPackages returns the locked packages without Shopware, the packages excluded from the zip and everything they require
+func shippedComposerPackages(lock *packagist.ComposerLock, excluded []string) []packagist.ComposerLockPackage {
+	provided := map[string]bool{}
+
+	var queue []string
+	for _, pkg := range lock.Packages {
+		if strings.HasPrefix(pkg.Name, "shopware/") || slices.Contains(excluded, pkg.Name) {
+			queue = append(queue, pkg.Name)
+		}
+	}
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		if provided[name] {
+			continue
+		}
+
+		provided[name] = true
+
+		if pkg := lock.GetPackage(name); pkg != nil {
+			for required := range pkg.Require {
+				queue = append(queue, required)
+			}
+		}
+	}
+
+	var packages []packagist.ComposerLockPackage
+
+	for _, pkg := range lock.Packages {
+		if !provided[pkg.Name] {
+			packages = append(packages, pkg)
+		}
+	}
+
+	slices.SortFunc(packages, func(a, b packagist.ComposerLockPackage) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return packages
+}
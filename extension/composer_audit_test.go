@@ -0,0 +1,133 @@
+package extension
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shopware/shopware-cli/internal/packagist"
+)
+
+const auditTestComposerLock = `{
+	"packages": [
+		{"name": "shopware/core", "version": "6.6.10.0", "require": {"guzzlehttp/guzzle": "^7.8", "symfony/http-kernel": "^7.1"}},
+		{"name": "guzzlehttp/guzzle", "version": "7.4.0", "require": {"guzzlehttp/psr7": "^2.0"}},
+		{"name": "guzzlehttp/psr7", "version": "2.1.0"},
+		{"name": "symfony/http-kernel", "version": "v7.1.0"},
+		{"name": "league/csv", "version": "9.0.0"},
+		{"name": "dompdf/dompdf", "version": "v2.0.0"}
+	],
+	"packages-dev": [
+		{"name": "phpunit/phpunit", "version": "9.0.0"}
+	]
+}`
+
+type auditTestTransport struct {
+	server *httptest.Server
+}
+
+func (a auditTestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	target, _ := url.Parse(a.server.URL)
+	req.URL.Scheme = target.Scheme
+	req.URL.Host = target.Host
+
+	return a.server.Client().Transport.RoundTrip(req)
+}
+
+func newAuditTestPlugin(t *testing.T, advisories string) PlatformPlugin {
+	t.Helper()
+
+	originalClient := http.DefaultClient
+	t.Cleanup(func() {
+		http.DefaultClient = originalClient
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, []string{"dompdf/dompdf", "league/csv"}, r.PostForm["packages[]"])
+
+		_, _ = w.Write([]byte(advisories))
+	}))
+	t.Cleanup(server.Close)
+
+	http.DefaultClient = &http.Client{Transport: auditTestTransport{server: server}}
+
+	tmpDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "composer.lock"), []byte(auditTestComposerLock), os.ModePerm))
+
+	return PlatformPlugin{path: tmpDir, config: &Config{}}
+}
+
+func TestShippedComposerPackages(t *testing.T) {
+	tmpDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "composer.lock"), []byte(auditTestComposerLock), os.ModePerm))
+
+	lock, err := packagist.ReadComposerLock(filepath.Join(tmpDir, "composer.lock"))
+	assert.NoError(t, err)
+
+	var names []string
+	for _, pkg := range shippedComposerPackages(lock, nil) {
+		names = append(names, pkg.Name)
+	}
+
+	assert.Equal(t, []string{"dompdf/dompdf", "league/csv"}, names)
+
+	names = nil
+	for _, pkg := range shippedComposerPackages(lock, []string{"league/csv"}) {
+		names = append(names, pkg.Name)
+	}
+
+	assert.Equal(t, []string{"dompdf/dompdf"}, names)
+}
+
+func TestValidateComposerAudit(t *testing.T) {
+	ext := newAuditTestPlugin(t, `{"advisories": {
+		"dompdf/dompdf": [
+			{"title": "Remote code execution", "cve": "CVE-2023-24813", "link": "https://github.com/advisories/GHSA-1", "affectedVersions": "<2.0.3", "severity": "critical"},
+			{"title": "Denial of service", "link": "https://github.com/advisories/GHSA-2", "affectedVersions": "<2.0.1", "severity": "low"},
+			{"title": "Fixed long ago", "link": "https://github.com/advisories/GHSA-3", "affectedVersions": "<1.0.0", "severity": "high"}
+		],
+		"league/csv": [
+			{"title": "Unrated", "link": "https://github.com/advisories/GHSA-4", "affectedVersions": ">=9.0.0,<9.1.0", "severity": null}
+		]
+	}}`)
+
+	check := newValidationContext(ext)
+	validateComposerAudit(t.Context(), check)
+
+	assert.Len(t, check.Errors(), 2)
+	assert.Equal(t, "composer.audit", check.Errors()[0].Identifier)
+	assert.Equal(t, "composer.lock: the package dompdf/dompdf v2.0.0 is affected by the critical severity advisory Remote code execution (CVE-2023-24813), see https://github.com/advisories/GHSA-1", check.Errors()[0].Message)
+	assert.Contains(t, check.Errors()[1].Message, "league/csv 9.0.0 is affected by the medium severity advisory Unrated")
+
+	assert.Len(t, check.Warnings(), 1)
+	assert.Contains(t, check.Warnings()[0].Message, "the low severity advisory Denial of service")
+}
+
+func TestValidateComposerAuditSeverity(t *testing.T) {
+	ext := newAuditTestPlugin(t, `{"advisories": {"dompdf/dompdf": [{"title": "Remote code execution", "affectedVersions": "<2.0.3", "severity": "high"}]}}`)
+	ext.config.Validation.Audit.Severity = "critical"
+
+	check := newValidationContext(ext)
+	validateComposerAudit(t.Context(), check)
+
+	assert.Empty(t, check.Errors())
+	assert.Len(t, check.Warnings(), 1)
+}
+
+func TestValidateComposerAuditDisabled(t *testing.T) {
+	ext := PlatformPlugin{path: t.TempDir(), config: &Config{}}
+	ext.config.Validation.Audit.Disabled = true
+	assert.NoError(t, os.WriteFile(filepath.Join(ext.path, "composer.lock"), []byte("invalid"), os.ModePerm))
+
+	check := newValidationContext(ext)
+	validateComposerAudit(t.Context(), check)
+
+	assert.Empty(t, check.Errors())
+	assert.Empty(t, check.Warnings())
+}
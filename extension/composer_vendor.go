@@ -0,0 +1,106 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// vendorDevDirs are removed from the root folder of each bundled package, they are never autoloaded in production
+var vendorDevDirs = []string{"tests", "Tests", "test", "docs", "doc", ".github", ".gitlab", "examples"}
+
+// vendorDevFilePrefixes are configs of the tooling of the bundled packages like phpunit.xml.dist or phpstan.neon
+var vendorDevFilePrefixes = []string{
+	".editorconfig",
+	".gitattributes",
+	".gitignore",
+	".php-cs-fixer",
+	".php_cs",
+	".scrutinizer",
+	".travis",
+	"phpcs.xml",
+	"phpstan.neon",
+	"phpunit.xml",
+	"psalm.xml",
+	"infection.json",
+	"Makefile",
+}
+
+func composerInstallArgs(path string, cfg ConfigBuildZipComposer) []string {
+	args := []string{"install", "-d", path, "--no-dev", "-n", "-o"}
+
+	if cfg.ClassmapAuthoritative {
+		args = append(args, "--classmap-authoritative")
+	}
+
+	return args
+}
+
+// setComposerPlatformPHP resolves the dependencies for the PHP version instead of the PHP running composer
+func setComposerPlatformPHP(composer map[string]interface{}, phpVersion string) {
+	config, ok := composer["config"].(map[string]interface{})
+	if !ok {
+		config = make(map[string]interface{})
+		composer["config"] = config
+	}
+
+	platform, ok := config["platform"].(map[string]interface{})
+	if !ok {
+		platform = make(map[string]interface{})
+		config["platform"] = platform
+	}
+
+	platform["php"] = phpVersion
+}
+
+// removeVendorDevFiles deletes the tests, docs and tooling configs in the root folders of the packages like vendor/foo/bar
+// and returns the amount of removed paths
+func removeVendorDevFiles(vendorDir string) (int, error) {
+	packages, err := filepath.Glob(filepath.Join(vendorDir, "*", "*"))
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+
+	for _, pkg := range packages {
+		// vendor/composer and vendor/bin contain the autoloader and binaries
+		if vendor := filepath.Base(filepath.Dir(pkg)); vendor == "composer" || vendor == "bin" {
+			continue
+		}
+
+		entries, err := os.ReadDir(pkg)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			if !isVendorDevFile(entry) {
+				continue
+			}
+
+			if err := os.RemoveAll(filepath.Join(pkg, entry.Name())); err != nil {
+				return removed, err
+			}
+
+			removed++
+		}
+	}
+
+	return removed, nil
+}
+
+func isVendorDevFile(entry os.DirEntry) bool {
+	if entry.IsDir() {
+		return slices.Contains(vendorDevDirs, entry.Name())
+	}
+
+	for _, prefix := range vendorDevFilePrefixes {
+		if strings.HasPrefix(entry.Name(), prefix) {
+			return true
+		}
+	}
+
+	return false
+}
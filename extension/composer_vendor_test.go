@@ -0,0 +1,70 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComposerInstallArgs(t *testing.T) {
+	assert.Equal(t, []string{"install", "-d", "ext", "--no-dev", "-n", "-o"}, composerInstallArgs("ext", ConfigBuildZipComposer{}))
+	assert.Equal(t, []string{"install", "-d", "ext", "--no-dev", "-n", "-o", "--classmap-authoritative"}, composerInstallArgs("ext", ConfigBuildZipComposer{ClassmapAuthoritative: true}))
+}
+
+func TestSetComposerPlatformPHP(t *testing.T) {
+	composer := map[string]interface{}{
+		"config": map[string]interface{}{"sort-packages": true},
+	}
+
+	setComposerPlatformPHP(composer, "8.1")
+
+	assert.Equal(t, map[string]interface{}{
+		"sort-packages": true,
+		"platform":      map[string]interface{}{"php": "8.1"},
+	}, composer["config"])
+
+	composer = map[string]interface{}{}
+	setComposerPlatformPHP(composer, "8.2")
+
+	assert.Equal(t, map[string]interface{}{"platform": map[string]interface{}{"php": "8.2"}}, composer["config"])
+}
+
+func TestRemoveVendorDevFiles(t *testing.T) {
+	vendor := t.TempDir()
+
+	files := []string{
+		"foo/bar/src/Bar.php",
+		"foo/bar/tests/BarTest.php",
+		"foo/bar/phpunit.xml.dist",
+		"foo/bar/.gitattributes",
+		"foo/bar/LICENSE",
+		"foo/bar/composer.json",
+		"foo/bar/src/tests/Fixture.php",
+		"composer/autoload_classmap.php",
+		"composer/installed.json",
+		"bin/phpunit",
+	}
+
+	for _, file := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(vendor, file)), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(vendor, file), []byte("x"), os.ModePerm))
+	}
+
+	removed, err := removeVendorDevFiles(vendor)
+	require.NoError(t, err)
+	assert.Equal(t, 3, removed)
+
+	assert.NoDirExists(t, filepath.Join(vendor, "foo/bar/tests"))
+	assert.NoFileExists(t, filepath.Join(vendor, "foo/bar/phpunit.xml.dist"))
+	assert.NoFileExists(t, filepath.Join(vendor, "foo/bar/.gitattributes"))
+
+	// Only the root of the packages is cleaned up
+	assert.FileExists(t, filepath.Join(vendor, "foo/bar/src/tests/Fixture.php"))
+	assert.FileExists(t, filepath.Join(vendor, "foo/bar/LICENSE"))
+	assert.FileExists(t, filepath.Join(vendor, "foo/bar/composer.json"))
+	assert.FileExists(t, filepath.Join(vendor, "composer/installed.json"))
+	assert.FileExists(t, filepath.Join(vendor, "bin/phpunit"))
+}
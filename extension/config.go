@@ -5,6 +5,9 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/invopop/jsonschema"
 	orderedmap "github.com/wk8/go-ordered-map/v2"
@@ -32,6 +35,18 @@ type ConfigBuildZip struct {
 	Pack ConfigBuildZipPack `yaml:"pack,omitempty"`
 
 	Checksum ConfigBuildZipChecksum `yaml:"checksum,omitempty"`
+	// Configuration for the scan of the zip content for credentials
+	Secrets ConfigBuildZipSecrets `yaml:"secrets,omitempty"`
+	// When enabled, a zip is built for each Shopware major version allowed by the composer constraint
+	Matrix bool `yaml:"matrix,omitempty"`
+}
+
+// ConfigBuildZipSecrets configures the scan for API keys, private keys, .env files and composer credentials before zipping.
+type ConfigBuildZipSecrets struct {
+	// Findings with this severity or higher fail the build, off only reports them. Defaults to error. Findings in vendor are warnings.
+	FailOn ConfigValidationSeverity `yaml:"fail_on,omitempty"`
+	// Files which are not scanned, relative to the extension root. Folders and glob patterns are supported.
+	Ignore []string `yaml:"ignore,omitempty"`
 }
 
 // Configuration for checksum calculation.
@@ -49,6 +64,30 @@ type ConfigBuildZipComposer struct {
 	AfterHooks []string `yaml:"after_hooks,omitempty"`
 	// Composer packages to be excluded from the zip build
 	ExcludedPackages []string `yaml:"excluded_packages,omitempty"`
+	// Configuration for prefixing the namespaces of the bundled packages with PHP-Scoper
+	Scoper ConfigBuildZipComposerScoper `yaml:"scoper,omitempty"`
+	// When enabled, the composer dependencies are bundled into the vendor folder also for Shopware 6.5 and newer
+	BundleVendor bool `yaml:"bundle_vendor,omitempty"`
+	// PHP version like 8.2 the composer dependencies are resolved for, independent of the PHP of the build machine
+	PlatformPHP string `yaml:"platform_php,omitempty"`
+	// When enabled, the autoloader only loads classes from the classmap
+	ClassmapAuthoritative bool `yaml:"classmap_authoritative,omitempty"`
+	// When enabled, tests, docs and tooling configs of the bundled packages are removed from the vendor folder
+	RemoveDevFiles bool `yaml:"remove_dev_files,omitempty"`
+}
+
+// ConfigBuildZipComposerScoper configures PHP-Scoper, which prefixes the namespaces of the vendor folder to avoid conflicts with other extensions.
+type ConfigBuildZipComposerScoper struct {
+	// When enabled, the bundled composer packages are prefixed after the composer install
+	Enabled bool `yaml:"enabled"`
+	// Namespace prefix, defaults to the extension name followed by \Vendor
+	Prefix string `yaml:"prefix,omitempty"`
+	// Composer packages to prefix, defaults to all bundled packages
+	Include []string `yaml:"include,omitempty"`
+	// Composer packages which are not prefixed
+	Exclude []string `yaml:"exclude,omitempty"`
+	// Namespaces which are not prefixed, the namespaces of the extension and of Shopware, Symfony, Doctrine, Psr, Composer and Twig are always kept
+	ExcludeNamespaces []string `yaml:"exclude_namespaces,omitempty"`
 }
 
 type ConfigBuildZipAssets struct {
@@ -66,18 +105,36 @@ type ConfigBuildZipAssets struct {
 	DisableSass bool `yaml:"disable_sass"`
 	// When enabled, npm will install only production dependencies
 	NpmStrict bool `yaml:"npm_strict"`
+	// Size budgets of the compiled JavaScript files, the build fails when a file exceeds them
+	Budget ConfigBuildZipAssetsBudget `yaml:"budget,omitempty"`
+}
+
+// ConfigBuildZipAssetsBudget limits the size of each compiled JavaScript file, sizes are written like 2MB or 300KiB.
+type ConfigBuildZipAssetsBudget struct {
+	// Maximum size of each Administration JavaScript file
+	Administration string `yaml:"administration,omitempty"`
+	// Maximum size of each Storefront JavaScript file
+	Storefront string `yaml:"storefront,omitempty"`
+	// When enabled, the gzip compressed size is compared instead of the uncompressed size
+	Gzip bool `yaml:"gzip,omitempty"`
 }
 
 type ConfigBuildZipPackExcludes struct {
 	// Paths to exclude from the zip build
 	Paths []string `yaml:"paths,omitempty"`
+	// Patterns with the syntax of .gitignore to exclude from the zip build, they are applied after .sw-zip-blacklist and .zipignore
+	Patterns []string `yaml:"patterns,omitempty"`
 }
 
 type ConfigBuildZipPack struct {
 	// Excludes can be used to exclude files from the zip build
 	Excludes ConfigBuildZipPackExcludes `yaml:"excludes,omitempty"`
-	// Commands to run before the pack
+	// Commands to run before the pack, ARTIFACT_PATH, ARTIFACT_NAME, EXTENSION_NAME and EXTENSION_VERSION describe the zip file to be created
 	BeforeHooks []string `yaml:"before_hooks,omitempty"`
+	// Commands to run after the zip file and its checksum, SBOM and signature were created, with the same environment as before_hooks
+	AfterHooks []string `yaml:"after_hooks,omitempty"`
+	// When enabled, the compressed vendor folder is cached and only compressed again when composer.lock changes
+	VendorCache bool `yaml:"vendor_cache,omitempty"`
 }
 
 type ConfigExtraBundle struct {
@@ -98,7 +155,7 @@ type ConfigStore struct {
 	Categories *[]string `yaml:"categories" jsonschema:"enum=Administration,enum=SEOOptimierung,enum=Bonitaetsprüfung,enum=Rechtssicherheit,enum=Auswertung,enum=KommentarFeedback,enum=Tracking,enum=Integration,enum=PreissuchmaschinenPortale,enum=Warenwirtschaft,enum=Versand,enum=Bezahlung,enum=StorefrontDetailanpassungen,enum=Sprache,enum=Suche,enum=HeaderFooter,enum=Detailseite,enum=MenueKategorien,enum=Bestellprozess,enum=KundenkontoPersonalisierung,enum=Sonderfunktionen,enum=Themes,enum=Branche,enum=Home+Furnishings,enum=FashionBekleidung,enum=GartenNatur,enum=KosmetikGesundheit,enum=EssenTrinken,enum=KinderPartyGeschenke,enum=SportLifestyleReisen,enum=Bauhaus,enum=Elektronik,enum=Geraete,enum=Heimkueche,enum=Hobby,enum=Kueche,enum=Lebensmittel,enum=Medizin,enum=Mode,enum=Musik,enum=Spiel,enum=Technik,enum=Umweltschutz,enum=Wohnen,enum=Zubehoer"`
 	// Specifies the type of the extension.
 	Type *string `yaml:"type" jsonschema:"enum=extension,enum=theme"`
-	// Specifies the Path to the icon (256x256 px) for store.
+	// Specifies the Path to the icon (256x256 px) for store. SVG and WebP icons are converted to PNG.
 	Icon *string `yaml:"icon"`
 	// Specifies whether the extension should automatically be set compatible with Shopware bugfix versions.
 	AutomaticBugfixVersionCompatibility *bool `yaml:"automatic_bugfix_version_compatibility"`
@@ -124,6 +181,18 @@ type ConfigStore struct {
 	Images *[]ConfigStoreImage `yaml:"images,omitempty"`
 	// Specifies the directory where the images are located.
 	ImageDirectory *string `yaml:"image_directory,omitempty"`
+	// Specifies the price models (free, buy, rent) of the extension in store.
+	PriceModels *[]ConfigStorePriceModel `yaml:"price_models,omitempty"`
+	// Specifies what account producer extension upload does when the version exists already, can be overwritten with --on-conflict.
+	UploadOnConflict *string `yaml:"upload_on_conflict,omitempty" jsonschema:"enum=skip,enum=replace-if-not-reviewed,enum=bump-patch,enum=fail"`
+	// Specifies the features which can be bought inside of the extension, account producer extension info push synchronizes them to the store.
+	InAppFeatures *[]ConfigStoreInAppFeature `yaml:"in_app_features,omitempty"`
+	// Commands to run before account producer extension upload uploads the zip, ARTIFACT_PATH, ARTIFACT_NAME, EXTENSION_NAME and EXTENSION_VERSION describe the zip file
+	BeforeUploadHooks []string `yaml:"before_upload_hooks,omitempty"`
+	// Opens an issue in the tracker of the team when the automatic code review after an upload failed.
+	ReviewIssue *ConfigStoreReviewIssue `yaml:"review_issue,omitempty"`
+	// Pages of a demo shop which extension store screenshot captures into the image directory.
+	Screenshots *ConfigStoreScreenshots `yaml:"screenshots,omitempty"`
 }
 
 type Translatable interface {
@@ -151,6 +220,72 @@ type ConfigStoreImage struct {
 	Priority int `yaml:"priority"`
 }
 
+type ConfigStorePriceModel struct {
+	// Specifies the license model.
+	Type string `yaml:"type" jsonschema:"enum=free,enum=buy,enum=rent"`
+	// Specifies the default net price in EUR. Must be empty for free extensions.
+	Price float64 `yaml:"price,omitempty"`
+	// Specifies whether a free test phase is included. Only possible for rent.
+	TrialPhase bool `yaml:"trial_phase,omitempty"`
+	// Specifies country specific prices, overriding the default price.
+	CountryPrices []ConfigStoreCountryPrice `yaml:"country_prices,omitempty"`
+}
+
+type ConfigStoreInAppFeature struct {
+	// Identifier the extension checks the license of the feature with, like premium-export.
+	Identifier string `yaml:"identifier"`
+	// Specifies the name of the feature in store.
+	Name ConfigTranslated[string] `yaml:"name"`
+	// Specifies the description of the feature in store.
+	Description ConfigTranslated[string] `yaml:"description,omitempty"`
+	// Specifies the license model of the feature.
+	Type string `yaml:"type" jsonschema:"enum=buy,enum=rent"`
+	// Specifies the net price in EUR, for rent per month.
+	Price float64 `yaml:"price"`
+	// When enabled, the feature cannot be bought anymore. Existing purchases stay valid.
+	Disabled bool `yaml:"disabled,omitempty"`
+}
+
+type ConfigStoreReviewIssue struct {
+	// Issue tracker to open the issue in. GitHub requires GITHUB_TOKEN, GitLab GITLAB_TOKEN and Jira JIRA_USER and JIRA_API_TOKEN.
+	Provider string `yaml:"provider" jsonschema:"required,enum=github,enum=gitlab,enum=jira"`
+	// GitHub repository like owner/name, GitLab project path or ID or Jira project key.
+	Project string `yaml:"project" jsonschema:"required"`
+	// API URL of GitHub Enterprise or a self-hosted GitLab, required for Jira as URL of the site like https://acme.atlassian.net.
+	Url string `yaml:"url,omitempty"`
+	// Users the issue is assigned to: GitHub logins, GitLab usernames or Jira account IDs.
+	Assignees []string `yaml:"assignees,omitempty"`
+	// Labels added to the issue.
+	Labels []string `yaml:"labels,omitempty"`
+}
+
+type ConfigStoreScreenshots struct {
+	// URL of the demo shop, can be overwritten with --shop-url.
+	ShopUrl string `yaml:"shop_url,omitempty"`
+	// Size of the screenshots, the store shows images in 16:9.
+	Resolution string `yaml:"resolution,omitempty" jsonschema:"enum=1280x720,enum=1920x1080,enum=2560x1440"`
+	// Pages to capture in the order of their priority in store.
+	Pages []ConfigStoreScreenshotPage `yaml:"pages"`
+}
+
+type ConfigStoreScreenshotPage struct {
+	// Name used in the file name of the screenshot like product-detail.
+	Name string `yaml:"name" jsonschema:"required"`
+	// URL of the page in German and English, paths are relative to the shop URL like /en/detail/1.
+	Url ConfigTranslated[string] `yaml:"url" jsonschema:"required"`
+	// CSS selector of an element which is scrolled into the center of the screenshot.
+	Selector string `yaml:"selector,omitempty"`
+	// CSS selector of an element to wait for before capturing, defaults to the selector.
+	WaitFor string `yaml:"wait_for,omitempty"`
+}
+
+type ConfigStoreCountryPrice struct {
+	// ISO 3166-1 alpha-2 code of the country.
+	Country string `yaml:"country"`
+	// Net price in the given country.
+	Price float64 `yaml:"price"`
+}
+
 type ConfigStoreImageActivate struct {
 	German  bool `yaml:"de"`
 	English bool `yaml:"en"`
@@ -165,6 +300,84 @@ type ConfigStoreImagePreview struct {
 type ConfigValidation struct {
 	// Ignore items from the validation.
 	Ignore ConfigValidationList `yaml:"ignore,omitempty"`
+	// Change the severity of rules by their identifier, identifiers ending with * match all rules with this prefix.
+	Rules map[string]ConfigValidationSeverity `yaml:"rules,omitempty"`
+	// Path to a baseline file relative to the extension root, created with extension validate --generate-baseline. The findings listed in it are not reported.
+	Baseline string `yaml:"baseline,omitempty"`
+	// Run PHPStan as part of the validation.
+	PHPStan ConfigValidationPHPStan `yaml:"phpstan,omitempty"`
+	// Configure the PHP syntax linting.
+	PHPLint ConfigValidationPHPLint `yaml:"php_lint,omitempty"`
+	// Configure the allowed licenses of bundled composer and npm dependencies.
+	Licenses ConfigValidationLicenses `yaml:"licenses,omitempty"`
+	// Configure the validation of the manifest.xml of apps.
+	App ConfigValidationApp `yaml:"app,omitempty"`
+	// External validators, which report their findings as JSON on stdout.
+	External []ConfigValidationExternal `yaml:"external,omitempty"`
+	// Configure the security audit of the locked composer dependencies.
+	Audit ConfigValidationAudit `yaml:"audit,omitempty"`
+}
+
+// ConfigValidationAudit configures the check of the composer.lock against the security advisories of packagist.org.
+type ConfigValidationAudit struct {
+	// Disable the audit, e.g. for offline builds.
+	Disabled bool `yaml:"disabled,omitempty"`
+	// Advisories of this severity or higher are errors, lower ones are warnings. Defaults to medium.
+	Severity string `yaml:"severity,omitempty" jsonschema:"enum=low,enum=medium,enum=high,enum=critical"`
+}
+
+// ConfigValidationExternal is a command which checks the extension for own rules, like the house rules of an agency.
+type ConfigValidationExternal struct {
+	// Name of the validator, the identifiers of its findings are prefixed with it.
+	Name string `yaml:"name" jsonschema:"required"`
+	// Command executed with sh (cmd on Windows) in the extension directory, the path of the extension is passed as last argument. It runs only for extension folders or with --allow-external-validators and is stopped after 5 minutes.
+	Command string `yaml:"command" jsonschema:"required"`
+}
+
+// ConfigValidationApp configures the validation of the manifest.xml of apps.
+type ConfigValidationApp struct {
+	// Send a request to every endpoint of the manifest.xml, like webhooks and payment URLs, to check that the app server is reachable.
+	CheckUrls bool `yaml:"check_urls,omitempty"`
+}
+
+// ConfigValidationLicenses configures the license check of the dependencies bundled into the zip.
+type ConfigValidationLicenses struct {
+	// SPDX identifiers of the allowed licenses. Defaults to common permissive licenses, the license of the extension is always allowed.
+	Allowed []string `yaml:"allowed,omitempty"`
+}
+
+// ConfigValidationPHPLint configures against which PHP versions the PHP files are linted.
+type ConfigValidationPHPLint struct {
+	// PHP versions to lint against, e.g. 8.1, 8.2 and 8.3. The minimum PHP version of the composer.json is always linted.
+	Versions []string `yaml:"versions,omitempty"`
+}
+
+// ConfigValidationPHPStan configures PHPStan for extension validate without --full.
+type ConfigValidationPHPStan struct {
+	// Run PHPStan on every validation.
+	Enabled bool `yaml:"enabled,omitempty"`
+	// The rule level of PHPStan, defaults to 5.
+	Level *int `yaml:"level,omitempty" jsonschema:"minimum=0,maximum=10"`
+	// The severity of the PHPStan findings, defaults to error.
+	Severity string `yaml:"severity,omitempty" jsonschema:"enum=error,enum=warning"`
+	// Path to a PHPStan baseline file relative to the extension root.
+	Baseline string `yaml:"baseline,omitempty"`
+}
+
+// ConfigValidationSeverity is the severity of a validation rule, off disables the rule.
+type ConfigValidationSeverity string
+
+const (
+	ValidationSeverityError   ConfigValidationSeverity = "error"
+	ValidationSeverityWarning ConfigValidationSeverity = "warning"
+	ValidationSeverityOff     ConfigValidationSeverity = "off"
+)
+
+func (ConfigValidationSeverity) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: []any{ValidationSeverityError, ValidationSeverityWarning, ValidationSeverityOff},
+	}
 }
 
 type ConfigValidationList []ConfigValidationIgnoreItem
@@ -184,6 +397,8 @@ type ConfigValidationIgnoreItem struct {
 	Path string `yaml:"path,omitempty"`
 	// Optional to ignore only a specific message.
 	Message string `yaml:"message,omitempty"`
+	// Why the item is ignored, documents the decision for other developers. It is required by extension validate.
+	Reason string `yaml:"reason,omitempty"`
 }
 
 func (c *ConfigValidationIgnoreItem) UnmarshalYAML(value *yaml.Node) error {
@@ -196,6 +411,7 @@ func (c *ConfigValidationIgnoreItem) UnmarshalYAML(value *yaml.Node) error {
 		Identifier string `yaml:"identifier"`
 		Path       string `yaml:"path,omitempty"`
 		Message    string `yaml:"message,omitempty"`
+		Reason     string `yaml:"reason,omitempty"`
 	}
 	var obj objectFormat
 	if err := value.Decode(&obj); err != nil {
@@ -205,6 +421,7 @@ func (c *ConfigValidationIgnoreItem) UnmarshalYAML(value *yaml.Node) error {
 	c.Identifier = obj.Identifier
 	c.Path = obj.Path
 	c.Message = obj.Message
+	c.Reason = obj.Reason
 
 	return nil
 }
@@ -222,6 +439,16 @@ func (c ConfigValidationIgnoreItem) JSONSchema() *jsonschema.Schema {
 		Description: "The path of the item to ignore.",
 	})
 
+	ordMap.Set("message", &jsonschema.Schema{
+		Type:        "string",
+		Description: "Only ignore items containing this message.",
+	})
+
+	ordMap.Set("reason", &jsonschema.Schema{
+		Type:        "string",
+		Description: "Why the item is ignored, documents the decision for other developers. It is required by extension validate.",
+	})
+
 	return &jsonschema.Schema{
 		OneOf: []*jsonschema.Schema{
 			{
@@ -245,6 +472,27 @@ type Config struct {
 	Changelog changelog.Config `yaml:"changelog,omitempty"`
 	// Validation is the validation configuration of the extension.
 	Validation ConfigValidation `yaml:"validation,omitempty"`
+	// Release configures the release extension release --github creates in the repository of the extension.
+	Release ConfigRelease `yaml:"release,omitempty"`
+}
+
+type ConfigRelease struct {
+	// Hosting of the repository, GitHub requires GITHUB_TOKEN and GitLab GITLAB_TOKEN.
+	Provider string `yaml:"provider,omitempty" jsonschema:"enum=github,enum=gitlab"`
+	// GitHub repository like owner/name or GitLab project path or ID, defaults to the repository of the CI job.
+	Repository string `yaml:"repository,omitempty"`
+	// API URL of GitHub Enterprise or a self-hosted GitLab.
+	Url string `yaml:"url,omitempty"`
+	// Tag of the release, {version} is replaced with the extension version. Defaults to {version}.
+	Tag string `yaml:"tag,omitempty"`
+	// Name of the release, {version} is replaced with the extension version. Defaults to the tag.
+	Name string `yaml:"name,omitempty"`
+	// Branch or commit the tag is created from when it does not exist, defaults to the commit of the CI job.
+	Target string `yaml:"target,omitempty"`
+	// Creates the GitHub release as draft.
+	Draft bool `yaml:"draft,omitempty"`
+	// Marks the GitHub release as prerelease.
+	Prerelease bool `yaml:"prerelease,omitempty"`
 }
 
 func readExtensionConfig(dir string) (*Config, error) {
@@ -302,6 +550,172 @@ func validateExtensionConfig(config *Config) error {
 		return fmt.Errorf("store.info.videos.de can contain maximal 2 items")
 	}
 
+	if config.Store.PriceModels != nil {
+		if err := validatePriceModels(*config.Store.PriceModels); err != nil {
+			return err
+		}
+	}
+
+	if config.Store.InAppFeatures != nil {
+		if err := validateInAppFeatures(*config.Store.InAppFeatures); err != nil {
+			return err
+		}
+	}
+
+	if issue := config.Store.ReviewIssue; issue != nil {
+		if err := validateReviewIssue(*issue); err != nil {
+			return err
+		}
+	}
+
+	if provider := config.Release.Provider; provider != "" && provider != "github" && provider != "gitlab" {
+		return fmt.Errorf("release.provider must be github or gitlab, got %q", provider)
+	}
+
+	if screenshots := config.Store.Screenshots; screenshots != nil {
+		if err := validateScreenshots(*screenshots); err != nil {
+			return err
+		}
+	}
+
+	if onConflict := config.Store.UploadOnConflict; onConflict != nil && !slices.Contains([]string{"skip", "replace-if-not-reviewed", "bump-patch", "fail"}, *onConflict) {
+		return fmt.Errorf("store.upload_on_conflict must be skip, replace-if-not-reviewed, bump-patch or fail, got %q", *onConflict)
+	}
+
+	if failOn := config.Build.Zip.Secrets.FailOn; failOn != "" && failOn != ValidationSeverityError && failOn != ValidationSeverityWarning && failOn != ValidationSeverityOff {
+		return fmt.Errorf("build.zip.secrets.fail_on must be error, warning or off, got %q", failOn)
+	}
+
+	for identifier, severity := range config.Validation.Rules {
+		if severity != ValidationSeverityError && severity != ValidationSeverityWarning && severity != ValidationSeverityOff {
+			return fmt.Errorf("validation.rules.%s must be error, warning or off, got %q", identifier, severity)
+		}
+	}
+
+	return nil
+}
+
+func validateReviewIssue(issue ConfigStoreReviewIssue) error {
+	if !slices.Contains([]string{"github", "gitlab", "jira"}, issue.Provider) {
+		return fmt.Errorf("store.review_issue.provider must be github, gitlab or jira, got %q", issue.Provider)
+	}
+
+	if issue.Project == "" {
+		return fmt.Errorf("store.review_issue.project is required")
+	}
+
+	if issue.Provider == "jira" && issue.Url == "" {
+		return fmt.Errorf("store.review_issue.url is required for jira")
+	}
+
+	return nil
+}
+
+// StoreScreenshotResolutions are the 16:9 sizes extension store screenshot captures
+var StoreScreenshotResolutions = []string{"1280x720", "1920x1080", "2560x1440"}
+
+var screenshotNameRegExp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+func validateScreenshots(screenshots ConfigStoreScreenshots) error {
+	if screenshots.Resolution != "" && !slices.Contains(StoreScreenshotResolutions, screenshots.Resolution) {
+		return fmt.Errorf("store.screenshots.resolution must be one of %s, got %q", strings.Join(StoreScreenshotResolutions, ", "), screenshots.Resolution)
+	}
+
+	seen := make(map[string]bool)
+
+	for _, page := range screenshots.Pages {
+		if !screenshotNameRegExp.MatchString(page.Name) {
+			return fmt.Errorf("store.screenshots.pages: name %q must only contain letters, numbers, - and _", page.Name)
+		}
+
+		if seen[page.Name] {
+			return fmt.Errorf("store.screenshots.pages contains the name %s multiple times", page.Name)
+		}
+
+		seen[page.Name] = true
+
+		if page.Url.German == nil && page.Url.English == nil {
+			return fmt.Errorf("store.screenshots.pages: %s requires a german or english url", page.Name)
+		}
+	}
+
+	return nil
+}
+
+var inAppFeatureIdentifierRegExp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+func validateInAppFeatures(features []ConfigStoreInAppFeature) error {
+	seen := make(map[string]bool)
+
+	for _, feature := range features {
+		if !inAppFeatureIdentifierRegExp.MatchString(feature.Identifier) {
+			return fmt.Errorf("store.in_app_features: identifier %q must only contain lowercase letters, numbers, - and _", feature.Identifier)
+		}
+
+		if seen[feature.Identifier] {
+			return fmt.Errorf("store.in_app_features contains the identifier %s multiple times", feature.Identifier)
+		}
+
+		seen[feature.Identifier] = true
+
+		if feature.Type != "buy" && feature.Type != "rent" {
+			return fmt.Errorf("store.in_app_features: %s has the unknown type %s, must be one of buy, rent", feature.Identifier, feature.Type)
+		}
+
+		if feature.Price <= 0 {
+			return fmt.Errorf("store.in_app_features: %s requires a price greater than zero", feature.Identifier)
+		}
+
+		if feature.Name.German == nil || feature.Name.English == nil {
+			return fmt.Errorf("store.in_app_features: %s requires a german and english name", feature.Identifier)
+		}
+	}
+
+	return nil
+}
+
+func validatePriceModels(models []ConfigStorePriceModel) error {
+	seen := make(map[string]bool)
+
+	for _, model := range models {
+		if seen[model.Type] {
+			return fmt.Errorf("store.price_models contains the type %s multiple times", model.Type)
+		}
+
+		seen[model.Type] = true
+
+		switch model.Type {
+		case "free":
+			if model.Price != 0 || len(model.CountryPrices) > 0 {
+				return fmt.Errorf("store.price_models: free price model cannot have a price")
+			}
+		case "buy", "rent":
+			if model.Price <= 0 {
+				return fmt.Errorf("store.price_models: %s price model requires a price greater than zero", model.Type)
+			}
+		default:
+			return fmt.Errorf("store.price_models: unknown type %s, must be one of free, buy, rent", model.Type)
+		}
+
+		if model.TrialPhase && model.Type != "rent" {
+			return fmt.Errorf("store.price_models: trial_phase is only possible for rent")
+		}
+
+		for _, countryPrice := range model.CountryPrices {
+			if len(countryPrice.Country) != 2 {
+				return fmt.Errorf("store.price_models: country %q must be a two letter ISO code", countryPrice.Country)
+			}
+
+			if countryPrice.Price <= 0 {
+				return fmt.Errorf("store.price_models: price for country %s must be greater than zero", countryPrice.Country)
+			}
+		}
+	}
+
+	if seen["free"] && len(models) > 1 {
+		return fmt.Errorf("store.price_models: free cannot be combined with other price models")
+	}
+
 	return nil
 }
 
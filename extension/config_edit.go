@@ -0,0 +1,317 @@
+package extension
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The store limits the meta title and description, the schema uses the same limits
+const (
+	StoreMetaTitleMaxLength       = 50
+	StoreMetaDescriptionMaxLength = 185
+)
+
+// StoreMetadata are the store settings of the .shopware-extension.yml the interactive editor asks for
+type StoreMetadata struct {
+	Availabilities         []string
+	DefaultLocale          string
+	Localizations          []string
+	Categories             []string
+	Type                   string
+	MetaTitleGerman        string
+	MetaTitleEnglish       string
+	MetaDescriptionGerman  string
+	MetaDescriptionEnglish string
+}
+
+// ExtensionConfigFile returns the path of the config file in dir, .shopware-extension.yml when none exists yet
+func ExtensionConfigFile(dir string) string {
+	for _, name := range []string{".shopware-extension.yml", ".shopware-extension.yaml"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return filepath.Join(dir, name)
+		}
+	}
+
+	return filepath.Join(dir, ".shopware-extension.yml")
+}
+
+// ReadStoreMetadata returns the store settings of the config in dir, empty ones when there is no config
+func ReadStoreMetadata(dir string) (StoreMetadata, error) {
+	config, err := readExtensionConfig(dir)
+	if err != nil {
+		return StoreMetadata{}, err
+	}
+
+	store := config.Store
+	metadata := StoreMetadata{}
+
+	if store.Availabilities != nil {
+		metadata.Availabilities = *store.Availabilities
+	}
+
+	if store.DefaultLocale != nil {
+		metadata.DefaultLocale = *store.DefaultLocale
+	}
+
+	if store.Localizations != nil {
+		metadata.Localizations = *store.Localizations
+	}
+
+	if store.Categories != nil {
+		metadata.Categories = *store.Categories
+	}
+
+	if store.Type != nil {
+		metadata.Type = *store.Type
+	}
+
+	for target, value := range map[*string]*string{
+		&metadata.MetaTitleGerman:        store.MetaTitle.German,
+		&metadata.MetaTitleEnglish:       store.MetaTitle.English,
+		&metadata.MetaDescriptionGerman:  store.MetaDescription.German,
+		&metadata.MetaDescriptionEnglish: store.MetaDescription.English,
+	} {
+		if value != nil {
+			*target = *value
+		}
+	}
+
+	return metadata, nil
+}
+
+// Validate returns the problems the store would reject the metadata for
+func (m StoreMetadata) Validate() []string {
+	var problems []string
+
+	if len(m.Availabilities) == 0 {
+		problems = append(problems, "select at least one store availability")
+	}
+
+	if m.DefaultLocale != "" && len(m.Localizations) > 0 && !slices.Contains(m.Localizations, m.DefaultLocale) {
+		problems = append(problems, fmt.Sprintf("the default locale %s must be one of the localizations", m.DefaultLocale))
+	}
+
+	for name, value := range map[string]string{"meta title (de)": m.MetaTitleGerman, "meta title (en)": m.MetaTitleEnglish} {
+		if err := ValidateStoreText(value, StoreMetaTitleMaxLength); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", name, err))
+		}
+	}
+
+	for name, value := range map[string]string{"meta description (de)": m.MetaDescriptionGerman, "meta description (en)": m.MetaDescriptionEnglish} {
+		if err := ValidateStoreText(value, StoreMetaDescriptionMaxLength); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", name, err))
+		}
+	}
+
+	slices.Sort(problems)
+
+	return problems
+}
+
+// ValidateStoreText checks the length of a text the store limits
+func ValidateStoreText(value string, maxLength int) error {
+	if length := utf8.RuneCountInString(value); length > maxLength {
+		return fmt.Errorf("%d characters are too long, the store allows %d", length, maxLength)
+	}
+
+	return nil
+}
+
+// ConfigStoreChoices returns the values the schema allows for a store setting like categories, used when the store
+// cannot be asked for them
+func ConfigStoreChoices(property string) []string {
+	var root configSchema
+	if err := json.Unmarshal(extensionConfigSchema, &root); err != nil {
+		return nil
+	}
+
+	store, ok := root.Defs["ConfigStore"]
+	if !ok {
+		return nil
+	}
+
+	schema, ok := store.Properties[property]
+	if !ok {
+		return nil
+	}
+
+	if schema.Items != nil {
+		schema = schema.Items
+	}
+
+	choices := make([]string, 0, len(schema.Enum))
+
+	for _, value := range schema.Enum {
+		if choice, ok := value.(string); ok {
+			choices = append(choices, choice)
+		}
+	}
+
+	return choices
+}
+
+// WriteStoreMetadata sets the store settings in the config of dir. The other settings and comments of the file are kept,
+// empty settings are removed.
+func WriteStoreMetadata(dir string, metadata StoreMetadata) (string, error) {
+	file := ExtensionConfigFile(dir)
+
+	content, err := os.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return "", fmt.Errorf("cannot parse %s: %w", file, err)
+	}
+
+	if len(document.Content) == 0 {
+		document = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return "", fmt.Errorf("%s must contain a mapping", file)
+	}
+
+	store := setConfigMappingNode(root, "store")
+
+	values := []struct {
+		key   string
+		value any
+	}{
+		{"availabilities", metadata.Availabilities},
+		{"default_locale", metadata.DefaultLocale},
+		{"localizations", metadata.Localizations},
+		{"categories", metadata.Categories},
+		{"type", metadata.Type},
+	}
+
+	for _, value := range values {
+		if err := setConfigValue(store, value.key, value.value); err != nil {
+			return "", err
+		}
+	}
+
+	translated := []struct {
+		key             string
+		german, english string
+	}{
+		{"meta_title", metadata.MetaTitleGerman, metadata.MetaTitleEnglish},
+		{"meta_description", metadata.MetaDescriptionGerman, metadata.MetaDescriptionEnglish},
+	}
+
+	for _, value := range translated {
+		if value.german == "" && value.english == "" {
+			removeConfigValue(store, value.key)
+			continue
+		}
+
+		translation := setConfigMappingNode(store, value.key)
+
+		if err := setConfigValue(translation, "de", value.german); err != nil {
+			return "", err
+		}
+
+		if err := setConfigValue(translation, "en", value.english); err != nil {
+			return "", err
+		}
+	}
+
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(detectYAMLIndent(content))
+
+	if err := encoder.Encode(&document); err != nil {
+		return "", err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+
+	return file, os.WriteFile(file, buf.Bytes(), 0o644)
+}
+
+// setConfigMappingNode returns the mapping of the key, it is created when missing or empty
+func setConfigMappingNode(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			if mapping.Content[i+1].Kind != yaml.MappingNode {
+				mapping.Content[i+1] = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			}
+
+			return mapping.Content[i+1]
+		}
+	}
+
+	value := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+
+	return value
+}
+
+// setConfigValue replaces the value of the key and keeps its comments, empty values remove the key
+func setConfigValue(mapping *yaml.Node, key string, value any) error {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			removeConfigValue(mapping, key)
+			return nil
+		}
+	case []string:
+		if len(v) == 0 {
+			removeConfigValue(mapping, key)
+			return nil
+		}
+	}
+
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return err
+	}
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			node.LineComment = mapping.Content[i+1].LineComment
+			mapping.Content[i+1] = &node
+
+			return nil
+		}
+	}
+
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, &node)
+
+	return nil
+}
+
+func removeConfigValue(mapping *yaml.Node, key string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = slices.Delete(mapping.Content, i, i+2)
+			return
+		}
+	}
+}
+
+// detectYAMLIndent returns the indentation of the first indented line, defaulting to two spaces
+func detectYAMLIndent(content []byte) int {
+	for _, line := range strings.Split(string(content), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") && !strings.HasPrefix(trimmed, "-") && len(trimmed) != len(line) {
+			return len(line) - len(trimmed)
+		}
+	}
+
+	return 2
+}
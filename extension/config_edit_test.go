@@ -0,0 +1,105 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteStoreMetadataKeepsOtherSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, ".shopware-extension.yml")
+
+	require.NoError(t, os.WriteFile(configFile, []byte(`# Build settings
+build:
+  zip:
+    assets:
+      enabled: false
+store:
+  availabilities:
+    - German
+  categories: [Administration]
+  default_locale: de_DE # used for missing translations
+  tags:
+    en:
+      - tools
+`), os.ModePerm))
+
+	metadata, err := ReadStoreMetadata(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"German"}, metadata.Availabilities)
+	assert.Equal(t, []string{"Administration"}, metadata.Categories)
+
+	metadata.Availabilities = []string{"German", "International"}
+	metadata.Categories = []string{"Integration"}
+	metadata.Localizations = []string{"de_DE", "en_GB"}
+	metadata.DefaultLocale = "en_GB"
+	metadata.MetaTitleEnglish = "Tools"
+
+	file, err := WriteStoreMetadata(tmpDir, metadata)
+	require.NoError(t, err)
+	assert.Equal(t, configFile, file)
+
+	content, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, `# Build settings
+build:
+  zip:
+    assets:
+      enabled: false
+store:
+  availabilities:
+    - German
+    - International
+  categories:
+    - Integration
+  default_locale: en_GB # used for missing translations
+  tags:
+    en:
+      - tools
+  localizations:
+    - de_DE
+    - en_GB
+  meta_title:
+    en: Tools
+`, string(content))
+
+	config, err := readExtensionConfig(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, "Tools", *config.Store.MetaTitle.English)
+}
+
+func TestWriteStoreMetadataCreatesConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	_, err := WriteStoreMetadata(tmpDir, StoreMetadata{Availabilities: []string{"International"}, Type: "extension"})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, ".shopware-extension.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, "store:\n  availabilities:\n    - International\n  type: extension\n", string(content))
+}
+
+func TestStoreMetadataValidate(t *testing.T) {
+	metadata := StoreMetadata{
+		Localizations:    []string{"de_DE"},
+		DefaultLocale:    "en_GB",
+		MetaTitleEnglish: "This meta title is much longer than the store allows it to be",
+	}
+
+	assert.Equal(t, []string{
+		"meta title (en): 61 characters are too long, the store allows 50",
+		"select at least one store availability",
+		"the default locale en_GB must be one of the localizations",
+	}, metadata.Validate())
+}
+
+func TestConfigStoreChoices(t *testing.T) {
+	assert.Equal(t, []string{"German", "International"}, ConfigStoreChoices("availabilities"))
+	assert.Contains(t, ConfigStoreChoices("categories"), "Administration")
+	assert.Contains(t, ConfigStoreChoices("localizations"), "en_GB")
+	assert.Empty(t, ConfigStoreChoices("icon"))
+}
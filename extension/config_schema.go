@@ -0,0 +1,343 @@
+package extension
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed shopware-extension-schema.json
+var extensionConfigSchema []byte
+
+// ExtensionConfigSchema returns the JSON schema of the .shopware-extension.yml, e.g. for the autocompletion of IDEs
+func ExtensionConfigSchema() []byte {
+	return extensionConfigSchema
+}
+
+// ConfigProblem is a mistake in the .shopware-extension.yml
+type ConfigProblem struct {
+	Line int
+	// Path is the dotted path of the key, like build.zip.assets.enabled
+	Path    string
+	Message string
+}
+
+func (p ConfigProblem) String() string {
+	if p.Path == "" {
+		return fmt.Sprintf("%d: %s", p.Line, p.Message)
+	}
+
+	return fmt.Sprintf("%d: %s: %s", p.Line, p.Path, p.Message)
+}
+
+// configSchema is the subset of JSON schema the generated schema of the config uses
+type configSchema struct {
+	Ref                  string                   `json:"$ref"`
+	Defs                 map[string]*configSchema `json:"$defs"`
+	Type                 string                   `json:"type"`
+	Properties           map[string]*configSchema `json:"properties"`
+	AdditionalProperties json.RawMessage          `json:"additionalProperties"`
+	Items                *configSchema            `json:"items"`
+	Enum                 []any                    `json:"enum"`
+	Required             []string                 `json:"required"`
+	OneOf                []*configSchema          `json:"oneOf"`
+	Minimum              *float64                 `json:"minimum"`
+	Maximum              *float64                 `json:"maximum"`
+}
+
+// ValidateExtensionConfigFile checks the config file against the JSON schema, for options which exclude each other
+// and for the rules which are checked when the config is loaded
+func ValidateExtensionConfigFile(file string) ([]ConfigProblem, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return []ConfigProblem{{Message: err.Error()}}, nil
+	}
+
+	if len(document.Content) == 0 {
+		return nil, nil
+	}
+
+	var root configSchema
+	if err := json.Unmarshal(extensionConfigSchema, &root); err != nil {
+		return nil, fmt.Errorf("cannot parse the config schema: %w", err)
+	}
+
+	validator := configSchemaValidator{defs: root.Defs}
+	validator.validate(&root, document.Content[0], "")
+
+	problems := validator.problems
+	problems = append(problems, validateExclusiveConfigOptions(document.Content[0])...)
+
+	// The rules of validateExtensionConfig need a config, which can be decoded
+	if len(problems) == 0 {
+		var config Config
+		if err := document.Decode(&config); err != nil {
+			problems = append(problems, ConfigProblem{Line: document.Content[0].Line, Message: err.Error()})
+		} else if err := validateExtensionConfig(&config); err != nil {
+			problems = append(problems, ConfigProblem{Line: document.Content[0].Line, Message: err.Error()})
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+
+	return problems, nil
+}
+
+type configSchemaValidator struct {
+	defs     map[string]*configSchema
+	problems []ConfigProblem
+}
+
+func (v *configSchemaValidator) resolve(schema *configSchema) *configSchema {
+	for schema.Ref != "" {
+		resolved, ok := v.defs[strings.TrimPrefix(schema.Ref, "#/$defs/")]
+		if !ok {
+			return schema
+		}
+
+		schema = resolved
+	}
+
+	return schema
+}
+
+func (v *configSchemaValidator) addProblem(node *yaml.Node, path, format string, args ...any) {
+	v.problems = append(v.problems, ConfigProblem{Line: node.Line, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *configSchemaValidator) validate(schema *configSchema, node *yaml.Node, path string) {
+	schema = v.resolve(schema)
+
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	// Empty keys are decoded as zero value, like the loading of the config does
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+
+	if len(schema.OneOf) > 0 {
+		v.validateOneOf(schema, node, path)
+		return
+	}
+
+	if schema.Type != "" && !configNodeHasType(node, schema.Type) {
+		v.addProblem(node, path, "must be of type %s, got %s", schema.Type, configNodeType(node))
+		return
+	}
+
+	if len(schema.Enum) > 0 {
+		allowed := make([]string, 0, len(schema.Enum))
+		for _, value := range schema.Enum {
+			allowed = append(allowed, fmt.Sprint(value))
+		}
+
+		if !slices.Contains(allowed, node.Value) {
+			v.addProblem(node, path, "%q is not allowed, must be one of %s", node.Value, strings.Join(allowed, ", "))
+		}
+	}
+
+	if schema.Minimum != nil || schema.Maximum != nil {
+		if number, err := strconv.ParseFloat(node.Value, 64); err == nil {
+			if schema.Minimum != nil && number < *schema.Minimum {
+				v.addProblem(node, path, "must be at least %s", strconv.FormatFloat(*schema.Minimum, 'f', -1, 64))
+			}
+
+			if schema.Maximum != nil && number > *schema.Maximum {
+				v.addProblem(node, path, "must be at most %s", strconv.FormatFloat(*schema.Maximum, 'f', -1, 64))
+			}
+		}
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		v.validateObject(schema, node, path)
+	case yaml.SequenceNode:
+		if schema.Items != nil {
+			for index, item := range node.Content {
+				v.validate(schema.Items, item, fmt.Sprintf("%s[%d]", path, index))
+			}
+		}
+	}
+}
+
+func (v *configSchemaValidator) validateObject(schema *configSchema, node *yaml.Node, path string) {
+	var additional *configSchema
+	additionalAllowed := true
+
+	if len(schema.AdditionalProperties) > 0 {
+		if string(schema.AdditionalProperties) == "false" {
+			additionalAllowed = false
+		} else if string(schema.AdditionalProperties) != "true" {
+			additional = &configSchema{}
+			if err := json.Unmarshal(schema.AdditionalProperties, additional); err != nil {
+				additional = nil
+			}
+		}
+	}
+
+	present := map[string]bool{}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		present[key.Value] = true
+
+		keyPath := key.Value
+		if path != "" {
+			keyPath = path + "." + key.Value
+		}
+
+		if property, ok := schema.Properties[key.Value]; ok {
+			v.validate(property, value, keyPath)
+			continue
+		}
+
+		if additional != nil {
+			v.validate(additional, value, keyPath)
+			continue
+		}
+
+		if !additionalAllowed {
+			v.addProblem(key, keyPath, "unknown key, allowed are %s", strings.Join(sortedConfigKeys(schema.Properties), ", "))
+		}
+	}
+
+	for _, required := range schema.Required {
+		if !present[required] {
+			v.addProblem(node, path, "the key %s is required", required)
+		}
+	}
+}
+
+// validateOneOf accepts the node, when one of the alternatives has no problems
+func (v *configSchemaValidator) validateOneOf(schema *configSchema, node *yaml.Node, path string) {
+	types := make([]string, 0, len(schema.OneOf))
+
+	for _, alternative := range schema.OneOf {
+		check := configSchemaValidator{defs: v.defs}
+		check.validate(alternative, node, path)
+
+		if len(check.problems) == 0 {
+			return
+		}
+
+		types = append(types, v.resolve(alternative).Type)
+	}
+
+	v.addProblem(node, path, "must be one of the types %s, got %s", strings.Join(types, ", "), configNodeType(node))
+}
+
+func sortedConfigKeys(properties map[string]*configSchema) []string {
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func configNodeType(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+
+	switch node.Tag {
+	case "!!bool":
+		return "boolean"
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	}
+
+	return "string"
+}
+
+func configNodeHasType(node *yaml.Node, expected string) bool {
+	actual := configNodeType(node)
+
+	return actual == expected || (expected == "number" && actual == "integer")
+}
+
+// validateExclusiveConfigOptions reports options which have no effect, because another option overrides them
+func validateExclusiveConfigOptions(root *yaml.Node) []ConfigProblem {
+	var problems []ConfigProblem
+
+	if images, directory := findConfigNode(root, "store", "images"), findConfigNode(root, "store", "image_directory"); images != nil && directory != nil {
+		problems = append(problems, ConfigProblem{Line: images.Line, Path: "store.images", Message: "cannot be combined with store.image_directory, which takes precedence"})
+	}
+
+	if enabled := findConfigNode(root, "build", "zip", "assets", "enabled"); enabled != nil && enabled.Value == "false" {
+		for _, key := range []string{"enable_es_build_for_admin", "enable_es_build_for_storefront", "disable_sass", "npm_strict", "before_hooks", "after_hooks"} {
+			if option := findConfigNode(root, "build", "zip", "assets", key); option != nil && option.Value != "false" {
+				problems = append(problems, ConfigProblem{Line: option.Line, Path: "build.zip.assets." + key, Message: "has no effect, as build.zip.assets.enabled is false"})
+			}
+		}
+	}
+
+	if enabled := findConfigNode(root, "build", "zip", "composer", "enabled"); enabled != nil && enabled.Value == "false" {
+		for _, key := range []string{"before_hooks", "after_hooks", "excluded_packages"} {
+			if option := findConfigNode(root, "build", "zip", "composer", key); option != nil {
+				problems = append(problems, ConfigProblem{Line: option.Line, Path: "build.zip.composer." + key, Message: "has no effect, as build.zip.composer.enabled is false"})
+			}
+		}
+	}
+
+	if disabled := findConfigNode(root, "validation", "audit", "disabled"); disabled != nil && disabled.Value == "true" {
+		if severity := findConfigNode(root, "validation", "audit", "severity"); severity != nil {
+			problems = append(problems, ConfigProblem{Line: severity.Line, Path: "validation.audit.severity", Message: "has no effect, as validation.audit.disabled is true"})
+		}
+	}
+
+	return problems
+}
+
+// findConfigNode returns the value of the nested key, or nil when it is not set
+func findConfigNode(node *yaml.Node, keys ...string) *yaml.Node {
+	for _, key := range keys {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+
+		var value *yaml.Node
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				value = node.Content[i+1]
+				break
+			}
+		}
+
+		if value == nil {
+			return nil
+		}
+
+		node = value
+	}
+
+	if node.Tag == "!!null" {
+		return nil
+	}
+
+	return node
+}
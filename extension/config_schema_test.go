@@ -0,0 +1,128 @@
+package extension
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validateConfigContent(t *testing.T, content string) []ConfigProblem {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), ".shopware-extension.yml")
+	require.NoError(t, os.WriteFile(file, []byte(content), os.ModePerm))
+
+	problems, err := ValidateExtensionConfigFile(file)
+	require.NoError(t, err)
+
+	return problems
+}
+
+func TestExtensionConfigSchemaIsValidJSON(t *testing.T) {
+	var schema map[string]any
+	assert.NoError(t, json.Unmarshal(ExtensionConfigSchema(), &schema))
+	assert.Equal(t, "#/$defs/Config", schema["$ref"])
+}
+
+func TestValidateExtensionConfigFileValid(t *testing.T) {
+	problems := validateConfigContent(t, `
+store:
+  availabilities:
+    - German
+  default_locale: en_GB
+  meta_title:
+    en: My extension
+build:
+  zip:
+    assets:
+      enabled: true
+      enable_es_build_for_admin: true
+validation:
+  ignore:
+    - identifier: metadata.setup
+      reason: Not needed
+    - assets.not_built
+  rules:
+    twig.*: warning
+  phpstan:
+    level: 6
+`)
+
+	assert.Empty(t, problems)
+}
+
+func TestValidateExtensionConfigFileSchemaProblems(t *testing.T) {
+	problems := validateConfigContent(t, `store:
+  default_locale: fr_FR
+  availability:
+    - German
+build:
+  zip:
+    assets:
+      enabled: "yes"
+validation:
+  rules:
+    twig.syntax: fatal
+  phpstan:
+    level: 11
+  ignore:
+    - 1
+  external:
+    - name: house-rules
+`)
+
+	assert.Equal(t, []ConfigProblem{
+		{Line: 2, Path: "store.default_locale", Message: `"fr_FR" is not allowed, must be one of de_DE, en_GB`},
+		{Line: 3, Path: "store.availability", Message: "unknown key, allowed are automatic_bugfix_version_compatibility, availabilities, before_upload_hooks, categories, default_locale, description, faq, features, highlights, icon, image_directory, images, in_app_features, installation_manual, localizations, meta_description, meta_title, price_models, review_issue, screenshots, tags, type, upload_on_conflict, videos"},
+		{Line: 8, Path: "build.zip.assets.enabled", Message: "must be of type boolean, got string"},
+		{Line: 11, Path: "validation.rules.twig.syntax", Message: `"fatal" is not allowed, must be one of error, warning, off`},
+		{Line: 13, Path: "validation.phpstan.level", Message: "must be at most 10"},
+		{Line: 15, Path: "validation.ignore[0]", Message: "must be one of the types object, string, got integer"},
+		{Line: 17, Path: "validation.external[0]", Message: "the key command is required"},
+	}, problems)
+}
+
+func TestValidateExtensionConfigFileExclusiveOptions(t *testing.T) {
+	problems := validateConfigContent(t, `store:
+  images:
+    - file: a.png
+  image_directory: images
+build:
+  zip:
+    assets:
+      enabled: false
+      enable_es_build_for_storefront: true
+    composer:
+      enabled: false
+      excluded_packages:
+        - foo/bar
+`)
+
+	assert.Len(t, problems, 3)
+	assert.Equal(t, "store.images", problems[0].Path)
+	assert.Equal(t, 3, problems[0].Line)
+	assert.Equal(t, "build.zip.assets.enable_es_build_for_storefront: has no effect, as build.zip.assets.enabled is false", problems[1].Path+": "+problems[1].Message)
+	assert.Equal(t, "build.zip.composer.excluded_packages", problems[2].Path)
+}
+
+func TestValidateExtensionConfigFileRules(t *testing.T) {
+	problems := validateConfigContent(t, `store:
+  price_models:
+    - type: free
+      price: 10
+`)
+
+	assert.Len(t, problems, 1)
+	assert.Equal(t, "store.price_models: free price model cannot have a price", problems[0].Message)
+}
+
+func TestValidateExtensionConfigFileSyntaxError(t *testing.T) {
+	problems := validateConfigContent(t, "store: [")
+
+	assert.Len(t, problems, 1)
+	assert.Contains(t, problems[0].Message, "yaml:")
+}
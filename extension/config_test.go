@@ -47,3 +47,158 @@ validation:
 	assert.Equal(t, "foo", ext.Validation.Ignore[1].Identifier)
 	assert.Equal(t, "bar", ext.Validation.Ignore[1].Path)
 }
+
+func TestConfigStorePriceModels(t *testing.T) {
+	cfg := `
+store:
+  price_models:
+    - type: buy
+      price: 49.99
+      country_prices:
+        - country: CH
+          price: 59
+    - type: rent
+      price: 4.99
+      trial_phase: true
+`
+
+	tmpDir := t.TempDir()
+
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".shopware-extension.yml"), []byte(cfg), 0o644))
+
+	ext, err := readExtensionConfig(tmpDir)
+	assert.NoError(t, err)
+	assert.Len(t, *ext.Store.PriceModels, 2)
+	assert.Equal(t, "buy", (*ext.Store.PriceModels)[0].Type)
+	assert.Equal(t, 49.99, (*ext.Store.PriceModels)[0].Price)
+	assert.Equal(t, "CH", (*ext.Store.PriceModels)[0].CountryPrices[0].Country)
+	assert.True(t, (*ext.Store.PriceModels)[1].TrialPhase)
+}
+
+func TestConfigStorePriceModelsInvalid(t *testing.T) {
+	cases := map[string]string{
+		"free with price":     "    - type: free\n      price: 10\n",
+		"buy without price":   "    - type: buy\n",
+		"trial phase for buy": "    - type: buy\n      price: 10\n      trial_phase: true\n",
+		"duplicate type":      "    - type: rent\n      price: 1\n    - type: rent\n      price: 2\n",
+		"free combined":       "    - type: free\n    - type: buy\n      price: 2\n",
+		"unknown type":        "    - type: lease\n      price: 2\n",
+		"invalid country":     "    - type: buy\n      price: 2\n      country_prices:\n        - country: Germany\n          price: 3\n",
+	}
+
+	for name, models := range cases {
+		t.Run(name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+
+			assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".shopware-extension.yml"), []byte("store:\n  price_models:\n"+models), 0o644))
+
+			_, err := readExtensionConfig(tmpDir)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestConfigStoreInAppFeatures(t *testing.T) {
+	cfg := `
+store:
+  in_app_features:
+    - identifier: premium-export
+      name:
+        de: Premium Export
+        en: Premium export
+      type: rent
+      price: 9.99
+`
+
+	tmpDir := t.TempDir()
+
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".shopware-extension.yml"), []byte(cfg), 0o644))
+
+	ext, err := readExtensionConfig(tmpDir)
+	assert.NoError(t, err)
+	assert.Len(t, *ext.Store.InAppFeatures, 1)
+	assert.Equal(t, "premium-export", (*ext.Store.InAppFeatures)[0].Identifier)
+	assert.Equal(t, "Premium export", *(*ext.Store.InAppFeatures)[0].Name.English)
+	assert.Equal(t, 9.99, (*ext.Store.InAppFeatures)[0].Price)
+}
+
+func TestConfigStoreInAppFeaturesInvalid(t *testing.T) {
+	name := "      name:\n        de: Export\n        en: Export\n"
+
+	cases := map[string]string{
+		"invalid identifier":   "    - identifier: Premium Export\n" + name + "      type: buy\n      price: 10\n",
+		"duplicate identifier": "    - identifier: export\n" + name + "      type: buy\n      price: 10\n    - identifier: export\n" + name + "      type: rent\n      price: 1\n",
+		"unknown type":         "    - identifier: export\n" + name + "      type: free\n      price: 10\n",
+		"without price":        "    - identifier: export\n" + name + "      type: buy\n",
+		"without name":         "    - identifier: export\n      type: buy\n      price: 10\n",
+	}
+
+	for name, features := range cases {
+		t.Run(name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+
+			assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".shopware-extension.yml"), []byte("store:\n  in_app_features:\n"+features), 0o644))
+
+			_, err := readExtensionConfig(tmpDir)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestConfigStoreReviewIssue(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".shopware-extension.yml"), []byte("store:\n  review_issue:\n    provider: github\n    project: acme/plugin\n    assignees: [alice]\n"), 0o644))
+
+	ext, err := readExtensionConfig(tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "acme/plugin", ext.Store.ReviewIssue.Project)
+	assert.Equal(t, []string{"alice"}, ext.Store.ReviewIssue.Assignees)
+
+	cases := map[string]string{
+		"unknown provider":  "    provider: trello\n    project: board\n",
+		"without project":   "    provider: gitlab\n",
+		"jira without site": "    provider: jira\n    project: PLUG\n",
+	}
+
+	for name, issue := range cases {
+		t.Run(name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+
+			assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".shopware-extension.yml"), []byte("store:\n  review_issue:\n"+issue), 0o644))
+
+			_, err := readExtensionConfig(tmpDir)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestConfigValidationRules(t *testing.T) {
+	cfg := `
+validation:
+  rules:
+    snippet.unused: off
+    eslint/*: warning
+  ignore:
+    - identifier: twig.unknown_block
+      path: Resources/views/storefront/base.html.twig
+      reason: The block is only rendered by our theme
+`
+
+	tmpDir := t.TempDir()
+
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".shopware-extension.yml"), []byte(cfg), 0o644))
+
+	ext, err := readExtensionConfig(tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]ConfigValidationSeverity{
+		"snippet.unused": ValidationSeverityOff,
+		"eslint/*":       ValidationSeverityWarning,
+	}, ext.Validation.Rules)
+	assert.Equal(t, "The block is only rendered by our theme", ext.Validation.Ignore[0].Reason)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".shopware-extension.yml"), []byte("validation:\n  rules:\n    snippet.unused: info\n"), 0o644))
+
+	_, err = readExtensionConfig(tmpDir)
+	assert.Error(t, err)
+}
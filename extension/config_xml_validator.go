@@ -0,0 +1,315 @@
+package extension
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// defaultConfigLocale is the locale of titles and labels without lang attribute
+const defaultConfigLocale = "en-GB"
+
+// configInputFieldTypes are the types of input-field allowed by the Shopware config schema
+var configInputFieldTypes = []string{
+	"text", "textarea", "text-editor", "url", "password", "email",
+	"int", "float", "bool", "checkbox",
+	"datetime", "date", "time", "colorpicker",
+	"single-select", "multi-select",
+}
+
+var (
+	configFieldNameRegExp  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+	configColorRegExp      = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+	configDateRegExp       = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	configTimeRegExp       = regexp.MustCompile(`^\d{2}:\d{2}(?::\d{2})?$`)
+	configSelectFieldTypes = []string{"single-select", "multi-select"}
+)
+
+type configCard struct {
+	line   int
+	titles map[string]string
+	fields []*configField
+}
+
+type configField struct {
+	line int
+	// input-field or component
+	element      string
+	fieldType    string
+	name         string
+	labels       map[string]string
+	defaultValue *string
+	options      []string
+}
+
+type configXML struct {
+	cards   []*configCard
+	locales []string
+}
+
+func validateConfigXML(context *ValidationContext) {
+	rootDir := context.Extension.GetRootDir()
+
+	for _, resourcesDir := range context.Extension.GetResourcesDirs() {
+		file := path.Join(resourcesDir, "config", "config.xml")
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+
+		relPath := strings.TrimPrefix(file, rootDir+"/")
+
+		config, line, err := parseConfigXML(content)
+		if err != nil {
+			context.AddErrorAt("config.syntax", fileLocation{relPath, line}, err.Error())
+			continue
+		}
+
+		validateConfigCards(config, relPath, context)
+	}
+}
+
+func validateConfigCards(config *configXML, relPath string, context *ValidationContext) {
+	names := map[string]int{}
+
+	for _, card := range config.cards {
+		if len(card.titles) == 0 {
+			context.AddErrorAt("config.missing_label", fileLocation{relPath, card.line}, "the card has no title")
+		} else {
+			for _, locale := range missingConfigLocales(card.titles, config.locales) {
+				context.AddWarningAt("config.missing_label", fileLocation{relPath, card.line}, fmt.Sprintf("the card title is not translated in %s", locale))
+			}
+		}
+
+		for _, field := range card.fields {
+			location := fileLocation{relPath, field.line}
+
+			if field.name == "" {
+				context.AddErrorAt("config.invalid_field", location, fmt.Sprintf("the %s has no name", field.element))
+				continue
+			}
+
+			if !configFieldNameRegExp.MatchString(field.name) {
+				context.AddErrorAt("config.invalid_field", location, fmt.Sprintf("the field name %s may only contain letters, numbers, underscores and dashes", field.name))
+			}
+
+			if firstLine, ok := names[field.name]; ok {
+				context.AddErrorAt("config.invalid_field", location, fmt.Sprintf("the field name %s is already used in line %d", field.name, firstLine))
+			} else {
+				names[field.name] = field.line
+			}
+
+			if field.element == "component" && field.fieldType == "" {
+				context.AddErrorAt("config.invalid_field", location, fmt.Sprintf("the component %s has no name attribute with the administration component to use", field.name))
+			}
+
+			if field.element == "input-field" && !slices.Contains(configInputFieldTypes, field.fieldType) {
+				context.AddErrorAt("config.invalid_field", location, fmt.Sprintf("the field %s has the unknown type %s, allowed are %s", field.name, field.fieldType, strings.Join(configInputFieldTypes, ", ")))
+				continue
+			}
+
+			if len(field.labels) == 0 {
+				context.AddWarningAt("config.missing_label", location, fmt.Sprintf("the field %s has no label", field.name))
+			} else {
+				for _, locale := range missingConfigLocales(field.labels, config.locales) {
+					context.AddWarningAt("config.missing_label", location, fmt.Sprintf("the label of the field %s is not translated in %s", field.name, locale))
+				}
+			}
+
+			if field.element == "input-field" && slices.Contains(configSelectFieldTypes, field.fieldType) && len(field.options) == 0 {
+				context.AddErrorAt("config.invalid_field", location, fmt.Sprintf("the %s field %s has no options", field.fieldType, field.name))
+			}
+
+			if field.defaultValue != nil && field.element == "input-field" {
+				if err := validateConfigDefaultValue(field, *field.defaultValue); err != nil {
+					context.AddErrorAt("config.invalid_default", location, fmt.Sprintf("the default value of the field %s %s", field.name, err.Error()))
+				}
+			}
+		}
+	}
+}
+
+func missingConfigLocales(translations map[string]string, locales []string) []string {
+	missing := []string{}
+
+	for _, locale := range locales {
+		if strings.TrimSpace(translations[locale]) == "" {
+			missing = append(missing, locale)
+		}
+	}
+
+	return missing
+}
+
+func validateConfigDefaultValue(field *configField, value string) error {
+	value = strings.TrimSpace(value)
+
+	switch field.fieldType {
+	case "int":
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+	case "float":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+	case "bool", "checkbox":
+		if value != "true" && value != "false" && value != "1" && value != "0" {
+			return fmt.Errorf("%q must be true or false", value)
+		}
+	case "single-select":
+		if !slices.Contains(field.options, value) {
+			return fmt.Errorf("%q is not one of the option ids %s", value, strings.Join(field.options, ", "))
+		}
+	case "url":
+		if parsed, err := url.Parse(value); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return fmt.Errorf("%q is not an absolute URL", value)
+		}
+	case "email":
+		if !strings.Contains(value, "@") {
+			return fmt.Errorf("%q is not an email address", value)
+		}
+	case "colorpicker":
+		if !configColorRegExp.MatchString(value) {
+			return fmt.Errorf("%q is not a hex color like #ff0000", value)
+		}
+	case "date":
+		if !configDateRegExp.MatchString(value) {
+			return fmt.Errorf("%q is not a date like 2024-01-31", value)
+		}
+	case "time":
+		if !configTimeRegExp.MatchString(value) {
+			return fmt.Errorf("%q is not a time like 13:30", value)
+		}
+	}
+
+	return nil
+}
+
+// parseConfigXML reads the cards and fields with their line numbers, on syntax errors the line of the error is returned
+func parseConfigXML(content []byte) (*configXML, int, error) {
+	decoder := xml.NewDecoder(strings.NewReader(string(content)))
+
+	config := &configXML{locales: []string{defaultConfigLocale}}
+
+	var (
+		stack  []string
+		text   strings.Builder
+		card   *configCard
+		field  *configField
+		lang   string
+		option string
+	)
+
+	for {
+		line, _ := decoder.InputPos()
+
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			line, _ = decoder.InputPos()
+			return nil, line, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Name.Local)
+			text.Reset()
+			lang = configLocale(t)
+
+			if lang != defaultConfigLocale && !slices.Contains(config.locales, lang) {
+				config.locales = append(config.locales, lang)
+			}
+
+			switch {
+			case matchesConfigPath(stack, "card"):
+				card = &configCard{line: line, titles: map[string]string{}}
+				config.cards = append(config.cards, card)
+			case card != nil && matchesConfigPath(stack, "card", "input-field"):
+				field = &configField{line: line, element: "input-field", fieldType: "text", labels: map[string]string{}}
+				card.fields = append(card.fields, field)
+
+				if fieldType := configAttr(t, "type"); fieldType != "" {
+					field.fieldType = fieldType
+				}
+			case card != nil && matchesConfigPath(stack, "card", "component"):
+				field = &configField{line: line, element: "component", fieldType: configAttr(t, "name"), labels: map[string]string{}}
+				card.fields = append(card.fields, field)
+			case field != nil && matchesConfigPath(stack, "option"):
+				option = ""
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			value := strings.TrimSpace(text.String())
+
+			switch {
+			case card != nil && matchesConfigPath(stack, "card", "title"):
+				card.titles[lang] = value
+			case field != nil && (matchesConfigPath(stack, "input-field", stack[len(stack)-1]) || matchesConfigPath(stack, "component", stack[len(stack)-1])):
+				switch stack[len(stack)-1] {
+				case "name":
+					field.name = value
+				case "label":
+					field.labels[lang] = value
+				case "defaultValue":
+					field.defaultValue = &value
+				}
+			case field != nil && matchesConfigPath(stack, "option", "id"):
+				option = value
+			case field != nil && matchesConfigPath(stack, "option"):
+				if option != "" {
+					field.options = append(field.options, option)
+				}
+			case matchesConfigPath(stack, "card", "input-field"), matchesConfigPath(stack, "card", "component"):
+				field = nil
+			case matchesConfigPath(stack, "card"):
+				card = nil
+			}
+
+			stack = stack[:len(stack)-1]
+			text.Reset()
+		}
+	}
+
+	return config, 0, nil
+}
+
+// matchesConfigPath reports whether the element stack ends with the given elements
+func matchesConfigPath(stack []string, elements ...string) bool {
+	if len(stack) < len(elements) {
+		return false
+	}
+
+	return slices.Equal(stack[len(stack)-len(elements):], elements)
+}
+
+func configAttr(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+func configLocale(element xml.StartElement) string {
+	if lang := configAttr(element, "lang"); lang != "" {
+		return lang
+	}
+
+	return defaultConfigLocale
+}
@@ -0,0 +1,166 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestConfigXML(t *testing.T, content string) string {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+
+	_ = os.MkdirAll(filepath.Join(tmpDir, "Resources", "config"), os.ModePerm)
+	_ = os.WriteFile(filepath.Join(tmpDir, "Resources", "config", "config.xml"), []byte(content), os.ModePerm)
+
+	return tmpDir
+}
+
+func TestValidateConfigXMLValid(t *testing.T) {
+	tmpDir := writeTestConfigXML(t, `<?xml version="1.0" encoding="UTF-8"?>
+<config xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+    <card>
+        <title>Basic configuration</title>
+        <title lang="de-DE">Grundeinstellungen</title>
+
+        <input-field>
+            <name>apiKey</name>
+            <label>API key</label>
+            <label lang="de-DE">API-Schlüssel</label>
+        </input-field>
+
+        <input-field type="int">
+            <name>timeout</name>
+            <label>Timeout</label>
+            <label lang="de-DE">Zeitlimit</label>
+            <defaultValue>30</defaultValue>
+        </input-field>
+
+        <input-field type="single-select">
+            <name>mode</name>
+            <label>Mode</label>
+            <label lang="de-DE">Modus</label>
+            <defaultValue>live</defaultValue>
+            <options>
+                <option>
+                    <id>live</id>
+                    <name>Live</name>
+                    <name lang="de-DE">Live</name>
+                </option>
+                <option>
+                    <id>sandbox</id>
+                    <name>Sandbox</name>
+                </option>
+            </options>
+        </input-field>
+
+        <component name="sw-entity-single-select">
+            <name>salesChannel</name>
+            <entity>sales_channel</entity>
+            <label>Sales Channel</label>
+            <label lang="de-DE">Verkaufskanal</label>
+        </component>
+    </card>
+</config>
+`)
+
+	context := newValidationContext(App{path: tmpDir})
+
+	validateConfigXML(context)
+
+	assert.Empty(t, context.errors)
+	assert.Empty(t, context.warnings)
+}
+
+func TestValidateConfigXMLErrors(t *testing.T) {
+	tmpDir := writeTestConfigXML(t, `<?xml version="1.0" encoding="UTF-8"?>
+<config>
+    <card>
+        <input-field type="number">
+            <name>amount</name>
+            <label>Amount</label>
+        </input-field>
+        <input-field type="bool">
+            <name>active</name>
+            <label>Active</label>
+            <label lang="de-DE">Aktiv</label>
+            <defaultValue>yes</defaultValue>
+        </input-field>
+        <input-field type="single-select">
+            <name>active</name>
+            <label>Mode</label>
+            <label lang="de-DE">Modus</label>
+        </input-field>
+        <component>
+            <name>product</name>
+            <label>Product</label>
+            <label lang="de-DE">Produkt</label>
+        </component>
+    </card>
+</config>
+`)
+
+	context := newValidationContext(App{path: tmpDir})
+
+	validateConfigXML(context)
+
+	assert.Equal(t, []ValidationMessage{
+		{Identifier: "config.missing_label", Message: "Resources/config/config.xml:3: the card has no title", File: "Resources/config/config.xml", Line: 3},
+		{Identifier: "config.invalid_field", Message: "Resources/config/config.xml:4: the field amount has the unknown type number, allowed are text, textarea, text-editor, url, password, email, int, float, bool, checkbox, datetime, date, time, colorpicker, single-select, multi-select", File: "Resources/config/config.xml", Line: 4},
+		{Identifier: "config.invalid_default", Message: `Resources/config/config.xml:8: the default value of the field active "yes" must be true or false`, File: "Resources/config/config.xml", Line: 8},
+		{Identifier: "config.invalid_field", Message: "Resources/config/config.xml:14: the field name active is already used in line 8", File: "Resources/config/config.xml", Line: 14},
+		{Identifier: "config.invalid_field", Message: "Resources/config/config.xml:14: the single-select field active has no options", File: "Resources/config/config.xml", Line: 14},
+		{Identifier: "config.invalid_field", Message: "Resources/config/config.xml:19: the component product has no name attribute with the administration component to use", File: "Resources/config/config.xml", Line: 19},
+	}, context.errors)
+	assert.Empty(t, context.warnings)
+}
+
+func TestValidateConfigXMLMissingTranslations(t *testing.T) {
+	tmpDir := writeTestConfigXML(t, `<config>
+    <card>
+        <title>Basic configuration</title>
+        <title lang="de-DE">Grundeinstellungen</title>
+        <input-field>
+            <name>title</name>
+            <label lang="de-DE">Titel</label>
+        </input-field>
+        <input-field type="colorpicker">
+            <name>color</name>
+            <defaultValue>red</defaultValue>
+        </input-field>
+    </card>
+</config>
+`)
+
+	context := newValidationContext(App{path: tmpDir})
+
+	validateConfigXML(context)
+
+	assert.Equal(t, []ValidationMessage{
+		{Identifier: "config.invalid_default", Message: `Resources/config/config.xml:9: the default value of the field color "red" is not a hex color like #ff0000`, File: "Resources/config/config.xml", Line: 9},
+	}, context.errors)
+	assert.Equal(t, []ValidationMessage{
+		{Identifier: "config.missing_label", Message: "Resources/config/config.xml:5: the label of the field title is not translated in en-GB", File: "Resources/config/config.xml", Line: 5},
+		{Identifier: "config.missing_label", Message: "Resources/config/config.xml:9: the field color has no label", File: "Resources/config/config.xml", Line: 9},
+	}, context.warnings)
+}
+
+func TestValidateConfigXMLSyntaxError(t *testing.T) {
+	tmpDir := writeTestConfigXML(t, `<config>
+    <card>
+        <title>Basic</title>
+    </cards>
+</config>
+`)
+
+	context := newValidationContext(App{path: tmpDir})
+
+	validateConfigXML(context)
+
+	assert.Len(t, context.errors, 1)
+	assert.Equal(t, "config.syntax", context.errors[0].Identifier)
+	assert.Contains(t, context.errors[0].Message, "Resources/config/config.xml:4: ")
+}
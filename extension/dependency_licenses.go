@@ -0,0 +1,326 @@
+package extension
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// BundledDependency is a composer package of the vendor folder or a npm package compiled into the JavaScript of the extension
+type BundledDependency struct {
+	Name     string   `json:"name"`
+	Version  string   `json:"version"`
+	Type     string   `json:"type"`
+	Licenses []string `json:"licenses"`
+	// Source is the file listing the dependency, relative to the extension path
+	Source string `json:"source"`
+}
+
+const (
+	DependencyTypeComposer = "composer"
+	DependencyTypeNpm      = "npm"
+)
+
+// defaultAllowedLicenses are permissive licenses, which can be bundled into extensions of any license
+var defaultAllowedLicenses = []string{"MIT", "MIT-0", "BSD-2-Clause", "BSD-3-Clause", "Apache-2.0", "ISC", "0BSD", "Zlib", "Unlicense", "CC0-1.0", "CC-BY-4.0", "BlueOak-1.0.0", "Python-2.0"}
+
+var copyleftLicensePrefixes = []string{"GPL", "LGPL", "AGPL", "MPL", "EPL", "EUPL", "CDDL", "OSL", "CPL", "CC-BY-SA", "SSPL"}
+
+type licenseVerdict int
+
+const (
+	licenseAllowed licenseVerdict = iota
+	licenseCopyleft
+	licenseUnknown
+	licenseNotAllowed
+)
+
+// FindBundledDependencies returns the composer packages of the vendor folder and the production npm packages of the package-lock.json files
+func FindBundledDependencies(ext Extension) ([]BundledDependency, error) {
+	dependencies, err := findComposerDependencies(ext.GetPath())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, resourcesDir := range ext.GetResourcesDirs() {
+		for _, app := range []string{"administration", "storefront"} {
+			npmDependencies, err := findNpmDependencies(ext.GetPath(), filepath.Join(resourcesDir, "app", app))
+			if err != nil {
+				return nil, err
+			}
+
+			dependencies = append(dependencies, npmDependencies...)
+		}
+	}
+
+	return dependencies, nil
+}
+
+func findComposerDependencies(extPath string) ([]BundledDependency, error) {
+	installedFile := filepath.Join(extPath, "vendor", "composer", "installed.json")
+
+	content, err := os.ReadFile(installedFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	type installedPackage struct {
+		Name    string   `json:"name"`
+		Version string   `json:"version"`
+		License []string `json:"license"`
+	}
+
+	var installed struct {
+		Packages        []installedPackage `json:"packages"`
+		DevPackageNames []string           `json:"dev-package-names"`
+	}
+
+	// Composer 1 writes a plain list of packages
+	if strings.HasPrefix(strings.TrimSpace(string(content)), "[") {
+		err = json.Unmarshal(content, &installed.Packages)
+	} else {
+		err = json.Unmarshal(content, &installed)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", installedFile, err)
+	}
+
+	dependencies := []BundledDependency{}
+
+	for _, pkg := range installed.Packages {
+		// A vendor folder with Shopware itself is a development setup, the zip build installs only the dependencies of the extension
+		if pkg.Name == "shopware/core" {
+			return nil, nil
+		}
+
+		if slices.Contains(installed.DevPackageNames, pkg.Name) {
+			continue
+		}
+
+		dependencies = append(dependencies, BundledDependency{
+			Name:     pkg.Name,
+			Version:  pkg.Version,
+			Type:     DependencyTypeComposer,
+			Licenses: pkg.License,
+			Source:   "vendor/composer/installed.json",
+		})
+	}
+
+	return dependencies, nil
+}
+
+func findNpmDependencies(extPath, appDir string) ([]BundledDependency, error) {
+	lockFile := filepath.Join(appDir, "package-lock.json")
+
+	content, err := os.ReadFile(lockFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var lock struct {
+		Packages map[string]struct {
+			Version string `json:"version"`
+			License any    `json:"license"`
+			Dev     bool   `json:"dev"`
+		} `json:"packages"`
+		Dependencies map[string]struct {
+			Version string `json:"version"`
+			Dev     bool   `json:"dev"`
+		} `json:"dependencies"`
+	}
+
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", lockFile, err)
+	}
+
+	source, err := filepath.Rel(extPath, lockFile)
+	if err != nil {
+		source = lockFile
+	}
+
+	source = filepath.ToSlash(source)
+
+	dependencies := []BundledDependency{}
+	seen := map[string]bool{}
+
+	add := func(name, version string, license any) {
+		if seen[name+"@"+version] {
+			return
+		}
+
+		seen[name+"@"+version] = true
+
+		dependencies = append(dependencies, BundledDependency{Name: name, Version: version, Type: DependencyTypeNpm, Licenses: npmLicenses(license), Source: source})
+	}
+
+	// Lockfile version 2 and 3 contain the licenses of all packages
+	if len(lock.Packages) > 0 {
+		for key, pkg := range lock.Packages {
+			index := strings.LastIndex(key, "node_modules/")
+			if index == -1 || pkg.Dev {
+				continue
+			}
+
+			add(key[index+len("node_modules/"):], pkg.Version, pkg.License)
+		}
+	} else {
+		for name, pkg := range lock.Dependencies {
+			if pkg.Dev {
+				continue
+			}
+
+			var packageJson struct {
+				License any `json:"license"`
+			}
+
+			if content, err := os.ReadFile(filepath.Join(appDir, "node_modules", name, "package.json")); err == nil {
+				_ = json.Unmarshal(content, &packageJson)
+			}
+
+			add(name, pkg.Version, packageJson.License)
+		}
+	}
+
+	slices.SortFunc(dependencies, func(a, b BundledDependency) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return dependencies, nil
+}
+
+// npmLicenses supports the license string and the deprecated license objects of package.json files
+func npmLicenses(license any) []string {
+	switch l := license.(type) {
+	case string:
+		if l == "" {
+			return nil
+		}
+
+		return []string{l}
+	case map[string]any:
+		if licenseType, ok := l["type"].(string); ok {
+			return []string{licenseType}
+		}
+	case []any:
+		licenses := []string{}
+		for _, item := range l {
+			licenses = append(licenses, npmLicenses(item)...)
+		}
+
+		return licenses
+	}
+
+	return nil
+}
+
+// classifyLicenses checks the licenses of a dependency, multiple licenses and OR expressions are alternatives of which one needs to be allowed
+func classifyLicenses(licenses []string, allowed []string) licenseVerdict {
+	alternatives := []string{}
+
+	for _, license := range licenses {
+		for _, alternative := range strings.Split(strings.Trim(license, "() "), " OR ") {
+			if alternative = strings.Trim(alternative, "() "); alternative != "" {
+				alternatives = append(alternatives, alternative)
+			}
+		}
+	}
+
+	if len(alternatives) == 0 {
+		return licenseUnknown
+	}
+
+	verdict := licenseNotAllowed
+
+	for _, alternative := range alternatives {
+		if isLicenseAllowed(alternative, allowed) {
+			return licenseAllowed
+		}
+
+		switch {
+		case isCopyleftLicense(alternative):
+			verdict = licenseCopyleft
+		case isUnknownLicense(alternative) && verdict != licenseCopyleft:
+			verdict = licenseUnknown
+		}
+	}
+
+	return verdict
+}
+
+// isLicenseAllowed requires all parts of an AND expression to be allowed
+func isLicenseAllowed(license string, allowed []string) bool {
+	for _, part := range strings.Split(license, " AND ") {
+		part = strings.Trim(part, "() ")
+
+		if !slices.ContainsFunc(allowed, func(a string) bool { return strings.EqualFold(a, part) }) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isCopyleftLicense(license string) bool {
+	for _, part := range strings.Split(license, " AND ") {
+		part = strings.ToUpper(strings.Trim(part, "() "))
+
+		for _, prefix := range copyleftLicensePrefixes {
+			if strings.HasPrefix(part, prefix) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isUnknownLicense(license string) bool {
+	upper := strings.ToUpper(license)
+
+	return upper == "NOASSERTION" || upper == "UNKNOWN" || strings.HasPrefix(upper, "SEE LICENSE")
+}
+
+// validateDependencyLicenses checks the licenses of the bundled dependencies against the allowed licenses of the extension config
+func validateDependencyLicenses(vc *ValidationContext) {
+	dependencies, err := FindBundledDependencies(vc.Extension)
+	if err != nil {
+		vc.AddWarning("license.unknown", err.Error())
+		return
+	}
+
+	allowed := vc.Extension.GetExtensionConfig().Validation.Licenses.Allowed
+	if len(allowed) == 0 {
+		allowed = defaultAllowedLicenses
+	}
+
+	// Dependencies with the license of the extension itself are always fine
+	if ownLicense, err := vc.Extension.GetLicense(); err == nil && ownLicense != "" {
+		allowed = append(slices.Clone(allowed), ownLicense)
+	}
+
+	for _, dependency := range dependencies {
+		licenses := strings.Join(dependency.Licenses, ", ")
+
+		switch classifyLicenses(dependency.Licenses, allowed) {
+		case licenseCopyleft:
+			vc.AddErrorAt("license.copyleft", fileLocation{dependency.Source, 0}, fmt.Sprintf("the %s package %s %s is licensed under the copyleft license %s, which is not allowed", dependency.Type, dependency.Name, dependency.Version, licenses))
+		case licenseUnknown:
+			vc.AddWarningAt("license.unknown", fileLocation{dependency.Source, 0}, fmt.Sprintf("the %s package %s %s has no known license", dependency.Type, dependency.Name, dependency.Version))
+		case licenseNotAllowed:
+			vc.AddErrorAt("license.not_allowed", fileLocation{dependency.Source, 0}, fmt.Sprintf("the %s package %s %s is licensed under %s, which is not allowed", dependency.Type, dependency.Name, dependency.Version, licenses))
+		case licenseAllowed:
+		}
+	}
+}
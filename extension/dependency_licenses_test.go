@@ -0,0 +1,138 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeDependencyFixtures(t *testing.T, files map[string]string) PlatformPlugin {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+
+	for file, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(tmpDir, file)), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, file), []byte(content), os.ModePerm))
+	}
+
+	ext := PlatformPlugin{path: tmpDir, config: &Config{}}
+	ext.Composer.Autoload.Psr4 = map[string]string{"MyPlugin\\": "src/"}
+	ext.Composer.License = "proprietary"
+	ext.Composer.Version = "1.0.0"
+	ext.Composer.Extra.ShopwarePluginClass = "MyPlugin\\MyPlugin"
+
+	return ext
+}
+
+func TestFindBundledDependencies(t *testing.T) {
+	ext := writeDependencyFixtures(t, map[string]string{
+		"vendor/composer/installed.json": `{"packages": [
+			{"name": "foo/bar", "version": "v1.2.0", "license": ["MIT"]},
+			{"name": "phpunit/phpunit", "version": "10.0.0", "license": ["BSD-3-Clause"]}
+		], "dev-package-names": ["phpunit/phpunit"]}`,
+		"src/Resources/app/administration/package-lock.json": `{"lockfileVersion": 3, "packages": {
+			"": {"name": "admin"},
+			"node_modules/@scope/lib": {"version": "2.0.0", "license": "ISC"},
+			"node_modules/jest": {"version": "29.0.0", "license": "MIT", "dev": true}
+		}}`,
+		"src/Resources/app/storefront/package-lock.json": `{"lockfileVersion": 1, "dependencies": {
+			"legacy": {"version": "0.1.0"}
+		}}`,
+		"src/Resources/app/storefront/node_modules/legacy/package.json": `{"license": {"type": "GPL-2.0"}}`,
+	})
+
+	dependencies, err := FindBundledDependencies(ext)
+	require.NoError(t, err)
+
+	assert.Equal(t, []BundledDependency{
+		{Name: "foo/bar", Version: "v1.2.0", Type: DependencyTypeComposer, Licenses: []string{"MIT"}, Source: "vendor/composer/installed.json"},
+		{Name: "@scope/lib", Version: "2.0.0", Type: DependencyTypeNpm, Licenses: []string{"ISC"}, Source: "src/Resources/app/administration/package-lock.json"},
+		{Name: "legacy", Version: "0.1.0", Type: DependencyTypeNpm, Licenses: []string{"GPL-2.0"}, Source: "src/Resources/app/storefront/package-lock.json"},
+	}, dependencies)
+}
+
+func TestFindBundledDependenciesSkipsShopwareDevSetup(t *testing.T) {
+	ext := writeDependencyFixtures(t, map[string]string{
+		"vendor/composer/installed.json": `[{"name": "shopware/core", "version": "6.6.0.0", "license": ["MIT"]}, {"name": "foo/gpl", "license": ["GPL-3.0-only"]}]`,
+	})
+
+	dependencies, err := FindBundledDependencies(ext)
+	require.NoError(t, err)
+	assert.Empty(t, dependencies)
+}
+
+func TestClassifyLicenses(t *testing.T) {
+	allowed := []string{"MIT", "Apache-2.0"}
+
+	assert.Equal(t, licenseAllowed, classifyLicenses([]string{"mit"}, allowed))
+	assert.Equal(t, licenseAllowed, classifyLicenses([]string{"GPL-3.0-only", "MIT"}, allowed))
+	assert.Equal(t, licenseAllowed, classifyLicenses([]string{"(GPL-2.0 OR MIT)"}, allowed))
+	assert.Equal(t, licenseAllowed, classifyLicenses([]string{"MIT AND Apache-2.0"}, allowed))
+	assert.Equal(t, licenseCopyleft, classifyLicenses([]string{"MIT AND LGPL-2.1"}, allowed))
+	assert.Equal(t, licenseCopyleft, classifyLicenses([]string{"AGPL-3.0-or-later"}, allowed))
+	assert.Equal(t, licenseUnknown, classifyLicenses(nil, allowed))
+	assert.Equal(t, licenseUnknown, classifyLicenses([]string{"SEE LICENSE IN LICENSE.md"}, allowed))
+	assert.Equal(t, licenseNotAllowed, classifyLicenses([]string{"BSD-4-Clause"}, allowed))
+}
+
+func TestValidateDependencyLicenses(t *testing.T) {
+	ext := writeDependencyFixtures(t, map[string]string{
+		"vendor/composer/installed.json": `{"packages": [
+			{"name": "foo/mit", "version": "1.0.0", "license": ["MIT"]},
+			{"name": "foo/gpl", "version": "1.0.0", "license": ["GPL-3.0-only"]},
+			{"name": "foo/own", "version": "1.0.0", "license": ["proprietary"]},
+			{"name": "foo/none", "version": "1.0.0"}
+		]}`,
+	})
+
+	check := newValidationContext(ext)
+	validateDependencyLicenses(check)
+
+	assert.Len(t, check.errors, 1)
+	assert.Equal(t, "license.copyleft", check.errors[0].Identifier)
+	assert.Equal(t, "vendor/composer/installed.json: the composer package foo/gpl 1.0.0 is licensed under the copyleft license GPL-3.0-only, which is not allowed", check.errors[0].Message)
+	assert.Equal(t, "vendor/composer/installed.json", check.errors[0].File)
+
+	assert.Len(t, check.warnings, 1)
+	assert.Equal(t, "license.unknown", check.warnings[0].Identifier)
+
+	ext.config.Validation.Licenses.Allowed = []string{"GPL-3.0-only"}
+
+	check = newValidationContext(ext)
+	validateDependencyLicenses(check)
+
+	assert.Len(t, check.errors, 1)
+	assert.Equal(t, "license.not_allowed", check.errors[0].Identifier)
+}
+
+func TestGenerateSPDX(t *testing.T) {
+	ext := writeDependencyFixtures(t, nil)
+
+	document, err := GenerateSPDX(ext, []BundledDependency{
+		{Name: "foo/bar", Version: "v1.2.0", Type: DependencyTypeComposer, Licenses: []string{"MIT", "GPL-2.0-or-later"}},
+		{Name: "@scope/lib", Version: "2.0.0", Type: DependencyTypeNpm},
+	}, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	require.NoError(t, err)
+
+	assert.Equal(t, "SPDX-2.3", document.SPDXVersion)
+	assert.Equal(t, "2024-01-02T03:04:05Z", document.CreationInfo.Created)
+	assert.Contains(t, document.DocumentNamespace, "https://spdx.shopware.com/MyPlugin-")
+	assert.Len(t, document.Packages, 3)
+
+	assert.Equal(t, "SPDXRef-Package-MyPlugin", document.Packages[0].SPDXID)
+	assert.Equal(t, "1.0.0", document.Packages[0].VersionInfo)
+	assert.Equal(t, "proprietary", document.Packages[0].LicenseDeclared)
+
+	assert.Equal(t, "(MIT OR GPL-2.0-or-later)", document.Packages[1].LicenseDeclared)
+	assert.Equal(t, "pkg:composer/foo/bar@1.2.0", document.Packages[1].ExternalRefs[0].ReferenceLocator)
+	assert.Equal(t, "NOASSERTION", document.Packages[2].LicenseDeclared)
+	assert.Equal(t, "pkg:npm/%40scope/lib@2.0.0", document.Packages[2].ExternalRefs[0].ReferenceLocator)
+
+	assert.Equal(t, SPDXRelationship{SpdxElementId: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSpdxElement: "SPDXRef-Package-MyPlugin"}, document.Relationships[0])
+	assert.Len(t, document.Relationships, 3)
+}
@@ -0,0 +1,234 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/deprecation"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var (
+	deprecationClassRegExp     = regexp.MustCompile(`\bShopware(?:\\+[A-Za-z_]\w*)+`)
+	deprecationServiceRegExp   = regexp.MustCompile(`\bid="([^"]+)"`)
+	deprecationComponentRegExp = regexp.MustCompile("(?:<|['\"`])((?:sw|mt)-[a-z0-9-]+)")
+	deprecationBackslashRegExp = regexp.MustCompile(`\\+`)
+)
+
+var deprecationKindLabels = map[string]string{
+	deprecation.KindClass:     "class",
+	deprecation.KindService:   "service",
+	deprecation.KindComponent: "administration component",
+}
+
+// shopwareAPIReference is a reference to a Shopware API found in the source code
+type shopwareAPIReference struct {
+	kind string
+	name string
+	line int
+}
+
+type deprecatedAPIUsage struct {
+	entry    deprecation.Entry
+	location fileLocation
+}
+
+// validateDeprecatedAPIUsage reports usages of Shopware APIs which are deprecated or removed in the Shopware versions allowed by the composer constraint
+func validateDeprecatedAPIUsage(ctx context.Context, vc *ValidationContext) {
+	if _, err := vc.Extension.GetShopwareVersionConstraint(); err != nil {
+		return
+	}
+
+	db, err := deprecation.NewDatabase()
+	if err != nil {
+		vc.AddWarning("deprecation.database", fmt.Sprintf("Could not load the deprecation database: %s", err.Error()))
+		return
+	}
+
+	usages := findDeprecatedAPIUsages(vc.Extension, db)
+	if len(usages) == 0 {
+		return
+	}
+
+	maxVersion := getMaxSupportedShopwareVersion(ctx, vc.Extension, db)
+	if maxVersion == nil {
+		return
+	}
+
+	for _, usage := range usages {
+		reportDeprecatedAPIUsage(vc, usage, maxVersion)
+	}
+}
+
+// getMaxSupportedShopwareVersion returns the newest Shopware version allowed by the composer constraint of the extension
+func getMaxSupportedShopwareVersion(ctx context.Context, ext Extension, db *deprecation.Database) *version.Version {
+	constraint, err := ext.GetShopwareVersionConstraint()
+	if err != nil {
+		return nil
+	}
+
+	versions, err := GetShopwareVersions(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Debugf("Could not fetch Shopware versions, using the releases of the deprecation database: %v", err)
+		versions = db.Releases()
+	}
+
+	return getMaxMatchingVersion(constraint, versions)
+}
+
+func getMaxMatchingVersion(constraint *version.Constraints, versions []string) *version.Version {
+	var maxVersion *version.Version
+
+	for _, r := range versions {
+		v, err := version.NewVersion(r)
+		if err != nil {
+			continue
+		}
+
+		if constraint.Check(v) && (maxVersion == nil || v.GreaterThan(maxVersion)) {
+			maxVersion = v
+		}
+	}
+
+	return maxVersion
+}
+
+// findDeprecatedAPIUsages returns the usages of Shopware APIs which are listed in the deprecation database
+func findDeprecatedAPIUsages(ext Extension, db *deprecation.Database) []deprecatedAPIUsage {
+	rootDir := ext.GetRootDir()
+	usages := []deprecatedAPIUsage{}
+
+	for _, sourceDir := range ext.GetSourceDirs() {
+		_ = filepath.WalkDir(sourceDir, func(file string, d os.DirEntry, err error) error {
+			if err != nil {
+				return nil //nolint:nilerr
+			}
+
+			if d.IsDir() {
+				switch d.Name() {
+				case "node_modules", "vendor", "dist", "public":
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			relPath := strings.TrimPrefix(file, rootDir+"/")
+
+			for _, reference := range findShopwareAPIReferences(file) {
+				entry, ok := db.Lookup(reference.kind, reference.name)
+				if !ok {
+					continue
+				}
+
+				usages = append(usages, deprecatedAPIUsage{entry: entry, location: fileLocation{relPath, reference.line}})
+			}
+
+			return nil
+		})
+	}
+
+	return usages
+}
+
+func reportDeprecatedAPIUsage(vc *ValidationContext, usage deprecatedAPIUsage, maxVersion *version.Version) {
+	entry := usage.entry
+	location := usage.location
+
+	replacement := ""
+	if entry.Replacement != "" {
+		replacement = fmt.Sprintf(", use %s instead", entry.Replacement)
+	}
+
+	label := deprecationKindLabels[entry.Kind]
+
+	if entry.Removed != "" && maxVersion.GreaterThanOrEqual(version.Must(version.NewVersion(entry.Removed))) {
+		vc.AddErrorAt("deprecation.removed", location, fmt.Sprintf("the %s %s was removed in Shopware %s, but the extension claims to support Shopware %s%s", label, entry.Name, entry.Removed, maxVersion.String(), replacement))
+		return
+	}
+
+	if entry.Deprecated != "" && maxVersion.GreaterThanOrEqual(version.Must(version.NewVersion(entry.Deprecated))) {
+		removal := ""
+		if entry.Removed != "" {
+			removal = fmt.Sprintf(" and will be removed in Shopware %s", entry.Removed)
+		}
+
+		vc.AddWarningAt("deprecation.deprecated", location, fmt.Sprintf("the %s %s is deprecated since Shopware %s%s%s", label, entry.Name, entry.Deprecated, removal, replacement))
+	}
+}
+
+// findShopwareAPIReferences returns the Shopware classes, services and administration components referenced in the file
+func findShopwareAPIReferences(file string) []shopwareAPIReference {
+	isAdministration := strings.Contains(filepath.ToSlash(file), "/app/administration/")
+
+	var searchClasses, searchServices, searchComponents bool
+
+	switch filepath.Ext(file) {
+	case ".php":
+		searchClasses = true
+	case ".xml":
+		searchClasses = true
+		searchServices = true
+	case ".js", ".ts", ".twig", ".html":
+		searchComponents = isAdministration
+	}
+
+	if !searchClasses && !searchServices && !searchComponents {
+		return nil
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+
+	references := []shopwareAPIReference{}
+	seen := map[string]bool{}
+
+	add := func(kind, name string, line int) {
+		key := fmt.Sprintf("%s:%s:%d", kind, name, line)
+		if seen[key] {
+			return
+		}
+
+		seen[key] = true
+		references = append(references, shopwareAPIReference{kind: kind, name: name, line: line})
+	}
+
+	for i, line := range strings.Split(string(content), "\n") {
+		if searchClasses {
+			for _, match := range deprecationClassRegExp.FindAllString(line, -1) {
+				// Class names in PHP strings have escaped backslashes
+				add(deprecation.KindClass, deprecationBackslashRegExp.ReplaceAllString(match, `\`), i+1)
+			}
+		}
+
+		if searchServices {
+			for _, match := range deprecationServiceRegExp.FindAllStringSubmatch(line, -1) {
+				add(deprecation.KindService, match[1], i+1)
+			}
+		}
+
+		if searchComponents {
+			// Overridden components are reported by validateAdminComponents
+			overridden := adminComponentOverrideTargets(line)
+
+			for _, match := range deprecationComponentRegExp.FindAllStringSubmatch(line, -1) {
+				if slices.Contains(overridden, match[1]) {
+					continue
+				}
+
+				add(deprecation.KindComponent, match[1], i+1)
+			}
+		}
+	}
+
+	return references
+}
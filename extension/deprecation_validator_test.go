@@ -0,0 +1,81 @@
+package extension
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/shyim/go-version"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shopware/shopware-cli/internal/deprecation"
+)
+
+func TestDeprecatedAPIUsage(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	ext := PlatformPlugin{
+		path:   tmpDir,
+		config: &Config{},
+	}
+	ext.Composer.Autoload.Psr4 = map[string]string{"MyPlugin\\": "src/"}
+
+	controllerDir := path.Join(tmpDir, "src", "Controller")
+	componentDir := path.Join(tmpDir, "src", "Resources", "app", "administration", "src", "component")
+	_ = os.MkdirAll(controllerDir, os.ModePerm)
+	_ = os.MkdirAll(componentDir, os.ModePerm)
+
+	_ = os.WriteFile(path.Join(controllerDir, "MyController.php"), []byte(`<?php
+
+namespace MyPlugin\Controller;
+
+use Shopware\Core\Framework\Routing\Annotation\RouteScope;
+use Shopware\Core\Framework\Context;
+`), os.ModePerm)
+	_ = os.WriteFile(path.Join(componentDir, "my-component.html.twig"), []byte(`<div>
+    <sw-button variant="primary">Save</sw-button>
+    <sw-page></sw-page>
+</div>
+`), os.ModePerm)
+
+	db, err := deprecation.NewDatabase()
+	assert.NoError(t, err)
+
+	usages := findDeprecatedAPIUsages(ext, db)
+	assert.Len(t, usages, 2)
+
+	t.Run("removed in supported version", func(t *testing.T) {
+		vc := newValidationContext(ext)
+
+		for _, usage := range usages {
+			reportDeprecatedAPIUsage(vc, usage, version.Must(version.NewVersion("6.7.0.0")))
+		}
+
+		assert.Len(t, vc.Errors(), 2)
+		assert.Equal(t, "deprecation.removed", vc.Errors()[0].Identifier)
+		assert.Contains(t, vc.Errors()[0].Message, "Controller/MyController.php:5: the class Shopware\\Core\\Framework\\Routing\\Annotation\\RouteScope was removed in Shopware 6.5.0.0")
+	})
+
+	t.Run("deprecated in supported version", func(t *testing.T) {
+		vc := newValidationContext(ext)
+
+		for _, usage := range usages {
+			reportDeprecatedAPIUsage(vc, usage, version.Must(version.NewVersion("6.6.4.0")))
+		}
+
+		assert.Len(t, vc.Errors(), 1)
+		assert.Len(t, vc.Warnings(), 1)
+		assert.Equal(t, "deprecation.deprecated", vc.Warnings()[0].Identifier)
+		assert.Contains(t, vc.Warnings()[0].Message, "the administration component sw-button is deprecated since Shopware 6.6.0.0 and will be removed in Shopware 6.7.0.0, use mt-button instead")
+	})
+}
+
+func TestFindShopwareAPIReferencesInPHPStrings(t *testing.T) {
+	file := path.Join(t.TempDir(), "services.php")
+	_ = os.WriteFile(file, []byte(`<?php
+$class = 'Shopware\\Storefront\\Framework\\Cache\\CacheStore';
+`), os.ModePerm)
+
+	references := findShopwareAPIReferences(file)
+	assert.Equal(t, []shopwareAPIReference{{kind: deprecation.KindClass, name: "Shopware\\Storefront\\Framework\\Cache\\CacheStore", line: 2}}, references)
+}
@@ -0,0 +1,144 @@
+package extension
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// externalValidatorTimeout is the time after which an external validator is stopped
+const externalValidatorTimeout = 5 * time.Minute
+
+// externalValidatorOutput is the JSON an external validator prints to stdout, e.g.
+//
+//	{"findings": [{"identifier": "no_var_dump", "severity": "error", "message": "Remove var_dump", "path": "src/Service/Foo.php", "line": 12}]}
+type externalValidatorOutput struct {
+	Findings []externalValidatorFinding `json:"findings"`
+}
+
+type externalValidatorFinding struct {
+	Identifier string `json:"identifier"`
+	// error or warning, defaults to error
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	// relative to the extension directory
+	Path string `json:"path"`
+	Line int    `json:"line"`
+}
+
+// validateExternal runs the validators of validation.external and adds their findings, so agencies can enforce own rules.
+// The findings are prefixed with the name of the validator and can be ignored or reconfigured like every other rule.
+// The commands come with the extension, so they are only executed when allowed.
+func validateExternal(ctx context.Context, vc *ValidationContext, allowed bool) {
+	config := vc.Extension.GetExtensionConfig()
+	if config == nil {
+		return
+	}
+
+	if !allowed {
+		if len(config.Validation.External) > 0 {
+			vc.AddWarning("external.skipped", "The external validators of validation.external run only for extension folders, pass --allow-external-validators to run them for this extension")
+		}
+
+		return
+	}
+
+	for _, validator := range config.Validation.External {
+		if validator.Name == "" || validator.Command == "" {
+			vc.AddError("external.failed", "An external validator requires a name and a command")
+			continue
+		}
+
+		output, err := runExternalValidator(ctx, vc.Extension.GetPath(), validator)
+		if err != nil {
+			vc.AddError("external.failed", fmt.Sprintf("The external validator %s failed: %s", validator.Name, err.Error()))
+			continue
+		}
+
+		for _, finding := range output.Findings {
+			identifier := validator.Name
+			if finding.Identifier != "" {
+				identifier += "." + finding.Identifier
+			}
+
+			location, located := externalFindingLocation(vc.Extension, finding)
+
+			switch {
+			case !located && finding.Severity == "warning":
+				vc.AddWarning(identifier, finding.Message)
+			case !located:
+				vc.AddError(identifier, finding.Message)
+			case finding.Severity == "warning":
+				vc.AddWarningAt(identifier, location, finding.Message)
+			default:
+				vc.AddErrorAt(identifier, location, finding.Message)
+			}
+		}
+	}
+}
+
+// runExternalValidator executes the command like the zip hooks. A non-zero exit code is expected when the validator
+// found something, so only output which is no valid JSON is treated as failure.
+func runExternalValidator(ctx context.Context, extensionDir string, validator ConfigValidationExternal) (*externalValidatorOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, externalValidatorTimeout)
+	defer cancel()
+
+	cmd := externalValidatorCommand(ctx, validator.Command, extensionDir)
+	cmd.Dir = extensionDir
+	cmd.Env = append(os.Environ(), fmt.Sprintf("EXTENSION_DIR=%s", extensionDir))
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	runErr := cmd.Run()
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("the command did not finish within %s", externalValidatorTimeout)
+	}
+
+	var output externalValidatorOutput
+
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		if runErr != nil {
+			return nil, fmt.Errorf("%w: %s", runErr, strings.TrimSpace(stderr.String()))
+		}
+
+		return nil, fmt.Errorf("the output is no valid JSON: %w", err)
+	}
+
+	return &output, nil
+}
+
+// externalValidatorCommand runs the command with the shell of the system and passes the extension directory as last argument
+func externalValidatorCommand(ctx context.Context, command, extensionDir string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command+` "`+extensionDir+`"`)
+	}
+
+	return exec.CommandContext(ctx, "sh", "-c", command+` "$@"`, "sh", extensionDir)
+}
+
+// externalFindingLocation returns the location of the finding like the built-in validators report it,
+// their paths are relative to the root dir of the extension, which is the src folder of plugins
+func externalFindingLocation(ext Extension, finding externalValidatorFinding) (fileLocation, bool) {
+	if finding.Path == "" {
+		return fileLocation{}, false
+	}
+
+	location := filepath.Join(ext.GetPath(), filepath.FromSlash(finding.Path))
+
+	if relPath, err := filepath.Rel(ext.GetRootDir(), location); err == nil && !strings.HasPrefix(relPath, "..") {
+		location = filepath.ToSlash(relPath)
+	}
+
+	return fileLocation{file: location, line: max(finding.Line, 0)}, true
+}
@@ -0,0 +1,108 @@
+package extension
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newExternalValidatorTestPlugin(t *testing.T, validators ...ConfigValidationExternal) PlatformPlugin {
+	t.Helper()
+
+	config := &Config{}
+	config.Validation.External = validators
+
+	ext := PlatformPlugin{path: t.TempDir(), config: config}
+	ext.Composer.Extra.ShopwarePluginClass = "FroshTools\\FroshTools"
+
+	return ext
+}
+
+func TestValidateExternalFindings(t *testing.T) {
+	ext := newExternalValidatorTestPlugin(t, ConfigValidationExternal{
+		Name: "house-rules",
+		Command: `echo '{"findings": [
+			{"identifier": "no_var_dump", "message": "Remove var_dump", "path": "src/Service/Foo.php", "line": 12},
+			{"identifier": "readme", "severity": "warning", "message": "Add a README", "path": "README.md"},
+			{"message": "Use the agency license"}
+		]}'; exit 1`,
+	})
+
+	check := newValidationContext(ext)
+	validateExternal(t.Context(), check, true)
+
+	assert.Len(t, check.Errors(), 2)
+	assert.Equal(t, "house-rules.no_var_dump", check.Errors()[0].Identifier)
+	assert.Equal(t, "Service/Foo.php:12: Remove var_dump", check.Errors()[0].Message)
+	assert.Equal(t, "house-rules", check.Errors()[1].Identifier)
+	assert.Equal(t, "Use the agency license", check.Errors()[1].Message)
+
+	assert.Len(t, check.Warnings(), 1)
+	assert.Equal(t, "house-rules.readme", check.Warnings()[0].Identifier)
+	assert.Equal(t, filepath.Join(ext.GetPath(), "README.md")+": Add a README", check.Warnings()[0].Message)
+}
+
+func TestValidateExternalReceivesExtensionPath(t *testing.T) {
+	ext := newExternalValidatorTestPlugin(t, ConfigValidationExternal{
+		Name:    "path",
+		Command: `printf '{"findings": [{"message": "%s %s %s"}]}' "$EXTENSION_DIR" "$(pwd)"`,
+	})
+
+	check := newValidationContext(ext)
+	validateExternal(t.Context(), check, true)
+
+	dir, err := filepath.EvalSymlinks(ext.GetPath())
+	assert.NoError(t, err)
+
+	assert.Len(t, check.Errors(), 1)
+	assert.Contains(t, []string{
+		ext.GetPath() + " " + ext.GetPath() + " " + ext.GetPath(),
+		ext.GetPath() + " " + dir + " " + ext.GetPath(),
+	}, check.Errors()[0].Message)
+}
+
+func TestValidateExternalFailed(t *testing.T) {
+	ext := newExternalValidatorTestPlugin(t,
+		ConfigValidationExternal{Name: "broken", Command: "echo 'not json'"},
+		ConfigValidationExternal{Name: "crashed", Command: "echo 'boom' >&2; exit 2"},
+		ConfigValidationExternal{Name: "unnamed"},
+	)
+
+	check := newValidationContext(ext)
+	validateExternal(t.Context(), check, true)
+
+	assert.Len(t, check.Errors(), 3)
+
+	for _, err := range check.Errors() {
+		assert.Equal(t, "external.failed", err.Identifier)
+	}
+
+	assert.Contains(t, check.Errors()[0].Message, "The external validator broken failed: the output is no valid JSON")
+	assert.Equal(t, "The external validator crashed failed: exit status 2: boom", check.Errors()[1].Message)
+	assert.Equal(t, "An external validator requires a name and a command", check.Errors()[2].Message)
+}
+
+func TestValidateExternalWithoutValidators(t *testing.T) {
+	ext := newExternalValidatorTestPlugin(t)
+
+	check := newValidationContext(ext)
+	validateExternal(t.Context(), check, true)
+
+	assert.Empty(t, check.Errors())
+	assert.Empty(t, check.Warnings())
+}
+
+func TestValidateExternalSkipsUntrustedExtensions(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "executed")
+
+	ext := newExternalValidatorTestPlugin(t, ConfigValidationExternal{Name: "house-rules", Command: "touch " + marker})
+
+	check := newValidationContext(ext)
+	validateExternal(t.Context(), check, false)
+
+	assert.NoFileExists(t, marker)
+	assert.Empty(t, check.Errors())
+	assert.Len(t, check.Warnings(), 1)
+	assert.Equal(t, "external.skipped", check.Warnings()[0].Identifier)
+}
@@ -0,0 +1,64 @@
+package extension
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+)
+
+// Fixer repairs problems the validation reports. Fixers only return the changes, RunFixers writes them.
+type Fixer interface {
+	Name() string
+	Fix(ctx context.Context, ext Extension) ([]FileChange, error)
+}
+
+// FileChange is the new content of a file changed by a fixer
+type FileChange struct {
+	Path        string
+	Description string
+	Before      []byte
+	After       []byte
+}
+
+// FixResult is a change applied by a fixer
+type FixResult struct {
+	Fixer  string
+	Change FileChange
+}
+
+var availableFixers = []Fixer{}
+
+func AddFixer(fixer Fixer) {
+	availableFixers = append(availableFixers, fixer)
+}
+
+func GetFixers() []Fixer {
+	return availableFixers
+}
+
+// RunFixers applies the given fixers one after another, so a fixer sees the changes of the previous ones
+func RunFixers(ctx context.Context, ext Extension, fixers []Fixer) ([]FixResult, error) {
+	results := []FixResult{}
+
+	for _, fixer := range fixers {
+		changes, err := fixer.Fix(ctx, ext)
+		if err != nil {
+			return nil, fmt.Errorf("fixer %s failed: %w", fixer.Name(), err)
+		}
+
+		for _, change := range changes {
+			if bytes.Equal(change.Before, change.After) {
+				continue
+			}
+
+			if err := os.WriteFile(change.Path, change.After, 0o644); err != nil {
+				return nil, err
+			}
+
+			results = append(results, FixResult{Fixer: fixer.Name(), Change: change})
+		}
+	}
+
+	return results, nil
+}
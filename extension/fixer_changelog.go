@@ -0,0 +1,80 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	changelogVersionHeadingRegExp = regexp.MustCompile(`^#+\s*\[?[vV]?(\d+\.\d+\.\d+[^\s\]]*)\]?(?:\s+-\s+.*)?$`)
+	changelogBulletRegExp         = regexp.MustCompile(`^(\s*)[*+](\s+)`)
+)
+
+// ChangelogFixer formats the changelog files, so each version is a level one heading the store can parse. Files in the
+// keep a changelog format keep their sections.
+type ChangelogFixer struct{}
+
+func (ChangelogFixer) Name() string {
+	return "changelog"
+}
+
+func (ChangelogFixer) Fix(_ context.Context, ext Extension) ([]FileChange, error) {
+	files, err := filepath.Glob(fmt.Sprintf("%s/CHANGELOG*.md", ext.GetPath()))
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []FileChange{}
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, FileChange{
+			Path:        file,
+			Description: "formatted version headings and list items",
+			Before:      content,
+			After:       []byte(formatChangelog(string(content))),
+		})
+	}
+
+	return changes, nil
+}
+
+func formatChangelog(content string) string {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	inCodeBlock := false
+	// The sections of keep a changelog files are parsed with their format
+	keepAChangelog := isKeepAChangelog(content)
+
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t")
+
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+		}
+
+		if inCodeBlock {
+			lines[i] = line
+			continue
+		}
+
+		if matches := changelogVersionHeadingRegExp.FindStringSubmatch(line); matches != nil && !keepAChangelog {
+			line = "# " + matches[1]
+		}
+
+		lines[i] = changelogBulletRegExp.ReplaceAllString(line, "${1}-${2}")
+	}
+
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+}
+
+func init() {
+	AddFixer(ChangelogFixer{})
+}
@@ -0,0 +1,174 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/shopware/shopware-cli/internal/spdx"
+)
+
+// composerLinkSections are the composer.json sections containing package links, composer sorts them with sort-packages enabled
+var composerLinkSections = []string{"require", "require-dev", "conflict", "replace", "provide", "suggest"}
+
+// ComposerJSONFixer normalizes the composer.json to the format composer writes itself
+type ComposerJSONFixer struct{}
+
+func (ComposerJSONFixer) Name() string {
+	return "composer-json"
+}
+
+func (ComposerJSONFixer) Fix(_ context.Context, ext Extension) ([]FileChange, error) {
+	if ext.GetType() != TypePlatformPlugin {
+		return nil, nil
+	}
+
+	composerFile := path.Join(ext.GetPath(), "composer.json")
+
+	content, err := os.ReadFile(composerFile)
+	if err != nil {
+		return nil, nil //nolint:nilerr
+	}
+
+	parsed, err := parseOrderedJSON(content)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", composerFile, err)
+	}
+
+	composer, ok := parsed.(*orderedObject)
+	if !ok {
+		return nil, nil
+	}
+
+	for _, section := range composerLinkSections {
+		if links, ok := composer.values[section].(*orderedObject); ok {
+			slices.SortStableFunc(links.keys, compareComposerPackages)
+		}
+	}
+
+	descriptions := []string{"normalized indentation and sorted packages"}
+
+	if fixComposerAutoload(composer) {
+		descriptions = append(descriptions, "added missing psr-4 separators")
+	}
+
+	if fixComposerLicense(composer) {
+		descriptions = append(descriptions, "normalized license identifiers")
+	}
+
+	return []FileChange{
+		{
+			Path:        composerFile,
+			Description: strings.Join(descriptions, ", "),
+			Before:      content,
+			After:       encodeOrderedJSON(composer, "    "),
+		},
+	}, nil
+}
+
+// fixComposerAutoload adds the namespace separator composer requires at the end of psr-4 namespaces and the trailing
+// slash to their directories
+func fixComposerAutoload(composer *orderedObject) bool {
+	changed := false
+
+	for _, section := range []string{"autoload", "autoload-dev"} {
+		autoload, ok := composer.values[section].(*orderedObject)
+		if !ok {
+			continue
+		}
+
+		psr4, ok := autoload.values["psr-4"].(*orderedObject)
+		if !ok {
+			continue
+		}
+
+		for i, namespace := range psr4.keys {
+			value := psr4.values[namespace]
+
+			if dir, ok := value.(string); ok && dir != "" && !strings.HasSuffix(dir, "/") {
+				value = dir + "/"
+				changed = true
+			}
+
+			// A namespace which exists with the separator too is left for the validation to report
+			if _, exists := psr4.values[namespace+"\\"]; namespace != "" && !strings.HasSuffix(namespace, "\\") && !exists {
+				delete(psr4.values, namespace)
+				namespace += "\\"
+				psr4.keys[i] = namespace
+				changed = true
+			}
+
+			psr4.values[namespace] = value
+		}
+	}
+
+	return changed
+}
+
+// fixComposerLicense writes the license identifiers like in the SPDX list, so mit becomes MIT
+func fixComposerLicense(composer *orderedObject) bool {
+	spdxList, err := spdx.NewSpdxLicenses()
+	if err != nil {
+		return false
+	}
+
+	normalize := func(license string) string {
+		if strings.EqualFold(strings.TrimSpace(license), "proprietary") {
+			return "proprietary"
+		}
+
+		return spdxList.Normalize(license)
+	}
+
+	changed := false
+
+	switch license := composer.values["license"].(type) {
+	case string:
+		if normalized := normalize(license); normalized != license {
+			composer.values["license"] = normalized
+			changed = true
+		}
+	case []any:
+		for i, value := range license {
+			if identifier, ok := value.(string); ok && normalize(identifier) != identifier {
+				license[i] = normalize(identifier)
+				changed = true
+			}
+		}
+	}
+
+	return changed
+}
+
+// compareComposerPackages sorts platform packages like php and ext-* before the regular packages
+func compareComposerPackages(a, b string) int {
+	aPlatform := isComposerPlatformPackage(a)
+	bPlatform := isComposerPlatformPackage(b)
+
+	if aPlatform != bPlatform {
+		if aPlatform {
+			return -1
+		}
+
+		return 1
+	}
+
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+func isComposerPlatformPackage(name string) bool {
+	name = strings.ToLower(name)
+
+	if name == "php" || strings.HasPrefix(name, "php-") || name == "composer" || strings.HasPrefix(name, "composer-") {
+		return true
+	}
+
+	return strings.HasPrefix(name, "ext-") || strings.HasPrefix(name, "lib-")
+}
+
+func init() {
+	AddFixer(ComposerJSONFixer{})
+}
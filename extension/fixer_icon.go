@@ -0,0 +1,92 @@
+package extension
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	maxIconDimension = 256
+	maxIconFileSize  = 50 * 1024
+)
+
+// IconFixer scales down extension icons which are bigger than the store allows
+type IconFixer struct{}
+
+func (IconFixer) Name() string {
+	return "icon"
+}
+
+func (IconFixer) Fix(_ context.Context, ext Extension) ([]FileChange, error) {
+	iconPath := ext.GetIconPath()
+	if iconPath == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(iconPath)
+	if err != nil {
+		return nil, nil //nolint:nilerr
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode icon: %w", err)
+	}
+
+	width, height := src.Bounds().Dx(), src.Bounds().Dy()
+
+	if width <= maxIconDimension && height <= maxIconDimension && len(content) <= maxIconFileSize {
+		return nil, nil
+	}
+
+	var dst image.Image = src
+	description := fmt.Sprintf("compressed from %d bytes", len(content))
+	resize := width > maxIconDimension || height > maxIconDimension
+
+	if resize {
+		// The longer side is scaled to the maximum, so the aspect ratio is kept
+		newWidth, newHeight := maxIconDimension, maxIconDimension
+		if width > height {
+			newHeight = max(1, height*maxIconDimension/width)
+		} else {
+			newWidth = max(1, width*maxIconDimension/height)
+		}
+
+		scaled := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
+		draw.BiLinear.Scale(scaled, scaled.Rect, src, src.Bounds(), draw.Over, nil)
+
+		dst = scaled
+		description = fmt.Sprintf("resized from %dx%d to %dx%d", width, height, newWidth, newHeight)
+	}
+
+	var buf bytes.Buffer
+
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, dst); err != nil {
+		return nil, fmt.Errorf("cannot encode icon: %w", err)
+	}
+
+	// Recompressing does not always help, keep the original then
+	if !resize && buf.Len() >= len(content) {
+		return nil, nil
+	}
+
+	return []FileChange{
+		{
+			Path:        iconPath,
+			Description: description,
+			Before:      content,
+			After:       buf.Bytes(),
+		},
+	}, nil
+}
+
+func init() {
+	AddFixer(IconFixer{})
+}
@@ -0,0 +1,133 @@
+package extension
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// SnippetFixer adds the keys missing in a language with the text of the main language (en-GB) and sorts the keys of all snippet files
+type SnippetFixer struct{}
+
+func (SnippetFixer) Name() string {
+	return "snippets"
+}
+
+func (SnippetFixer) Fix(_ context.Context, ext Extension) ([]FileChange, error) {
+	changes := []FileChange{}
+
+	folders := map[string]bool{}
+	for _, folder := range getStorefrontSnippetFolders(ext) {
+		folders[folder] = false
+	}
+
+	for _, folder := range getAdministrationSnippetFolders(ext) {
+		folders[folder] = true
+	}
+
+	for folder, administration := range folders {
+		snippetFiles, err := findSnippetFiles(folder, administration)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, files := range snippetFiles {
+			var main *orderedObject
+
+			if mainFile := findMainSnippetFile(files); mainFile != "" {
+				mainContent, err := os.ReadFile(mainFile)
+				if err != nil {
+					return nil, err
+				}
+
+				parsed, err := parseOrderedJSON(mainContent)
+				if err != nil {
+					return nil, fmt.Errorf("cannot parse %s: %w", mainFile, err)
+				}
+
+				main, _ = parsed.(*orderedObject)
+			}
+
+			for _, file := range files {
+				change, err := fixSnippetFile(main, file)
+				if err != nil {
+					return nil, err
+				}
+
+				if change != nil {
+					changes = append(changes, *change)
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b FileChange) int {
+		return cmp.Compare(a.Path, b.Path)
+	})
+
+	return changes, nil
+}
+
+func fixSnippetFile(main *orderedObject, file string) (*FileChange, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	snippets, err := parseOrderedJSON(content)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", file, err)
+	}
+
+	object, ok := snippets.(*orderedObject)
+	if !ok {
+		return nil, nil
+	}
+
+	description := "sorted keys"
+
+	if main != nil && stubMissingSnippets(main, object) {
+		description = "added missing keys from the main language and sorted keys"
+	}
+
+	object.sortKeys()
+
+	var buf bytes.Buffer
+	writeOrderedJSON(&buf, object, detectJSONIndent(content), 0)
+
+	if bytes.HasSuffix(content, []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+
+	return &FileChange{Path: file, Description: description, Before: content, After: buf.Bytes()}, nil
+}
+
+func stubMissingSnippets(main, target *orderedObject) bool {
+	changed := false
+
+	for _, key := range main.keys {
+		value, exists := target.values[key]
+
+		if !exists {
+			target.set(key, main.values[key])
+			changed = true
+			continue
+		}
+
+		mainChild, mainIsObject := main.values[key].(*orderedObject)
+		child, isObject := value.(*orderedObject)
+
+		if mainIsObject && isObject && stubMissingSnippets(mainChild, child) {
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+func init() {
+	AddFixer(SnippetFixer{})
+}
@@ -0,0 +1,134 @@
+package extension
+
+import (
+	"bytes"
+	"image"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComposerJSONFixerSortsPackages(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "composer.json"), []byte(`{
+  "name": "frosh/test",
+  "require": {
+    "shopware/core": "~6.6.0",
+    "ext-json": "*",
+    "php": ">=8.2",
+    "Acme/lib": "^1.0"
+  },
+  "autoload": {"psr-4": {"Frosh\\Test\\": "src/"}}
+}`), os.ModePerm))
+
+	ext := PlatformPlugin{path: tmpDir, config: &Config{}}
+
+	changes, err := ComposerJSONFixer{}.Fix(getTestContext(), ext)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, `{
+    "name": "frosh/test",
+    "require": {
+        "ext-json": "*",
+        "php": ">=8.2",
+        "Acme/lib": "^1.0",
+        "shopware/core": "~6.6.0"
+    },
+    "autoload": {
+        "psr-4": {
+            "Frosh\\Test\\": "src/"
+        }
+    }
+}
+`, string(changes[0].After))
+}
+
+func TestChangelogFixerFormatsHeadings(t *testing.T) {
+	assert.Equal(t, "# 1.0.1\n\n- Fixed a bug\n  - With details\n\n# 1.0.0\n\n- Initial release\n", formatChangelog("## v1.0.1   \r\n\r\n* Fixed a bug\r\n  + With details\r\n\r\n# 1.0.0\n\n- Initial release\n\n\n"))
+	assert.Equal(t, "# 1.0.0\n\n```\n* keep\n```\n", formatChangelog("#1.0.0\n\n```\n* keep\n```"))
+	assert.Equal(t, "## [1.0.0] - 2024-05-01\n### Added\n- Initial release\n", formatChangelog("## [1.0.0] - 2024-05-01\n### Added\n* Initial release\n"))
+}
+
+func TestIconFixerResizesBigIcons(t *testing.T) {
+	iconPath := filepath.Join(t.TempDir(), "icon.png")
+	assert.NoError(t, createTestImageWithSize(iconPath, 512, 512))
+
+	changes, err := IconFixer{}.Fix(getTestContext(), &mockExtension{iconPath: iconPath})
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "resized from 512x512 to 256x256", changes[0].Description)
+
+	img, _, err := image.DecodeConfig(bytes.NewReader(changes[0].After))
+	assert.NoError(t, err)
+	assert.Equal(t, 256, img.Width)
+	assert.Equal(t, 256, img.Height)
+}
+
+func TestIconFixerKeepsAspectRatio(t *testing.T) {
+	iconPath := filepath.Join(t.TempDir(), "icon.png")
+	assert.NoError(t, createTestImageWithSize(iconPath, 1024, 512))
+
+	changes, err := IconFixer{}.Fix(getTestContext(), &mockExtension{iconPath: iconPath})
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "resized from 1024x512 to 256x128", changes[0].Description)
+
+	img, _, err := image.DecodeConfig(bytes.NewReader(changes[0].After))
+	assert.NoError(t, err)
+	assert.Equal(t, 256, img.Width)
+	assert.Equal(t, 128, img.Height)
+}
+
+func TestRunFixersWritesOnlyChangedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	formatted := "# 1.0.0\n\n- Initial release\n"
+
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "CHANGELOG.md"), []byte(formatted), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "CHANGELOG_de-DE.md"), []byte("## 1.0.0\n* Erstes Release"), os.ModePerm))
+
+	results, err := RunFixers(getTestContext(), &mockExtension{path: tmpDir, config: &Config{}}, GetFixers())
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "changelog", results[0].Fixer)
+	assert.Equal(t, filepath.Join(tmpDir, "CHANGELOG_de-DE.md"), results[0].Change.Path)
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "CHANGELOG_de-DE.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "# 1.0.0\n- Erstes Release\n", string(content))
+}
+
+func TestComposerJSONFixerFixesAutoloadAndLicense(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "composer.json"), []byte("{  \n"+`    "name": "frosh/test",   
+    "license": "mit",
+    "autoload": {"psr-4": {"Frosh\\Test": "src", "Frosh\\Other\\": "other/"}},
+    "autoload-dev": {"psr-4": {"Frosh\\Test\\Tests": "tests"}}
+}`), os.ModePerm))
+
+	ext := PlatformPlugin{path: tmpDir, config: &Config{}}
+
+	changes, err := ComposerJSONFixer{}.Fix(getTestContext(), ext)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "normalized indentation and sorted packages, added missing psr-4 separators, normalized license identifiers", changes[0].Description)
+	assert.Equal(t, `{
+    "name": "frosh/test",
+    "license": "MIT",
+    "autoload": {
+        "psr-4": {
+            "Frosh\\Test\\": "src/",
+            "Frosh\\Other\\": "other/"
+        }
+    },
+    "autoload-dev": {
+        "psr-4": {
+            "Frosh\\Test\\Tests\\": "tests/"
+        }
+    }
+}
+`, string(changes[0].After))
+}
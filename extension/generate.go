@@ -0,0 +1,275 @@
+package extension
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopware/shopware-cli/internal/esbuild"
+)
+
+const (
+	GenerateEntity          = "entity"
+	GenerateCommand         = "command"
+	GenerateScheduledTask   = "scheduled-task"
+	GenerateEventSubscriber = "event-subscriber"
+	GenerateAdminModule     = "admin-module"
+	GenerateCmsElement      = "cms-element"
+	GenerateMigration       = "migration"
+)
+
+// GenerateKinds are the constructs Generate can add to a plugin
+var GenerateKinds = []string{GenerateEntity, GenerateCommand, GenerateScheduledTask, GenerateEventSubscriber, GenerateAdminModule, GenerateCmsElement, GenerateMigration}
+
+// generateNameSuffixes are removed from the name, as the templates append them already
+var generateNameSuffixes = map[string][]string{
+	GenerateEntity:          {"Definition", "Entity"},
+	GenerateCommand:         {"Command"},
+	GenerateScheduledTask:   {"TaskHandler", "Task"},
+	GenerateEventSubscriber: {"Subscriber"},
+}
+
+//go:embed all:generate
+var generateFiles embed.FS
+
+type GenerateOptions struct {
+	Kind string
+	// Name is the PascalCase name of the construct like ExportProducts
+	Name string
+	// Now is used for the timestamp of migrations, defaults to the current time. Timestamps of existing migrations are skipped.
+	Now time.Time
+}
+
+type generateData struct {
+	Namespace       string
+	Name            string
+	KebabName       string
+	SnakeName       string
+	PluginKebabName string
+	SnakePluginName string
+	// EntityName is the entity and table name prefixed with the plugin like frosh_tools_bundle
+	EntityName string
+	// ModuleName is the Administration module or CMS element name prefixed with the plugin like frosh-tools-bundle
+	ModuleName  string
+	ModuleRoute string
+	ModuleBlock string
+	Timestamp   int64
+}
+
+// Generate renders the boilerplate of the construct into the plugin. Services are registered in services.xml and
+// Administration code is imported in main.js. The created and changed files are returned relative to the plugin folder.
+func Generate(ext Extension, opts GenerateOptions) ([]string, error) {
+	if !slices.Contains(GenerateKinds, opts.Kind) {
+		return nil, fmt.Errorf("unknown generator %s, use one of %s", opts.Kind, strings.Join(GenerateKinds, ", "))
+	}
+
+	plugin, ok := ext.(*PlatformPlugin)
+	if !ok {
+		return nil, fmt.Errorf("generators require a plugin, %s is not supported", ext.GetType())
+	}
+
+	for _, suffix := range generateNameSuffixes[opts.Kind] {
+		if opts.Name != suffix {
+			opts.Name = strings.TrimSuffix(opts.Name, suffix)
+		}
+	}
+
+	if !scaffoldNameRegExp.MatchString(opts.Name) {
+		return nil, fmt.Errorf("the name %s must be in PascalCase like ExportProducts", opts.Name)
+	}
+
+	pluginName, err := plugin.GetName()
+	if err != nil {
+		return nil, err
+	}
+
+	pluginClass := plugin.Composer.Extra.ShopwarePluginClass
+	separator := strings.LastIndex(pluginClass, "\\")
+
+	if separator < 0 {
+		return nil, fmt.Errorf("the plugin class %s has no namespace", pluginClass)
+	}
+
+	namespace := pluginClass[:separator]
+
+	srcDir := plugin.Composer.Autoload.Psr4[namespace+"\\"]
+	if srcDir == "" {
+		srcDir = "src/"
+	}
+
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	data := generateData{
+		Namespace:       namespace,
+		Name:            opts.Name,
+		KebabName:       esbuild.ToKebabCase(opts.Name),
+		PluginKebabName: esbuild.ToKebabCase(pluginName),
+		Timestamp:       nextMigrationTimestamp(plugin, opts.Now.Unix()),
+	}
+
+	data.SnakeName = strings.ReplaceAll(data.KebabName, "-", "_")
+	data.SnakePluginName = strings.ReplaceAll(data.PluginKebabName, "-", "_")
+	data.EntityName = data.SnakePluginName + "_" + data.SnakeName
+	data.ModuleName = data.PluginKebabName + "-" + data.KebabName
+	data.ModuleRoute = strings.ReplaceAll(data.ModuleName, "-", ".")
+	data.ModuleBlock = strings.ReplaceAll(data.ModuleName, "-", "_")
+
+	return renderGenerator(plugin.GetPath(), path.Clean(filepath.ToSlash(srcDir)), opts.Kind, data)
+}
+
+// renderGenerator renders all templates first, so nothing is written when one of the files exists already.
+// Templates starting with _ are merged into the services.xml or main.js instead of being written.
+func renderGenerator(root, srcDir, kind string, data generateData) ([]string, error) {
+	replacer := strings.NewReplacer("__MODULE_NAME__", data.ModuleName, "__NAME__", data.Name, "__TIMESTAMP__", strconv.FormatInt(data.Timestamp, 10))
+
+	files := map[string][]byte{}
+	merges := map[string][]byte{}
+
+	kindRoot := path.Join("generate", kind)
+
+	err := fs.WalkDir(generateFiles, kindRoot, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		content, err := generateFiles.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		rendered, err := renderScaffoldTemplate(file, content, data)
+		if err != nil {
+			return err
+		}
+
+		relPath := strings.TrimSuffix(strings.TrimPrefix(file, kindRoot+"/"), ".tmpl")
+
+		if strings.HasPrefix(relPath, "_") {
+			merges[strings.TrimPrefix(relPath, "_")] = rendered
+			return nil
+		}
+
+		relPath = path.Join(srcDir, replacer.Replace(relPath))
+
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(relPath))); err == nil {
+			return fmt.Errorf("the file %s exists already", relPath)
+		}
+
+		files[relPath] = rendered
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make([]string, 0, len(files)+len(merges))
+
+	for relPath, content := range files {
+		targetFile := filepath.Join(root, filepath.FromSlash(relPath))
+
+		if err := os.MkdirAll(filepath.Dir(targetFile), os.ModePerm); err != nil {
+			return nil, err
+		}
+
+		if err := os.WriteFile(targetFile, content, os.ModePerm); err != nil {
+			return nil, err
+		}
+
+		changed = append(changed, relPath)
+	}
+
+	if services, ok := merges["services.xml"]; ok {
+		relPath := path.Join(srcDir, "Resources/config/services.xml")
+
+		if err := registerGeneratedServices(filepath.Join(root, filepath.FromSlash(relPath)), string(services)); err != nil {
+			return nil, err
+		}
+
+		changed = append(changed, relPath)
+	}
+
+	if imports, ok := merges["main.js"]; ok {
+		relPath := path.Join(srcDir, "Resources/app/administration/src/main.js")
+
+		if err := appendGeneratedImport(filepath.Join(root, filepath.FromSlash(relPath)), string(imports)); err != nil {
+			return nil, err
+		}
+
+		changed = append(changed, relPath)
+	}
+
+	slices.Sort(changed)
+
+	return changed, nil
+}
+
+// registerGeneratedServices inserts the service definitions before </services>, a missing services.xml is created
+func registerGeneratedServices(file, services string) error {
+	content, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		content, err = scaffoldFiles.ReadFile("scaffold/plugin/src/Resources/config/services.xml.tmpl")
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(file), os.ModePerm)
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	xml := string(content)
+
+	end := strings.LastIndex(xml, "</services>")
+	if end < 0 {
+		return fmt.Errorf("cannot find </services> in %s, register the services manually:\n%s", file, services)
+	}
+
+	lineStart := strings.LastIndex(xml[:end], "\n") + 1
+	indent := strings.Repeat(" ", len(xml[lineStart:end])-len(strings.TrimLeft(xml[lineStart:end], " "))+4)
+
+	var definitions strings.Builder
+
+	definitions.WriteString("\n")
+
+	for _, line := range strings.Split(strings.TrimRight(services, "\n"), "\n") {
+		if line != "" {
+			definitions.WriteString(indent + line)
+		}
+
+		definitions.WriteString("\n")
+	}
+
+	return os.WriteFile(file, []byte(xml[:lineStart]+definitions.String()+xml[lineStart:]), os.ModePerm)
+}
+
+// appendGeneratedImport adds the import to the main.js of the Administration, a missing main.js is created
+func appendGeneratedImport(file, imports string) error {
+	content, err := os.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if strings.Contains(string(content), strings.TrimSpace(imports)) {
+		return nil
+	}
+
+	if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+		content = append(content, '\n')
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), os.ModePerm); err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, append(content, imports...), os.ModePerm)
+}
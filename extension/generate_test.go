@@ -0,0 +1,169 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newGenerateTestPlugin(t *testing.T) Extension {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	_, err := Scaffold(dir, ScaffoldOptions{Type: ScaffoldTypePlugin, Name: "FroshTools", Namespace: "Frosh\\Tools"})
+	require.NoError(t, err)
+
+	ext, err := GetExtensionByFolder(filepath.Join(dir, "FroshTools"))
+	require.NoError(t, err)
+
+	return ext
+}
+
+func readGeneratedFile(t *testing.T, ext Extension, file string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join(ext.GetPath(), file))
+	require.NoError(t, err)
+
+	return string(content)
+}
+
+func TestGenerateEntity(t *testing.T) {
+	ext := newGenerateTestPlugin(t)
+
+	files, err := Generate(ext, GenerateOptions{Kind: GenerateEntity, Name: "BundleEntity", Now: time.Unix(1700000000, 0)})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"src/Core/Content/Bundle/BundleCollection.php",
+		"src/Core/Content/Bundle/BundleDefinition.php",
+		"src/Core/Content/Bundle/BundleEntity.php",
+		"src/Migration/Migration1700000000CreateBundleTable.php",
+		"src/Resources/config/services.xml",
+	}, files)
+
+	definition := readGeneratedFile(t, ext, "src/Core/Content/Bundle/BundleDefinition.php")
+	assert.Contains(t, definition, "namespace Frosh\\Tools\\Core\\Content\\Bundle;")
+	assert.Contains(t, definition, "public const ENTITY_NAME = 'frosh_tools_bundle';")
+
+	migration := readGeneratedFile(t, ext, "src/Migration/Migration1700000000CreateBundleTable.php")
+	assert.Contains(t, migration, "class Migration1700000000CreateBundleTable extends MigrationStep")
+	assert.Contains(t, migration, "CREATE TABLE IF NOT EXISTS `frosh_tools_bundle`")
+
+	services := readGeneratedFile(t, ext, "src/Resources/config/services.xml")
+	assert.Contains(t, services, `        <service id="Frosh\Tools\Core\Content\Bundle\BundleDefinition">
+            <tag name="shopware.entity.definition" entity="frosh_tools_bundle"/>
+        </service>
+    </services>`)
+}
+
+func TestGenerateServicesAreAppended(t *testing.T) {
+	ext := newGenerateTestPlugin(t)
+
+	_, err := Generate(ext, GenerateOptions{Kind: GenerateCommand, Name: "ExportProducts"})
+	require.NoError(t, err)
+
+	_, err = Generate(ext, GenerateOptions{Kind: GenerateScheduledTask, Name: "Cleanup"})
+	require.NoError(t, err)
+
+	_, err = Generate(ext, GenerateOptions{Kind: GenerateEventSubscriber, Name: "ProductSubscriber"})
+	require.NoError(t, err)
+
+	command := readGeneratedFile(t, ext, "src/Command/ExportProductsCommand.php")
+	assert.Contains(t, command, "#[AsCommand(name: 'frosh-tools:export-products'")
+
+	task := readGeneratedFile(t, ext, "src/ScheduledTask/CleanupTask.php")
+	assert.Contains(t, task, "return 'frosh_tools.cleanup';")
+
+	services := readGeneratedFile(t, ext, "src/Resources/config/services.xml")
+	assert.Contains(t, services, `<service id="Frosh\Tools\Command\ExportProductsCommand">`)
+	assert.Contains(t, services, `<service id="Frosh\Tools\ScheduledTask\CleanupTaskHandler">`)
+	assert.Contains(t, services, `<service id="Frosh\Tools\Subscriber\ProductSubscriber">`)
+	assert.Equal(t, 1, strings.Count(services, "</services>"))
+}
+
+func TestGenerateAdministration(t *testing.T) {
+	ext := newGenerateTestPlugin(t)
+
+	files, err := Generate(ext, GenerateOptions{Kind: GenerateAdminModule, Name: "Overview"})
+	require.NoError(t, err)
+
+	assert.Contains(t, files, "src/Resources/app/administration/src/module/frosh-tools-overview/index.js")
+	assert.Contains(t, files, "src/Resources/app/administration/src/module/frosh-tools-overview/page/frosh-tools-overview-list/frosh-tools-overview-list.html.twig")
+
+	module := readGeneratedFile(t, ext, "src/Resources/app/administration/src/module/frosh-tools-overview/index.js")
+	assert.Contains(t, module, "path: 'frosh.tools.overview.list',")
+
+	list := readGeneratedFile(t, ext, "src/Resources/app/administration/src/module/frosh-tools-overview/page/frosh-tools-overview-list/frosh-tools-overview-list.html.twig")
+	assert.Contains(t, list, "{{ $tc('frosh-tools-overview.general.description') }}")
+
+	_, err = Generate(ext, GenerateOptions{Kind: GenerateCmsElement, Name: "Video"})
+	require.NoError(t, err)
+
+	assert.FileExists(t, filepath.Join(ext.GetPath(), "src/Resources/views/storefront/element/cms-element-frosh-tools-video.html.twig"))
+
+	main := readGeneratedFile(t, ext, "src/Resources/app/administration/src/main.js")
+	assert.Contains(t, main, "Shopware.Locale.extend('en-GB', enGB);\nimport './module/frosh-tools-overview';\nimport './module/sw-cms/elements/frosh-tools-video';\n")
+}
+
+func TestGenerateDoesNotOverwriteFiles(t *testing.T) {
+	ext := newGenerateTestPlugin(t)
+
+	_, err := Generate(ext, GenerateOptions{Kind: GenerateEventSubscriber, Name: "Product"})
+	require.NoError(t, err)
+
+	services := readGeneratedFile(t, ext, "src/Resources/config/services.xml")
+
+	_, err = Generate(ext, GenerateOptions{Kind: GenerateEventSubscriber, Name: "Product"})
+	assert.ErrorContains(t, err, "the file src/Subscriber/ProductSubscriber.php exists already")
+	assert.Equal(t, services, readGeneratedFile(t, ext, "src/Resources/config/services.xml"))
+}
+
+func TestGenerateInvalidOptions(t *testing.T) {
+	ext := newGenerateTestPlugin(t)
+
+	_, err := Generate(ext, GenerateOptions{Kind: "controller", Name: "Product"})
+	assert.ErrorContains(t, err, "unknown generator controller")
+
+	_, err = Generate(ext, GenerateOptions{Kind: GenerateCommand, Name: "export-products"})
+	assert.ErrorContains(t, err, "must be in PascalCase")
+
+	dir := t.TempDir()
+	_, err = Scaffold(dir, ScaffoldOptions{Type: ScaffoldTypeApp, Name: "FroshApp"})
+	require.NoError(t, err)
+
+	app, err := GetExtensionByFolder(filepath.Join(dir, "FroshApp"))
+	require.NoError(t, err)
+
+	_, err = Generate(app, GenerateOptions{Kind: GenerateCommand, Name: "ExportProducts"})
+	assert.ErrorContains(t, err, "generators require a plugin")
+}
+
+func TestGenerateMigrationSkipsUsedTimestamps(t *testing.T) {
+	ext := newGenerateTestPlugin(t)
+	now := time.Unix(1700000000, 0)
+
+	_, err := Generate(ext, GenerateOptions{Kind: GenerateEntity, Name: "Bundle", Now: now})
+	require.NoError(t, err)
+
+	files, err := Generate(ext, GenerateOptions{Kind: GenerateMigration, Name: "AddColorToBundle", Now: now})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"src/Migration/Migration1700000001AddColorToBundle.php"}, files)
+
+	migration := readGeneratedFile(t, ext, "src/Migration/Migration1700000001AddColorToBundle.php")
+	assert.Contains(t, migration, "return 1700000001;")
+	assert.Contains(t, migration, "public function updateDestructive(Connection $connection): void")
+
+	vc := newValidationContext(ext)
+	validateMigrations(vc)
+
+	assert.Empty(t, vc.Errors())
+	assert.Empty(t, vc.Warnings())
+}
@@ -0,0 +1,39 @@
+package extension
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// RunHooks runs the commands one after another with sh in the given folder, env is added to the environment
+func RunHooks(hooks []string, dir string, env []string) error {
+	for _, hook := range hooks {
+		hookCmd := exec.Command("sh", "-c", hook)
+		hookCmd.Stdout = os.Stdout
+		hookCmd.Stderr = os.Stderr
+		hookCmd.Dir = dir
+		hookCmd.Env = append(os.Environ(), env...)
+
+		if err := hookCmd.Run(); err != nil {
+			return fmt.Errorf("%s: %w", hook, err)
+		}
+	}
+
+	return nil
+}
+
+// ArtifactHookEnv describes the zip file for the hooks around packing and uploading it
+func ArtifactHookEnv(artifactPath, name, version string) []string {
+	if abs, err := filepath.Abs(artifactPath); err == nil {
+		artifactPath = abs
+	}
+
+	return []string{
+		fmt.Sprintf("ARTIFACT_PATH=%s", artifactPath),
+		fmt.Sprintf("ARTIFACT_NAME=%s", filepath.Base(artifactPath)),
+		fmt.Sprintf("EXTENSION_NAME=%s", name),
+		fmt.Sprintf("EXTENSION_VERSION=%s", version),
+	}
+}
@@ -0,0 +1,31 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunHooksWithArtifactEnv(t *testing.T) {
+	dir := t.TempDir()
+
+	env := ArtifactHookEnv(filepath.Join(dir, "FroshTools-1.0.0.zip"), "FroshTools", "1.0.0")
+
+	err := RunHooks([]string{`echo "$ARTIFACT_NAME $EXTENSION_NAME $EXTENSION_VERSION" > hook.txt`}, dir, env)
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(dir, "hook.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "FroshTools-1.0.0.zip FroshTools 1.0.0\n", string(content))
+	assert.Contains(t, env, "ARTIFACT_PATH="+filepath.Join(dir, "FroshTools-1.0.0.zip"))
+}
+
+func TestRunHooksStopsAtFailure(t *testing.T) {
+	dir := t.TempDir()
+
+	err := RunHooks([]string{"exit 3", "touch second"}, dir, nil)
+	assert.ErrorContains(t, err, "exit 3")
+	assert.NoFileExists(t, filepath.Join(dir, "second"))
+}
@@ -0,0 +1,74 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/shyim/go-version"
+)
+
+// MatrixTarget is one Shopware major version of the build matrix
+type MatrixTarget struct {
+	// Major is the major version like 6.6
+	Major string
+	// Version is the lowest version of the major allowed by the constraint of the extension
+	Version string
+}
+
+// Constraint pins the asset build to the version of the target
+func (t MatrixTarget) Constraint() *version.Constraints {
+	c := version.MustConstraints(version.NewConstraint(t.Version))
+
+	return &c
+}
+
+// GetShopwareMatrixTargets returns each Shopware major version allowed by the constraint, in ascending order
+func GetShopwareMatrixTargets(ctx context.Context, constraint *version.Constraints) ([]MatrixTarget, error) {
+	versions, err := GetShopwareVersions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get shopware versions: %w", err)
+	}
+
+	targets := getMatrixTargets(constraint, versions)
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no released Shopware version matches the constraint %s", constraint.String())
+	}
+
+	return targets, nil
+}
+
+func getMatrixTargets(constraint *version.Constraints, versions []string) []MatrixTarget {
+	byMajor := make(map[string][]string)
+
+	for _, v := range versions {
+		parsed, err := version.NewVersion(v)
+		if err != nil || !constraint.Check(parsed) {
+			continue
+		}
+
+		segments := parsed.Segments()
+		if len(segments) < 2 {
+			continue
+		}
+
+		major := fmt.Sprintf("%d.%d", segments[0], segments[1])
+		byMajor[major] = append(byMajor[major], v)
+	}
+
+	targets := make([]MatrixTarget, 0, len(byMajor))
+
+	for major, majorVersions := range byMajor {
+		targets = append(targets, MatrixTarget{
+			Major:   major,
+			Version: getMinMatchingVersion(constraint, majorVersions),
+		})
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return version.Must(version.NewVersion(targets[i].Major)).LessThan(version.Must(version.NewVersion(targets[j].Major)))
+	})
+
+	return targets
+}
@@ -0,0 +1,29 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/shyim/go-version"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMatrixTargets(t *testing.T) {
+	versions := []string{"6.4.20.2", "6.5.0.0-rc1", "6.5.0.0", "6.5.8.0", "6.6.0.0", "6.6.2.0", "6.6.10.0", "6.7.0.0"}
+
+	constraint := version.MustConstraints(version.NewConstraint("~6.5.1 || >=6.6.1 <6.7"))
+
+	assert.Equal(t, []MatrixTarget{
+		{Major: "6.5", Version: "6.5.8.0"},
+		{Major: "6.6", Version: "6.6.2.0"},
+	}, getMatrixTargets(&constraint, versions))
+
+	constraint = version.MustConstraints(version.NewConstraint("~6.6.0"))
+	targets := getMatrixTargets(&constraint, versions)
+
+	assert.Equal(t, []MatrixTarget{{Major: "6.6", Version: "6.6.0.0"}}, targets)
+	assert.True(t, targets[0].Constraint().Check(version.Must(version.NewVersion("6.6.0.0"))))
+	assert.False(t, targets[0].Constraint().Check(version.Must(version.NewVersion("6.6.2.0"))))
+
+	constraint = version.MustConstraints(version.NewConstraint("^7.0"))
+	assert.Empty(t, getMatrixTargets(&constraint, versions))
+}
@@ -0,0 +1,166 @@
+package extension
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	migrationStepRegExp      = regexp.MustCompile(`\bextends\s+\\?(?:Shopware\\Core\\Framework\\Migration\\)?MigrationStep\b`)
+	migrationTimestampRegExp = regexp.MustCompile(`function\s+getCreationTimestamp\s*\(\s*\)\s*:\s*int\s*\{\s*return\s+(\d+)\s*;`)
+	migrationUpdateRegExp    = regexp.MustCompile(`function\s+update\s*\(`)
+
+	// migrationDestructiveRegExp matches statements which remove data, dropping indexes or foreign keys is fine in update()
+	migrationDestructiveRegExp = regexp.MustCompile("(?i)\\bDROP\\s+(?:TABLE|COLUMN)\\b|\\bDROP\\s+`|\\bTRUNCATE\\b|->drop(?:Table|Column)IfExists\\s*\\(")
+)
+
+type phpMigration struct {
+	// relPath is relative to the root dir of the extension
+	relPath   string
+	timestamp int64
+	// timestampLine is the line of the timestamp returned by getCreationTimestamp()
+	timestampLine int
+	content       string
+}
+
+// findMigrations returns the classes extending MigrationStep with their creation timestamp
+func findMigrations(ext Extension) []phpMigration {
+	rootDir := ext.GetRootDir()
+
+	var migrations []phpMigration
+
+	for _, sourceDir := range ext.GetSourceDirs() {
+		_ = filepath.WalkDir(sourceDir, func(file string, d os.DirEntry, err error) error {
+			if err != nil {
+				return nil //nolint:nilerr
+			}
+
+			if d.IsDir() {
+				if d.Name() == "node_modules" || d.Name() == "vendor" {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			if filepath.Ext(file) != ".php" {
+				return nil
+			}
+
+			content, err := os.ReadFile(file)
+			if err != nil || !migrationStepRegExp.Match(content) {
+				return nil //nolint:nilerr
+			}
+
+			migration := phpMigration{relPath: strings.TrimPrefix(file, rootDir+"/"), content: string(content)}
+
+			if match := migrationTimestampRegExp.FindSubmatchIndex(content); match != nil {
+				migration.timestamp, _ = strconv.ParseInt(string(content[match[2]:match[3]]), 10, 64)
+				migration.timestampLine = bytes.Count(content[:match[2]], []byte("\n")) + 1
+			}
+
+			migrations = append(migrations, migration)
+
+			return nil
+		})
+	}
+
+	return migrations
+}
+
+// validateMigrations reports migrations sharing a creation timestamp, as the order Shopware executes them in is undefined,
+// and update() methods removing data, which belongs into updateDestructive() for blue-green deployments
+func validateMigrations(vc *ValidationContext) {
+	byTimestamp := map[int64][]fileLocation{}
+
+	for _, migration := range findMigrations(vc.Extension) {
+		if migration.timestamp != 0 {
+			byTimestamp[migration.timestamp] = append(byTimestamp[migration.timestamp], fileLocation{migration.relPath, migration.timestampLine})
+		}
+
+		if line, statement := findDestructiveUpdate(migration.content); line > 0 {
+			vc.AddWarningAt("migration.destructive_update", fileLocation{migration.relPath, line}, fmt.Sprintf("update() contains the destructive %s, move it into updateDestructive() which runs only with migration:migrate-destructive", statement))
+		}
+	}
+
+	timestamps := make([]int64, 0, len(byTimestamp))
+	for timestamp := range byTimestamp {
+		timestamps = append(timestamps, timestamp)
+	}
+
+	slices.Sort(timestamps)
+
+	for _, timestamp := range timestamps {
+		locations := byTimestamp[timestamp]
+
+		slices.SortFunc(locations, func(a, b fileLocation) int {
+			return strings.Compare(a.file, b.file)
+		})
+
+		for _, location := range locations[1:] {
+			vc.AddWarningAt("migration.duplicate_timestamp", location, fmt.Sprintf("the creation timestamp %d is also used by %s, the order in which both migrations run is undefined", timestamp, locations[0].file))
+		}
+	}
+}
+
+// findDestructiveUpdate returns the line and statement of the first destructive statement in the update() method
+func findDestructiveUpdate(content string) (int, string) {
+	match := migrationUpdateRegExp.FindStringIndex(content)
+	if match == nil {
+		return 0, ""
+	}
+
+	start := strings.Index(content[match[1]:], "{")
+	if start < 0 {
+		return 0, ""
+	}
+
+	start += match[1]
+	end := len(content)
+	depth := 0
+
+	for i := start; i < len(content); i++ {
+		switch content[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		}
+
+		if depth == 0 {
+			end = i
+			break
+		}
+	}
+
+	destructive := migrationDestructiveRegExp.FindStringIndex(content[start:end])
+	if destructive == nil {
+		return 0, ""
+	}
+
+	offset := start + destructive[0]
+	statement := strings.Trim(content[offset:start+destructive[1]], "`(->")
+
+	return strings.Count(content[:offset], "\n") + 1, strings.Join(strings.Fields(statement), " ")
+}
+
+// nextMigrationTimestamp returns the timestamp or the next one not used by a migration of the extension
+func nextMigrationTimestamp(ext Extension, timestamp int64) int64 {
+	used := map[int64]bool{}
+
+	for _, migration := range findMigrations(ext) {
+		used[migration.timestamp] = true
+	}
+
+	for used[timestamp] {
+		timestamp++
+	}
+
+	return timestamp
+}
@@ -0,0 +1,75 @@
+package extension
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestMigration(t *testing.T, dir, class string, timestamp int, update, updateDestructive string) {
+	t.Helper()
+
+	_ = os.WriteFile(path.Join(dir, class+".php"), []byte(fmt.Sprintf(`<?php declare(strict_types=1);
+
+namespace MyPlugin\Migration;
+
+use Doctrine\DBAL\Connection;
+use Shopware\Core\Framework\Migration\MigrationStep;
+
+class %s extends MigrationStep
+{
+    public function getCreationTimestamp(): int
+    {
+        return %d;
+    }
+
+    public function update(Connection $connection): void
+    {
+%s
+    }
+
+    public function updateDestructive(Connection $connection): void
+    {
+%s
+    }
+}
+`, class, timestamp, update, updateDestructive)), os.ModePerm)
+}
+
+func TestValidateMigrations(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	ext := &PlatformPlugin{
+		path:   tmpDir,
+		config: &Config{},
+	}
+	ext.Composer.Autoload.Psr4 = map[string]string{"MyPlugin\\": "src/"}
+
+	migrationDir := path.Join(tmpDir, "src", "Migration")
+	_ = os.MkdirAll(migrationDir, os.ModePerm)
+
+	writeTestMigration(t, migrationDir, "Migration1700000000CreateTable", 1700000000, `        $connection->executeStatement('CREATE TABLE my_plugin (id BINARY(16) NOT NULL)');`, "")
+	writeTestMigration(t, migrationDir, "Migration1700000000AddColumn", 1700000000, `        if ($this->columnExists($connection, 'my_plugin', 'name')) {
+            return;
+        }
+
+        $connection->executeStatement('ALTER TABLE my_plugin ADD COLUMN name VARCHAR(255) NULL');`, "")
+	writeTestMigration(t, migrationDir, "Migration1700000100DropColumn", 1700000100, "        $connection->executeStatement('ALTER TABLE `my_plugin` DROP INDEX `idx.name`, DROP `name`');", "")
+	writeTestMigration(t, migrationDir, "Migration1700000200DropTable", 1700000200, "", `        $this->dropTableIfExists($connection, 'my_plugin');`)
+	writeTestMigration(t, migrationDir, "Migration1700000300Helper", 1700000300, `        $this->dropColumnIfExists($connection, 'my_plugin', 'name');`, "")
+
+	vc := newValidationContext(ext)
+
+	validateMigrations(vc)
+
+	assert.Empty(t, vc.Errors())
+
+	assert.ElementsMatch(t, []ValidationMessage{
+		{Identifier: "migration.duplicate_timestamp", Message: "Migration/Migration1700000000CreateTable.php:12: the creation timestamp 1700000000 is also used by Migration/Migration1700000000AddColumn.php, the order in which both migrations run is undefined", File: "Migration/Migration1700000000CreateTable.php", Line: 12},
+		{Identifier: "migration.destructive_update", Message: "Migration/Migration1700000100DropColumn.php:17: update() contains the destructive DROP, move it into updateDestructive() which runs only with migration:migrate-destructive", File: "Migration/Migration1700000100DropColumn.php", Line: 17},
+		{Identifier: "migration.destructive_update", Message: "Migration/Migration1700000300Helper.php:17: update() contains the destructive dropColumnIfExists, move it into updateDestructive() which runs only with migration:migrate-destructive", File: "Migration/Migration1700000300Helper.php", Line: 17},
+	}, vc.Warnings())
+}
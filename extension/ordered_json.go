@@ -0,0 +1,189 @@
+package extension
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// orderedObject is a JSON object which keeps the order of its keys, so fixed files keep their structure
+type orderedObject struct {
+	keys   []string
+	values map[string]any
+}
+
+func (o *orderedObject) set(key string, value any) {
+	if _, exists := o.values[key]; !exists {
+		o.keys = append(o.keys, key)
+	}
+
+	o.values[key] = value
+}
+
+func parseOrderedJSON(content []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	return readOrderedJSONValue(decoder)
+}
+
+func readOrderedJSONValue(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+
+	switch delim {
+	case '{':
+		object := &orderedObject{values: map[string]any{}}
+
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			key, ok := keyToken.(string)
+			if !ok {
+				return nil, fmt.Errorf("unexpected object key %v", keyToken)
+			}
+
+			value, err := readOrderedJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+
+			object.set(key, value)
+		}
+
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+
+		return object, nil
+	case '[':
+		list := []any{}
+
+		for decoder.More() {
+			value, err := readOrderedJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+
+			list = append(list, value)
+		}
+
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+
+		return list, nil
+	}
+
+	return nil, fmt.Errorf("unexpected delimiter %s", delim)
+}
+
+func writeOrderedJSON(buf *bytes.Buffer, value any, indent string, level int) {
+	switch v := value.(type) {
+	case *orderedObject:
+		if len(v.keys) == 0 {
+			buf.WriteString("{}")
+			return
+		}
+
+		buf.WriteString("{\n")
+
+		for i, key := range v.keys {
+			buf.WriteString(strings.Repeat(indent, level+1))
+			writeJSONString(buf, key)
+			buf.WriteString(": ")
+			writeOrderedJSON(buf, v.values[key], indent, level+1)
+
+			if i < len(v.keys)-1 {
+				buf.WriteByte(',')
+			}
+
+			buf.WriteByte('\n')
+		}
+
+		buf.WriteString(strings.Repeat(indent, level) + "}")
+	case []any:
+		if len(v) == 0 {
+			buf.WriteString("[]")
+			return
+		}
+
+		buf.WriteString("[\n")
+
+		for i, item := range v {
+			buf.WriteString(strings.Repeat(indent, level+1))
+			writeOrderedJSON(buf, item, indent, level+1)
+
+			if i < len(v)-1 {
+				buf.WriteByte(',')
+			}
+
+			buf.WriteByte('\n')
+		}
+
+		buf.WriteString(strings.Repeat(indent, level) + "]")
+	case string:
+		writeJSONString(buf, v)
+	case json.Number:
+		buf.WriteString(v.String())
+	default:
+		encoded, _ := json.Marshal(v)
+		buf.Write(encoded)
+	}
+}
+
+func writeJSONString(buf *bytes.Buffer, value string) {
+	var encoded bytes.Buffer
+
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value)
+
+	buf.Write(bytes.TrimSuffix(encoded.Bytes(), []byte("\n")))
+}
+
+// detectJSONIndent returns the indentation of the first indented line, defaulting to four spaces
+func detectJSONIndent(content []byte) string {
+	for _, line := range strings.Split(string(content), "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+
+		if trimmed != "" && len(trimmed) != len(line) {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+
+	return "    "
+}
+
+// sortKeys sorts the keys of the object and all nested objects alphabetically
+func (o *orderedObject) sortKeys() {
+	slices.Sort(o.keys)
+
+	for _, value := range o.values {
+		if child, ok := value.(*orderedObject); ok {
+			child.sortKeys()
+		}
+	}
+}
+
+// encodeOrderedJSON formats the value with the given indentation and a trailing newline
+func encodeOrderedJSON(value any, indent string) []byte {
+	var buf bytes.Buffer
+
+	writeOrderedJSON(&buf, value, indent, 0)
+	buf.WriteByte('\n')
+
+	return buf.Bytes()
+}
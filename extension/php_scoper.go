@@ -0,0 +1,316 @@
+package extension
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/shopware/shopware-cli/internal/system"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+const phpScoperVersion = "0.18.17"
+
+var (
+	phpScoperPharURL = fmt.Sprintf("https://github.com/humbug/php-scoper/releases/download/%s/php-scoper.phar", phpScoperVersion)
+	// phpScoperPharSHA256 is the checksum of the phar of the pinned release, update it together with the version.
+	// It has to be taken from the downloaded release asset, the phar is not run without a pinned checksum.
+	phpScoperPharSHA256 = ""
+)
+
+// phpScoperKeptNamespaces are provided by Shopware at runtime, references to them must not be prefixed
+var phpScoperKeptNamespaces = []string{"Shopware", "Symfony", "Doctrine", "Psr", "Composer", "Twig"}
+
+// phpScoperSkippedPackages define global functions which cannot be prefixed
+var phpScoperSkippedPackages = []string{"symfony/polyfill-"}
+
+// ScopeComposerDependencies prefixes the namespaces of the bundled composer packages with PHP-Scoper, so they cannot conflict
+// with the same packages in other extensions. The source folders of the extension are scoped too, so their references point
+// to the prefixed classes. Afterwards the composer autoloader is dumped again.
+func ScopeComposerDependencies(ctx context.Context, extDir, name string, cfg ConfigBuildZipComposerScoper) error {
+	extDir = filepath.Clean(extDir)
+
+	dependencies, err := findComposerDependencies(extDir)
+	if err != nil {
+		return fmt.Errorf("find composer dependencies: %w", err)
+	}
+
+	packages := phpScoperPackages(dependencies, cfg)
+
+	if len(packages) == 0 {
+		logging.FromContext(ctx).Infof("No composer packages to scope")
+		return nil
+	}
+
+	namespaces, sourceDirs, err := phpScoperExtensionAutoload(extDir)
+	if err != nil {
+		return err
+	}
+
+	prefix := cfg.Prefix
+	if prefix == "" {
+		prefix = name + `\Vendor`
+	}
+
+	scopedPaths := slices.Clone(sourceDirs)
+	for _, pkg := range packages {
+		scopedPaths = append(scopedPaths, filepath.Join("vendor", pkg))
+	}
+
+	// The installed.json contains the autoload namespaces of the packages, PHP-Scoper prefixes them for the new autoloader
+	scopedPaths = append(scopedPaths, filepath.Join("vendor", "composer", "installed.json"))
+
+	phar, err := downloadPhpScoperPhar(ctx, filepath.Join(system.GetShopwareCliCacheDir(), "tools"))
+	if err != nil {
+		return err
+	}
+
+	configFile, err := os.CreateTemp("", "scoper-*.inc.php")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.Remove(configFile.Name()) }()
+
+	excludeNamespaces := append(slices.Clone(phpScoperKeptNamespaces), namespaces...)
+	excludeNamespaces = append(excludeNamespaces, cfg.ExcludeNamespaces...)
+
+	if _, err := configFile.WriteString(generatePhpScoperConfig(prefix, sourceDirs, packages, excludeNamespaces)); err != nil {
+		_ = configFile.Close()
+		return err
+	}
+
+	if err := configFile.Close(); err != nil {
+		return err
+	}
+
+	outputDir, err := os.MkdirTemp(filepath.Dir(extDir), "php-scoper")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.RemoveAll(outputDir) }()
+
+	logging.FromContext(ctx).Infof("Prefixing %d composer packages with %s", len(packages), prefix)
+
+	scoper := exec.CommandContext(ctx, "php", phar, "add-prefix", "--config", configFile.Name(), "--output-dir", outputDir, "--force", "--no-interaction")
+	scoper.Dir = extDir
+	scoper.Stdout = os.Stdout
+	scoper.Stderr = os.Stderr
+
+	if err := scoper.Run(); err != nil {
+		return fmt.Errorf("php-scoper: %w", err)
+	}
+
+	for _, scopedPath := range scopedPaths {
+		if err := os.RemoveAll(filepath.Join(extDir, scopedPath)); err != nil {
+			return err
+		}
+
+		if err := os.Rename(filepath.Join(outputDir, scopedPath), filepath.Join(extDir, scopedPath)); err != nil {
+			return fmt.Errorf("move scoped %s: %w", scopedPath, err)
+		}
+	}
+
+	dumpAutoload := exec.CommandContext(ctx, "composer", "dump-autoload", "-d", extDir, "--no-dev", "-n", "--classmap-authoritative")
+	dumpAutoload.Stdout = os.Stdout
+	dumpAutoload.Stderr = os.Stderr
+
+	if err := dumpAutoload.Run(); err != nil {
+		return fmt.Errorf("composer dump-autoload: %w", err)
+	}
+
+	return nil
+}
+
+// phpScoperPackages returns the bundled packages matching the include and exclude lists
+func phpScoperPackages(dependencies []BundledDependency, cfg ConfigBuildZipComposerScoper) []string {
+	var packages []string
+
+	for _, dependency := range dependencies {
+		if len(cfg.Include) > 0 && !slices.Contains(cfg.Include, dependency.Name) {
+			continue
+		}
+
+		if slices.Contains(cfg.Exclude, dependency.Name) {
+			continue
+		}
+
+		if slices.ContainsFunc(phpScoperSkippedPackages, func(prefix string) bool { return strings.HasPrefix(dependency.Name, prefix) }) {
+			continue
+		}
+
+		packages = append(packages, dependency.Name)
+	}
+
+	return packages
+}
+
+// phpScoperExtensionAutoload returns the psr-4 namespaces and folders of the extension
+func phpScoperExtensionAutoload(extDir string) ([]string, []string, error) {
+	content, err := os.ReadFile(filepath.Join(extDir, "composer.json"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("scoping requires a composer.json: %w", err)
+	}
+
+	var composer struct {
+		Autoload struct {
+			Psr4 map[string]string `json:"psr-4"`
+		} `json:"autoload"`
+	}
+
+	if err := json.Unmarshal(content, &composer); err != nil {
+		return nil, nil, fmt.Errorf("cannot parse composer.json: %w", err)
+	}
+
+	var namespaces, sourceDirs []string
+
+	for namespace, dir := range composer.Autoload.Psr4 {
+		dir = filepath.Clean(dir)
+
+		if dir == "." || strings.HasPrefix(dir, "..") {
+			return nil, nil, fmt.Errorf("scoping requires the autoload path of %s to be a folder of the extension", namespace)
+		}
+
+		namespaces = append(namespaces, strings.TrimSuffix(namespace, `\`))
+		sourceDirs = append(sourceDirs, dir)
+	}
+
+	slices.Sort(namespaces)
+	slices.Sort(sourceDirs)
+
+	return namespaces, slices.Compact(sourceDirs), nil
+}
+
+// generatePhpScoperConfig builds the scoper.inc.php with a finder for the source folders and one for the scoped packages
+func generatePhpScoperConfig(prefix string, sourceDirs, packages, excludeNamespaces []string) string {
+	vendorDirs := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		vendorDirs = append(vendorDirs, "vendor/"+pkg)
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString("<?php declare(strict_types=1);\n\n")
+	builder.WriteString("use Isolated\\Symfony\\Component\\Finder\\Finder;\n\n")
+	builder.WriteString("return [\n")
+	builder.WriteString(fmt.Sprintf("    'prefix' => %s,\n", phpString(prefix)))
+	builder.WriteString("    'finders' => [\n")
+	builder.WriteString(fmt.Sprintf("        Finder::create()->files()->in(%s),\n", phpArray(sourceDirs)))
+	builder.WriteString(fmt.Sprintf("        Finder::create()->files()->ignoreVCS(true)->in(%s),\n", phpArray(vendorDirs)))
+	builder.WriteString("        Finder::create()->files()->in('vendor/composer')->depth(0)->name('installed.json'),\n")
+	builder.WriteString("    ],\n")
+	builder.WriteString(fmt.Sprintf("    'exclude-namespaces' => %s,\n", phpArray(excludeNamespaces)))
+	builder.WriteString("];\n")
+
+	return builder.String()
+}
+
+func phpArray(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, phpString(value))
+	}
+
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+func phpString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+func downloadPhpScoperPhar(ctx context.Context, dir string) (string, error) {
+	phar := filepath.Join(dir, fmt.Sprintf("php-scoper-%s.phar", phpScoperVersion))
+
+	if phpScoperPharSHA256 == "" {
+		return "", fmt.Errorf("no checksum is pinned for PHP-Scoper %s", phpScoperVersion)
+	}
+
+	// The cached phar is verified before every run too, so a modified file is never executed
+	if _, err := os.Stat(phar); err == nil {
+		if err := verifyPhpScoperPhar(phar); err == nil {
+			return phar, nil
+		}
+
+		logging.FromContext(ctx).Warnf("The cached PHP-Scoper phar does not match the pinned checksum, downloading it again")
+	}
+
+	logging.FromContext(ctx).Infof("Downloading PHP-Scoper %s", phpScoperVersion)
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, phpScoperPharURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		return "", fmt.Errorf("cannot download PHP-Scoper: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("cannot download PHP-Scoper from %s: got status code %d", phpScoperPharURL, resp.StatusCode)
+	}
+
+	// Download into a temporary file first, so an aborted download is never used
+	tmp, err := os.CreateTemp(dir, "php-scoper-*.download")
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		_ = tmp.Close()
+		return "", fmt.Errorf("cannot download PHP-Scoper: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	if err := verifyPhpScoperPhar(tmp.Name()); err != nil {
+		return "", fmt.Errorf("cannot download PHP-Scoper: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), phar); err != nil {
+		return "", err
+	}
+
+	return phar, nil
+}
+
+func verifyPhpScoperPhar(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != phpScoperPharSHA256 {
+		return fmt.Errorf("the checksum %s of the PHP-Scoper phar does not match the pinned checksum %s", sum, phpScoperPharSHA256)
+	}
+
+	return nil
+}
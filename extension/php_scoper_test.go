@@ -0,0 +1,95 @@
+package extension
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhpScoperPackages(t *testing.T) {
+	dependencies := []BundledDependency{
+		{Name: "league/csv"},
+		{Name: "symfony/polyfill-php83"},
+		{Name: "guzzlehttp/guzzle"},
+		{Name: "spatie/pdf"},
+	}
+
+	assert.Equal(t, []string{"league/csv", "guzzlehttp/guzzle", "spatie/pdf"}, phpScoperPackages(dependencies, ConfigBuildZipComposerScoper{}))
+	assert.Equal(t, []string{"league/csv", "spatie/pdf"}, phpScoperPackages(dependencies, ConfigBuildZipComposerScoper{Exclude: []string{"guzzlehttp/guzzle"}}))
+	assert.Equal(t, []string{"league/csv"}, phpScoperPackages(dependencies, ConfigBuildZipComposerScoper{Include: []string{"league/csv", "symfony/polyfill-php83"}}))
+}
+
+func TestPhpScoperExtensionAutoload(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "composer.json"), []byte(`{"autoload": {"psr-4": {"Frosh\\Tools\\": "src/", "Frosh\\Tools\\Migration\\": "src/Migration"}}}`), 0o644))
+
+	namespaces, sourceDirs, err := phpScoperExtensionAutoload(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{`Frosh\Tools`, `Frosh\Tools\Migration`}, namespaces)
+	assert.Equal(t, []string{"src", "src/Migration"}, sourceDirs)
+}
+
+func TestPhpScoperExtensionAutoloadRejectsRoot(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "composer.json"), []byte(`{"autoload": {"psr-4": {"Frosh\\Tools\\": ""}}}`), 0o644))
+
+	_, _, err := phpScoperExtensionAutoload(dir)
+	assert.ErrorContains(t, err, "to be a folder of the extension")
+}
+
+func TestGeneratePhpScoperConfig(t *testing.T) {
+	config := generatePhpScoperConfig(`FroshTools\Vendor`, []string{"src"}, []string{"league/csv"}, []string{"Shopware", `Frosh\Tools`})
+
+	assert.Contains(t, config, `'prefix' => 'FroshTools\\Vendor',`)
+	assert.Contains(t, config, `Finder::create()->files()->in(['src']),`)
+	assert.Contains(t, config, `Finder::create()->files()->ignoreVCS(true)->in(['vendor/league/csv']),`)
+	assert.Contains(t, config, `'exclude-namespaces' => ['Shopware', 'Frosh\\Tools'],`)
+}
+
+func TestDownloadPhpScoperPharVerifiesChecksum(t *testing.T) {
+	content := "phar content"
+	sum := sha256.Sum256([]byte(content))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(content))
+	}))
+	t.Cleanup(server.Close)
+
+	originalURL, originalSum := phpScoperPharURL, phpScoperPharSHA256
+	t.Cleanup(func() {
+		phpScoperPharURL, phpScoperPharSHA256 = originalURL, originalSum
+	})
+
+	phpScoperPharURL = server.URL
+	phpScoperPharSHA256 = hex.EncodeToString(sum[:])
+
+	dir := t.TempDir()
+
+	phar, err := downloadPhpScoperPhar(t.Context(), dir)
+	require.NoError(t, err)
+
+	// A modified cached phar is downloaded again
+	require.NoError(t, os.WriteFile(phar, []byte("modified"), 0o644))
+
+	phar, err = downloadPhpScoperPhar(t.Context(), dir)
+	require.NoError(t, err)
+
+	downloaded, err := os.ReadFile(phar)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(downloaded))
+
+	content = "tampered"
+
+	_, err = downloadPhpScoperPhar(t.Context(), t.TempDir())
+	assert.ErrorContains(t, err, "does not match the pinned checksum")
+}
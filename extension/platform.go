@@ -5,15 +5,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
 	"os"
 	"path"
+	"slices"
 	"strings"
 
 	"github.com/shyim/go-version"
 
 	"github.com/shopware/shopware-cli/internal/phplint"
-	"github.com/shopware/shopware-cli/logging"
+	"github.com/shopware/shopware-cli/internal/shopwareversion"
 )
 
 var ErrPlatformInvalidType = errors.New("invalid composer type")
@@ -273,75 +273,126 @@ func (p PlatformPlugin) Validate(c context.Context, ctx *ValidationContext) {
 		ctx.AddError("metadata.autoload", "At least one of the properties psr-0 or psr-4 are required in the composer.json")
 	}
 
+	for namespace := range p.Composer.Autoload.Psr4 {
+		if namespace != "" && !strings.HasSuffix(namespace, "\\") {
+			ctx.AddError("metadata.autoload", fmt.Sprintf("The psr-4 namespace %s must end with a namespace separator", namespace))
+		}
+	}
+
 	validateExtensionIcon(ctx)
 
 	validateTheme(ctx)
-	validatePHPFiles(c, ctx)
+	validatePHPFiles(c, ctx, p.Composer.Require["php"])
 }
 
-func validatePHPFiles(c context.Context, ctx *ValidationContext) {
-	constraint, err := ctx.Extension.GetShopwareVersionConstraint()
-	if err != nil {
-		ctx.AddError("php.linter", fmt.Sprintf("Could not parse shopware version constraint: %s", err.Error()))
-		return
-	}
-
-	phpVersion, err := GetPhpVersion(c, constraint)
+func validatePHPFiles(c context.Context, ctx *ValidationContext, phpConstraint string) {
+	phpVersions, err := getPHPLintVersions(c, ctx, phpConstraint)
 	if err != nil {
-		ctx.AddWarning("php.linter", fmt.Sprintf("Could not find min php version for plugin: %s", err.Error()))
+		ctx.AddWarning("php.linter", fmt.Sprintf("Could not find php versions to lint against: %s", err.Error()))
 		return
 	}
 
-	if phpVersion == "7.2" {
-		phpVersion = "7.3"
-		logging.FromContext(c).Infof("PHP 7.2 is not supported for PHP linting, using 7.3 now")
+	for _, val := range ctx.Extension.GetSourceDirs() {
+		// Files which cannot be parsed by the newest PHP version have a syntax error, otherwise they use too new syntax for the older versions
+		failedFiles := map[string]bool{}
+
+		for i := len(phpVersions) - 1; i >= 0; i-- {
+			phpVersion := phpVersions[i]
+
+			phpErrors, err := phplint.LintFolder(c, phpVersion, val)
+			if err != nil {
+				ctx.AddWarning("php.linter", fmt.Sprintf("Could not lint php files with PHP %s: %s", phpVersion, err.Error()))
+				continue
+			}
+
+			for _, error := range phpErrors {
+				if failedFiles[error.File] {
+					continue
+				}
+
+				failedFiles[error.File] = true
+
+				if i == len(phpVersions)-1 {
+					ctx.AddError("php.linter", fmt.Sprintf("%s: %s", error.File, error.Message))
+				} else {
+					ctx.AddError("php.linter", fmt.Sprintf("%s: uses syntax which is not supported by PHP %s: %s", error.File, phpVersion, error.Message))
+				}
+			}
+		}
 	}
+}
 
-	for _, val := range ctx.Extension.GetSourceDirs() {
-		phpErrors, err := phplint.LintFolder(c, phpVersion, val)
+// getPHPLintVersions returns the PHP versions to lint against in ascending order.
+// These are the configured versions and the minimum PHP version of the composer.json,
+// falling back to the minimum PHP version of the supported Shopware versions.
+// Versions without a linter are replaced with the next newer one and reported as a warning,
+// versions newer than every linter are skipped with a warning.
+func getPHPLintVersions(c context.Context, ctx *ValidationContext, phpConstraint string) ([]string, error) {
+	ext := ctx.Extension
 
-		if err != nil {
-			ctx.AddWarning("php.linter", fmt.Sprintf("Could not lint php files: %s", err.Error()))
-			continue
-		}
+	versions := []string{}
 
-		for _, error := range phpErrors {
-			ctx.AddError("php.linter", fmt.Sprintf("%s: %s", error.File, error.Message))
-		}
+	if cfg := ext.GetExtensionConfig(); cfg != nil {
+		versions = append(versions, cfg.Validation.PHPLint.Versions...)
 	}
-}
 
-func GetPhpVersion(ctx context.Context, constraint *version.Constraints) (string, error) {
-	r, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://raw.githubusercontent.com/FriendsOfShopware/shopware-static-data/main/data/php-version.json", http.NoBody)
+	if phpConstraint != "" {
+		minVersion, err := phplint.MinimumVersion(phpConstraint)
+		if err != nil {
+			return nil, err
+		}
 
-	resp, err := http.DefaultClient.Do(r)
-	if err != nil {
-		return "", err
+		versions = append(versions, minVersion)
 	}
 
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			logging.FromContext(ctx).Errorf("GetPhpVersion: %v", err)
+	if len(versions) == 0 {
+		constraint, err := ext.GetShopwareVersionConstraint()
+		if err != nil {
+			return nil, fmt.Errorf("could not parse shopware version constraint: %w", err)
 		}
-	}()
 
-	var shopwareToPHPVersion map[string]string
+		phpVersion, err := GetPhpVersion(c, constraint)
+		if err != nil {
+			return nil, fmt.Errorf("could not find min php version for plugin: %w", err)
+		}
 
-	err = json.NewDecoder(resp.Body).Decode(&shopwareToPHPVersion)
-	if err != nil {
-		return "", err
+		versions = append(versions, phpVersion)
 	}
 
-	for shopwareVersion, phpVersion := range shopwareToPHPVersion {
-		shopwareVersionConstraint, err := version.NewVersion(shopwareVersion)
+	resolved := make([]string, 0, len(versions))
+
+	for _, phpVersion := range versions {
+		supported, err := phplint.ResolveVersion(phpVersion)
 		if err != nil {
+			ctx.AddWarning("php.linter", fmt.Sprintf("PHP %s cannot be linted: %s", phpVersion, err.Error()))
 			continue
 		}
 
-		if constraint.Check(shopwareVersionConstraint) {
-			return phpVersion, nil
+		if supported != phpVersion {
+			ctx.AddWarning("php.linter", fmt.Sprintf("PHP %s is not available for linting, the PHP files are linted with PHP %s. Syntax which is not supported by PHP %s is not detected", phpVersion, supported, phpVersion))
+		}
+
+		if !slices.Contains(resolved, supported) {
+			resolved = append(resolved, supported)
 		}
 	}
 
-	return "", errors.New("could not find php version for shopware version")
+	if len(resolved) == 0 {
+		return nil, fmt.Errorf("none of the PHP versions %v can be linted", versions)
+	}
+
+	slices.SortFunc(resolved, func(a, b string) int {
+		return slices.Index(phplint.SupportedVersions, a) - slices.Index(phplint.SupportedVersions, b)
+	})
+
+	return resolved, nil
+}
+
+func GetPhpVersion(ctx context.Context, constraint *version.Constraints) (string, error) {
+	metadata, err := shopwareversion.Load(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return metadata.PHPVersion(constraint)
 }
@@ -148,3 +148,44 @@ func TestPluginGermanDescriptionMissingOnlyEnglishMarket(t *testing.T) {
 
 	assert.Len(t, ctx.errors, 0)
 }
+
+func TestPHPLintVersionsWarnAboutVersionsWithoutLinter(t *testing.T) {
+	plugin := getTestPlugin(t.TempDir())
+	plugin.config.Validation.PHPLint.Versions = []string{"8.2"}
+
+	ctx := newValidationContext(plugin)
+
+	versions, err := getPHPLintVersions(t.Context(), ctx, "^8.0")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"8.1", "8.2"}, versions)
+
+	assert.Len(t, ctx.Warnings(), 1)
+	assert.Equal(t, "php.linter", ctx.Warnings()[0].Identifier)
+	assert.Contains(t, ctx.Warnings()[0].Message, "PHP 8.0 is not available for linting, the PHP files are linted with PHP 8.1")
+}
+
+func TestPHPLintVersionsSkipUnresolvableVersions(t *testing.T) {
+	plugin := getTestPlugin(t.TempDir())
+	plugin.config.Validation.PHPLint.Versions = []string{"8.2", "99.0"}
+
+	ctx := newValidationContext(plugin)
+
+	versions, err := getPHPLintVersions(t.Context(), ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"8.2"}, versions)
+
+	assert.Len(t, ctx.Warnings(), 1)
+	assert.Equal(t, "php.linter", ctx.Warnings()[0].Identifier)
+	assert.Contains(t, ctx.Warnings()[0].Message, "PHP 99.0 cannot be linted")
+}
+
+func TestPHPLintVersionsOfSupportedConstraint(t *testing.T) {
+	plugin := getTestPlugin(t.TempDir())
+
+	ctx := newValidationContext(plugin)
+
+	versions, err := getPHPLintVersions(t.Context(), ctx, "^7.4 || ^8.0")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"7.4"}, versions)
+	assert.False(t, ctx.HasWarnings())
+}
@@ -0,0 +1,156 @@
+package extension
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var spdxIdInvalidChars = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+type SPDXDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      SPDXCreationInfo   `json:"creationInfo"`
+	Packages          []SPDXPackage      `json:"packages"`
+	Relationships     []SPDXRelationship `json:"relationships"`
+}
+
+type SPDXCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type SPDXPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	ExternalRefs     []SPDXExternalRef `json:"externalRefs,omitempty"`
+}
+
+type SPDXExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type SPDXRelationship struct {
+	SpdxElementId      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSpdxElement string `json:"relatedSpdxElement"`
+}
+
+// GenerateSPDX creates a SPDX 2.3 software bill of materials of the extension and its bundled dependencies
+func GenerateSPDX(ext Extension, dependencies []BundledDependency, created time.Time) (*SPDXDocument, error) {
+	name, err := ext.GetName()
+	if err != nil {
+		return nil, err
+	}
+
+	extVersion := ""
+	if v, err := ext.GetVersion(); err == nil && v != nil {
+		extVersion = v.String()
+	}
+
+	license, _ := ext.GetLicense()
+
+	rootId := "SPDXRef-Package-" + spdxIdInvalidChars.ReplaceAllString(name, "-")
+
+	document := &SPDXDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              name,
+		DocumentNamespace: fmt.Sprintf("https://spdx.shopware.com/%s-%s", name, uuid.NewString()),
+		CreationInfo: SPDXCreationInfo{
+			Created:  created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: shopware-cli"},
+		},
+		Packages: []SPDXPackage{
+			{
+				SPDXID:           rootId,
+				Name:             name,
+				VersionInfo:      extVersion,
+				DownloadLocation: "NOASSERTION",
+				LicenseConcluded: "NOASSERTION",
+				LicenseDeclared:  spdxLicenseExpression([]string{license}),
+				CopyrightText:    "NOASSERTION",
+			},
+		},
+		Relationships: []SPDXRelationship{
+			{SpdxElementId: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSpdxElement: rootId},
+		},
+	}
+
+	for i, dependency := range dependencies {
+		id := fmt.Sprintf("SPDXRef-Package-%s-%s-%d", dependency.Type, spdxIdInvalidChars.ReplaceAllString(dependency.Name, "-"), i)
+
+		document.Packages = append(document.Packages, SPDXPackage{
+			SPDXID:           id,
+			Name:             dependency.Name,
+			VersionInfo:      dependency.Version,
+			DownloadLocation: "NOASSERTION",
+			LicenseConcluded: "NOASSERTION",
+			LicenseDeclared:  spdxLicenseExpression(dependency.Licenses),
+			CopyrightText:    "NOASSERTION",
+			ExternalRefs: []SPDXExternalRef{
+				{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: dependencyPurl(dependency)},
+			},
+		})
+
+		document.Relationships = append(document.Relationships, SPDXRelationship{SpdxElementId: rootId, RelationshipType: "CONTAINS", RelatedSpdxElement: id})
+	}
+
+	return document, nil
+}
+
+// spdxLicenseExpression joins multiple licenses of a package as alternatives
+func spdxLicenseExpression(licenses []string) string {
+	parts := []string{}
+
+	for _, license := range licenses {
+		if license = strings.TrimSpace(license); license != "" {
+			parts = append(parts, license)
+		}
+	}
+
+	switch len(parts) {
+	case 0:
+		return "NOASSERTION"
+	case 1:
+		return parts[0]
+	}
+
+	for i, part := range parts {
+		if strings.Contains(part, " ") {
+			parts[i] = "(" + part + ")"
+		}
+	}
+
+	return "(" + strings.Join(parts, " OR ") + ")"
+}
+
+func dependencyPurl(dependency BundledDependency) string {
+	name := dependency.Name
+	if dependency.Type == DependencyTypeNpm && strings.HasPrefix(name, "@") {
+		name = "%40" + name[1:]
+	}
+
+	purl := fmt.Sprintf("pkg:%s/%s", dependency.Type, name)
+	if dependency.Version != "" {
+		purl += "@" + strings.TrimPrefix(dependency.Version, "v")
+	}
+
+	return purl
+}
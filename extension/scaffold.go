@@ -0,0 +1,292 @@
+package extension
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/shopware/shopware-cli/internal/esbuild"
+)
+
+const (
+	ScaffoldTypePlugin = "plugin"
+	ScaffoldTypeApp    = "app"
+	ScaffoldTypeTheme  = "theme"
+)
+
+//go:embed all:scaffold
+var scaffoldFiles embed.FS
+
+var (
+	scaffoldNameRegExp      = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+	scaffoldNamespaceRegExp = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*(\\[A-Z][A-Za-z0-9]*)*$`)
+)
+
+// The store requires descriptions with 150 up to 185 characters, so the placeholders are long enough to validate
+const (
+	scaffoldDescriptionEnglish = "Replace this placeholder with a short summary of what the extension does for merchants. It is shown in the Administration and on the detail page in the Shopware Store."
+	scaffoldDescriptionGerman  = "Ersetze diesen Platzhalter durch eine kurze Zusammenfassung, was die Erweiterung für Händler leistet. Er wird in der Administration und im Shopware Store angezeigt."
+)
+
+type ScaffoldOptions struct {
+	Type string
+	// Name is the technical name like FroshTools
+	Name string
+	// Namespace is the PHP namespace of plugins and themes, defaults to the name
+	Namespace string
+	// ComposerName is the composer package of plugins and themes, defaults to the kebab case of the namespace
+	ComposerName    string
+	ShopwareVersion string
+	License         string
+	Author          string
+	// Label defaults to the name
+	Label            string
+	ManufacturerLink string
+	SupportLink      string
+}
+
+type scaffoldData struct {
+	ScaffoldOptions
+	KebabName          string
+	Theme              bool
+	DescriptionEnglish string
+	DescriptionGerman  string
+}
+
+// Scaffold creates a new extension in the folder dir/<name> and returns the created files relative to it
+func Scaffold(dir string, opts ScaffoldOptions) ([]string, error) {
+	if err := opts.applyDefaults(); err != nil {
+		return nil, err
+	}
+
+	root := filepath.Join(dir, opts.Name)
+
+	if entries, err := os.ReadDir(root); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("the folder %s exists already and is not empty", root)
+	}
+
+	data := scaffoldData{
+		ScaffoldOptions:    opts,
+		KebabName:          esbuild.ToKebabCase(opts.Name),
+		Theme:              opts.Type == ScaffoldTypeTheme,
+		DescriptionEnglish: scaffoldDescriptionEnglish,
+		DescriptionGerman:  scaffoldDescriptionGerman,
+	}
+
+	layers := map[string]string{"shared": ""}
+
+	switch opts.Type {
+	case ScaffoldTypeApp:
+		layers["app"] = ""
+		layers["resources"] = "Resources"
+	case ScaffoldTypeTheme:
+		layers["plugin"] = ""
+		layers["theme"] = ""
+		layers["resources"] = "src/Resources"
+	default:
+		layers["plugin"] = ""
+		layers["resources"] = "src/Resources"
+	}
+
+	var created []string
+
+	for layer, target := range layers {
+		files, err := renderScaffoldLayer(root, layer, target, data)
+		if err != nil {
+			return nil, err
+		}
+
+		created = append(created, files...)
+	}
+
+	resourcesDir := layers["resources"]
+
+	if err := writeScaffoldImage(filepath.Join(root, resourcesDir, "config", "plugin.png"), 256, 256); err != nil {
+		return nil, err
+	}
+
+	created = append(created, path.Join(resourcesDir, "config", "plugin.png"))
+
+	if data.Theme {
+		preview := path.Join(resourcesDir, "app", "storefront", "src", "assets", "theme-preview.png")
+
+		if err := writeScaffoldImage(filepath.Join(root, preview), 1280, 720); err != nil {
+			return nil, err
+		}
+
+		created = append(created, preview)
+	}
+
+	slices.Sort(created)
+
+	return created, nil
+}
+
+func (o *ScaffoldOptions) applyDefaults() error {
+	if o.Type != ScaffoldTypePlugin && o.Type != ScaffoldTypeApp && o.Type != ScaffoldTypeTheme {
+		return fmt.Errorf("unknown extension type %s, use plugin, app or theme", o.Type)
+	}
+
+	if !scaffoldNameRegExp.MatchString(o.Name) {
+		return fmt.Errorf("the name %s must be in PascalCase like FroshTools", o.Name)
+	}
+
+	if o.Namespace == "" {
+		o.Namespace = o.Name
+	}
+
+	if !scaffoldNamespaceRegExp.MatchString(o.Namespace) {
+		return fmt.Errorf("the namespace %s is not a valid PHP namespace like Frosh\\Tools", o.Namespace)
+	}
+
+	if o.ComposerName == "" {
+		parts := strings.Split(o.Namespace, "\\")
+		vendor := esbuild.ToKebabCase(parts[0])
+
+		o.ComposerName = vendor + "/" + esbuild.ToKebabCase(o.Name)
+
+		if len(parts) > 1 {
+			o.ComposerName = vendor + "/" + esbuild.ToKebabCase(strings.Join(parts[1:], ""))
+		}
+	}
+
+	if o.ShopwareVersion == "" {
+		o.ShopwareVersion = "~6.6.0"
+	}
+
+	if o.License == "" {
+		o.License = "MIT"
+	}
+
+	if o.Author == "" {
+		o.Author = o.Name
+	}
+
+	if o.Label == "" {
+		o.Label = o.Name
+	}
+
+	if o.ManufacturerLink == "" {
+		o.ManufacturerLink = "https://example.com"
+	}
+
+	if o.SupportLink == "" {
+		o.SupportLink = o.ManufacturerLink
+	}
+
+	return nil
+}
+
+var scaffoldTemplateFuncs = template.FuncMap{
+	"json": func(value string) (string, error) {
+		var buf bytes.Buffer
+
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		err := encoder.Encode(value)
+
+		return strings.TrimSuffix(buf.String(), "\n"), err
+	},
+	"xml": func(value string) (string, error) {
+		var buf bytes.Buffer
+		err := xml.EscapeText(&buf, []byte(value))
+
+		return buf.String(), err
+	},
+}
+
+func renderScaffoldTemplate(name string, content []byte, data any) ([]byte, error) {
+	tpl, err := template.New(name).Funcs(scaffoldTemplateFuncs).Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	var rendered bytes.Buffer
+	if err := tpl.Execute(&rendered, data); err != nil {
+		return nil, fmt.Errorf("render %s: %w", name, err)
+	}
+
+	return rendered.Bytes(), nil
+}
+
+// renderScaffoldLayer renders the templates of the layer into target, __NAME__ in paths is replaced with the name
+func renderScaffoldLayer(root, layer, target string, data scaffoldData) ([]string, error) {
+	var created []string
+
+	layerRoot := path.Join("scaffold", layer)
+
+	err := fs.WalkDir(scaffoldFiles, layerRoot, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		content, err := scaffoldFiles.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		rendered, err := renderScaffoldTemplate(file, content, data)
+		if err != nil {
+			return err
+		}
+
+		relPath := strings.TrimSuffix(strings.TrimPrefix(file, layerRoot+"/"), ".tmpl")
+		relPath = path.Join(target, strings.ReplaceAll(relPath, "__NAME__", data.Name))
+
+		targetFile := filepath.Join(root, filepath.FromSlash(relPath))
+
+		if err := os.MkdirAll(filepath.Dir(targetFile), os.ModePerm); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(targetFile, rendered, os.ModePerm); err != nil {
+			return err
+		}
+
+		created = append(created, relPath)
+
+		return nil
+	})
+
+	return created, err
+}
+
+// writeScaffoldImage writes a placeholder png, as the store requires an icon and themes a preview
+func writeScaffoldImage(file string, width, height int) error {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: 24, G: 155, B: 255, A: 255})
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), os.ModePerm); err != nil {
+		return err
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	if err := png.Encode(f, img); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
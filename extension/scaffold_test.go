@@ -0,0 +1,109 @@
+package extension
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaffoldPlugin(t *testing.T) {
+	dir := t.TempDir()
+
+	files, err := Scaffold(dir, ScaffoldOptions{Type: ScaffoldTypePlugin, Name: "FroshTools", Namespace: "Frosh\\Tools", ShopwareVersion: "~6.6.0", License: "MIT"})
+	require.NoError(t, err)
+
+	assert.Contains(t, files, "composer.json")
+	assert.Contains(t, files, "src/FroshTools.php")
+	assert.Contains(t, files, "src/Resources/config/config.xml")
+	assert.Contains(t, files, "src/Resources/config/plugin.png")
+	assert.Contains(t, files, "src/Resources/app/administration/src/main.js")
+	assert.Contains(t, files, ".github/workflows/ci.yml")
+
+	var composer map[string]any
+	content, err := os.ReadFile(filepath.Join(dir, "FroshTools", "composer.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, &composer))
+	assert.Equal(t, "frosh/tools", composer["name"])
+	assert.Equal(t, map[string]any{"Frosh\\Tools\\": "src/"}, composer["autoload"].(map[string]any)["psr-4"])
+
+	ext, err := GetExtensionByFolder(filepath.Join(dir, "FroshTools"))
+	require.NoError(t, err)
+
+	name, err := ext.GetName()
+	require.NoError(t, err)
+	assert.Equal(t, "FroshTools", name)
+
+	assert.Empty(t, scaffoldValidationErrors(t, ext))
+}
+
+func TestScaffoldTheme(t *testing.T) {
+	dir := t.TempDir()
+
+	files, err := Scaffold(dir, ScaffoldOptions{Type: ScaffoldTypeTheme, Name: "FroshTheme"})
+	require.NoError(t, err)
+
+	assert.Contains(t, files, "src/Resources/theme.json")
+	assert.Contains(t, files, "src/Resources/app/storefront/src/assets/theme-preview.png")
+
+	plugin, err := os.ReadFile(filepath.Join(dir, "FroshTheme", "src", "FroshTheme.php"))
+	require.NoError(t, err)
+	assert.Contains(t, string(plugin), "class FroshTheme extends Plugin implements ThemeInterface")
+
+	ext, err := GetExtensionByFolder(filepath.Join(dir, "FroshTheme"))
+	require.NoError(t, err)
+
+	assert.Empty(t, scaffoldValidationErrors(t, ext))
+}
+
+func TestScaffoldApp(t *testing.T) {
+	dir := t.TempDir()
+
+	files, err := Scaffold(dir, ScaffoldOptions{Type: ScaffoldTypeApp, Name: "FroshApp", Author: "Friends of Shopware"})
+	require.NoError(t, err)
+
+	assert.Contains(t, files, "manifest.xml")
+	assert.Contains(t, files, "Resources/config/config.xml")
+	assert.Contains(t, files, "Resources/snippet/storefront.en-GB.json")
+	assert.NotContains(t, files, "composer.json")
+
+	ext, err := GetExtensionByFolder(filepath.Join(dir, "FroshApp"))
+	require.NoError(t, err)
+	assert.Equal(t, TypePlatformApp, ext.GetType())
+
+	assert.Empty(t, scaffoldValidationErrors(t, ext))
+}
+
+func TestScaffoldInvalidOptions(t *testing.T) {
+	_, err := Scaffold(t.TempDir(), ScaffoldOptions{Type: ScaffoldTypePlugin, Name: "frosh-tools"})
+	assert.Error(t, err)
+
+	_, err = Scaffold(t.TempDir(), ScaffoldOptions{Type: "bundle", Name: "FroshTools"})
+	assert.Error(t, err)
+
+	_, err = Scaffold(t.TempDir(), ScaffoldOptions{Type: ScaffoldTypePlugin, Name: "FroshTools", Namespace: "Frosh/Tools"})
+	assert.Error(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "FroshTools"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "FroshTools", "README.md"), []byte(""), os.ModePerm))
+
+	_, err = Scaffold(dir, ScaffoldOptions{Type: ScaffoldTypePlugin, Name: "FroshTools"})
+	assert.Error(t, err)
+}
+
+// scaffoldValidationErrors ignores the same errors as the validation of a folder, the assets are built when zipping
+func scaffoldValidationErrors(t *testing.T, ext Extension) []ValidationMessage {
+	t.Helper()
+
+	vc := RunValidation(t.Context(), ext, ValidationOptions{RunExternalValidators: true})
+	vc.ApplyIgnores([]ConfigValidationIgnoreItem{
+		{Identifier: "zip.disallowed_file", Message: ".gitignore is not allowed in the zip file"},
+		{Identifier: "assets.not_built"},
+	})
+
+	return vc.Errors()
+}
@@ -0,0 +1,227 @@
+package extension
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/shopware/shopware-cli/internal/system"
+)
+
+// secretScanMaxFileSize skips large files like bundled assets, credentials are found in small config files
+const secretScanMaxFileSize = 2 * 1024 * 1024
+
+// SecretFinding is a file or line in the build output which looks like a credential
+type SecretFinding struct {
+	// Slash separated path relative to the scanned folder
+	Path     string
+	Line     int
+	Rule     string
+	Severity ConfigValidationSeverity
+	Message  string
+}
+
+func (f SecretFinding) String() string {
+	location := f.Path
+	if f.Line > 0 {
+		location = fmt.Sprintf("%s:%d", f.Path, f.Line)
+	}
+
+	return fmt.Sprintf("%s: %s (%s)", location, f.Message, f.Rule)
+}
+
+type secretPattern struct {
+	rule     string
+	message  string
+	severity ConfigValidationSeverity
+	regExp   *regexp.Regexp
+	// valueGroup is the submatch, which must contain a digit to count as secret. Labels and snippet keys have none.
+	valueGroup int
+}
+
+var secretPatterns = []secretPattern{
+	{rule: "private_key", message: "private key", severity: ValidationSeverityError, regExp: regexp.MustCompile(`-----BEGIN ((RSA|DSA|EC|OPENSSH|ENCRYPTED|PGP) )?PRIVATE KEY( BLOCK)?-----`)},
+	{rule: "aws_access_key", message: "AWS access key", severity: ValidationSeverityError, regExp: regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{rule: "github_token", message: "GitHub token", severity: ValidationSeverityError, regExp: regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{60,})\b`)},
+	{rule: "gitlab_token", message: "GitLab token", severity: ValidationSeverityError, regExp: regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}\b`)},
+	{rule: "slack_token", message: "Slack token", severity: ValidationSeverityError, regExp: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}\b`)},
+	{rule: "stripe_key", message: "Stripe secret key", severity: ValidationSeverityError, regExp: regexp.MustCompile(`\b[sr]k_live_[A-Za-z0-9]{20,}\b`)},
+	{rule: "google_api_key", message: "Google API key", severity: ValidationSeverityError, regExp: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{rule: "shopware_access_key", message: "Shopware integration access key", severity: ValidationSeverityWarning, regExp: regexp.MustCompile(`\bSW(IA|UA|SC)[A-Z0-9]{22,}\b`)},
+	{rule: "generic_secret", message: "hardcoded secret", severity: ValidationSeverityWarning, regExp: regexp.MustCompile(`(?i)\b[\w-]*(api[_-]?key|secret|passw(or)?d|access[_-]?token)[\w-]*['"]?\s*(=>|[:=])\s*['"]([A-Za-z0-9_/+=.-]{16,})['"]`), valueGroup: 4},
+}
+
+// composerAuthKeys are the sections of a composer auth.json which contain credentials
+var composerAuthKeys = []string{"http-basic", "github-oauth", "gitlab-oauth", "gitlab-token", "bitbucket-oauth", "bearer", "forgejo-token"}
+
+// ScanForSecrets scans the files which go into the zip for credentials. Ignored paths are matched exactly, as folder prefix or as glob pattern.
+// Findings in the bundled vendor folder are warnings.
+func ScanForSecrets(dir string, ignores []string) ([]SecretFinding, error) {
+	entries, err := system.Walk(dir, system.WalkOptions{ExcludeDirs: []string{".git", "node_modules"}})
+	if err != nil {
+		return nil, fmt.Errorf("walking directory for secrets: %w", err)
+	}
+
+	findings := []SecretFinding{}
+
+	for _, entry := range entries {
+		if entry.Entry.IsDir() || isSecretScanIgnored(entry.Path, ignores) {
+			continue
+		}
+
+		file := filepath.Join(dir, filepath.FromSlash(entry.Path))
+		name := path.Base(entry.Path)
+
+		if isEnvFile(name) {
+			findings = append(findings, SecretFinding{Path: entry.Path, Rule: "env_file", Severity: ValidationSeverityError, Message: "environment files contain the credentials of a system and must not be shipped"})
+			continue
+		}
+
+		info, err := entry.Entry.Info()
+		if err != nil || info.Size() > secretScanMaxFileSize {
+			continue
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		// Binary files like images cannot contain readable credentials
+		if bytes.IndexByte(content[:min(len(content), 8000)], 0) != -1 {
+			continue
+		}
+
+		if name == "auth.json" && hasComposerCredentials(content) {
+			findings = append(findings, SecretFinding{Path: entry.Path, Rule: "composer_auth", Severity: ValidationSeverityError, Message: "composer auth.json with credentials"})
+			continue
+		}
+
+		findings = append(findings, scanContentForSecrets(entry.Path, content)...)
+	}
+
+	// Bundled libraries ship test fixtures and docblock examples with keys, they are reported without failing the build
+	for i := range findings {
+		if strings.HasPrefix(findings[i].Path, "vendor/") {
+			findings[i].Severity = ValidationSeverityWarning
+		}
+	}
+
+	slices.SortStableFunc(findings, func(a, b SecretFinding) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return findings, nil
+}
+
+func scanContentForSecrets(relPath string, content []byte) []SecretFinding {
+	findings := []SecretFinding{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), secretScanMaxFileSize)
+
+	line := 0
+
+	for scanner.Scan() {
+		line++
+
+		for _, pattern := range secretPatterns {
+			if matchesSecretPattern(pattern, scanner.Bytes()) {
+				findings = append(findings, SecretFinding{Path: relPath, Line: line, Rule: pattern.rule, Severity: pattern.severity, Message: fmt.Sprintf("possible %s", pattern.message)})
+				break
+			}
+		}
+	}
+
+	return findings
+}
+
+func matchesSecretPattern(pattern secretPattern, line []byte) bool {
+	if pattern.valueGroup == 0 {
+		return pattern.regExp.Match(line)
+	}
+
+	for _, match := range pattern.regExp.FindAllSubmatch(line, -1) {
+		if bytes.ContainsAny(match[pattern.valueGroup], "0123456789") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isEnvFile(name string) bool {
+	if name != ".env" && !strings.HasPrefix(name, ".env.") {
+		return false
+	}
+
+	// Templates without values are fine to ship
+	switch strings.TrimPrefix(name, ".env.") {
+	case "dist", "example", "sample", "template":
+		return false
+	}
+
+	return true
+}
+
+func hasComposerCredentials(content []byte) bool {
+	var auth map[string]json.RawMessage
+	if err := json.Unmarshal(content, &auth); err != nil {
+		return false
+	}
+
+	for _, key := range composerAuthKeys {
+		value, ok := auth[key]
+		if !ok {
+			continue
+		}
+
+		trimmed := strings.TrimSpace(string(value))
+		if trimmed != "{}" && trimmed != "[]" && trimmed != "null" && trimmed != `""` {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isSecretScanIgnored(relPath string, ignores []string) bool {
+	for _, ignore := range ignores {
+		ignore = strings.Trim(ignore, "/")
+
+		if relPath == ignore || strings.HasPrefix(relPath, ignore+"/") {
+			return true
+		}
+
+		if matched, _ := path.Match(ignore, relPath); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SecretFindingsFailBuild returns true when a finding reaches the severity, off never fails the build
+func SecretFindingsFailBuild(findings []SecretFinding, failOn ConfigValidationSeverity) bool {
+	for _, finding := range findings {
+		switch failOn {
+		case ValidationSeverityOff:
+			return false
+		case ValidationSeverityWarning:
+			return true
+		default:
+			if finding.Severity == ValidationSeverityError {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,337 @@
+package extension
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/deprecation"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var (
+	phpNamespaceRegExp   = regexp.MustCompile(`(?m)^\s*namespace\s+([\w\\]+)\s*[;{]`)
+	phpDeclarationRegExp = regexp.MustCompile(`(?mi)^(?:\s*(?:abstract|final|readonly)\s+)*\s*(?:class|interface|trait|enum)\s+(\w+)`)
+)
+
+// xmlElement is an element of a container or routing file with its attributes and line
+type xmlElement struct {
+	name   string
+	parent string
+	attrs  map[string]string
+	line   int
+}
+
+type decoratedService struct {
+	id       string
+	location fileLocation
+}
+
+// validateServicesXML checks the XML files of Resources/config, which are loaded into the Symfony container and router
+func validateServicesXML(ctx context.Context, vc *ValidationContext) {
+	rootDir := vc.Extension.GetRootDir()
+	namespaces := extensionNamespaces(vc.Extension)
+
+	var classes map[string]bool
+
+	services := map[string]fileLocation{}
+	decorations := []decoratedService{}
+
+	for _, resourcesDir := range vc.Extension.GetResourcesDirs() {
+		configDir := filepath.Join(resourcesDir, "config")
+
+		_ = filepath.WalkDir(configDir, func(file string, d os.DirEntry, err error) error {
+			if err != nil {
+				return nil //nolint:nilerr
+			}
+
+			if d.IsDir() || filepath.Ext(file) != ".xml" {
+				return nil
+			}
+
+			relPath := strings.TrimPrefix(file, rootDir+"/")
+			configPath := filepath.ToSlash(strings.TrimPrefix(file, configDir+"/"))
+
+			// The plugin configuration has its own validation
+			if configPath == "config.xml" {
+				return nil
+			}
+
+			elements, line, err := readXMLElements(file)
+			if err != nil {
+				vc.AddErrorAt("xml.syntax", fileLocation{relPath, line}, err.Error())
+				return nil
+			}
+
+			switch {
+			case configPath == "services.xml" || strings.HasPrefix(configPath, "services/"):
+				if classes == nil {
+					classes = findPHPClasses(vc.Extension)
+				}
+
+				for _, element := range elements {
+					location := fileLocation{relPath, element.line}
+
+					switch {
+					case element.name == "import" && element.parent == "imports":
+						validateXMLResource(vc, "services.missing_import", file, location, element.attrs["resource"])
+					case element.name == "service" && element.parent == "services":
+						validateServiceDefinition(vc, element, location, namespaces, classes, services)
+
+						if decorates := element.attrs["decorates"]; decorates != "" {
+							decorations = append(decorations, decoratedService{id: decorates, location: location})
+						}
+					}
+				}
+			case configPath == "routes.xml" || strings.HasPrefix(configPath, "routes/"):
+				for _, element := range elements {
+					if element.name == "import" {
+						validateXMLResource(vc, "routes.missing_resource", file, fileLocation{relPath, element.line}, element.attrs["resource"])
+					}
+				}
+			}
+
+			return nil
+		})
+	}
+
+	validateDecoratedServices(ctx, vc, decorations, services)
+}
+
+func validateServiceDefinition(vc *ValidationContext, element xmlElement, location fileLocation, namespaces []string, classes map[string]bool, services map[string]fileLocation) {
+	id := element.attrs["id"]
+
+	if id != "" {
+		if previous, ok := services[id]; ok {
+			vc.AddErrorAt("services.duplicate_id", location, fmt.Sprintf("the service %s is already defined in %s", id, previous))
+		} else {
+			services[id] = location
+		}
+	}
+
+	if element.attrs["alias"] != "" || element.attrs["parent"] != "" {
+		return
+	}
+
+	// Without class attribute the id is used as class name
+	class := element.attrs["class"]
+	if class == "" {
+		class = id
+	}
+
+	class = strings.TrimPrefix(class, `\`)
+
+	if !strings.Contains(class, `\`) || strings.Contains(class, "%") || classes[strings.ToLower(class)] {
+		return
+	}
+
+	// Classes of Shopware, Symfony and other dependencies cannot be checked, they are not part of the extension
+	for _, namespace := range namespaces {
+		if strings.HasPrefix(class, namespace) {
+			vc.AddErrorAt("services.missing_class", location, fmt.Sprintf("the class %s of the service %s does not exist", class, id))
+			return
+		}
+	}
+}
+
+func validateXMLResource(vc *ValidationContext, identifier, file string, location fileLocation, resource string) {
+	// Globs, bundle paths, parameters and namespaces of attribute routes cannot be checked
+	if resource == "" || strings.ContainsAny(resource, `*?{%\`) || strings.HasPrefix(resource, "@") {
+		return
+	}
+
+	target := resource
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(file), resource)
+	}
+
+	if _, err := os.Stat(target); err != nil {
+		vc.AddErrorAt(identifier, location, fmt.Sprintf("the imported resource %s does not exist", resource))
+	}
+}
+
+// validateDecoratedServices reports decorations of Shopware services, which are removed in a supported Shopware version
+func validateDecoratedServices(ctx context.Context, vc *ValidationContext, decorations []decoratedService, services map[string]fileLocation) {
+	if len(decorations) == 0 {
+		return
+	}
+
+	db, err := deprecation.NewDatabase()
+	if err != nil {
+		return
+	}
+
+	removed := []decoratedService{}
+	entries := map[string]deprecation.Entry{}
+
+	for _, decoration := range decorations {
+		// Decorating an own service always works
+		if _, ok := services[decoration.id]; ok {
+			continue
+		}
+
+		entry, ok := db.Lookup(deprecation.KindService, decoration.id)
+		if !ok {
+			entry, ok = db.Lookup(deprecation.KindClass, decoration.id)
+		}
+
+		if ok && entry.Removed != "" {
+			removed = append(removed, decoration)
+			entries[decoration.id] = entry
+		}
+	}
+
+	if len(removed) == 0 {
+		return
+	}
+
+	constraint, err := vc.Extension.GetShopwareVersionConstraint()
+	if err != nil {
+		return
+	}
+
+	versions, err := GetShopwareVersions(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Debugf("Could not fetch Shopware versions, using the releases of the deprecation database: %v", err)
+		versions = db.Releases()
+	}
+
+	maxVersion := getMaxMatchingVersion(constraint, versions)
+	if maxVersion == nil {
+		return
+	}
+
+	for _, decoration := range removed {
+		entry := entries[decoration.id]
+
+		if maxVersion.LessThan(version.Must(version.NewVersion(entry.Removed))) {
+			continue
+		}
+
+		replacement := ""
+		if entry.Replacement != "" {
+			replacement = fmt.Sprintf(", decorate %s instead", entry.Replacement)
+		}
+
+		vc.AddErrorAt("services.decorated_removed", decoration.location, fmt.Sprintf("the decorated service %s does not exist since Shopware %s, but the extension claims to support Shopware %s%s", decoration.id, entry.Removed, maxVersion.String(), replacement))
+	}
+}
+
+// readXMLElements returns all elements of the file, on syntax errors the line of the error is returned
+func readXMLElements(file string) ([]xmlElement, int, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	decoder := xml.NewDecoder(strings.NewReader(string(content)))
+
+	elements := []xmlElement{}
+	stack := []string{""}
+
+	for {
+		line, _ := decoder.InputPos()
+
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			line, _ = decoder.InputPos()
+			return nil, line, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			attrs := map[string]string{}
+			for _, attr := range t.Attr {
+				attrs[attr.Name.Local] = attr.Value
+			}
+
+			elements = append(elements, xmlElement{name: t.Name.Local, parent: stack[len(stack)-1], attrs: attrs, line: line})
+			stack = append(stack, t.Name.Local)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	return elements, 0, nil
+}
+
+// findPHPClasses returns the lowercased names of all classes, interfaces, traits and enums declared in the extension
+func findPHPClasses(ext Extension) map[string]bool {
+	classes := map[string]bool{}
+
+	for _, sourceDir := range ext.GetSourceDirs() {
+		_ = filepath.WalkDir(sourceDir, func(file string, d os.DirEntry, err error) error {
+			if err != nil {
+				return nil //nolint:nilerr
+			}
+
+			if d.IsDir() {
+				if d.Name() == "node_modules" {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			if filepath.Ext(file) != ".php" {
+				return nil
+			}
+
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return nil //nolint:nilerr
+			}
+
+			namespace := ""
+			if match := phpNamespaceRegExp.FindSubmatch(content); match != nil {
+				namespace = string(match[1]) + `\`
+			}
+
+			for _, match := range phpDeclarationRegExp.FindAllSubmatch(content, -1) {
+				classes[strings.ToLower(namespace+string(match[1]))] = true
+			}
+
+			return nil
+		})
+	}
+
+	return classes
+}
+
+// extensionNamespaces returns the PSR-4 namespaces of the extension, classes in these namespaces must be part of the extension
+func extensionNamespaces(ext Extension) []string {
+	var psr4 map[string]string
+
+	switch e := ext.(type) {
+	case PlatformPlugin:
+		psr4 = e.Composer.Autoload.Psr4
+	case *PlatformPlugin:
+		psr4 = e.Composer.Autoload.Psr4
+	case ShopwareBundle:
+		psr4 = e.Composer.Autoload.Psr4
+	case *ShopwareBundle:
+		psr4 = e.Composer.Autoload.Psr4
+	}
+
+	namespaces := []string{}
+
+	for namespace := range psr4 {
+		if namespace != "" {
+			namespaces = append(namespaces, strings.TrimSuffix(namespace, `\`)+`\`)
+		}
+	}
+
+	return namespaces
+}
@@ -0,0 +1,81 @@
+package extension
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateServicesXML(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	ext := PlatformPlugin{
+		path:   tmpDir,
+		config: &Config{},
+	}
+	ext.Composer.Autoload.Psr4 = map[string]string{"MyPlugin\\": "src/"}
+
+	configDir := path.Join(tmpDir, "src", "Resources", "config")
+	_ = os.MkdirAll(path.Join(configDir, "services"), os.ModePerm)
+	_ = os.MkdirAll(path.Join(tmpDir, "src", "Controller"), os.ModePerm)
+
+	_ = os.WriteFile(path.Join(tmpDir, "src", "Controller", "MyController.php"), []byte(`<?php
+
+namespace MyPlugin\Controller;
+
+final class MyController
+{
+}
+`), os.ModePerm)
+
+	_ = os.WriteFile(path.Join(configDir, "services.xml"), []byte(`<?xml version="1.0" ?>
+<container xmlns="http://symfony.com/schema/dic/services">
+    <imports>
+        <import resource="services/*.xml"/>
+        <import resource="missing.xml"/>
+    </imports>
+
+    <services>
+        <service id="MyPlugin\Controller\MyController" public="true">
+            <argument type="service" id="Shopware\Core\Framework\Context"/>
+        </service>
+        <service id="MyPlugin\Service\MissingService"/>
+        <service id="my_plugin.logger" class="Monolog\Logger"/>
+        <service id="MyPlugin\Decorator" class="MyPlugin\Controller\MyController" decorates="MyPlugin\Controller\MyController"/>
+    </services>
+</container>
+`), os.ModePerm)
+
+	_ = os.WriteFile(path.Join(configDir, "services", "logger.xml"), []byte(`<?xml version="1.0" ?>
+<container xmlns="http://symfony.com/schema/dic/services">
+    <services>
+        <service id="my_plugin.logger" class="Monolog\Logger"/>
+        <service id="my_plugin.alias" alias="my_plugin.logger"/>
+    </services>
+</container>
+`), os.ModePerm)
+
+	_ = os.WriteFile(path.Join(configDir, "routes.xml"), []byte(`<?xml version="1.0" encoding="UTF-8" ?>
+<routes xmlns="http://symfony.com/schema/routing">
+    <import resource="../../Controller" type="attribute"/>
+    <import resource="../../Api" type="attribute"/>
+</routes>
+`), os.ModePerm)
+
+	_ = os.WriteFile(path.Join(configDir, "config.xml"), []byte(`<config><card>`), os.ModePerm)
+	_ = os.WriteFile(path.Join(configDir, "acl.xml"), []byte(`<acl><privilege></acl>`), os.ModePerm)
+
+	vc := newValidationContext(ext)
+
+	validateServicesXML(t.Context(), vc)
+
+	assert.Equal(t, []ValidationMessage{
+		{Identifier: "xml.syntax", Message: "Resources/config/acl.xml:1: XML syntax error on line 1: element <privilege> closed by </acl>", File: "Resources/config/acl.xml", Line: 1},
+		{Identifier: "routes.missing_resource", Message: "Resources/config/routes.xml:4: the imported resource ../../Api does not exist", File: "Resources/config/routes.xml", Line: 4},
+		{Identifier: "services.missing_import", Message: "Resources/config/services.xml:5: the imported resource missing.xml does not exist", File: "Resources/config/services.xml", Line: 5},
+		{Identifier: "services.missing_class", Message: "Resources/config/services.xml:12: the class MyPlugin\\Service\\MissingService of the service MyPlugin\\Service\\MissingService does not exist", File: "Resources/config/services.xml", Line: 12},
+		{Identifier: "services.duplicate_id", Message: "Resources/config/services.xml:13: the service my_plugin.logger is already defined in Resources/config/services/logger.xml:4", File: "Resources/config/services.xml", Line: 13},
+	}, vc.Errors())
+}
@@ -0,0 +1,43 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopware/shopware-cli/internal/shopwareversion"
+)
+
+// validateShopwareVersionSupport warns when all Shopware versions allowed by the constraint reached their end of life
+func validateShopwareVersionSupport(ctx context.Context, vc *ValidationContext) {
+	constraint, err := vc.Extension.GetShopwareVersionConstraint()
+	if err != nil {
+		return
+	}
+
+	metadata, err := shopwareversion.Load(ctx)
+	if err != nil {
+		return
+	}
+
+	reportEndOfLifeShopwareVersions(vc, metadata.LinesOf(constraint), time.Now())
+}
+
+func reportEndOfLifeShopwareVersions(vc *ValidationContext, lines []shopwareversion.Line, now time.Time) {
+	if len(lines) == 0 {
+		return
+	}
+
+	eol := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		if !line.IsEOL(now) {
+			return
+		}
+
+		eol = append(eol, fmt.Sprintf("%s (%s)", line.Version, line.EOL))
+	}
+
+	vc.AddWarning("shopware.eol", fmt.Sprintf("The extension supports only Shopware versions which reached their end of life: %s. Add support for a maintained Shopware version", strings.Join(eol, ", ")))
+}
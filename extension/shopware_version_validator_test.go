@@ -0,0 +1,27 @@
+package extension
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shopware/shopware-cli/internal/shopwareversion"
+)
+
+func TestReportEndOfLifeShopwareVersions(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ext := PlatformPlugin{path: t.TempDir(), config: &Config{}}
+
+	vc := newValidationContext(ext)
+	reportEndOfLifeShopwareVersions(vc, []shopwareversion.Line{{Version: "6.4", EOL: "2024-07-01"}, {Version: "6.5", EOL: "2025-07-01"}}, now)
+
+	assert.Len(t, vc.Warnings(), 1)
+	assert.Equal(t, "shopware.eol", vc.Warnings()[0].Identifier)
+	assert.Contains(t, vc.Warnings()[0].Message, "6.4 (2024-07-01), 6.5 (2025-07-01)")
+
+	vc = newValidationContext(ext)
+	reportEndOfLifeShopwareVersions(vc, []shopwareversion.Line{{Version: "6.5", EOL: "2025-07-01"}, {Version: "6.6", EOL: "2026-07-01"}}, now)
+
+	assert.Empty(t, vc.Warnings())
+}
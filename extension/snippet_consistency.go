@@ -0,0 +1,277 @@
+package extension
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// Storefront snippets use %name%, Administration snippets and ICU messages use {name} or {name, plural, ...}
+	snippetPlaceholderRegExp = regexp.MustCompile(`%[A-Za-z_][\w.\-]*%`)
+	snippetArgumentRegExp    = regexp.MustCompile(`^\{\s*([A-Za-z_][\w.\-]*)\s*[,}]`)
+	snippetSourceExtensions  = []string{".php", ".twig", ".js", ".ts", ".vue", ".xml", ".html"}
+)
+
+// compareSnippetPlaceholders reports translations which don't use the same placeholders as the main language
+func compareSnippetPlaceholders(mainFile, checkFile []byte, mainFilePath, filePath string, context *ValidationContext) {
+	mainSnippets, err := flattenSnippetFile(mainFile)
+	if err != nil {
+		return
+	}
+
+	checkSnippets, err := flattenSnippetFile(checkFile)
+	if err != nil {
+		return
+	}
+
+	for _, key := range sortedSnippetKeys(checkSnippets) {
+		value := checkSnippets[key]
+
+		if !hasBalancedBraces(value) {
+			context.AddWarning("snippet.invalid_format", fmt.Sprintf("Snippet file: %s, key %s has unbalanced braces", filePath, key))
+			continue
+		}
+
+		mainValue, ok := mainSnippets[key]
+		if !ok {
+			continue
+		}
+
+		expected := snippetPlaceholders(mainValue)
+		actual := snippetPlaceholders(value)
+
+		if !slices.Equal(expected, actual) {
+			context.AddWarning("snippet.placeholder_mismatch", fmt.Sprintf("Snippet file: %s, key %s uses the placeholders [%s], but the main language (%s) uses [%s]", filePath, key, strings.Join(actual, ", "), mainFilePath, strings.Join(expected, ", ")))
+		}
+	}
+}
+
+// validateMainSnippetFile checks the format of the main language and reports keys which are not used in the source code
+func validateMainSnippetFile(file, rootDir string, context *ValidationContext) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return
+	}
+
+	snippets, err := flattenSnippetFile(content)
+	if err != nil {
+		return
+	}
+
+	normalizedPath := strings.ReplaceAll(file, rootDir+"/", "")
+	sources := context.snippetSources()
+
+	for _, key := range sortedSnippetKeys(snippets) {
+		if !hasBalancedBraces(snippets[key]) {
+			context.AddWarning("snippet.invalid_format", fmt.Sprintf("Snippet file: %s, key %s has unbalanced braces", normalizedPath, key))
+		}
+
+		if !sources.isUsed(key) {
+			context.AddWarning("snippet.unused", fmt.Sprintf("Snippet file: %s, key %s is not used in the source code", normalizedPath, key))
+		}
+	}
+}
+
+// snippetSources contains the source code of the extension to look up snippet usages
+type snippetSources struct {
+	content    string
+	namespaces map[string]bool
+}
+
+func (c *ValidationContext) snippetSources() *snippetSources {
+	if c.snippetUsage != nil {
+		return c.snippetUsage
+	}
+
+	dirs := c.Extension.GetSourceDirs()
+	if len(dirs) == 0 {
+		dirs = []string{c.Extension.GetRootDir()}
+	}
+
+	var builder strings.Builder
+
+	for _, dir := range dirs {
+		_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return nil //nolint:nilerr
+			}
+
+			if d.IsDir() {
+				switch d.Name() {
+				case "node_modules", "vendor", "dist", "public":
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			if !slices.Contains(snippetSourceExtensions, filepath.Ext(path)) {
+				return nil
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil //nolint:nilerr
+			}
+
+			builder.Write(content)
+			builder.WriteByte('\n')
+
+			return nil
+		})
+	}
+
+	c.snippetUsage = &snippetSources{content: builder.String(), namespaces: map[string]bool{}}
+
+	return c.snippetUsage
+}
+
+// isUsed reports whether the key is referenced, either completely or as prefix of a dynamic key like 'myPlugin.status.' + status.
+// Keys in namespaces which are never referenced are considered as overrides of Shopware snippets.
+func (s *snippetSources) isUsed(key string) bool {
+	segments := strings.Split(key, ".")
+	if len(segments) < 2 {
+		return true
+	}
+
+	if !s.isNamespaceReferenced(segments[0]) {
+		return true
+	}
+
+	if strings.Contains(s.content, key) {
+		return true
+	}
+
+	for i := len(segments) - 1; i > 0; i-- {
+		prefix := strings.Join(segments[:i], ".") + "."
+
+		for _, suffix := range []string{"'", "\"", "`", "${"} {
+			if strings.Contains(s.content, prefix+suffix) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (s *snippetSources) isNamespaceReferenced(namespace string) bool {
+	if referenced, ok := s.namespaces[namespace]; ok {
+		return referenced
+	}
+
+	referenced := false
+
+	for _, quote := range []string{"'", "\"", "`"} {
+		if strings.Contains(s.content, quote+namespace+".") {
+			referenced = true
+			break
+		}
+	}
+
+	s.namespaces[namespace] = referenced
+
+	return referenced
+}
+
+func flattenSnippetFile(content []byte) (map[string]string, error) {
+	var data map[string]any
+
+	if err := json.Unmarshal(content, &data); err != nil {
+		return nil, err
+	}
+
+	result := map[string]string{}
+	flattenSnippets("", data, result)
+
+	return result, nil
+}
+
+func flattenSnippets(prefix string, data map[string]any, result map[string]string) {
+	for key, value := range data {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch v := value.(type) {
+		case map[string]any:
+			flattenSnippets(key, v, result)
+		case string:
+			result[key] = v
+		}
+	}
+}
+
+func sortedSnippetKeys(snippets map[string]string) []string {
+	keys := make([]string, 0, len(snippets))
+	for key := range snippets {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+// snippetPlaceholders returns the sorted and unique placeholder names of a snippet
+func snippetPlaceholders(value string) []string {
+	placeholders := []string{}
+
+	add := func(name string) {
+		if !slices.Contains(placeholders, name) {
+			placeholders = append(placeholders, name)
+		}
+	}
+
+	for _, match := range snippetPlaceholderRegExp.FindAllString(value, -1) {
+		add(match)
+	}
+
+	// Only the top level arguments are compared, the branches of plural and select arguments contain translated text
+	depth := 0
+
+	for i, c := range value {
+		switch c {
+		case '{':
+			if depth == 0 {
+				if match := snippetArgumentRegExp.FindStringSubmatch(value[i:]); match != nil {
+					add("{" + match[1] + "}")
+				}
+			}
+
+			depth++
+		case '}':
+			if depth > 0 {
+				depth--
+			}
+		}
+	}
+
+	slices.Sort(placeholders)
+
+	return placeholders
+}
+
+func hasBalancedBraces(value string) bool {
+	depth := 0
+
+	for _, c := range value {
+		switch c {
+		case '{':
+			depth++
+		case '}':
+			depth--
+
+			if depth < 0 {
+				return false
+			}
+		}
+	}
+
+	return depth == 0
+}
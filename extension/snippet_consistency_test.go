@@ -0,0 +1,131 @@
+package extension
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnippetValidatePlaceholderMismatch(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	context := newValidationContext(PlatformPlugin{
+		path:   tmpDir,
+		config: &Config{},
+	})
+
+	_ = os.MkdirAll(path.Join(tmpDir, "Resources", "snippet"), os.ModePerm)
+	_ = os.WriteFile(path.Join(tmpDir, "Resources", "snippet", "storefront.en-GB.json"), []byte(`{"a": "Hello %name%", "b": "{count} items", "c": "{0} None|]0,Inf[ %count% items"}`), os.ModePerm)
+	_ = os.WriteFile(path.Join(tmpDir, "Resources", "snippet", "storefront.de-DE.json"), []byte(`{"a": "Hallo %nmae%", "b": "{count Artikel", "c": "{0} Keine|]0,Inf[ %count% Artikel"}`), os.ModePerm)
+
+	assert.NoError(t, validateStorefrontSnippetsByPath(tmpDir, tmpDir, context))
+	assert.Len(t, context.errors, 0)
+	assert.Equal(t, []ValidationMessage{
+		{Identifier: "snippet.placeholder_mismatch", Message: "Snippet file: Resources/snippet/storefront.de-DE.json, key a uses the placeholders [%nmae%], but the main language (Resources/snippet/storefront.en-GB.json) uses [%name%]"},
+		{Identifier: "snippet.invalid_format", Message: "Snippet file: Resources/snippet/storefront.de-DE.json, key b has unbalanced braces"},
+	}, context.warnings)
+}
+
+func TestSnippetValidateTranslatedPlural(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	context := newValidationContext(PlatformPlugin{
+		path:   tmpDir,
+		config: &Config{},
+	})
+
+	_ = os.MkdirAll(path.Join(tmpDir, "Resources", "snippet"), os.ModePerm)
+	_ = os.WriteFile(path.Join(tmpDir, "Resources", "snippet", "storefront.en-GB.json"), []byte(`{"a": "{count, plural, one {item} other {{count} items}} for {name}", "b": "{gender, select, male {he} other {they}}"}`), os.ModePerm)
+	_ = os.WriteFile(path.Join(tmpDir, "Resources", "snippet", "storefront.de-DE.json"), []byte(`{"a": "{count, plural, one {Artikel} other {{count} Artikel}} für {name}", "b": "{geschlecht, select, male {er} other {sie}}"}`), os.ModePerm)
+
+	assert.NoError(t, validateStorefrontSnippetsByPath(tmpDir, tmpDir, context))
+	assert.Len(t, context.errors, 0)
+	assert.Equal(t, []ValidationMessage{
+		{Identifier: "snippet.placeholder_mismatch", Message: "Snippet file: Resources/snippet/storefront.de-DE.json, key b uses the placeholders [{geschlecht}], but the main language (Resources/snippet/storefront.en-GB.json) uses [{gender}]"},
+	}, context.warnings)
+}
+
+func TestSnippetValidateUnusedKeys(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	context := newValidationContext(PlatformPlugin{
+		path:   tmpDir,
+		config: &Config{},
+	})
+
+	_ = os.MkdirAll(path.Join(tmpDir, "src", "Resources", "snippet"), os.ModePerm)
+	_ = os.MkdirAll(path.Join(tmpDir, "src", "Resources", "views"), os.ModePerm)
+	_ = os.WriteFile(path.Join(tmpDir, "src", "Resources", "views", "index.html.twig"), []byte(`{{ 'myPlugin.title'|trans }} {{ ('myPlugin.status.' ~ status)|trans }}`), os.ModePerm)
+	_ = os.WriteFile(path.Join(tmpDir, "src", "Resources", "snippet", "storefront.en-GB.json"), []byte(`{
+	"myPlugin": {"title": "Title", "unused": "Unused", "status": {"open": "Open"}},
+	"checkout": {"addToCart": "Override of a Shopware snippet"}
+}`), os.ModePerm)
+
+	assert.NoError(t, validateStorefrontSnippetsByPath(path.Join(tmpDir, "src", "Resources", "snippet"), tmpDir, context))
+	assert.Len(t, context.errors, 0)
+	assert.Equal(t, []ValidationMessage{
+		{Identifier: "snippet.unused", Message: "Snippet file: src/Resources/snippet/storefront.en-GB.json, key myPlugin.unused is not used in the source code"},
+	}, context.warnings)
+}
+
+func TestSnippetFixerAddsMissingKeysAndSorts(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	ext := PlatformPlugin{
+		path:   tmpDir,
+		config: &Config{},
+	}
+	ext.Composer.Autoload.Psr4 = map[string]string{"MyPlugin\\": "src/"}
+
+	snippetDir := path.Join(tmpDir, "src", "Resources", "snippet")
+	_ = os.MkdirAll(snippetDir, os.ModePerm)
+	_ = os.WriteFile(path.Join(snippetDir, "storefront.en-GB.json"), []byte(`{
+  "myPlugin": {
+    "title": "Title",
+    "description": "<b>Description</b>"
+  },
+  "other": "Other"
+}
+`), os.ModePerm)
+	_ = os.WriteFile(path.Join(snippetDir, "storefront.de-DE.json"), []byte(`{
+  "zeta": "Zeta",
+  "myPlugin": {
+    "title": "Titel"
+  }
+}
+`), os.ModePerm)
+	_ = os.WriteFile(path.Join(snippetDir, "storefront.nl-NL.json"), []byte(`{
+  "myPlugin": {
+    "description": "Omschrijving",
+    "title": "Titel"
+  },
+  "other": "Ander"
+}
+`), os.ModePerm)
+
+	changes, err := SnippetFixer{}.Fix(getTestContext(), ext)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 3)
+
+	for _, change := range changes {
+		switch path.Base(change.Path) {
+		case "storefront.de-DE.json":
+			assert.Equal(t, "added missing keys from the main language and sorted keys", change.Description)
+			assert.Equal(t, `{
+  "myPlugin": {
+    "description": "<b>Description</b>",
+    "title": "Titel"
+  },
+  "other": "Other",
+  "zeta": "Zeta"
+}
+`, string(change.After))
+		case "storefront.en-GB.json":
+			assert.Contains(t, string(change.After), "\"description\": \"<b>Description</b>\",\n    \"title\"")
+		case "storefront.nl-NL.json":
+			assert.Equal(t, string(change.Before), string(change.After))
+		}
+	}
+}
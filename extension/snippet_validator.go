@@ -15,77 +15,86 @@ import (
 func validateStorefrontSnippets(context *ValidationContext) {
 	rootDir := context.Extension.GetRootDir()
 
-	for _, val := range context.Extension.GetResourcesDirs() {
-		storefrontFolder := path.Join(val, "snippet")
-
-		if err := validateStorefrontSnippetsByPath(storefrontFolder, rootDir, context); err != nil {
+	for _, val := range getStorefrontSnippetFolders(context.Extension) {
+		if err := validateStorefrontSnippetsByPath(val, rootDir, context); err != nil {
 			return
 		}
 	}
+}
 
-	for _, extraBundle := range context.Extension.GetExtensionConfig().Build.ExtraBundles {
-		bundlePath := rootDir
+func validateStorefrontSnippetsByPath(snippetFolder, rootDir string, context *ValidationContext) error {
+	snippetFiles, err := findSnippetFiles(snippetFolder, false)
+	if err != nil {
+		return err
+	}
 
-		if extraBundle.Path != "" {
-			bundlePath = path.Join(bundlePath, extraBundle.Path)
-		} else {
-			bundlePath = path.Join(bundlePath, extraBundle.Name)
+	for _, files := range snippetFiles {
+		if len(files) == 1 {
+			// We have no other file to compare against
+			validateMainSnippetFile(files[0], rootDir, context)
+			continue
 		}
 
-		storefrontFolder := path.Join(bundlePath, "Resources", "snippet")
+		mainFile := findMainSnippetFile(files)
 
-		if err := validateStorefrontSnippetsByPath(storefrontFolder, rootDir, context); err != nil {
-			return
+		if len(mainFile) == 0 {
+			context.AddWarning("snippet.validator", fmt.Sprintf("No en-GB.json file found in %s, using %s", snippetFolder, files[0]))
+			mainFile = files[0]
 		}
-	}
-}
 
-func validateStorefrontSnippetsByPath(snippetFolder, rootDir string, context *ValidationContext) error {
-	if _, err := os.Stat(snippetFolder); err != nil {
-		return nil //nolint:nilerr
-	}
+		mainFileContent, err := os.ReadFile(mainFile)
+		if err != nil {
+			return err
+		}
 
-	snippetFiles := make(map[string][]string)
+		if !json.Valid(mainFileContent) {
+			context.AddError("snippet.validator", fmt.Sprintf("File '%s' contains invalid JSON", mainFile))
 
-	err := filepath.WalkDir(snippetFolder, func(path string, d os.DirEntry, err error) error {
-		if d.IsDir() {
-			return nil
+			continue
 		}
 
-		if filepath.Ext(path) != ".json" {
-			return nil
+		for _, file := range files {
+			// makes no sense to compare to ourself
+			if file == mainFile {
+				continue
+			}
+
+			compareSnippets(mainFileContent, mainFile, file, context, rootDir)
 		}
 
-		containingFolder := filepath.Dir(path)
+		validateMainSnippetFile(mainFile, rootDir, context)
+	}
 
-		if _, ok := snippetFiles[containingFolder]; !ok {
-			snippetFiles[containingFolder] = []string{}
-		}
+	return nil
+}
 
-		snippetFiles[containingFolder] = append(snippetFiles[containingFolder], path)
+func validateAdministrationSnippets(context *ValidationContext) {
+	rootDir := context.Extension.GetRootDir()
 
-		return nil
-	})
+	for _, val := range getAdministrationSnippetFolders(context.Extension) {
+		if err := validateAdministrationByPath(val, rootDir, context); err != nil {
+			return
+		}
+	}
+}
+
+func validateAdministrationByPath(adminFolder, rootDir string, context *ValidationContext) error {
+	snippetFiles, err := findSnippetFiles(adminFolder, true)
 	if err != nil {
 		return err
 	}
 
-	for _, files := range snippetFiles {
+	for folder, files := range snippetFiles {
 		if len(files) == 1 {
 			// We have no other file to compare against
+			validateMainSnippetFile(files[0], rootDir, context)
 			continue
 		}
 
-		var mainFile string
-
-		for _, file := range files {
-			if strings.HasSuffix(filepath.Base(file), "en-GB.json") {
-				mainFile = file
-			}
-		}
+		mainFile := findMainSnippetFile(files)
 
 		if len(mainFile) == 0 {
-			context.AddWarning("snippet.validator", fmt.Sprintf("No en-GB.json file found in %s, using %s", snippetFolder, files[0]))
+			context.AddWarning("snippet.validator", fmt.Sprintf("No en-GB.json file found in %s, using %s", strings.ReplaceAll(folder, rootDir+"/", ""), strings.ReplaceAll(files[0], rootDir+"/", "")))
 			mainFile = files[0]
 		}
 
@@ -108,24 +117,46 @@ func validateStorefrontSnippetsByPath(snippetFolder, rootDir string, context *Va
 
 			compareSnippets(mainFileContent, mainFile, file, context, rootDir)
 		}
+
+		validateMainSnippetFile(mainFile, rootDir, context)
 	}
 
 	return nil
 }
 
-func validateAdministrationSnippets(context *ValidationContext) {
-	rootDir := context.Extension.GetRootDir()
+func getStorefrontSnippetFolders(ext Extension) []string {
+	folders := []string{}
 
-	for _, val := range context.Extension.GetResourcesDirs() {
-		adminFolder := path.Join(val, "app", "administration")
+	for _, val := range ext.GetResourcesDirs() {
+		folders = append(folders, path.Join(val, "snippet"))
+	}
 
-		if err := validateAdministrationByPath(adminFolder, rootDir, context); err != nil {
-			return
-		}
+	for _, bundlePath := range getExtraBundlePaths(ext) {
+		folders = append(folders, path.Join(bundlePath, "Resources", "snippet"))
 	}
 
-	for _, extraBundle := range context.Extension.GetExtensionConfig().Build.ExtraBundles {
-		bundlePath := rootDir
+	return folders
+}
+
+func getAdministrationSnippetFolders(ext Extension) []string {
+	folders := []string{}
+
+	for _, val := range ext.GetResourcesDirs() {
+		folders = append(folders, path.Join(val, "app", "administration"))
+	}
+
+	for _, bundlePath := range getExtraBundlePaths(ext) {
+		folders = append(folders, path.Join(bundlePath, "Resources", "app", "administration"))
+	}
+
+	return folders
+}
+
+func getExtraBundlePaths(ext Extension) []string {
+	paths := []string{}
+
+	for _, extraBundle := range ext.GetExtensionConfig().Build.ExtraBundles {
+		bundlePath := ext.GetRootDir()
 
 		if extraBundle.Path != "" {
 			bundlePath = path.Join(bundlePath, extraBundle.Path)
@@ -133,22 +164,21 @@ func validateAdministrationSnippets(context *ValidationContext) {
 			bundlePath = path.Join(bundlePath, extraBundle.Name)
 		}
 
-		adminFolder := path.Join(bundlePath, "Resources", "app", "administration")
-
-		if err := validateAdministrationByPath(adminFolder, rootDir, context); err != nil {
-			return
-		}
+		paths = append(paths, bundlePath)
 	}
-}
 
-func validateAdministrationByPath(adminFolder, rootDir string, context *ValidationContext) error {
-	if _, err := os.Stat(adminFolder); err != nil {
-		return nil //nolint:nilerr
-	}
+	return paths
+}
 
+// findSnippetFiles returns the snippet files grouped by their folder. Administration snippets have to be in a folder named snippet.
+func findSnippetFiles(folder string, administration bool) (map[string][]string, error) {
 	snippetFiles := make(map[string][]string)
 
-	err := filepath.WalkDir(adminFolder, func(path string, d os.DirEntry, err error) error {
+	if _, err := os.Stat(folder); err != nil {
+		return snippetFiles, nil //nolint:nilerr
+	}
+
+	err := filepath.WalkDir(folder, func(path string, d os.DirEntry, err error) error {
 		if d.IsDir() {
 			return nil
 		}
@@ -159,63 +189,29 @@ func validateAdministrationByPath(adminFolder, rootDir string, context *Validati
 
 		containingFolder := filepath.Dir(path)
 
-		if filepath.Base(containingFolder) != "snippet" {
+		if administration && filepath.Base(containingFolder) != "snippet" {
 			return nil
 		}
 
-		if _, ok := snippetFiles[containingFolder]; !ok {
-			snippetFiles[containingFolder] = []string{}
-		}
-
 		snippetFiles[containingFolder] = append(snippetFiles[containingFolder], path)
 
 		return nil
 	})
-	if err != nil {
-		return err
-	}
 
-	for folder, files := range snippetFiles {
-		if len(files) == 1 {
-			// We have no other file to compare against
-			continue
-		}
-
-		var mainFile string
-
-		for _, file := range files {
-			if strings.HasSuffix(filepath.Base(file), "en-GB.json") {
-				mainFile = file
-			}
-		}
-
-		if len(mainFile) == 0 {
-			context.AddWarning("snippet.validator", fmt.Sprintf("No en-GB.json file found in %s, using %s", strings.ReplaceAll(folder, rootDir+"/", ""), strings.ReplaceAll(files[0], rootDir+"/", "")))
-			mainFile = files[0]
-		}
-
-		mainFileContent, err := os.ReadFile(mainFile)
-		if err != nil {
-			return err
-		}
-
-		if !json.Valid(mainFileContent) {
-			context.AddError("snippet.validator", fmt.Sprintf("File '%s' contains invalid JSON", mainFile))
-
-			continue
-		}
+	return snippetFiles, err
+}
 
-		for _, file := range files {
-			// makes no sense to compare to ourself
-			if file == mainFile {
-				continue
-			}
+// findMainSnippetFile returns the en-GB file which the other languages are compared against
+func findMainSnippetFile(files []string) string {
+	var mainFile string
 
-			compareSnippets(mainFileContent, mainFile, file, context, rootDir)
+	for _, file := range files {
+		if strings.HasSuffix(filepath.Base(file), "en-GB.json") {
+			mainFile = file
 		}
 	}
 
-	return nil
+	return mainFile
 }
 
 func compareSnippets(mainFile []byte, mainFilePath, file string, context *ValidationContext, extensionRoot string) {
@@ -259,4 +255,6 @@ func compareSnippets(mainFile []byte, mainFilePath, file string, context *Valida
 			continue
 		}
 	}
+
+	compareSnippetPlaceholders(mainFile, checkFile, normalizedMainFilePath, strings.ReplaceAll(file, extensionRoot+"/", ""), context)
 }
@@ -0,0 +1,93 @@
+package extension
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const defaultScreenshotResolution = "1920x1080"
+
+// StoreScreenshot is a page captured in one language by extension store screenshot
+type StoreScreenshot struct {
+	Url      string
+	Selector string
+	WaitFor  string
+	// File is the png in the language folder of the image directory
+	File string
+}
+
+// Size returns the width and height of the configured resolution
+func (s ConfigStoreScreenshots) Size() (int, int) {
+	resolution := s.Resolution
+	if resolution == "" {
+		resolution = defaultScreenshotResolution
+	}
+
+	width, height, _ := strings.Cut(resolution, "x")
+
+	w, _ := strconv.Atoi(width)
+	h, _ := strconv.Atoi(height)
+
+	return w, h
+}
+
+// StoreScreenshots returns the screenshots of the pages in store.screenshots. They are written into the de and en folders of
+// store.image_directory with the priority as prefix, so account producer extension info push uploads them in this order.
+func StoreScreenshots(ext Extension, shopUrl string) ([]StoreScreenshot, error) {
+	cfg := ext.GetExtensionConfig()
+
+	if cfg == nil || cfg.Store.Screenshots == nil || len(cfg.Store.Screenshots.Pages) == 0 {
+		return nil, fmt.Errorf("configure the pages to capture in store.screenshots of the extension config")
+	}
+
+	if cfg.Store.ImageDirectory == nil {
+		return nil, fmt.Errorf("store.screenshots requires store.image_directory, the screenshots are uploaded from there")
+	}
+
+	if shopUrl == "" {
+		shopUrl = cfg.Store.Screenshots.ShopUrl
+	}
+
+	if shopUrl == "" {
+		return nil, fmt.Errorf("set store.screenshots.shop_url or --shop-url to the URL of the demo shop")
+	}
+
+	imageDir := filepath.Join(ext.GetPath(), *cfg.Store.ImageDirectory)
+
+	var screenshots []StoreScreenshot
+
+	for i, page := range cfg.Store.Screenshots.Pages {
+		fileName := fmt.Sprintf("%02d_%s.png", i+1, page.Name)
+
+		for language, url := range map[string]*string{"de": page.Url.German, "en": page.Url.English} {
+			if url == nil || *url == "" {
+				continue
+			}
+
+			screenshots = append(screenshots, StoreScreenshot{
+				Url:      screenshotUrl(shopUrl, *url),
+				Selector: page.Selector,
+				WaitFor:  page.WaitFor,
+				File:     filepath.Join(imageDir, language, fileName),
+			})
+		}
+	}
+
+	slices.SortFunc(screenshots, func(a, b StoreScreenshot) int {
+		return cmp.Compare(a.File, b.File)
+	})
+
+	return screenshots, nil
+}
+
+func screenshotUrl(shopUrl, url string) string {
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		return url
+	}
+
+	return strings.TrimSuffix(shopUrl, "/") + "/" + strings.TrimPrefix(url, "/")
+}
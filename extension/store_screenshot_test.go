@@ -0,0 +1,70 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreScreenshots(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "composer.json"), []byte(`{"name": "frosh/tools", "type": "shopware-platform-plugin", "extra": {"shopware-plugin-class": "Frosh\\Tools\\FroshTools"}, "autoload": {"psr-4": {"Frosh\\Tools\\": "src/"}}}`), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".shopware-extension.yml"), []byte(`store:
+  image_directory: src/Resources/store/images
+  screenshots:
+    shop_url: http://localhost:8000/
+    resolution: 1280x720
+    pages:
+      - name: product-detail
+        url:
+          de: /de/detail/1
+          en: /en/detail/1
+        selector: .product-detail
+      - name: checkout
+        url:
+          en: https://demo.example.com/checkout
+`), os.ModePerm))
+
+	ext, err := GetExtensionByFolder(dir)
+	require.NoError(t, err)
+
+	width, height := ext.GetExtensionConfig().Store.Screenshots.Size()
+	assert.Equal(t, 1280, width)
+	assert.Equal(t, 720, height)
+
+	screenshots, err := StoreScreenshots(ext, "")
+	require.NoError(t, err)
+
+	imageDir := filepath.Join(dir, "src", "Resources", "store", "images")
+
+	assert.Equal(t, []StoreScreenshot{
+		{Url: "http://localhost:8000/de/detail/1", Selector: ".product-detail", File: filepath.Join(imageDir, "de", "01_product-detail.png")},
+		{Url: "http://localhost:8000/en/detail/1", Selector: ".product-detail", File: filepath.Join(imageDir, "en", "01_product-detail.png")},
+		{Url: "https://demo.example.com/checkout", File: filepath.Join(imageDir, "en", "02_checkout.png")},
+	}, screenshots)
+
+	screenshots, err = StoreScreenshots(ext, "http://shop.test")
+	require.NoError(t, err)
+	assert.Equal(t, "http://shop.test/de/detail/1", screenshots[0].Url)
+}
+
+func TestStoreScreenshotsRequireImageDirectory(t *testing.T) {
+	cfg := &Config{}
+	cfg.Store.Screenshots = &ConfigStoreScreenshots{ShopUrl: "http://localhost", Pages: []ConfigStoreScreenshotPage{{Name: "home"}}}
+
+	_, err := StoreScreenshots(PlatformPlugin{path: t.TempDir(), config: cfg}, "")
+	assert.ErrorContains(t, err, "store.image_directory")
+}
+
+func TestValidateScreenshots(t *testing.T) {
+	url := "/en"
+
+	assert.NoError(t, validateScreenshots(ConfigStoreScreenshots{Pages: []ConfigStoreScreenshotPage{{Name: "home", Url: ConfigTranslated[string]{English: &url}}}}))
+	assert.ErrorContains(t, validateScreenshots(ConfigStoreScreenshots{Resolution: "800x600"}), "store.screenshots.resolution must be one of")
+	assert.ErrorContains(t, validateScreenshots(ConfigStoreScreenshots{Pages: []ConfigStoreScreenshotPage{{Name: "home"}}}), "requires a german or english url")
+	assert.ErrorContains(t, validateScreenshots(ConfigStoreScreenshots{Pages: []ConfigStoreScreenshotPage{{Name: "my page", Url: ConfigTranslated[string]{English: &url}}}}), "must only contain")
+}
@@ -0,0 +1,38 @@
+package extension
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReadStoreText returns the text of a store option like the description. Values starting with file: are read from the
+// file relative to the extension directory, markdown files are converted to HTML.
+func ReadStoreText(value, extensionDir string) (string, error) {
+	if !strings.HasPrefix(value, "file:") {
+		return value, nil
+	}
+
+	filePath := fmt.Sprintf("%s/%s", extensionDir, strings.TrimPrefix(value, "file:"))
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("error reading file at path %s with error: %v", filePath, err)
+	}
+
+	if filepath.Ext(filePath) != ".md" {
+		return string(content), nil
+	}
+
+	md := GetConfiguredGoldMark()
+
+	var buf bytes.Buffer
+	err = md.Convert(content, &buf)
+	if err != nil {
+		return "", fmt.Errorf("cannot convert file at path %s from markdown to html with error: %v", filePath, err)
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,30 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadStoreText(t *testing.T) {
+	tmpDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "description.md"), []byte("**Fast** search"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "manual.html"), []byte("<p>Install it</p>"), os.ModePerm))
+
+	text, err := ReadStoreText("<p>Inline</p>", tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "<p>Inline</p>", text)
+
+	text, err = ReadStoreText("file:description.md", tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "<p><strong>Fast</strong> search</p>\n", text)
+
+	text, err = ReadStoreText("file:manual.html", tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "<p>Install it</p>", text)
+
+	_, err = ReadStoreText("file:missing.md", tmpDir)
+	assert.Error(t, err)
+}
@@ -4,38 +4,277 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 )
 
+// themeFieldTypes are the types of theme config fields the theme manager of the administration can render
+var themeFieldTypes = []string{"color", "fontFamily", "media", "text", "textarea", "url", "number", "switch", "checkbox"}
+
+var (
+	themeReferenceRegExp = regexp.MustCompile(`^@[A-Za-z][A-Za-z0-9_]*$`)
+	themeHexColorRegExp  = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+)
+
+type themeJSON struct {
+	Name              string            `json:"name"`
+	PreviewMedia      string            `json:"previewMedia"`
+	Views             []string          `json:"views"`
+	Style             []json.RawMessage `json:"style"`
+	Script            []json.RawMessage `json:"script"`
+	Asset             []string          `json:"asset"`
+	ConfigInheritance []string          `json:"configInheritance"`
+	Config            struct {
+		Fields map[string]themeConfigField `json:"fields"`
+	} `json:"config"`
+}
+
+type themeConfigField struct {
+	Type   string          `json:"type"`
+	Value  json.RawMessage `json:"value"`
+	Custom json.RawMessage `json:"custom"`
+}
+
 func validateTheme(ctx *ValidationContext) {
-	themeJSONPath := fmt.Sprintf("%s/src/Resources/theme.json", ctx.Extension.GetPath())
+	for _, resourcesDir := range ctx.Extension.GetResourcesDirs() {
+		themeJSONPath := path.Join(resourcesDir, "theme.json")
+
+		if _, err := os.Stat(themeJSONPath); os.IsNotExist(err) {
+			continue
+		}
 
-	if _, err := os.Stat(themeJSONPath); !os.IsNotExist(err) {
 		content, err := os.ReadFile(themeJSONPath)
 		if err != nil {
 			ctx.AddError("theme.validator", "Invalid theme.json")
-			return
+			continue
 		}
 
 		var theme themeJSON
 		err = json.Unmarshal(content, &theme)
 		if err != nil {
 			ctx.AddError("theme.validator", "Cannot decode theme.json")
-			return
+			continue
 		}
 
+		relPath := strings.TrimPrefix(themeJSONPath, ctx.Extension.GetRootDir()+"/")
+
 		if len(theme.PreviewMedia) == 0 {
 			ctx.AddError("theme.validator", "Required field \"previewMedia\" missing in theme.json")
-			return
+		} else if _, err := os.Stat(path.Join(resourcesDir, theme.PreviewMedia)); os.IsNotExist(err) {
+			ctx.AddError("theme.validator", fmt.Sprintf("Theme preview image file is expected to be placed at %s, but not found there.", path.Join(resourcesDir, theme.PreviewMedia)))
 		}
 
-		expectedMediaPath := fmt.Sprintf("%s/src/Resources/%s", ctx.Extension.GetPath(), theme.PreviewMedia)
+		name, _ := ctx.Extension.GetName()
 
-		if _, err := os.Stat(expectedMediaPath); os.IsNotExist(err) {
-			ctx.AddError("theme.validator", fmt.Sprintf("Theme preview image file is expected to be placed at %s, but not found there.", expectedMediaPath))
+		validateThemeEntries(ctx, relPath, resourcesDir, name, &theme)
+		validateThemeInheritance(ctx, relPath, name, &theme)
+		validateThemeConfigFields(ctx, relPath, resourcesDir, theme.Config.Fields)
+	}
+}
+
+// themeEntryPaths returns the entries of style or script, which are either paths, references like @Storefront
+// or objects with the path as key and the resolve mapping as value
+func themeEntryPaths(entries []json.RawMessage) []string {
+	var paths []string
+
+	for _, entry := range entries {
+		var value string
+		if err := json.Unmarshal(entry, &value); err == nil {
+			paths = append(paths, value)
+			continue
+		}
+
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(entry, &object); err == nil {
+			keys := make([]string, 0, len(object))
+			for key := range object {
+				keys = append(keys, key)
+			}
+
+			sort.Strings(keys)
+			paths = append(paths, keys...)
 		}
 	}
+
+	return paths
 }
 
-type themeJSON struct {
-	PreviewMedia string `json:"previewMedia"`
+func validateThemeEntries(ctx *ValidationContext, relPath, resourcesDir, name string, theme *themeJSON) {
+	// The compiled scripts in dist are created by the build, their absence is reported by assets.not_built
+	_, storefrontSourceErr := os.Stat(path.Join(resourcesDir, "app", "storefront", "src"))
+	hasStorefrontSource := storefrontSourceErr == nil
+
+	checkEntries := func(key string, entries []string, extensions []string) {
+		for _, entry := range entries {
+			if strings.HasPrefix(entry, "@") {
+				if !themeReferenceRegExp.MatchString(entry) {
+					ctx.AddErrorAt("theme.invalid_entry", fileLocation{relPath, 0}, fmt.Sprintf("%s contains the invalid theme reference %s", key, entry))
+				}
+
+				continue
+			}
+
+			if len(extensions) > 0 && !slices.Contains(extensions, path.Ext(entry)) {
+				ctx.AddErrorAt("theme.invalid_entry", fileLocation{relPath, 0}, fmt.Sprintf("%s entry %s must be a %s file", key, entry, strings.Join(extensions, " or ")))
+				continue
+			}
+
+			if key == "script" && hasStorefrontSource && strings.Contains(entry, "/dist/") {
+				continue
+			}
+
+			if _, err := os.Stat(path.Join(resourcesDir, entry)); os.IsNotExist(err) {
+				ctx.AddErrorAt("theme.missing_file", fileLocation{relPath, 0}, fmt.Sprintf("%s references %s, which does not exist in %s", key, entry, strings.TrimPrefix(resourcesDir, ctx.Extension.GetRootDir()+"/")))
+			}
+		}
+	}
+
+	checkEntries("style", themeEntryPaths(theme.Style), []string{".scss", ".css"})
+	checkEntries("script", themeEntryPaths(theme.Script), []string{".js"})
+	checkEntries("asset", theme.Asset, nil)
+
+	for _, entry := range theme.Views {
+		if !themeReferenceRegExp.MatchString(entry) {
+			ctx.AddErrorAt("theme.invalid_entry", fileLocation{relPath, 0}, fmt.Sprintf("views contains %s, but only theme references like @Storefront or @%s are allowed", entry, name))
+		}
+	}
+
+	for _, entry := range theme.ConfigInheritance {
+		if !themeReferenceRegExp.MatchString(entry) {
+			ctx.AddErrorAt("theme.invalid_entry", fileLocation{relPath, 0}, fmt.Sprintf("configInheritance contains %s, but only theme references like @Storefront are allowed", entry))
+		}
+	}
+}
+
+// validateThemeInheritance checks that views, style and script inherit from the Storefront or a parent theme,
+// otherwise the theme misses the templates, styles or scripts of the default Storefront
+func validateThemeInheritance(ctx *ValidationContext, relPath, name string, theme *themeJSON) {
+	inheritsParent := func(entries []string) bool {
+		for _, entry := range entries {
+			if strings.HasPrefix(entry, "@") && entry != "@Plugins" && entry != "@"+name {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	lists := []struct {
+		key     string
+		present bool
+		entries []string
+	}{
+		{"views", theme.Views != nil, theme.Views},
+		{"style", theme.Style != nil, themeEntryPaths(theme.Style)},
+		{"script", theme.Script != nil, themeEntryPaths(theme.Script)},
+	}
+
+	for _, list := range lists {
+		// Without the key Shopware uses the default, which inherits from the Storefront
+		if !list.present {
+			continue
+		}
+
+		if !inheritsParent(list.entries) {
+			ctx.AddWarningAt("theme.inheritance", fileLocation{relPath, 0}, fmt.Sprintf("%s does not contain @Storefront or a parent theme, so the %s of the Storefront are missing", list.key, themeInheritanceSubject(list.key)))
+		}
+	}
+
+	if theme.Views != nil && !slices.Contains(theme.Views, "@Plugins") {
+		ctx.AddWarningAt("theme.inheritance", fileLocation{relPath, 0}, "views does not contain @Plugins, so the templates of other extensions are not rendered with this theme")
+	}
+}
+
+func themeInheritanceSubject(key string) string {
+	switch key {
+	case "views":
+		return "templates"
+	case "style":
+		return "styles"
+	}
+
+	return "scripts"
+}
+
+func validateThemeConfigFields(ctx *ValidationContext, relPath, resourcesDir string, fields map[string]themeConfigField) {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		field := fields[name]
+
+		// Fields without type override the value of an inherited field, custom fields are rendered by an own component
+		if field.Type == "" || len(field.Custom) > 0 {
+			continue
+		}
+
+		if !slices.Contains(themeFieldTypes, field.Type) {
+			ctx.AddErrorAt("theme.invalid_config", fileLocation{relPath, 0}, fmt.Sprintf("the config field %s has the unknown type %s, allowed are %s", name, field.Type, strings.Join(themeFieldTypes, ", ")))
+			continue
+		}
+
+		if len(field.Value) == 0 || string(field.Value) == "null" {
+			continue
+		}
+
+		if err := validateThemeFieldValue(resourcesDir, field); err != nil {
+			ctx.AddErrorAt("theme.invalid_config", fileLocation{relPath, 0}, fmt.Sprintf("the value of the config field %s %s", name, err.Error()))
+		}
+	}
+}
+
+func validateThemeFieldValue(resourcesDir string, field themeConfigField) error {
+	switch field.Type {
+	case "switch", "checkbox":
+		var value bool
+		if err := json.Unmarshal(field.Value, &value); err != nil {
+			return fmt.Errorf("must be a boolean for the type %s", field.Type)
+		}
+
+		return nil
+	case "number":
+		var number float64
+		if err := json.Unmarshal(field.Value, &number); err == nil {
+			return nil
+		}
+
+		var value string
+		if err := json.Unmarshal(field.Value, &value); err == nil {
+			if _, err := strconv.ParseFloat(value, 64); err == nil {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("must be a number")
+	}
+
+	var value string
+	if err := json.Unmarshal(field.Value, &value); err != nil {
+		return fmt.Errorf("must be a string for the type %s", field.Type)
+	}
+
+	switch field.Type {
+	case "color":
+		if strings.HasPrefix(value, "#") && !themeHexColorRegExp.MatchString(value) {
+			return fmt.Errorf("must be a valid hex color, but is %s", value)
+		}
+	case "media":
+		if value == "" || strings.Contains(value, "://") {
+			return nil
+		}
+
+		if _, err := os.Stat(path.Join(resourcesDir, value)); os.IsNotExist(err) {
+			return fmt.Errorf("references %s, which does not exist", value)
+		}
+	}
+
+	return nil
 }
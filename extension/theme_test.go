@@ -0,0 +1,139 @@
+package extension
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newThemeTestPlugin(t *testing.T, themeJSON string, files ...string) PlatformPlugin {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	resourcesDir := path.Join(tmpDir, "src", "Resources")
+
+	for _, file := range append(files, "theme.json") {
+		file = path.Join(resourcesDir, file)
+
+		assert.NoError(t, os.MkdirAll(path.Dir(file), os.ModePerm))
+		assert.NoError(t, os.WriteFile(file, []byte(themeJSON), os.ModePerm))
+	}
+
+	ext := PlatformPlugin{path: tmpDir, config: &Config{}}
+	ext.Composer.Extra.ShopwarePluginClass = "SwagTheme\\SwagTheme"
+	ext.Composer.Autoload.Psr4 = map[string]string{"SwagTheme\\": "src/"}
+
+	return ext
+}
+
+func TestValidateThemeValid(t *testing.T) {
+	ext := newThemeTestPlugin(t, `{
+		"name": "SwagTheme",
+		"views": ["@Storefront", "@Plugins", "@SwagTheme"],
+		"style": ["app/storefront/src/scss/overrides.scss", "@Storefront", "app/storefront/src/scss/base.scss"],
+		"script": ["@Storefront", "app/storefront/dist/storefront/js/swag-theme/swag-theme.js"],
+		"asset": ["@Storefront", "app/storefront/src/assets"],
+		"previewMedia": "app/storefront/src/assets/preview.jpg",
+		"config": {
+			"fields": {
+				"sw-color-brand-primary": {"value": "#008490"},
+				"sw-logo-desktop": {"type": "media", "value": "app/storefront/src/assets/logo.png"},
+				"swag-show-banner": {"type": "switch", "value": true},
+				"swag-banner-count": {"type": "number", "value": "3"},
+				"swag-color": {"type": "color", "value": "$sw-color-brand-primary"}
+			}
+		}
+	}`,
+		"app/storefront/src/scss/overrides.scss",
+		"app/storefront/src/scss/base.scss",
+		"app/storefront/src/main.js",
+		"app/storefront/src/assets/preview.jpg",
+		"app/storefront/src/assets/logo.png",
+	)
+
+	check := newValidationContext(ext)
+	validateTheme(check)
+
+	assert.Empty(t, check.Errors())
+	assert.Empty(t, check.Warnings())
+}
+
+func TestValidateThemeMissingFiles(t *testing.T) {
+	ext := newThemeTestPlugin(t, `{
+		"style": ["@Storefront", "app/storefront/src/scss/base.scss", "app/storefront/src/main.js"],
+		"script": ["@Storefront", "app/storefront/dist/storefront/js/swag-theme/swag-theme.js"],
+		"previewMedia": "app/storefront/src/assets/preview.jpg"
+	}`)
+
+	check := newValidationContext(ext)
+	validateTheme(check)
+
+	assert.Len(t, check.Errors(), 4)
+	assert.Equal(t, "theme.validator", check.Errors()[0].Identifier)
+	assert.Equal(t, "theme.missing_file", check.Errors()[1].Identifier)
+	assert.Equal(t, "Resources/theme.json: style references app/storefront/src/scss/base.scss, which does not exist in Resources", check.Errors()[1].Message)
+	assert.Equal(t, "theme.invalid_entry", check.Errors()[2].Identifier)
+	assert.Equal(t, "Resources/theme.json: style entry app/storefront/src/main.js must be a .scss or .css file", check.Errors()[2].Message)
+	assert.Equal(t, "Resources/theme.json", check.Errors()[2].File)
+	assert.Equal(t, "theme.missing_file", check.Errors()[3].Identifier)
+	assert.Contains(t, check.Errors()[3].Message, "script references app/storefront/dist/storefront/js/swag-theme/swag-theme.js")
+}
+
+func TestValidateThemeInheritance(t *testing.T) {
+	ext := newThemeTestPlugin(t, `{
+		"views": ["@SwagTheme"],
+		"style": ["@SwagTheme"],
+		"script": ["@Storefront", "@Parent Theme"],
+		"configInheritance": ["Storefront"],
+		"previewMedia": "preview.jpg"
+	}`, "preview.jpg")
+
+	check := newValidationContext(ext)
+	validateTheme(check)
+
+	assert.Len(t, check.Errors(), 2)
+	assert.Equal(t, "Resources/theme.json: script contains the invalid theme reference @Parent Theme", check.Errors()[0].Message)
+	assert.Equal(t, "Resources/theme.json: configInheritance contains Storefront, but only theme references like @Storefront are allowed", check.Errors()[1].Message)
+
+	assert.Len(t, check.Warnings(), 3)
+	assert.Equal(t, "theme.inheritance", check.Warnings()[0].Identifier)
+	assert.Equal(t, "Resources/theme.json: views does not contain @Storefront or a parent theme, so the templates of the Storefront are missing", check.Warnings()[0].Message)
+	assert.Contains(t, check.Warnings()[1].Message, "style does not contain @Storefront")
+	assert.Contains(t, check.Warnings()[2].Message, "views does not contain @Plugins")
+}
+
+func TestValidateThemeConfigFields(t *testing.T) {
+	ext := newThemeTestPlugin(t, `{
+		"previewMedia": "preview.jpg",
+		"config": {
+			"fields": {
+				"a-unknown": {"type": "colour", "value": "#fff"},
+				"b-color": {"type": "color", "value": "#ggg"},
+				"c-switch": {"type": "switch", "value": "yes"},
+				"d-number": {"type": "number", "value": "many"},
+				"e-media": {"type": "media", "value": "logo.png"},
+				"f-text": {"type": "text", "value": 1},
+				"g-custom": {"type": "select", "custom": {"componentName": "sw-single-select"}},
+				"h-null": {"type": "media", "value": null}
+			}
+		}
+	}`, "preview.jpg")
+
+	check := newValidationContext(ext)
+	validateTheme(check)
+
+	assert.Len(t, check.Errors(), 6)
+
+	for _, err := range check.Errors() {
+		assert.Equal(t, "theme.invalid_config", err.Identifier)
+	}
+
+	assert.Contains(t, check.Errors()[0].Message, "the config field a-unknown has the unknown type colour")
+	assert.Equal(t, "Resources/theme.json: the value of the config field b-color must be a valid hex color, but is #ggg", check.Errors()[1].Message)
+	assert.Equal(t, "Resources/theme.json: the value of the config field c-switch must be a boolean for the type switch", check.Errors()[2].Message)
+	assert.Equal(t, "Resources/theme.json: the value of the config field d-number must be a number", check.Errors()[3].Message)
+	assert.Equal(t, "Resources/theme.json: the value of the config field e-media references logo.png, which does not exist", check.Errors()[4].Message)
+	assert.Equal(t, "Resources/theme.json: the value of the config field f-text must be a string for the type text", check.Errors()[5].Message)
+}
@@ -0,0 +1,452 @@
+package extension
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MachineTranslationsFileName lists the machine translated entries until they are reviewed, it is never added to the zip
+const MachineTranslationsFileName = ".machine-translations.json"
+
+const snippetSourceLocale = "en-GB"
+
+// Translator is implemented by the providers of internal/translation
+type Translator interface {
+	Translate(ctx context.Context, texts []string, sourceLocale, targetLocale string) ([]string, error)
+}
+
+type TranslateOptions struct {
+	// Targets are locales like de-DE, store texts are only translated for de-DE and en-GB
+	Targets    []string
+	Translator Translator
+	// Provider is recorded for the machine translated entries
+	Provider string
+}
+
+// MachineTranslation is an entry which was filled by a translation provider and should be reviewed
+type MachineTranslation struct {
+	// File is relative to the extension folder
+	File string `json:"file"`
+	// Key is the dotted snippet key or the store option, empty for translated store text files
+	Key      string `json:"key,omitempty"`
+	Locale   string `json:"locale"`
+	Provider string `json:"provider"`
+}
+
+type missingSnippet struct {
+	object *orderedObject
+	key    string
+	// path is the dotted key of the snippet
+	path string
+	text string
+}
+
+// Translate fills the snippet keys missing in the target locales and the store texts missing in German or English.
+// Existing translations are never changed. The filled entries are added to MachineTranslationsFileName and returned.
+func Translate(ctx context.Context, ext Extension, opts TranslateOptions) ([]MachineTranslation, error) {
+	var translations []MachineTranslation
+
+	folders := map[string]bool{}
+	for _, folder := range getStorefrontSnippetFolders(ext) {
+		folders[folder] = false
+	}
+
+	for _, folder := range getAdministrationSnippetFolders(ext) {
+		folders[folder] = true
+	}
+
+	for folder, administration := range folders {
+		snippetFiles, err := findSnippetFiles(folder, administration)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, files := range snippetFiles {
+			mainFile := findMainSnippetFile(files)
+			if mainFile == "" {
+				continue
+			}
+
+			for _, target := range opts.Targets {
+				if target == snippetSourceLocale {
+					continue
+				}
+
+				translated, err := translateSnippetFile(ctx, ext, mainFile, target, opts)
+				if err != nil {
+					return nil, err
+				}
+
+				translations = append(translations, translated...)
+			}
+		}
+	}
+
+	translated, err := translateStoreTexts(ctx, ext, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	translations = append(translations, translated...)
+
+	slices.SortFunc(translations, compareMachineTranslations)
+
+	if len(translations) == 0 {
+		return translations, nil
+	}
+
+	return translations, recordMachineTranslations(ext.GetPath(), translations)
+}
+
+// translateSnippetFile fills the target file next to the main file, storefront.en-GB.json becomes storefront.de-DE.json
+func translateSnippetFile(ctx context.Context, ext Extension, mainFile, target string, opts TranslateOptions) ([]MachineTranslation, error) {
+	mainContent, err := os.ReadFile(mainFile)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := parseOrderedJSON(mainContent)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", mainFile, err)
+	}
+
+	main, ok := parsed.(*orderedObject)
+	if !ok {
+		return nil, nil
+	}
+
+	targetFile := filepath.Join(filepath.Dir(mainFile), strings.Replace(filepath.Base(mainFile), snippetSourceLocale, target, 1))
+	indent := detectJSONIndent(mainContent)
+	snippets := &orderedObject{values: map[string]any{}}
+
+	if content, err := os.ReadFile(targetFile); err == nil {
+		parsed, err := parseOrderedJSON(content)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %s: %w", targetFile, err)
+		}
+
+		if snippets, ok = parsed.(*orderedObject); !ok {
+			return nil, nil
+		}
+
+		indent = detectJSONIndent(content)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	missing := collectMissingSnippets(main, snippets, "")
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	texts := make([]string, len(missing))
+	for i, snippet := range missing {
+		texts[i] = snippet.text
+	}
+
+	translated, err := opts.Translator.Translate(ctx, texts, snippetSourceLocale, target)
+	if err != nil {
+		return nil, fmt.Errorf("translate %s: %w", targetFile, err)
+	}
+
+	relPath := strings.TrimPrefix(targetFile, ext.GetPath()+"/")
+	entries := make([]MachineTranslation, len(missing))
+
+	for i, snippet := range missing {
+		snippet.object.set(snippet.key, translated[i])
+		entries[i] = MachineTranslation{File: relPath, Key: snippet.path, Locale: target, Provider: opts.Provider}
+	}
+
+	if err := os.WriteFile(targetFile, encodeOrderedJSON(snippets, indent), os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// collectMissingSnippets returns the texts of main which are missing or empty in target, missing objects are created
+func collectMissingSnippets(main, target *orderedObject, prefix string) []missingSnippet {
+	var missing []missingSnippet
+
+	for _, key := range main.keys {
+		switch value := main.values[key].(type) {
+		case *orderedObject:
+			child, ok := target.values[key].(*orderedObject)
+			if !ok {
+				if _, exists := target.values[key]; exists {
+					continue
+				}
+
+				child = &orderedObject{values: map[string]any{}}
+				target.set(key, child)
+			}
+
+			missing = append(missing, collectMissingSnippets(value, child, prefix+key+".")...)
+		case string:
+			if existing, exists := target.values[key]; exists && existing != "" {
+				continue
+			}
+
+			if value != "" {
+				missing = append(missing, missingSnippet{object: target, key: key, path: prefix + key, text: value})
+			}
+		}
+	}
+
+	return missing
+}
+
+// translateStoreTexts fills the German or English store texts of the extension config from the other language
+func translateStoreTexts(ctx context.Context, ext Extension, opts TranslateOptions) ([]MachineTranslation, error) {
+	cfg := ext.GetExtensionConfig()
+	if cfg == nil || cfg.FileName == "" {
+		return nil, nil
+	}
+
+	if _, err := os.Stat(filepath.Join(ext.GetPath(), cfg.FileName)); err != nil {
+		return nil, nil //nolint:nilerr
+	}
+
+	options := []struct {
+		key   string
+		value *ConfigTranslated[string]
+	}{
+		{"store.meta_title", &cfg.Store.MetaTitle},
+		{"store.meta_description", &cfg.Store.MetaDescription},
+		{"store.description", &cfg.Store.Description},
+		{"store.installation_manual", &cfg.Store.InstallationManual},
+	}
+
+	var translations []MachineTranslation
+
+	// changes maps the option keys like store.description.de to the translated values
+	changes := map[string]string{}
+
+	for _, target := range opts.Targets {
+		var source string
+
+		switch target {
+		case "de-DE":
+			source = "en-GB"
+		case "en-GB":
+			source = "de-DE"
+		default:
+			continue
+		}
+
+		for _, option := range options {
+			from, to := option.value.English, &option.value.German
+			language := "de"
+			if target == "en-GB" {
+				from, to, language = option.value.German, &option.value.English, "en"
+			}
+
+			if from == nil || *from == "" || (*to != nil && **to != "") {
+				continue
+			}
+
+			translated, entry, err := translateStoreText(ctx, ext, *from, source, target, opts)
+			if err != nil {
+				return nil, fmt.Errorf("translate %s: %w", option.key, err)
+			}
+
+			*to = &translated
+			changes[option.key+"."+language] = translated
+
+			if entry.File == "" {
+				entry = MachineTranslation{File: cfg.FileName, Key: option.key, Locale: target, Provider: opts.Provider}
+			}
+
+			translations = append(translations, entry)
+		}
+	}
+
+	if len(translations) == 0 {
+		return nil, nil
+	}
+
+	return translations, writeStoreTexts(filepath.Join(ext.GetPath(), cfg.FileName), changes)
+}
+
+// writeStoreTexts sets the changed store texts in the extension config, the other settings and comments of the file are kept
+func writeStoreTexts(file string, changes map[string]string) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return fmt.Errorf("cannot parse %s: %w", file, err)
+	}
+
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("%s must contain a mapping", file)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(changes)) {
+		parts := strings.Split(key, ".")
+		mapping := document.Content[0]
+
+		for _, part := range parts[:len(parts)-1] {
+			mapping = setConfigMappingNode(mapping, part)
+		}
+
+		if err := setConfigValue(mapping, parts[len(parts)-1], changes[key]); err != nil {
+			return err
+		}
+	}
+
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(detectYAMLIndent(content))
+
+	if err := encoder.Encode(&document); err != nil {
+		return err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, buf.Bytes(), 0o644)
+}
+
+// translateStoreText returns the translated value, texts read with file: are translated into a new file which is referenced instead
+func translateStoreText(ctx context.Context, ext Extension, value, source, target string, opts TranslateOptions) (string, MachineTranslation, error) {
+	if !strings.HasPrefix(value, "file:") {
+		translated, err := opts.Translator.Translate(ctx, []string{value}, source, target)
+		if err != nil {
+			return "", MachineTranslation{}, err
+		}
+
+		return translated[0], MachineTranslation{}, nil
+	}
+
+	sourceFile := strings.TrimPrefix(value, "file:")
+	targetFile := storeTextFileName(sourceFile, localeLanguage(source), localeLanguage(target))
+
+	targetPath := filepath.Join(ext.GetPath(), targetFile)
+
+	if _, err := os.Stat(targetPath); err == nil {
+		return "", MachineTranslation{}, fmt.Errorf("the file %s exists already, reference it in the extension config", targetFile)
+	}
+
+	content, err := os.ReadFile(filepath.Join(ext.GetPath(), sourceFile))
+	if err != nil {
+		return "", MachineTranslation{}, err
+	}
+
+	translated, err := opts.Translator.Translate(ctx, []string{string(content)}, source, target)
+	if err != nil {
+		return "", MachineTranslation{}, err
+	}
+
+	if err := os.WriteFile(targetPath, []byte(translated[0]), os.ModePerm); err != nil {
+		return "", MachineTranslation{}, err
+	}
+
+	return "file:" + targetFile, MachineTranslation{File: targetFile, Locale: target, Provider: opts.Provider}, nil
+}
+
+// storeTextFileName swaps the language in names like description.en.md, otherwise it is added like description.de.md
+func storeTextFileName(file, source, target string) string {
+	dir, base := path.Split(file)
+
+	if strings.Contains(base, "."+source+".") {
+		return dir + strings.Replace(base, "."+source+".", "."+target+".", 1)
+	}
+
+	ext := path.Ext(base)
+
+	return dir + strings.TrimSuffix(base, ext) + "." + target + ext
+}
+
+func localeLanguage(locale string) string {
+	language, _, _ := strings.Cut(locale, "-")
+
+	return strings.ToLower(language)
+}
+
+// recordMachineTranslations merges the entries into the MachineTranslationsFileName of the extension
+func recordMachineTranslations(dir string, translations []MachineTranslation) error {
+	file := filepath.Join(dir, MachineTranslationsFileName)
+
+	entries, err := ReadMachineTranslations(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, translation := range translations {
+		if !slices.Contains(entries, translation) {
+			entries = append(entries, translation)
+		}
+	}
+
+	slices.SortFunc(entries, compareMachineTranslations)
+
+	var buf bytes.Buffer
+
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(entries); err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, buf.Bytes(), os.ModePerm)
+}
+
+// ReadMachineTranslations returns the machine translated entries of the extension folder which were not reviewed yet
+func ReadMachineTranslations(dir string) ([]MachineTranslation, error) {
+	content, err := os.ReadFile(filepath.Join(dir, MachineTranslationsFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []MachineTranslation
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", MachineTranslationsFileName, err)
+	}
+
+	return entries, nil
+}
+
+// validateMachineTranslations warns about every machine translated entry which was not reviewed yet
+func validateMachineTranslations(vc *ValidationContext) {
+	entries, err := ReadMachineTranslations(vc.Extension.GetPath())
+	if err != nil {
+		vc.AddWarningAt("snippet.machine_translated", fileLocation{file: MachineTranslationsFileName}, err.Error())
+		return
+	}
+
+	for _, entry := range entries {
+		what := "The file"
+		if entry.Key != "" {
+			what = entry.Key
+		}
+
+		vc.AddWarningAt("snippet.machine_translated", fileLocation{file: entry.File}, fmt.Sprintf("%s was machine translated to %s by %s and is not reviewed yet, remove it from %s after the review", what, entry.Locale, entry.Provider, MachineTranslationsFileName))
+	}
+}
+
+func compareMachineTranslations(a, b MachineTranslation) int {
+	return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Locale, b.Locale), cmp.Compare(a.Key, b.Key))
+}
@@ -0,0 +1,140 @@
+package extension
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type prefixTranslator struct {
+	calls int
+}
+
+func (t *prefixTranslator) Translate(_ context.Context, texts []string, _, targetLocale string) ([]string, error) {
+	t.calls++
+
+	translated := make([]string, len(texts))
+	for i, text := range texts {
+		translated[i] = targetLocale + ": " + text
+	}
+
+	return translated, nil
+}
+
+func TestTranslateFillsMissingSnippets(t *testing.T) {
+	ext := newGenerateTestPlugin(t)
+	snippetDir := filepath.Join(ext.GetPath(), "src", "Resources", "snippet")
+
+	require.NoError(t, os.MkdirAll(snippetDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(snippetDir, "storefront.en-GB.json"), []byte(`{
+    "frosh": {
+        "title": "Hello %name%",
+        "empty": "Empty",
+        "kept": "Kept"
+    }
+}
+`), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(snippetDir, "storefront.de-DE.json"), []byte(`{
+  "frosh": {
+    "kept": "Behalten",
+    "empty": ""
+  }
+}
+`), os.ModePerm))
+
+	translator := &prefixTranslator{}
+
+	translations, err := Translate(getTestContext(), ext, TranslateOptions{Targets: []string{"de-DE", "fr-FR"}, Translator: translator, Provider: "deepl"})
+	require.NoError(t, err)
+
+	assert.Contains(t, translations, MachineTranslation{File: "src/Resources/snippet/storefront.de-DE.json", Key: "frosh.empty", Locale: "de-DE", Provider: "deepl"})
+	assert.Contains(t, translations, MachineTranslation{File: "src/Resources/snippet/storefront.de-DE.json", Key: "frosh.title", Locale: "de-DE", Provider: "deepl"})
+	assert.Contains(t, translations, MachineTranslation{File: "src/Resources/snippet/storefront.fr-FR.json", Key: "frosh.kept", Locale: "fr-FR", Provider: "deepl"})
+	assert.NotContains(t, translations, MachineTranslation{File: "src/Resources/snippet/storefront.de-DE.json", Key: "frosh.kept", Locale: "de-DE", Provider: "deepl"})
+
+	german, err := os.ReadFile(filepath.Join(snippetDir, "storefront.de-DE.json"))
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "frosh": {
+    "kept": "Behalten",
+    "empty": "de-DE: Empty",
+    "title": "de-DE: Hello %name%"
+  }
+}
+`, string(german))
+
+	french, err := os.ReadFile(filepath.Join(snippetDir, "storefront.fr-FR.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(french), `        "title": "fr-FR: Hello %name%",`)
+
+	recorded, err := ReadMachineTranslations(ext.GetPath())
+	require.NoError(t, err)
+	assert.Equal(t, translations, recorded)
+
+	// A second run has nothing left to translate
+	calls := translator.calls
+
+	translations, err = Translate(getTestContext(), ext, TranslateOptions{Targets: []string{"de-DE", "fr-FR"}, Translator: translator, Provider: "deepl"})
+	require.NoError(t, err)
+	assert.Empty(t, translations)
+	assert.Equal(t, calls, translator.calls)
+}
+
+func TestTranslateStoreTexts(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "composer.json"), []byte(`{"name": "frosh/tools", "type": "shopware-platform-plugin", "extra": {"shopware-plugin-class": "Frosh\\Tools\\FroshTools"}, "autoload": {"psr-4": {"Frosh\\Tools\\": "src/"}}}`), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "description.en.md"), []byte("# Tools\n"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".shopware-extension.yml"), []byte(`# Store settings
+store:
+  meta_title:
+    en: Tools
+    de: Werkzeuge
+  meta_description:
+    de: Werkzeuge für Händler
+  description:
+    en: file:description.en.md
+`), os.ModePerm))
+
+	ext, err := GetExtensionByFolder(dir)
+	require.NoError(t, err)
+
+	translations, err := Translate(getTestContext(), ext, TranslateOptions{Targets: []string{"de-DE", "en-GB"}, Translator: &prefixTranslator{}, Provider: "openai"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []MachineTranslation{
+		{File: ".shopware-extension.yml", Key: "store.meta_description", Locale: "en-GB", Provider: "openai"},
+		{File: "description.de.md", Locale: "de-DE", Provider: "openai"},
+	}, translations)
+
+	description, err := os.ReadFile(filepath.Join(dir, "description.de.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "de-DE: # Tools\n", string(description))
+
+	config, err := os.ReadFile(filepath.Join(dir, ".shopware-extension.yml"))
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(config), "de: file:description.de.md"))
+	assert.True(t, strings.Contains(string(config), "en: 'en-GB: Werkzeuge für Händler'"))
+
+	// Only the translated keys are changed, comments and unset settings are kept as they are
+	assert.True(t, strings.HasPrefix(string(config), "# Store settings\nstore:\n  meta_title:\n    en: Tools\n"))
+	assert.NotContains(t, string(config), "build:")
+
+	vc := newValidationContext(ext)
+	validateMachineTranslations(vc)
+
+	assert.Len(t, vc.Warnings(), 2)
+	assert.Equal(t, "snippet.machine_translated", vc.Warnings()[0].Identifier)
+	assert.Equal(t, ".shopware-extension.yml", vc.Warnings()[0].File)
+	assert.Contains(t, vc.Warnings()[0].Message, "store.meta_description was machine translated to en-GB by openai")
+}
+
+func TestStoreTextFileName(t *testing.T) {
+	assert.Equal(t, "docs/description.de.md", storeTextFileName("docs/description.en.md", "en", "de"))
+	assert.Equal(t, "description.de.html", storeTextFileName("description.html", "en", "de"))
+}
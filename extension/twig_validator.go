@@ -0,0 +1,507 @@
+package extension
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const twigExtension = ".html.twig"
+
+// twigPairedTags maps the tags which need an end tag to their end tag
+var twigPairedTags = map[string]string{
+	"block":                  "endblock",
+	"if":                     "endif",
+	"for":                    "endfor",
+	"set":                    "endset",
+	"apply":                  "endapply",
+	"autoescape":             "endautoescape",
+	"embed":                  "endembed",
+	"macro":                  "endmacro",
+	"spaceless":              "endspaceless",
+	"with":                   "endwith",
+	"filter":                 "endfilter",
+	"sandbox":                "endsandbox",
+	"cache":                  "endcache",
+	"guard":                  "endguard",
+	"sw_silent_feature_call": "endsw_silent_feature_call",
+}
+
+var twigRawEndRegExp = regexp.MustCompile(`\{%[-~]?\s*end(verbatim|raw)\s*[-~]?%\}`)
+
+type twigTag struct {
+	name string
+	args string
+	line int
+}
+
+type twigSyntaxError struct {
+	line    int
+	message string
+}
+
+// twigTemplate is the outcome of scanning a template for its tag structure
+type twigTemplate struct {
+	extends string
+	// extendsLine is the line of the sw_extends tag
+	extendsLine int
+	// Blocks on the top level, in a child template only these override blocks of the parent
+	topLevelBlocks []twigTag
+	blocks         map[string]bool
+	errors         []twigSyntaxError
+}
+
+func validateTwigTemplates(context *ValidationContext) {
+	rootDir := context.Extension.GetRootDir()
+	extensionName, _ := context.Extension.GetName()
+	storefrontViews := findStorefrontViews(context.Extension.GetPath())
+
+	for _, resourcesDir := range context.Extension.GetResourcesDirs() {
+		viewsDir := path.Join(resourcesDir, "views")
+
+		resolver := twigTemplateResolver{
+			namespaces: map[string]string{},
+			cache:      map[string]*twigTemplate{},
+		}
+
+		if storefrontViews != "" {
+			resolver.namespaces["Storefront"] = storefrontViews
+		}
+
+		if extensionName != "" {
+			resolver.namespaces[extensionName] = viewsDir
+		}
+
+		for _, folder := range []string{viewsDir, path.Join(resourcesDir, "app", "administration")} {
+			validateTwigTemplatesByPath(folder, rootDir, &resolver, context)
+		}
+	}
+}
+
+func validateTwigTemplatesByPath(folder, rootDir string, resolver *twigTemplateResolver, context *ValidationContext) {
+	if _, err := os.Stat(folder); err != nil {
+		return
+	}
+
+	_ = filepath.WalkDir(folder, func(file string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil //nolint:nilerr
+		}
+
+		if d.IsDir() {
+			if d.Name() == "node_modules" {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !strings.HasSuffix(d.Name(), twigExtension) {
+			return nil
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil //nolint:nilerr
+		}
+
+		relPath := strings.TrimPrefix(file, rootDir+"/")
+		template := scanTwigTemplate(string(content))
+
+		for _, syntaxErr := range template.errors {
+			context.AddErrorAt("twig.syntax", fileLocation{relPath, syntaxErr.line}, syntaxErr.message)
+		}
+
+		if template.extends == "" {
+			return nil
+		}
+
+		parentBlocks, found, known := resolver.blocks(template.extends)
+
+		if !known {
+			return nil
+		}
+
+		if !found {
+			context.AddErrorAt("twig.missing_extends_target", fileLocation{relPath, template.extendsLine}, fmt.Sprintf("the template %s extended by sw_extends does not exist", template.extends))
+			return nil
+		}
+
+		// Without all parents it is not known which blocks exist
+		if parentBlocks == nil {
+			return nil
+		}
+
+		for _, block := range template.topLevelBlocks {
+			if !parentBlocks[block.name] {
+				context.AddWarningAt("twig.unknown_block", fileLocation{relPath, block.line}, fmt.Sprintf("the block %s does not exist in %s and will never be rendered", block.name, template.extends))
+			}
+		}
+
+		return nil
+	})
+}
+
+// twigTemplateResolver looks up templates like @Storefront/storefront/base.html.twig in the views folders of known namespaces
+type twigTemplateResolver struct {
+	namespaces map[string]string
+	cache      map[string]*twigTemplate
+}
+
+// blocks returns all blocks of the template and its parents. known is false when the namespace of the template
+// cannot be checked, e.g. because Shopware is not installed. blocks is nil when not all parents could be resolved.
+func (r *twigTemplateResolver) blocks(name string) (blocks map[string]bool, found bool, known bool) {
+	template, found, known := r.load(name)
+	if !found || !known {
+		return nil, found, known
+	}
+
+	blocks = map[string]bool{}
+	visited := map[string]bool{name: true}
+
+	for {
+		for block := range template.blocks {
+			blocks[block] = true
+		}
+
+		parentName := template.extends
+		if parentName == "" || visited[parentName] {
+			return blocks, true, true
+		}
+
+		visited[parentName] = true
+
+		parent, parentFound, parentKnown := r.load(parentName)
+		if !parentFound || !parentKnown {
+			return nil, true, true
+		}
+
+		template = parent
+	}
+}
+
+func (r *twigTemplateResolver) load(name string) (*twigTemplate, bool, bool) {
+	if !strings.HasPrefix(name, "@") || !strings.Contains(name, "/") {
+		return nil, false, false
+	}
+
+	namespace, templatePath, _ := strings.Cut(name[1:], "/")
+
+	viewsDir, ok := r.namespaces[namespace]
+	if !ok {
+		return nil, false, false
+	}
+
+	if template, ok := r.cache[name]; ok {
+		return template, template != nil, true
+	}
+
+	content, err := os.ReadFile(filepath.Join(viewsDir, filepath.FromSlash(templatePath)))
+	if err != nil {
+		r.cache[name] = nil
+		return nil, false, true
+	}
+
+	template := scanTwigTemplate(string(content))
+	r.cache[name] = template
+
+	return template, true, true
+}
+
+// findStorefrontViews looks for the Storefront templates installed by composer in the extension or the surrounding project
+func findStorefrontViews(extensionPath string) string {
+	dir := extensionPath
+
+	for i := 0; i < 5; i++ {
+		for _, candidate := range []string{
+			filepath.Join(dir, "vendor", "shopware", "storefront", "Resources", "views"),
+			filepath.Join(dir, "vendor", "shopware", "platform", "src", "Storefront", "Resources", "views"),
+		} {
+			if stat, err := os.Stat(candidate); err == nil && stat.IsDir() {
+				return candidate
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+
+		dir = parent
+	}
+
+	return ""
+}
+
+// scanTwigTemplate checks that all tags are closed and properly nested and collects the blocks and the sw_extends target.
+// It does not parse expressions, Twig itself reports these at runtime.
+//
+//nolint:gocyclo
+func scanTwigTemplate(content string) *twigTemplate {
+	template := &twigTemplate{blocks: map[string]bool{}}
+
+	var stack []twigTag
+
+	lastOffset, lastLine := 0, 1
+
+	lineAt := func(offset int) int {
+		lastLine += strings.Count(content[lastOffset:offset], "\n")
+		lastOffset = offset
+
+		return lastLine
+	}
+
+	addError := func(line int, format string, args ...any) {
+		template.errors = append(template.errors, twigSyntaxError{line: line, message: fmt.Sprintf(format, args...)})
+	}
+
+	pos := 0
+
+	for pos < len(content) {
+		start := strings.Index(content[pos:], "{")
+		if start == -1 {
+			break
+		}
+
+		start += pos
+
+		if start+1 >= len(content) {
+			break
+		}
+
+		var closing string
+
+		switch content[start+1] {
+		case '#':
+			closing = "#}"
+		case '%':
+			closing = "%}"
+		case '{':
+			closing = "}}"
+		default:
+			pos = start + 1
+			continue
+		}
+
+		line := lineAt(start)
+
+		if closing == "#}" {
+			end := strings.Index(content[start+2:], closing)
+			if end == -1 {
+				addError(line, "unclosed comment")
+				break
+			}
+
+			pos = start + 2 + end + 2
+			continue
+		}
+
+		end := findTwigTagEnd(content, start+2, closing)
+		if end == -1 {
+			if closing == "}}" {
+				addError(line, "unclosed expression, missing }}")
+			} else {
+				addError(line, "unclosed tag, missing %%}")
+			}
+
+			break
+		}
+
+		pos = end + 2
+
+		if closing == "}}" {
+			continue
+		}
+
+		inner := strings.Trim(strings.TrimSpace(content[start+2:end]), "-~")
+		inner = strings.TrimSpace(inner)
+
+		name, args, _ := strings.Cut(inner, " ")
+		args = strings.TrimSpace(args)
+
+		if name == "" {
+			addError(line, "empty tag")
+			continue
+		}
+
+		tag := twigTag{name: name, args: args, line: line}
+
+		switch {
+		case name == "verbatim" || name == "raw":
+			rawEnd := twigRawEndRegExp.FindStringIndex(content[pos:])
+			if rawEnd == nil {
+				addError(line, "unclosed {%% %s %%}", name)
+				pos = len(content)
+				continue
+			}
+
+			pos += rawEnd[1]
+		case name == "sw_extends":
+			if template.extends != "" {
+				addError(line, "a template can only have one sw_extends")
+			}
+
+			template.extends = parseTwigExtendsTarget(args)
+			template.extendsLine = line
+		case name == "else":
+			if len(stack) == 0 || (stack[len(stack)-1].name != "if" && stack[len(stack)-1].name != "for") {
+				addError(line, "unexpected {%% else %%} outside of if or for")
+			}
+		case name == "elseif":
+			if len(stack) == 0 || stack[len(stack)-1].name != "if" {
+				addError(line, "unexpected {%% elseif %%} outside of if")
+			}
+		case strings.HasPrefix(name, "end"):
+			if !isTwigEndTag(name) {
+				continue
+			}
+
+			if len(stack) == 0 {
+				addError(line, "unexpected {%% %s %%}, there is no open tag", name)
+				continue
+			}
+
+			open := stack[len(stack)-1]
+			expected := twigPairedTags[open.name]
+
+			if name != expected {
+				addError(line, "unexpected {%% %s %%}, expected {%% %s %%} for the {%% %s %%} opened on line %d", name, expected, open.name, open.line)
+
+				// Recover when the end tag closes an outer tag, otherwise ignore it
+				for i := len(stack) - 2; i >= 0; i-- {
+					if twigPairedTags[stack[i].name] == name {
+						stack = stack[:i]
+						break
+					}
+				}
+
+				continue
+			}
+
+			stack = stack[:len(stack)-1]
+
+			if name == "endblock" && args != "" && args != strings.Fields(open.args)[0] {
+				addError(line, "the {%% endblock %s %%} does not match the block %s opened on line %d", args, strings.Fields(open.args)[0], open.line)
+			}
+		default:
+			if _, paired := twigPairedTags[name]; !paired {
+				continue
+			}
+
+			// Inline variants don't have an end tag
+			if name == "set" && strings.Contains(args, "=") {
+				continue
+			}
+
+			if name == "block" {
+				fields := strings.Fields(args)
+				if len(fields) == 0 {
+					addError(line, "the block tag needs a name")
+					continue
+				}
+
+				blockName := fields[0]
+
+				if template.blocks[blockName] {
+					addError(line, "the block %s is already defined", blockName)
+				}
+
+				template.blocks[blockName] = true
+
+				if !twigStackHasBlock(stack) {
+					template.topLevelBlocks = append(template.topLevelBlocks, twigTag{name: blockName, line: line})
+				}
+
+				// {% block title 'value' %} is a shorthand without end tag
+				if len(fields) > 1 {
+					continue
+				}
+			}
+
+			stack = append(stack, tag)
+		}
+	}
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		addError(stack[i].line, "unclosed {%% %s %%}, missing {%% %s %%}", stack[i].name, twigPairedTags[stack[i].name])
+	}
+
+	return template
+}
+
+// findTwigTagEnd returns the offset of the closing delimiter, skipping delimiters inside of strings
+func findTwigTagEnd(content string, offset int, closing string) int {
+	var quote byte
+
+	for i := offset; i < len(content)-1; i++ {
+		c := content[i]
+
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+
+			continue
+		}
+
+		if c == '\'' || c == '"' {
+			quote = c
+			continue
+		}
+
+		if content[i:i+2] == closing {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func isTwigEndTag(name string) bool {
+	for _, end := range twigPairedTags {
+		if end == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func twigStackHasBlock(stack []twigTag) bool {
+	for _, tag := range stack {
+		if tag.name == "block" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseTwigExtendsTarget returns the template of {% sw_extends '...' %} and {% sw_extends { template: '...' } %}
+func parseTwigExtendsTarget(args string) string {
+	if strings.HasPrefix(args, "{") {
+		_, rest, found := strings.Cut(args, "template:")
+		if !found {
+			return ""
+		}
+
+		args = strings.TrimSpace(rest)
+	}
+
+	if len(args) == 0 || (args[0] != '\'' && args[0] != '"') {
+		// Dynamic targets can't be checked
+		return ""
+	}
+
+	end := strings.IndexByte(args[1:], args[0])
+	if end == -1 {
+		return ""
+	}
+
+	return args[1 : end+1]
+}
@@ -0,0 +1,101 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanTwigTemplateValid(t *testing.T) {
+	template := scanTwigTemplate(`{% sw_extends '@Storefront/storefront/base.html.twig' %}
+
+{% block base_header %}
+    {% if page.header %}
+        {{ parent() }}
+    {% else %}
+        {% set title %}{{ "}}"|trans }}{% endset %}
+        {% block base_header_title title %}
+    {% endif %}
+    {# {% if %} in a comment #}
+    {% verbatim %}{% if {% endverbatim %}
+{%- endblock base_header -%}
+`)
+
+	assert.Empty(t, template.errors)
+	assert.Equal(t, "@Storefront/storefront/base.html.twig", template.extends)
+	assert.Len(t, template.topLevelBlocks, 1)
+	assert.Equal(t, "base_header", template.topLevelBlocks[0].name)
+	assert.True(t, template.blocks["base_header_title"])
+}
+
+func TestScanTwigTemplateErrors(t *testing.T) {
+	template := scanTwigTemplate(`{% block a %}
+{% if foo %}
+{% endblock %}
+{% block a %}{% endblock %}
+{% endfor %}
+{% for item in items %}
+{{ item
+`)
+
+	messages := make([]string, 0, len(template.errors))
+	for _, err := range template.errors {
+		messages = append(messages, err.message)
+	}
+
+	assert.Equal(t, []string{
+		"unexpected {% endblock %}, expected {% endif %} for the {% if %} opened on line 2",
+		"the block a is already defined",
+		"unexpected {% endfor %}, there is no open tag",
+		"unclosed expression, missing }}",
+		"unclosed {% for %}, missing {% endfor %}",
+	}, messages)
+	assert.Equal(t, 7, template.errors[3].line)
+}
+
+func TestParseTwigExtendsTarget(t *testing.T) {
+	assert.Equal(t, "@Storefront/storefront/base.html.twig", parseTwigExtendsTarget(`'@Storefront/storefront/base.html.twig'`))
+	assert.Equal(t, "@Storefront/storefront/base.html.twig", parseTwigExtendsTarget(`{ template: "@Storefront/storefront/base.html.twig", scopes: ['default'] }`))
+	assert.Equal(t, "", parseTwigExtendsTarget(`templateName`))
+}
+
+func TestValidateTwigTemplatesByPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	storefront := filepath.Join(tmpDir, "storefront")
+	views := filepath.Join(tmpDir, "Resources", "views")
+
+	_ = os.MkdirAll(filepath.Join(storefront, "storefront", "page"), os.ModePerm)
+	_ = os.MkdirAll(filepath.Join(views, "storefront", "page"), os.ModePerm)
+
+	_ = os.WriteFile(filepath.Join(storefront, "storefront", "base.html.twig"), []byte(`{% block base_body %}{% block base_main %}{% endblock %}{% endblock %}`), os.ModePerm)
+	_ = os.WriteFile(filepath.Join(storefront, "storefront", "page", "index.html.twig"), []byte(`{% sw_extends '@Storefront/storefront/base.html.twig' %}{% block page_content %}{% endblock %}`), os.ModePerm)
+
+	_ = os.WriteFile(filepath.Join(views, "storefront", "page", "index.html.twig"), []byte(`{% sw_extends '@Storefront/storefront/page/index.html.twig' %}
+{% block base_main %}{% block my_new_block %}{% endblock %}{% endblock %}
+{% block page_content %}{% endblock %}
+{% block page_removed %}{% endblock %}
+`), os.ModePerm)
+	_ = os.WriteFile(filepath.Join(views, "storefront", "page", "missing.html.twig"), []byte(`{% sw_extends '@Storefront/storefront/page/missing.html.twig' %}`), os.ModePerm)
+	_ = os.WriteFile(filepath.Join(views, "storefront", "page", "other.html.twig"), []byte(`{% sw_extends '@OtherPlugin/storefront/page/other.html.twig' %}{% block foo %}{% endblock %}`), os.ModePerm)
+
+	context := newValidationContext(PlatformPlugin{
+		path:   tmpDir,
+		config: &Config{},
+	})
+
+	resolver := twigTemplateResolver{
+		namespaces: map[string]string{"Storefront": storefront},
+		cache:      map[string]*twigTemplate{},
+	}
+
+	validateTwigTemplatesByPath(views, tmpDir, &resolver, context)
+
+	assert.Equal(t, []ValidationMessage{
+		{Identifier: "twig.missing_extends_target", Message: "Resources/views/storefront/page/missing.html.twig:1: the template @Storefront/storefront/page/missing.html.twig extended by sw_extends does not exist", File: "Resources/views/storefront/page/missing.html.twig", Line: 1},
+	}, context.errors)
+	assert.Equal(t, []ValidationMessage{
+		{Identifier: "twig.unknown_block", Message: "Resources/views/storefront/page/index.html.twig:4: the block page_removed does not exist in @Storefront/storefront/page/index.html.twig and will never be rendered", File: "Resources/views/storefront/page/index.html.twig", Line: 4},
+	}, context.warnings)
+}
@@ -3,7 +3,6 @@ package extension
 import (
 	"fmt"
 	"image"
-	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,17 +10,42 @@ import (
 	"golang.org/x/net/context"
 
 	"github.com/shopware/shopware-cli/internal/spdx"
+	"github.com/shopware/shopware-cli/internal/system"
 )
 
 type ValidationMessage struct {
 	Identifier string
 	Message    string
+	// File and Line locate the finding, the file is relative to the root dir of the extension. The message starts
+	// with the location too, so ignores matching on the message keep working.
+	File string
+	Line int
+}
+
+// fileLocation is a line in a file relative to the root dir of the extension, the line is 0 for the whole file
+type fileLocation struct {
+	file string
+	line int
+}
+
+func (l fileLocation) String() string {
+	if l.line == 0 {
+		return l.file
+	}
+
+	return fmt.Sprintf("%s:%d", l.file, l.line)
+}
+
+func (l fileLocation) message(id, message string) ValidationMessage {
+	return ValidationMessage{Identifier: id, Message: l.String() + ": " + message, File: l.file, Line: l.line}
 }
 
 type ValidationContext struct {
 	Extension Extension
 	errors    []ValidationMessage
 	warnings  []ValidationMessage
+
+	snippetUsage *snippetSources
 }
 
 func newValidationContext(ext Extension) *ValidationContext {
@@ -32,6 +56,11 @@ func (c *ValidationContext) AddError(id, message string) {
 	c.errors = append(c.errors, ValidationMessage{Identifier: id, Message: message})
 }
 
+// AddErrorAt adds an error found at the given location
+func (c *ValidationContext) AddErrorAt(id string, location fileLocation, message string) {
+	c.errors = append(c.errors, location.message(id, message))
+}
+
 func (c *ValidationContext) HasErrors() bool {
 	return len(c.errors) > 0
 }
@@ -44,6 +73,11 @@ func (c *ValidationContext) AddWarning(id, message string) {
 	c.warnings = append(c.warnings, ValidationMessage{Identifier: id, Message: message})
 }
 
+// AddWarningAt adds a warning found at the given location
+func (c *ValidationContext) AddWarningAt(id string, location fileLocation, message string) {
+	c.warnings = append(c.warnings, location.message(id, message))
+}
+
 func (c *ValidationContext) HasWarnings() bool {
 	return len(c.warnings) > 0
 }
@@ -121,13 +155,33 @@ func validateExtensionIcon(ctx *ValidationContext) {
 	}
 }
 
-func RunValidation(ctx context.Context, ext Extension) *ValidationContext {
+// ValidationOptions configures the checks of RunValidation
+type ValidationOptions struct {
+	// RunExternalValidators executes the commands of validation.external. They are part of the extension, so they
+	// should only run for trusted extensions like the own folder.
+	RunExternalValidators bool
+}
+
+func RunValidation(ctx context.Context, ext Extension, opts ValidationOptions) *ValidationContext {
 	vc := newValidationContext(ext)
 
 	runDefaultValidate(vc)
 	ext.Validate(ctx, vc)
+	validateChangelog(vc)
 	validateAdministrationSnippets(vc)
 	validateStorefrontSnippets(vc)
+	validateMachineTranslations(vc)
+	validateTwigTemplates(vc)
+	validateConfigXML(vc)
+	validateServicesXML(ctx, vc)
+	validateMigrations(vc)
+	validateBuiltAssets(ctx, vc)
+	validateDependencyLicenses(vc)
+	validateComposerAudit(ctx, vc)
+	validateShopwareVersionSupport(ctx, vc)
+	validateDeprecatedAPIUsage(ctx, vc)
+	validateAdminComponents(ctx, vc)
+	validateExternal(ctx, vc, opts.RunExternalValidators)
 	vc.ApplyIgnores(ext.GetExtensionConfig().Validation.Ignore)
 
 	return vc
@@ -156,7 +210,12 @@ func runDefaultValidate(vc *ValidationContext) {
 	}
 
 	notAllowedErrorFormat := "file %s is not allowed in the zip file"
-	_ = filepath.Walk(vc.Extension.GetPath(), func(p string, info fs.FileInfo, _ error) error {
+
+	// Files ignored by git never end up in the zip, so there is no need to descend into them
+	entries, _ := system.Walk(vc.Extension.GetPath(), system.WalkOptions{ExcludeDirs: []string{".git"}, Gitignore: true})
+
+	for _, entry := range entries {
+		p := filepath.Join(vc.Extension.GetPath(), filepath.FromSlash(entry.Path))
 		base := filepath.Base(p)
 
 		if base == ".." {
@@ -180,27 +239,25 @@ func runDefaultValidate(vc *ValidationContext) {
 				vc.AddError("zip.disallowed_file", fmt.Sprintf(notAllowedErrorFormat, p))
 			}
 		}
+	}
 
-		license, err := vc.Extension.GetLicense()
+	license, err := vc.Extension.GetLicense()
 
+	if err != nil {
+		vc.AddError("metadata.license", fmt.Sprintf("Could not read the license of the extension: %s", err.Error()))
+	} else if strings.TrimSpace(strings.ToLower(license)) != "proprietary" {
+		spdxList, err := spdx.NewSpdxLicenses()
 		if err != nil {
-			vc.AddError("metadata.license", fmt.Sprintf("Could not read the license of the extension: %s", err.Error()))
-		} else if strings.TrimSpace(strings.ToLower(license)) != "proprietary" {
-			spdxList, err := spdx.NewSpdxLicenses()
+			vc.AddWarning("metadata.license", fmt.Sprintf("Could not load the SPDX license list: %s", err.Error()))
+		} else {
+			valid, err := spdxList.Validate(license)
 			if err != nil {
-				vc.AddWarning("metadata.license", fmt.Sprintf("Could not load the SPDX license list: %s", err.Error()))
-			} else {
-				valid, err := spdxList.Validate(license)
-				if err != nil {
-					vc.AddError("metadata.license", fmt.Sprintf("Could not validate the license: %s", err.Error()))
-				} else if !valid {
-					vc.AddError("metadata.license", fmt.Sprintf("The license %s is not a valid SPDX license", license))
-				}
+				vc.AddError("metadata.license", fmt.Sprintf("Could not validate the license: %s", err.Error()))
+			} else if !valid {
+				vc.AddError("metadata.license", fmt.Sprintf("The license %s is not a valid SPDX license", license))
 			}
 		}
-
-		return nil
-	})
+	}
 
 	metaData := vc.Extension.GetMetaData()
 	if len([]rune(metaData.Label.German)) == 0 {
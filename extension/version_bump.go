@@ -0,0 +1,151 @@
+package extension
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/shyim/go-version"
+)
+
+var manifestMetaVersionRegExp = regexp.MustCompile(`(?s)(<meta>.*?<version>)\s*[^<]*?\s*(</version>)`)
+
+// NextVersion returns the version after current for patch, minor or major, anything else is used as the new version
+func NextVersion(current *version.Version, bump string) (*version.Version, error) {
+	segments := current.Segments()
+
+	switch bump {
+	case "patch":
+		return version.NewVersion(fmt.Sprintf("%d.%d.%d", segments[0], segments[1], segments[2]+1))
+	case "minor":
+		return version.NewVersion(fmt.Sprintf("%d.%d.0", segments[0], segments[1]+1))
+	case "major":
+		return version.NewVersion(fmt.Sprintf("%d.0.0", segments[0]+1))
+	}
+
+	next, err := version.NewVersion(bump)
+	if err != nil {
+		return nil, fmt.Errorf("%q is neither patch, minor, major nor a version: %w", bump, err)
+	}
+
+	if !next.GreaterThan(current) {
+		return nil, fmt.Errorf("version %s must be greater than the current version %s", next, current)
+	}
+
+	return next, nil
+}
+
+// SetExtensionVersion writes the version into the composer.json or the manifest.xml of the extension folder
+// and keeps the formatting of the file. It returns the changed file.
+func SetExtensionVersion(ext Extension, newVersion string) (string, error) {
+	if ext.GetType() == TypePlatformApp {
+		manifestFile := filepath.Join(ext.GetPath(), "manifest.xml")
+
+		content, err := os.ReadFile(manifestFile)
+		if err != nil {
+			return "", err
+		}
+
+		loc := manifestMetaVersionRegExp.FindSubmatchIndex(content)
+		if loc == nil {
+			return "", fmt.Errorf("cannot find the version in %s", manifestFile)
+		}
+
+		updated := append([]byte{}, content[:loc[3]]...)
+		updated = append(updated, newVersion...)
+		updated = append(updated, content[loc[4]:]...)
+
+		return manifestFile, os.WriteFile(manifestFile, updated, os.ModePerm)
+	}
+
+	composerFile := filepath.Join(ext.GetPath(), "composer.json")
+
+	content, err := os.ReadFile(composerFile)
+	if err != nil {
+		return "", err
+	}
+
+	parsed, err := parseOrderedJSON(content)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse %s: %w", composerFile, err)
+	}
+
+	composer, ok := parsed.(*orderedObject)
+	if !ok {
+		return "", fmt.Errorf("%s does not contain an object", composerFile)
+	}
+
+	composer.set("version", newVersion)
+
+	return composerFile, os.WriteFile(composerFile, encodeOrderedJSON(composer, detectJSONIndent(content)), os.ModePerm)
+}
+
+// AddChangelogVersion inserts an empty entry for the version above the newest version of every changelog file.
+// Without changelog files, the English and German ones are created, as the store requires both. It returns the changed files.
+func AddChangelogVersion(extPath, newVersion string) ([]string, error) {
+	files, err := filepath.Glob(fmt.Sprintf("%s/CHANGELOG*.md", extPath))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		files = []string{filepath.Join(extPath, "CHANGELOG.md"), filepath.Join(extPath, "CHANGELOG_de-DE.md")}
+	}
+
+	changed := make([]string, 0, len(files))
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+		first, exists := -1, false
+
+		for i, line := range lines {
+			matches := changelogVersionHeadingRegExp.FindStringSubmatch(strings.TrimRight(line, " \t"))
+			if matches == nil {
+				continue
+			}
+
+			if first == -1 {
+				first = i
+			}
+
+			if matches[1] == newVersion {
+				exists = true
+				break
+			}
+		}
+
+		if exists {
+			continue
+		}
+
+		entry := []string{"# " + newVersion, "", "- ", ""}
+
+		var newLines []string
+
+		switch {
+		case len(content) == 0:
+			newLines = entry
+		case first == -1:
+			newLines = append(entry, lines...)
+		default:
+			newLines = append([]string{}, lines[:first]...)
+			newLines = append(newLines, entry...)
+			newLines = append(newLines, lines[first:]...)
+		}
+
+		if err := os.WriteFile(file, []byte(strings.Join(newLines, "\n")), os.ModePerm); err != nil {
+			return nil, err
+		}
+
+		changed = append(changed, file)
+	}
+
+	return changed, nil
+}
@@ -0,0 +1,103 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shyim/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextVersion(t *testing.T) {
+	current := version.Must(version.NewVersion("1.2.3"))
+
+	cases := map[string]string{
+		"patch": "1.2.4",
+		"minor": "1.3.0",
+		"major": "2.0.0",
+		"1.5.0": "1.5.0",
+	}
+
+	for bump, expected := range cases {
+		next, err := NextVersion(current, bump)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, next.String())
+	}
+
+	_, err := NextVersion(current, "1.0.0")
+	assert.ErrorContains(t, err, "must be greater")
+
+	_, err = NextVersion(current, "next")
+	assert.ErrorContains(t, err, "neither patch, minor, major nor a version")
+}
+
+func TestSetExtensionVersionKeepsComposerFormatting(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "composer.json"), []byte("{\n  \"name\": \"frosh/tools\",\n  \"version\": \"1.0.0\",\n  \"type\": \"shopware-platform-plugin\"\n}\n"), os.ModePerm))
+
+	file, err := SetExtensionVersion(PlatformPlugin{path: dir}, "1.1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "composer.json"), file)
+
+	content, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n  \"name\": \"frosh/tools\",\n  \"version\": \"1.1.0\",\n  \"type\": \"shopware-platform-plugin\"\n}\n", string(content))
+}
+
+func TestSetExtensionVersionOfApp(t *testing.T) {
+	dir := t.TempDir()
+
+	manifest := `<?xml version="1.0" encoding="UTF-8"?>
+<manifest>
+    <!-- keep me -->
+    <meta>
+        <name>MyApp</name>
+        <version>1.0.0</version>
+    </meta>
+    <setup>
+        <version>ignored</version>
+    </setup>
+</manifest>
+`
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "manifest.xml"), []byte(manifest), os.ModePerm))
+
+	_, err := SetExtensionVersion(App{path: dir}, "2.0.0")
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(dir, "manifest.xml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "<!-- keep me -->")
+	assert.Contains(t, string(content), "<version>2.0.0</version>")
+	assert.Contains(t, string(content), "<version>ignored</version>")
+}
+
+func TestAddChangelogVersion(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "CHANGELOG.md"), []byte("# 1.0.0\n- Initial release\n"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "CHANGELOG_de-DE.md"), []byte("# 1.1.0\n\n- \n\n# 1.0.0\n- Erstveröffentlichung\n"), os.ModePerm))
+
+	changed, err := AddChangelogVersion(dir, "1.1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "CHANGELOG.md")}, changed)
+
+	content, err := os.ReadFile(filepath.Join(dir, "CHANGELOG.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "# 1.1.0\n\n- \n\n# 1.0.0\n- Initial release\n", string(content))
+}
+
+func TestAddChangelogVersionCreatesFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	changed, err := AddChangelogVersion(dir, "1.0.0")
+	assert.NoError(t, err)
+	assert.Len(t, changed, 2)
+
+	content, err := os.ReadFile(filepath.Join(dir, "CHANGELOG_de-DE.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "# 1.0.0\n\n- \n", string(content))
+}
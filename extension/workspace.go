@@ -0,0 +1,180 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WorkspaceFileName is the config of a repository containing several extensions
+const WorkspaceFileName = ".shopware-workspace.yml"
+
+// Workspace lists the extensions of a monorepo, so they can be built, zipped and validated together
+type Workspace struct {
+	// Root is the folder containing the workspace config
+	Root string `yaml:"-"`
+	// Extensions are the folders of the extensions relative to the workspace, glob patterns like plugins/* are allowed
+	Extensions []string `yaml:"extensions"`
+	// CacheDir is shared by the asset and vendor caches of all extensions, relative to the workspace
+	CacheDir string `yaml:"cache_dir,omitempty"`
+}
+
+// FindWorkspace looks for the workspace config in the folder and its parents
+func FindWorkspace(dir string) (*Workspace, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		file := filepath.Join(dir, WorkspaceFileName)
+
+		if _, err := os.Stat(file); err == nil {
+			return ReadWorkspace(file)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, fmt.Errorf("cannot find %s in %s or its parents", WorkspaceFileName, dir)
+		}
+
+		dir = parent
+	}
+}
+
+func ReadWorkspace(file string) (*Workspace, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace := &Workspace{}
+
+	if err := yaml.Unmarshal(content, workspace); err != nil {
+		return nil, fmt.Errorf("file: %s: %w", file, err)
+	}
+
+	if len(workspace.Extensions) == 0 {
+		return nil, fmt.Errorf("file: %s: extensions must contain at least one folder", file)
+	}
+
+	workspace.Root = filepath.Dir(file)
+
+	return workspace, nil
+}
+
+// ExtensionPaths resolves the configured folders to absolute paths. Glob matches without an extension are skipped,
+// a pattern matching no extension at all is an error.
+func (w *Workspace) ExtensionPaths() ([]string, error) {
+	var paths []string
+
+	for _, pattern := range w.Extensions {
+		matches, err := filepath.Glob(filepath.Join(w.Root, filepath.FromSlash(pattern)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid extension pattern %s: %w", pattern, err)
+		}
+
+		found := false
+
+		for _, match := range matches {
+			if stat, err := os.Stat(match); err != nil || !stat.IsDir() {
+				continue
+			}
+
+			if _, err := GetExtensionByFolder(match); err != nil {
+				continue
+			}
+
+			found = true
+
+			if !slices.Contains(paths, match) {
+				paths = append(paths, match)
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("the extension pattern %s of %s matches no extension", pattern, WorkspaceFileName)
+		}
+	}
+
+	slices.Sort(paths)
+
+	return paths, nil
+}
+
+// CacheDirectory returns the absolute cache folder, empty when the default cache of shopware-cli is used
+func (w *Workspace) CacheDirectory() string {
+	if w.CacheDir == "" || filepath.IsAbs(w.CacheDir) {
+		return w.CacheDir
+	}
+
+	return filepath.Join(w.Root, w.CacheDir)
+}
+
+// ChangedExtensionPaths returns the extensions with changes since the merge base of the ref, including uncommitted and untracked files
+func ChangedExtensionPaths(ctx context.Context, repo, ref string, paths []string) ([]string, error) {
+	topLevel, err := runWorkspaceGit(ctx, repo, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := runWorkspaceGit(ctx, repo, "diff", "--name-only", "--merge-base", ref)
+	if err != nil {
+		return nil, err
+	}
+
+	untracked, err := runWorkspaceGit(ctx, repo, "ls-files", "--others", "--exclude-standard", "--full-name")
+	if err != nil {
+		return nil, err
+	}
+
+	// git prints symlinks resolved, the extension paths have to be compared the same way
+	topLevel = resolveSymlinks(topLevel)
+
+	var changedFiles []string
+
+	for _, file := range strings.Split(changed+"\n"+untracked, "\n") {
+		if file = strings.TrimSpace(file); file != "" {
+			changedFiles = append(changedFiles, filepath.Join(topLevel, filepath.FromSlash(file)))
+		}
+	}
+
+	var result []string
+
+	for _, path := range paths {
+		resolved := resolveSymlinks(path)
+
+		if slices.ContainsFunc(changedFiles, func(file string) bool {
+			return strings.HasPrefix(file, resolved+string(filepath.Separator))
+		}) {
+			result = append(result, path)
+		}
+	}
+
+	return result, nil
+}
+
+func resolveSymlinks(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+
+	return path
+}
+
+func runWorkspaceGit(ctx context.Context, repo string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", repo}, args...)...)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w, %s", strings.Join(args, " "), err, output)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,96 @@
+package extension
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createWorkspacePlugin(t *testing.T, dir string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(dir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "composer.json"), []byte(`{"name": "frosh/`+filepath.Base(dir)+`", "type": "shopware-platform-plugin"}`), 0o644))
+}
+
+func runWorkspaceTestGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com", "GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+}
+
+func TestWorkspaceExtensionPaths(t *testing.T) {
+	root := t.TempDir()
+
+	createWorkspacePlugin(t, filepath.Join(root, "plugins", "FroshTools"))
+	createWorkspacePlugin(t, filepath.Join(root, "plugins", "FroshPlatformMailer"))
+	createWorkspacePlugin(t, filepath.Join(root, "apps", "FroshApp"))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "plugins", "docs"), os.ModePerm))
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, WorkspaceFileName), []byte("extensions:\n  - plugins/*\n  - apps/FroshApp\ncache_dir: .cache\n"), 0o644))
+
+	ws, err := FindWorkspace(filepath.Join(root, "plugins", "FroshTools"))
+	require.NoError(t, err)
+
+	assert.Equal(t, root, ws.Root)
+	assert.Equal(t, filepath.Join(root, ".cache"), ws.CacheDirectory())
+
+	paths, err := ws.ExtensionPaths()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		filepath.Join(root, "apps", "FroshApp"),
+		filepath.Join(root, "plugins", "FroshPlatformMailer"),
+		filepath.Join(root, "plugins", "FroshTools"),
+	}, paths)
+}
+
+func TestWorkspaceExtensionPathsWithoutMatch(t *testing.T) {
+	ws := &Workspace{Root: t.TempDir(), Extensions: []string{"plugins/*"}}
+
+	_, err := ws.ExtensionPaths()
+	assert.ErrorContains(t, err, "matches no extension")
+}
+
+func TestFindWorkspaceMissing(t *testing.T) {
+	_, err := FindWorkspace(t.TempDir())
+	assert.ErrorContains(t, err, WorkspaceFileName)
+}
+
+func TestChangedExtensionPaths(t *testing.T) {
+	root := t.TempDir()
+
+	tools := filepath.Join(root, "plugins", "FroshTools")
+	mailer := filepath.Join(root, "plugins", "FroshPlatformMailer")
+	app := filepath.Join(root, "plugins", "FroshApp")
+
+	createWorkspacePlugin(t, tools)
+	createWorkspacePlugin(t, mailer)
+	createWorkspacePlugin(t, app)
+
+	runWorkspaceTestGit(t, root, "init", "-q", "-b", "main")
+	runWorkspaceTestGit(t, root, "add", "-A")
+	runWorkspaceTestGit(t, root, "commit", "-q", "-m", "initial")
+	runWorkspaceTestGit(t, root, "checkout", "-q", "-b", "feature")
+
+	require.NoError(t, os.WriteFile(filepath.Join(tools, "README.md"), []byte("# FroshTools"), 0o644))
+	runWorkspaceTestGit(t, root, "add", "-A")
+	runWorkspaceTestGit(t, root, "commit", "-q", "-m", "readme")
+
+	// Untracked files count as a change as well
+	require.NoError(t, os.WriteFile(filepath.Join(app, "CHANGELOG.md"), []byte("# 1.0.0"), 0o644))
+
+	changed, err := ChangedExtensionPaths(t.Context(), root, "main", []string{app, mailer, tools})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{app, tools}, changed)
+}
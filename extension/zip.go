@@ -10,19 +10,23 @@ import (
 	"io"
 	"io/fs"
 	"log"
-	"net/http"
 	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/shyim/go-version"
 	"github.com/zeebo/xxh3"
 
 	"github.com/shopware/shopware-cli/internal/changelog"
+	"github.com/shopware/shopware-cli/internal/shopwareversion"
+	"github.com/shopware/shopware-cli/internal/system"
 	"github.com/shopware/shopware-cli/logging"
 )
 
@@ -35,6 +39,7 @@ var (
 		".gitlab-ci.yml",
 		".gitpod.Dockerfile",
 		".gitpod.yml",
+		MachineTranslationsFileName,
 		".php-cs-fixer.cache",
 		".php-cs-fixer.dist.php",
 		".php_cs.cache",
@@ -90,8 +95,26 @@ var (
 		".tar.gz",
 		".zip",
 	}
+
+	// These files are written by the operating system and never part of the zip
+	osMetadataFiles = []string{
+		".DS_Store",
+		"Thumbs.db",
+		"desktop.ini",
+		"__MACOSX",
+	}
 )
 
+// zipModTime is the modification time of all zip entries, so identical files result in a byte-identical zip.
+// SOURCE_DATE_EPOCH overrides it like in other reproducible builds.
+var zipModTime = sync.OnceValue(func() time.Time {
+	if epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+		return time.Unix(epoch, 0).UTC()
+	}
+
+	return time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+})
+
 func Unzip(r *zip.Reader, dest string) error {
 	errorFormat := "unzip: %w"
 
@@ -196,63 +219,40 @@ func GenerateChecksumJSON(ctx context.Context, baseFolder string, ext Extension)
 		ExtensionVersion: version.String(),
 	}
 
-	// Walk through all files in the folder and calculate checksums
-	err = filepath.Walk(baseFolder, func(path string, info fs.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+	// Walk through all files in the folder and calculate checksums, vendor and node_modules are never part of it
+	entries, err := system.Walk(baseFolder, system.WalkOptions{ExcludeDirs: []string{"vendor", "node_modules"}})
+	if err != nil {
+		return fmt.Errorf("walking directory for checksums: %w", err)
+	}
 
-		// Skip directories and hidden files
-		if info.IsDir() {
-			// Skip vendor and node_modules directories
-			if info.Name() == "vendor" || info.Name() == "node_modules" {
-				return filepath.SkipDir
-			}
-			return nil
+	for _, entry := range entries {
+		if entry.Entry.IsDir() {
+			continue
 		}
 
-		if strings.Contains(path, "Resources/public/administration") {
-			// Skip files in Resources/public/administration
-			return nil
-		}
+		relPath := entry.Path
 
-		// Get relative path for the file
-		relPath, err := filepath.Rel(baseFolder, path)
-		if err != nil {
-			return fmt.Errorf("get relative path: %w", err)
+		if strings.Contains(relPath, "Resources/public/administration") {
+			// Skip files in Resources/public/administration
+			continue
 		}
 
 		if slices.Contains(ignores, relPath) {
-			return nil
+			continue
 		}
 
 		// Skip checksum.json itself if it exists
 		if relPath == "checksum.json" {
-			return nil
-		}
-
-		// Skip vendor and node_modules files
-		if strings.Contains(relPath, "vendor/") || strings.Contains(relPath, "node_modules/") {
-			return nil
+			continue
 		}
 
 		// Calculate checksum
-		checksum, err := ChecksumFile(path)
+		checksum, err := ChecksumFile(filepath.Join(baseFolder, filepath.FromSlash(relPath)))
 		if err != nil {
-			return err
+			return fmt.Errorf("walking directory for checksums: %w", err)
 		}
 
-		// Normalize path separators to forward slashes for consistent output
-		relPath = filepath.ToSlash(relPath)
-
-		// Add to hashes map
 		checksumData.Hashes[relPath] = checksum
-
-		return nil
-	})
-
-	if err != nil {
-		return fmt.Errorf("walking directory for checksums: %w", err)
 	}
 
 	// Write checksum.json file
@@ -291,19 +291,51 @@ func CreateZip(baseFolder, zipFile string) error {
 }
 
 func AddZipFiles(w *zip.Writer, basePath, baseInZip string) error {
+	return addZipFiles(w, basePath, baseInZip, nil)
+}
+
+// addZipFiles adds the folder recursively, directories for which skipDir returns true are left out
+func addZipFiles(w *zip.Writer, basePath, baseInZip string, skipDir func(pathInZip string) bool) error {
+	return walkZipFiles(basePath, baseInZip, skipDir, func(sourcePath, zipPath string) error {
+		return addFileToZip(w, sourcePath, zipPath)
+	})
+}
+
+// ListZipFiles returns the paths of the files CreateZip would pack, in the order of the zip
+func ListZipFiles(baseFolder string) ([]string, error) {
+	var files []string
+
+	err := walkZipFiles(baseFolder, "", nil, func(_, zipPath string) error {
+		files = append(files, filepath.ToSlash(zipPath))
+		return nil
+	})
+
+	return files, err
+}
+
+func walkZipFiles(basePath, baseInZip string, skipDir func(pathInZip string) bool, fn func(sourcePath, zipPath string) error) error {
 	files, err := os.ReadDir(basePath)
 	if err != nil {
 		return fmt.Errorf("could not zip dir, basePath: %q, baseInZip: %q, %w", basePath, baseInZip, err)
 	}
 
+	// ReadDir sorts by name, so the order of the entries only depends on the files
 	for _, file := range files {
+		if isOSMetadataFile(file.Name()) {
+			continue
+		}
+
 		if file.IsDir() {
+			if skipDir != nil && skipDir(filepath.Join(baseInZip, file.Name())) {
+				continue
+			}
+
 			// Add files of directory recursively
-			if err = AddZipFiles(w, filepath.Join(basePath, file.Name()), filepath.Join(baseInZip, file.Name())); err != nil {
+			if err = walkZipFiles(filepath.Join(basePath, file.Name()), filepath.Join(baseInZip, file.Name()), skipDir, fn); err != nil {
 				return err
 			}
 		} else {
-			if err = addFileToZip(w, filepath.Join(basePath, file.Name()), filepath.Join(baseInZip, file.Name())); err != nil {
+			if err = fn(filepath.Join(basePath, file.Name()), filepath.Join(baseInZip, file.Name())); err != nil {
 				return err
 			}
 		}
@@ -312,6 +344,10 @@ func AddZipFiles(w *zip.Writer, basePath, baseInZip string) error {
 	return nil
 }
 
+func isOSMetadataFile(name string) bool {
+	return slices.Contains(osMetadataFiles, name) || strings.HasPrefix(name, "._")
+}
+
 func CleanupExtensionFolder(path string, additionalPaths []string) error {
 	defaultNotAllowedPaths = append(defaultNotAllowedPaths, additionalPaths...)
 
@@ -384,7 +420,8 @@ func PrepareFolderForZipping(ctx context.Context, path string, ext Extension, ex
 
 	shopware65Constraint, _ := version.NewConstraint(">=6.5.0")
 
-	if shopware65Constraint.Check(version.Must(version.NewVersion(minVersion))) {
+	// Shopware 6.5 installs the composer dependencies of extensions itself
+	if shopware65Constraint.Check(version.Must(version.NewVersion(minVersion))) && !extCfg.Build.Zip.Composer.BundleVendor {
 		return nil
 	}
 
@@ -396,6 +433,10 @@ func PrepareFolderForZipping(ctx context.Context, path string, ext Extension, ex
 
 	filtered := filterRequires(composer, extCfg)
 
+	if extCfg.Build.Zip.Composer.PlatformPHP != "" {
+		setComposerPlatformPHP(composer, extCfg.Build.Zip.Composer.PlatformPHP)
+	}
+
 	if len(filtered["require"].(map[string]interface{})) == 0 {
 		return nil
 	}
@@ -421,7 +462,7 @@ func PrepareFolderForZipping(ctx context.Context, path string, ext Extension, ex
 	}
 
 	// Execute composer in this directory
-	composerInstallCmd := exec.Command("composer", "install", "-d", path, "--no-dev", "-n", "-o")
+	composerInstallCmd := exec.Command("composer", composerInstallArgs(path, extCfg.Build.Zip.Composer)...)
 	composerInstallCmd.Stdout = os.Stdout
 	composerInstallCmd.Stderr = os.Stderr
 	err = composerInstallCmd.Run()
@@ -433,6 +474,15 @@ func PrepareFolderForZipping(ctx context.Context, path string, ext Extension, ex
 
 	_ = os.WriteFile(composerJSONPath, content, 0o644) //nolint:gosec
 
+	if extCfg.Build.Zip.Composer.RemoveDevFiles {
+		removed, err := removeVendorDevFiles(filepath.Join(path, "vendor"))
+		if err != nil {
+			return fmt.Errorf(errorFormat, err)
+		}
+
+		logging.FromContext(ctx).Infof("Removed %d development files from the vendor folder", removed)
+	}
+
 	return nil
 }
 
@@ -453,12 +503,19 @@ func addFileToZip(zipWriter *zip.Writer, sourcePath string, zipPath string) erro
 		return fmt.Errorf(zipErrorFormat, sourcePath, zipPath, err)
 	}
 
-	header, err := zip.FileInfoHeader(fileInfo)
-	if err != nil {
-		return fmt.Errorf(zipErrorFormat, sourcePath, zipPath, err)
+	// Only the content and the executable bit are kept, timestamps, owners and permissions of the build machine are dropped
+	header := &zip.FileHeader{
+		Name:     filepath.ToSlash(zipPath),
+		Method:   zip.Deflate,
+		Modified: zipModTime(),
+	}
+
+	mode := fs.FileMode(0o644)
+	if fileInfo.Mode()&0o111 != 0 {
+		mode = 0o755
 	}
-	header.Name = zipPath
-	header.Method = zip.Deflate
+
+	header.SetMode(mode)
 
 	f, err := zipWriter.CreateHeader(header)
 	if err != nil {
@@ -558,49 +615,14 @@ func addComposerReplacements(composer map[string]interface{}, minVersion string)
 	return composer, nil
 }
 
-type packagistResponse struct {
-	Packages struct {
-		Core []struct {
-			Version string `json:"version_normalized"`
-		} `json:"shopware/core"`
-	} `json:"packages"`
-}
-
+// GetShopwareVersions returns the released Shopware versions, they are refreshed once a day and the bundled versions are used offline
 func GetShopwareVersions(ctx context.Context) ([]string, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://repo.packagist.org/p2/shopware/core.json", http.NoBody)
+	metadata, err := shopwareversion.Load(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("create composer version request: %w", err)
-	}
-
-	req.Header.Set("User-Agent", "Shopware CLI")
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("fetch composer versions: %w", err)
-	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			logging.FromContext(ctx).Errorf("lookupForMinMatchingVersion: %v", err)
-		}
-	}()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("fetch composer versions: %s", resp.Status)
-	}
-
-	var pckResponse packagistResponse
-
-	var versions []string
-
-	if err := json.NewDecoder(resp.Body).Decode(&pckResponse); err != nil {
-		return nil, fmt.Errorf("decode composer versions: %w", err)
-	}
-
-	for _, v := range pckResponse.Packages.Core {
-		versions = append(versions, v.Version)
+		return nil, err
 	}
 
-	return versions, nil
+	return metadata.Versions(), nil
 }
 
 func lookupForMinMatchingVersion(ctx context.Context, versionConstraint *version.Constraints) (string, error) {
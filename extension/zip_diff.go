@@ -0,0 +1,291 @@
+package extension
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+)
+
+// zipDiffAssetDirs contain the compiled JavaScript and CSS of the Administration and the Storefront
+var zipDiffAssetDirs = []string{"Resources/public/", "Resources/app/storefront/dist/"}
+
+// ZipDiff describes what a zip ships compared to a previous zip of the extension
+type ZipDiff struct {
+	Added   []ZipDiffFile `json:"added"`
+	Removed []ZipDiffFile `json:"removed"`
+	Changed []ZipDiffFile `json:"changed"`
+	// Requires are the changed requirements of the composer.json
+	Requires []ZipDiffPackage `json:"requires"`
+	// Packages are the changed composer packages bundled in the vendor folder
+	Packages []ZipDiffPackage `json:"packages"`
+	// Assets are the compiled JavaScript and CSS files with a different size
+	Assets          []ZipDiffFile `json:"assets"`
+	AssetsSizeDelta int64         `json:"assetsSizeDelta"`
+}
+
+// ZipDiffFile is a file of the zips, the path is relative to the extension folder. Sizes are uncompressed.
+type ZipDiffFile struct {
+	Path    string `json:"path"`
+	OldSize int64  `json:"oldSize"`
+	NewSize int64  `json:"newSize"`
+}
+
+func (f ZipDiffFile) SizeDelta() int64 {
+	return f.NewSize - f.OldSize
+}
+
+// ZipDiffPackage is a composer package with an empty version when it does not exist in the zip
+type ZipDiffPackage struct {
+	Name       string `json:"name"`
+	OldVersion string `json:"oldVersion"`
+	NewVersion string `json:"newVersion"`
+}
+
+// HasChanges returns false when both zips contain the same files
+func (d *ZipDiff) HasChanges() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Changed) > 0
+}
+
+type zipDiffEntry struct {
+	file *zip.File
+	size int64
+	crc  uint32
+}
+
+// DiffZips compares two zips of an extension. The folder of the extension in the zip is ignored, so renamed extensions can be compared.
+func DiffZips(oldZip, newZip string) (*ZipDiff, error) {
+	oldReader, err := zip.OpenReader(oldZip)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", oldZip, err)
+	}
+
+	defer func() { _ = oldReader.Close() }()
+
+	newReader, err := zip.OpenReader(newZip)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", newZip, err)
+	}
+
+	defer func() { _ = newReader.Close() }()
+
+	oldEntries := readZipDiffEntries(&oldReader.Reader)
+	newEntries := readZipDiffEntries(&newReader.Reader)
+
+	diff := &ZipDiff{
+		Added:    []ZipDiffFile{},
+		Removed:  []ZipDiffFile{},
+		Changed:  []ZipDiffFile{},
+		Requires: []ZipDiffPackage{},
+		Packages: []ZipDiffPackage{},
+		Assets:   []ZipDiffFile{},
+	}
+
+	for _, name := range sortedZipDiffPaths(oldEntries, newEntries) {
+		oldEntry, inOld := oldEntries[name]
+		newEntry, inNew := newEntries[name]
+
+		file := ZipDiffFile{Path: name}
+
+		if inOld {
+			file.OldSize = oldEntry.size
+		}
+
+		if inNew {
+			file.NewSize = newEntry.size
+		}
+
+		switch {
+		case !inOld:
+			diff.Added = append(diff.Added, file)
+		case !inNew:
+			diff.Removed = append(diff.Removed, file)
+		case oldEntry.crc != newEntry.crc || oldEntry.size != newEntry.size:
+			diff.Changed = append(diff.Changed, file)
+		}
+
+		if isZipDiffAsset(name) && file.OldSize != file.NewSize {
+			diff.Assets = append(diff.Assets, file)
+			diff.AssetsSizeDelta += file.SizeDelta()
+		}
+	}
+
+	oldRequires, err := readZipComposerRequires(oldEntries["composer.json"])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", oldZip, err)
+	}
+
+	newRequires, err := readZipComposerRequires(newEntries["composer.json"])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", newZip, err)
+	}
+
+	diff.Requires = diffZipPackages(oldRequires, newRequires)
+
+	oldPackages, err := readZipInstalledPackages(oldEntries["vendor/composer/installed.json"])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", oldZip, err)
+	}
+
+	newPackages, err := readZipInstalledPackages(newEntries["vendor/composer/installed.json"])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", newZip, err)
+	}
+
+	diff.Packages = diffZipPackages(oldPackages, newPackages)
+
+	return diff, nil
+}
+
+// readZipDiffEntries returns the files of the zip by their path without the extension folder
+func readZipDiffEntries(r *zip.Reader) map[string]zipDiffEntry {
+	entries := make(map[string]zipDiffEntry)
+
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		name := f.Name
+
+		if _, rest, found := strings.Cut(name, "/"); found {
+			name = rest
+		}
+
+		entries[name] = zipDiffEntry{file: f, size: int64(f.UncompressedSize64), crc: f.CRC32}
+	}
+
+	return entries
+}
+
+func sortedZipDiffPaths(oldEntries, newEntries map[string]zipDiffEntry) []string {
+	paths := make([]string, 0, len(oldEntries)+len(newEntries))
+
+	for name := range oldEntries {
+		paths = append(paths, name)
+	}
+
+	for name := range newEntries {
+		if _, ok := oldEntries[name]; !ok {
+			paths = append(paths, name)
+		}
+	}
+
+	slices.Sort(paths)
+
+	return paths
+}
+
+func isZipDiffAsset(name string) bool {
+	ext := path.Ext(name)
+	if ext != ".js" && ext != ".css" {
+		return false
+	}
+
+	for _, dir := range zipDiffAssetDirs {
+		if strings.Contains(name, dir) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func readZipDiffJSON(entry zipDiffEntry, target any) error {
+	r, err := entry.file.Open()
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = r.Close() }()
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(content, target); err != nil {
+		return fmt.Errorf("parse %s: %w", entry.file.Name, err)
+	}
+
+	return nil
+}
+
+func readZipComposerRequires(entry zipDiffEntry) (map[string]string, error) {
+	if entry.file == nil {
+		return map[string]string{}, nil
+	}
+
+	var composer struct {
+		Require map[string]string `json:"require"`
+	}
+
+	if err := readZipDiffJSON(entry, &composer); err != nil {
+		return nil, err
+	}
+
+	return composer.Require, nil
+}
+
+// readZipInstalledPackages reads the installed.json of composer 1 (a list) and composer 2 (an object with packages)
+func readZipInstalledPackages(entry zipDiffEntry) (map[string]string, error) {
+	packages := map[string]string{}
+
+	if entry.file == nil {
+		return packages, nil
+	}
+
+	type installedPackage struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+
+	var installed struct {
+		Packages []installedPackage `json:"packages"`
+	}
+
+	if err := readZipDiffJSON(entry, &installed); err != nil {
+		var list []installedPackage
+
+		if listErr := readZipDiffJSON(entry, &list); listErr != nil {
+			return nil, err
+		}
+
+		installed.Packages = list
+	}
+
+	for _, pkg := range installed.Packages {
+		packages[pkg.Name] = pkg.Version
+	}
+
+	return packages, nil
+}
+
+func diffZipPackages(oldPackages, newPackages map[string]string) []ZipDiffPackage {
+	names := make([]string, 0, len(oldPackages)+len(newPackages))
+
+	for name := range oldPackages {
+		names = append(names, name)
+	}
+
+	for name := range newPackages {
+		if _, ok := oldPackages[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	changes := []ZipDiffPackage{}
+
+	for _, name := range names {
+		if oldPackages[name] != newPackages[name] {
+			changes = append(changes, ZipDiffPackage{Name: name, OldVersion: oldPackages[name], NewVersion: newPackages[name]})
+		}
+	}
+
+	return changes
+}
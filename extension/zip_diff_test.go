@@ -0,0 +1,96 @@
+package extension
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeDiffTestZip(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	zipFile := filepath.Join(t.TempDir(), "FroshTools.zip")
+
+	f, err := os.Create(zipFile)
+	require.NoError(t, err)
+
+	w := zip.NewWriter(f)
+
+	for name, content := range files {
+		entry, err := w.Create("FroshTools/" + name)
+		require.NoError(t, err)
+
+		_, err = entry.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, w.Close())
+	require.NoError(t, f.Close())
+
+	return zipFile
+}
+
+func TestDiffZips(t *testing.T) {
+	oldZip := writeDiffTestZip(t, map[string]string{
+		"composer.json":                  `{"require": {"shopware/core": "~6.5.0", "symfony/yaml": "*"}}`,
+		"src/FroshTools.php":             "<?php",
+		"src/Removed.php":                "<?php",
+		"src/Resources/public/admin.js":  "console.log(1)",
+		"vendor/composer/installed.json": `{"packages": [{"name": "league/csv", "version": "9.0.0"}, {"name": "psr/log", "version": "3.0.0"}]}`,
+	})
+
+	newZip := writeDiffTestZip(t, map[string]string{
+		"composer.json":                  `{"require": {"shopware/core": "~6.6.0", "league/csv": "^9.0"}}`,
+		"src/FroshTools.php":             "<?php",
+		"src/Added.php":                  "<?php",
+		"src/Resources/public/admin.js":  "console.log(1, 2, 3)",
+		"vendor/composer/installed.json": `[{"name": "league/csv", "version": "9.1.0"}]`,
+	})
+
+	diff, err := DiffZips(oldZip, newZip)
+	require.NoError(t, err)
+
+	assert.True(t, diff.HasChanges())
+	assert.Equal(t, []ZipDiffFile{{Path: "src/Added.php", NewSize: 5}}, diff.Added)
+	assert.Equal(t, []ZipDiffFile{{Path: "src/Removed.php", OldSize: 5}}, diff.Removed)
+	assert.Equal(t, []string{"composer.json", "src/Resources/public/admin.js", "vendor/composer/installed.json"}, zipDiffPaths(diff.Changed))
+
+	assert.Equal(t, []ZipDiffPackage{
+		{Name: "league/csv", NewVersion: "^9.0"},
+		{Name: "shopware/core", OldVersion: "~6.5.0", NewVersion: "~6.6.0"},
+		{Name: "symfony/yaml", OldVersion: "*"},
+	}, diff.Requires)
+
+	assert.Equal(t, []ZipDiffPackage{
+		{Name: "league/csv", OldVersion: "9.0.0", NewVersion: "9.1.0"},
+		{Name: "psr/log", OldVersion: "3.0.0"},
+	}, diff.Packages)
+
+	assert.Equal(t, []ZipDiffFile{{Path: "src/Resources/public/admin.js", OldSize: 14, NewSize: 20}}, diff.Assets)
+	assert.Equal(t, int64(6), diff.AssetsSizeDelta)
+}
+
+func TestDiffZipsWithoutChanges(t *testing.T) {
+	files := map[string]string{"composer.json": `{}`, "src/FroshTools.php": "<?php"}
+
+	diff, err := DiffZips(writeDiffTestZip(t, files), writeDiffTestZip(t, files))
+	require.NoError(t, err)
+
+	assert.False(t, diff.HasChanges())
+	assert.Empty(t, diff.Requires)
+	assert.Empty(t, diff.Packages)
+}
+
+func zipDiffPaths(files []ZipDiffFile) []string {
+	paths := make([]string, 0, len(files))
+
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+
+	return paths
+}
@@ -0,0 +1,130 @@
+package extension
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shopware/shopware-cli/internal/system"
+)
+
+// zipIgnoreFiles contain gitignore patterns of files which are not packed, relative to the extension root
+var zipIgnoreFiles = []string{".sw-zip-blacklist", ".zipignore"}
+
+// ZipIgnore matches paths against patterns with the semantics of .gitignore: negation with !, directory only patterns
+// with a trailing slash, patterns with a slash anchored to the root and ** matching any amount of folders
+type ZipIgnore struct {
+	gitignore *system.Gitignore
+}
+
+// NewZipIgnore parses the patterns, later patterns take precedence over earlier ones
+func NewZipIgnore(patterns []string) (*ZipIgnore, error) {
+	gitignore, err := system.NewGitignore(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ZipIgnore{gitignore: gitignore}, nil
+}
+
+// LoadZipIgnore reads the patterns of .sw-zip-blacklist and .zipignore of the extension followed by the additional patterns
+func LoadZipIgnore(extDir string, additionalPatterns []string) (*ZipIgnore, error) {
+	var patterns []string
+
+	for _, file := range zipIgnoreFiles {
+		lines, err := readZipIgnoreFile(filepath.Join(extDir, file))
+		if err != nil {
+			return nil, err
+		}
+
+		patterns = append(patterns, lines...)
+	}
+
+	return NewZipIgnore(append(patterns, additionalPatterns...))
+}
+
+func readZipIgnoreFile(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var lines []string
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	return lines, scanner.Err()
+}
+
+// Match reports whether the path relative to the extension root is ignored. Like git, files inside an ignored folder
+// cannot be included again by a negated pattern.
+func (z *ZipIgnore) Match(path string, isDir bool) bool {
+	path = strings.Trim(filepath.ToSlash(path), "/")
+
+	parts := strings.Split(path, "/")
+
+	for i := 1; i < len(parts); i++ {
+		if z.matchRules(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+
+	return z.matchRules(path, isDir)
+}
+
+func (z *ZipIgnore) matchRules(path string, isDir bool) bool {
+	return z.gitignore.Match(path, isDir)
+}
+
+// RemoveZipIgnoredFiles deletes the files of the extension folder matched by .sw-zip-blacklist, .zipignore or the patterns
+func RemoveZipIgnoredFiles(extDir string, patterns []string) error {
+	ignore, err := LoadZipIgnore(extDir, patterns)
+	if err != nil {
+		return err
+	}
+
+	if ignore.gitignore.Empty() {
+		return nil
+	}
+
+	return removeIgnored(extDir, "", ignore)
+}
+
+func removeIgnored(root, rel string, ignore *ZipIgnore) error {
+	entries, err := os.ReadDir(filepath.Join(root, rel))
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		entryRel := filepath.Join(rel, entry.Name())
+
+		if ignore.matchRules(filepath.ToSlash(entryRel), entry.IsDir()) {
+			if err := os.RemoveAll(filepath.Join(root, entryRel)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if entry.IsDir() {
+			if err := removeIgnored(root, entryRel, ignore); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
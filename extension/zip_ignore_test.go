@@ -0,0 +1,92 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZipIgnoreMatch(t *testing.T) {
+	ignore, err := NewZipIgnore([]string{
+		"# comment",
+		"*.log",
+		"!important.log",
+		"/docs",
+		"build/",
+		"src/Resources/app/**/*.spec.js",
+		"**/fixtures",
+		"cache/*",
+		"!cache/keep",
+		"node_modules/",
+		"!node_modules/keep.js",
+		`\#notes.md`,
+	})
+	require.NoError(t, err)
+
+	cases := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{"debug.log", false, true},
+		{"src/debug.log", false, true},
+		{"src/important.log", false, false},
+		{"docs", true, true},
+		{"docs/index.md", false, true},
+		{"src/docs", true, false},
+		{"build", true, true},
+		{"build", false, false},
+		{"src/build/file.php", false, true},
+		{"src/Resources/app/administration/src/main.spec.js", false, true},
+		{"src/Resources/app/main.spec.js", false, true},
+		{"src/Resources/app/administration/src/main.js", false, false},
+		{"fixtures", true, true},
+		{"tests/unit/fixtures/data.json", false, true},
+		{"cache", true, false},
+		{"cache/file", false, true},
+		{"cache/keep", false, false},
+		{"node_modules/keep.js", false, true},
+		{"#notes.md", false, true},
+		{"composer.json", false, false},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.ignored, ignore.Match(c.path, c.isDir), c.path)
+	}
+}
+
+func TestRemoveZipIgnoredFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		".zipignore":         "*.md\n!README.md\nsrc/Resources/app/administration/src/\n",
+		".sw-zip-blacklist":  "docs\n",
+		"README.md":          "",
+		"CHANGELOG.md":       "",
+		"docs/index.html":    "",
+		"src/FroshTools.php": "",
+		"src/Resources/app/administration/src/main.js":    "",
+		"src/Resources/public/administration/js/tools.js": "",
+		"src/Resources/config/services.yml":               "",
+		"src/Resources/config/debug.yml":                  "",
+	}
+
+	for file, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, file)), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(content), 0o644))
+	}
+
+	require.NoError(t, RemoveZipIgnoredFiles(dir, []string{"debug.yml"}))
+
+	for _, removed := range []string{"CHANGELOG.md", "docs", "src/Resources/app/administration/src", "src/Resources/config/debug.yml"} {
+		assert.NoFileExists(t, filepath.Join(dir, removed))
+		assert.NoDirExists(t, filepath.Join(dir, removed))
+	}
+
+	for _, kept := range []string{"README.md", "src/FroshTools.php", "src/Resources/public/administration/js/tools.js", "src/Resources/config/services.yml"} {
+		assert.FileExists(t, filepath.Join(dir, kept))
+	}
+}
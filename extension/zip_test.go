@@ -1,10 +1,12 @@
 package extension
 
 import (
+	"archive/zip"
 	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/shyim/go-version"
 	"github.com/stretchr/testify/assert"
@@ -183,3 +185,90 @@ func TestGenerateChecksumJSONIgnores(t *testing.T) {
 	assert.Contains(t, checksum.Hashes, "composer.json", "composer.json should be in the checksum list")
 	assert.NotContains(t, checksum.Hashes, "src/Resources/test.txt", "src/Resources/test.txt should be in the checksum list")
 }
+
+func writeReproducibleZipFixture(t *testing.T, modTime time.Time, fileMode os.FileMode) string {
+	t.Helper()
+
+	base := t.TempDir()
+	extDir := filepath.Join(base, "FroshTools")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(extDir, "src", "Resources"), os.ModePerm))
+	require.NoError(t, os.MkdirAll(filepath.Join(extDir, "bin"), os.ModePerm))
+
+	files := map[string]os.FileMode{
+		"composer.json":            fileMode,
+		"src/FroshTools.php":       fileMode,
+		"src/Resources/config.xml": fileMode,
+		"src/.DS_Store":            fileMode,
+		"src/._FroshTools.php":     fileMode,
+		"bin/console":              0o700,
+	}
+
+	for file, mode := range files {
+		path := filepath.Join(extDir, filepath.FromSlash(file))
+
+		require.NoError(t, os.WriteFile(path, []byte("content of "+file), mode))
+		require.NoError(t, os.Chmod(path, mode))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	return base
+}
+
+func TestCreateZipIsReproducible(t *testing.T) {
+	first := filepath.Join(t.TempDir(), "first.zip")
+	second := filepath.Join(t.TempDir(), "second.zip")
+
+	require.NoError(t, CreateZip(writeReproducibleZipFixture(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), 0o600), first))
+	require.NoError(t, CreateZip(writeReproducibleZipFixture(t, time.Date(2025, 1, 2, 8, 30, 0, 0, time.UTC), 0o664), second))
+
+	firstContent, err := os.ReadFile(first)
+	require.NoError(t, err)
+
+	secondContent, err := os.ReadFile(second)
+	require.NoError(t, err)
+
+	assert.Equal(t, firstContent, secondContent)
+
+	r, err := zip.OpenReader(first)
+	require.NoError(t, err)
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	names := make([]string, 0, len(r.File))
+
+	for _, f := range r.File {
+		names = append(names, f.Name)
+
+		assert.True(t, f.Modified.Equal(zipModTime()), f.Name)
+
+		if f.Name == "FroshTools/bin/console" {
+			assert.Equal(t, os.FileMode(0o755), f.Mode(), f.Name)
+		} else {
+			assert.Equal(t, os.FileMode(0o644), f.Mode(), f.Name)
+		}
+	}
+
+	assert.Equal(t, []string{
+		"FroshTools/bin/console",
+		"FroshTools/composer.json",
+		"FroshTools/src/FroshTools.php",
+		"FroshTools/src/Resources/config.xml",
+	}, names)
+}
+
+func TestListZipFilesMatchesZip(t *testing.T) {
+	base := writeReproducibleZipFixture(t, time.Now(), 0o644)
+
+	files, err := ListZipFiles(base)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"FroshTools/bin/console",
+		"FroshTools/composer.json",
+		"FroshTools/src/FroshTools.php",
+		"FroshTools/src/Resources/config.xml",
+	}, files)
+}
@@ -0,0 +1,229 @@
+package extension
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/zeebo/xxh3"
+
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// keptVendorLayers is the amount of cached vendor layers kept per extension, older ones are removed
+const keptVendorLayers = 3
+
+// vendorLayerVersion is part of the key, increase it when the zip entries are written differently
+const vendorLayerVersion = "3"
+
+// CreateZipWithVendorLayer creates the zip like CreateZip, but the vendor folder of the extension is copied already compressed
+// from a cached layer. The layer is only compressed again when composer.lock or the content of vendor changes.
+func CreateZipWithVendorLayer(ctx context.Context, baseFolder, zipFile, extName, cacheDir string) error {
+	extDir := filepath.Join(baseFolder, extName)
+	vendorInZip := filepath.Join(extName, "vendor")
+
+	if _, err := os.Stat(filepath.Join(extDir, "vendor")); os.IsNotExist(err) {
+		return CreateZip(baseFolder, zipFile)
+	}
+
+	layerFile, err := vendorLayer(ctx, extDir, extName, vendorInZip, cacheDir)
+	if err != nil {
+		return fmt.Errorf("vendor layer: %w", err)
+	}
+
+	outFile, err := os.Create(zipFile)
+	if err != nil {
+		return fmt.Errorf("create zipfile: %w", err)
+	}
+
+	defer func() {
+		_ = outFile.Close()
+	}()
+
+	w := zip.NewWriter(outFile)
+
+	if err := addZipFiles(w, baseFolder, "", func(pathInZip string) bool { return pathInZip == vendorInZip }); err != nil {
+		return err
+	}
+
+	layer, err := zip.OpenReader(layerFile)
+	if err != nil {
+		return fmt.Errorf("open vendor layer: %w", err)
+	}
+
+	defer func() {
+		_ = layer.Close()
+	}()
+
+	for _, file := range layer.File {
+		// Copy takes over the compressed data, so the vendor files are not compressed again
+		if err := w.Copy(file); err != nil {
+			return fmt.Errorf("copy %s from vendor layer: %w", file.Name, err)
+		}
+	}
+
+	return w.Close()
+}
+
+// vendorLayer returns the cached zip of the vendor folder and creates it when there is none for the current dependencies
+func vendorLayer(ctx context.Context, extDir, extName, vendorInZip, cacheDir string) (string, error) {
+	key, err := vendorLayerKey(extDir)
+	if err != nil {
+		return "", err
+	}
+
+	layerFile := filepath.Join(cacheDir, fmt.Sprintf("%s-%s.zip", extName, key))
+
+	if _, err := os.Stat(layerFile); err == nil {
+		logging.FromContext(ctx).Infof("Using cached vendor layer %s", layerFile)
+
+		// Mark the layer as recently used, so it is not pruned
+		now := time.Now()
+		_ = os.Chtimes(layerFile, now, now)
+
+		return layerFile, nil
+	}
+
+	logging.FromContext(ctx).Infof("Compressing vendor layer, it will be reused until composer.lock changes")
+
+	if err := os.MkdirAll(cacheDir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	tmpFile, err := os.CreateTemp(cacheDir, extName+"-*.zip.tmp")
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	w := zip.NewWriter(tmpFile)
+
+	if err := AddZipFiles(w, filepath.Join(extDir, "vendor"), vendorInZip); err != nil {
+		_ = tmpFile.Close()
+		return "", err
+	}
+
+	if err := w.Close(); err != nil {
+		_ = tmpFile.Close()
+		return "", err
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmpFile.Name(), layerFile); err != nil {
+		return "", err
+	}
+
+	pruneVendorLayers(ctx, cacheDir, extName)
+
+	return layerFile, nil
+}
+
+// vendorLayerKey hashes composer.lock and the content of the vendor folder. The lock alone is not enough, the generated
+// autoload files and PHP-Scoper prefixes change without it, as well as hooks editing vendor.
+func vendorLayerKey(extDir string) (string, error) {
+	hasher := xxh3.New()
+
+	_, _ = hasher.WriteString(vendorLayerVersion + "\n")
+
+	lock, err := os.ReadFile(filepath.Join(extDir, "composer.lock"))
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	_, _ = hasher.Write(lock)
+
+	vendorDir := filepath.Join(extDir, "vendor")
+
+	err = filepath.WalkDir(vendorDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(vendorDir, path)
+		if err != nil {
+			return err
+		}
+
+		var content uint64
+
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+
+			content = xxh3.HashString(target)
+		case info.Mode().IsRegular():
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			content = xxh3.Hash(data)
+		}
+
+		_, _ = fmt.Fprintf(hasher, "%s:%s:%x\n", filepath.ToSlash(rel), info.Mode(), content)
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := hasher.Sum128().Bytes()
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func pruneVendorLayers(ctx context.Context, cacheDir, extName string) {
+	layers, err := filepath.Glob(filepath.Join(cacheDir, extName+"-*.zip"))
+	if err != nil {
+		return
+	}
+
+	// The key has 32 hex characters, this keeps layers of extensions with a longer name sharing the prefix
+	layers = slices.DeleteFunc(layers, func(layer string) bool {
+		return len(filepath.Base(layer)) != len(extName)+len("-.zip")+32
+	})
+
+	if len(layers) <= keptVendorLayers {
+		return
+	}
+
+	modTime := func(file string) time.Time {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}
+		}
+
+		return info.ModTime()
+	}
+
+	slices.SortFunc(layers, func(a, b string) int {
+		return modTime(b).Compare(modTime(a))
+	})
+
+	for _, layer := range layers[keptVendorLayers:] {
+		if err := os.Remove(layer); err != nil {
+			logging.FromContext(ctx).Warnf("Cannot remove old vendor layer %s: %v", layer, err)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package extension
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeVendorLayerFixture(t *testing.T, base string, lock string) {
+	t.Helper()
+
+	extDir := filepath.Join(base, "FroshTools")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(extDir, "vendor", "acme", "lib"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(extDir, "composer.json"), []byte(`{"name": "frosh/tools"}`), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(extDir, "composer.lock"), []byte(lock), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(extDir, "vendor", "autoload.php"), []byte("<?php"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(extDir, "vendor", "acme", "lib", "Lib.php"), []byte("<?php class Lib {}"), os.ModePerm))
+}
+
+func readZipEntries(t *testing.T, file string) map[string]string {
+	t.Helper()
+
+	r, err := zip.OpenReader(file)
+	require.NoError(t, err)
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	entries := make(map[string]string)
+
+	for _, f := range r.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+
+		entries[f.Name] = string(content)
+	}
+
+	return entries
+}
+
+func TestCreateZipWithVendorLayer(t *testing.T) {
+	base := t.TempDir()
+	cacheDir := t.TempDir()
+	zipFile := filepath.Join(t.TempDir(), "FroshTools.zip")
+
+	writeVendorLayerFixture(t, base, `{"content-hash": "a"}`)
+
+	require.NoError(t, CreateZipWithVendorLayer(getTestContext(), base, zipFile, "FroshTools", cacheDir))
+
+	assert.Equal(t, map[string]string{
+		"FroshTools/composer.json":           `{"name": "frosh/tools"}`,
+		"FroshTools/composer.lock":           `{"content-hash": "a"}`,
+		"FroshTools/vendor/autoload.php":     "<?php",
+		"FroshTools/vendor/acme/lib/Lib.php": "<?php class Lib {}",
+	}, readZipEntries(t, zipFile))
+
+	layers, err := filepath.Glob(filepath.Join(cacheDir, "FroshTools-*.zip"))
+	require.NoError(t, err)
+	assert.Len(t, layers, 1)
+
+	// An unchanged composer.lock reuses the layer
+	require.NoError(t, CreateZipWithVendorLayer(getTestContext(), base, zipFile, "FroshTools", cacheDir))
+
+	reused, err := filepath.Glob(filepath.Join(cacheDir, "FroshTools-*.zip"))
+	require.NoError(t, err)
+	assert.Equal(t, layers, reused)
+
+	// A changed composer.lock creates a new layer
+	require.NoError(t, os.WriteFile(filepath.Join(base, "FroshTools", "composer.lock"), []byte(`{"content-hash": "b"}`), os.ModePerm))
+	require.NoError(t, CreateZipWithVendorLayer(getTestContext(), base, zipFile, "FroshTools", cacheDir))
+
+	layers, err = filepath.Glob(filepath.Join(cacheDir, "FroshTools-*.zip"))
+	require.NoError(t, err)
+	assert.Len(t, layers, 2)
+}
+
+func TestCreateZipWithVendorLayerWithoutVendor(t *testing.T) {
+	base := t.TempDir()
+	cacheDir := t.TempDir()
+	zipFile := filepath.Join(t.TempDir(), "FroshTools.zip")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(base, "FroshTools"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "FroshTools", "composer.json"), []byte(`{}`), os.ModePerm))
+
+	require.NoError(t, CreateZipWithVendorLayer(getTestContext(), base, zipFile, "FroshTools", cacheDir))
+
+	assert.Equal(t, map[string]string{"FroshTools/composer.json": "{}"}, readZipEntries(t, zipFile))
+
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestCreateZipWithVendorLayerDetectsSameSizeChanges(t *testing.T) {
+	base := t.TempDir()
+	cacheDir := t.TempDir()
+	zipFile := filepath.Join(t.TempDir(), "FroshTools.zip")
+
+	writeVendorLayerFixture(t, base, `{"content-hash": "a"}`)
+
+	require.NoError(t, CreateZipWithVendorLayer(getTestContext(), base, zipFile, "FroshTools", cacheDir))
+
+	// Changing the psr-4 namespace regenerates the autoloader with the same size and composer.lock
+	require.NoError(t, os.WriteFile(filepath.Join(base, "FroshTools", "vendor", "acme", "lib", "Lib.php"), []byte("<?php class Bib {}"), os.ModePerm))
+	require.NoError(t, CreateZipWithVendorLayer(getTestContext(), base, zipFile, "FroshTools", cacheDir))
+
+	assert.Equal(t, "<?php class Bib {}", readZipEntries(t, zipFile)["FroshTools/vendor/acme/lib/Lib.php"])
+}
@@ -0,0 +1,482 @@
+// Package accountmock provides an in-memory fake of the Shopware Account API.
+// Responses are based on recorded fixtures, binaries and code reviews are kept in memory.
+package accountmock
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// Token is the access token handed out by the mock on login
+const Token = "mock-token"
+
+// ProducerId is the producer id of the mocked company
+const ProducerId = 4001
+
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Body   []byte
+}
+
+type Server struct {
+	mux *http.ServeMux
+
+	mu         sync.Mutex
+	requests   []Request
+	nextId     int
+	extensions map[int]string
+	binaries   map[int][]map[string]any
+	reviews    map[int][]map[string]any
+	files      map[int][]byte
+
+	// activeCompany is the company the token acts for, it is changed with the active membership
+	activeCompany int
+}
+
+func New() *Server {
+	s := &Server{
+		mux:        http.NewServeMux(),
+		nextId:     5000,
+		extensions: map[int]string{},
+		binaries:   map[int][]map[string]any{},
+		reviews:    map[int][]map[string]any{},
+		files:      map[int][]byte{},
+
+		activeCompany: 2001,
+	}
+
+	s.mux.HandleFunc("POST /accesstokens", s.fixture("accesstokens.json"))
+	s.mux.HandleFunc("GET /account/{user}", s.fixture("profile.json"))
+	s.mux.HandleFunc("GET /account/{user}/memberships", s.fixture("memberships.json"))
+	s.mux.HandleFunc("POST /account/{user}/memberships/change", s.changeMembership)
+	s.mux.HandleFunc("GET /companies/{company}/allocations", s.allocations)
+	s.mux.HandleFunc("GET /producers", s.fixture("producers.json"))
+	s.mux.HandleFunc("GET /pluginstatics/all", s.fixture("pluginstatics.json"))
+	s.mux.HandleFunc("GET /pluginstatics/softwareVersions", s.fixture("softwareVersions.json"))
+
+	s.mux.HandleFunc("GET /plugins", s.listExtensions)
+	s.mux.HandleFunc("GET /plugins/{extension}", s.getExtension)
+	s.mux.HandleFunc("PUT /plugins/{extension}", s.empty)
+	s.mux.HandleFunc("POST /plugins/{extension}/icon", s.empty)
+	s.mux.HandleFunc("GET /plugins/{extension}/pictures", s.emptyList)
+	s.mux.HandleFunc("POST /plugins/{extension}/pictures", s.addPicture)
+	s.mux.HandleFunc("PUT /plugins/{extension}/pictures/{picture}", s.empty)
+	s.mux.HandleFunc("DELETE /plugins/{extension}/pictures/{picture}", s.empty)
+	s.mux.HandleFunc("POST /plugins/{extension}/reviews", s.triggerReview)
+	s.mux.HandleFunc("GET /plugins/{extension}/binaries/{binary}/checkresults", s.listReviews)
+
+	s.mux.HandleFunc("GET /producers/{producer}/plugins/{extension}/binaries", s.listBinaries)
+	s.mux.HandleFunc("POST /producers/{producer}/plugins/{extension}/binaries", s.createBinary)
+	s.mux.HandleFunc("PUT /producers/{producer}/plugins/{extension}/binaries/{binary}", s.empty)
+	s.mux.HandleFunc("POST /producers/{producer}/plugins/{extension}/binaries/{binary}/file", s.uploadBinaryFile)
+	s.mux.HandleFunc("GET /producers/{producer}/plugins/{extension}/binaries/{binary}/file", s.downloadBinaryFile)
+	s.mux.HandleFunc("GET /producers/{producer}/plugins/{extension}/pricemodels", s.emptyList)
+	s.mux.HandleFunc("PUT /producers/{producer}/plugins/{extension}/pricemodels", s.empty)
+	s.mux.HandleFunc("GET /producers/{producer}/plugins/{extension}/inappfeatures", s.emptyList)
+	s.mux.HandleFunc("PUT /producers/{producer}/plugins/{extension}/inappfeatures", s.empty)
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery, Body: body})
+	s.mu.Unlock()
+
+	if r.URL.Path != "/accesstokens" && r.Header.Get("x-shopware-token") != Token {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"code": "UsersException-1", "message": "Unauthorized"})
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// Requests returns all requests the server received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request{}, s.requests...)
+}
+
+// AddExtension registers an extension with the given name and returns its id.
+// Extensions are also registered on demand when they are searched by name.
+func (s *Server) AddExtension(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addExtension(name)
+}
+
+func (s *Server) addExtension(name string) int {
+	for id, existing := range s.extensions {
+		if existing == name {
+			return id
+		}
+	}
+
+	s.nextId++
+	id := s.nextId
+	s.extensions[id] = name
+
+	var binaries []map[string]any
+	mustUnmarshalFixture("binaries.json", &binaries)
+
+	for _, binary := range binaries {
+		s.nextId++
+		binary["id"] = s.nextId
+	}
+
+	s.binaries[id] = binaries
+
+	return id
+}
+
+func (s *Server) fixture(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		content, err := fixtures.ReadFile("fixtures/" + name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		_, _ = w.Write(content)
+	}
+}
+
+// changeMembership switches the company the token acts for to the one of the membership
+func (s *Server) changeMembership(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Membership struct {
+			Id int `json:"id"`
+		} `json:"membership"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	content, err := fixtures.ReadFile("fixtures/memberships.json")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var memberships []struct {
+		Id      int `json:"id"`
+		Company struct {
+			Id int `json:"id"`
+		} `json:"company"`
+	}
+
+	if err := json.Unmarshal(content, &memberships); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	for _, membership := range memberships {
+		if membership.Id == request.Membership.Id {
+			s.mu.Lock()
+			s.activeCompany = membership.Company.Id
+			s.mu.Unlock()
+
+			s.empty(w, r)
+
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusNotFound, map[string]string{"code": "MembershipException-1", "message": "Membership not found"})
+}
+
+// allocations answers only for the active company like the real API, the other companies of the user are forbidden
+func (s *Server) allocations(w http.ResponseWriter, r *http.Request) {
+	company, _ := strconv.Atoi(r.PathValue("company"))
+
+	s.mu.Lock()
+	active := s.activeCompany
+	s.mu.Unlock()
+
+	if company != active {
+		writeJSON(w, http.StatusForbidden, map[string]string{"code": "CompaniesException-4", "message": "Access denied"})
+		return
+	}
+
+	s.fixture("allocations.json")(w, r)
+}
+
+func (s *Server) empty(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
+
+func (s *Server) emptyList(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, []any{})
+}
+
+func (s *Server) listExtensions(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if search := r.URL.Query().Get("search"); search != "" {
+		s.addExtension(search)
+	}
+
+	ids := make([]int, 0, len(s.extensions))
+	for id := range s.extensions {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	list := make([]map[string]any, 0, len(ids))
+	for _, id := range ids {
+		list = append(list, map[string]any{"id": id, "name": s.extensions[id]})
+	}
+
+	writeJSON(w, http.StatusOK, paginate(r, list))
+}
+
+func (s *Server) getExtension(w http.ResponseWriter, r *http.Request) {
+	id, name, ok := s.lookupExtension(r)
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	var extension map[string]any
+	mustUnmarshalFixture("plugin.json", &extension)
+
+	extension["id"] = id
+	extension["name"] = name
+	extension["code"] = name
+
+	writeJSON(w, http.StatusOK, extension)
+}
+
+func (s *Server) listBinaries(w http.ResponseWriter, r *http.Request) {
+	id, _, ok := s.lookupExtension(r)
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, paginate(r, s.binaries[id]))
+}
+
+func (s *Server) createBinary(w http.ResponseWriter, r *http.Request) {
+	id, _, ok := s.lookupExtension(r)
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	var create struct {
+		Version string `json:"version"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&create); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextId++
+
+	binary := map[string]any{
+		"id":      s.nextId,
+		"name":    create.Version,
+		"version": create.Version,
+		"status": map[string]any{
+			"id":          1,
+			"name":        "waitingforcodereview",
+			"description": "Waiting for code review",
+		},
+		"compatibleSoftwareVersions": []any{},
+		"changelogs":                 []any{},
+	}
+
+	s.binaries[id] = append(s.binaries[id], binary)
+
+	writeJSON(w, http.StatusOK, binary)
+}
+
+func (s *Server) uploadBinaryFile(w http.ResponseWriter, r *http.Request) {
+	binaryId, err := strconv.Atoi(r.PathValue("binary"))
+	if err != nil {
+		writeNotFound(w)
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.files[binaryId] = content
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
+
+func (s *Server) downloadBinaryFile(w http.ResponseWriter, r *http.Request) {
+	binaryId, err := strconv.Atoi(r.PathValue("binary"))
+	if err != nil {
+		writeNotFound(w)
+		return
+	}
+
+	s.mu.Lock()
+	content, ok := s.files[binaryId]
+	s.mu.Unlock()
+
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	w.Header().Set("content-type", "application/zip")
+	_, _ = w.Write(content)
+}
+
+func (s *Server) triggerReview(w http.ResponseWriter, r *http.Request) {
+	id, _, ok := s.lookupExtension(r)
+	if !ok {
+		writeNotFound(w)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	binaries := s.binaries[id]
+	if len(binaries) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"code": "BinariesException-1", "message": "No binary to review"})
+		return
+	}
+
+	binaryId, _ := binaries[len(binaries)-1]["id"].(int)
+
+	s.nextId++
+
+	s.reviews[binaryId] = append(s.reviews[binaryId], map[string]any{
+		"id":       s.nextId,
+		"binaryId": binaryId,
+		"type": map[string]any{
+			"id":          3,
+			"name":        "automaticcodereviewsucceeded",
+			"description": "Automatic code review succeeded",
+		},
+		"message":         "",
+		"subCheckResults": []any{},
+	})
+
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
+
+func (s *Server) listReviews(w http.ResponseWriter, r *http.Request) {
+	binaryId, err := strconv.Atoi(r.PathValue("binary"))
+	if err != nil {
+		writeNotFound(w)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reviews := s.reviews[binaryId]
+	if reviews == nil {
+		reviews = []map[string]any{}
+	}
+
+	writeJSON(w, http.StatusOK, reviews)
+}
+
+func (s *Server) addPicture(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	s.nextId++
+	id := s.nextId
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, []map[string]any{{"id": id, "remoteLink": "", "details": []any{}, "priority": 0}})
+}
+
+func (s *Server) lookupExtension(r *http.Request) (int, string, bool) {
+	id, err := strconv.Atoi(r.PathValue("extension"))
+	if err != nil {
+		return 0, "", false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name, ok := s.extensions[id]
+
+	return id, name, ok
+}
+
+func mustUnmarshalFixture(name string, v any) {
+	content, err := fixtures.ReadFile("fixtures/" + name)
+	if err != nil {
+		panic(err)
+	}
+
+	if err := json.Unmarshal(content, v); err != nil {
+		panic(err)
+	}
+}
+
+// paginate applies the limit and offset query parameters like the listing endpoints of the account API
+func paginate[T any](r *http.Request, list []T) []T {
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	list = list[min(max(offset, 0), len(list)):]
+
+	if limit, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && limit >= 0 && limit < len(list) {
+		list = list[:limit]
+	}
+
+	return list
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeNotFound(w http.ResponseWriter) {
+	writeJSON(w, http.StatusNotFound, map[string]string{"code": "PluginsException-1", "message": "Plugin not found"})
+}
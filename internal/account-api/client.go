@@ -20,6 +20,7 @@ func SetUserAgent(userAgent string) {
 }
 
 type Client struct {
+	BaseURL          string       `json:"base_url,omitempty"`
 	Token            token        `json:"token"`
 	ActiveMembership Membership   `json:"active_membership"`
 	Memberships      []Membership `json:"memberships"`
@@ -41,7 +42,7 @@ func (c *Client) NewAuthenticatedRequest(ctx context.Context, method, path strin
 }
 
 func (*Client) doRequest(request *http.Request) ([]byte, error) {
-	resp, err := http.DefaultClient.Do(request)
+	resp, err := httpClient.Do(request)
 	if err != nil {
 		return nil, err
 	}
@@ -64,6 +65,14 @@ func (*Client) doRequest(request *http.Request) ([]byte, error) {
 	return data, nil
 }
 
+func (c *Client) apiUrl() string {
+	if c.BaseURL == "" {
+		return DefaultApiUrl
+	}
+
+	return c.BaseURL
+}
+
 func (c *Client) GetActiveCompanyID() int {
 	return c.Token.UserID
 }
@@ -107,7 +116,7 @@ func getApiTokenCacheFilePath() (string, error) {
 	return filepath.Join(shopwareCacheDir, CacheFileName), nil
 }
 
-func createApiFromTokenCache(ctx context.Context) (*Client, error) {
+func createApiFromTokenCache(ctx context.Context, apiUrl string) (*Client, error) {
 	tokenFilePath, err := getApiTokenCacheFilePath()
 	if err != nil {
 		return nil, err
@@ -131,6 +140,10 @@ func createApiFromTokenCache(ctx context.Context) (*Client, error) {
 	logging.FromContext(ctx).Debugf("Using token cache from %s", tokenFilePath)
 	logging.FromContext(ctx).Debugf("Impersonating currently as %s (%d)", client.ActiveMembership.Company.Name, client.ActiveMembership.Company.Id)
 
+	if client.apiUrl() != apiUrl {
+		return nil, fmt.Errorf("token was issued by %s", client.apiUrl())
+	}
+
 	if !client.isTokenValid() {
 		return nil, fmt.Errorf("token is expired")
 	}
@@ -0,0 +1,226 @@
+package account_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/shopware/shopware-cli/logging"
+)
+
+const extensionIdCacheFileName = "extension-ids.json"
+
+// ExtensionCandidate is an extension of the producer with a name similar to the searched one
+type ExtensionCandidate struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// AmbiguousExtensionError is returned when no extension has the searched name, the candidates have similar names
+type AmbiguousExtensionError struct {
+	Name       string               `json:"name"`
+	Candidates []ExtensionCandidate `json:"candidates"`
+}
+
+func (e *AmbiguousExtensionError) Error() string {
+	if len(e.Candidates) == 0 {
+		return fmt.Sprintf("cannot find Extension by name %s", e.Name)
+	}
+
+	names := make([]string, 0, len(e.Candidates))
+	for _, candidate := range e.Candidates {
+		names = append(names, candidate.Name)
+	}
+
+	return fmt.Sprintf("cannot find Extension by name %s, did you mean %s?", e.Name, strings.Join(names, ", "))
+}
+
+// extensionIdCache remembers the ids of resolved extension names per producer, so commands do not search the extension list every time
+type extensionIdCache struct {
+	path string
+	Ids  map[string]int `json:"ids"`
+}
+
+func getExtensionIdCacheFilePath() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(cacheDir, "shopware-cli", extensionIdCacheFileName), nil
+}
+
+func readExtensionIdCache() *extensionIdCache {
+	cache := &extensionIdCache{Ids: map[string]int{}}
+
+	path, err := getExtensionIdCacheFilePath()
+	if err != nil {
+		return cache
+	}
+
+	cache.path = path
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+
+	if err := json.Unmarshal(content, cache); err != nil || cache.Ids == nil {
+		cache.Ids = map[string]int{}
+	}
+
+	return cache
+}
+
+func extensionIdCacheKey(producerId int, name string) string {
+	return fmt.Sprintf("%d/%s", producerId, strings.ToLower(name))
+}
+
+func (c *extensionIdCache) set(key string, id int) error {
+	if id == 0 {
+		delete(c.Ids, key)
+	} else {
+		c.Ids[key] = id
+	}
+
+	if c.path == "" {
+		return nil
+	}
+
+	content, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o750); err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.path, content, 0o600)
+}
+
+// GetExtensionByName resolves the extension by its name case-insensitive. Resolved ids are cached, when no extension
+// matches an AmbiguousExtensionError with extensions of similar names is returned.
+func (e ProducerEndpoint) GetExtensionByName(ctx context.Context, name string) (*Extension, error) {
+	cache := readExtensionIdCache()
+	key := extensionIdCacheKey(e.GetId(), name)
+
+	if id, ok := cache.Ids[key]; ok {
+		ext, err := e.GetExtensionById(ctx, id)
+		if err == nil {
+			return ext, nil
+		}
+
+		logging.FromContext(ctx).Debugf("Cached extension id %d of %s is not valid anymore: %v", id, name, err)
+
+		_ = cache.set(key, 0)
+	}
+
+	extensions, err := e.Extensions(ctx, &ListExtensionCriteria{Search: name})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ext := range extensions {
+		if strings.EqualFold(ext.Name, name) {
+			if err := cache.set(key, ext.Id); err != nil {
+				logging.FromContext(ctx).Debugf("Cannot cache extension id of %s: %v", name, err)
+			}
+
+			return e.GetExtensionById(ctx, ext.Id)
+		}
+	}
+
+	allExtensions, err := e.AllExtensions(ctx, ListExtensionCriteria{}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, &AmbiguousExtensionError{Name: name, Candidates: findSimilarExtensions(name, append(extensions, allExtensions...))}
+}
+
+// RememberExtensionName caches the id for the name, e.g. after the user picked one of the candidates
+func (e ProducerEndpoint) RememberExtensionName(name string, id int) error {
+	return readExtensionIdCache().set(extensionIdCacheKey(e.GetId(), name), id)
+}
+
+// findSimilarExtensions returns the extensions containing the name or with a small edit distance, the most similar first
+func findSimilarExtensions(name string, extensions []Extension) []ExtensionCandidate {
+	type scored struct {
+		candidate ExtensionCandidate
+		distance  int
+	}
+
+	search := normalizeExtensionName(name)
+	maxDistance := max(2, len(search)/4)
+
+	matches := []scored{}
+	seen := map[int]bool{}
+
+	for _, ext := range extensions {
+		if seen[ext.Id] {
+			continue
+		}
+
+		seen[ext.Id] = true
+
+		normalized := normalizeExtensionName(ext.Name)
+		distance := levenshteinDistance(search, normalized)
+
+		if distance > maxDistance && (search == "" || !strings.Contains(normalized, search) && !strings.Contains(search, normalized)) {
+			continue
+		}
+
+		matches = append(matches, scored{candidate: ExtensionCandidate{Id: ext.Id, Name: ext.Name}, distance: distance})
+	}
+
+	slices.SortStableFunc(matches, func(a, b scored) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+
+		return strings.Compare(a.candidate.Name, b.candidate.Name)
+	})
+
+	candidates := make([]ExtensionCandidate, 0, len(matches))
+	for _, match := range matches {
+		candidates = append(candidates, match.candidate)
+	}
+
+	return candidates
+}
+
+// normalizeExtensionName ignores the case and separators, so frosh-tools matches FroshTools
+func normalizeExtensionName(name string) string {
+	return strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(name))
+}
+
+func levenshteinDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
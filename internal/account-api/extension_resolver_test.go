@@ -0,0 +1,79 @@
+package account_api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetExtensionByNameCachesTheId(t *testing.T) {
+	client, mock := newMockClient(t)
+
+	id := mock.AddExtension("FroshTools")
+
+	p, err := client.Producer(t.Context())
+	require.NoError(t, err)
+
+	ext, err := p.GetExtensionByName(t.Context(), "FroshTools")
+	require.NoError(t, err)
+	assert.Equal(t, id, ext.Id)
+
+	requests := len(mock.Requests())
+
+	ext, err = p.GetExtensionByName(t.Context(), "froshtools")
+	require.NoError(t, err)
+	assert.Equal(t, id, ext.Id)
+
+	// Only the extension itself is loaded, the name is resolved from the cache
+	for _, request := range mock.Requests()[requests:] {
+		assert.NotContains(t, request.Query, "search=")
+	}
+}
+
+func TestRememberExtensionName(t *testing.T) {
+	client, mock := newMockClient(t)
+
+	id := mock.AddExtension("FroshTools")
+
+	p, err := client.Producer(t.Context())
+	require.NoError(t, err)
+
+	require.NoError(t, p.RememberExtensionName("tools", id))
+
+	ext, err := p.GetExtensionByName(t.Context(), "Tools")
+	require.NoError(t, err)
+	assert.Equal(t, "FroshTools", ext.Name)
+}
+
+func TestFindSimilarExtensions(t *testing.T) {
+	extensions := []Extension{
+		{Id: 1, Name: "FroshTools"},
+		{Id: 2, Name: "FroshPlatformMailArchive"},
+		{Id: 3, Name: "SwagPayPal"},
+		{Id: 4, Name: "FroshTool"},
+		{Id: 1, Name: "FroshTools"},
+	}
+
+	assert.Equal(t, []ExtensionCandidate{{Id: 1, Name: "FroshTools"}, {Id: 4, Name: "FroshTool"}}, findSimilarExtensions("frosh-tools", extensions))
+	assert.Equal(t, []ExtensionCandidate{{Id: 2, Name: "FroshPlatformMailArchive"}}, findSimilarExtensions("MailArchive", extensions))
+	assert.Empty(t, findSimilarExtensions("Unrelated", extensions))
+}
+
+func TestAmbiguousExtensionError(t *testing.T) {
+	var err error = &AmbiguousExtensionError{Name: "frosh-tools", Candidates: []ExtensionCandidate{{Id: 1, Name: "FroshTools"}, {Id: 4, Name: "FroshTool"}}}
+
+	var ambiguous *AmbiguousExtensionError
+	assert.True(t, errors.As(err, &ambiguous))
+	assert.Equal(t, "cannot find Extension by name frosh-tools, did you mean FroshTools, FroshTool?", err.Error())
+
+	assert.Equal(t, "cannot find Extension by name foo", (&AmbiguousExtensionError{Name: "foo"}).Error())
+}
+
+func TestLevenshteinDistance(t *testing.T) {
+	assert.Equal(t, 0, levenshteinDistance("frosh", "frosh"))
+	assert.Equal(t, 1, levenshteinDistance("froshtool", "froshtools"))
+	assert.Equal(t, 3, levenshteinDistance("kitten", "sitting"))
+	assert.Equal(t, 5, levenshteinDistance("", "hello"))
+}
@@ -0,0 +1,80 @@
+package account_api
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var httpTraceEnabled = false
+
+// httpClient is used for all requests against the Shopware Account API.
+var httpClient = &http.Client{Transport: traceTransport{next: http.DefaultTransport}}
+
+// SetHTTPTrace enables logging of every account API call.
+func SetHTTPTrace(enabled bool) {
+	httpTraceEnabled = enabled
+}
+
+var requestIdHeaders = []string{"X-Request-Id", "X-Correlation-Id", "Cf-Ray"}
+
+var secretQueryParameters = []string{"password", "token", "secret", "key"}
+
+type traceTransport struct {
+	next http.RoundTripper
+}
+
+func (t traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !httpTraceEnabled {
+		return t.next.RoundTrip(req)
+	}
+
+	logger := logging.FromContext(req.Context())
+	start := time.Now()
+
+	resp, err := t.next.RoundTrip(req)
+	duration := time.Since(start)
+
+	if err != nil {
+		logger.Infof("HTTP %s %s failed after %s: %v", req.Method, redactURL(req.URL), duration, err)
+		return resp, err
+	}
+
+	requestIds := make([]string, 0)
+
+	for _, header := range requestIdHeaders {
+		if value := resp.Header.Get(header); value != "" {
+			requestIds = append(requestIds, header+"="+value)
+		}
+	}
+
+	logger.Infof("HTTP %s %s %d %s %s", req.Method, redactURL(req.URL), resp.StatusCode, duration.Round(time.Millisecond), strings.Join(requestIds, " "))
+
+	return resp, nil
+}
+
+func redactURL(u *url.URL) string {
+	redacted := *u
+	redacted.User = nil
+
+	query := redacted.Query()
+	hasSecrets := false
+
+	for name := range query {
+		for _, secret := range secretQueryParameters {
+			if strings.Contains(strings.ToLower(name), secret) {
+				query.Set(name, "REDACTED")
+				hasSecrets = true
+			}
+		}
+	}
+
+	if hasSecrets {
+		redacted.RawQuery = query.Encode()
+	}
+
+	return redacted.String()
+}
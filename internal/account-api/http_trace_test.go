@@ -0,0 +1,47 @@
+package account_api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/shopware/shopware-cli/logging"
+)
+
+func TestTraceTransportRedactsSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret-token", r.Header.Get("Authorization"))
+
+		w.Header().Set("X-Request-Id", "abc")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	SetHTTPTrace(true)
+	t.Cleanup(func() { SetHTTPTrace(false) })
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	ctx := logging.WithLogger(t.Context(), zap.New(core).Sugar())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/producers?token=secret-query&limit=10", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret-token")
+
+	resp, err := httpClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	require.Equal(t, 1, logs.Len())
+
+	message := logs.All()[0].Message
+	assert.Contains(t, message, "GET "+server.URL+"/producers?limit=10&token=REDACTED 204")
+	assert.Contains(t, message, "X-Request-Id=abc")
+	assert.False(t, strings.Contains(message, "secret"), message)
+}
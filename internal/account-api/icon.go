@@ -0,0 +1,53 @@
+package account_api
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/srwiley/oksvg"
+	"github.com/srwiley/rasterx"
+	_ "golang.org/x/image/webp"
+)
+
+const storeIconSize = 256
+
+// decodeStoreIcon decodes the given icon into an image. SVG icons are rasterized to the store icon size.
+func decodeStoreIcon(r io.Reader, fileName string) (image.Image, string, error) {
+	if strings.EqualFold(filepath.Ext(fileName), ".svg") {
+		img, err := rasterizeSVG(r, storeIconSize)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return img, "svg", nil
+	}
+
+	return image.Decode(r)
+}
+
+func rasterizeSVG(r io.Reader, size int) (image.Image, error) {
+	icon, err := oksvg.ReadIconStream(r, oksvg.IgnoreErrorMode)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse svg: %w", err)
+	}
+
+	// The icon is fitted into the square and centered, so non square icons keep their aspect ratio
+	width, height := float64(size), float64(size)
+
+	if icon.ViewBox.W > 0 && icon.ViewBox.H > 0 {
+		scale := float64(size) / max(icon.ViewBox.W, icon.ViewBox.H)
+		width, height = icon.ViewBox.W*scale, icon.ViewBox.H*scale
+	}
+
+	icon.SetTarget((float64(size)-width)/2, (float64(size)-height)/2, width, height)
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	icon.Draw(rasterx.NewDasher(size, size, rasterx.NewScannerGV(size, size, dst, dst.Bounds())), 1)
+
+	return dst, nil
+}
@@ -0,0 +1,97 @@
+package account_api
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeStoreIconRasterizesSquareSVG(t *testing.T) {
+	svg := `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 32 32"><rect width="32" height="32" fill="#ff0000"/></svg>`
+
+	img, format, err := decodeStoreIcon(strings.NewReader(svg), "icon.svg")
+	require.NoError(t, err)
+
+	assert.Equal(t, "svg", format)
+	assert.Equal(t, image.Rect(0, 0, storeIconSize, storeIconSize), img.Bounds())
+	assertOpaqueRed(t, img.At(1, 1))
+	assertOpaqueRed(t, img.At(storeIconSize-2, storeIconSize-2))
+}
+
+func TestDecodeStoreIconKeepsAspectRatioOfSVG(t *testing.T) {
+	svg := `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 64 32"><rect width="64" height="32" fill="#ff0000"/></svg>`
+
+	img, _, err := decodeStoreIcon(strings.NewReader(svg), "ICON.SVG")
+	require.NoError(t, err)
+
+	assert.Equal(t, image.Rect(0, 0, storeIconSize, storeIconSize), img.Bounds())
+
+	// The icon is centered with transparent bars above and below
+	_, _, _, alpha := img.At(storeIconSize/2, 10).RGBA()
+	assert.Zero(t, alpha)
+
+	_, _, _, alpha = img.At(storeIconSize/2, storeIconSize-10).RGBA()
+	assert.Zero(t, alpha)
+
+	assertOpaqueRed(t, img.At(1, storeIconSize/2))
+	assertOpaqueRed(t, img.At(storeIconSize-2, storeIconSize/2))
+}
+
+func TestDecodeStoreIconDecodesPNG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 10, 20))))
+
+	img, format, err := decodeStoreIcon(&buf, "icon.png")
+	require.NoError(t, err)
+
+	assert.Equal(t, "png", format)
+	assert.Equal(t, image.Rect(0, 0, 10, 20), img.Bounds())
+}
+
+func TestDecodeStoreIconRejectsInvalidSVG(t *testing.T) {
+	_, _, err := decodeStoreIcon(strings.NewReader("<svg"), "icon.svg")
+	assert.Error(t, err)
+}
+
+func TestUpdateExtensionIconConvertsSVG(t *testing.T) {
+	client, mock := newMockClient(t)
+
+	p, err := client.Producer(t.Context())
+	require.NoError(t, err)
+
+	ext, err := p.GetExtensionByName(t.Context(), "FroshTools")
+	require.NoError(t, err)
+
+	iconPath := filepath.Join(t.TempDir(), "icon.svg")
+	require.NoError(t, os.WriteFile(iconPath, []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 32 16"><rect width="32" height="16"/></svg>`), 0o644))
+
+	require.NoError(t, p.UpdateExtensionIcon(t.Context(), ext.Id, iconPath))
+
+	last := mock.Requests()[len(mock.Requests())-1]
+	assert.Equal(t, fmt.Sprintf("/plugins/%d/icon", ext.Id), last.Path)
+
+	// The multipart body contains the rasterized icon as png
+	start := bytes.Index(last.Body, []byte("\x89PNG"))
+	require.NotEqual(t, -1, start)
+	assert.Contains(t, string(last.Body), `filename="icon.png"`)
+
+	img, err := png.Decode(bytes.NewReader(last.Body[start:]))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, storeIconSize, storeIconSize), img.Bounds())
+}
+
+func assertOpaqueRed(t *testing.T, c color.Color) {
+	t.Helper()
+
+	r, g, b, a := c.RGBA()
+	assert.Equal(t, []uint32{0xffff, 0, 0, 0xffff}, []uint32{r, g, b, a})
+}
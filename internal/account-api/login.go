@@ -7,43 +7,55 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/shopware/shopware-cli/logging"
 )
 
-const ApiUrl = "https://api.shopware.com"
+const DefaultApiUrl = "https://api.shopware.com"
 
 type AccountConfig interface {
 	GetAccountEmail() string
 	GetAccountPassword() string
+	// GetAccountApiUrl returns the base URL of the account API, empty for the default one
+	GetAccountApiUrl() string
 }
 
+// NewApi returns a client with the cached token, it logs in when no valid token is cached
 func NewApi(ctx context.Context, config AccountConfig) (*Client, error) {
+	client, err := createApiFromTokenCache(ctx, accountApiUrl(config))
+
+	if err == nil {
+		return client, nil
+	}
+
+	return Login(ctx, config)
+}
+
+// Login logs in with the credentials of the config without using the token cache and caches the new token
+func Login(ctx context.Context, config AccountConfig) (*Client, error) {
 	errorFormat := "login: %v"
 
 	request := LoginRequest{
 		Email:    config.GetAccountEmail(),
 		Password: config.GetAccountPassword(),
 	}
-	client, err := createApiFromTokenCache(ctx)
 
-	if err == nil {
-		return client, nil
-	}
+	apiUrl := accountApiUrl(config)
 
 	s, err := json.Marshal(request)
 	if err != nil {
 		return nil, fmt.Errorf(errorFormat, err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ApiUrl+"/accesstokens", bytes.NewBuffer(s))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiUrl+"/accesstokens", bytes.NewBuffer(s))
 	if err != nil {
 		return nil, fmt.Errorf("create access token request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf(errorFormat, err)
 	}
@@ -69,7 +81,7 @@ func NewApi(ctx context.Context, config AccountConfig) (*Client, error) {
 		return nil, fmt.Errorf(errorFormat, err)
 	}
 
-	memberships, err := fetchMemberships(ctx, token)
+	memberships, err := fetchMemberships(ctx, apiUrl, token)
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +94,8 @@ func NewApi(ctx context.Context, config AccountConfig) (*Client, error) {
 		}
 	}
 
-	client = &Client{
+	client := &Client{
+		BaseURL:          apiUrl,
 		Token:            token,
 		Memberships:      memberships,
 		ActiveMembership: activeMemberShip,
@@ -95,15 +108,24 @@ func NewApi(ctx context.Context, config AccountConfig) (*Client, error) {
 	return client, nil
 }
 
-func fetchMemberships(ctx context.Context, token token) ([]Membership, error) {
-	r, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/account/%d/memberships", ApiUrl, token.UserAccountID), http.NoBody)
+func accountApiUrl(config AccountConfig) string {
+	apiUrl := strings.TrimSuffix(config.GetAccountApiUrl(), "/")
+	if apiUrl == "" {
+		apiUrl = DefaultApiUrl
+	}
+
+	return apiUrl
+}
+
+func fetchMemberships(ctx context.Context, apiUrl string, token token) ([]Membership, error) {
+	r, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/account/%d/memberships", apiUrl, token.UserAccountID), http.NoBody)
 	r.Header.Set("x-shopware-token", token.Token)
 
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := http.DefaultClient.Do(r)
+	resp, err := httpClient.Do(r)
 	if err != nil {
 		return nil, err
 	}
@@ -148,6 +170,7 @@ type tokenExpire struct {
 type LoginRequest struct {
 	Email    string `json:"shopwareId"`
 	Password string `json:"password"`
+	ApiUrl   string `json:"-"`
 }
 
 func (l LoginRequest) GetAccountEmail() string {
@@ -158,6 +181,10 @@ func (l LoginRequest) GetAccountPassword() string {
 	return l.Password
 }
 
+func (l LoginRequest) GetAccountApiUrl() string {
+	return l.ApiUrl
+}
+
 type Membership struct {
 	Id           int    `json:"id"`
 	CreationDate string `json:"creationDate"`
@@ -224,12 +251,12 @@ func (c *Client) ChangeActiveMembership(ctx context.Context, selected Membership
 		return fmt.Errorf("ChangeActiveMembership: %v", err)
 	}
 
-	r, err := c.NewAuthenticatedRequest(ctx, "POST", fmt.Sprintf("%s/account/%d/memberships/change", ApiUrl, c.GetUserID()), bytes.NewBuffer(s))
+	r, err := c.NewAuthenticatedRequest(ctx, "POST", fmt.Sprintf("%s/account/%d/memberships/change", c.apiUrl(), c.GetUserID()), bytes.NewBuffer(s))
 	if err != nil {
 		return err
 	}
 
-	resp, err := http.DefaultClient.Do(r)
+	resp, err := httpClient.Do(r)
 	if err != nil {
 		return err
 	}
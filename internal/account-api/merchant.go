@@ -14,8 +14,18 @@ func (c *Client) Merchant() *MerchantEndpoint {
 	return &MerchantEndpoint{c: c}
 }
 
+// Shops fetches all shops of the merchant
 func (m MerchantEndpoint) Shops(ctx context.Context) (MerchantShopList, error) {
-	r, err := m.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/shops?limit=100&userId=%d", ApiUrl, m.c.GetActiveCompanyID()), nil)
+	return m.ListShops(ctx, 0)
+}
+
+// ListShops fetches the shops page by page, a limit of zero or less fetches all of them
+func (m MerchantEndpoint) ListShops(ctx context.Context, limit int) (MerchantShopList, error) {
+	return FetchPages(ctx, limit, m.shopsPage)
+}
+
+func (m MerchantEndpoint) shopsPage(ctx context.Context, limit, offset int) ([]*MerchantShop, error) {
+	r, err := m.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/shops?limit=%d&offset=%d&userId=%d", m.c.apiUrl(), limit, offset, m.c.GetActiveCompanyID()), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -25,7 +35,7 @@ func (m MerchantEndpoint) Shops(ctx context.Context) (MerchantShopList, error) {
 		return nil, err
 	}
 
-	var shops MerchantShopList
+	var shops []*MerchantShop
 	if err := json.Unmarshal(body, &shops); err != nil {
 		return nil, fmt.Errorf("shops: %v", err)
 	}
@@ -141,7 +151,7 @@ func (m MerchantShopList) GetByDomain(domain string) *MerchantShop {
 }
 
 func (m MerchantEndpoint) GetComposerToken(ctx context.Context, shopId int) (string, error) {
-	r, err := m.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/companies/%d/shops/%d/packagestoken", ApiUrl, m.c.GetActiveCompanyID(), shopId), nil)
+	r, err := m.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/companies/%d/shops/%d/packagestoken", m.c.apiUrl(), m.c.GetActiveCompanyID(), shopId), nil)
 	if err != nil {
 		return "", err
 	}
@@ -168,7 +178,7 @@ func (m MerchantEndpoint) GetComposerToken(ctx context.Context, shopId int) (str
 }
 
 func (m MerchantEndpoint) GenerateComposerToken(ctx context.Context, shopId int) (string, error) {
-	r, err := m.c.NewAuthenticatedRequest(ctx, "POST", fmt.Sprintf("%s/companies/%d/shops/%d/packagestoken", ApiUrl, m.c.GetActiveCompanyID(), shopId), nil)
+	r, err := m.c.NewAuthenticatedRequest(ctx, "POST", fmt.Sprintf("%s/companies/%d/shops/%d/packagestoken", m.c.apiUrl(), m.c.GetActiveCompanyID(), shopId), nil)
 	if err != nil {
 		return "", err
 	}
@@ -190,7 +200,7 @@ func (m MerchantEndpoint) GenerateComposerToken(ctx context.Context, shopId int)
 }
 
 func (m MerchantEndpoint) SaveComposerToken(ctx context.Context, shopId int, token string) error {
-	r, err := m.c.NewAuthenticatedRequest(ctx, "POST", fmt.Sprintf("%s/companies/%d/shops/%d/packagestoken/%s", ApiUrl, m.c.GetActiveCompanyID(), shopId, token), nil)
+	r, err := m.c.NewAuthenticatedRequest(ctx, "POST", fmt.Sprintf("%s/companies/%d/shops/%d/packagestoken/%s", m.c.apiUrl(), m.c.GetActiveCompanyID(), shopId, token), nil)
 	if err != nil {
 		return err
 	}
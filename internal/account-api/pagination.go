@@ -0,0 +1,59 @@
+package account_api
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// DefaultPageSize is the page size used to fetch the entries of listing endpoints page by page
+const DefaultPageSize = 100
+
+// MaxPages protects against endpoints which never return a short page
+const MaxPages = 1000
+
+// PageFetcher loads one page of a listing endpoint
+type PageFetcher[T any] func(ctx context.Context, limit, offset int) ([]T, error)
+
+// FetchPages loads the pages until the endpoint returns fewer entries than requested.
+// A limit of zero or less fetches all entries, otherwise at most limit entries are returned.
+// Endpoints ignoring the limit or the offset are detected by pages longer than requested or repeating the previous page.
+func FetchPages[T any](ctx context.Context, limit int, fetch PageFetcher[T]) ([]T, error) {
+	all := []T{}
+
+	var previous []T
+
+	for offset, pages := 0, 0; limit <= 0 || len(all) < limit; pages++ {
+		if pages == MaxPages {
+			return nil, fmt.Errorf("stopped after %d pages, the endpoint does not seem to paginate", MaxPages)
+		}
+
+		pageSize := DefaultPageSize
+		if limit > 0 {
+			pageSize = min(pageSize, limit-len(all))
+		}
+
+		page, err := fetch(ctx, pageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(page) > 0 && reflect.DeepEqual(page, previous) {
+			break
+		}
+
+		all = append(all, page...)
+		offset += len(page)
+		previous = page
+
+		if len(page) != pageSize {
+			break
+		}
+	}
+
+	if limit > 0 && len(all) > limit {
+		all = all[:limit]
+	}
+
+	return all, nil
+}
@@ -0,0 +1,131 @@
+package account_api
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pageRequest struct {
+	limit, offset int
+}
+
+func fakePages(total int, requests *[]pageRequest) PageFetcher[int] {
+	return func(_ context.Context, limit, offset int) ([]int, error) {
+		*requests = append(*requests, pageRequest{limit, offset})
+
+		page := []int{}
+		for i := offset; i < min(offset+limit, total); i++ {
+			page = append(page, i)
+		}
+
+		return page, nil
+	}
+}
+
+func TestFetchPagesLoadsAllPages(t *testing.T) {
+	var requests []pageRequest
+
+	all, err := FetchPages(t.Context(), 0, fakePages(250, &requests))
+	require.NoError(t, err)
+
+	assert.Len(t, all, 250)
+	assert.Equal(t, 249, all[249])
+	assert.Equal(t, []pageRequest{{100, 0}, {100, 100}, {100, 200}}, requests)
+}
+
+func TestFetchPagesStopsAtLimit(t *testing.T) {
+	var requests []pageRequest
+
+	all, err := FetchPages(t.Context(), 150, fakePages(250, &requests))
+	require.NoError(t, err)
+
+	assert.Len(t, all, 150)
+	assert.Equal(t, []pageRequest{{100, 0}, {50, 100}}, requests)
+}
+
+func TestFetchPagesStopsWhenLimitIsIgnored(t *testing.T) {
+	calls := 0
+
+	all, err := FetchPages(t.Context(), 150, func(_ context.Context, _, offset int) ([]int, error) {
+		calls++
+
+		page := make([]int, 200)
+		for i := range page {
+			page[i] = offset + i
+		}
+
+		return page, nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, calls)
+	assert.Len(t, all, 150)
+}
+
+func TestFetchPagesStopsWhenOffsetIsIgnored(t *testing.T) {
+	calls := 0
+
+	all, err := FetchPages(t.Context(), 0, func(_ context.Context, limit, _ int) ([]int, error) {
+		calls++
+
+		page := make([]int, limit)
+		for i := range page {
+			page[i] = i
+		}
+
+		return page, nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, calls)
+	assert.Len(t, all, 100)
+}
+
+func TestFetchPagesStopsAfterMaxPages(t *testing.T) {
+	calls := 0
+
+	_, err := FetchPages(t.Context(), 0, func(_ context.Context, limit, offset int) ([]int, error) {
+		calls++
+
+		page := make([]int, limit)
+		for i := range page {
+			page[i] = offset + i
+		}
+
+		return page, nil
+	})
+
+	assert.ErrorContains(t, err, "stopped after 1000 pages")
+	assert.Equal(t, MaxPages, calls)
+}
+
+func TestFetchPagesReturnsErrors(t *testing.T) {
+	_, err := FetchPages(t.Context(), 0, func(_ context.Context, _, _ int) ([]int, error) {
+		return nil, fmt.Errorf("boom")
+	})
+
+	assert.EqualError(t, err, "boom")
+}
+
+func TestAllExtensionsPaginates(t *testing.T) {
+	client, mock := newMockClient(t)
+
+	for i := 0; i < DefaultPageSize+5; i++ {
+		mock.AddExtension(fmt.Sprintf("Extension%d", i))
+	}
+
+	p, err := client.Producer(t.Context())
+	require.NoError(t, err)
+
+	all, err := p.AllExtensions(t.Context(), ListExtensionCriteria{}, 0)
+	require.NoError(t, err)
+	assert.Len(t, all, DefaultPageSize+5)
+
+	limited, err := p.AllExtensions(t.Context(), ListExtensionCriteria{}, 10)
+	require.NoError(t, err)
+	assert.Len(t, limited, 10)
+}
@@ -4,25 +4,36 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"strconv"
-	"strings"
 
 	"github.com/gorilla/schema"
 )
 
+// ErrNotProducer is returned when the company is not unlocked as producer
+var ErrNotProducer = errors.New("this company is not unlocked as producer")
+
 type ProducerEndpoint struct {
 	c          *Client
 	producerId int
+	companyId  int
 }
 
 func (e ProducerEndpoint) GetId() int {
 	return e.producerId
 }
 
+func (e ProducerEndpoint) GetCompanyId() int {
+	return e.companyId
+}
+
+// Producer returns the producer of the active company
 func (c *Client) Producer(ctx context.Context) (*ProducerEndpoint, error) {
-	r, err := c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/companies/%d/allocations", ApiUrl, c.GetActiveCompanyID()), nil)
+	companyId := c.GetActiveCompanyID()
+
+	r, err := c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/companies/%d/allocations", c.apiUrl(), companyId), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -38,10 +49,58 @@ func (c *Client) Producer(ctx context.Context) (*ProducerEndpoint, error) {
 	}
 
 	if !allocation.IsProducer {
-		return nil, fmt.Errorf("this company is not unlocked as producer")
+		return nil, ErrNotProducer
 	}
 
-	return &ProducerEndpoint{producerId: allocation.ProducerID, c: c}, nil
+	return &ProducerEndpoint{producerId: allocation.ProducerID, companyId: companyId, c: c}, nil
+}
+
+// ProducerMembership is the producer of a company the user is a member of
+type ProducerMembership struct {
+	Membership Membership
+	Producer   *Producer
+}
+
+// ProducerMemberships returns the producers of all companies the user is a member of, companies without producer are skipped.
+// The token only grants access to the active company, so the active membership is switched for each company and restored at the end.
+func (c *Client) ProducerMemberships(ctx context.Context) (producers []ProducerMembership, err error) {
+	active := c.GetActiveMembership()
+
+	defer func() {
+		if active.Id == 0 || c.GetActiveCompanyID() == active.Company.Id {
+			return
+		}
+
+		if restoreErr := c.ChangeActiveMembership(ctx, active); restoreErr != nil {
+			err = errors.Join(err, fmt.Errorf("cannot switch back to company %s: %w", active.Company.Name, restoreErr))
+		}
+	}()
+
+	for _, membership := range c.GetMemberships() {
+		if membership.Company.Id != c.GetActiveCompanyID() {
+			if err := c.ChangeActiveMembership(ctx, membership); err != nil {
+				return nil, fmt.Errorf("cannot switch to company %s: %w", membership.Company.Name, err)
+			}
+		}
+
+		p, err := c.Producer(ctx)
+		if errors.Is(err, ErrNotProducer) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("cannot get producer of company %s: %w", membership.Company.Name, err)
+		}
+
+		profile, err := p.Profile(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get producer profile of company %s: %w", membership.Company.Name, err)
+		}
+
+		producers = append(producers, ProducerMembership{Membership: membership, Producer: profile})
+	}
+
+	return producers, nil
 }
 
 type companyAllocation struct {
@@ -54,7 +113,7 @@ type companyAllocation struct {
 }
 
 func (e ProducerEndpoint) Profile(ctx context.Context) (*Producer, error) {
-	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/producers?companyId=%d", ApiUrl, e.c.GetActiveCompanyID()), nil)
+	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/producers?companyId=%d", e.c.apiUrl(), e.companyId), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -132,7 +191,7 @@ func (e ProducerEndpoint) Extensions(ctx context.Context, criteria *ListExtensio
 		return nil, fmt.Errorf("list_extensions: %v", err)
 	}
 
-	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/plugins?%s", ApiUrl, form.Encode()), nil)
+	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/plugins?%s", e.c.apiUrl(), form.Encode()), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -150,30 +209,21 @@ func (e ProducerEndpoint) Extensions(ctx context.Context, criteria *ListExtensio
 	return extensions, nil
 }
 
-func (e ProducerEndpoint) GetExtensionByName(ctx context.Context, name string) (*Extension, error) {
-	criteria := ListExtensionCriteria{
-		Search: name,
-	}
-
-	extensions, err := e.Extensions(ctx, &criteria)
-	if err != nil {
-		return nil, err
-	}
-
-	for _, ext := range extensions {
-		if strings.EqualFold(ext.Name, name) {
-			return e.GetExtensionById(ctx, ext.Id)
-		}
-	}
+// AllExtensions fetches the extensions matching the criteria page by page, a limit of zero or less fetches all of them
+func (e ProducerEndpoint) AllExtensions(ctx context.Context, criteria ListExtensionCriteria, limit int) ([]Extension, error) {
+	return FetchPages(ctx, limit, func(ctx context.Context, pageLimit, offset int) ([]Extension, error) {
+		criteria.Limit = pageLimit
+		criteria.Offset = offset
 
-	return nil, fmt.Errorf("cannot find Extension by name %s", name)
+		return e.Extensions(ctx, &criteria)
+	})
 }
 
 func (e ProducerEndpoint) GetExtensionById(ctx context.Context, id int) (*Extension, error) {
 	errorFormat := "GetExtensionById: %v"
 
 	// Create it
-	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/plugins/%d", ApiUrl, id), nil)
+	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/plugins/%d", e.c.apiUrl(), id), nil)
 	if err != nil {
 		return nil, fmt.Errorf(errorFormat, err)
 	}
@@ -291,22 +341,7 @@ type Extension struct {
 		Name        string `json:"name"`
 		Description string `json:"description"`
 	} `json:"license"`
-	Infos []*struct {
-		Id                 int          `json:"id"`
-		Locale             Locale       `json:"locale"`
-		Name               string       `json:"name"`
-		Description        string       `json:"description"`
-		InstallationManual string       `json:"installationManual"`
-		ShortDescription   string       `json:"shortDescription"`
-		Highlights         string       `json:"highlights"`
-		Features           string       `json:"features"`
-		MetaTitle          string       `json:"metaTitle"`
-		MetaDescription    string       `json:"metaDescription"`
-		Tags               []StoreTag   `json:"tags"`
-		Videos             []StoreVideo `json:"videos"`
-		Faqs               []StoreFaq   `json:"faqs"`
-		SupportInfo        interface{}  `json:"supportInfo"`
-	} `json:"infos"`
+	Infos               []*ExtensionInfo   `json:"infos"`
 	PriceModels         []interface{}      `json:"priceModels"`
 	Variants            []interface{}      `json:"variants"`
 	StoreAvailabilities []StoreAvailablity `json:"storeAvailabilities"`
@@ -367,6 +402,24 @@ type Extension struct {
 	CancellationOffers                    []interface{} `json:"cancellationOffers"`
 }
 
+// ExtensionInfo contains the texts of the store listing in one locale
+type ExtensionInfo struct {
+	Id                 int          `json:"id"`
+	Locale             Locale       `json:"locale"`
+	Name               string       `json:"name"`
+	Description        string       `json:"description"`
+	InstallationManual string       `json:"installationManual"`
+	ShortDescription   string       `json:"shortDescription"`
+	Highlights         string       `json:"highlights"`
+	Features           string       `json:"features"`
+	MetaTitle          string       `json:"metaTitle"`
+	MetaDescription    string       `json:"metaDescription"`
+	Tags               []StoreTag   `json:"tags"`
+	Videos             []StoreVideo `json:"videos"`
+	Faqs               []StoreFaq   `json:"faqs"`
+	SupportInfo        interface{}  `json:"supportInfo"`
+}
+
 type CreateExtensionRequest struct {
 	Name       string `json:"name,omitempty"`
 	Generation struct {
@@ -382,7 +435,7 @@ func (e ProducerEndpoint) UpdateExtension(ctx context.Context, extension *Extens
 	}
 
 	// Patch the name
-	r, err := e.c.NewAuthenticatedRequest(ctx, "PUT", fmt.Sprintf("%s/plugins/%d", ApiUrl, extension.Id), bytes.NewBuffer(requestBody))
+	r, err := e.c.NewAuthenticatedRequest(ctx, "PUT", fmt.Sprintf("%s/plugins/%d", e.c.apiUrl(), extension.Id), bytes.NewBuffer(requestBody))
 	if err != nil {
 		return err
 	}
@@ -394,7 +447,7 @@ func (e ProducerEndpoint) UpdateExtension(ctx context.Context, extension *Extens
 
 func (e ProducerEndpoint) GetSoftwareVersions(ctx context.Context, generation string) (*SoftwareVersionList, error) {
 	errorFormat := "shopware_versions: %v"
-	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/pluginstatics/softwareVersions?filter=[{\"property\":\"pluginGeneration\",\"value\":\"%s\"},{\"property\":\"includeNonPublic\",\"value\":\"1\"}]", ApiUrl, generation), nil)
+	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/pluginstatics/softwareVersions?filter=[{\"property\":\"pluginGeneration\",\"value\":\"%s\"},{\"property\":\"includeNonPublic\",\"value\":\"1\"}]", e.c.apiUrl(), generation), nil)
 	if err != nil {
 		return nil, fmt.Errorf(errorFormat, err)
 	}
@@ -513,7 +566,7 @@ type ExtensionGeneralInformation struct {
 }
 
 func (e ProducerEndpoint) GetExtensionGeneralInfo(ctx context.Context) (*ExtensionGeneralInformation, error) {
-	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/pluginstatics/all", ApiUrl), nil)
+	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/pluginstatics/all", e.c.apiUrl()), nil)
 	if err != nil {
 		return nil, fmt.Errorf("GetExtensionGeneralInfo: %v", err)
 	}
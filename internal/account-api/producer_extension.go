@@ -6,13 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"image"
-	_ "image/gif"
-	_ "image/jpeg"
 	"image/png"
 	"io"
 	"mime/multipart"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/microcosm-cc/bluemonday"
 	"github.com/shyim/go-version"
@@ -67,10 +66,17 @@ type ExtensionCreate struct {
 	Version          string                     `json:"version"`
 }
 
+// GetExtensionBinaries fetches all binaries of the extension page by page
 func (e ProducerEndpoint) GetExtensionBinaries(ctx context.Context, extensionId int) ([]*ExtensionBinary, error) {
+	return FetchPages(ctx, 0, func(ctx context.Context, limit, offset int) ([]*ExtensionBinary, error) {
+		return e.getExtensionBinariesPage(ctx, extensionId, limit, offset)
+	})
+}
+
+func (e ProducerEndpoint) getExtensionBinariesPage(ctx context.Context, extensionId, limit, offset int) ([]*ExtensionBinary, error) {
 	errorFormat := "GetExtensionBinaries: %v"
 
-	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/producers/%d/plugins/%d/binaries", ApiUrl, e.producerId, extensionId), nil)
+	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/producers/%d/plugins/%d/binaries?limit=%d&offset=%d", e.c.apiUrl(), e.producerId, extensionId, limit, offset), nil)
 	if err != nil {
 		return nil, fmt.Errorf(errorFormat, err)
 	}
@@ -96,7 +102,7 @@ func (e ProducerEndpoint) UpdateExtensionBinaryInfo(ctx context.Context, extensi
 		return fmt.Errorf(errorFormat, err)
 	}
 
-	r, err := e.c.NewAuthenticatedRequest(ctx, "PUT", fmt.Sprintf("%s/producers/%d/plugins/%d/binaries/%d", ApiUrl, e.producerId, extensionId, update.Id), bytes.NewReader(content))
+	r, err := e.c.NewAuthenticatedRequest(ctx, "PUT", fmt.Sprintf("%s/producers/%d/plugins/%d/binaries/%d", e.c.apiUrl(), e.producerId, extensionId, update.Id), bytes.NewReader(content))
 	if err != nil {
 		return fmt.Errorf(errorFormat, err)
 	}
@@ -114,7 +120,7 @@ func (e ProducerEndpoint) CreateExtensionBinary(ctx context.Context, extensionId
 		return nil, fmt.Errorf(errorFormat, err)
 	}
 
-	r, err := e.c.NewAuthenticatedRequest(ctx, "POST", fmt.Sprintf("%s/producers/%d/plugins/%d/binaries", ApiUrl, e.producerId, extensionId), bytes.NewReader(createPayload))
+	r, err := e.c.NewAuthenticatedRequest(ctx, "POST", fmt.Sprintf("%s/producers/%d/plugins/%d/binaries", e.c.apiUrl(), e.producerId, extensionId), bytes.NewReader(createPayload))
 	if err != nil {
 		return nil, fmt.Errorf(errorFormat, err)
 	}
@@ -157,7 +163,7 @@ func (e ProducerEndpoint) UpdateExtensionBinaryFile(ctx context.Context, extensi
 		return fmt.Errorf(errorFormat, err)
 	}
 
-	r, err := e.c.NewAuthenticatedRequest(ctx, "POST", fmt.Sprintf("%s/producers/%d/plugins/%d/binaries/%d/file", ApiUrl, e.producerId, extensionId, binaryId), &b)
+	r, err := e.c.NewAuthenticatedRequest(ctx, "POST", fmt.Sprintf("%s/producers/%d/plugins/%d/binaries/%d/file", e.c.apiUrl(), e.producerId, extensionId, binaryId), &b)
 	if err != nil {
 		return fmt.Errorf(errorFormat, err)
 	}
@@ -169,36 +175,77 @@ func (e ProducerEndpoint) UpdateExtensionBinaryFile(ctx context.Context, extensi
 	return err
 }
 
+// DownloadExtensionBinaryFile writes the zip of the binary as customers receive it to target
+func (e ProducerEndpoint) DownloadExtensionBinaryFile(ctx context.Context, extensionId, binaryId int, target string) error {
+	errorFormat := "DownloadExtensionBinaryFile: %v"
+
+	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/producers/%d/plugins/%d/binaries/%d/file", e.c.apiUrl(), e.producerId, extensionId, binaryId), nil)
+	if err != nil {
+		return fmt.Errorf(errorFormat, err)
+	}
+
+	r.Header.Set("accept", "application/zip")
+
+	content, err := e.c.doRequest(r)
+	if err != nil {
+		return fmt.Errorf(errorFormat, err)
+	}
+
+	if err := os.WriteFile(target, content, os.ModePerm); err != nil {
+		return fmt.Errorf(errorFormat, err)
+	}
+
+	return nil
+}
+
 func (e ProducerEndpoint) UpdateExtensionIcon(ctx context.Context, extensionId int, iconFilePath string) error {
 	errorFormat := "UpdateExtensionIcon: %v"
 
 	var b bytes.Buffer
 	w := multipart.NewWriter(&b)
 
-	fileWriter, err := w.CreateFormFile("file", filepath.Base(iconFilePath))
+	iconFile, err := os.Open(iconFilePath)
 	if err != nil {
 		return fmt.Errorf(errorFormat, err)
 	}
 
-	iconFile, err := os.Open(iconFilePath)
+	defer func() {
+		_ = iconFile.Close()
+	}()
+
+	img, format, err := decodeStoreIcon(iconFile, iconFilePath)
 	if err != nil {
 		return fmt.Errorf(errorFormat, err)
 	}
 
-	img, _, err := image.Decode(iconFile)
+	fileName := filepath.Base(iconFilePath)
+
+	// The store accepts only png, jpeg and gif, everything else gets converted to png
+	needsConversion := format != "png" && format != "jpeg" && format != "gif"
+	if needsConversion {
+		fileName = strings.TrimSuffix(fileName, filepath.Ext(fileName)) + ".png"
+	}
+
+	fileWriter, err := w.CreateFormFile("file", fileName)
 	if err != nil {
 		return fmt.Errorf(errorFormat, err)
 	}
 
-	if img.Bounds().Dx() != 256 || img.Bounds().Dy() != 256 {
+	if img.Bounds().Dx() != storeIconSize || img.Bounds().Dy() != storeIconSize {
 		logging.FromContext(ctx).Infof("Resizing store icon image from %dx%d to 256x256", img.Bounds().Dx(), img.Bounds().Dy())
-		dst := image.NewRGBA(image.Rect(0, 0, 256, 256))
+		dst := image.NewRGBA(image.Rect(0, 0, storeIconSize, storeIconSize))
 
 		draw.CatmullRom.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Over, nil)
 
 		if err := png.Encode(fileWriter, dst); err != nil {
 			return fmt.Errorf(errorFormat, err)
 		}
+	} else if needsConversion {
+		logging.FromContext(ctx).Infof("Converting store icon image from %s to png", format)
+
+		if err := png.Encode(fileWriter, img); err != nil {
+			return fmt.Errorf(errorFormat, err)
+		}
 	} else {
 		logging.FromContext(ctx).Debugf("Store icon image is already 256x256, copying original file")
 		// If already 256x256, just copy the original file
@@ -210,16 +257,12 @@ func (e ProducerEndpoint) UpdateExtensionIcon(ctx context.Context, extensionId i
 		}
 	}
 
-	if err := iconFile.Close(); err != nil {
-		return fmt.Errorf(errorFormat, err)
-	}
-
 	err = w.Close()
 	if err != nil {
 		return fmt.Errorf(errorFormat, err)
 	}
 
-	r, err := e.c.NewAuthenticatedRequest(ctx, "POST", fmt.Sprintf("%s/plugins/%d/icon", ApiUrl, extensionId), &b)
+	r, err := e.c.NewAuthenticatedRequest(ctx, "POST", fmt.Sprintf("%s/plugins/%d/icon", e.c.apiUrl(), extensionId), &b)
 	if err != nil {
 		return fmt.Errorf(errorFormat, err)
 	}
@@ -241,13 +284,14 @@ type ExtensionImage struct {
 		Caption   string `json:"caption"`
 		Locale    Locale `json:"locale"`
 	} `json:"details"`
-	Priority int `json:"priority"`
+	Priority     int    `json:"priority"`
+	CreationDate string `json:"creationDate,omitempty"`
 }
 
 func (e ProducerEndpoint) GetExtensionImages(ctx context.Context, extensionId int) ([]*ExtensionImage, error) {
 	errorFormat := "GetExtensionImages: %v"
 
-	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/plugins/%d/pictures", ApiUrl, extensionId), nil)
+	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/plugins/%d/pictures", e.c.apiUrl(), extensionId), nil)
 	if err != nil {
 		return nil, fmt.Errorf(errorFormat, err)
 	}
@@ -268,7 +312,7 @@ func (e ProducerEndpoint) GetExtensionImages(ctx context.Context, extensionId in
 func (e ProducerEndpoint) DeleteExtensionImages(ctx context.Context, extensionId, imageId int) error {
 	errorFormat := "DeleteExtensionImages: %v"
 
-	r, err := e.c.NewAuthenticatedRequest(ctx, "DELETE", fmt.Sprintf("%s/plugins/%d/pictures/%d", ApiUrl, extensionId, imageId), nil)
+	r, err := e.c.NewAuthenticatedRequest(ctx, "DELETE", fmt.Sprintf("%s/plugins/%d/pictures/%d", e.c.apiUrl(), extensionId, imageId), nil)
 	if err != nil {
 		return fmt.Errorf(errorFormat, err)
 	}
@@ -286,7 +330,7 @@ func (e ProducerEndpoint) UpdateExtensionImage(ctx context.Context, extensionId
 		return fmt.Errorf(errorFormat, err)
 	}
 
-	r, err := e.c.NewAuthenticatedRequest(ctx, "PUT", fmt.Sprintf("%s/plugins/%d/pictures/%d", ApiUrl, extensionId, image.Id), bytes.NewReader(content))
+	r, err := e.c.NewAuthenticatedRequest(ctx, "PUT", fmt.Sprintf("%s/plugins/%d/pictures/%d", e.c.apiUrl(), extensionId, image.Id), bytes.NewReader(content))
 	if err != nil {
 		return fmt.Errorf(errorFormat, err)
 	}
@@ -320,7 +364,7 @@ func (e ProducerEndpoint) AddExtensionImage(ctx context.Context, extensionId int
 		return nil, fmt.Errorf(errorFormat, err)
 	}
 
-	r, err := e.c.NewAuthenticatedRequest(ctx, "POST", fmt.Sprintf("%s/plugins/%d/pictures", ApiUrl, extensionId), &b)
+	r, err := e.c.NewAuthenticatedRequest(ctx, "POST", fmt.Sprintf("%s/plugins/%d/pictures", e.c.apiUrl(), extensionId), &b)
 	if err != nil {
 		return nil, fmt.Errorf(errorFormat, err)
 	}
@@ -344,7 +388,7 @@ func (e ProducerEndpoint) AddExtensionImage(ctx context.Context, extensionId int
 func (e ProducerEndpoint) TriggerCodeReview(ctx context.Context, extensionId int) error {
 	errorFormat := "TriggerCodeReview: %v"
 
-	r, err := e.c.NewAuthenticatedRequest(ctx, "POST", fmt.Sprintf("%s/plugins/%d/reviews", ApiUrl, extensionId), nil)
+	r, err := e.c.NewAuthenticatedRequest(ctx, "POST", fmt.Sprintf("%s/plugins/%d/reviews", e.c.apiUrl(), extensionId), nil)
 	if err != nil {
 		return fmt.Errorf(errorFormat, err)
 	}
@@ -357,7 +401,7 @@ func (e ProducerEndpoint) TriggerCodeReview(ctx context.Context, extensionId int
 func (e ProducerEndpoint) GetBinaryReviewResults(ctx context.Context, extensionId, binaryId int) ([]BinaryReviewResult, error) {
 	errorFormat := "GetBinaryReviewResults: %v"
 
-	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/plugins/%d/binaries/%d/checkresults", ApiUrl, extensionId, binaryId), nil)
+	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/plugins/%d/binaries/%d/checkresults", e.c.apiUrl(), extensionId, binaryId), nil)
 	if err != nil {
 		return nil, fmt.Errorf(errorFormat, err)
 	}
@@ -429,6 +473,22 @@ func (review BinaryReviewResult) GetSummary() string {
 	return message
 }
 
+// NewSoftwareVersionList returns selectable versions for released Shopware versions, used when the versions of the account cannot be loaded
+func NewSoftwareVersionList(versions []string) SoftwareVersionList {
+	list := make(SoftwareVersionList, 0, len(versions))
+
+	for _, name := range versions {
+		v, err := version.NewVersion(name)
+		if err != nil || v.Prerelease() != "" {
+			continue
+		}
+
+		list = append(list, SoftwareVersion{Name: name, Selectable: true, Major: fmt.Sprintf("%d.%d", v.Major(), v.Minor())})
+	}
+
+	return list
+}
+
 func (list SoftwareVersionList) FilterOnVersion(constriant *version.Constraints) SoftwareVersionList {
 	newList := make(SoftwareVersionList, 0)
 
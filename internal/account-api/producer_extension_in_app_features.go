@@ -0,0 +1,63 @@
+package account_api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+type ExtensionInAppFeature struct {
+	Id         int                         `json:"id,omitempty"`
+	Identifier string                      `json:"identifier"`
+	Type       string                      `json:"type"`
+	Price      float64                     `json:"price"`
+	Active     bool                        `json:"active"`
+	Infos      []ExtensionInAppFeatureInfo `json:"infos"`
+}
+
+type ExtensionInAppFeatureInfo struct {
+	Locale      string `json:"locale"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (e ProducerEndpoint) GetExtensionInAppFeatures(ctx context.Context, extensionId int) ([]ExtensionInAppFeature, error) {
+	errorFormat := "GetExtensionInAppFeatures: %v"
+
+	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/producers/%d/plugins/%d/inappfeatures", e.c.apiUrl(), e.producerId, extensionId), nil)
+	if err != nil {
+		return nil, fmt.Errorf(errorFormat, err)
+	}
+
+	body, err := e.c.doRequest(r)
+	if err != nil {
+		return nil, fmt.Errorf(errorFormat, err)
+	}
+
+	var features []ExtensionInAppFeature
+	if err := json.Unmarshal(body, &features); err != nil {
+		return nil, fmt.Errorf(errorFormat, err)
+	}
+
+	return features, nil
+}
+
+// UpdateExtensionInAppFeatures replaces the in-app features, features missing in the list are deactivated by the store
+func (e ProducerEndpoint) UpdateExtensionInAppFeatures(ctx context.Context, extensionId int, features []ExtensionInAppFeature) error {
+	errorFormat := "UpdateExtensionInAppFeatures: %v"
+
+	content, err := json.Marshal(features)
+	if err != nil {
+		return fmt.Errorf(errorFormat, err)
+	}
+
+	r, err := e.c.NewAuthenticatedRequest(ctx, "PUT", fmt.Sprintf("%s/producers/%d/plugins/%d/inappfeatures", e.c.apiUrl(), e.producerId, extensionId), bytes.NewReader(content))
+	if err != nil {
+		return fmt.Errorf(errorFormat, err)
+	}
+
+	_, err = e.c.doRequest(r)
+
+	return err
+}
@@ -0,0 +1,62 @@
+package account_api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+type ExtensionPriceModel struct {
+	Id                 int                          `json:"id,omitempty"`
+	Type               string                       `json:"type"`
+	Price              float64                      `json:"price"`
+	TrialPhaseIncluded bool                         `json:"trialPhaseIncluded"`
+	CountryPrices      []ExtensionPriceModelCountry `json:"countryPrices"`
+}
+
+type ExtensionPriceModelCountry struct {
+	Country string  `json:"country"`
+	Price   float64 `json:"price"`
+}
+
+func (e ProducerEndpoint) GetExtensionPriceModels(ctx context.Context, extensionId int) ([]ExtensionPriceModel, error) {
+	errorFormat := "GetExtensionPriceModels: %v"
+
+	r, err := e.c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/producers/%d/plugins/%d/pricemodels", e.c.apiUrl(), e.producerId, extensionId), nil)
+	if err != nil {
+		return nil, fmt.Errorf(errorFormat, err)
+	}
+
+	body, err := e.c.doRequest(r)
+	if err != nil {
+		return nil, fmt.Errorf(errorFormat, err)
+	}
+
+	var models []ExtensionPriceModel
+	if err := json.Unmarshal(body, &models); err != nil {
+		return nil, fmt.Errorf(errorFormat, err)
+	}
+
+	return models, nil
+}
+
+func (e ProducerEndpoint) UpdateExtensionPriceModels(ctx context.Context, extensionId int, models []ExtensionPriceModel) error {
+	errorFormat := "UpdateExtensionPriceModels: %v"
+
+	content, err := json.Marshal(models)
+	if err != nil {
+		return fmt.Errorf(errorFormat, err)
+	}
+
+	r, err := e.c.NewAuthenticatedRequest(ctx, "PUT", fmt.Sprintf("%s/producers/%d/plugins/%d/pricemodels", e.c.apiUrl(), e.producerId, extensionId), bytes.NewReader(content))
+	if err != nil {
+		return fmt.Errorf(errorFormat, err)
+	}
+
+	if _, err := e.c.doRequest(r); err != nil {
+		return fmt.Errorf(errorFormat, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,221 @@
+package account_api
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/account-api/accountmock"
+)
+
+func newMockClient(t *testing.T) (*Client, *accountmock.Server) {
+	t.Helper()
+
+	// Keep the token cache away from the real user cache
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	mock := accountmock.New()
+	server := httptest.NewServer(mock)
+	t.Cleanup(server.Close)
+
+	client, err := NewApi(t.Context(), LoginRequest{Email: "producer@example.com", Password: "secret", ApiUrl: server.URL + "/"})
+	require.NoError(t, err)
+
+	return client, mock
+}
+
+func TestNewApiUsesConfiguredApiUrl(t *testing.T) {
+	client, mock := newMockClient(t)
+
+	assert.Equal(t, "/accesstokens", mock.Requests()[0].Path)
+	assert.NotEqual(t, DefaultApiUrl, client.BaseURL)
+	assert.Equal(t, 2001, client.GetActiveCompanyID())
+	assert.Equal(t, "Mock Company", client.GetActiveMembership().Company.Name)
+
+	// A second login is served from the token cache
+	cached, err := NewApi(t.Context(), LoginRequest{ApiUrl: client.BaseURL})
+	require.NoError(t, err)
+	assert.Equal(t, client.Token, cached.Token)
+	assert.Len(t, mock.Requests(), 2)
+
+	// The cached token does not belong to another server
+	_, err = createApiFromTokenCache(t.Context(), DefaultApiUrl)
+	assert.Error(t, err)
+}
+
+func TestLoginIgnoresTheTokenCache(t *testing.T) {
+	client, mock := newMockClient(t)
+
+	_, err := Login(t.Context(), LoginRequest{Email: "producer@example.com", Password: "secret", ApiUrl: client.BaseURL})
+	require.NoError(t, err)
+
+	requests := mock.Requests()
+	assert.Equal(t, "/accesstokens", requests[len(requests)-2].Path)
+}
+
+func TestProducerProfile(t *testing.T) {
+	client, _ := newMockClient(t)
+
+	p, err := client.Producer(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, accountmock.ProducerId, p.GetId())
+
+	profile, err := p.Profile(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "Mock Producer", profile.Name)
+}
+
+func TestProducerMembershipsSwitchesTheActiveCompany(t *testing.T) {
+	client, mock := newMockClient(t)
+
+	producers, err := client.ProducerMemberships(t.Context())
+	require.NoError(t, err)
+
+	require.Len(t, producers, 2)
+	assert.Equal(t, 2001, producers[0].Membership.Company.Id)
+	assert.Equal(t, 2002, producers[1].Membership.Company.Id)
+	assert.Equal(t, "Mock Producer", producers[1].Producer.Name)
+
+	// The mock only answers the allocations of the active company, the original one is active again afterwards
+	var changes []string
+
+	for _, request := range mock.Requests() {
+		if strings.HasSuffix(request.Path, "/memberships/change") {
+			changes = append(changes, string(request.Body))
+		}
+	}
+
+	assert.Equal(t, []string{`{"membership":{"id":3002}}`, `{"membership":{"id":3001}}`}, changes)
+	assert.Equal(t, 2001, client.GetActiveCompanyID())
+
+	p, err := client.Producer(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 2001, p.GetCompanyId())
+}
+
+func TestProducerExtensionBinaryLifecycle(t *testing.T) {
+	client, mock := newMockClient(t)
+
+	p, err := client.Producer(t.Context())
+	require.NoError(t, err)
+
+	ext, err := p.GetExtensionByName(t.Context(), "FroshTools")
+	require.NoError(t, err)
+	assert.Equal(t, "FroshTools", ext.Name)
+	assert.Equal(t, "platform", ext.Generation.Name)
+
+	binaries, err := p.GetExtensionBinaries(t.Context(), ext.Id)
+	require.NoError(t, err)
+	require.Len(t, binaries, 1)
+	assert.Equal(t, "1.0.0", binaries[0].Version)
+
+	versions, err := p.GetSoftwareVersions(t.Context(), "platform")
+	require.NoError(t, err)
+	assert.NotEmpty(t, *versions)
+
+	binary, err := p.CreateExtensionBinary(t.Context(), ext.Id, ExtensionCreate{Version: "1.1.0"})
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", binary.Version)
+
+	require.NoError(t, p.UpdateExtensionBinaryInfo(t.Context(), ext.Id, ExtensionUpdate{Id: binary.Id, SoftwareVersions: []string{"6.6.0.0"}}))
+
+	zipPath := filepath.Join(t.TempDir(), "FroshTools.zip")
+	require.NoError(t, os.WriteFile(zipPath, []byte("zip"), 0o644))
+	require.NoError(t, p.UpdateExtensionBinaryFile(t.Context(), ext.Id, binary.Id, zipPath))
+
+	downloadPath := filepath.Join(t.TempDir(), "download.zip")
+	require.NoError(t, p.DownloadExtensionBinaryFile(t.Context(), ext.Id, binary.Id, downloadPath))
+
+	downloaded, err := os.ReadFile(downloadPath)
+	require.NoError(t, err)
+	assert.Equal(t, "zip", string(downloaded))
+
+	reviews, err := p.GetBinaryReviewResults(t.Context(), ext.Id, binary.Id)
+	require.NoError(t, err)
+	assert.Empty(t, reviews)
+
+	require.NoError(t, p.TriggerCodeReview(t.Context(), ext.Id))
+
+	reviews, err = p.GetBinaryReviewResults(t.Context(), ext.Id, binary.Id)
+	require.NoError(t, err)
+	require.Len(t, reviews, 1)
+	assert.True(t, reviews[0].HasPassed())
+	assert.False(t, reviews[0].IsPending())
+
+	binaries, err = p.GetExtensionBinaries(t.Context(), ext.Id)
+	require.NoError(t, err)
+	assert.Len(t, binaries, 2)
+
+	var uploaded bool
+	for _, r := range mock.Requests() {
+		if r.Method == "POST" && r.Path == fmt.Sprintf("/producers/%d/plugins/%d/binaries/%d/file", accountmock.ProducerId, ext.Id, binary.Id) {
+			uploaded = true
+		}
+	}
+
+	assert.True(t, uploaded)
+}
+
+func TestProducerExtensionPriceModels(t *testing.T) {
+	client, _ := newMockClient(t)
+
+	p, err := client.Producer(t.Context())
+	require.NoError(t, err)
+
+	ext, err := p.GetExtensionByName(t.Context(), "FroshTools")
+	require.NoError(t, err)
+
+	models, err := p.GetExtensionPriceModels(t.Context(), ext.Id)
+	require.NoError(t, err)
+	assert.Empty(t, models)
+
+	require.NoError(t, p.UpdateExtensionPriceModels(t.Context(), ext.Id, []ExtensionPriceModel{{Type: "buy", Price: 49.99}}))
+}
+
+func TestProducerExtensionInAppFeatures(t *testing.T) {
+	client, mock := newMockClient(t)
+
+	p, err := client.Producer(t.Context())
+	require.NoError(t, err)
+
+	ext, err := p.GetExtensionByName(t.Context(), "FroshTools")
+	require.NoError(t, err)
+
+	features, err := p.GetExtensionInAppFeatures(t.Context(), ext.Id)
+	require.NoError(t, err)
+	assert.Empty(t, features)
+
+	require.NoError(t, p.UpdateExtensionInAppFeatures(t.Context(), ext.Id, []ExtensionInAppFeature{{Identifier: "premium-export", Type: "rent", Price: 9.99, Active: true}}))
+
+	requests := mock.Requests()
+	last := requests[len(requests)-1]
+
+	assert.Equal(t, "PUT", last.Method)
+	assert.Equal(t, fmt.Sprintf("/producers/%d/plugins/%d/inappfeatures", accountmock.ProducerId, ext.Id), last.Path)
+	assert.JSONEq(t, `[{"identifier":"premium-export","type":"rent","price":9.99,"active":true,"infos":null}]`, string(last.Body))
+}
+
+func TestProducerUnknownExtension(t *testing.T) {
+	client, _ := newMockClient(t)
+
+	p, err := client.Producer(t.Context())
+	require.NoError(t, err)
+
+	_, err = p.GetExtensionById(t.Context(), 1)
+	assert.Error(t, err)
+}
+
+func TestRequestWithoutTokenIsRejected(t *testing.T) {
+	client, _ := newMockClient(t)
+	client.Token.Token = "invalid"
+
+	_, err := client.Producer(t.Context())
+	assert.ErrorContains(t, err, "got status code 401")
+}
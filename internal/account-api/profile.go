@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
 
 	"github.com/shopware/shopware-cli/logging"
 )
@@ -13,12 +12,12 @@ import (
 func (c *Client) GetMyProfile(ctx context.Context) (*MyProfile, error) {
 	errorFormat := "GetMyProfile: %v"
 
-	request, err := c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/account/%d", ApiUrl, c.Token.UserAccountID), nil)
+	request, err := c.NewAuthenticatedRequest(ctx, "GET", fmt.Sprintf("%s/account/%d", c.apiUrl(), c.Token.UserAccountID), nil)
 	if err != nil {
 		return nil, fmt.Errorf(errorFormat, err)
 	}
 
-	resp, err := http.DefaultClient.Do(request)
+	resp, err := httpClient.Do(request)
 	if err != nil {
 		return nil, fmt.Errorf(errorFormat, err)
 	}
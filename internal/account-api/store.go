@@ -0,0 +1,167 @@
+package account_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// storeApiUrl is the public API of the Shopware Store, tests point it to a local server
+var storeApiUrl = DefaultApiUrl
+
+// StoreExtensionRelease is a released version of an extension in the Shopware Store
+type StoreExtensionRelease struct {
+	Version      string `json:"version"`
+	Text         string `json:"text"`
+	CreationDate string `json:"creationDate"`
+}
+
+// StoreExtensionBinary is an uploaded version of an extension with the Shopware versions it supports
+type StoreExtensionBinary struct {
+	Version                    string              `json:"version"`
+	CompatibleSoftwareVersions SoftwareVersionList `json:"compatibleSoftwareVersions"`
+}
+
+type StoreExtensionDetail struct {
+	Id        int                     `json:"id"`
+	Name      string                  `json:"name"`
+	Changelog []StoreExtensionRelease `json:"changelog"`
+	Binaries  []StoreExtensionBinary  `json:"binaries"`
+}
+
+// GetStoreExtensionDetail returns the store listing of the extension, nil is returned when the extension is not available in the store.
+// With a Shopware version only extensions compatible with it are found, an empty one searches all extensions.
+func GetStoreExtensionDetail(ctx context.Context, shopwareVersion, name string) (*StoreExtensionDetail, error) {
+	var listing []StoreExtensionDetail
+
+	query := map[string]string{"search": name, "limit": "25", "language": "en-GB"}
+	detailQuery := map[string]string{"language": "en-GB"}
+
+	if shopwareVersion != "" {
+		query["shopwareVersion"] = shopwareVersion
+		detailQuery["shopwareVersion"] = shopwareVersion
+	}
+
+	if err := getStoreJson(ctx, storeApiUrl+"/swplatform/extensionstore/extensions", query, &listing); err != nil {
+		return nil, err
+	}
+
+	for _, ext := range listing {
+		if !strings.EqualFold(ext.Name, name) {
+			continue
+		}
+
+		var detail StoreExtensionDetail
+
+		if err := getStoreJson(ctx, fmt.Sprintf("%s/swplatform/extensionstore/extensions/%d", storeApiUrl, ext.Id), detailQuery, &detail); err != nil {
+			return nil, err
+		}
+
+		return &detail, nil
+	}
+
+	return nil, nil
+}
+
+// LastRelease returns the date of the newest release of the extension, a zero time when it has none
+func (d StoreExtensionDetail) LastRelease() time.Time {
+	return lastReleaseDate(d.Changelog)
+}
+
+// HasBinaryForShopwareVersion returns whether any version of the extension supports the Shopware version
+func (d StoreExtensionDetail) HasBinaryForShopwareVersion(shopwareVersion string) bool {
+	for _, binary := range d.Binaries {
+		for _, softwareVersion := range binary.CompatibleSoftwareVersions {
+			if softwareVersion.Name == shopwareVersion {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// SupportsShopwareVersion returns whether the binary of the version supports the Shopware version, known is false when the store has no binary for the version
+func (d StoreExtensionDetail) SupportsShopwareVersion(version, shopwareVersion string) (supported bool, known bool) {
+	for _, binary := range d.Binaries {
+		if binary.Version != version {
+			continue
+		}
+
+		for _, softwareVersion := range binary.CompatibleSoftwareVersions {
+			if softwareVersion.Name == shopwareVersion {
+				return true, true
+			}
+		}
+
+		return false, true
+	}
+
+	return false, false
+}
+
+func lastReleaseDate(releases []StoreExtensionRelease) time.Time {
+	var last time.Time
+
+	for _, release := range releases {
+		date, err := ParseDate(release.CreationDate)
+		if err != nil {
+			continue
+		}
+
+		if date.After(last) {
+			last = date
+		}
+	}
+
+	return last
+}
+
+// ParseDate parses the dates of the store and account API, which are either RFC 3339 or "2006-01-02 15:04:05"
+func ParseDate(value string) (time.Time, error) {
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Parse(time.DateTime, value)
+	}
+
+	return date, nil
+}
+
+func getStoreJson(ctx context.Context, url string, query map[string]string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	q := req.URL.Query()
+	for key, value := range query {
+		q.Set(key, value)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", httpUserAgent)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logging.FromContext(ctx).Errorf("Cannot close response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API returned non-OK status: %d\n%s", resp.StatusCode, string(body))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(target)
+}
@@ -0,0 +1,33 @@
+package account_api
+
+import (
+	"sync"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+var storeHTMLPolicy = sync.OnceValue(func() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+
+	p.AllowElements("h1", "h2", "h3", "h4", "h5", "h6", "p", "br", "hr", "strong", "b", "em", "i", "u", "s", "blockquote", "code", "pre", "ul", "ol", "li")
+	p.AllowElements("table", "thead", "tbody", "tr", "th", "td", "div", "span")
+	p.AllowAttrs("src", "alt", "width", "height").OnElements("img")
+
+	p.AllowAttrs("href").OnElements("a")
+	p.AllowAttrs("target").Matching(bluemonday.Paragraph).OnElements("a")
+	p.AllowURLSchemes("http", "https", "mailto")
+	p.RequireParseableURLs(true)
+
+	return p
+})
+
+// StoreHTMLPolicy returns the markup the store preview renders of descriptions, installation manuals and changelogs.
+// It is no copy of the rules of the Shopware Store, the texts are uploaded unchanged and the store applies its own rules.
+func StoreHTMLPolicy() *bluemonday.Policy {
+	return storeHTMLPolicy()
+}
+
+// SanitizeStoreHTML removes the markup outside of StoreHTMLPolicy, so the preview can embed the text safely
+func SanitizeStoreHTML(content string) string {
+	return StoreHTMLPolicy().Sanitize(content)
+}
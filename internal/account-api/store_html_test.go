@@ -0,0 +1,24 @@
+package account_api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeStoreHTMLKeepsAllowedMarkup(t *testing.T) {
+	content := `<h2>Features</h2>
+<p>Adds <strong>fast</strong> search, see <a href="https://example.com/docs">the docs</a>.</p>
+<ul>
+<li>One</li>
+</ul>`
+
+	assert.Equal(t, content, SanitizeStoreHTML(content))
+}
+
+func TestSanitizeStoreHTMLRemovesDisallowedMarkup(t *testing.T) {
+	assert.Equal(t, "<p>Hello</p>", SanitizeStoreHTML(`<p style="color: red" class="lead">Hello<script>alert(1)</script></p>`))
+	assert.Equal(t, "<span>Click</span>", SanitizeStoreHTML(`<span onclick="alert(1)">Click</span><iframe src="https://example.com"></iframe>`))
+	assert.Equal(t, "link", SanitizeStoreHTML(`<a href="javascript:alert(1)">link</a>`))
+	assert.Equal(t, `<img src="https://example.com/a.png">`, SanitizeStoreHTML(`<img src="https://example.com/a.png" onerror="alert(1)">`))
+}
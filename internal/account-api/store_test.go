@@ -0,0 +1,88 @@
+package account_api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastReleaseDate(t *testing.T) {
+	releases := []StoreExtensionRelease{
+		{Version: "1.0.0", CreationDate: "2022-01-10T10:00:00+01:00"},
+		{Version: "1.2.0", CreationDate: "2023-05-02 08:30:00"},
+		{Version: "1.1.0", CreationDate: "2022-11-01T10:00:00+01:00"},
+		{Version: "0.9.0", CreationDate: "invalid"},
+	}
+
+	assert.Equal(t, time.Date(2023, 5, 2, 8, 30, 0, 0, time.UTC), lastReleaseDate(releases))
+	assert.True(t, lastReleaseDate(nil).IsZero())
+}
+
+func TestSupportsShopwareVersion(t *testing.T) {
+	detail := StoreExtensionDetail{Binaries: []StoreExtensionBinary{
+		{Version: "2.0.0", CompatibleSoftwareVersions: SoftwareVersionList{{Name: "6.6.0.0"}}},
+		{Version: "1.0.0", CompatibleSoftwareVersions: SoftwareVersionList{{Name: "6.5.8.0"}, {Name: "6.6.0.0"}}},
+	}}
+
+	supported, known := detail.SupportsShopwareVersion("2.0.0", "6.5.8.0")
+	assert.False(t, supported)
+	assert.True(t, known)
+
+	supported, known = detail.SupportsShopwareVersion("1.0.0", "6.5.8.0")
+	assert.True(t, supported)
+	assert.True(t, known)
+
+	_, known = detail.SupportsShopwareVersion("3.0.0", "6.5.8.0")
+	assert.False(t, known)
+}
+
+func TestHasBinaryForShopwareVersion(t *testing.T) {
+	detail := StoreExtensionDetail{Binaries: []StoreExtensionBinary{
+		{Version: "2.0.0", CompatibleSoftwareVersions: SoftwareVersionList{{Name: "6.6.0.0"}}},
+		{Version: "1.0.0", CompatibleSoftwareVersions: SoftwareVersionList{{Name: "6.5.8.0"}}},
+	}}
+
+	assert.True(t, detail.HasBinaryForShopwareVersion("6.5.8.0"))
+	assert.False(t, detail.HasBinaryForShopwareVersion("6.4.20.0"))
+}
+
+func TestGetStoreExtensionDetailWithoutShopwareVersion(t *testing.T) {
+	var queries []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+
+		if r.URL.Path == "/swplatform/extensionstore/extensions" {
+			_ = json.NewEncoder(w).Encode([]StoreExtensionDetail{{Id: 1, Name: "OtherExtension"}, {Id: 2, Name: "FroshTools"}})
+			return
+		}
+
+		assert.Equal(t, "/swplatform/extensionstore/extensions/2", r.URL.Path)
+		_ = json.NewEncoder(w).Encode(StoreExtensionDetail{Id: 2, Name: "FroshTools"})
+	}))
+	t.Cleanup(server.Close)
+
+	previous := storeApiUrl
+	storeApiUrl = server.URL
+	t.Cleanup(func() { storeApiUrl = previous })
+
+	detail, err := GetStoreExtensionDetail(t.Context(), "", "FroshTools")
+	require.NoError(t, err)
+	require.NotNil(t, detail)
+	assert.Equal(t, 2, detail.Id)
+
+	for _, query := range queries {
+		assert.NotContains(t, query, "shopwareVersion")
+	}
+
+	queries = nil
+
+	_, err = GetStoreExtensionDetail(t.Context(), "6.6.0.0", "FroshTools")
+	require.NoError(t, err)
+	assert.Contains(t, queries[0], "shopwareVersion=6.6.0.0")
+}
@@ -50,7 +50,7 @@ func GetFutureExtensionUpdates(ctx context.Context, currentVersion string, futur
 	req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
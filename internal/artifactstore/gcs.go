@@ -0,0 +1,171 @@
+package artifactstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const gcsDefaultEndpoint = "https://storage.googleapis.com"
+
+// gcsStore uploads the artifacts with the JSON API of Google Cloud Storage. The access token is taken from
+// GOOGLE_OAUTH_ACCESS_TOKEN or gcloud auth print-access-token, STORAGE_EMULATOR_HOST points to an emulator.
+type gcsStore struct {
+	bucket   string
+	prefix   string
+	endpoint string
+	token    func(ctx context.Context) (string, error)
+	client   *http.Client
+}
+
+type gcsObject struct {
+	Name        string            `json:"name"`
+	ContentType string            `json:"contentType"`
+	Metadata    map[string]string `json:"metadata"`
+}
+
+func newGCSStore(u *url.URL) (*gcsStore, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("the output %s has no bucket", u.String())
+	}
+
+	store := &gcsStore{
+		bucket:   u.Host,
+		prefix:   u.Path,
+		endpoint: gcsDefaultEndpoint,
+		token:    gcloudAccessToken,
+		client:   &http.Client{Timeout: 10 * time.Minute},
+	}
+
+	if emulator := os.Getenv("STORAGE_EMULATOR_HOST"); emulator != "" {
+		if !strings.Contains(emulator, "://") {
+			emulator = "http://" + emulator
+		}
+
+		store.endpoint = strings.TrimRight(emulator, "/")
+		store.token = func(context.Context) (string, error) { return os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"), nil }
+	}
+
+	return store, nil
+}
+
+func gcloudAccessToken(ctx context.Context) (string, error) {
+	if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		return token, nil
+	}
+
+	output, err := exec.CommandContext(ctx, "gcloud", "auth", "print-access-token").Output()
+	if err != nil {
+		return "", fmt.Errorf("uploading to Google Cloud Storage requires GOOGLE_OAUTH_ACCESS_TOKEN or a logged in gcloud: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+func (s *gcsStore) Put(ctx context.Context, artifacts []*Artifact) ([]string, error) {
+	token, err := s.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	locations := make([]string, 0, len(artifacts))
+
+	for _, artifact := range artifacts {
+		key := objectKey(s.prefix, artifact)
+
+		if err := s.putObject(ctx, token, key, artifact); err != nil {
+			return nil, err
+		}
+
+		locations = append(locations, fmt.Sprintf("gs://%s/%s", s.bucket, key))
+	}
+
+	return locations, nil
+}
+
+// putObject uses a multipart upload, so the checksum metadata is stored together with the content
+func (s *gcsStore) putObject(ctx context.Context, token, key string, artifact *Artifact) error {
+	metadata, err := json.Marshal(gcsObject{Name: key, ContentType: artifact.ContentType, Metadata: checksumMetadata(artifact)})
+	if err != nil {
+		return err
+	}
+
+	body, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+
+	go func() {
+		writer.CloseWithError(writeGCSMultipart(form, metadata, artifact))
+	}()
+
+	uploadURL := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?uploadType=multipart", s.endpoint, url.PathEscape(s.bucket))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, body)
+	if err != nil {
+		_ = body.Close()
+		return err
+	}
+
+	req.Header.Set("Content-Type", "multipart/related; boundary="+form.Boundary())
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("upload %s to Google Cloud Storage: %w", artifact.Name, err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		content, _ := io.ReadAll(resp.Body)
+
+		return fmt.Errorf("upload %s to Google Cloud Storage: %s: %s", artifact.Name, resp.Status, strings.TrimSpace(string(content)))
+	}
+
+	return nil
+}
+
+func writeGCSMultipart(form *multipart.Writer, metadata []byte, artifact *Artifact) error {
+	metadataPart, err := form.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/json; charset=UTF-8"}})
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(metadataPart, bytes.NewReader(metadata)); err != nil {
+		return err
+	}
+
+	contentPart, err := form.CreatePart(textproto.MIMEHeader{"Content-Type": {artifact.ContentType}})
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(artifact.Path)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	if _, err := io.Copy(contentPart, file); err != nil {
+		return err
+	}
+
+	return form.Close()
+}
@@ -0,0 +1,73 @@
+package artifactstore
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGCSStorePut(t *testing.T) {
+	artifact := createTestArtifact(t, "FroshTools-1.0.0.zip", "content")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/upload/storage/v1/b/releases/o", r.URL.Path)
+		assert.Equal(t, "multipart", r.URL.Query().Get("uploadType"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		require.NoError(t, err)
+		assert.Equal(t, "multipart/related", mediaType)
+
+		reader := multipart.NewReader(r.Body, params["boundary"])
+
+		metadataPart, err := reader.NextPart()
+		require.NoError(t, err)
+
+		var object gcsObject
+		require.NoError(t, json.NewDecoder(metadataPart).Decode(&object))
+		assert.Equal(t, gcsObject{Name: "frosh/FroshTools-1.0.0.zip", ContentType: "application/zip", Metadata: map[string]string{"sha256": artifact.SHA256, "version": "1.0.0"}}, object)
+
+		contentPart, err := reader.NextPart()
+		require.NoError(t, err)
+
+		content, _ := io.ReadAll(contentPart)
+		assert.Equal(t, "content", string(content))
+
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("STORAGE_EMULATOR_HOST", server.URL)
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "token")
+
+	store, err := New("gs://releases/frosh")
+	require.NoError(t, err)
+
+	locations, err := store.Put(t.Context(), []*Artifact{artifact})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gs://releases/frosh/FroshTools-1.0.0.zip"}, locations)
+}
+
+func TestGCSStoreError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("access denied"))
+	}))
+	defer server.Close()
+
+	t.Setenv("STORAGE_EMULATOR_HOST", server.URL)
+
+	store, err := New("gs://releases")
+	require.NoError(t, err)
+
+	_, err = store.Put(t.Context(), []*Artifact{createTestArtifact(t, "FroshTools.zip", "content")})
+	assert.ErrorContains(t, err, "403 Forbidden: access denied")
+}
@@ -0,0 +1,72 @@
+package artifactstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// localStore copies the artifacts into a directory and writes a .sha256 file next to each of them
+type localStore struct {
+	dir string
+}
+
+func newLocalStore(dir string) *localStore {
+	return &localStore{dir: dir}
+}
+
+func (s *localStore) Put(_ context.Context, artifacts []*Artifact) ([]string, error) {
+	if err := os.MkdirAll(s.dir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("create output directory: %w", err)
+	}
+
+	locations := make([]string, 0, len(artifacts))
+
+	for _, artifact := range artifacts {
+		target := filepath.Join(s.dir, artifact.Name)
+
+		if err := copyArtifact(artifact.Path, target); err != nil {
+			return nil, err
+		}
+
+		// The format of sha256sum, so the file can be verified with sha256sum -c
+		if err := os.WriteFile(target+".sha256", fmt.Appendf(nil, "%s  %s\n", artifact.SHA256, artifact.Name), os.ModePerm); err != nil {
+			return nil, err
+		}
+
+		locations = append(locations, target)
+	}
+
+	return locations, nil
+}
+
+func copyArtifact(source, target string) error {
+	if sourceAbs, err := filepath.Abs(source); err == nil {
+		if targetAbs, err := filepath.Abs(target); err == nil && sourceAbs == targetAbs {
+			return nil
+		}
+	}
+
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = in.Close()
+	}()
+
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("copy %s: %w", source, err)
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,387 @@
+package artifactstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	ociEmptyMediaType    = "application/vnd.oci.empty.v1+json"
+	ociArtifactType      = "application/vnd.shopware.extension.v1"
+	ociEmptyDigest       = "sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"
+)
+
+var (
+	ociTagRegExp            = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9._-]{0,127}$`)
+	ociInvalidTagCharRegExp = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+	ociChallengeParamRegExp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+)
+
+// ociStore pushes the artifacts as layers of one OCI artifact manifest, like oras does. The version of the first artifact
+// is the default tag. The credentials are taken from OCI_USERNAME and OCI_PASSWORD, registries on localhost are accessed
+// with plain HTTP.
+type ociStore struct {
+	registry   string
+	repository string
+	tag        string
+	scheme     string
+	username   string
+	password   string
+	client     *http.Client
+	// annotations are added to the manifest
+	annotations map[string]string
+
+	// authorization is the header value after the registry asked for authentication once
+	authorization string
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ociManifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType"`
+	ArtifactType  string            `json:"artifactType"`
+	Config        ociDescriptor     `json:"config"`
+	Layers        []ociDescriptor   `json:"layers"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+func newOCIStore(u *url.URL) (*ociStore, error) {
+	repository := strings.Trim(u.Path, "/")
+
+	if u.Host == "" || repository == "" {
+		return nil, fmt.Errorf("the output %s must look like oci://registry/repository[:tag]", u.String())
+	}
+
+	tag := ""
+	if index := strings.LastIndex(repository, ":"); index != -1 {
+		repository, tag = repository[:index], repository[index+1:]
+
+		if !ociTagRegExp.MatchString(tag) {
+			return nil, fmt.Errorf("invalid tag %s in the output %s", tag, u.String())
+		}
+	}
+
+	scheme := "https"
+	if host, _, err := net.SplitHostPort(u.Host); (err == nil && isLocalHost(host)) || isLocalHost(u.Host) {
+		scheme = "http"
+	}
+
+	return &ociStore{
+		registry:   u.Host,
+		repository: repository,
+		tag:        tag,
+		scheme:     scheme,
+		username:   os.Getenv("OCI_USERNAME"),
+		password:   os.Getenv("OCI_PASSWORD"),
+		client:     &http.Client{Timeout: 10 * time.Minute},
+	}, nil
+}
+
+// NewOCI returns the store for a reference like ghcr.io/acme/plugin:1.2.3, the annotations are added to the manifest
+func NewOCI(reference string, annotations map[string]string) (Store, error) {
+	u, err := url.Parse("oci://" + strings.TrimPrefix(reference, "oci://"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference %s: %w", reference, err)
+	}
+
+	store, err := newOCIStore(u)
+	if err != nil {
+		return nil, err
+	}
+
+	store.annotations = annotations
+
+	return store, nil
+}
+
+func isLocalHost(host string) bool {
+	return host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
+
+// ociTagOfVersion turns the version into a valid tag, e.g. the + of build metadata is not allowed in tags
+func ociTagOfVersion(version string) string {
+	if version == "" {
+		return "latest"
+	}
+
+	tag := ociInvalidTagCharRegExp.ReplaceAllString(version, "-")
+
+	if !ociTagRegExp.MatchString(tag) {
+		return "latest"
+	}
+
+	return tag
+}
+
+func (s *ociStore) Put(ctx context.Context, artifacts []*Artifact) ([]string, error) {
+	if len(artifacts) == 0 {
+		return nil, nil
+	}
+
+	if err := s.pushBlob(ctx, ociEmptyDigest, 2, func() (io.Reader, error) { return strings.NewReader("{}"), nil }); err != nil {
+		return nil, err
+	}
+
+	manifest := ociManifest{
+		SchemaVersion: 2,
+		MediaType:     ociManifestMediaType,
+		ArtifactType:  ociArtifactType,
+		Config:        ociDescriptor{MediaType: ociEmptyMediaType, Digest: ociEmptyDigest, Size: 2},
+		Annotations: map[string]string{
+			"org.opencontainers.image.created": time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+
+	for _, artifact := range artifacts {
+		digest := "sha256:" + artifact.SHA256
+
+		if err := s.pushBlob(ctx, digest, artifact.Size, func() (io.Reader, error) { return os.Open(artifact.Path) }); err != nil {
+			return nil, fmt.Errorf("push %s: %w", artifact.Name, err)
+		}
+
+		manifest.Layers = append(manifest.Layers, ociDescriptor{
+			MediaType:   artifact.ContentType,
+			Digest:      digest,
+			Size:        artifact.Size,
+			Annotations: map[string]string{"org.opencontainers.image.title": artifact.Name},
+		})
+	}
+
+	if artifacts[0].Version != "" {
+		manifest.Annotations["org.opencontainers.image.version"] = artifacts[0].Version
+	}
+
+	for key, value := range s.annotations {
+		manifest.Annotations[key] = value
+	}
+
+	tag := s.tag
+	if tag == "" {
+		tag = ociTagOfVersion(artifacts[0].Version)
+	}
+
+	content, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(ctx, http.MethodPut, s.url("manifests/"+tag), ociManifestMediaType, int64(len(content)), func() (io.Reader, error) { return bytes.NewReader(content), nil })
+	if err != nil {
+		return nil, fmt.Errorf("push manifest: %w", err)
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("push manifest: unexpected status %s", resp.Status)
+	}
+
+	manifestDigest := sha256.Sum256(content)
+
+	return []string{fmt.Sprintf("oci://%s/%s:%s@sha256:%s", s.registry, s.repository, tag, hex.EncodeToString(manifestDigest[:]))}, nil
+}
+
+func (s *ociStore) url(path string) string {
+	return fmt.Sprintf("%s://%s/v2/%s/%s", s.scheme, s.registry, s.repository, path)
+}
+
+// pushBlob uploads the blob with a monolithic upload, blobs known to the registry are skipped
+func (s *ociStore) pushBlob(ctx context.Context, digest string, size int64, open func() (io.Reader, error)) error {
+	resp, err := s.do(ctx, http.MethodHead, s.url("blobs/"+digest), "", 0, nil)
+	if err != nil {
+		return err
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	resp, err = s.do(ctx, http.MethodPost, s.url("blobs/uploads/"), "", 0, nil)
+	if err != nil {
+		return err
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("start blob upload: unexpected status %s", resp.Status)
+	}
+
+	location, err := resp.Request.URL.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return fmt.Errorf("invalid upload location: %w", err)
+	}
+
+	query := location.Query()
+	query.Set("digest", digest)
+	location.RawQuery = query.Encode()
+
+	resp, err = s.do(ctx, http.MethodPut, location.String(), "application/octet-stream", size, open)
+	if err != nil {
+		return err
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("upload blob %s: unexpected status %s", digest, resp.Status)
+	}
+
+	return nil
+}
+
+// do sends the request and authenticates once, when the registry answers with 401.
+// The body is opened for every attempt, as it is consumed by the first one.
+func (s *ociStore) do(ctx context.Context, method, target, contentType string, size int64, open func() (io.Reader, error)) (*http.Response, error) {
+	resp, err := s.send(ctx, method, target, contentType, size, open)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	_ = resp.Body.Close()
+
+	if err := s.authenticate(ctx, resp.Header.Get("WWW-Authenticate")); err != nil {
+		return nil, err
+	}
+
+	return s.send(ctx, method, target, contentType, size, open)
+}
+
+func (s *ociStore) send(ctx context.Context, method, target, contentType string, size int64, open func() (io.Reader, error)) (*http.Response, error) {
+	var body io.Reader = http.NoBody
+
+	if open != nil {
+		reader, err := open()
+		if err != nil {
+			return nil, err
+		}
+
+		if closer, ok := reader.(io.Closer); ok {
+			defer func() {
+				_ = closer.Close()
+			}()
+		}
+
+		body = reader
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if open != nil {
+		req.ContentLength = size
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	if s.authorization != "" {
+		req.Header.Set("Authorization", s.authorization)
+	}
+
+	return s.client.Do(req)
+}
+
+// authenticate handles the Basic and Bearer challenges of the distribution spec
+func (s *ociStore) authenticate(ctx context.Context, challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if s.username == "" {
+			return fmt.Errorf("the registry %s requires OCI_USERNAME and OCI_PASSWORD", s.registry)
+		}
+
+		s.authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(s.username+":"+s.password))
+
+		return nil
+	case "bearer":
+		return s.fetchBearerToken(ctx, params)
+	}
+
+	return fmt.Errorf("the registry %s requested the unsupported authentication %q", s.registry, challenge)
+}
+
+func (s *ociStore) fetchBearerToken(ctx context.Context, params string) error {
+	values := map[string]string{}
+	for _, match := range ociChallengeParamRegExp.FindAllStringSubmatch(params, -1) {
+		values[match[1]] = match[2]
+	}
+
+	realm, err := url.Parse(values["realm"])
+	if err != nil || values["realm"] == "" {
+		return fmt.Errorf("the registry %s sent an invalid authentication realm", s.registry)
+	}
+
+	query := realm.Query()
+	if values["service"] != "" {
+		query.Set("service", values["service"])
+	}
+
+	query.Set("scope", fmt.Sprintf("repository:%s:pull,push", s.repository))
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	if s.username != "" {
+		req.SetBasicAuth(s.username, s.password)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetch registry token: %w", err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetch registry token: unexpected status %s, check OCI_USERNAME and OCI_PASSWORD", resp.Status)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return fmt.Errorf("decode registry token: %w", err)
+	}
+
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+
+	s.authorization = "Bearer " + token.Token
+
+	return nil
+}
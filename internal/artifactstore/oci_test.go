@@ -0,0 +1,158 @@
+package artifactstore
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRegistry implements the push endpoints of the distribution spec and requires a bearer token
+type fakeRegistry struct {
+	mu        sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte
+}
+
+func newFakeRegistry(t *testing.T) (*fakeRegistry, *httptest.Server) {
+	t.Helper()
+
+	registry := &fakeRegistry{blobs: map[string][]byte{}, manifests: map[string][]byte{}}
+
+	var server *httptest.Server
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		registry.mu.Lock()
+		defer registry.mu.Unlock()
+
+		if r.URL.Path == "/token" {
+			user, password, _ := r.BasicAuth()
+			assert.Equal(t, "frosh", user)
+			assert.Equal(t, "secret", password)
+			assert.Equal(t, "repository:frosh/tools:pull,push", r.URL.Query().Get("scope"))
+
+			_, _ = w.Write([]byte(`{"token": "registry-token"}`))
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer registry-token" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		path := strings.TrimPrefix(r.URL.Path, "/v2/frosh/tools/")
+
+		switch {
+		case r.Method == http.MethodHead && strings.HasPrefix(path, "blobs/"):
+			if _, ok := registry.blobs[strings.TrimPrefix(path, "blobs/")]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case r.Method == http.MethodPost && path == "blobs/uploads/":
+			w.Header().Set("Location", "/v2/frosh/tools/blobs/uploads/session?state=1")
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodPut && path == "blobs/uploads/session":
+			assert.Equal(t, "1", r.URL.Query().Get("state"))
+
+			content, _ := io.ReadAll(r.Body)
+			registry.blobs[r.URL.Query().Get("digest")] = content
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPut && strings.HasPrefix(path, "manifests/"):
+			assert.Equal(t, ociManifestMediaType, r.Header.Get("Content-Type"))
+
+			content, _ := io.ReadAll(r.Body)
+			registry.manifests[strings.TrimPrefix(path, "manifests/")] = content
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	t.Cleanup(server.Close)
+
+	return registry, server
+}
+
+func TestOCIStorePut(t *testing.T) {
+	registry, server := newFakeRegistry(t)
+
+	t.Setenv("OCI_USERNAME", "frosh")
+	t.Setenv("OCI_PASSWORD", "secret")
+
+	zip := createTestArtifact(t, "FroshTools-1.0.0+build.1.zip", "content")
+	zip.Version = "1.0.0+build.1"
+	sbom := createTestArtifact(t, "FroshTools-1.0.0+build.1.spdx.json", "{}")
+
+	store, err := New("oci://" + strings.TrimPrefix(server.URL, "http://") + "/frosh/tools")
+	require.NoError(t, err)
+
+	locations, err := store.Put(t.Context(), []*Artifact{zip, sbom})
+	require.NoError(t, err)
+
+	assert.Len(t, locations, 1)
+	assert.Contains(t, locations[0], "/frosh/tools:1.0.0-build.1@sha256:")
+
+	assert.Equal(t, []byte("content"), registry.blobs["sha256:"+zip.SHA256])
+	assert.Equal(t, []byte("{}"), registry.blobs[ociEmptyDigest])
+
+	var manifest ociManifest
+	require.NoError(t, json.Unmarshal(registry.manifests["1.0.0-build.1"], &manifest))
+
+	assert.Equal(t, ociArtifactType, manifest.ArtifactType)
+	assert.Equal(t, ociEmptyDigest, manifest.Config.Digest)
+	assert.Equal(t, "1.0.0+build.1", manifest.Annotations["org.opencontainers.image.version"])
+	assert.Len(t, manifest.Layers, 2)
+	assert.Equal(t, ociDescriptor{
+		MediaType:   "application/zip",
+		Digest:      "sha256:" + zip.SHA256,
+		Size:        7,
+		Annotations: map[string]string{"org.opencontainers.image.title": "FroshTools-1.0.0+build.1.zip"},
+	}, manifest.Layers[0])
+	assert.Equal(t, "application/json", manifest.Layers[1].MediaType)
+}
+
+func TestOCIStoreTag(t *testing.T) {
+	store, err := New("oci://ghcr.io/frosh/tools:stable")
+	require.NoError(t, err)
+
+	oci := store.(*ociStore)
+	assert.Equal(t, "ghcr.io", oci.registry)
+	assert.Equal(t, "frosh/tools", oci.repository)
+	assert.Equal(t, "stable", oci.tag)
+	assert.Equal(t, "https", oci.scheme)
+
+	_, err = New("oci://ghcr.io/frosh/tools:-invalid")
+	assert.ErrorContains(t, err, "invalid tag")
+
+	assert.Equal(t, "latest", ociTagOfVersion(""))
+	assert.Equal(t, "1.0.0-build.1", ociTagOfVersion("1.0.0+build.1"))
+}
+
+func TestNewOCIAnnotations(t *testing.T) {
+	registry, server := newFakeRegistry(t)
+
+	t.Setenv("OCI_USERNAME", "frosh")
+	t.Setenv("OCI_PASSWORD", "secret")
+
+	store, err := NewOCI(strings.TrimPrefix(server.URL, "http://")+"/frosh/tools:1.2.3", map[string]string{
+		"org.opencontainers.image.revision":    "abc123",
+		"com.shopware.extension.compatibility": "~6.6.0",
+	})
+	require.NoError(t, err)
+
+	_, err = store.Put(t.Context(), []*Artifact{createTestArtifact(t, "FroshTools.zip", "content")})
+	require.NoError(t, err)
+
+	var manifest ociManifest
+	require.NoError(t, json.Unmarshal(registry.manifests["1.2.3"], &manifest))
+
+	assert.Equal(t, "abc123", manifest.Annotations["org.opencontainers.image.revision"])
+	assert.Equal(t, "~6.6.0", manifest.Annotations["com.shopware.extension.compatibility"])
+	assert.Equal(t, "1.0.0", manifest.Annotations["org.opencontainers.image.version"])
+}
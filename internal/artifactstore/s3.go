@@ -0,0 +1,271 @@
+package artifactstore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+const s3DefaultRegion = "us-east-1"
+
+type s3Credentials struct {
+	accessKeyId     string
+	secretAccessKey string
+	sessionToken    string
+}
+
+// s3Store uploads the artifacts with the credentials of the AWS environment variables, otherwise with the credentials
+// the AWS CLI resolves with its credential chain (profiles, SSO, IRSA, ECS and EC2 instance roles).
+// S3 compatible storages like MinIO are used with AWS_ENDPOINT_URL_S3 or AWS_ENDPOINT_URL.
+type s3Store struct {
+	bucket      string
+	prefix      string
+	region      string
+	endpoint    string
+	credentials func(ctx context.Context) (s3Credentials, error)
+	client      *http.Client
+	now         func() time.Time
+}
+
+// awsCLICredentials is the output of aws configure export-credentials --format process
+type awsCLICredentials struct {
+	AccessKeyId     string `json:"AccessKeyId"`
+	SecretAccessKey string `json:"SecretAccessKey"`
+	SessionToken    string `json:"SessionToken"`
+}
+
+func newS3Store(u *url.URL) (*s3Store, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("the output %s has no bucket", u.String())
+	}
+
+	region := firstEnv("AWS_REGION", "AWS_DEFAULT_REGION")
+	if region == "" {
+		region = s3DefaultRegion
+	}
+
+	return &s3Store{
+		bucket:      u.Host,
+		prefix:      u.Path,
+		region:      region,
+		endpoint:    strings.TrimRight(firstEnv("AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"), "/"),
+		credentials: awsCredentials,
+		client:      &http.Client{Timeout: 10 * time.Minute},
+		now:         time.Now,
+	}, nil
+}
+
+func awsCredentials(ctx context.Context) (s3Credentials, error) {
+	credentials := s3Credentials{
+		accessKeyId:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+
+	if credentials.accessKeyId != "" && credentials.secretAccessKey != "" {
+		return credentials, nil
+	}
+
+	output, err := exec.CommandContext(ctx, "aws", "configure", "export-credentials", "--format", "process").Output()
+	if err != nil {
+		return s3Credentials{}, fmt.Errorf("uploading to S3 requires the environment variables AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY or a configured AWS CLI: %w", err)
+	}
+
+	var exported awsCLICredentials
+	if err := json.Unmarshal(output, &exported); err != nil {
+		return s3Credentials{}, fmt.Errorf("cannot parse the credentials of the AWS CLI: %w", err)
+	}
+
+	if exported.AccessKeyId == "" || exported.SecretAccessKey == "" {
+		return s3Credentials{}, fmt.Errorf("the AWS CLI returned no credentials")
+	}
+
+	return s3Credentials{
+		accessKeyId:     exported.AccessKeyId,
+		secretAccessKey: exported.SecretAccessKey,
+		sessionToken:    exported.SessionToken,
+	}, nil
+}
+
+func (s *s3Store) Put(ctx context.Context, artifacts []*Artifact) ([]string, error) {
+	credentials, err := s.credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	locations := make([]string, 0, len(artifacts))
+
+	for _, artifact := range artifacts {
+		key := objectKey(s.prefix, artifact)
+
+		if err := s.putObject(ctx, key, artifact, credentials); err != nil {
+			return nil, err
+		}
+
+		locations = append(locations, fmt.Sprintf("s3://%s/%s", s.bucket, key))
+	}
+
+	return locations, nil
+}
+
+// objectURL uses virtual-hosted-style URLs for AWS and path-style URLs for custom endpoints
+func (s *s3Store) objectURL(key string) string {
+	if s.endpoint != "" {
+		return fmt.Sprintf("%s/%s/%s", s.endpoint, s.bucket, s3EscapePath(key))
+	}
+
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucket, s.region, s3EscapePath(key))
+}
+
+func (s *s3Store) putObject(ctx context.Context, key string, artifact *Artifact, credentials s3Credentials) error {
+	file, err := os.Open(artifact.Path)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), file)
+	if err != nil {
+		return err
+	}
+
+	checksum, err := hex.DecodeString(artifact.SHA256)
+	if err != nil {
+		return err
+	}
+
+	req.ContentLength = artifact.Size
+	req.Header.Set("Content-Type", artifact.ContentType)
+	req.Header.Set("X-Amz-Checksum-Sha256", base64.StdEncoding.EncodeToString(checksum))
+
+	for name, value := range checksumMetadata(artifact) {
+		req.Header.Set("X-Amz-Meta-"+name, value)
+	}
+
+	signS3Request(req, artifact.SHA256, credentials, s.region, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("upload %s to S3: %w", artifact.Name, err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+
+		return fmt.Errorf("upload %s to S3: %s: %s", artifact.Name, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+// signS3Request adds the AWS Signature Version 4 to the request, the payload hash is the hex encoded SHA-256 of the body
+func signS3Request(req *http.Request, payloadHash string, credentials s3Credentials, region string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	if credentials.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", credentials.sessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+
+		if name == "content-type" || name == "range" || strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		"/" + s3EscapePath(strings.TrimPrefix(req.URL.Path, "/")),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, region)
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hex.EncodeToString(requestHash[:])}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+credentials.secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", credentials.accessKeyId, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+
+	return h.Sum(nil)
+}
+
+// s3EscapePath encodes every segment of the key like S3 expects it in the canonical request,
+// all characters except the unreserved ones of RFC 3986 are percent-encoded
+func s3EscapePath(key string) string {
+	var escaped strings.Builder
+
+	for _, b := range []byte(key) {
+		if b == '/' || b == '-' || b == '_' || b == '.' || b == '~' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9') {
+			escaped.WriteByte(b)
+			continue
+		}
+
+		fmt.Fprintf(&escaped, "%%%02X", b)
+	}
+
+	return escaped.String()
+}
+
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
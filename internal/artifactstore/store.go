@@ -0,0 +1,129 @@
+package artifactstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Artifact is a local file which is stored together with its checksum
+type Artifact struct {
+	// Path of the local file
+	Path string
+	// Name of the file in the storage
+	Name        string
+	Version     string
+	ContentType string
+	Size        int64
+	// SHA256 is the hex encoded SHA-256 checksum of the file
+	SHA256 string
+}
+
+// Store pushes artifacts to a storage. All artifacts of one call belong to the same build.
+type Store interface {
+	// Put stores the artifacts and returns their locations
+	Put(ctx context.Context, artifacts []*Artifact) ([]string, error)
+}
+
+// NewArtifact reads the size and checksum of the file
+func NewArtifact(path, version string) (*Artifact, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	hash := sha256.New()
+
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return nil, fmt.Errorf("calculate checksum of %s: %w", path, err)
+	}
+
+	return &Artifact{
+		Path:        path,
+		Name:        filepath.Base(path),
+		Version:     version,
+		ContentType: contentTypeOf(path),
+		Size:        size,
+		SHA256:      hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+func contentTypeOf(path string) string {
+	switch filepath.Ext(path) {
+	case ".zip":
+		return "application/zip"
+	case ".json":
+		return "application/json"
+	}
+
+	return "application/octet-stream"
+}
+
+// IsRemote returns true when the output points to a remote storage instead of a local directory
+func IsRemote(output string) bool {
+	u, err := url.Parse(output)
+	if err != nil {
+		return false
+	}
+
+	return u.Scheme == "s3" || u.Scheme == "gs" || u.Scheme == "oci"
+}
+
+// New returns the store for the output URI. Supported are local directories (with or without file://),
+// s3://bucket/prefix, gs://bucket/prefix and oci://registry/repository[:tag].
+func New(output string) (Store, error) {
+	if !strings.Contains(output, "://") {
+		return newLocalStore(output), nil
+	}
+
+	u, err := url.Parse(output)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output %s: %w", output, err)
+	}
+
+	switch u.Scheme {
+	case "file":
+		return newLocalStore(u.Path), nil
+	case "s3":
+		return newS3Store(u)
+	case "gs":
+		return newGCSStore(u)
+	case "oci":
+		return newOCIStore(u)
+	}
+
+	return nil, fmt.Errorf("unsupported output scheme %s, supported are file://, s3://, gs:// and oci://", u.Scheme)
+}
+
+// objectKey joins the prefix of the output URI with the artifact name
+func objectKey(prefix string, artifact *Artifact) string {
+	prefix = strings.Trim(prefix, "/")
+
+	if prefix == "" {
+		return artifact.Name
+	}
+
+	return prefix + "/" + artifact.Name
+}
+
+// checksumMetadata is attached to every stored object
+func checksumMetadata(artifact *Artifact) map[string]string {
+	metadata := map[string]string{"sha256": artifact.SHA256}
+
+	if artifact.Version != "" {
+		metadata["version"] = artifact.Version
+	}
+
+	return metadata
+}
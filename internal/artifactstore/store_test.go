@@ -0,0 +1,71 @@
+package artifactstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestArtifact(t *testing.T, name, content string) *Artifact {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(file, []byte(content), os.ModePerm))
+
+	artifact, err := NewArtifact(file, "1.0.0")
+	require.NoError(t, err)
+
+	return artifact
+}
+
+func TestNewArtifact(t *testing.T) {
+	artifact := createTestArtifact(t, "FroshTools-1.0.0.zip", "content")
+
+	assert.Equal(t, "FroshTools-1.0.0.zip", artifact.Name)
+	assert.Equal(t, "1.0.0", artifact.Version)
+	assert.Equal(t, "application/zip", artifact.ContentType)
+	assert.Equal(t, int64(7), artifact.Size)
+	assert.Equal(t, "ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73", artifact.SHA256)
+}
+
+func TestNew(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	for output, expected := range map[string]any{
+		"dist":                            &localStore{},
+		"file:///tmp/dist":                &localStore{},
+		"s3://bucket/releases":            &s3Store{},
+		"gs://bucket/releases":            &gcsStore{},
+		"oci://ghcr.io/frosh/tools:1.0.0": &ociStore{},
+	} {
+		store, err := New(output)
+		require.NoError(t, err, output)
+		assert.IsType(t, expected, store, output)
+	}
+
+	_, err := New("ftp://example.com/dist")
+	assert.ErrorContains(t, err, "unsupported output scheme ftp")
+
+	assert.True(t, IsRemote("s3://bucket"))
+	assert.False(t, IsRemote("file:///tmp/dist"))
+	assert.False(t, IsRemote("dist"))
+}
+
+func TestLocalStore(t *testing.T) {
+	artifact := createTestArtifact(t, "FroshTools-1.0.0.zip", "content")
+	dir := filepath.Join(t.TempDir(), "dist")
+
+	locations, err := newLocalStore(dir).Put(t.Context(), []*Artifact{artifact})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{filepath.Join(dir, "FroshTools-1.0.0.zip")}, locations)
+	assert.FileExists(t, locations[0])
+
+	checksum, err := os.ReadFile(locations[0] + ".sha256")
+	require.NoError(t, err)
+	assert.Equal(t, artifact.SHA256+"  FroshTools-1.0.0.zip\n", string(checksum))
+}
@@ -0,0 +1,506 @@
+package assetcache
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shopware/shopware-cli/internal/system"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// Cache stores directories as tar.gz archives in a local directory and optionally on a remote HTTP cache.
+// The remote cache is read with GET and written with PUT on <url>/<key>.tar.gz, like the HTTP caches of Gradle or Turborepo.
+// The SHA256 of each archive is stored next to it as <key>.tar.gz.sha256, remote entries which do not match it are ignored.
+type Cache struct {
+	dir    string
+	remote string
+	token  string
+}
+
+func New(dir, remote, token string) *Cache {
+	return &Cache{dir: dir, remote: strings.TrimSuffix(remote, "/"), token: token}
+}
+
+// NewFromEnv uses SHOPWARE_CLI_ASSET_CACHE_DIR (defaults to the shopware-cli cache directory), SHOPWARE_CLI_ASSET_CACHE_URL
+// and SHOPWARE_CLI_ASSET_CACHE_TOKEN, which is sent as bearer token to the remote cache
+func NewFromEnv() *Cache {
+	dir := os.Getenv("SHOPWARE_CLI_ASSET_CACHE_DIR")
+	if dir == "" {
+		dir = filepath.Join(system.GetShopwareCliCacheDir(), "assets")
+	}
+
+	return New(dir, os.Getenv("SHOPWARE_CLI_ASSET_CACHE_URL"), os.Getenv("SHOPWARE_CLI_ASSET_CACHE_TOKEN"))
+}
+
+// WithDir returns a copy of the cache storing the local archives in another directory
+func (c *Cache) WithDir(dir string) *Cache {
+	return New(dir, c.remote, c.token)
+}
+
+// Restore extracts the entry into the target directory. The first return value is false when there is no entry.
+func (c *Cache) Restore(ctx context.Context, key, target string) (bool, error) {
+	file := c.file(key)
+
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		if !c.download(ctx, key) {
+			return false, nil
+		}
+	}
+
+	if err := extract(file, target); err != nil {
+		return false, fmt.Errorf("extract cache entry %s: %w", key, err)
+	}
+
+	return true, nil
+}
+
+// Save stores the given paths, relative to the source directory, under the key. Missing paths are skipped.
+func (c *Cache) Save(ctx context.Context, key, source string, paths []string) error {
+	if err := os.MkdirAll(c.dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(c.dir, key+"-*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	if err := archive(tmpFile, source, paths); err != nil {
+		_ = tmpFile.Close()
+		return fmt.Errorf("archive cache entry %s: %w", key, err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpFile.Name(), c.file(key)); err != nil {
+		return err
+	}
+
+	c.upload(ctx, key)
+
+	return nil
+}
+
+func (c *Cache) file(key string) string {
+	return filepath.Join(c.dir, key+".tar.gz")
+}
+
+// download fetches the entry from the remote cache, a failing remote cache or a corrupt entry only makes the entry missing
+func (c *Cache) download(ctx context.Context, key string) bool {
+	if c.remote == "" {
+		return false
+	}
+
+	var checksum strings.Builder
+
+	if !c.get(ctx, key+".tar.gz.sha256", &checksum) {
+		return false
+	}
+
+	if err := os.MkdirAll(c.dir, os.ModePerm); err != nil {
+		return false
+	}
+
+	tmpFile, err := os.CreateTemp(c.dir, key+"-*.tmp")
+	if err != nil {
+		return false
+	}
+
+	defer func() {
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	h := sha256.New()
+
+	if !c.get(ctx, key+".tar.gz", io.MultiWriter(tmpFile, h)) {
+		_ = tmpFile.Close()
+		return false
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return false
+	}
+
+	if hex.EncodeToString(h.Sum(nil)) != strings.TrimSpace(checksum.String()) {
+		logging.FromContext(ctx).Warnf("Ignoring remote asset cache entry %s, its checksum does not match", key)
+		return false
+	}
+
+	return os.Rename(tmpFile.Name(), c.file(key)) == nil
+}
+
+// get writes the remote file into w, it returns false when the file is missing or cannot be read
+func (c *Cache) get(ctx context.Context, name string, w io.Writer) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.remote+"/"+name, nil)
+	if err != nil {
+		return false
+	}
+
+	c.authorize(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		logging.FromContext(ctx).Warnf("Cannot read remote asset cache: %v", err)
+		return false
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode != http.StatusNotFound {
+			logging.FromContext(ctx).Warnf("Cannot read remote asset cache: %s", resp.Status)
+		}
+
+		return false
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		logging.FromContext(ctx).Warnf("Cannot read remote asset cache: %v", err)
+		return false
+	}
+
+	return true
+}
+
+// upload pushes the entry and its checksum to the remote cache, failures are only logged as the build itself succeeded
+func (c *Cache) upload(ctx context.Context, key string) {
+	if c.remote == "" {
+		return
+	}
+
+	file, err := os.Open(c.file(key))
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	h := sha256.New()
+
+	size, err := io.Copy(h, file)
+	if err != nil {
+		return
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return
+	}
+
+	// The checksum is written last, so readers never see an entry without its complete archive
+	if !c.put(ctx, key+".tar.gz", "application/gzip", file, size) {
+		return
+	}
+
+	checksum := hex.EncodeToString(h.Sum(nil))
+	c.put(ctx, key+".tar.gz.sha256", "text/plain", strings.NewReader(checksum), int64(len(checksum)))
+}
+
+func (c *Cache) put(ctx context.Context, name, contentType string, body io.Reader, size int64) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.remote+"/"+name, body)
+	if err != nil {
+		return false
+	}
+
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	c.authorize(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		logging.FromContext(ctx).Warnf("Cannot write remote asset cache: %v", err)
+		return false
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		logging.FromContext(ctx).Warnf("Cannot write remote asset cache: %s", resp.Status)
+		return false
+	}
+
+	return true
+}
+
+func (c *Cache) authorize(req *http.Request) {
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+}
+
+// HashFiles writes the content of all files below the root into the hash, directories with the given names are skipped
+func HashFiles(h hash.Hash, root string, excludeDirs ...string) error {
+	entries, err := system.Walk(root, system.WalkOptions{ExcludeDirs: excludeDirs})
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.Entry.IsDir() {
+			continue
+		}
+
+		if err := HashFile(h, filepath.Join(root, filepath.FromSlash(entry.Path)), entry.Path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// HashFile writes the name and content of the file into the hash, a missing file is hashed as missing
+func HashFile(h hash.Hash, file, name string) error {
+	content, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		_, _ = fmt.Fprintf(h, "%s\x00missing\n", name)
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(content)
+
+	_, _ = fmt.Fprintf(h, "%s\x00%s\n", name, hex.EncodeToString(sum[:]))
+
+	return nil
+}
+
+func archive(w io.Writer, source string, paths []string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	for _, p := range paths {
+		root := filepath.Join(source, p)
+
+		if _, err := os.Lstat(root); os.IsNotExist(err) {
+			continue
+		}
+
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			return addToArchive(tw, source, path, d)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gz.Close()
+}
+
+func addToArchive(tw *tar.Writer, source, path string, d fs.DirEntry) error {
+	info, err := d.Info()
+	if err != nil {
+		return err
+	}
+
+	var link string
+
+	// node_modules/.bin consists of symlinks, which have to stay symlinks
+	if info.Mode()&os.ModeSymlink != 0 {
+		if link, err = os.Readlink(path); err != nil {
+			return err
+		}
+	}
+
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+
+	rel, err := filepath.Rel(source, path)
+	if err != nil {
+		return err
+	}
+
+	header.Name = filepath.ToSlash(rel)
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	_, err = io.Copy(tw, file)
+
+	return err
+}
+
+func extract(file, target string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(target, os.ModePerm); err != nil {
+		return err
+	}
+
+	root, err := filepath.EvalSymlinks(target)
+	if err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(gz)
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(root, filepath.FromSlash(header.Name))
+
+		// The entry may come from a remote cache, so neither its name nor the symlinks created before may leave the target
+		resolved, err := resolveInside(root, path)
+		if err != nil {
+			return fmt.Errorf("invalid path in cache entry: %s", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(resolved, os.ModePerm); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			link := filepath.FromSlash(header.Linkname)
+
+			if filepath.IsAbs(link) || !isInside(root, filepath.Join(filepath.Dir(resolved), link)) {
+				return fmt.Errorf("invalid symlink in cache entry: %s -> %s", header.Name, header.Linkname)
+			}
+
+			if err := os.MkdirAll(filepath.Dir(resolved), os.ModePerm); err != nil {
+				return err
+			}
+
+			_ = os.Remove(resolved)
+
+			if err := os.Symlink(link, resolved); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			// A file replaces a symlink of an earlier entry instead of writing through it
+			if info, err := os.Lstat(resolved); err == nil && info.Mode()&os.ModeSymlink != 0 {
+				if err := os.Remove(resolved); err != nil {
+					return err
+				}
+			}
+
+			if err := extractFile(tr, resolved, header.FileInfo().Mode()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// resolveInside resolves the symlinks of the existing parent directories of the path and returns an error when the
+// resolved path is outside of root
+func resolveInside(root, path string) (string, error) {
+	if !isInside(root, path) {
+		return "", fmt.Errorf("%s is outside of %s", path, root)
+	}
+
+	existing := filepath.Dir(path)
+
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+
+		existing = filepath.Dir(existing)
+	}
+
+	resolvedDir, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", err
+	}
+
+	rest, err := filepath.Rel(existing, path)
+	if err != nil {
+		return "", err
+	}
+
+	resolved := filepath.Join(resolvedDir, rest)
+
+	if !isInside(root, resolved) {
+		return "", fmt.Errorf("%s is outside of %s", path, root)
+	}
+
+	return resolved, nil
+}
+
+func isInside(root, path string) bool {
+	return strings.HasPrefix(filepath.Clean(path), root+string(os.PathSeparator))
+}
+
+func extractFile(r io.Reader, path string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil { //nolint:gosec
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
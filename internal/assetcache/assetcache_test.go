@@ -0,0 +1,212 @@
+package assetcache
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFixture(t *testing.T, root string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "node_modules", "vite", "bin"), os.ModePerm))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "node_modules", ".bin"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "node_modules", "vite", "bin", "vite.js"), []byte("#!/usr/bin/env node"), 0o755))
+	require.NoError(t, os.Symlink("../vite/bin/vite.js", filepath.Join(root, "node_modules", ".bin", "vite")))
+}
+
+func TestSaveAndRestore(t *testing.T) {
+	source := t.TempDir()
+	writeFixture(t, source)
+
+	cache := New(t.TempDir(), "", "")
+
+	restored, err := cache.Restore(t.Context(), "key", t.TempDir())
+	require.NoError(t, err)
+	assert.False(t, restored)
+
+	require.NoError(t, cache.Save(t.Context(), "key", source, []string{"node_modules", "missing"}))
+
+	target := t.TempDir()
+
+	restored, err = cache.Restore(t.Context(), "key", target)
+	require.NoError(t, err)
+	assert.True(t, restored)
+
+	content, err := os.ReadFile(filepath.Join(target, "node_modules", ".bin", "vite"))
+	require.NoError(t, err)
+	assert.Equal(t, "#!/usr/bin/env node", string(content))
+
+	link, err := os.Readlink(filepath.Join(target, "node_modules", ".bin", "vite"))
+	require.NoError(t, err)
+	assert.Equal(t, "../vite/bin/vite.js", link)
+
+	info, err := os.Stat(filepath.Join(target, "node_modules", "vite", "bin", "vite.js"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+}
+
+func TestRemoteCache(t *testing.T) {
+	var mu sync.Mutex
+
+	entries := make(map[string][]byte)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodPut:
+			content, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+
+			entries[r.URL.Path] = content
+		case http.MethodGet:
+			content, ok := entries[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			_, _ = w.Write(content)
+		}
+	}))
+	defer server.Close()
+
+	source := t.TempDir()
+	writeFixture(t, source)
+
+	require.NoError(t, New(t.TempDir(), server.URL+"/cache/", "secret").Save(t.Context(), "key", source, []string{"node_modules"}))
+	assert.Contains(t, entries, "/cache/key.tar.gz")
+	assert.Contains(t, entries, "/cache/key.tar.gz.sha256")
+
+	// A second machine with an empty local cache reads the entry from the remote cache
+	target := t.TempDir()
+
+	restored, err := New(t.TempDir(), server.URL+"/cache", "secret").Restore(t.Context(), "key", target)
+	require.NoError(t, err)
+	assert.True(t, restored)
+	assert.FileExists(t, filepath.Join(target, "node_modules", "vite", "bin", "vite.js"))
+
+	restored, err = New(t.TempDir(), server.URL+"/cache", "secret").Restore(t.Context(), "other", target)
+	require.NoError(t, err)
+	assert.False(t, restored)
+
+	// A modified entry does not match its checksum
+	mu.Lock()
+	entries["/cache/key.tar.gz"] = archiveOf(t, tarEntry{name: "node_modules/evil.js", content: "evil"})
+	mu.Unlock()
+
+	restored, err = New(t.TempDir(), server.URL+"/cache", "secret").Restore(t.Context(), "key", t.TempDir())
+	require.NoError(t, err)
+	assert.False(t, restored)
+}
+
+type tarEntry struct {
+	name    string
+	link    string
+	content string
+}
+
+// archiveOf creates a cache entry with the given entries, like a manipulated remote cache could return it
+func archiveOf(t *testing.T, entries ...tarEntry) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Mode: 0o644, Typeflag: tar.TypeReg, Size: int64(len(entry.content))}
+
+		if entry.link != "" {
+			header = &tar.Header{Name: entry.name, Mode: 0o777, Typeflag: tar.TypeSymlink, Linkname: entry.link}
+		}
+
+		require.NoError(t, tw.WriteHeader(header))
+
+		_, err := tw.Write([]byte(entry.content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	return buf.Bytes()
+}
+
+func TestExtractRejectsEntriesOutsideOfTarget(t *testing.T) {
+	cases := map[string][]tarEntry{
+		"parent path":      {{name: "../evil.js", content: "evil"}},
+		"absolute symlink": {{name: "node_modules/evil", link: "/tmp"}},
+		"escaping symlink": {{name: "node_modules/evil", link: "../../outside"}},
+		"symlink chain": {
+			{name: "node_modules/up", link: ".."},
+			{name: "node_modules/up/evil", link: "../outside"},
+		},
+	}
+
+	for name, entries := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			target := filepath.Join(dir, "target")
+			file := filepath.Join(dir, "entry.tar.gz")
+
+			require.NoError(t, os.WriteFile(file, archiveOf(t, entries...), 0o644))
+
+			assert.Error(t, extract(file, target))
+			assert.NoFileExists(t, filepath.Join(dir, "evil.js"))
+		})
+	}
+}
+
+func TestExtractWritesThroughSymlinksInsideOfTarget(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	file := filepath.Join(dir, "entry.tar.gz")
+
+	require.NoError(t, os.WriteFile(file, archiveOf(t,
+		tarEntry{name: "node_modules/vite/bin/vite.js", content: "vite"},
+		tarEntry{name: "node_modules/linked", link: "vite"},
+		tarEntry{name: "node_modules/linked/package.json", content: "{}"},
+	), 0o644))
+
+	require.NoError(t, extract(file, target))
+	assert.FileExists(t, filepath.Join(target, "node_modules", "vite", "package.json"))
+}
+
+func TestHashFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.js"), []byte("a"), os.ModePerm))
+
+	hashOf := func() string {
+		h := sha256.New()
+		require.NoError(t, HashFiles(h, root, "node_modules"))
+
+		return string(h.Sum(nil))
+	}
+
+	before := hashOf()
+
+	// node_modules is excluded
+	require.NoError(t, os.WriteFile(filepath.Join(root, "node_modules", "vite", "bin", "vite.js"), []byte("changed"), os.ModePerm))
+	assert.Equal(t, before, hashOf())
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.js"), []byte("b"), os.ModePerm))
+	assert.NotEqual(t, before, hashOf())
+}
@@ -0,0 +1,120 @@
+// Package buildinfo describes the running binary and checks for newer releases
+package buildinfo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/shyim/go-version"
+)
+
+// LatestReleaseURL is the GitHub API endpoint of the newest release
+const LatestReleaseURL = "https://api.github.com/repos/shopware/shopware-cli/releases/latest"
+
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	GoVersion string `json:"goVersion"`
+	Platform  string `json:"platform"`
+	// Latest is only set when the online check ran
+	Latest          string `json:"latest,omitempty"`
+	UpdateAvailable bool   `json:"updateAvailable"`
+}
+
+// Read returns the information passed with -ldflags, missing values are taken from the build information Go embeds
+// into binaries installed with go install or built inside a git checkout
+func Read(buildVersion, commit, date string) Info {
+	info := Info{
+		Version:   buildVersion,
+		Commit:    commit,
+		Date:      date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	if (info.Version == "" || info.Version == "dev") && build.Main.Version != "" && build.Main.Version != "(devel)" {
+		info.Version = strings.TrimPrefix(build.Main.Version, "v")
+	}
+
+	for _, setting := range build.Settings {
+		switch {
+		case setting.Key == "vcs.revision" && info.Commit == "":
+			info.Commit = setting.Value
+		case setting.Key == "vcs.time" && info.Date == "":
+			info.Date = setting.Value
+		}
+	}
+
+	return info
+}
+
+// CheckLatest sets the newest release and whether it is newer than the running version
+func (i *Info) CheckLatest(ctx context.Context, client *http.Client, url string) error {
+	latest, err := fetchLatestRelease(ctx, client, url)
+	if err != nil {
+		return err
+	}
+
+	i.Latest = latest
+	i.UpdateAvailable = IsNewer(i.Version, latest)
+
+	return nil
+}
+
+func fetchLatestRelease(ctx context.Context, client *http.Client, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return "", fmt.Errorf("create latest release request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetch latest release: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("fetch latest release: %s %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", fmt.Errorf("decode latest release: %w", err)
+	}
+
+	return strings.TrimPrefix(release.TagName, "v"), nil
+}
+
+// IsNewer reports whether latest is a newer version than current, development builds are never outdated
+func IsNewer(current, latest string) bool {
+	currentVersion, err := version.NewVersion(current)
+	if err != nil {
+		return false
+	}
+
+	latestVersion, err := version.NewVersion(latest)
+	if err != nil {
+		return false
+	}
+
+	return latestVersion.GreaterThan(currentVersion)
+}
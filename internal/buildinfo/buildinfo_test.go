@@ -0,0 +1,53 @@
+package buildinfo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead(t *testing.T) {
+	info := Read("0.6.10", "abc123", "2025-01-02T03:04:05Z")
+
+	assert.Equal(t, "0.6.10", info.Version)
+	assert.Equal(t, "abc123", info.Commit)
+	assert.Equal(t, "2025-01-02T03:04:05Z", info.Date)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+	assert.Equal(t, runtime.GOOS+"/"+runtime.GOARCH, info.Platform)
+}
+
+func TestIsNewer(t *testing.T) {
+	assert.True(t, IsNewer("0.6.9", "0.6.10"))
+	assert.False(t, IsNewer("0.6.10", "0.6.10"))
+	assert.False(t, IsNewer("0.7.0", "0.6.10"))
+	assert.False(t, IsNewer("dev", "0.6.10"))
+}
+
+func TestCheckLatest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/vnd.github+json", r.Header.Get("Accept"))
+		_, _ = w.Write([]byte(`{"tag_name": "v0.7.0", "name": "0.7.0"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	info := Info{Version: "0.6.10"}
+	require.NoError(t, info.CheckLatest(t.Context(), server.Client(), server.URL))
+
+	assert.Equal(t, "0.7.0", info.Latest)
+	assert.True(t, info.UpdateAvailable)
+}
+
+func TestCheckLatestFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "rate limit exceeded", http.StatusForbidden)
+	}))
+	t.Cleanup(server.Close)
+
+	info := Info{Version: "0.6.10"}
+	assert.ErrorContains(t, info.CheckLatest(t.Context(), server.Client(), server.URL), "403 Forbidden rate limit exceeded")
+	assert.Empty(t, info.Latest)
+}
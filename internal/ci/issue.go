@@ -0,0 +1,286 @@
+package ci
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Issue is a ticket opened in the tracker of the team
+type Issue struct {
+	Title string
+	// Body is Markdown, Jira shows it as plain text
+	Body      string
+	Labels    []string
+	Assignees []string
+}
+
+// IssueTracker opens issues in GitHub, GitLab or Jira
+type IssueTracker interface {
+	// CreateIssue opens the issue and returns its URL. When an open issue with the same title exists, its URL is returned instead.
+	CreateIssue(ctx context.Context, issue Issue) (string, error)
+}
+
+// NewIssueTracker returns the tracker of the provider. project is the GitHub repository like owner/name, the GitLab
+// project path or ID or the Jira project key. GitHub requires GITHUB_TOKEN, GitLab GITLAB_TOKEN and Jira JIRA_USER and JIRA_API_TOKEN.
+func NewIssueTracker(provider, project, apiURL string) (IssueTracker, error) {
+	switch provider {
+	case "github":
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return nil, fmt.Errorf("GITHUB_TOKEN is required to create GitHub issues")
+		}
+
+		if apiURL == "" {
+			apiURL = os.Getenv("GITHUB_API_URL")
+		}
+
+		if apiURL == "" {
+			apiURL = "https://api.github.com"
+		}
+
+		return &GithubIssues{apiURL: strings.TrimSuffix(apiURL, "/"), repository: project, token: token}, nil
+	case "gitlab":
+		token := os.Getenv("GITLAB_TOKEN")
+		if token == "" {
+			return nil, fmt.Errorf("GITLAB_TOKEN is required to create GitLab issues")
+		}
+
+		if apiURL == "" {
+			apiURL = os.Getenv("CI_API_V4_URL")
+		}
+
+		if apiURL == "" {
+			apiURL = "https://gitlab.com/api/v4"
+		}
+
+		return &GitlabIssues{apiURL: strings.TrimSuffix(apiURL, "/"), projectID: project, token: token}, nil
+	case "jira":
+		user, token := os.Getenv("JIRA_USER"), os.Getenv("JIRA_API_TOKEN")
+		if user == "" || token == "" {
+			return nil, fmt.Errorf("JIRA_USER and JIRA_API_TOKEN are required to create Jira issues")
+		}
+
+		if apiURL == "" {
+			return nil, fmt.Errorf("the URL of the Jira site is required")
+		}
+
+		return &JiraIssues{siteURL: strings.TrimSuffix(apiURL, "/"), projectKey: project, user: user, token: token}, nil
+	}
+
+	return nil, fmt.Errorf("unknown issue tracker %q, must be github, gitlab or jira", provider)
+}
+
+// JobURL returns the URL of the current CI job, empty outside of GitHub Actions and GitLab CI
+func JobURL() string {
+	if os.Getenv("GITHUB_ACTIONS") == "true" && os.Getenv("GITHUB_RUN_ID") != "" {
+		return fmt.Sprintf("%s/%s/actions/runs/%s", os.Getenv("GITHUB_SERVER_URL"), os.Getenv("GITHUB_REPOSITORY"), os.Getenv("GITHUB_RUN_ID"))
+	}
+
+	return os.Getenv("CI_JOB_URL")
+}
+
+// GithubIssues creates issues with the issues API
+type GithubIssues struct {
+	apiURL     string
+	repository string
+	token      string
+}
+
+func (g *GithubIssues) CreateIssue(ctx context.Context, issue Issue) (string, error) {
+	issuesURL := fmt.Sprintf("%s/repos/%s/issues", g.apiURL, g.repository)
+
+	var existing []struct {
+		Title       string `json:"title"`
+		HTMLURL     string `json:"html_url"`
+		PullRequest any    `json:"pull_request"`
+	}
+
+	if err := g.request(ctx, http.MethodGet, issuesURL+"?state=open&per_page=100", nil, &existing); err != nil {
+		return "", err
+	}
+
+	for _, open := range existing {
+		if open.PullRequest == nil && open.Title == issue.Title {
+			return open.HTMLURL, nil
+		}
+	}
+
+	payload := map[string]any{"title": issue.Title, "body": issue.Body}
+
+	if len(issue.Labels) > 0 {
+		payload["labels"] = issue.Labels
+	}
+
+	if len(issue.Assignees) > 0 {
+		payload["assignees"] = issue.Assignees
+	}
+
+	var created struct {
+		HTMLURL string `json:"html_url"`
+	}
+
+	if err := g.request(ctx, http.MethodPost, issuesURL, payload, &created); err != nil {
+		return "", err
+	}
+
+	return created.HTMLURL, nil
+}
+
+func (g *GithubIssues) request(ctx context.Context, method, requestURL string, payload, response any) error {
+	headers := map[string]string{
+		"Authorization":        "Bearer " + g.token,
+		"Accept":               "application/vnd.github+json",
+		"X-GitHub-Api-Version": "2022-11-28",
+	}
+
+	return doPullRequestAPIRequest(ctx, method, requestURL, headers, payload, response)
+}
+
+// GitlabIssues creates issues with the issues API, assignees are usernames which are resolved to user IDs
+type GitlabIssues struct {
+	apiURL    string
+	projectID string
+	token     string
+}
+
+func (g *GitlabIssues) CreateIssue(ctx context.Context, issue Issue) (string, error) {
+	issuesURL := fmt.Sprintf("%s/projects/%s/issues", g.apiURL, url.PathEscape(g.projectID))
+
+	var existing []struct {
+		Title  string `json:"title"`
+		WebURL string `json:"web_url"`
+	}
+
+	if err := g.request(ctx, http.MethodGet, fmt.Sprintf("%s?state=opened&in=title&search=%s", issuesURL, url.QueryEscape(issue.Title)), nil, &existing); err != nil {
+		return "", err
+	}
+
+	for _, open := range existing {
+		if open.Title == issue.Title {
+			return open.WebURL, nil
+		}
+	}
+
+	payload := map[string]any{"title": issue.Title, "description": issue.Body}
+
+	if len(issue.Labels) > 0 {
+		payload["labels"] = strings.Join(issue.Labels, ",")
+	}
+
+	if len(issue.Assignees) > 0 {
+		ids := make([]int, 0, len(issue.Assignees))
+
+		for _, username := range issue.Assignees {
+			var users []struct {
+				ID int `json:"id"`
+			}
+
+			if err := g.request(ctx, http.MethodGet, fmt.Sprintf("%s/users?username=%s", g.apiURL, url.QueryEscape(username)), nil, &users); err != nil {
+				return "", err
+			}
+
+			if len(users) == 0 {
+				return "", fmt.Errorf("cannot find the GitLab user %s", username)
+			}
+
+			ids = append(ids, users[0].ID)
+		}
+
+		payload["assignee_ids"] = ids
+	}
+
+	var created struct {
+		WebURL string `json:"web_url"`
+	}
+
+	if err := g.request(ctx, http.MethodPost, issuesURL, payload, &created); err != nil {
+		return "", err
+	}
+
+	return created.WebURL, nil
+}
+
+func (g *GitlabIssues) request(ctx context.Context, method, requestURL string, payload, response any) error {
+	return doPullRequestAPIRequest(ctx, method, requestURL, map[string]string{"PRIVATE-TOKEN": g.token}, payload, response)
+}
+
+// JiraIssues creates bugs with the REST API of Jira Cloud, the assignee is the account ID of the first assignee
+type JiraIssues struct {
+	siteURL    string
+	projectKey string
+	user       string
+	token      string
+}
+
+func (j *JiraIssues) CreateIssue(ctx context.Context, issue Issue) (string, error) {
+	// The summary is matched as phrase, its quotes are dropped as they cannot be escaped inside the phrase
+	phrase := `"` + strings.ReplaceAll(issue.Title, `"`, "") + `"`
+	jql := fmt.Sprintf(`project = %s AND statusCategory != Done AND summary ~ %s`, jqlString(j.projectKey), jqlString(phrase))
+
+	var existing struct {
+		Issues []struct {
+			Key    string `json:"key"`
+			Fields struct {
+				Summary string `json:"summary"`
+			} `json:"fields"`
+		} `json:"issues"`
+	}
+
+	if err := j.request(ctx, http.MethodPost, j.siteURL+"/rest/api/3/search/jql", map[string]any{"jql": jql, "fields": []string{"summary"}, "maxResults": 50}, &existing); err != nil {
+		return "", err
+	}
+
+	for _, open := range existing.Issues {
+		if open.Fields.Summary == issue.Title {
+			return j.browseURL(open.Key), nil
+		}
+	}
+
+	fields := map[string]any{
+		"project":     map[string]string{"key": j.projectKey},
+		"issuetype":   map[string]string{"name": "Bug"},
+		"summary":     issue.Title,
+		"description": issue.Body,
+	}
+
+	if len(issue.Labels) > 0 {
+		fields["labels"] = issue.Labels
+	}
+
+	if len(issue.Assignees) > 0 {
+		fields["assignee"] = map[string]string{"accountId": issue.Assignees[0]}
+	}
+
+	var created struct {
+		Key string `json:"key"`
+	}
+
+	if err := j.request(ctx, http.MethodPost, j.siteURL+"/rest/api/2/issue", map[string]any{"fields": fields}, &created); err != nil {
+		return "", err
+	}
+
+	return j.browseURL(created.Key), nil
+}
+
+// jqlString quotes the value as JQL string literal
+func jqlString(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+func (j *JiraIssues) browseURL(key string) string {
+	return fmt.Sprintf("%s/browse/%s", j.siteURL, key)
+}
+
+func (j *JiraIssues) request(ctx context.Context, method, requestURL string, payload, response any) error {
+	headers := map[string]string{
+		"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(j.user+":"+j.token)),
+		"Accept":        "application/json",
+	}
+
+	return doPullRequestAPIRequest(ctx, method, requestURL, headers, payload, response)
+}
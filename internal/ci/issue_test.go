@@ -0,0 +1,147 @@
+package ci
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIssueTrackerRequiresToken(t *testing.T) {
+	clearCIEnv(t)
+	t.Setenv("JIRA_USER", "")
+
+	_, err := NewIssueTracker("github", "acme/plugin", "")
+	assert.ErrorContains(t, err, "GITHUB_TOKEN is required")
+
+	_, err = NewIssueTracker("jira", "PLUG", "https://acme.atlassian.net")
+	assert.ErrorContains(t, err, "JIRA_USER and JIRA_API_TOKEN are required")
+
+	_, err = NewIssueTracker("trello", "board", "")
+	assert.ErrorContains(t, err, "unknown issue tracker")
+}
+
+func TestGithubIssuesCreatesIssue(t *testing.T) {
+	clearCIEnv(t)
+
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`[{"title": "Code review of FroshTools 1.0.0 failed", "html_url": "https://github.com/acme/plugin/pull/1", "pull_request": {}}]`))
+		case http.MethodPost:
+			var payload map[string]any
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			assert.Equal(t, "Code review of FroshTools 1.0.0 failed", payload["title"])
+			assert.Equal(t, []any{"alice"}, payload["assignees"])
+			assert.Equal(t, []any{"store-review"}, payload["labels"])
+
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"html_url": "https://github.com/acme/plugin/issues/2"}`))
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_TOKEN", "secret")
+
+	tracker, err := NewIssueTracker("github", "acme/plugin", server.URL)
+	require.NoError(t, err)
+
+	issueURL, err := tracker.CreateIssue(t.Context(), Issue{Title: "Code review of FroshTools 1.0.0 failed", Body: "body", Labels: []string{"store-review"}, Assignees: []string{"alice"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://github.com/acme/plugin/issues/2", issueURL)
+	assert.Equal(t, []string{"GET /repos/acme/plugin/issues", "POST /repos/acme/plugin/issues"}, requests)
+}
+
+func TestGitlabIssuesReusesOpenIssue(t *testing.T) {
+	clearCIEnv(t)
+
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath())
+		assert.Equal(t, "secret", r.Header.Get("PRIVATE-TOKEN"))
+
+		_, _ = w.Write([]byte(`[{"title": "Code review of FroshTools 1.0.0 failed", "web_url": "https://gitlab.com/acme/plugin/-/issues/3"}]`))
+	}))
+	defer server.Close()
+
+	t.Setenv("GITLAB_TOKEN", "secret")
+
+	tracker, err := NewIssueTracker("gitlab", "acme/plugin", server.URL+"/api/v4")
+	require.NoError(t, err)
+
+	issueURL, err := tracker.CreateIssue(t.Context(), Issue{Title: "Code review of FroshTools 1.0.0 failed"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://gitlab.com/acme/plugin/-/issues/3", issueURL)
+	assert.Equal(t, []string{"GET /api/v4/projects/acme%2Fplugin/issues"}, requests)
+}
+
+func TestJiraIssuesCreatesBug(t *testing.T) {
+	clearCIEnv(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "bot@acme.com", user)
+		assert.Equal(t, "secret", password)
+
+		switch r.URL.Path {
+		case "/rest/api/3/search/jql":
+			var payload struct {
+				JQL string `json:"jql"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			assert.Equal(t, `project = "PLUG" AND statusCategory != Done AND summary ~ "\"Code review of FroshTools 1.0.0 failed\""`, payload.JQL)
+
+			_, _ = w.Write([]byte(`{"issues": []}`))
+		case "/rest/api/2/issue":
+			var payload struct {
+				Fields map[string]any `json:"fields"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			assert.Equal(t, map[string]any{"key": "PLUG"}, payload.Fields["project"])
+			assert.Equal(t, map[string]any{"accountId": "5b10a2844c20165700ede21g"}, payload.Fields["assignee"])
+
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"key": "PLUG-12"}`))
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("JIRA_USER", "bot@acme.com")
+	t.Setenv("JIRA_API_TOKEN", "secret")
+
+	tracker, err := NewIssueTracker("jira", "PLUG", server.URL)
+	require.NoError(t, err)
+
+	issueURL, err := tracker.CreateIssue(t.Context(), Issue{Title: "Code review of FroshTools 1.0.0 failed", Assignees: []string{"5b10a2844c20165700ede21g"}})
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/browse/PLUG-12", issueURL)
+}
+
+func TestJQLStringEscapesQuotes(t *testing.T) {
+	assert.Equal(t, `"PLUG\" OR project = \"OTHER"`, jqlString(`PLUG" OR project = "OTHER`))
+	assert.Equal(t, `"C:\\temp"`, jqlString(`C:\temp`))
+}
+
+func TestJobURL(t *testing.T) {
+	clearCIEnv(t)
+	t.Setenv("CI_JOB_URL", "")
+
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_SERVER_URL", "https://github.com")
+	t.Setenv("GITHUB_REPOSITORY", "acme/plugin")
+	t.Setenv("GITHUB_RUN_ID", "42")
+
+	assert.Equal(t, "https://github.com/acme/plugin/actions/runs/42", JobURL())
+}
@@ -0,0 +1,243 @@
+package ci
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// ErrNoPullRequest is returned when the CI job does not run for a pull request or merge request
+var ErrNoPullRequest = errors.New("the CI job does not run for a pull request or merge request")
+
+var githubPullRequestRefRegExp = regexp.MustCompile(`^refs/pull/(\d+)/`)
+
+// PullRequestCommenter posts comments on the pull request or merge request of the current CI job
+type PullRequestCommenter interface {
+	// UpsertComment updates the comment containing the marker or creates a new one, so a job posts only a single comment
+	UpsertComment(ctx context.Context, marker, body string) error
+}
+
+// NewPullRequestCommenter returns the commenter of the current CI environment. GitHub requires GITHUB_TOKEN and GitLab
+// GITLAB_TOKEN, as the job token of GitLab cannot write notes.
+func NewPullRequestCommenter() (PullRequestCommenter, error) {
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		return newGithubPullRequest()
+	}
+
+	if os.Getenv("GITLAB_CI") == "true" {
+		return newGitlabMergeRequest()
+	}
+
+	return nil, ErrNoPullRequest
+}
+
+// GithubPullRequest comments on pull requests with the issue comments API
+type GithubPullRequest struct {
+	apiURL     string
+	repository string
+	number     int
+	token      string
+}
+
+func newGithubPullRequest() (*GithubPullRequest, error) {
+	number := githubPullRequestNumber()
+	if number == 0 {
+		return nil, ErrNoPullRequest
+	}
+
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("GITHUB_TOKEN is required to comment on the pull request")
+	}
+
+	apiURL := os.Getenv("GITHUB_API_URL")
+	if apiURL == "" {
+		apiURL = "https://api.github.com"
+	}
+
+	return &GithubPullRequest{
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		repository: os.Getenv("GITHUB_REPOSITORY"),
+		number:     number,
+		token:      token,
+	}, nil
+}
+
+// githubPullRequestNumber reads the number from the event payload and falls back to the ref like refs/pull/42/merge
+func githubPullRequestNumber() int {
+	if eventPath := os.Getenv("GITHUB_EVENT_PATH"); eventPath != "" {
+		if content, err := os.ReadFile(eventPath); err == nil {
+			var event struct {
+				PullRequest struct {
+					Number int `json:"number"`
+				} `json:"pull_request"`
+			}
+
+			if err := json.Unmarshal(content, &event); err == nil && event.PullRequest.Number > 0 {
+				return event.PullRequest.Number
+			}
+		}
+	}
+
+	if match := githubPullRequestRefRegExp.FindStringSubmatch(os.Getenv("GITHUB_REF")); match != nil {
+		number, _ := strconv.Atoi(match[1])
+
+		return number
+	}
+
+	return 0
+}
+
+func (g *GithubPullRequest) UpsertComment(ctx context.Context, marker, body string) error {
+	commentsURL := fmt.Sprintf("%s/repos/%s/issues/%d/comments", g.apiURL, g.repository, g.number)
+
+	for page := 1; ; page++ {
+		var comments []struct {
+			ID   int    `json:"id"`
+			Body string `json:"body"`
+		}
+
+		if err := g.request(ctx, http.MethodGet, fmt.Sprintf("%s?per_page=100&page=%d", commentsURL, page), nil, &comments); err != nil {
+			return err
+		}
+
+		for _, comment := range comments {
+			if strings.Contains(comment.Body, marker) {
+				return g.request(ctx, http.MethodPatch, fmt.Sprintf("%s/repos/%s/issues/comments/%d", g.apiURL, g.repository, comment.ID), map[string]string{"body": body}, nil)
+			}
+		}
+
+		if len(comments) < 100 {
+			break
+		}
+	}
+
+	return g.request(ctx, http.MethodPost, commentsURL, map[string]string{"body": body}, nil)
+}
+
+func (g *GithubPullRequest) request(ctx context.Context, method, requestURL string, payload, response any) error {
+	headers := map[string]string{
+		"Authorization":        "Bearer " + g.token,
+		"Accept":               "application/vnd.github+json",
+		"X-GitHub-Api-Version": "2022-11-28",
+	}
+
+	return doPullRequestAPIRequest(ctx, method, requestURL, headers, payload, response)
+}
+
+// GitlabMergeRequest comments on merge requests with the notes API
+type GitlabMergeRequest struct {
+	apiURL    string
+	projectID string
+	iid       string
+	token     string
+}
+
+func newGitlabMergeRequest() (*GitlabMergeRequest, error) {
+	iid := os.Getenv("CI_MERGE_REQUEST_IID")
+	if iid == "" {
+		return nil, ErrNoPullRequest
+	}
+
+	token := os.Getenv("GITLAB_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("GITLAB_TOKEN is required to comment on the merge request")
+	}
+
+	return &GitlabMergeRequest{
+		apiURL:    strings.TrimSuffix(os.Getenv("CI_API_V4_URL"), "/"),
+		projectID: os.Getenv("CI_PROJECT_ID"),
+		iid:       iid,
+		token:     token,
+	}, nil
+}
+
+func (g *GitlabMergeRequest) UpsertComment(ctx context.Context, marker, body string) error {
+	notesURL := fmt.Sprintf("%s/projects/%s/merge_requests/%s/notes", g.apiURL, url.PathEscape(g.projectID), g.iid)
+
+	for page := 1; ; page++ {
+		var notes []struct {
+			ID   int    `json:"id"`
+			Body string `json:"body"`
+		}
+
+		if err := g.request(ctx, http.MethodGet, fmt.Sprintf("%s?per_page=100&page=%d", notesURL, page), nil, &notes); err != nil {
+			return err
+		}
+
+		for _, note := range notes {
+			if strings.Contains(note.Body, marker) {
+				return g.request(ctx, http.MethodPut, fmt.Sprintf("%s/%d", notesURL, note.ID), map[string]string{"body": body}, nil)
+			}
+		}
+
+		if len(notes) < 100 {
+			break
+		}
+	}
+
+	return g.request(ctx, http.MethodPost, notesURL, map[string]string{"body": body}, nil)
+}
+
+func (g *GitlabMergeRequest) request(ctx context.Context, method, requestURL string, payload, response any) error {
+	return doPullRequestAPIRequest(ctx, method, requestURL, map[string]string{"PRIVATE-TOKEN": g.token}, payload, response)
+}
+
+func doPullRequestAPIRequest(ctx context.Context, method, requestURL string, headers map[string]string, payload, response any) error {
+	var body io.Reader
+
+	if payload != nil {
+		content, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+
+		body = bytes.NewReader(content)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("User-Agent", "Shopware CLI")
+	req.Header.Set("Content-Type", "application/json")
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logging.FromContext(ctx).Errorf("Cannot close response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		content, _ := io.ReadAll(resp.Body)
+
+		return fmt.Errorf("%s %s failed with %s: %s", method, req.URL.Path, resp.Status, strings.TrimSpace(string(content)))
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(response)
+}
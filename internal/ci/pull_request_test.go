@@ -0,0 +1,147 @@
+package ci
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clearCIEnv(t *testing.T) {
+	t.Helper()
+
+	for _, key := range []string{"GITHUB_ACTIONS", "GITHUB_EVENT_PATH", "GITHUB_REF", "GITHUB_TOKEN", "GITLAB_CI", "GITLAB_TOKEN", "CI_MERGE_REQUEST_IID", "GITHUB_REPOSITORY", "GITHUB_SHA", "CI_PROJECT_ID", "CI_COMMIT_SHA"} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestNewPullRequestCommenterOutsideOfPullRequests(t *testing.T) {
+	clearCIEnv(t)
+
+	_, err := NewPullRequestCommenter()
+	assert.ErrorIs(t, err, ErrNoPullRequest)
+
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_REF", "refs/heads/main")
+
+	_, err = NewPullRequestCommenter()
+	assert.ErrorIs(t, err, ErrNoPullRequest)
+}
+
+func TestNewPullRequestCommenterRequiresToken(t *testing.T) {
+	clearCIEnv(t)
+	t.Setenv("GITLAB_CI", "true")
+	t.Setenv("CI_MERGE_REQUEST_IID", "3")
+
+	_, err := NewPullRequestCommenter()
+	assert.ErrorContains(t, err, "GITLAB_TOKEN is required")
+}
+
+func TestGithubPullRequestNumberFromEvent(t *testing.T) {
+	clearCIEnv(t)
+
+	eventPath := filepath.Join(t.TempDir(), "event.json")
+	require.NoError(t, os.WriteFile(eventPath, []byte(`{"pull_request": {"number": 42}}`), os.ModePerm))
+
+	t.Setenv("GITHUB_EVENT_PATH", eventPath)
+	assert.Equal(t, 42, githubPullRequestNumber())
+
+	t.Setenv("GITHUB_EVENT_PATH", "")
+	t.Setenv("GITHUB_REF", "refs/pull/7/merge")
+	assert.Equal(t, 7, githubPullRequestNumber())
+}
+
+func TestGithubPullRequestUpdatesExistingComment(t *testing.T) {
+	clearCIEnv(t)
+
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`[{"id": 1, "body": "Looks good"}, {"id": 5, "body": "<!-- marker -->\nold"}]`))
+		case http.MethodPatch:
+			var payload map[string]string
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			assert.Equal(t, "<!-- marker -->\nnew", payload["body"])
+
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_REF", "refs/pull/42/merge")
+	t.Setenv("GITHUB_TOKEN", "secret")
+	t.Setenv("GITHUB_API_URL", server.URL)
+	t.Setenv("GITHUB_REPOSITORY", "acme/plugin")
+
+	commenter, err := NewPullRequestCommenter()
+	require.NoError(t, err)
+	require.NoError(t, commenter.UpsertComment(t.Context(), "<!-- marker -->", "<!-- marker -->\nnew"))
+
+	assert.Equal(t, []string{"GET /repos/acme/plugin/issues/42/comments", "PATCH /repos/acme/plugin/issues/comments/5"}, requests)
+}
+
+func TestGitlabMergeRequestCreatesComment(t *testing.T) {
+	clearCIEnv(t)
+
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath())
+		assert.Equal(t, "secret", r.Header.Get("PRIVATE-TOKEN"))
+
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`[]`))
+		case http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("GITLAB_CI", "true")
+	t.Setenv("GITLAB_TOKEN", "secret")
+	t.Setenv("CI_API_V4_URL", server.URL+"/api/v4")
+	t.Setenv("CI_PROJECT_ID", "acme/plugin")
+	t.Setenv("CI_MERGE_REQUEST_IID", "3")
+
+	commenter, err := NewPullRequestCommenter()
+	require.NoError(t, err)
+	require.NoError(t, commenter.UpsertComment(t.Context(), "<!-- marker -->", "body"))
+
+	assert.Equal(t, []string{"GET /api/v4/projects/acme%2Fplugin/merge_requests/3/notes", "POST /api/v4/projects/acme%2Fplugin/merge_requests/3/notes"}, requests)
+}
+
+func TestPullRequestCommentFailure(t *testing.T) {
+	clearCIEnv(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message": "Resource not accessible by integration"}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_REF", "refs/pull/42/merge")
+	t.Setenv("GITHUB_TOKEN", "secret")
+	t.Setenv("GITHUB_API_URL", server.URL)
+	t.Setenv("GITHUB_REPOSITORY", "acme/plugin")
+
+	commenter, err := NewPullRequestCommenter()
+	require.NoError(t, err)
+
+	err = commenter.UpsertComment(t.Context(), "<!-- marker -->", "body")
+	assert.ErrorContains(t, err, "403 Forbidden")
+	assert.ErrorContains(t, err, "Resource not accessible by integration")
+}
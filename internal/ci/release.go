@@ -0,0 +1,336 @@
+package ci
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// Release is published in the repository of the extension with the zip and its checksum
+type Release struct {
+	Tag  string
+	Name string
+	// Body is Markdown, HTML is shown as well
+	Body string
+	// Target is the branch or commit the tag is created from when it does not exist
+	Target     string
+	Draft      bool
+	Prerelease bool
+	// Assets are the files attached to the release
+	Assets []string
+	// Package is the name of the GitLab generic package the assets are uploaded into
+	Package string
+}
+
+// ReleasePublisher creates releases in GitHub or GitLab
+type ReleasePublisher interface {
+	// PublishRelease creates the release of the tag or adds the assets to the existing one and returns its URL.
+	// Assets with the same name are replaced.
+	PublishRelease(ctx context.Context, release Release) (string, error)
+}
+
+// NewReleasePublisher returns the publisher of the provider. repository is the GitHub repository like owner/name or the
+// GitLab project path or ID and defaults to the repository of the CI job. GitHub requires GITHUB_TOKEN and GitLab GITLAB_TOKEN.
+func NewReleasePublisher(provider, repository, apiURL string) (ReleasePublisher, error) {
+	switch provider {
+	case "github", "":
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			return nil, fmt.Errorf("GITHUB_TOKEN is required to create GitHub releases")
+		}
+
+		if repository == "" {
+			repository = os.Getenv("GITHUB_REPOSITORY")
+		}
+
+		if repository == "" {
+			return nil, fmt.Errorf("set release.repository to the GitHub repository like owner/name")
+		}
+
+		if apiURL == "" {
+			apiURL = os.Getenv("GITHUB_API_URL")
+		}
+
+		if apiURL == "" {
+			apiURL = "https://api.github.com"
+		}
+
+		return &GithubReleases{apiURL: strings.TrimSuffix(apiURL, "/"), repository: repository, token: token}, nil
+	case "gitlab":
+		token := os.Getenv("GITLAB_TOKEN")
+		if token == "" {
+			return nil, fmt.Errorf("GITLAB_TOKEN is required to create GitLab releases")
+		}
+
+		if repository == "" {
+			repository = os.Getenv("CI_PROJECT_ID")
+		}
+
+		if repository == "" {
+			return nil, fmt.Errorf("set release.repository to the GitLab project path or ID")
+		}
+
+		if apiURL == "" {
+			apiURL = os.Getenv("CI_API_V4_URL")
+		}
+
+		if apiURL == "" {
+			apiURL = "https://gitlab.com/api/v4"
+		}
+
+		return &GitlabReleases{apiURL: strings.TrimSuffix(apiURL, "/"), projectID: repository, token: token}, nil
+	}
+
+	return nil, fmt.Errorf("unknown release provider %q, must be github or gitlab", provider)
+}
+
+// GithubReleases creates releases with the releases API and uploads the assets to them
+type GithubReleases struct {
+	apiURL     string
+	repository string
+	token      string
+}
+
+type githubRelease struct {
+	ID        int    `json:"id"`
+	TagName   string `json:"tag_name"`
+	HTMLURL   string `json:"html_url"`
+	UploadURL string `json:"upload_url"`
+	Assets    []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"assets"`
+}
+
+func (g *GithubReleases) PublishRelease(ctx context.Context, release Release) (string, error) {
+	releasesURL := fmt.Sprintf("%s/repos/%s/releases", g.apiURL, g.repository)
+
+	var existing []githubRelease
+
+	if err := g.request(ctx, http.MethodGet, releasesURL+"?per_page=100", nil, &existing); err != nil {
+		return "", err
+	}
+
+	var published *githubRelease
+
+	for i := range existing {
+		if existing[i].TagName == release.Tag {
+			published = &existing[i]
+			logging.FromContext(ctx).Infof("Adding the assets to the existing release %s", published.HTMLURL)
+
+			break
+		}
+	}
+
+	if published == nil {
+		if release.Target == "" {
+			release.Target = os.Getenv("GITHUB_SHA")
+		}
+
+		payload := map[string]any{
+			"tag_name":   release.Tag,
+			"name":       release.Name,
+			"body":       release.Body,
+			"draft":      release.Draft,
+			"prerelease": release.Prerelease,
+		}
+
+		if release.Target != "" {
+			payload["target_commitish"] = release.Target
+		}
+
+		published = &githubRelease{}
+
+		if err := g.request(ctx, http.MethodPost, releasesURL, payload, published); err != nil {
+			return "", err
+		}
+	}
+
+	uploadURL, _, _ := strings.Cut(published.UploadURL, "{")
+
+	for _, asset := range release.Assets {
+		name := filepath.Base(asset)
+
+		for _, uploaded := range published.Assets {
+			if uploaded.Name != name {
+				continue
+			}
+
+			if err := g.request(ctx, http.MethodDelete, fmt.Sprintf("%s/assets/%d", releasesURL, uploaded.ID), nil, nil); err != nil {
+				return "", err
+			}
+		}
+
+		if err := uploadReleaseAsset(ctx, http.MethodPost, uploadURL+"?name="+url.QueryEscape(name), g.headers(), asset); err != nil {
+			return "", err
+		}
+	}
+
+	return published.HTMLURL, nil
+}
+
+func (g *GithubReleases) headers() map[string]string {
+	return map[string]string{
+		"Authorization":        "Bearer " + g.token,
+		"Accept":               "application/vnd.github+json",
+		"X-GitHub-Api-Version": "2022-11-28",
+	}
+}
+
+func (g *GithubReleases) request(ctx context.Context, method, requestURL string, payload, response any) error {
+	return doPullRequestAPIRequest(ctx, method, requestURL, g.headers(), payload, response)
+}
+
+// GitlabReleases uploads the assets into the generic package registry and links them in the release, as GitLab
+// releases cannot contain files
+type GitlabReleases struct {
+	apiURL    string
+	projectID string
+	token     string
+}
+
+func (g *GitlabReleases) PublishRelease(ctx context.Context, release Release) (string, error) {
+	projectURL := fmt.Sprintf("%s/projects/%s", g.apiURL, url.PathEscape(g.projectID))
+
+	links := []map[string]string{}
+
+	for _, asset := range release.Assets {
+		name := filepath.Base(asset)
+		packageURL := fmt.Sprintf("%s/packages/generic/%s/%s/%s", projectURL, url.PathEscape(release.Package), url.PathEscape(release.Tag), url.PathEscape(name))
+
+		if err := uploadReleaseAsset(ctx, http.MethodPut, packageURL, g.headers(), asset); err != nil {
+			return "", err
+		}
+
+		links = append(links, map[string]string{"name": name, "url": packageURL, "link_type": "package"})
+	}
+
+	var existing []struct {
+		TagName string `json:"tag_name"`
+		Links   struct {
+			Self string `json:"self"`
+		} `json:"_links"`
+		Assets struct {
+			Links []struct {
+				Name string `json:"name"`
+			} `json:"links"`
+		} `json:"assets"`
+	}
+
+	if err := g.request(ctx, http.MethodGet, projectURL+"/releases?per_page=100", nil, &existing); err != nil {
+		return "", err
+	}
+
+	for _, published := range existing {
+		if published.TagName != release.Tag {
+			continue
+		}
+
+		logging.FromContext(ctx).Infof("Adding the assets to the existing release %s", published.Links.Self)
+
+		for _, link := range links {
+			linked := false
+
+			for _, existingLink := range published.Assets.Links {
+				linked = linked || existingLink.Name == link["name"]
+			}
+
+			if linked {
+				continue
+			}
+
+			if err := g.request(ctx, http.MethodPost, fmt.Sprintf("%s/releases/%s/assets/links", projectURL, url.PathEscape(release.Tag)), link, nil); err != nil {
+				return "", err
+			}
+		}
+
+		return published.Links.Self, nil
+	}
+
+	if release.Target == "" {
+		release.Target = os.Getenv("CI_COMMIT_SHA")
+	}
+
+	payload := map[string]any{
+		"tag_name":    release.Tag,
+		"name":        release.Name,
+		"description": release.Body,
+		"assets":      map[string]any{"links": links},
+	}
+
+	if release.Target != "" {
+		payload["ref"] = release.Target
+	}
+
+	var created struct {
+		Links struct {
+			Self string `json:"self"`
+		} `json:"_links"`
+	}
+
+	if err := g.request(ctx, http.MethodPost, projectURL+"/releases", payload, &created); err != nil {
+		return "", err
+	}
+
+	return created.Links.Self, nil
+}
+
+func (g *GitlabReleases) headers() map[string]string {
+	return map[string]string{"PRIVATE-TOKEN": g.token}
+}
+
+func (g *GitlabReleases) request(ctx context.Context, method, requestURL string, payload, response any) error {
+	return doPullRequestAPIRequest(ctx, method, requestURL, g.headers(), payload, response)
+}
+
+// uploadReleaseAsset sends the content of the file as request body
+func uploadReleaseAsset(ctx context.Context, method, requestURL string, headers map[string]string, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, f)
+	if err != nil {
+		return err
+	}
+
+	req.ContentLength = stat.Size()
+	req.Header.Set("User-Agent", "Shopware CLI")
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		content, _ := io.ReadAll(resp.Body)
+
+		return fmt.Errorf("upload %s failed with %s: %s", filepath.Base(file), resp.Status, strings.TrimSpace(string(content)))
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return nil
+}
@@ -0,0 +1,217 @@
+package ci
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeReleaseAssets(t *testing.T) []string {
+	t.Helper()
+
+	dir := t.TempDir()
+	zip := filepath.Join(dir, "FroshTools.zip")
+	checksum := zip + ".sha256"
+
+	require.NoError(t, os.WriteFile(zip, []byte("zip"), os.ModePerm))
+	require.NoError(t, os.WriteFile(checksum, []byte("abc  FroshTools.zip\n"), os.ModePerm))
+
+	return []string{zip, checksum}
+}
+
+func TestNewReleasePublisherRequiresTokenAndRepository(t *testing.T) {
+	clearCIEnv(t)
+
+	_, err := NewReleasePublisher("github", "acme/plugin", "")
+	assert.ErrorContains(t, err, "GITHUB_TOKEN is required")
+
+	t.Setenv("GITHUB_TOKEN", "secret")
+
+	_, err = NewReleasePublisher("github", "", "")
+	assert.ErrorContains(t, err, "set release.repository")
+
+	t.Setenv("GITHUB_REPOSITORY", "acme/plugin")
+
+	_, err = NewReleasePublisher("github", "", "")
+	assert.NoError(t, err)
+
+	_, err = NewReleasePublisher("gitlab", "acme/plugin", "")
+	assert.ErrorContains(t, err, "GITLAB_TOKEN is required")
+
+	_, err = NewReleasePublisher("bitbucket", "acme/plugin", "")
+	assert.ErrorContains(t, err, "unknown release provider")
+}
+
+func TestGithubReleasesCreatesReleaseWithAssets(t *testing.T) {
+	clearCIEnv(t)
+
+	var requests []string
+
+	uploads := map[string]string{}
+
+	var server *httptest.Server
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		switch {
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`[{"tag_name": "0.9.0"}]`))
+		case r.URL.Path == "/repos/acme/plugin/releases":
+			var payload map[string]any
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			assert.Equal(t, "1.0.0", payload["tag_name"])
+			assert.Equal(t, "FroshTools 1.0.0", payload["name"])
+			assert.Equal(t, "* Fixed the cache", payload["body"])
+			assert.Equal(t, "main", payload["target_commitish"])
+			assert.Equal(t, false, payload["draft"])
+
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 1, "html_url": "https://github.com/acme/plugin/releases/tag/1.0.0", "upload_url": "` + server.URL + `/upload/1/assets{?name,label}"}`))
+		default:
+			assert.Equal(t, "application/octet-stream", r.Header.Get("Content-Type"))
+
+			content, _ := io.ReadAll(r.Body)
+			uploads[r.URL.Query().Get("name")] = string(content)
+
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_TOKEN", "secret")
+
+	publisher, err := NewReleasePublisher("github", "acme/plugin", server.URL)
+	require.NoError(t, err)
+
+	releaseURL, err := publisher.PublishRelease(t.Context(), Release{Tag: "1.0.0", Name: "FroshTools 1.0.0", Body: "* Fixed the cache", Target: "main", Assets: writeReleaseAssets(t)})
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://github.com/acme/plugin/releases/tag/1.0.0", releaseURL)
+	assert.Equal(t, []string{"GET /repos/acme/plugin/releases", "POST /repos/acme/plugin/releases", "POST /upload/1/assets", "POST /upload/1/assets"}, requests)
+	assert.Equal(t, map[string]string{"FroshTools.zip": "zip", "FroshTools.zip.sha256": "abc  FroshTools.zip\n"}, uploads)
+}
+
+func TestGithubReleasesReplacesAssetsOfExistingRelease(t *testing.T) {
+	clearCIEnv(t)
+
+	var requests []string
+
+	var server *httptest.Server
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`[{"id": 1, "tag_name": "1.0.0", "html_url": "https://github.com/acme/plugin/releases/tag/1.0.0", "upload_url": "` + server.URL + `/upload/1/assets{?name,label}", "assets": [{"id": 7, "name": "FroshTools.zip"}]}]`))
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_TOKEN", "secret")
+
+	publisher, err := NewReleasePublisher("github", "acme/plugin", server.URL)
+	require.NoError(t, err)
+
+	releaseURL, err := publisher.PublishRelease(t.Context(), Release{Tag: "1.0.0", Assets: writeReleaseAssets(t)})
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://github.com/acme/plugin/releases/tag/1.0.0", releaseURL)
+	assert.Equal(t, []string{"GET /repos/acme/plugin/releases", "DELETE /repos/acme/plugin/releases/assets/7", "POST /upload/1/assets", "POST /upload/1/assets"}, requests)
+}
+
+func TestGitlabReleasesUploadsPackageAndCreatesRelease(t *testing.T) {
+	clearCIEnv(t)
+
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath())
+		assert.Equal(t, "secret", r.Header.Get("PRIVATE-TOKEN"))
+
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`[]`))
+		case http.MethodPost:
+			var payload struct {
+				TagName     string `json:"tag_name"`
+				Description string `json:"description"`
+				Ref         string `json:"ref"`
+				Assets      struct {
+					Links []map[string]string `json:"links"`
+				} `json:"assets"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			assert.Equal(t, "1.0.0", payload.TagName)
+			assert.Equal(t, "* Fixed the cache", payload.Description)
+			assert.Equal(t, "abc123", payload.Ref)
+			assert.Len(t, payload.Assets.Links, 2)
+			assert.Equal(t, "FroshTools.zip", payload.Assets.Links[0]["name"])
+			assert.Equal(t, "package", payload.Assets.Links[0]["link_type"])
+
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"_links": {"self": "https://gitlab.com/acme/plugin/-/releases/1.0.0"}}`))
+		default:
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("GITLAB_TOKEN", "secret")
+	t.Setenv("CI_COMMIT_SHA", "abc123")
+
+	publisher, err := NewReleasePublisher("gitlab", "acme/plugin", server.URL)
+	require.NoError(t, err)
+
+	releaseURL, err := publisher.PublishRelease(t.Context(), Release{Tag: "1.0.0", Body: "* Fixed the cache", Package: "FroshTools", Assets: writeReleaseAssets(t)})
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://gitlab.com/acme/plugin/-/releases/1.0.0", releaseURL)
+	assert.Equal(t, []string{
+		"PUT /projects/acme%2Fplugin/packages/generic/FroshTools/1.0.0/FroshTools.zip",
+		"PUT /projects/acme%2Fplugin/packages/generic/FroshTools/1.0.0/FroshTools.zip.sha256",
+		"GET /projects/acme%2Fplugin/releases",
+		"POST /projects/acme%2Fplugin/releases",
+	}, requests)
+}
+
+func TestGitlabReleasesLinksMissingAssetsOfExistingRelease(t *testing.T) {
+	clearCIEnv(t)
+
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath())
+
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`[{"tag_name": "1.0.0", "_links": {"self": "https://gitlab.com/acme/plugin/-/releases/1.0.0"}, "assets": {"links": [{"name": "FroshTools.zip"}]}}]`))
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	t.Setenv("GITLAB_TOKEN", "secret")
+
+	publisher, err := NewReleasePublisher("gitlab", "42", server.URL)
+	require.NoError(t, err)
+
+	releaseURL, err := publisher.PublishRelease(t.Context(), Release{Tag: "1.0.0", Package: "FroshTools", Assets: writeReleaseAssets(t)})
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://gitlab.com/acme/plugin/-/releases/1.0.0", releaseURL)
+	assert.Equal(t, "POST /projects/42/releases/1.0.0/assets/links", requests[len(requests)-1])
+	assert.Len(t, requests, 4)
+}
@@ -3,3 +3,11 @@ package color
 import "github.com/charmbracelet/lipgloss"
 
 var GreenText = lipgloss.NewStyle().Foreground(lipgloss.Color("#04B575"))
+
+var RedText = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87"))
+
+var YellowText = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFB86C"))
+
+var GrayText = lipgloss.NewStyle().Foreground(lipgloss.Color("#6C7086"))
+
+var BoldText = lipgloss.NewStyle().Bold(true)
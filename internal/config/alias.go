@@ -0,0 +1,95 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// completionCommands are the hidden commands cobra uses for shell completion, the alias follows them
+var completionCommands = []string{"__complete", "__completeNoDesc"}
+
+// ExpandAlias replaces the first argument with its alias definition. Built-in commands always win over aliases.
+// Aliases can refer to other aliases, loops are reported as error.
+func ExpandAlias(args []string, aliases map[string]string, isCommand func(name string) bool) ([]string, error) {
+	if len(args) == 0 || len(aliases) == 0 {
+		return args, nil
+	}
+
+	for _, completion := range completionCommands {
+		if args[0] == completion {
+			expanded, err := ExpandAlias(args[1:], aliases, isCommand)
+			if err != nil {
+				return nil, err
+			}
+
+			return append([]string{completion}, expanded...), nil
+		}
+	}
+
+	seen := map[string]bool{}
+
+	for {
+		name := args[0]
+		definition, ok := aliases[name]
+
+		if !ok || isCommand(name) {
+			return args, nil
+		}
+
+		if seen[name] {
+			return nil, fmt.Errorf("alias %q expands to itself", name)
+		}
+
+		seen[name] = true
+
+		expansion, err := splitAliasArgs(definition)
+		if err != nil {
+			return nil, fmt.Errorf("alias %q: %w", name, err)
+		}
+
+		if len(expansion) == 0 {
+			return nil, fmt.Errorf("alias %q is empty", name)
+		}
+
+		args = append(expansion, args[1:]...)
+	}
+}
+
+// splitAliasArgs splits an alias definition like a shell would, supporting single and double quotes
+func splitAliasArgs(definition string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg := false
+
+	for _, r := range definition {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in %q", definition)
+	}
+
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func isBuiltinCommand(name string) bool {
+	return name == "extension" || name == "project"
+}
+
+func TestExpandAlias(t *testing.T) {
+	aliases := map[string]string{
+		"rls":     `extension zip . --release --output-directory "build dir"`,
+		"ext":     "extension",
+		"project": "extension validate",
+		"loop":    "loop2",
+		"loop2":   "loop",
+		"pv":      "ext validate --full",
+	}
+
+	cases := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{"no args", []string{}, []string{}},
+		{"unknown command", []string{"foo", "bar"}, []string{"foo", "bar"}},
+		{"alias with quotes", []string{"rls", "--verbose"}, []string{"extension", "zip", ".", "--release", "--output-directory", "build dir", "--verbose"}},
+		{"built-in command wins", []string{"project", "dump"}, []string{"project", "dump"}},
+		{"nested alias", []string{"pv", "."}, []string{"extension", "validate", "--full", "."}},
+		{"completion", []string{"__complete", "ext", ""}, []string{"__complete", "extension", ""}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			expanded, err := ExpandAlias(tc.args, aliases, isBuiltinCommand)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, expanded)
+		})
+	}
+
+	_, err := ExpandAlias([]string{"loop"}, aliases, isBuiltinCommand)
+	assert.ErrorContains(t, err, "expands to itself")
+}
+
+func TestSplitAliasArgs(t *testing.T) {
+	args, err := splitAliasArgs(`a 'b c'  "d 'e'" f""`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b c", "d 'e'", "f"}, args)
+
+	_, err = splitAliasArgs(`a "b`)
+	assert.Error(t, err)
+}
+
+func TestAliasesFromFileWithEnvCredentials(t *testing.T) {
+	defer resetState()
+
+	t.Setenv("SHOPWARE_CLI_ACCOUNT_EMAIL", "test@test.com")
+
+	assert.NoError(t, InitConfig("testdata/aliases.yml"))
+	assert.Equal(t, map[string]string{"rls": "extension zip . --release"}, Config{}.GetAliases())
+}
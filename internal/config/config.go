@@ -29,7 +29,10 @@ type configData struct {
 		Email    string `env:"SHOPWARE_CLI_ACCOUNT_EMAIL" yaml:"email"`
 		Password string `env:"SHOPWARE_CLI_ACCOUNT_PASSWORD" yaml:"password"`
 		Company  int    `env:"SHOPWARE_CLI_ACCOUNT_COMPANY" yaml:"company"`
+		ApiUrl   string `env:"SHOPWARE_CLI_ACCOUNT_API_URL" yaml:"api_url,omitempty"`
 	} `yaml:"account"`
+	// Aliases maps a shortcut to the arguments it expands to, like git aliases
+	Aliases map[string]string `yaml:"aliases,omitempty"`
 }
 
 type ExtensionConfig struct {
@@ -87,6 +90,16 @@ func InitConfig(configPath string) error {
 	if len(state.inner.Account.Email) > 0 {
 		state.loadedFromEnv = true
 
+		// Aliases are not part of the environment, take them from the file anyway
+		if content, err := os.ReadFile(state.cfgPath); err == nil {
+			var fileConfig configData
+			if err := yaml.Unmarshal(content, &fileConfig); err != nil {
+				return err
+			}
+
+			state.inner.Aliases = fileConfig.Aliases
+		}
+
 		state.isReady = true
 
 		return nil
@@ -159,6 +172,18 @@ func (Config) GetAccountPassword() string {
 	return state.inner.Account.Password
 }
 
+func (Config) GetAccountApiUrl() string {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	return state.inner.Account.ApiUrl
+}
+
+func (Config) GetAliases() map[string]string {
+	state.mu.RLock()
+	defer state.mu.RUnlock()
+	return state.inner.Aliases
+}
+
 func (Config) GetAccountCompanyId() int {
 	state.mu.RLock()
 	defer state.mu.RUnlock()
@@ -26,6 +26,7 @@ func TestParseEnvConfig(t *testing.T) {
 	t.Setenv("SHOPWARE_CLI_ACCOUNT_EMAIL", testData.email)
 	t.Setenv("SHOPWARE_CLI_ACCOUNT_PASSWORD", testData.password)
 	t.Setenv("SHOPWARE_CLI_ACCOUNT_COMPANY", strconv.Itoa(testData.companyId))
+	t.Setenv("SHOPWARE_CLI_ACCOUNT_API_URL", "http://127.0.0.1:8080")
 
 	assert.NoError(t, InitConfig(""))
 	assert.True(t, state.loadedFromEnv)
@@ -34,6 +35,7 @@ func TestParseEnvConfig(t *testing.T) {
 	assert.Equal(t, testData.email, confService.GetAccountEmail())
 	assert.Equal(t, testData.password, confService.GetAccountPassword())
 	assert.Equal(t, testData.companyId, confService.GetAccountCompanyId())
+	assert.Equal(t, "http://127.0.0.1:8080", confService.GetAccountApiUrl())
 }
 
 func TestParseFileConfig(t *testing.T) {
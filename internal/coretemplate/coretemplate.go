@@ -0,0 +1,183 @@
+package coretemplate
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/system"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+const storefrontRepository = "https://github.com/shopware/storefront"
+
+// StorefrontCheckout returns the path to a cached checkout of the Shopware storefront in the given version.
+// The checkout is cloned once and reused by later calls.
+func StorefrontCheckout(ctx context.Context, version string) (string, error) {
+	version, err := normalizeVersion(version)
+	if err != nil {
+		return "", err
+	}
+
+	cacheDir := path.Join(system.GetShopwareCliCacheDir(), "core-templates", "storefront", version)
+
+	if _, err := os.Stat(cacheDir); err == nil {
+		logging.FromContext(ctx).Debugf("Using cached storefront checkout %s", cacheDir)
+		return cacheDir, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cacheDir), os.ModePerm); err != nil {
+		return "", err
+	}
+
+	// Clone into a temporary folder first, so an interrupted clone does not leave a broken cache entry
+	tempDir, err := os.MkdirTemp(filepath.Dir(cacheDir), "clone-*")
+	if err != nil {
+		return "", err
+	}
+
+	// After the rename the folder does not exist anymore, otherwise the clone failed
+	defer func() {
+		_ = os.RemoveAll(tempDir)
+	}()
+
+	logging.FromContext(ctx).Infof("Downloading Shopware storefront %s", version)
+
+	git := exec.CommandContext(ctx, "git", "-c", "advice.detachedHead=false", "clone", "-q", "--branch", "v"+version, storefrontRepository, tempDir, "--depth", "1")
+	output, err := git.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("cannot clone storefront in version %s: %w, %s", version, err, output)
+	}
+
+	if err := os.RemoveAll(path.Join(tempDir, ".git")); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tempDir, cacheDir); err != nil {
+		// Another process cloned the same version meanwhile
+		if _, statErr := os.Stat(cacheDir); statErr == nil {
+			return cacheDir, nil
+		}
+
+		return "", err
+	}
+
+	return cacheDir, nil
+}
+
+// normalizeVersion converts versions like v6.6 or 6.6.0 into the 6.6.0.0 of the tags, pre-releases are lowercase like 6.7.0.0-rc1
+func normalizeVersion(v string) (string, error) {
+	parsed, err := version.NewVersion(strings.TrimPrefix(v, "v"))
+	if err != nil {
+		return "", fmt.Errorf("invalid Shopware version %s: %w", v, err)
+	}
+
+	return strings.ToLower(parsed.NormalizedString()), nil
+}
+
+// TemplateName normalizes a template reference like @Storefront/storefront/base.html.twig or
+// src/Resources/views/storefront/base.html.twig to storefront/base.html.twig.
+func TemplateName(template string) string {
+	template = filepath.ToSlash(template)
+
+	if idx := strings.LastIndex(template, "Resources/views/"); idx != -1 {
+		return template[idx+len("Resources/views/"):]
+	}
+
+	if strings.HasPrefix(template, "@") {
+		parts := strings.SplitN(template, "/", 2)
+		if len(parts) == 2 {
+			return parts[1]
+		}
+	}
+
+	return strings.TrimPrefix(template, "/")
+}
+
+// TemplatePath returns the absolute path of the template inside the given checkout.
+func TemplatePath(checkout, template string) string {
+	return path.Join(checkout, "Resources", "views", TemplateName(template))
+}
+
+// ListTemplates returns all twig templates inside the views folder relative to it.
+func ListTemplates(viewsDir string) ([]string, error) {
+	templates := make([]string, 0)
+
+	err := filepath.WalkDir(viewsDir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || filepath.Ext(file) != ".twig" {
+			return nil
+		}
+
+		rel, err := filepath.Rel(viewsDir, file)
+		if err != nil {
+			return err
+		}
+
+		templates = append(templates, filepath.ToSlash(rel))
+
+		return nil
+	})
+
+	return templates, err
+}
+
+// Diff returns a line based diff from oldText to newText.
+func Diff(oldText, newText string) []DiffLine {
+	dmp := diffmatchpatch.New()
+
+	a, b, lines := dmp.DiffLinesToChars(oldText, newText)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(a, b, false), lines)
+
+	result := make([]DiffLine, 0)
+
+	for _, diff := range diffs {
+		for _, line := range strings.SplitAfter(diff.Text, "\n") {
+			if line == "" {
+				continue
+			}
+
+			result = append(result, DiffLine{Type: diff.Type, Text: strings.TrimSuffix(line, "\n")})
+		}
+	}
+
+	return result
+}
+
+type DiffLine struct {
+	Type diffmatchpatch.Operation
+	Text string
+}
+
+func (l DiffLine) String() string {
+	switch l.Type {
+	case diffmatchpatch.DiffInsert:
+		return "+" + l.Text
+	case diffmatchpatch.DiffDelete:
+		return "-" + l.Text
+	default:
+		return " " + l.Text
+	}
+}
+
+// HasChanges reports whether the diff contains any added or removed lines.
+func HasChanges(lines []DiffLine) bool {
+	for _, line := range lines {
+		if line.Type != diffmatchpatch.DiffEqual {
+			return true
+		}
+	}
+
+	return false
+}
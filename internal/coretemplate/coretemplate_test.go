@@ -0,0 +1,60 @@
+package coretemplate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateName(t *testing.T) {
+	assert.Equal(t, "storefront/base.html.twig", TemplateName("@Storefront/storefront/base.html.twig"))
+	assert.Equal(t, "storefront/base.html.twig", TemplateName("src/Resources/views/storefront/base.html.twig"))
+	assert.Equal(t, "storefront/base.html.twig", TemplateName("storefront/base.html.twig"))
+	assert.Equal(t, "storefront/base.html.twig", TemplateName("/storefront/base.html.twig"))
+}
+
+func TestNormalizeVersion(t *testing.T) {
+	for input, expected := range map[string]string{
+		"6.6.0.0":     "6.6.0.0",
+		"v6.6.0":      "6.6.0.0",
+		"6.6":         "6.6.0.0",
+		"6.7.0.0-RC1": "6.7.0.0-rc1",
+	} {
+		normalized, err := normalizeVersion(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, normalized, input)
+	}
+
+	_, err := normalizeVersion("trunk")
+	assert.Error(t, err)
+}
+
+func TestDiff(t *testing.T) {
+	lines := Diff("a\nb\nc\n", "a\nd\nc\n")
+
+	assert.True(t, HasChanges(lines))
+	assert.Equal(t, []string{" a", "-b", "+d", " c"}, []string{lines[0].String(), lines[1].String(), lines[2].String(), lines[3].String()})
+}
+
+func TestDiffWithoutChanges(t *testing.T) {
+	lines := Diff("a\nb\n", "a\nb\n")
+
+	assert.False(t, HasChanges(lines))
+	assert.Len(t, lines, 2)
+	assert.Equal(t, diffmatchpatch.DiffEqual, lines[0].Type)
+}
+
+func TestListTemplates(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "storefront", "page"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "storefront", "page", "index.html.twig"), []byte(""), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "storefront", "README.md"), []byte(""), os.ModePerm))
+
+	templates, err := ListTemplates(tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"storefront/page/index.html.twig"}, templates)
+}
@@ -0,0 +1,79 @@
+package coretemplate
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"sort"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+const (
+	DriftStatusChanged = "changed"
+	DriftStatusRemoved = "removed"
+)
+
+type DriftEntry struct {
+	Template     string
+	Status       string
+	ChangedLines int
+	OverriddenBy []string
+}
+
+// CalculateDrift compares the overridden templates between two storefront checkouts.
+// overrides maps the template name to the extensions overriding it. Templates which are not part of the core in
+// the old version or did not change are omitted. The result is sorted by review priority: removed templates first,
+// then by the amount of changed lines.
+func CalculateDrift(fromCheckout, toCheckout string, overrides map[string][]string) ([]DriftEntry, error) {
+	entries := make([]DriftEntry, 0)
+
+	for template, overriddenBy := range overrides {
+		oldContent, err := os.ReadFile(TemplatePath(fromCheckout, template))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		newContent, err := os.ReadFile(TemplatePath(toCheckout, template))
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+
+			entries = append(entries, DriftEntry{Template: template, Status: DriftStatusRemoved, OverriddenBy: overriddenBy})
+			continue
+		}
+
+		changedLines := 0
+
+		for _, line := range Diff(string(oldContent), string(newContent)) {
+			if line.Type != diffmatchpatch.DiffEqual {
+				changedLines++
+			}
+		}
+
+		if changedLines == 0 {
+			continue
+		}
+
+		entries = append(entries, DriftEntry{Template: template, Status: DriftStatusChanged, ChangedLines: changedLines, OverriddenBy: overriddenBy})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Status != entries[j].Status {
+			return entries[i].Status == DriftStatusRemoved
+		}
+
+		if entries[i].ChangedLines != entries[j].ChangedLines {
+			return entries[i].ChangedLines > entries[j].ChangedLines
+		}
+
+		return entries[i].Template < entries[j].Template
+	})
+
+	return entries, nil
+}
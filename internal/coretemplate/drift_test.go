@@ -0,0 +1,56 @@
+package coretemplate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTemplate(t *testing.T, checkout, template, content string) {
+	t.Helper()
+
+	file := TemplatePath(checkout, template)
+
+	assert.NoError(t, os.MkdirAll(filepath.Dir(file), os.ModePerm))
+	assert.NoError(t, os.WriteFile(file, []byte(content), os.ModePerm))
+}
+
+func TestCalculateDrift(t *testing.T) {
+	from := t.TempDir()
+	to := t.TempDir()
+
+	writeTemplate(t, from, "storefront/base.html.twig", "a\nb\n")
+	writeTemplate(t, to, "storefront/base.html.twig", "a\nb\n")
+
+	writeTemplate(t, from, "storefront/small.html.twig", "a\nb\n")
+	writeTemplate(t, to, "storefront/small.html.twig", "a\nc\n")
+
+	writeTemplate(t, from, "storefront/big.html.twig", "a\nb\nc\n")
+	writeTemplate(t, to, "storefront/big.html.twig", "d\ne\nf\n")
+
+	writeTemplate(t, from, "storefront/removed.html.twig", "a\n")
+
+	overrides := map[string][]string{
+		"storefront/base.html.twig":    {"FroshTools"},
+		"storefront/small.html.twig":   {"FroshTools", "MyTheme"},
+		"storefront/big.html.twig":     {"MyTheme"},
+		"storefront/removed.html.twig": {"MyTheme"},
+		"storefront/own.html.twig":     {"MyTheme"},
+	}
+
+	entries, err := CalculateDrift(from, to, overrides)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+
+	assert.Equal(t, "storefront/removed.html.twig", entries[0].Template)
+	assert.Equal(t, DriftStatusRemoved, entries[0].Status)
+
+	assert.Equal(t, "storefront/big.html.twig", entries[1].Template)
+	assert.Equal(t, 6, entries[1].ChangedLines)
+
+	assert.Equal(t, "storefront/small.html.twig", entries[2].Template)
+	assert.Equal(t, 2, entries[2].ChangedLines)
+	assert.Equal(t, []string{"FroshTools", "MyTheme"}, entries[2].OverriddenBy)
+}
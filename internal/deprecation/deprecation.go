@@ -0,0 +1,114 @@
+package deprecation
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+//go:generate go run ../../scripts/shopware-deprecations -output shopware-deprecations.json
+
+// shopwareDeprecations is generated with go generate, do not edit it by hand
+//
+//go:embed shopware-deprecations.json
+var shopwareDeprecations []byte
+
+// Kinds of Shopware APIs tracked in the database
+const (
+	KindClass     = "class"
+	KindService   = "service"
+	KindComponent = "component"
+)
+
+// Entry is a Shopware API with the releases which added, deprecated and removed it, the versions are empty when not known
+type Entry struct {
+	Kind        string
+	Name        string
+	Replacement string
+	Added       string
+	Deprecated  string
+	Removed     string
+}
+
+// Database contains the added, deprecated and removed Shopware APIs by kind and name
+type Database struct {
+	entries  map[string]map[string]*Entry
+	releases []string
+}
+
+type databaseFile struct {
+	Releases []struct {
+		Version    string          `json:"version"`
+		Added      []databaseEntry `json:"added"`
+		Deprecated []databaseEntry `json:"deprecated"`
+		Removed    []databaseEntry `json:"removed"`
+	} `json:"releases"`
+}
+
+type databaseEntry struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Replacement string `json:"replacement"`
+}
+
+// NewDatabase loads the bundled deprecation database
+func NewDatabase() (*Database, error) {
+	var file databaseFile
+
+	if err := json.Unmarshal(shopwareDeprecations, &file); err != nil {
+		return nil, fmt.Errorf("cannot parse deprecation database: %w", err)
+	}
+
+	db := &Database{entries: map[string]map[string]*Entry{}}
+
+	for _, release := range file.Releases {
+		db.releases = append(db.releases, release.Version)
+
+		for _, item := range release.Added {
+			db.entry(item).Added = release.Version
+		}
+
+		for _, item := range release.Deprecated {
+			db.entry(item).Deprecated = release.Version
+		}
+
+		for _, item := range release.Removed {
+			db.entry(item).Removed = release.Version
+		}
+	}
+
+	return db, nil
+}
+
+func (d *Database) entry(item databaseEntry) *Entry {
+	if _, ok := d.entries[item.Type]; !ok {
+		d.entries[item.Type] = map[string]*Entry{}
+	}
+
+	entry, ok := d.entries[item.Type][item.Name]
+	if !ok {
+		entry = &Entry{Kind: item.Type, Name: item.Name}
+		d.entries[item.Type][item.Name] = entry
+	}
+
+	if item.Replacement != "" {
+		entry.Replacement = item.Replacement
+	}
+
+	return entry
+}
+
+// Lookup returns the entry of the given kind and name
+func (d *Database) Lookup(kind, name string) (Entry, bool) {
+	entry, ok := d.entries[kind][name]
+	if !ok {
+		return Entry{}, false
+	}
+
+	return *entry, true
+}
+
+// Releases returns the Shopware releases the database has changes for
+func (d *Database) Releases() []string {
+	return d.releases
+}
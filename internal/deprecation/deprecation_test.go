@@ -0,0 +1,36 @@
+package deprecation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDatabase(t *testing.T) {
+	db, err := NewDatabase()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, db.Releases())
+}
+
+func TestDatabaseLookupMergesReleases(t *testing.T) {
+	db, err := NewDatabase()
+	assert.NoError(t, err)
+
+	entry, ok := db.Lookup(KindClass, "Shopware\\Core\\Framework\\Routing\\Annotation\\RouteScope")
+	assert.True(t, ok)
+	assert.Equal(t, "6.4.11.0", entry.Deprecated)
+	assert.Equal(t, "6.5.0.0", entry.Removed)
+
+	entry, ok = db.Lookup(KindComponent, "sw-button")
+	assert.True(t, ok)
+	assert.Equal(t, "mt-button", entry.Replacement)
+	assert.Equal(t, "6.7.0.0", entry.Removed)
+
+	entry, ok = db.Lookup(KindComponent, "mt-button")
+	assert.True(t, ok)
+	assert.Equal(t, "6.6.0.0", entry.Added)
+	assert.Empty(t, entry.Removed)
+
+	_, ok = db.Lookup(KindComponent, "sw-page")
+	assert.False(t, ok)
+}
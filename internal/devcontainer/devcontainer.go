@@ -0,0 +1,163 @@
+// Package devcontainer runs a disposable Shopware shop in Docker for extension development
+package devcontainer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// DefaultImage ships a fully installed Shopware with Xdebug, tagged by the Shopware version
+const DefaultImage = "dockware/dev"
+
+// ShopRoot is the folder of Shopware inside the image
+const ShopRoot = "/var/www/html"
+
+type Mount struct {
+	Source string
+	// Target is relative to the shop root
+	Target string
+}
+
+type Options struct {
+	Name    string
+	Image   string
+	Version string
+	// Port on the host for the shop, the sales channel domain of the image is http://localhost
+	Port   int
+	Xdebug bool
+	Mounts []Mount
+}
+
+func (o Options) ImageName() string {
+	image := o.Image
+	if image == "" {
+		image = DefaultImage
+	}
+
+	version := o.Version
+	if version == "" {
+		version = "latest"
+	}
+
+	return image + ":" + version
+}
+
+// URL returns the address of the shop on the host
+func (o Options) URL() string {
+	if o.Port == 80 {
+		return "http://localhost"
+	}
+
+	return fmt.Sprintf("http://localhost:%d", o.Port)
+}
+
+func RunArguments(o Options) []string {
+	arguments := []string{"run", "--detach", "--name", o.Name, "--publish", strconv.Itoa(o.Port) + ":80"}
+
+	if o.Xdebug {
+		arguments = append(arguments, "--env", "XDEBUG_ENABLED=1")
+	}
+
+	for _, mount := range o.Mounts {
+		arguments = append(arguments, "--volume", mount.Source+":"+path.Join(ShopRoot, mount.Target))
+	}
+
+	return append(arguments, o.ImageName())
+}
+
+// State returns whether a container with the name exists and runs
+func State(ctx context.Context, name string) (exists, running bool, err error) {
+	output, err := exec.CommandContext(ctx, "docker", "container", "inspect", "--format", "{{.State.Running}}", name).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return false, false, nil
+		}
+
+		return false, false, err
+	}
+
+	return true, strings.TrimSpace(string(output)) == "true", nil
+}
+
+// Up creates the container, an existing container of the same name is started again and keeps its options
+func Up(ctx context.Context, o Options) error {
+	exists, running, err := State(ctx, o.Name)
+	if err != nil {
+		return err
+	}
+
+	if running {
+		return nil
+	}
+
+	arguments := RunArguments(o)
+
+	if exists {
+		logging.FromContext(ctx).Warnf("Starting the existing container %s, remove it with docker rm -f %s to apply changed options", o.Name, o.Name)
+		arguments = []string{"start", o.Name}
+	} else {
+		logging.FromContext(ctx).Infof("Starting %s as %s", o.ImageName(), o.Name)
+	}
+
+	docker := exec.CommandContext(ctx, "docker", arguments...)
+	docker.Stdout = os.Stderr
+	docker.Stderr = os.Stderr
+
+	return docker.Run()
+}
+
+// Console runs bin/console in the shop of the container
+func Console(ctx context.Context, name string, args ...string) error {
+	arguments := append([]string{"exec", "--user", "www-data", "--workdir", ShopRoot, name, "php", "bin/console"}, args...)
+
+	docker := exec.CommandContext(ctx, "docker", arguments...)
+	docker.Stdout = os.Stderr
+	docker.Stderr = os.Stderr
+
+	if err := docker.Run(); err != nil {
+		return fmt.Errorf("bin/console %s: %w", strings.Join(args, " "), err)
+	}
+
+	return nil
+}
+
+// WaitForShop polls the shop until it answers without a server error, the database and webserver take a while to start
+func WaitForShop(ctx context.Context, url string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		r, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+		if err != nil {
+			return err
+		}
+
+		if resp, err := http.DefaultClient.Do(r); err == nil {
+			_ = resp.Body.Close()
+
+			if resp.StatusCode < http.StatusInternalServerError {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("the shop at %s did not start within %s", url, timeout)
+		case <-ticker.C:
+		}
+	}
+}
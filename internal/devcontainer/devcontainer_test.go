@@ -0,0 +1,63 @@
+package devcontainer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunArguments(t *testing.T) {
+	o := Options{
+		Name:    "shopware-frosh-tools",
+		Version: "6.6.10.0",
+		Port:    80,
+		Xdebug:  true,
+		Mounts:  []Mount{{Source: "/home/dev/FroshTools", Target: "custom/plugins/FroshTools"}},
+	}
+
+	assert.Equal(t, []string{
+		"run", "--detach", "--name", "shopware-frosh-tools", "--publish", "80:80",
+		"--env", "XDEBUG_ENABLED=1",
+		"--volume", "/home/dev/FroshTools:/var/www/html/custom/plugins/FroshTools",
+		"dockware/dev:6.6.10.0",
+	}, RunArguments(o))
+
+	assert.Equal(t, "http://localhost", o.URL())
+}
+
+func TestImageNameDefaults(t *testing.T) {
+	assert.Equal(t, "dockware/dev:latest", Options{}.ImageName())
+	assert.Equal(t, "registry.example.com/shopware:6.5", Options{Image: "registry.example.com/shopware", Version: "6.5"}.ImageName())
+	assert.Equal(t, "http://localhost:8000", Options{Port: 8000}.URL())
+}
+
+func TestWaitForShop(t *testing.T) {
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	require.NoError(t, WaitForShop(t.Context(), server.URL, 10*time.Second))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestWaitForShopTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	assert.Error(t, WaitForShop(t.Context(), server.URL, 100*time.Millisecond))
+}
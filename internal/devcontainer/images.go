@@ -0,0 +1,266 @@
+package devcontainer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// The sources an image was made available from
+const (
+	ImageSourceLocal    = "local"
+	ImageSourceCache    = "cache"
+	ImageSourceRegistry = "registry"
+)
+
+const imageCacheIndexFile = "images.json"
+
+var imageCacheFileRegExp = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// ResolveVersions maps majors like 6.6 to the newest stable release of the major, full versions and latest are kept
+func ResolveVersions(requested, released []string) ([]string, error) {
+	resolved := make([]string, 0, len(requested))
+
+	for _, requestedVersion := range requested {
+		requestedVersion = strings.TrimSpace(requestedVersion)
+
+		if requestedVersion == "latest" || strings.Count(requestedVersion, ".") > 1 {
+			resolved = append(resolved, requestedVersion)
+			continue
+		}
+
+		var newest *version.Version
+
+		for _, r := range released {
+			v, err := version.NewVersion(r)
+			if err != nil || v.Prerelease() != "" {
+				continue
+			}
+
+			segments := v.Segments()
+			if len(segments) < 2 || fmt.Sprintf("%d.%d", segments[0], segments[1]) != requestedVersion {
+				continue
+			}
+
+			if newest == nil || v.GreaterThan(newest) {
+				newest = v
+			}
+		}
+
+		if newest == nil {
+			return nil, fmt.Errorf("no released Shopware version matches %s", requestedVersion)
+		}
+
+		resolved = append(resolved, newest.String())
+	}
+
+	return resolved, nil
+}
+
+// ImageCache keeps saved images as tar files in a folder, which can be shared between CI jobs
+type ImageCache struct {
+	Dir string
+}
+
+type imageCacheEntry struct {
+	File string `json:"file"`
+	// Sha256 of the tar file, to detect incomplete or modified files
+	Sha256 string `json:"sha256"`
+	// Id is the image id reported by Docker
+	Id string `json:"id"`
+}
+
+func (c ImageCache) readIndex() (map[string]imageCacheEntry, error) {
+	index := map[string]imageCacheEntry{}
+
+	content, err := os.ReadFile(filepath.Join(c.Dir, imageCacheIndexFile))
+	if os.IsNotExist(err) {
+		return index, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, &index); err != nil {
+		return nil, fmt.Errorf("read image cache index: %w", err)
+	}
+
+	return index, nil
+}
+
+func (c ImageCache) writeIndex(index map[string]imageCacheEntry) error {
+	content, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(c.Dir, imageCacheIndexFile), content, os.ModePerm)
+}
+
+func imageCacheFileName(image string) string {
+	return imageCacheFileRegExp.ReplaceAllString(image, "_") + ".tar"
+}
+
+// PullImage makes the image available in Docker from the local images, the cache or the registry and returns the source.
+// Images pulled from the registry are saved into the cache, images of the cache are verified by checksum and image id.
+func PullImage(ctx context.Context, image string, cache *ImageCache) (string, error) {
+	localId, _ := imageId(ctx, image)
+
+	if cache == nil {
+		if localId != "" {
+			return ImageSourceLocal, nil
+		}
+
+		return ImageSourceRegistry, dockerRun(ctx, "pull", image)
+	}
+
+	if err := os.MkdirAll(cache.Dir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	index, err := cache.readIndex()
+	if err != nil {
+		return "", err
+	}
+
+	entry, cached := index[image]
+
+	if cached && localId == "" {
+		if err := loadCachedImage(ctx, cache, image, entry); err != nil {
+			logging.FromContext(ctx).Warnf("Ignoring the cached %s: %v", image, err)
+		} else {
+			return ImageSourceCache, nil
+		}
+	}
+
+	source := ImageSourceLocal
+
+	if localId == "" {
+		if err := dockerRun(ctx, "pull", image); err != nil {
+			return "", err
+		}
+
+		source = ImageSourceRegistry
+
+		if localId, err = imageId(ctx, image); err != nil {
+			return "", fmt.Errorf("verify %s: %w", image, err)
+		}
+	}
+
+	if cached && entry.Id == localId {
+		return source, nil
+	}
+
+	entry, err = saveImage(ctx, cache, image, localId)
+	if err != nil {
+		return "", err
+	}
+
+	// Reread the index, parallel jobs may have added images meanwhile
+	index, err = cache.readIndex()
+	if err != nil {
+		return "", err
+	}
+
+	index[image] = entry
+
+	return source, cache.writeIndex(index)
+}
+
+func loadCachedImage(ctx context.Context, cache *ImageCache, image string, entry imageCacheEntry) error {
+	file := filepath.Join(cache.Dir, entry.File)
+
+	checksum, err := fileSha256(file)
+	if err != nil {
+		return err
+	}
+
+	if checksum != entry.Sha256 {
+		return fmt.Errorf("the checksum of %s does not match", entry.File)
+	}
+
+	if err := dockerRun(ctx, "load", "--input", file); err != nil {
+		return err
+	}
+
+	loadedId, err := imageId(ctx, image)
+	if err != nil {
+		return err
+	}
+
+	if loadedId != entry.Id {
+		return fmt.Errorf("loaded image id %s, expected %s", loadedId, entry.Id)
+	}
+
+	return nil
+}
+
+func saveImage(ctx context.Context, cache *ImageCache, image, id string) (imageCacheEntry, error) {
+	entry := imageCacheEntry{File: imageCacheFileName(image), Id: id}
+
+	// Saved under a temporary name, so an interrupted save does not leave a broken file behind
+	tmpFile := filepath.Join(cache.Dir, entry.File+".tmp")
+
+	if err := dockerRun(ctx, "save", "--output", tmpFile, image); err != nil {
+		return entry, err
+	}
+
+	checksum, err := fileSha256(tmpFile)
+	if err != nil {
+		return entry, err
+	}
+
+	entry.Sha256 = checksum
+
+	return entry, os.Rename(tmpFile, filepath.Join(cache.Dir, entry.File))
+}
+
+func imageId(ctx context.Context, image string) (string, error) {
+	output, err := exec.CommandContext(ctx, "docker", "image", "inspect", "--format", "{{.Id}}", image).Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+func dockerRun(ctx context.Context, args ...string) error {
+	docker := exec.CommandContext(ctx, "docker", args...)
+	docker.Stdout = os.Stderr
+	docker.Stderr = os.Stderr
+
+	if err := docker.Run(); err != nil {
+		return fmt.Errorf("docker %s: %w", args[0], err)
+	}
+
+	return nil
+}
+
+func fileSha256(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,36 @@
+package devcontainer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveVersions(t *testing.T) {
+	released := []string{"6.5.8.0", "6.5.8.14", "6.6.9.0", "6.6.10.0", "6.6.10.1-rc1", "v6.7.0.0-rc1"}
+
+	versions, err := ResolveVersions([]string{"6.5", " 6.6", "6.4.20.2", "latest"}, released)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"6.5.8.14", "6.6.10.0", "6.4.20.2", "latest"}, versions)
+
+	_, err = ResolveVersions([]string{"6.7"}, released)
+	assert.ErrorContains(t, err, "no released Shopware version matches 6.7")
+}
+
+func TestImageCacheIndex(t *testing.T) {
+	cache := ImageCache{Dir: t.TempDir()}
+
+	index, err := cache.readIndex()
+	require.NoError(t, err)
+	assert.Empty(t, index)
+
+	index["dockware/dev:6.6.10.0"] = imageCacheEntry{File: imageCacheFileName("dockware/dev:6.6.10.0"), Sha256: "abc", Id: "sha256:def"}
+	require.NoError(t, cache.writeIndex(index))
+
+	index, err = cache.readIndex()
+	require.NoError(t, err)
+
+	assert.Equal(t, imageCacheEntry{File: "dockware_dev_6.6.10.0.tar", Sha256: "abc", Id: "sha256:def"}, index["dockware/dev:6.6.10.0"])
+}
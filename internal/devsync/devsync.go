@@ -0,0 +1,260 @@
+// Package devsync copies changed files of an extension into a remote shop over SSH or SFTP
+package devsync
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultPatterns are the files which do not need a build to take effect in the shop
+var DefaultPatterns = []string{"*.php", "*.twig", "*.xml", "*.yml", "*.yaml"}
+
+// skippedDirs are never synchronized, vendor and node_modules are installed on the remote
+var skippedDirs = []string{".git", "node_modules", "vendor"}
+
+// Target is the remote shop, given as user@host:/path, ssh://user@host:port/path or sftp://user@host:port/path
+type Target struct {
+	SFTP bool
+	// Host contains the user like user@host
+	Host string
+	Port string
+	// Root is the folder of the shop on the remote
+	Root string
+}
+
+func ParseTarget(to string) (Target, error) {
+	if strings.HasPrefix(to, "ssh://") || strings.HasPrefix(to, "sftp://") {
+		u, err := url.Parse(to)
+		if err != nil {
+			return Target{}, err
+		}
+
+		host := u.Hostname()
+		if u.User != nil {
+			host = u.User.Username() + "@" + host
+		}
+
+		if u.Hostname() == "" || u.Path == "" {
+			return Target{}, fmt.Errorf("%s requires a host and the path of the shop", to)
+		}
+
+		return Target{SFTP: u.Scheme == "sftp", Host: host, Port: u.Port(), Root: u.Path}, nil
+	}
+
+	host, root, found := strings.Cut(to, ":")
+	if !found || host == "" || root == "" {
+		return Target{}, fmt.Errorf("%s must be user@host:/path, ssh://user@host/path or sftp://user@host/path", to)
+	}
+
+	return Target{Host: host, Root: root}, nil
+}
+
+// Snapshot contains the modification time and size of the matching files by their slash separated path
+type Snapshot map[string]string
+
+// TakeSnapshot collects the files of the folder matching one of the patterns
+func TakeSnapshot(root string, patterns []string) (Snapshot, error) {
+	snapshot := Snapshot{}
+
+	err := filepath.WalkDir(root, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if file != root && slices.Contains(skippedDirs, d.Name()) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !matchesPatterns(d.Name(), patterns) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			return err
+		}
+
+		snapshot[filepath.ToSlash(rel)] = fmt.Sprintf("%d-%d", info.ModTime().UnixNano(), info.Size())
+
+		return nil
+	})
+
+	return snapshot, err
+}
+
+func matchesPatterns(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Changes returns the sorted files which are new or modified in next and the files which were removed
+func Changes(previous, next Snapshot) (changed, removed []string) {
+	for file, state := range next {
+		if previous[file] != state {
+			changed = append(changed, file)
+		}
+	}
+
+	for file := range previous {
+		if _, ok := next[file]; !ok {
+			removed = append(removed, file)
+		}
+	}
+
+	slices.Sort(changed)
+	slices.Sort(removed)
+
+	return changed, removed
+}
+
+// Upload copies the files relative to localRoot into remoteDir of the target, with rsync over SSH or the sftp client
+func Upload(ctx context.Context, target Target, localRoot, remoteDir string, files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	var cmd *exec.Cmd
+
+	if target.SFTP {
+		cmd = exec.CommandContext(ctx, "sftp", sftpArgs(target)...)
+		cmd.Stdin = strings.NewReader(sftpBatch(remoteDir, files))
+	} else {
+		cmd = exec.CommandContext(ctx, "rsync", rsyncArgs(target, remoteDir)...)
+		cmd.Stdin = strings.NewReader(strings.Join(files, "\n") + "\n")
+	}
+
+	cmd.Dir = localRoot
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w, %s", cmd.Args[0], err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// RunCommand executes the command in the shop folder over SSH, also for SFTP targets
+func RunCommand(ctx context.Context, target Target, command string) error {
+	cmd := exec.CommandContext(ctx, "ssh", sshArgs(target, fmt.Sprintf("cd %s && %s", shellQuote(target.Root), command))...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+func rsyncArgs(target Target, remoteDir string) []string {
+	shell := "ssh"
+	if target.Port != "" {
+		shell += " -p " + target.Port
+	}
+
+	return []string{"-az", "--files-from=-", "-e", shell, ".", fmt.Sprintf("%s:%s/", target.Host, remoteDir)}
+}
+
+func sftpArgs(target Target) []string {
+	args := []string{"-b", "-"}
+
+	if target.Port != "" {
+		args = append(args, "-P", target.Port)
+	}
+
+	return append(args, target.Host)
+}
+
+// sftpBatch creates the missing folders, a leading - lets sftp ignore the error of existing ones
+func sftpBatch(remoteDir string, files []string) string {
+	var batch strings.Builder
+
+	created := map[string]bool{}
+
+	for _, file := range files {
+		dir := remoteDir
+
+		for _, segment := range append([]string{""}, strings.Split(path.Dir(file), "/")...) {
+			if segment == "." {
+				continue
+			}
+
+			dir = path.Join(dir, segment)
+
+			if !created[dir] {
+				created[dir] = true
+				fmt.Fprintf(&batch, "-mkdir %s\n", sftpQuote(dir))
+			}
+		}
+
+		fmt.Fprintf(&batch, "put %s %s\n", sftpQuote(file), sftpQuote(path.Join(remoteDir, file)))
+	}
+
+	return batch.String()
+}
+
+func sshArgs(target Target, command string) []string {
+	args := []string{}
+
+	if target.Port != "" {
+		args = append(args, "-p", target.Port)
+	}
+
+	return append(args, target.Host, command)
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func sftpQuote(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
+
+// Watch calls onChange with the changed and removed files whenever the folder changed, until the context is done
+func Watch(ctx context.Context, root string, patterns []string, interval time.Duration, previous Snapshot, onChange func(changed, removed []string) error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		next, err := TakeSnapshot(root, patterns)
+		if err != nil {
+			return err
+		}
+
+		changed, removed := Changes(previous, next)
+		if len(changed) == 0 && len(removed) == 0 {
+			continue
+		}
+
+		if err := onChange(changed, removed); err != nil {
+			return err
+		}
+
+		previous = next
+	}
+}
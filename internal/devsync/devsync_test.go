@@ -0,0 +1,78 @@
+package devsync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTarget(t *testing.T) {
+	target, err := ParseTarget("dev@shop.example.com:/var/www/shop")
+	require.NoError(t, err)
+	assert.Equal(t, Target{Host: "dev@shop.example.com", Root: "/var/www/shop"}, target)
+
+	target, err = ParseTarget("sftp://dev@shop.example.com:2222/var/www/shop")
+	require.NoError(t, err)
+	assert.Equal(t, Target{SFTP: true, Host: "dev@shop.example.com", Port: "2222", Root: "/var/www/shop"}, target)
+
+	target, err = ParseTarget("ssh://shop.example.com/var/www/shop")
+	require.NoError(t, err)
+	assert.Equal(t, Target{Host: "shop.example.com", Root: "/var/www/shop"}, target)
+
+	_, err = ParseTarget("shop.example.com")
+	assert.Error(t, err)
+
+	_, err = ParseTarget("sftp://shop.example.com")
+	assert.Error(t, err)
+}
+
+func TestSnapshotChanges(t *testing.T) {
+	root := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "src", "Resources", "views"), os.ModePerm))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "vendor", "foo"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "src", "Plugin.php"), []byte("<?php"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "src", "Resources", "views", "base.html.twig"), []byte("{}"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "src", "main.js"), []byte(""), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "vendor", "foo", "Foo.php"), []byte("<?php"), os.ModePerm))
+
+	previous, err := TakeSnapshot(root, DefaultPatterns)
+	require.NoError(t, err)
+	assert.Len(t, previous, 2)
+
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(filepath.Join(root, "src", "Plugin.php"), later, later))
+	require.NoError(t, os.Remove(filepath.Join(root, "src", "Resources", "views", "base.html.twig")))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "src", "Service.php"), []byte("<?php"), os.ModePerm))
+
+	next, err := TakeSnapshot(root, DefaultPatterns)
+	require.NoError(t, err)
+
+	changed, removed := Changes(previous, next)
+	assert.Equal(t, []string{"src/Plugin.php", "src/Service.php"}, changed)
+	assert.Equal(t, []string{"src/Resources/views/base.html.twig"}, removed)
+}
+
+func TestCommandArgs(t *testing.T) {
+	target := Target{Host: "dev@shop", Port: "2222", Root: "/var/www/shop"}
+
+	assert.Equal(t, []string{"-az", "--files-from=-", "-e", "ssh -p 2222", ".", "dev@shop:/var/www/shop/custom/plugins/FroshTools/"}, rsyncArgs(target, "/var/www/shop/custom/plugins/FroshTools"))
+	assert.Equal(t, []string{"-b", "-", "-P", "2222", "dev@shop"}, sftpArgs(target))
+	assert.Equal(t, []string{"-p", "2222", "dev@shop", "bin/console cache:clear"}, sshArgs(target, "bin/console cache:clear"))
+}
+
+func TestSftpBatch(t *testing.T) {
+	batch := sftpBatch("/shop/custom/plugins/FroshTools", []string{"src/Plugin.php", "src/Resources/config/services.xml"})
+
+	assert.Equal(t, `-mkdir "/shop/custom/plugins/FroshTools"
+-mkdir "/shop/custom/plugins/FroshTools/src"
+put "src/Plugin.php" "/shop/custom/plugins/FroshTools/src/Plugin.php"
+-mkdir "/shop/custom/plugins/FroshTools/src/Resources"
+-mkdir "/shop/custom/plugins/FroshTools/src/Resources/config"
+put "src/Resources/config/services.xml" "/shop/custom/plugins/FroshTools/src/Resources/config/services.xml"
+`, batch)
+}
@@ -0,0 +1,266 @@
+// Package doctor checks the local environment and suggests fixes for common setup problems.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopware/shopware-cli/internal/system"
+)
+
+type Status int
+
+const (
+	StatusOK Status = iota
+	StatusWarning
+	StatusError
+)
+
+type Result struct {
+	Name    string
+	Status  Status
+	Message string
+	// Fix describes how the user can solve the problem
+	Fix string
+}
+
+type Check func(ctx context.Context) Result
+
+// Run executes all checks in parallel and returns the results in the order of the checks
+func Run(ctx context.Context, checks []Check) []Result {
+	results := make([]Result, len(checks))
+
+	var wg sync.WaitGroup
+
+	for i, check := range checks {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			results[i] = check(ctx)
+		}()
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+func PHPCheck(minVersion string) Check {
+	return func(_ context.Context) Result {
+		result := Result{Name: "PHP"}
+
+		installed, err := system.GetInstalledPHPVersion()
+		if err != nil {
+			result.Status = StatusError
+			result.Message = err.Error()
+			result.Fix = fmt.Sprintf("Install PHP %s or newer and make sure the php binary is in your PATH", minVersion)
+
+			return result
+		}
+
+		ok, err := system.IsPHPVersionAtLeast(minVersion)
+		if err != nil || !ok {
+			result.Status = StatusError
+			result.Message = fmt.Sprintf("PHP %s is installed, but at least %s is required", installed, minVersion)
+			result.Fix = fmt.Sprintf("Update PHP to %s or newer", minVersion)
+
+			return result
+		}
+
+		result.Message = fmt.Sprintf("PHP %s", installed)
+
+		return result
+	}
+}
+
+func NodeCheck(minVersion string) Check {
+	return func(_ context.Context) Result {
+		result := Result{Name: "Node.js"}
+
+		installed, err := system.GetInstalledNodeVersion()
+		if err != nil {
+			result.Status = StatusWarning
+			result.Message = err.Error()
+			result.Fix = fmt.Sprintf("Install Node.js %s or newer to build the Administration and Storefront", minVersion)
+
+			return result
+		}
+
+		ok, err := system.IsNodeVersionAtLeast(minVersion)
+		if err != nil || !ok {
+			result.Status = StatusWarning
+			result.Message = fmt.Sprintf("Node.js %s is installed, but at least %s is recommended", installed, minVersion)
+			result.Fix = fmt.Sprintf("Update Node.js to %s or newer", minVersion)
+
+			return result
+		}
+
+		result.Message = fmt.Sprintf("Node.js %s", installed)
+
+		return result
+	}
+}
+
+func ComposerCheck() Check {
+	return func(ctx context.Context) Result {
+		result := Result{Name: "Composer"}
+
+		output, err := commandOutput(ctx, "composer", "--version", "--no-ansi")
+		if err != nil {
+			result.Status = StatusError
+			result.Message = err.Error()
+			result.Fix = "Install Composer, see https://getcomposer.org/download/"
+
+			return result
+		}
+
+		result.Message = strings.SplitN(output, "\n", 2)[0]
+
+		return result
+	}
+}
+
+func DockerCheck() Check {
+	return func(ctx context.Context) Result {
+		result := Result{Name: "Docker"}
+
+		if _, err := exec.LookPath("docker"); err != nil {
+			result.Status = StatusWarning
+			result.Message = "Docker is not installed"
+			result.Fix = "Install Docker to run Shopware locally in containers, see https://docs.docker.com/get-docker/"
+
+			return result
+		}
+
+		output, err := commandOutput(ctx, "docker", "info", "--format", "{{.ServerVersion}}")
+		if err != nil {
+			result.Status = StatusWarning
+			result.Message = "Docker is installed, but the daemon is not reachable"
+			result.Fix = "Start Docker or check that your user is allowed to access the Docker socket"
+
+			return result
+		}
+
+		result.Message = fmt.Sprintf("Docker %s", output)
+
+		return result
+	}
+}
+
+// CacheDirCheck makes sure the cache directory can be created and written
+func CacheDirCheck(dir string) Check {
+	return func(_ context.Context) Result {
+		result := Result{Name: "Cache directory"}
+
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return cacheDirError(result, dir, err)
+		}
+
+		probe, err := os.CreateTemp(dir, "doctor-*")
+		if err != nil {
+			return cacheDirError(result, dir, err)
+		}
+
+		_ = probe.Close()
+
+		if err := os.Remove(probe.Name()); err != nil {
+			return cacheDirError(result, dir, err)
+		}
+
+		result.Message = fmt.Sprintf("%s is writable", dir)
+
+		return result
+	}
+}
+
+func cacheDirError(result Result, dir string, err error) Result {
+	result.Status = StatusError
+	result.Message = err.Error()
+	result.Fix = fmt.Sprintf("Make sure your user owns %s or set XDG_CACHE_HOME to a writable directory", filepath.Clean(dir))
+
+	return result
+}
+
+// ReachabilityCheck checks that the url answers. Every HTTP response counts, only network errors fail.
+func ReachabilityCheck(name, url string, client *http.Client) Check {
+	return func(ctx context.Context) Result {
+		result := Result{Name: name}
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		r, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			result.Status = StatusError
+			result.Message = err.Error()
+
+			return result
+		}
+
+		resp, err := client.Do(r)
+		if err != nil {
+			result.Status = StatusError
+			result.Message = fmt.Sprintf("%s is not reachable: %s", url, err.Error())
+			result.Fix = "Check your network connection, proxy settings (HTTPS_PROXY) and firewall"
+
+			return result
+		}
+
+		_ = resp.Body.Close()
+
+		result.Message = fmt.Sprintf("%s is reachable", url)
+
+		return result
+	}
+}
+
+// CredentialsCheck validates the stored credentials with the given login function
+func CredentialsCheck(name string, configured bool, login func(ctx context.Context) error, fix string) Check {
+	return func(ctx context.Context) Result {
+		result := Result{Name: name}
+
+		if !configured {
+			result.Status = StatusWarning
+			result.Message = "No credentials configured"
+			result.Fix = fix
+
+			return result
+		}
+
+		if err := login(ctx); err != nil {
+			result.Status = StatusError
+			result.Message = fmt.Sprintf("The credentials are not valid: %s", err.Error())
+			result.Fix = fix
+
+			return result
+		}
+
+		result.Message = "Credentials are valid"
+
+		return result
+	}
+}
+
+func commandOutput(ctx context.Context, name string, args ...string) (string, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return "", fmt.Errorf("%s is not installed", name)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, name, args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("%s %s failed: %w", name, strings.Join(args, " "), err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
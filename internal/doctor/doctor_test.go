@@ -0,0 +1,73 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunKeepsOrder(t *testing.T) {
+	checks := []Check{
+		func(_ context.Context) Result { return Result{Name: "first"} },
+		func(_ context.Context) Result { return Result{Name: "second", Status: StatusError} },
+	}
+
+	results := Run(t.Context(), checks)
+
+	assert.Equal(t, "first", results[0].Name)
+	assert.Equal(t, "second", results[1].Name)
+	assert.Equal(t, StatusError, results[1].Status)
+}
+
+func TestCacheDirCheck(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "shopware-cli")
+
+	result := CacheDirCheck(dir)(t.Context())
+	assert.Equal(t, StatusOK, result.Status)
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	file := filepath.Join(t.TempDir(), "file")
+	assert.NoError(t, os.WriteFile(file, []byte{}, 0o644))
+
+	result = CacheDirCheck(filepath.Join(file, "cache"))(t.Context())
+	assert.Equal(t, StatusError, result.Status)
+	assert.NotEmpty(t, result.Fix)
+}
+
+func TestReachabilityCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	result := ReachabilityCheck("API", server.URL, server.Client())(t.Context())
+	assert.Equal(t, StatusOK, result.Status)
+
+	server.Close()
+
+	result = ReachabilityCheck("API", server.URL, server.Client())(t.Context())
+	assert.Equal(t, StatusError, result.Status)
+}
+
+func TestCredentialsCheck(t *testing.T) {
+	login := func(_ context.Context) error { return errors.New("invalid password") }
+
+	result := CredentialsCheck("Account", false, login, "login")(t.Context())
+	assert.Equal(t, StatusWarning, result.Status)
+
+	result = CredentialsCheck("Account", true, login, "login")(t.Context())
+	assert.Equal(t, StatusError, result.Status)
+	assert.Contains(t, result.Message, "invalid password")
+
+	result = CredentialsCheck("Account", true, func(_ context.Context) error { return nil }, "login")(t.Context())
+	assert.Equal(t, StatusOK, result.Status)
+}
@@ -0,0 +1,35 @@
+package doctor
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/shopware/shopware-cli/internal/color"
+)
+
+// Print writes the results as checklist with the fixes of failed checks and returns the amount of errors
+func Print(w io.Writer, results []Result) int {
+	problems := 0
+
+	for _, result := range results {
+		var status string
+
+		switch result.Status {
+		case StatusOK:
+			status = color.GreenText.Render("✓")
+		case StatusWarning:
+			status = color.YellowText.Render("!")
+		case StatusError:
+			status = color.RedText.Render("✗")
+			problems++
+		}
+
+		_, _ = fmt.Fprintf(w, "%s %s: %s\n", status, result.Name, result.Message)
+
+		if result.Fix != "" && result.Status != StatusOK {
+			_, _ = fmt.Fprintf(w, "    Fix: %s\n", result.Fix)
+		}
+	}
+
+	return problems
+}
@@ -186,6 +186,21 @@ func GetPublicVCSURL(ctx context.Context, repo string) (string, error) {
 	return "", fmt.Errorf("unsupported vcs provider")
 }
 
+// CommitAndTag commits the files with the message and creates an annotated tag on the new commit
+func CommitAndTag(ctx context.Context, repo string, files []string, message, tag string) error {
+	if _, err := runGit(ctx, repo, append([]string{"add", "--"}, files...)...); err != nil {
+		return err
+	}
+
+	if _, err := runGit(ctx, repo, append([]string{"commit", "-m", message, "--"}, files...)...); err != nil {
+		return err
+	}
+
+	_, err := runGit(ctx, repo, "tag", "-a", tag, "-m", message)
+
+	return err
+}
+
 func unshallowRepository(ctx context.Context, repo string) error {
 	if _, err := os.Stat(path.Join(repo, ".git", "shallow")); os.IsNotExist(err) {
 		return nil
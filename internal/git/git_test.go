@@ -112,3 +112,23 @@ func prepareRepository(t *testing.T, tmpDir string) {
 	runCommand(t, tmpDir, "config", "user.name", "test")
 	runCommand(t, tmpDir, "config", "user.email", "test@test.de")
 }
+
+func TestCommitAndTag(t *testing.T) {
+	tmpDir := t.TempDir()
+	prepareRepository(t, tmpDir)
+	_ = os.WriteFile(filepath.Join(tmpDir, "a"), []byte(""), os.ModePerm)
+	runCommand(t, tmpDir, "add", "a")
+	runCommand(t, tmpDir, "commit", "-m", "initial commit", "--no-verify", "--no-gpg-sign")
+
+	_ = os.WriteFile(filepath.Join(tmpDir, "a"), []byte("1.1.0"), os.ModePerm)
+
+	assert.NoError(t, CommitAndTag(t.Context(), tmpDir, []string{filepath.Join(tmpDir, "a")}, "Release 1.1.0", "1.1.0"))
+
+	tag, err := runGit(t.Context(), tmpDir, "describe", "--tags", "--exact-match")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.1.0\n", tag)
+
+	status, err := runGit(t.Context(), tmpDir, "status", "--porcelain")
+	assert.NoError(t, err)
+	assert.Empty(t, status)
+}
@@ -0,0 +1,77 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Provenance describes where and from which commit an artifact was built
+type Provenance struct {
+	// Source is the URL of the repository
+	Source   string
+	Revision string
+	// BuildURL links to the CI job which built the artifact
+	BuildURL string
+}
+
+// GetProvenance reads the provenance from the environment of GitHub Actions and GitLab CI and falls back to the
+// origin and HEAD of the repository
+func GetProvenance(ctx context.Context, repo string) Provenance {
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		server := os.Getenv("GITHUB_SERVER_URL")
+		repository := os.Getenv("GITHUB_REPOSITORY")
+
+		return Provenance{
+			Source:   fmt.Sprintf("%s/%s", server, repository),
+			Revision: os.Getenv("GITHUB_SHA"),
+			BuildURL: fmt.Sprintf("%s/%s/actions/runs/%s", server, repository, os.Getenv("GITHUB_RUN_ID")),
+		}
+	}
+
+	if os.Getenv("GITLAB_CI") == "true" {
+		return Provenance{
+			Source:   os.Getenv("CI_PROJECT_URL"),
+			Revision: os.Getenv("CI_COMMIT_SHA"),
+			BuildURL: os.Getenv("CI_JOB_URL"),
+		}
+	}
+
+	provenance := Provenance{}
+
+	if origin, err := runGit(ctx, repo, "config", "--get", "remote.origin.url"); err == nil {
+		provenance.Source = normalizeRemoteURL(strings.TrimSpace(origin))
+	}
+
+	if revision, err := runGit(ctx, repo, "rev-parse", "HEAD"); err == nil {
+		provenance.Revision = strings.TrimSpace(revision)
+	}
+
+	return provenance
+}
+
+// normalizeRemoteURL turns SSH remotes like git@github.com:acme/plugin.git into browsable HTTPS URLs and removes credentials
+func normalizeRemoteURL(remote string) string {
+	remote = strings.TrimSuffix(remote, ".git")
+
+	if user, rest, ok := strings.Cut(remote, "@"); ok && !strings.Contains(user, "://") {
+		host, path, _ := strings.Cut(rest, ":")
+
+		return fmt.Sprintf("https://%s/%s", host, path)
+	}
+
+	if scheme, rest, ok := strings.Cut(remote, "://"); ok {
+		if _, host, hasCredentials := strings.Cut(rest, "@"); hasCredentials {
+			rest = host
+		}
+
+		if scheme == "ssh" {
+			scheme = "https"
+		}
+
+		return scheme + "://" + rest
+	}
+
+	return remote
+}
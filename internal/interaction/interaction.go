@@ -0,0 +1,96 @@
+// Package interaction decides whether commands may prompt the user.
+//
+// Commands must not prompt when --non-interactive or --yes is passed, SHOPWARE_CLI_NON_INTERACTIVE
+// or CI is set, or stdin is not a terminal. Confirmations are accepted with --yes, every other
+// prompt has to fail fast and tell the user which flag or argument provides the value instead.
+package interaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/huh"
+	"github.com/mattn/go-isatty"
+)
+
+const (
+	NonInteractiveFlag = "non-interactive"
+	YesFlag            = "yes"
+)
+
+// ErrInputRequired is returned when a command needs input but must not prompt
+var ErrInputRequired = errors.New("input required in non-interactive mode")
+
+type Mode struct {
+	// Never prompt, fail instead
+	NonInteractive bool
+	// Accept all confirmations, implies NonInteractive
+	AssumeYes bool
+}
+
+type modeKey struct{}
+
+func WithMode(ctx context.Context, mode Mode) context.Context {
+	return context.WithValue(ctx, modeKey{}, mode)
+}
+
+func modeFromContext(ctx context.Context) Mode {
+	mode, _ := ctx.Value(modeKey{}).(Mode)
+
+	return mode
+}
+
+// AssumeYes reports whether confirmations are accepted without asking
+func AssumeYes(ctx context.Context) bool {
+	return modeFromContext(ctx).AssumeYes
+}
+
+// IsInteractive reports whether the user can be prompted
+func IsInteractive(ctx context.Context) bool {
+	mode := modeFromContext(ctx)
+
+	if mode.NonInteractive || mode.AssumeYes {
+		return false
+	}
+
+	if os.Getenv("SHOPWARE_CLI_NON_INTERACTIVE") != "" || os.Getenv("CI") != "" {
+		return false
+	}
+
+	return isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd())
+}
+
+// RequireInput returns an error explaining how to pass the value, when the user cannot be prompted for it
+func RequireInput(ctx context.Context, what, hint string) error {
+	if IsInteractive(ctx) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: cannot ask for %s, %s", ErrInputRequired, what, hint)
+}
+
+// Confirm asks the user a yes/no question. With --yes the question is accepted without asking,
+// in non-interactive mode an error is returned.
+func Confirm(ctx context.Context, title, description string) (bool, error) {
+	if AssumeYes(ctx) {
+		return true, nil
+	}
+
+	if err := RequireInput(ctx, fmt.Sprintf("confirmation %q", title), "pass --yes to confirm"); err != nil {
+		return false, err
+	}
+
+	var confirmed bool
+
+	if err := huh.NewConfirm().
+		Title(title).
+		Description(description).
+		Value(&confirmed).
+		Run(); err != nil {
+		return false, err
+	}
+
+	return confirmed, nil
+}
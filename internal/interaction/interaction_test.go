@@ -0,0 +1,36 @@
+package interaction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssumeYesConfirms(t *testing.T) {
+	ctx := WithMode(t.Context(), Mode{AssumeYes: true})
+
+	assert.False(t, IsInteractive(ctx))
+
+	confirmed, err := Confirm(ctx, "Delete everything?", "")
+	assert.NoError(t, err)
+	assert.True(t, confirmed)
+}
+
+func TestNonInteractiveFailsFast(t *testing.T) {
+	ctx := WithMode(t.Context(), Mode{NonInteractive: true})
+
+	confirmed, err := Confirm(ctx, "Delete everything?", "")
+	assert.ErrorIs(t, err, ErrInputRequired)
+	assert.ErrorContains(t, err, "pass --yes to confirm")
+	assert.False(t, confirmed)
+
+	err = RequireInput(ctx, "the version", "pass it as second argument")
+	assert.ErrorIs(t, err, ErrInputRequired)
+	assert.ErrorContains(t, err, "cannot ask for the version, pass it as second argument")
+}
+
+func TestCIEnvironmentIsNotInteractive(t *testing.T) {
+	t.Setenv("CI", "true")
+
+	assert.False(t, IsInteractive(t.Context()))
+}
@@ -0,0 +1,171 @@
+package metrics
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// Names of the emitted metrics, the sinks prefix them with shopware_cli
+const (
+	BuildDuration      = "build_duration_seconds"
+	ZipSize            = "zip_size_bytes"
+	ValidationFindings = "validation_findings"
+	UploadDuration     = "upload_duration_seconds"
+	ReviewWaitDuration = "review_wait_seconds"
+)
+
+const prefix = "shopware_cli"
+
+// Labels tag a metric, like the extension name and version
+type Labels map[string]string
+
+// Sink sends metrics to a monitoring system
+type Sink interface {
+	Send(ctx context.Context, name string, value float64, labels Labels) error
+}
+
+// SinksFromEnv returns the sinks configured with SHOPWARE_CLI_METRICS_PUSHGATEWAY (URL of a Prometheus Pushgateway)
+// and SHOPWARE_CLI_METRICS_STATSD (host:port of a StatsD server), metrics are disabled without them
+func SinksFromEnv() []Sink {
+	var sinks []Sink
+
+	if pushgateway := os.Getenv("SHOPWARE_CLI_METRICS_PUSHGATEWAY"); pushgateway != "" {
+		sinks = append(sinks, Pushgateway{URL: pushgateway})
+	}
+
+	if statsd := os.Getenv("SHOPWARE_CLI_METRICS_STATSD"); statsd != "" {
+		sinks = append(sinks, StatsD{Address: statsd})
+	}
+
+	return sinks
+}
+
+// Emit sends the metric to the configured sinks. Metrics are optional, so a failing sink is only logged and does not fail the pipeline.
+func Emit(ctx context.Context, name string, value float64, labels Labels) {
+	for _, sink := range SinksFromEnv() {
+		if err := sink.Send(ctx, name, value, labels); err != nil {
+			logging.FromContext(ctx).Warnf("Cannot send metric %s: %v", name, err)
+		}
+	}
+}
+
+// EmitDuration sends the time since start in seconds
+func EmitDuration(ctx context.Context, name string, start time.Time, labels Labels) {
+	Emit(ctx, name, time.Since(start).Seconds(), labels)
+}
+
+// Pushgateway pushes the metrics as gauges to a Prometheus Pushgateway, the labels are the grouping key
+type Pushgateway struct {
+	URL string
+}
+
+func (p Pushgateway) Send(ctx context.Context, name string, value float64, labels Labels) error {
+	metric := prefix + "_" + name
+	body := fmt.Sprintf("# TYPE %s gauge\n%s %s\n", metric, metric, strconv.FormatFloat(value, 'f', -1, 64))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.groupingURL(labels), strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logging.FromContext(ctx).Errorf("Cannot close response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("pushgateway responded with %s", resp.Status)
+	}
+
+	return nil
+}
+
+// groupingURL returns the URL of the group, values which cannot be used in a path segment are base64 encoded
+func (p Pushgateway) groupingURL(labels Labels) string {
+	var builder strings.Builder
+
+	builder.WriteString(strings.TrimSuffix(p.URL, "/"))
+	builder.WriteString("/metrics/job/" + prefix)
+
+	for _, key := range sortedKeys(labels) {
+		value := labels[key]
+
+		if value == "" || strings.Contains(value, "/") {
+			builder.WriteString(fmt.Sprintf("/%s@base64/%s", key, base64.RawURLEncoding.EncodeToString([]byte(value))))
+			continue
+		}
+
+		builder.WriteString(fmt.Sprintf("/%s/%s", key, url.PathEscape(value)))
+	}
+
+	return builder.String()
+}
+
+// StatsD sends the metrics as gauges over UDP, the labels are sent as DogStatsD tags
+type StatsD struct {
+	Address string
+}
+
+func (s StatsD) Send(ctx context.Context, name string, value float64, labels Labels) error {
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, "udp", s.Address)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := conn.Close(); err != nil {
+			logging.FromContext(ctx).Errorf("Cannot close StatsD connection: %v", err)
+		}
+	}()
+
+	_, err = conn.Write([]byte(formatStatsD(name, value, labels)))
+
+	return err
+}
+
+func formatStatsD(name string, value float64, labels Labels) string {
+	line := fmt.Sprintf("%s.%s:%s|g", prefix, name, strconv.FormatFloat(value, 'f', -1, 64))
+
+	if len(labels) == 0 {
+		return line
+	}
+
+	tags := make([]string, 0, len(labels))
+	for _, key := range sortedKeys(labels) {
+		tags = append(tags, key+":"+labels[key])
+	}
+
+	return line + "|#" + strings.Join(tags, ",")
+}
+
+func sortedKeys(labels Labels) []string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSinksFromEnv(t *testing.T) {
+	t.Setenv("SHOPWARE_CLI_METRICS_PUSHGATEWAY", "")
+	t.Setenv("SHOPWARE_CLI_METRICS_STATSD", "")
+	assert.Empty(t, SinksFromEnv())
+
+	t.Setenv("SHOPWARE_CLI_METRICS_PUSHGATEWAY", "http://pushgateway:9091")
+	t.Setenv("SHOPWARE_CLI_METRICS_STATSD", "statsd:8125")
+	assert.Equal(t, []Sink{Pushgateway{URL: "http://pushgateway:9091"}, StatsD{Address: "statsd:8125"}}, SinksFromEnv())
+}
+
+func TestPushgatewaySend(t *testing.T) {
+	var path, body string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+
+		content, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		path = r.URL.EscapedPath()
+		body = string(content)
+	}))
+	defer server.Close()
+
+	err := Pushgateway{URL: server.URL + "/"}.Send(t.Context(), ZipSize, 1024, Labels{"version": "1.0.0", "extension": "FroshTools", "branch": "feature/x"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/metrics/job/shopware_cli/branch@base64/ZmVhdHVyZS94/extension/FroshTools/version/1.0.0", path)
+	assert.Equal(t, "# TYPE shopware_cli_zip_size_bytes gauge\nshopware_cli_zip_size_bytes 1024\n", body)
+}
+
+func TestPushgatewaySendFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	err := Pushgateway{URL: server.URL}.Send(t.Context(), ZipSize, 1024, nil)
+	assert.ErrorContains(t, err, "400 Bad Request")
+}
+
+func TestStatsDSend(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	require.NoError(t, StatsD{Address: conn.LocalAddr().String()}.Send(t.Context(), BuildDuration, 12.5, Labels{"extension": "FroshTools", "version": "1.0.0"}))
+
+	buf := make([]byte, 1024)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, "shopware_cli.build_duration_seconds:12.5|g|#extension:FroshTools,version:1.0.0", string(buf[:n]))
+	assert.Equal(t, "shopware_cli.validation_findings:3|g", formatStatsD(ValidationFindings, 3, nil))
+}
@@ -0,0 +1,458 @@
+package nodejs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/system"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// defaultMirror can be replaced with SHOPWARE_CLI_NODE_MIRROR, e.g. for an internal mirror of nodejs.org
+const defaultMirror = "https://nodejs.org/dist"
+
+type release struct {
+	Version string   `json:"version"`
+	Files   []string `json:"files"`
+	// LTS is the codename of the LTS line or false
+	LTS any `json:"lts"`
+}
+
+func mirror() string {
+	if m := os.Getenv("SHOPWARE_CLI_NODE_MIRROR"); m != "" {
+		return strings.TrimSuffix(m, "/")
+	}
+
+	return defaultMirror
+}
+
+// Ensure returns the bin directory of a Node.js satisfying the requirement. When the installed node satisfies it,
+// the returned directory is empty. Otherwise a matching toolchain of the cache directory is used, only without one
+// the newest matching release is downloaded.
+func Ensure(ctx context.Context, requirement Requirement) (string, error) {
+	if installed, err := system.GetInstalledNodeVersion(); err == nil {
+		ok, err := requirement.Check(installed)
+		if err != nil {
+			return "", err
+		}
+
+		if ok {
+			return "", nil
+		}
+
+		logging.FromContext(ctx).Infof("Installed Node.js %s does not satisfy %s", installed, requirement)
+	}
+
+	cacheDir := filepath.Join(system.GetShopwareCliCacheDir(), "node")
+
+	nodeVersion, err := cachedVersion(cacheDir, requirement)
+	if err != nil {
+		return "", err
+	}
+
+	if nodeVersion == "" {
+		platform, archiveExt, err := currentPlatform()
+		if err != nil {
+			return "", err
+		}
+
+		if nodeVersion, err = resolveVersion(ctx, requirement, platform); err != nil {
+			return "", err
+		}
+
+		logging.FromContext(ctx).Infof("Downloading Node.js %s", nodeVersion)
+
+		if err := download(ctx, nodeVersion, fmt.Sprintf("node-%s-%s.%s", nodeVersion, platform, archiveExt), filepath.Join(cacheDir, nodeVersion)); err != nil {
+			return "", fmt.Errorf("download node.js %s: %w", nodeVersion, err)
+		}
+	}
+
+	root := filepath.Join(cacheDir, nodeVersion)
+
+	if runtime.GOOS == "windows" {
+		return root, nil
+	}
+
+	return filepath.Join(root, "bin"), nil
+}
+
+// cachedVersion returns the newest toolchain of the cache directory satisfying the requirement, empty when there is none
+func cachedVersion(cacheDir string, requirement Requirement) (string, error) {
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	var newest *version.Version
+
+	for _, entry := range entries {
+		// Interrupted downloads leave .extract-* folders behind
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), "v") {
+			continue
+		}
+
+		v, err := version.NewVersion(strings.TrimPrefix(entry.Name(), "v"))
+		if err != nil {
+			continue
+		}
+
+		ok, err := requirement.Check(entry.Name())
+		if err != nil {
+			return "", err
+		}
+
+		if ok && (newest == nil || v.GreaterThan(newest)) {
+			newest = v
+		}
+	}
+
+	if newest == nil {
+		return "", nil
+	}
+
+	return "v" + newest.String(), nil
+}
+
+// Activate puts the bin directory first into PATH, so node and npm of all following commands are taken from it
+func Activate(binDir string) error {
+	return os.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// currentPlatform returns the platform name used in the file names of the Node.js releases and the archive extension
+func currentPlatform() (string, string, error) {
+	arch := map[string]string{"amd64": "x64", "arm64": "arm64"}[runtime.GOARCH]
+	if arch == "" {
+		return "", "", fmt.Errorf("downloading node.js is not supported on %s", runtime.GOARCH)
+	}
+
+	switch runtime.GOOS {
+	case "linux":
+		return "linux-" + arch, "tar.gz", nil
+	case "darwin":
+		return "darwin-" + arch, "tar.gz", nil
+	case "windows":
+		return "win-" + arch, "zip", nil
+	}
+
+	return "", "", fmt.Errorf("downloading node.js is not supported on %s", runtime.GOOS)
+}
+
+// resolveVersion picks the newest release matching the requirement, LTS releases are preferred.
+// The index lists the newest releases first.
+func resolveVersion(ctx context.Context, requirement Requirement, platform string) (string, error) {
+	constraint, err := version.NewConstraint(requirement.Constraint)
+	if err != nil {
+		return "", fmt.Errorf("invalid node version constraint %q: %w", requirement.Constraint, err)
+	}
+
+	body, err := fetch(ctx, mirror()+"/index.json")
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = body.Close()
+	}()
+
+	var releases []release
+
+	if err := json.NewDecoder(body).Decode(&releases); err != nil {
+		return "", fmt.Errorf("cannot parse node.js releases: %w", err)
+	}
+
+	// The files of the index are named like osx-arm64-tar instead of darwin-arm64
+	indexPlatform := strings.Replace(platform, "darwin-", "osx-", 1)
+
+	var newest *version.Version
+
+	for _, r := range releases {
+		v, err := version.NewVersion(strings.TrimPrefix(r.Version, "v"))
+		if err != nil || !constraint.Check(v) || !r.hasPlatform(indexPlatform) {
+			continue
+		}
+
+		if lts, ok := r.LTS.(string); ok && lts != "" {
+			return r.Version, nil
+		}
+
+		if newest == nil || v.GreaterThan(newest) {
+			newest = v
+		}
+	}
+
+	if newest == nil {
+		return "", fmt.Errorf("no node.js release for %s matches %s", platform, requirement)
+	}
+
+	return "v" + newest.String(), nil
+}
+
+func (r release) hasPlatform(platform string) bool {
+	for _, file := range r.Files {
+		if file == platform || strings.HasPrefix(file, platform+"-") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func download(ctx context.Context, nodeVersion, fileName, target string) error {
+	expected, err := fetchChecksum(ctx, nodeVersion, fileName)
+	if err != nil {
+		return err
+	}
+
+	body, err := fetch(ctx, fmt.Sprintf("%s/%s/%s", mirror(), nodeVersion, fileName))
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = body.Close()
+	}()
+
+	archive, err := os.CreateTemp("", "node-*")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = archive.Close()
+		_ = os.Remove(archive.Name())
+	}()
+
+	hash := sha256.New()
+
+	if _, err := io.Copy(io.MultiWriter(archive, hash), body); err != nil {
+		return err
+	}
+
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != expected {
+		return fmt.Errorf("checksum mismatch of %s, expected %s got %s", fileName, expected, actual)
+	}
+
+	// Extract next to the target and rename it, so an interrupted download does not leave a broken toolchain
+	tmpDir, err := os.MkdirTemp(filepath.Dir(target), ".extract-*")
+	if err != nil && os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+			return err
+		}
+
+		tmpDir, err = os.MkdirTemp(filepath.Dir(target), ".extract-*")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+
+	if strings.HasSuffix(fileName, ".zip") {
+		err = extractZip(archive.Name(), tmpDir)
+	} else {
+		err = extractTarGz(archive.Name(), tmpDir)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// The archives contain a single folder named like the archive
+	extracted := filepath.Join(tmpDir, strings.TrimSuffix(strings.TrimSuffix(fileName, ".zip"), ".tar.gz"))
+
+	return os.Rename(extracted, target)
+}
+
+func fetchChecksum(ctx context.Context, nodeVersion, fileName string) (string, error) {
+	body, err := fetch(ctx, fmt.Sprintf("%s/%s/SHASUMS256.txt", mirror(), nodeVersion))
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = body.Close()
+	}()
+
+	scanner := bufio.NewScanner(body)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+
+		if len(fields) == 2 && fields[1] == fileName {
+			return fields[0], nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return "", fmt.Errorf("no checksum for %s", fileName)
+}
+
+func fetch(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+
+		return nil, fmt.Errorf("cannot download %s: %s", url, resp.Status)
+	}
+
+	return resp.Body, nil
+}
+
+// safeJoin rejects archive entries pointing outside of the target folder
+func safeJoin(target, name string) (string, error) {
+	path := filepath.Join(target, filepath.FromSlash(name))
+
+	if !strings.HasPrefix(path, filepath.Clean(target)+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid path in archive: %s", name)
+	}
+
+	return path, nil
+}
+
+func extractTarGz(file, target string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(gz)
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		path, err := safeJoin(target, header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, os.ModePerm); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			// npm and npx are symlinks into lib/node_modules
+			if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+				return err
+			}
+
+			if err := os.Symlink(header.Linkname, path); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(path, tr, header.FileInfo().Mode()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func extractZip(file, target string) error {
+	r, err := zip.OpenReader(file)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	for _, f := range r.File {
+		path, err := safeJoin(target, f.Name)
+		if err != nil {
+			return err
+		}
+
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(path, os.ModePerm); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+
+		err = writeFile(path, rc, f.Mode())
+		_ = rc.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeFile(path string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+
+		return err
+	}
+
+	return f.Close()
+}
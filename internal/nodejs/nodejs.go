@@ -0,0 +1,171 @@
+package nodejs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/shopwareversion"
+)
+
+// Requirement is the Node.js version needed by an asset build
+type Requirement struct {
+	// Constraint like ^20.0.0, >=18 <21 or 20.*
+	Constraint string
+	// Source is the file the requirement was read from, empty for the default of the Shopware version
+	Source string
+}
+
+func (r Requirement) String() string {
+	if r.Source == "" {
+		return r.Constraint
+	}
+
+	return fmt.Sprintf("%s (%s)", r.Constraint, r.Source)
+}
+
+// Check reports whether the Node.js version like v20.11.0 or 20.11.0 satisfies the requirement
+func (r Requirement) Check(nodeVersion string) (bool, error) {
+	constraint, err := version.NewConstraint(r.Constraint)
+	if err != nil {
+		return false, fmt.Errorf("invalid node version constraint %q: %w", r.Constraint, err)
+	}
+
+	v, err := version.NewVersion(strings.TrimPrefix(nodeVersion, "v"))
+	if err != nil {
+		return false, err
+	}
+
+	return constraint.Check(v), nil
+}
+
+// ltsCodenames maps the names of the LTS lines usable in .nvmrc like lts/iron to their major version
+var ltsCodenames = map[string]string{
+	"argon":    "4",
+	"boron":    "6",
+	"carbon":   "8",
+	"dubnium":  "10",
+	"erbium":   "12",
+	"fermium":  "14",
+	"gallium":  "16",
+	"hydrogen": "18",
+	"iron":     "20",
+	"jod":      "22",
+}
+
+// versionFiles are read before the engines of package.json, like nvm and fnm do
+var versionFiles = []string{".nvmrc", ".node-version"}
+
+var partialVersionRegExp = regexp.MustCompile(`^\d+(\.\d+)?$`)
+
+// DetectRequirement returns the first requirement found in the folders, nil when none of them defines one
+func DetectRequirement(dirs ...string) (*Requirement, error) {
+	for _, dir := range dirs {
+		for _, file := range versionFiles {
+			content, err := os.ReadFile(filepath.Join(dir, file))
+			if err != nil {
+				continue
+			}
+
+			constraint := parseVersionFile(string(content))
+			if constraint == "" {
+				continue
+			}
+
+			return &Requirement{Constraint: constraint, Source: filepath.Join(dir, file)}, nil
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, "package.json"))
+		if err != nil {
+			continue
+		}
+
+		var packageJson struct {
+			Engines struct {
+				Node string `json:"node"`
+			} `json:"engines"`
+		}
+
+		if err := json.Unmarshal(content, &packageJson); err != nil {
+			return nil, fmt.Errorf("cannot parse %s: %w", filepath.Join(dir, "package.json"), err)
+		}
+
+		if packageJson.Engines.Node != "" {
+			return &Requirement{Constraint: normalizeRange(packageJson.Engines.Node), Source: filepath.Join(dir, "package.json")}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// parseVersionFile converts the content of a .nvmrc like 20, v20.11.0 or lts/iron into a constraint
+func parseVersionFile(content string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(content), "\n")
+	line = strings.TrimPrefix(strings.TrimSpace(line), "v")
+
+	switch {
+	case line == "":
+		return ""
+	case line == "node" || line == "latest" || line == "current" || line == "lts/*":
+		return "*"
+	case strings.HasPrefix(line, "lts/"):
+		if major, ok := ltsCodenames[strings.ToLower(strings.TrimPrefix(line, "lts/"))]; ok {
+			return major + ".*"
+		}
+
+		return ""
+	case partialVersionRegExp.MatchString(line):
+		return line + ".*"
+	}
+
+	return line
+}
+
+// normalizeRange converts the x wildcards of npm like 20.x into the * of the constraint parser
+func normalizeRange(constraint string) string {
+	fields := strings.Fields(constraint)
+
+	for i, field := range fields {
+		field = strings.ReplaceAll(field, ".x", ".*")
+		field = strings.ReplaceAll(field, ".X", ".*")
+
+		if field == "x" || field == "X" {
+			field = "*"
+		}
+
+		fields[i] = field
+	}
+
+	return strings.Join(fields, " ")
+}
+
+// ShopwareRequirement is the lowest Node.js version the build tooling of the Shopware version like 6.6.0.0 supports.
+// Versions newer than all lines like dev versions use the newest line, lines without a known Node.js version accept any.
+func ShopwareRequirement(lines []shopwareversion.Line, shopwareVersion string) Requirement {
+	var newest string
+
+	for _, line := range lines {
+		if strings.HasPrefix(shopwareVersion, line.Version+".") {
+			if line.Node == "" {
+				return Requirement{Constraint: "*"}
+			}
+
+			return Requirement{Constraint: ">=" + line.Node}
+		}
+
+		if line.Node != "" {
+			newest = line.Node
+		}
+	}
+
+	if newest == "" {
+		return Requirement{Constraint: "*"}
+	}
+
+	return Requirement{Constraint: ">=" + newest}
+}
@@ -0,0 +1,241 @@
+package nodejs
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/shopwareversion"
+)
+
+func TestParseVersionFile(t *testing.T) {
+	cases := map[string]string{
+		"20\n":         "20.*",
+		"v20.11":       "20.11.*",
+		"v20.11.1":     "20.11.1",
+		"lts/iron":     "20.*",
+		"lts/*":        "*",
+		"node":         "*",
+		"lts/unknown":  "",
+		"   \n":        "",
+		">=18 <21\n20": ">=18 <21",
+	}
+
+	for content, expected := range cases {
+		assert.Equal(t, expected, parseVersionFile(content), content)
+	}
+}
+
+func TestNormalizeRange(t *testing.T) {
+	assert.Equal(t, "20.*", normalizeRange("20.x"))
+	assert.Equal(t, ">=18.* <21", normalizeRange(">=18.x  <21"))
+	assert.Equal(t, "^20.0.0 || ^22.0.0", normalizeRange("^20.0.0 || ^22.0.0"))
+}
+
+func TestDetectRequirement(t *testing.T) {
+	root := t.TempDir()
+	admin := filepath.Join(root, "admin")
+
+	require.NoError(t, os.MkdirAll(admin, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(admin, "package.json"), []byte(`{"engines": {"node": "20.x"}}`), os.ModePerm))
+
+	requirement, err := DetectRequirement(root, admin)
+	require.NoError(t, err)
+	assert.Equal(t, &Requirement{Constraint: "20.*", Source: filepath.Join(admin, "package.json")}, requirement)
+
+	// A version file takes precedence over package.json
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".nvmrc"), []byte("lts/hydrogen\n"), os.ModePerm))
+
+	requirement, err = DetectRequirement(root, admin)
+	require.NoError(t, err)
+	assert.Equal(t, "18.*", requirement.Constraint)
+
+	requirement, err = DetectRequirement(t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, requirement)
+}
+
+func TestShopwareRequirement(t *testing.T) {
+	lines := []shopwareversion.Line{
+		{Version: "6.4"},
+		{Version: "6.5", Node: "18.0.0"},
+		{Version: "6.6", Node: "20.0.0"},
+	}
+
+	assert.Equal(t, Requirement{Constraint: ">=18.0.0"}, ShopwareRequirement(lines, "6.5.8.0"))
+	assert.Equal(t, Requirement{Constraint: "*"}, ShopwareRequirement(lines, "6.4.20.2"))
+	assert.Equal(t, Requirement{Constraint: ">=20.0.0"}, ShopwareRequirement(lines, "6.7.0.0"))
+	assert.Equal(t, Requirement{Constraint: "*"}, ShopwareRequirement(nil, "6.7.0.0"))
+
+	// The version of the line is a lower bound, newer Node.js versions can build it
+	ok, err := ShopwareRequirement(lines, "6.6.10.0").Check("v24.0.0")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = ShopwareRequirement(lines, "6.6.10.0").Check("18.20.0")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestEnsureDownloadsMatchingRelease(t *testing.T) {
+	platform, archiveExt, err := currentPlatform()
+	if err != nil || archiveExt != "tar.gz" {
+		t.Skip("the fake release is only built for tar.gz platforms")
+	}
+
+	fileName := fmt.Sprintf("node-v4.9.1-%s.tar.gz", platform)
+	archive := fakeNodeArchive(t, "node-v4.9.1-"+platform)
+	checksum := sha256.Sum256(archive)
+	indexPlatform := map[string]string{"darwin-x64": "osx-x64-tar", "darwin-arm64": "osx-arm64-tar"}[platform]
+
+	if indexPlatform == "" {
+		indexPlatform = platform
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/index.json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `[
+			{"version": "v5.0.0", "files": [%[1]q], "lts": false},
+			{"version": "v4.9.2", "files": ["win-x64-zip"], "lts": "Argon"},
+			{"version": "v4.9.1", "files": [%[1]q], "lts": "Argon"},
+			{"version": "v4.9.0", "files": [%[1]q], "lts": "Argon"}
+		]`, indexPlatform)
+	})
+	mux.HandleFunc("/v4.9.1/SHASUMS256.txt", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "%s  %s\n", hex.EncodeToString(checksum[:]), fileName)
+	})
+	mux.HandleFunc("/v4.9.1/"+fileName, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(archive)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	t.Setenv("SHOPWARE_CLI_NODE_MIRROR", server.URL)
+	t.Setenv("XDG_CACHE_HOME", cacheDir)
+	t.Setenv("HOME", cacheDir)
+
+	binDir, err := Ensure(t.Context(), Requirement{Constraint: "4.*"})
+	require.NoError(t, err)
+
+	if runtime.GOOS == "linux" {
+		assert.Equal(t, filepath.Join(cacheDir, "shopware-cli", "node", "v4.9.1", "bin"), binDir)
+	}
+
+	content, err := os.ReadFile(filepath.Join(binDir, "node"))
+	require.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh\n", string(content))
+
+	link, err := os.Readlink(filepath.Join(binDir, "npm"))
+	require.NoError(t, err)
+	assert.Equal(t, "../lib/node_modules/npm/bin/npm-cli.js", link)
+}
+
+func TestEnsureRejectsChecksumMismatch(t *testing.T) {
+	platform, archiveExt, err := currentPlatform()
+	if err != nil || archiveExt != "tar.gz" {
+		t.Skip("the fake release is only built for tar.gz platforms")
+	}
+
+	indexPlatform := map[string]string{"darwin-x64": "osx-x64-tar", "darwin-arm64": "osx-arm64-tar"}[platform]
+	if indexPlatform == "" {
+		indexPlatform = platform
+	}
+
+	fileName := fmt.Sprintf("node-v4.9.1-%s.tar.gz", platform)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/index.json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `[{"version": "v4.9.1", "files": [%q], "lts": "Argon"}]`, indexPlatform)
+	})
+	mux.HandleFunc("/v4.9.1/SHASUMS256.txt", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "%064d  %s\n", 0, fileName)
+	})
+	mux.HandleFunc("/v4.9.1/"+fileName, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(fakeNodeArchive(t, "node-v4.9.1-"+platform))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	t.Setenv("SHOPWARE_CLI_NODE_MIRROR", server.URL)
+	t.Setenv("XDG_CACHE_HOME", cacheDir)
+	t.Setenv("HOME", cacheDir)
+
+	_, err = Ensure(t.Context(), Requirement{Constraint: "4.*"})
+	assert.ErrorContains(t, err, "checksum mismatch")
+}
+
+func TestEnsureUsesCachedRelease(t *testing.T) {
+	// Any request to the mirror fails the test, the cached toolchain has to be used without the index
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	t.Setenv("SHOPWARE_CLI_NODE_MIRROR", server.URL)
+	t.Setenv("XDG_CACHE_HOME", cacheDir)
+	t.Setenv("HOME", cacheDir)
+
+	nodeDir := filepath.Join(cacheDir, "shopware-cli", "node")
+
+	for _, folder := range []string{"v4.8.0", "v4.9.1", "v5.0.0", ".extract-123"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(nodeDir, folder, "bin"), os.ModePerm))
+	}
+
+	version, err := cachedVersion(nodeDir, Requirement{Constraint: "4.*"})
+	require.NoError(t, err)
+	assert.Equal(t, "v4.9.1", version)
+
+	binDir, err := Ensure(t.Context(), Requirement{Constraint: "4.*"})
+	require.NoError(t, err)
+
+	if runtime.GOOS == "linux" {
+		assert.Equal(t, filepath.Join(nodeDir, "v4.9.1", "bin"), binDir)
+	}
+}
+
+func TestSafeJoin(t *testing.T) {
+	_, err := safeJoin("/tmp/target", "../etc/passwd")
+	assert.Error(t, err)
+
+	path, err := safeJoin("/tmp/target", "node/bin/node")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.FromSlash("/tmp/target/node/bin/node"), path)
+}
+
+func fakeNodeArchive(t *testing.T, folder string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: folder + "/bin/", Typeflag: tar.TypeDir, Mode: 0o755}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: folder + "/bin/node", Typeflag: tar.TypeReg, Mode: 0o755, Size: 10}))
+	_, err := tw.Write([]byte("#!/bin/sh\n"))
+	require.NoError(t, err)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: folder + "/bin/npm", Typeflag: tar.TypeSymlink, Linkname: "../lib/node_modules/npm/bin/npm-cli.js"}))
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	return buf.Bytes()
+}
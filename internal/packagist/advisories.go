@@ -0,0 +1,98 @@
+package packagist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// SecurityAdvisory is a known vulnerability of a package from the advisories database of packagist.org, which composer audit uses as well
+type SecurityAdvisory struct {
+	AdvisoryID  string `json:"advisoryId"`
+	PackageName string `json:"packageName"`
+	Title       string `json:"title"`
+	Link        string `json:"link"`
+	CVE         string `json:"cve"`
+	// AffectedVersions is a composer constraint like >=1.0.0,<1.2.3|>=2.0.0,<2.0.5
+	AffectedVersions string `json:"affectedVersions"`
+	// Severity is low, medium, high, critical or empty when the source does not rate the advisory
+	Severity string `json:"severity"`
+}
+
+// Affects reports whether the version is in the affected versions of the advisory
+func (a SecurityAdvisory) Affects(installed string) bool {
+	constraint, err := version.NewConstraint(a.AffectedVersions)
+	if err != nil {
+		return false
+	}
+
+	v, err := version.NewVersion(strings.TrimPrefix(installed, "v"))
+	if err != nil {
+		return false
+	}
+
+	return constraint.Check(v)
+}
+
+// GetSecurityAdvisories returns the advisories of the given packages by package name
+func GetSecurityAdvisories(ctx context.Context, packages []string) (map[string][]SecurityAdvisory, error) {
+	if len(packages) == 0 {
+		return map[string][]SecurityAdvisory{}, nil
+	}
+
+	form := url.Values{}
+	for _, name := range packages {
+		form.Add("packages[]", name)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://packagist.org/api/security-advisories/", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", "Shopware CLI")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logging.FromContext(ctx).Errorf("Cannot close response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get security advisories: %s", resp.Status)
+	}
+
+	var response struct {
+		Advisories json.RawMessage `json:"advisories"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	advisories := map[string][]SecurityAdvisory{}
+
+	// Without any advisory packagist sends an empty list instead of an object
+	if strings.HasPrefix(strings.TrimSpace(string(response.Advisories)), "[") {
+		return advisories, nil
+	}
+
+	if err := json.Unmarshal(response.Advisories, &advisories); err != nil {
+		return nil, err
+	}
+
+	return advisories, nil
+}
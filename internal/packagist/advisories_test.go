@@ -0,0 +1,65 @@
+package packagist
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecurityAdvisoryAffects(t *testing.T) {
+	advisory := SecurityAdvisory{AffectedVersions: ">=1.0.0,<1.2.3|>=2.0.0,<2.0.5"}
+
+	assert.True(t, advisory.Affects("1.0.0"))
+	assert.True(t, advisory.Affects("v2.0.4"))
+	assert.False(t, advisory.Affects("1.2.3"))
+	assert.False(t, advisory.Affects("2.1.0"))
+	assert.False(t, advisory.Affects("dev-main"))
+}
+
+func TestGetSecurityAdvisories(t *testing.T) {
+	originalClient := http.DefaultClient
+	defer func() {
+		http.DefaultClient = originalClient
+	}()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/security-advisories/", r.URL.Path)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, []string{"guzzlehttp/guzzle", "foo/bar"}, r.PostForm["packages[]"])
+
+		_, _ = w.Write([]byte(`{"advisories": {"guzzlehttp/guzzle": [{"advisoryId": "PKSA-1", "packageName": "guzzlehttp/guzzle", "title": "Cross-domain cookie leakage", "cve": "CVE-2022-29248", "affectedVersions": ">=7,<7.4.3", "severity": "high"}]}}`))
+	}))
+	defer server.Close()
+
+	http.DefaultClient = &http.Client{Transport: &mockTransport{server: server}}
+
+	advisories, err := GetSecurityAdvisories(t.Context(), []string{"guzzlehttp/guzzle", "foo/bar"})
+	require.NoError(t, err)
+
+	assert.Len(t, advisories["guzzlehttp/guzzle"], 1)
+	assert.Equal(t, "CVE-2022-29248", advisories["guzzlehttp/guzzle"][0].CVE)
+	assert.Equal(t, "high", advisories["guzzlehttp/guzzle"][0].Severity)
+	assert.Empty(t, advisories["foo/bar"])
+}
+
+func TestGetSecurityAdvisoriesWithoutAdvisories(t *testing.T) {
+	originalClient := http.DefaultClient
+	defer func() {
+		http.DefaultClient = originalClient
+	}()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"advisories": []}`))
+	}))
+	defer server.Close()
+
+	http.DefaultClient = &http.Client{Transport: &mockTransport{server: server}}
+
+	advisories, err := GetSecurityAdvisories(t.Context(), []string{"foo/bar"})
+	require.NoError(t, err)
+	assert.Empty(t, advisories)
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"maps"
+	"net/http"
 	"os"
+	"strings"
 )
 
 type ComposerAuthHttpBasic struct {
@@ -126,6 +128,42 @@ func (a *ComposerAuth) Json(formatted bool) ([]byte, error) {
 	return json.MarshalIndent(a, "", "  ")
 }
 
+// AuthorizeRequest adds the credentials configured for the host of the request like composer does for downloads
+func (a *ComposerAuth) AuthorizeRequest(r *http.Request) {
+	host := r.URL.Hostname()
+
+	// Composer stores the token of GitHub for github.com, the archives are downloaded from the API
+	if host == "api.github.com" || host == "codeload.github.com" {
+		host = "github.com"
+	}
+
+	if basic, ok := a.HTTPBasicAuth[host]; ok {
+		r.SetBasicAuth(basic.Username, basic.Password)
+	}
+
+	if token, ok := a.BearerAuth[host]; ok {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	if token, ok := a.GithubOAuth[host]; ok {
+		r.Header.Set("Authorization", "token "+token)
+	}
+
+	if token, ok := a.GitlabAuth[host]; ok {
+		r.Header.Set("PRIVATE-TOKEN", token.Token)
+	}
+
+	if token, ok := a.GitlabOAuth[host]; ok {
+		r.Header.Set("Authorization", "Bearer "+token.Token)
+	}
+
+	for _, header := range a.CustomHeaders[host] {
+		if name, value, ok := strings.Cut(header, ":"); ok {
+			r.Header.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+		}
+	}
+}
+
 func fillAuthStruct(auth *ComposerAuth) *ComposerAuth {
 	if auth.BearerAuth == nil {
 		auth.BearerAuth = map[string]string{}
@@ -2,6 +2,7 @@ package packagist
 
 import (
 	"encoding/json"
+	"net/http"
 	"os"
 	"path/filepath"
 	"testing"
@@ -369,3 +370,31 @@ func TestGithubDomainsUnmarshalling(t *testing.T) {
 		assert.JSONEq(t, `{"github-domains": ["github.com", "example.com"]}`, string(jsonData))
 	})
 }
+
+func TestAuthorizeRequest(t *testing.T) {
+	auth := &ComposerAuth{
+		HTTPBasicAuth: map[string]ComposerAuthHttpBasic{"repo.example.org": {Username: "user", Password: "pass"}},
+		GithubOAuth:   map[string]string{"github.com": "gh-token"},
+		GitlabAuth:    map[string]GitlabToken{"gitlab.example.org": {Token: "gl-token"}},
+		CustomHeaders: map[string][]string{"packages.example.org": {"X-Api-Key: key"}},
+	}
+
+	request := func(url string) *http.Request {
+		r, err := http.NewRequest(http.MethodGet, url, http.NoBody)
+		assert.NoError(t, err)
+
+		auth.AuthorizeRequest(r)
+
+		return r
+	}
+
+	user, password, ok := request("https://repo.example.org:8443/dist.zip").BasicAuth()
+	assert.True(t, ok)
+	assert.Equal(t, "user", user)
+	assert.Equal(t, "pass", password)
+
+	assert.Equal(t, "token gh-token", request("https://api.github.com/repos/acme/plugin/zipball/abc").Header.Get("Authorization"))
+	assert.Equal(t, "gl-token", request("https://gitlab.example.org/api/v4/archive.zip").Header.Get("PRIVATE-TOKEN"))
+	assert.Equal(t, "key", request("https://packages.example.org/dist.zip").Header.Get("X-Api-Key"))
+	assert.Empty(t, request("https://other.example.org/dist.zip").Header.Get("Authorization"))
+}
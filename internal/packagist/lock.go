@@ -7,12 +7,61 @@ import (
 )
 
 type ComposerLockPackage struct {
-	Name    string `json:"name"`
-	Version string `json:"version"`
+	Name      string                   `json:"name"`
+	Version   string                   `json:"version"`
+	Dist      *ComposerLockPackageDist `json:"dist,omitempty"`
+	License   []string                 `json:"license,omitempty"`
+	Abandoned *ComposerAbandoned       `json:"abandoned,omitempty"`
+	Require   map[string]string        `json:"require,omitempty"`
+}
+
+// ComposerAbandoned is set when the package is abandoned, composer stores either true or the name of the suggested replacement
+type ComposerAbandoned struct {
+	Abandoned   bool
+	Replacement string
+}
+
+func (a *ComposerAbandoned) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case bool:
+		a.Abandoned = v
+	case string:
+		a.Abandoned = true
+		a.Replacement = v
+	}
+
+	return nil
+}
+
+// IsAbandoned returns true when the package was abandoned at the time it was locked
+func (p ComposerLockPackage) IsAbandoned() bool {
+	return p.Abandoned != nil && p.Abandoned.Abandoned
+}
+
+func (a ComposerAbandoned) MarshalJSON() ([]byte, error) {
+	if a.Replacement != "" {
+		return json.Marshal(a.Replacement)
+	}
+
+	return json.Marshal(a.Abandoned)
+}
+
+type ComposerLockPackageDist struct {
+	Type      string `json:"type"`
+	URL       string `json:"url"`
+	Reference string `json:"reference"`
+	// Shasum is the SHA-1 of the archive, empty for archives of GitHub and GitLab which are created on demand
+	Shasum string `json:"shasum,omitempty"`
 }
 
 type ComposerLock struct {
-	Packages []ComposerLockPackage `json:"packages"`
+	Packages    []ComposerLockPackage `json:"packages"`
+	PackagesDev []ComposerLockPackage `json:"packages-dev"`
 }
 
 func (c *ComposerLock) GetPackage(name string) *ComposerLockPackage {
@@ -51,4 +51,31 @@ func TestReadComposerLock(t *testing.T) {
 		assert.Error(t, err)
 		assert.Nil(t, lock)
 	})
+
+	t.Run("license and abandoned state", func(t *testing.T) {
+		dir := t.TempDir()
+		lockFile := filepath.Join(dir, "composer.lock")
+		content := `{
+			"packages": [
+				{"name": "foo/replaced", "version": "1.0.0", "license": ["MIT"], "abandoned": "bar/new"},
+				{"name": "foo/abandoned", "version": "1.0.0", "abandoned": true},
+				{"name": "foo/maintained", "version": "1.0.0"}
+			],
+			"packages-dev": [
+				{"name": "foo/dev", "version": "2.0.0"}
+			]
+		}`
+		assert.NoError(t, os.WriteFile(lockFile, []byte(content), 0o644))
+
+		lock, err := ReadComposerLock(lockFile)
+		assert.NoError(t, err)
+		assert.Len(t, lock.PackagesDev, 1)
+
+		assert.True(t, lock.Packages[0].IsAbandoned())
+		assert.Equal(t, "bar/new", lock.Packages[0].Abandoned.Replacement)
+		assert.Equal(t, []string{"MIT"}, lock.Packages[0].License)
+		assert.True(t, lock.Packages[1].IsAbandoned())
+		assert.Empty(t, lock.Packages[1].Abandoned.Replacement)
+		assert.False(t, lock.Packages[2].IsAbandoned())
+	})
 }
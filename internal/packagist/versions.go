@@ -0,0 +1,96 @@
+package packagist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// PackagistVersion is a release of a package on packagist.org
+type PackagistVersion struct {
+	Version   string             `json:"version"`
+	Time      string             `json:"time"`
+	License   []string           `json:"license"`
+	Abandoned *ComposerAbandoned `json:"abandoned,omitempty"`
+}
+
+// ErrPackageNotFound is returned when the package is not published on packagist.org
+var ErrPackageNotFound = fmt.Errorf("package not found on packagist.org")
+
+// GetPackagistVersions returns the tagged releases of a package on packagist.org, the newest release first
+func GetPackagistVersions(ctx context.Context, name string) ([]PackagistVersion, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("https://repo.packagist.org/p2/%s.json", name), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", "Shopware CLI")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logging.FromContext(ctx).Errorf("Cannot close response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrPackageNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get versions of %s: %s", name, resp.Status)
+	}
+
+	var response struct {
+		Packages map[string][]PackagistVersion `json:"packages"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	return response.Packages[name], nil
+}
+
+// LatestStableVersion returns the highest version without stability suffix
+func LatestStableVersion(versions []string) *version.Version {
+	var latest *version.Version
+
+	for _, v := range versions {
+		parsed, err := version.NewVersion(strings.TrimPrefix(v, "v"))
+		if err != nil || parsed.IsPrerelease() {
+			continue
+		}
+
+		if latest == nil || parsed.GreaterThan(latest) {
+			latest = parsed
+		}
+	}
+
+	return latest
+}
+
+// MajorVersionLag returns how many major versions the installed version is behind the latest stable version
+func MajorVersionLag(installed string, latest *version.Version) int {
+	current, err := version.NewVersion(strings.TrimPrefix(installed, "v"))
+	if err != nil || latest == nil {
+		return 0
+	}
+
+	lag := latest.Segments()[0] - current.Segments()[0]
+	if lag < 0 {
+		return 0
+	}
+
+	return lag
+}
@@ -0,0 +1,58 @@
+package packagist
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shyim/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestStableVersion(t *testing.T) {
+	latest := LatestStableVersion([]string{"v5.4.0", "v7.1.0-RC1", "v6.4.3", "dev-main", "v6.10.0"})
+
+	require.NotNil(t, latest)
+	assert.Equal(t, "6.10.0", latest.String())
+
+	assert.Nil(t, LatestStableVersion([]string{"dev-main"}))
+}
+
+func TestMajorVersionLag(t *testing.T) {
+	latest := version.Must(version.NewVersion("7.1.0"))
+
+	assert.Equal(t, 2, MajorVersionLag("v5.4.0", latest))
+	assert.Equal(t, 0, MajorVersionLag("7.0.0", latest))
+	assert.Equal(t, 0, MajorVersionLag("8.0.0", latest))
+	assert.Equal(t, 0, MajorVersionLag("dev-main", latest))
+	assert.Equal(t, 0, MajorVersionLag("5.4.0", nil))
+}
+
+func TestGetPackagistVersions(t *testing.T) {
+	originalClient := http.DefaultClient
+	defer func() {
+		http.DefaultClient = originalClient
+	}()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/p2/foo/bar.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"packages": {"foo/bar": [{"version": "v2.0.0", "license": ["MIT"], "abandoned": "foo/baz"}, {"version": "v1.0.0"}]}}`))
+	}))
+	defer server.Close()
+
+	http.DefaultClient = &http.Client{Transport: &mockTransport{server: server}}
+
+	versions, err := GetPackagistVersions(t.Context(), "foo/bar")
+	require.NoError(t, err)
+	assert.Len(t, versions, 2)
+	assert.Equal(t, "v2.0.0", versions[0].Version)
+	assert.Equal(t, "foo/baz", versions[0].Abandoned.Replacement)
+
+	_, err = GetPackagistVersions(t.Context(), "foo/unknown")
+	assert.ErrorIs(t, err, ErrPackageNotFound)
+}
@@ -0,0 +1,47 @@
+package phplint
+
+import (
+	"fmt"
+
+	"github.com/shyim/go-version"
+)
+
+// SupportedVersions are the PHP versions with a wasm binary, ordered ascending
+var SupportedVersions = []string{"7.3", "7.4", "8.1", "8.2", "8.3"}
+
+// releasedVersions are the PHP versions a constraint can require, ordered ascending
+var releasedVersions = []string{"7.0", "7.1", "7.2", "7.3", "7.4", "8.0", "8.1", "8.2", "8.3", "8.4"}
+
+// ResolveVersion returns the lowest supported PHP version which is equal or newer than the given one
+func ResolveVersion(phpVersion string) (string, error) {
+	wanted, err := version.NewVersion(phpVersion)
+	if err != nil {
+		return "", fmt.Errorf("invalid php version %s: %w", phpVersion, err)
+	}
+
+	for _, supported := range SupportedVersions {
+		if !version.Must(version.NewVersion(supported)).LessThan(wanted) {
+			return supported, nil
+		}
+	}
+
+	return "", fmt.Errorf("php version %s is not supported for linting, supported are %v", phpVersion, SupportedVersions)
+}
+
+// MinimumVersion returns the lowest PHP version which is allowed by the given constraint, like the require.php of a composer.json.
+// It is not necessarily supported for linting, see ResolveVersion.
+func MinimumVersion(constraint string) (string, error) {
+	c, err := version.NewConstraint(constraint)
+	if err != nil {
+		return "", fmt.Errorf("invalid php constraint %s: %w", constraint, err)
+	}
+
+	for _, released := range releasedVersions {
+		// Check the first and a late patch release, so constraints like >=8.1.10 match 8.1 too
+		if c.Check(version.Must(version.NewVersion(released+".0"))) || c.Check(version.Must(version.NewVersion(released+".99"))) {
+			return released, nil
+		}
+	}
+
+	return "", fmt.Errorf("no php version matches the constraint %s", constraint)
+}
@@ -0,0 +1,45 @@
+package phplint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveVersion(t *testing.T) {
+	cases := map[string]string{
+		"7.2": "7.3",
+		"7.4": "7.4",
+		"8.0": "8.1",
+		"8.2": "8.2",
+	}
+
+	for phpVersion, expected := range cases {
+		resolved, err := ResolveVersion(phpVersion)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, resolved, phpVersion)
+	}
+
+	_, err := ResolveVersion("9.0")
+	assert.Error(t, err)
+}
+
+func TestMinimumVersion(t *testing.T) {
+	cases := map[string]string{
+		">=8.1":        "8.1",
+		"^8.2":         "8.2",
+		">=8.1.10":     "8.1",
+		"^7.4 || ^8.0": "7.4",
+		"^8.0":         "8.0",
+		">=7.2":        "7.2",
+	}
+
+	for constraint, expected := range cases {
+		minVersion, err := MinimumVersion(constraint)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, minVersion, constraint)
+	}
+
+	_, err := MinimumVersion(">=9.0")
+	assert.Error(t, err)
+}
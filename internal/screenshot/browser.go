@@ -0,0 +1,335 @@
+// Package screenshot captures pages with a headless Chromium controlled over the DevTools protocol
+package screenshot
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	launchTimeout = 30 * time.Second
+	pageTimeout   = 60 * time.Second
+	pollInterval  = 200 * time.Millisecond
+)
+
+// chromiumExecutables are searched in the PATH when CHROME_PATH is not set
+var chromiumExecutables = []string{"chromium", "chromium-browser", "google-chrome", "google-chrome-stable", "/Applications/Google Chrome.app/Contents/MacOS/Google Chrome"}
+
+var devToolsUrlRegExp = regexp.MustCompile(`DevTools listening on (ws://\S+)`)
+
+// FindChromium returns CHROME_PATH or the first Chromium or Chrome installation
+func FindChromium() (string, error) {
+	if executable := os.Getenv("CHROME_PATH"); executable != "" {
+		return executable, nil
+	}
+
+	for _, executable := range chromiumExecutables {
+		if found, err := exec.LookPath(executable); err == nil {
+			return found, nil
+		}
+	}
+
+	return "", fmt.Errorf("cannot find Chromium or Chrome, install it or set CHROME_PATH")
+}
+
+// Browser is a headless Chromium, all pages are rendered with the same viewport
+type Browser struct {
+	cmd     *exec.Cmd
+	dataDir string
+	conn    *websocket.Conn
+	mu      sync.Mutex
+	nextId  int
+	width   int
+	height  int
+}
+
+// Launch starts the executable with a temporary profile and connects to its DevTools endpoint
+func Launch(ctx context.Context, executable string, width, height int) (*Browser, error) {
+	dataDir, err := os.MkdirTemp("", "shopware-cli-chromium-")
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{
+		"--headless=new",
+		"--remote-debugging-port=0",
+		"--user-data-dir=" + dataDir,
+		"--hide-scrollbars",
+		"--no-first-run",
+		"--no-default-browser-check",
+		"--disable-gpu",
+		fmt.Sprintf("--window-size=%d,%d", width, height),
+	}
+
+	// Chromium refuses to start as root with the sandbox, which is common in containers
+	if os.Geteuid() == 0 {
+		args = append(args, "--no-sandbox")
+	}
+
+	cmd := exec.CommandContext(ctx, executable, append(args, "about:blank")...)
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		_ = os.RemoveAll(dataDir)
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		_ = os.RemoveAll(dataDir)
+		return nil, fmt.Errorf("start %s: %w", executable, err)
+	}
+
+	browser := &Browser{cmd: cmd, dataDir: dataDir, width: width, height: height}
+
+	url, err := readDevToolsUrl(stderr, launchTimeout)
+	if err != nil {
+		_ = browser.Close()
+		return nil, err
+	}
+
+	browser.conn, _, err = websocket.DefaultDialer.DialContext(ctx, url, nil)
+	if err != nil {
+		_ = browser.Close()
+		return nil, fmt.Errorf("connect to chromium: %w", err)
+	}
+
+	return browser, nil
+}
+
+// readDevToolsUrl waits for the websocket URL Chromium prints on start, the remaining output is discarded
+func readDevToolsUrl(stderr io.Reader, timeout time.Duration) (string, error) {
+	found := make(chan string, 1)
+
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+
+		for scanner.Scan() {
+			if match := devToolsUrlRegExp.FindStringSubmatch(scanner.Text()); match != nil {
+				found <- match[1]
+				_, _ = io.Copy(io.Discard, stderr)
+
+				return
+			}
+		}
+
+		close(found)
+	}()
+
+	select {
+	case url, ok := <-found:
+		if !ok {
+			return "", fmt.Errorf("chromium exited without opening the DevTools endpoint")
+		}
+
+		return url, nil
+	case <-time.After(timeout):
+		return "", fmt.Errorf("chromium did not open the DevTools endpoint within %s", timeout)
+	}
+}
+
+// Capture opens the URL in a new tab and waits until the page and the waitFor element are loaded. The selector is scrolled
+// into the center of the viewport, the png has always the size of the viewport.
+func (b *Browser) Capture(ctx context.Context, url, selector, waitFor string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, pageTimeout)
+	defer cancel()
+
+	var target struct {
+		TargetId string `json:"targetId"`
+	}
+
+	if err := b.call(ctx, "", "Target.createTarget", map[string]any{"url": "about:blank"}, &target); err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = b.call(context.Background(), "", "Target.closeTarget", map[string]any{"targetId": target.TargetId}, nil)
+	}()
+
+	var attached struct {
+		SessionId string `json:"sessionId"`
+	}
+
+	if err := b.call(ctx, "", "Target.attachToTarget", map[string]any{"targetId": target.TargetId, "flatten": true}, &attached); err != nil {
+		return nil, err
+	}
+
+	session := attached.SessionId
+
+	if err := b.call(ctx, session, "Emulation.setDeviceMetricsOverride", map[string]any{"width": b.width, "height": b.height, "deviceScaleFactor": 1, "mobile": false}, nil); err != nil {
+		return nil, err
+	}
+
+	var navigation struct {
+		ErrorText string `json:"errorText"`
+	}
+
+	if err := b.call(ctx, session, "Page.navigate", map[string]any{"url": url}, &navigation); err != nil {
+		return nil, err
+	}
+
+	if navigation.ErrorText != "" {
+		return nil, fmt.Errorf("open %s: %s", url, navigation.ErrorText)
+	}
+
+	if waitFor == "" {
+		waitFor = selector
+	}
+
+	if err := b.waitUntilLoaded(ctx, session, waitFor); err != nil {
+		return nil, fmt.Errorf("open %s: %w", url, err)
+	}
+
+	if selector != "" {
+		quoted, _ := json.Marshal(selector)
+
+		found, err := b.evaluate(ctx, session, fmt.Sprintf(`(() => { const element = document.querySelector(%s); if (!element) { return false; } element.scrollIntoView({block: 'center'}); return true; })()`, quoted))
+		if err != nil {
+			return nil, err
+		}
+
+		if !found {
+			return nil, fmt.Errorf("the selector %s matches no element on %s", selector, url)
+		}
+	}
+
+	var screenshot struct {
+		Data string `json:"data"`
+	}
+
+	if err := b.call(ctx, session, "Page.captureScreenshot", map[string]any{"format": "png"}, &screenshot); err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(screenshot.Data)
+}
+
+// waitUntilLoaded polls until the document and its fonts are loaded and the selector matches an element
+func (b *Browser) waitUntilLoaded(ctx context.Context, session, selector string) error {
+	quoted, _ := json.Marshal(selector)
+	expression := fmt.Sprintf(`document.readyState === 'complete' && document.fonts.status === 'loaded' && (%s === '' || document.querySelector(%s) !== null)`, quoted, quoted)
+
+	for {
+		loaded, err := b.evaluate(ctx, session, expression)
+		if err != nil {
+			return err
+		}
+
+		if loaded {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if selector != "" {
+				return fmt.Errorf("timed out waiting for %s", selector)
+			}
+
+			return fmt.Errorf("timed out waiting for the page to load")
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+func (b *Browser) evaluate(ctx context.Context, session, expression string) (bool, error) {
+	var evaluated struct {
+		Result struct {
+			Value any `json:"value"`
+		} `json:"result"`
+		ExceptionDetails *struct {
+			Text string `json:"text"`
+		} `json:"exceptionDetails"`
+	}
+
+	if err := b.call(ctx, session, "Runtime.evaluate", map[string]any{"expression": expression, "returnByValue": true}, &evaluated); err != nil {
+		return false, err
+	}
+
+	if evaluated.ExceptionDetails != nil {
+		return false, fmt.Errorf("evaluate script: %s", evaluated.ExceptionDetails.Text)
+	}
+
+	value, _ := evaluated.Result.Value.(bool)
+
+	return value, nil
+}
+
+// call sends a DevTools command and waits for its response, events received meanwhile are ignored
+func (b *Browser) call(ctx context.Context, session, method string, params map[string]any, result any) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextId++
+	id := b.nextId
+
+	message := map[string]any{"id": id, "method": method, "params": params}
+	if session != "" {
+		message["sessionId"] = session
+	}
+
+	deadline, _ := ctx.Deadline()
+
+	if err := b.conn.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+
+	if err := b.conn.SetReadDeadline(deadline); err != nil {
+		return err
+	}
+
+	if err := b.conn.WriteJSON(message); err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+
+	for {
+		var response struct {
+			Id     int             `json:"id"`
+			Result json.RawMessage `json:"result"`
+			Error  *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+
+		if err := b.conn.ReadJSON(&response); err != nil {
+			return fmt.Errorf("%s: %w", method, err)
+		}
+
+		if response.Id != id {
+			continue
+		}
+
+		if response.Error != nil {
+			return fmt.Errorf("%s: %s", method, response.Error.Message)
+		}
+
+		if result == nil {
+			return nil
+		}
+
+		return json.Unmarshal(response.Result, result)
+	}
+}
+
+// Close stops Chromium and removes its temporary profile
+func (b *Browser) Close() error {
+	if b.conn != nil {
+		_ = b.conn.Close()
+	}
+
+	if b.cmd.Process != nil {
+		_ = b.cmd.Process.Kill()
+		_ = b.cmd.Wait()
+	}
+
+	return os.RemoveAll(b.dataDir)
+}
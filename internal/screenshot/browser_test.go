@@ -0,0 +1,139 @@
+package screenshot
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeBrowser answers the DevTools commands with the result of respond and sends an event before every response
+func newFakeBrowser(t *testing.T, respond func(method string, params map[string]any) (any, string)) *Browser {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+
+		defer func() { _ = conn.Close() }()
+
+		for {
+			var message struct {
+				Id     int            `json:"id"`
+				Method string         `json:"method"`
+				Params map[string]any `json:"params"`
+			}
+
+			if err := conn.ReadJSON(&message); err != nil {
+				return
+			}
+
+			_ = conn.WriteJSON(map[string]any{"method": "Page.loadEventFired", "params": map[string]any{}})
+
+			result, errorMessage := respond(message.Method, message.Params)
+
+			if errorMessage != "" {
+				_ = conn.WriteJSON(map[string]any{"id": message.Id, "error": map[string]any{"message": errorMessage}})
+				continue
+			}
+
+			_ = conn.WriteJSON(map[string]any{"id": message.Id, "result": result})
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return &Browser{conn: conn, width: 1920, height: 1080}
+}
+
+func TestCapture(t *testing.T) {
+	var methods []string
+
+	browser := newFakeBrowser(t, func(method string, params map[string]any) (any, string) {
+		methods = append(methods, method)
+
+		switch method {
+		case "Target.createTarget":
+			return map[string]any{"targetId": "target"}, ""
+		case "Target.attachToTarget":
+			return map[string]any{"sessionId": "session"}, ""
+		case "Emulation.setDeviceMetricsOverride":
+			assert.Equal(t, float64(1920), params["width"])
+		case "Page.navigate":
+			assert.Equal(t, "http://localhost:8000/en", params["url"])
+		case "Runtime.evaluate":
+			return map[string]any{"result": map[string]any{"type": "boolean", "value": true}}, ""
+		case "Page.captureScreenshot":
+			return map[string]any{"data": base64.StdEncoding.EncodeToString([]byte("png"))}, ""
+		}
+
+		return map[string]any{}, ""
+	})
+
+	png, err := browser.Capture(t.Context(), "http://localhost:8000/en", ".product-detail", "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "png", string(png))
+	assert.Equal(t, []string{
+		"Target.createTarget",
+		"Target.attachToTarget",
+		"Emulation.setDeviceMetricsOverride",
+		"Page.navigate",
+		"Runtime.evaluate",
+		"Runtime.evaluate",
+		"Page.captureScreenshot",
+		"Target.closeTarget",
+	}, methods)
+}
+
+func TestCaptureMissingSelector(t *testing.T) {
+	browser := newFakeBrowser(t, func(method string, params map[string]any) (any, string) {
+		switch method {
+		case "Runtime.evaluate":
+			scrolls := strings.Contains(params["expression"].(string), "scrollIntoView")
+			return map[string]any{"result": map[string]any{"value": !scrolls}}, ""
+		case "Page.navigate":
+			return map[string]any{}, ""
+		}
+
+		return map[string]any{"targetId": "target", "sessionId": "session"}, ""
+	})
+
+	_, err := browser.Capture(t.Context(), "http://localhost:8000/en", ".missing", "body")
+	assert.ErrorContains(t, err, "the selector .missing matches no element")
+}
+
+func TestCaptureNavigationError(t *testing.T) {
+	browser := newFakeBrowser(t, func(method string, _ map[string]any) (any, string) {
+		if method == "Page.navigate" {
+			return map[string]any{"errorText": "net::ERR_CONNECTION_REFUSED"}, ""
+		}
+
+		return map[string]any{"targetId": "target", "sessionId": "session"}, ""
+	})
+
+	_, err := browser.Capture(t.Context(), "http://localhost:8000/en", "", "")
+	assert.ErrorContains(t, err, "net::ERR_CONNECTION_REFUSED")
+}
+
+func TestReadDevToolsUrl(t *testing.T) {
+	url, err := readDevToolsUrl(strings.NewReader("[0101/000000.000000:WARNING] something\n\nDevTools listening on ws://127.0.0.1:40000/devtools/browser/abc\n"), time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "ws://127.0.0.1:40000/devtools/browser/abc", url)
+
+	_, err = readDevToolsUrl(strings.NewReader("Failed to launch\n"), time.Second)
+	assert.ErrorContains(t, err, "exited without opening")
+}
@@ -0,0 +1,356 @@
+// Package shopwareversion provides the released Shopware versions with their PHP requirement and the end of life of each
+// minor version line. A bundled copy is used when the data cannot be refreshed, so the commands work offline.
+package shopwareversion
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/system"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+//go:generate go run ../../scripts/shopware-versions -output shopware-versions.json
+
+// bundledVersions is generated with go generate, do not edit it by hand
+//
+//go:embed shopware-versions.json
+var bundledVersions []byte
+
+const (
+	// refreshInterval is the time after which the cached metadata is fetched again
+	refreshInterval = 24 * time.Hour
+	fetchTimeout    = 10 * time.Second
+	cacheFileName   = "shopware-versions.json"
+)
+
+var (
+	packagistURL  = "https://repo.packagist.org/p2/shopware/core.json"
+	phpVersionURL = "https://raw.githubusercontent.com/FriendsOfShopware/shopware-static-data/main/data/php-version.json"
+	endOfLifeURL  = "https://endoflife.date/api/shopware.json"
+)
+
+// Release is a tagged version of shopware/core
+type Release struct {
+	Version string `json:"version"`
+	// PHP is the lowest PHP version the release supports like 8.2, empty when not known
+	PHP string `json:"php,omitempty"`
+}
+
+// Line is a minor version line like 6.6, which receives updates until its end of life
+type Line struct {
+	Version  string `json:"version"`
+	Released string `json:"released,omitempty"`
+	// EOL is the date the line stops receiving security updates, empty when not announced yet
+	EOL string `json:"eol,omitempty"`
+	// Node is the lowest Node.js version the Administration and Storefront of the line build with, empty when not known
+	Node string `json:"node,omitempty"`
+}
+
+// IsEOL returns true when the end of life of the line is before now
+func (l Line) IsEOL(now time.Time) bool {
+	eol, err := time.Parse(time.DateOnly, l.EOL)
+
+	return err == nil && now.After(eol)
+}
+
+type Metadata struct {
+	UpdatedAt time.Time `json:"updatedAt"`
+	Lines     []Line    `json:"lines"`
+	Releases  []Release `json:"releases"`
+}
+
+type cachedMetadata struct {
+	// CheckedAt is the last refresh attempt, failed attempts are not repeated before refreshInterval passed
+	CheckedAt time.Time `json:"checkedAt"`
+	Metadata  *Metadata `json:"metadata"`
+}
+
+var (
+	loaded     *Metadata
+	loadedLock sync.Mutex
+)
+
+// Bundled returns the metadata shipped with the CLI
+func Bundled() (*Metadata, error) {
+	var metadata Metadata
+
+	if err := json.Unmarshal(bundledVersions, &metadata); err != nil {
+		return nil, fmt.Errorf("cannot parse bundled Shopware versions: %w", err)
+	}
+
+	return &metadata, nil
+}
+
+// Load returns the cached metadata and refreshes it once a day. When the refresh fails, the previous cache or the bundled
+// metadata is used, whichever is newer.
+func Load(ctx context.Context) (*Metadata, error) {
+	loadedLock.Lock()
+	defer loadedLock.Unlock()
+
+	if loaded != nil {
+		return loaded, nil
+	}
+
+	metadata, err := Bundled()
+	if err != nil {
+		return nil, err
+	}
+
+	cacheFile := filepath.Join(system.GetShopwareCliCacheDir(), cacheFileName)
+	cached := readCache(cacheFile)
+
+	if cached != nil && cached.Metadata.UpdatedAt.After(metadata.UpdatedAt) {
+		metadata = cached.Metadata
+	}
+
+	if cached != nil && time.Since(cached.CheckedAt) < refreshInterval {
+		loaded = metadata
+		return loaded, nil
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+
+	cache := cachedMetadata{CheckedAt: time.Now(), Metadata: metadata}
+
+	if fetched, err := Fetch(fetchCtx, metadata); err != nil {
+		logging.FromContext(ctx).Debugf("Could not refresh the Shopware versions, using the versions of %s: %v", metadata.UpdatedAt.Format(time.DateOnly), err)
+	} else {
+		cache.Metadata = fetched
+	}
+
+	if err := writeCache(cacheFile, cache); err != nil {
+		logging.FromContext(ctx).Debugf("Could not cache the Shopware versions: %v", err)
+	}
+
+	loaded = cache.Metadata
+
+	return loaded, nil
+}
+
+func readCache(file string) *cachedMetadata {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+
+	var cached cachedMetadata
+	if err := json.Unmarshal(content, &cached); err != nil || cached.Metadata == nil {
+		return nil
+	}
+
+	return &cached
+}
+
+func writeCache(file string, cache cachedMetadata) error {
+	content, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0o750); err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, content, 0o600)
+}
+
+// Fetch downloads the releases from Packagist, their PHP requirement from shopware-static-data and the end of life dates
+// from endoflife.date. PHP versions and lines which cannot be fetched and the Node.js versions are taken from previous.
+func Fetch(ctx context.Context, previous *Metadata) (*Metadata, error) {
+	var packagist struct {
+		Packages struct {
+			Core []struct {
+				Version string `json:"version_normalized"`
+			} `json:"shopware/core"`
+		} `json:"packages"`
+	}
+
+	if err := fetchJSON(ctx, packagistURL, &packagist); err != nil {
+		return nil, err
+	}
+
+	if len(packagist.Packages.Core) == 0 {
+		return nil, fmt.Errorf("packagist returned no versions of shopware/core")
+	}
+
+	phpVersions := map[string]string{}
+	for _, release := range previous.Releases {
+		phpVersions[release.Version] = release.PHP
+	}
+
+	var fetchedPHPVersions map[string]string
+	if err := fetchJSON(ctx, phpVersionURL, &fetchedPHPVersions); err != nil {
+		logging.FromContext(ctx).Debugf("Could not fetch the PHP versions of Shopware: %v", err)
+	}
+
+	for shopwareVersion, phpVersion := range fetchedPHPVersions {
+		phpVersions[shopwareVersion] = phpVersion
+	}
+
+	metadata := &Metadata{UpdatedAt: time.Now().UTC().Truncate(time.Second), Lines: previous.Lines}
+
+	for _, core := range packagist.Packages.Core {
+		metadata.Releases = append(metadata.Releases, Release{Version: core.Version, PHP: phpVersions[core.Version]})
+	}
+
+	sortReleases(metadata.Releases)
+
+	var cycles []struct {
+		Cycle       string `json:"cycle"`
+		ReleaseDate string `json:"releaseDate"`
+		// EOL is a date or false
+		EOL any `json:"eol"`
+	}
+
+	if err := fetchJSON(ctx, endOfLifeURL, &cycles); err != nil {
+		logging.FromContext(ctx).Debugf("Could not fetch the end of life of the Shopware versions: %v", err)
+		return metadata, nil
+	}
+
+	nodeVersions := map[string]string{}
+	for _, line := range previous.Lines {
+		nodeVersions[line.Version] = line.Node
+	}
+
+	metadata.Lines = []Line{}
+
+	for _, cycle := range cycles {
+		line := Line{Version: cycle.Cycle, Released: cycle.ReleaseDate, Node: nodeVersions[cycle.Cycle]}
+
+		if eol, ok := cycle.EOL.(string); ok {
+			line.EOL = eol
+		}
+
+		metadata.Lines = append(metadata.Lines, line)
+	}
+
+	slices.SortFunc(metadata.Lines, func(a, b Line) int {
+		return compareVersions(a.Version, b.Version)
+	})
+
+	return metadata, nil
+}
+
+func fetchJSON(ctx context.Context, url string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("User-Agent", "Shopware CLI")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logging.FromContext(ctx).Errorf("Cannot close response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetch %s: %s", url, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+func sortReleases(releases []Release) {
+	slices.SortFunc(releases, func(a, b Release) int {
+		return compareVersions(a.Version, b.Version)
+	})
+}
+
+func compareVersions(a, b string) int {
+	versionA, errA := version.NewVersion(a)
+	versionB, errB := version.NewVersion(b)
+
+	if errA != nil || errB != nil {
+		return 0
+	}
+
+	return versionA.Compare(versionB)
+}
+
+// Versions returns all released versions in ascending order
+func (m *Metadata) Versions() []string {
+	versions := make([]string, 0, len(m.Releases))
+
+	for _, release := range m.Releases {
+		versions = append(versions, release.Version)
+	}
+
+	return versions
+}
+
+// PHPVersion returns the lowest PHP version of the oldest stable release matching the constraint
+func (m *Metadata) PHPVersion(constraint *version.Constraints) (string, error) {
+	for _, release := range m.Releases {
+		v, err := version.NewVersion(release.Version)
+		if err != nil || v.Prerelease() != "" || release.PHP == "" || !constraint.Check(v) {
+			continue
+		}
+
+		return release.PHP, nil
+	}
+
+	return "", fmt.Errorf("could not find php version for shopware version")
+}
+
+// LinesOf returns the lines with at least one stable release matching the constraint
+func (m *Metadata) LinesOf(constraint *version.Constraints) []Line {
+	var lines []Line
+
+	for _, line := range m.Lines {
+		for _, release := range m.Releases {
+			v, err := version.NewVersion(release.Version)
+			if err != nil || v.Prerelease() != "" || fmt.Sprintf("%d.%d", v.Major(), v.Minor()) != line.Version {
+				continue
+			}
+
+			if constraint.Check(v) {
+				lines = append(lines, line)
+				break
+			}
+		}
+	}
+
+	return lines
+}
+
+// MinimumRequirements returns the lowest PHP and Node.js version of the oldest line which has not reached its end of life
+func (m *Metadata) MinimumRequirements(now time.Time) (string, string, error) {
+	for _, line := range m.Lines {
+		if line.IsEOL(now) || line.Node == "" {
+			continue
+		}
+
+		constraint, err := version.NewConstraint("~" + line.Version + ".0")
+		if err != nil {
+			return "", "", err
+		}
+
+		phpVersion, err := m.PHPVersion(&constraint)
+		if err != nil {
+			continue
+		}
+
+		return phpVersion, line.Node, nil
+	}
+
+	return "", "", fmt.Errorf("could not find the requirements of a maintained shopware version")
+}
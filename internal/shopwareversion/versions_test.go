@@ -0,0 +1,147 @@
+package shopwareversion
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shyim/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundled(t *testing.T) {
+	metadata, err := Bundled()
+	require.NoError(t, err)
+
+	assert.Contains(t, metadata.Versions(), "6.6.0.0")
+	assert.NotEmpty(t, metadata.Lines)
+
+	for _, release := range metadata.Releases {
+		assert.NotEmpty(t, release.PHP, release.Version)
+	}
+
+	_, _, err = metadata.MinimumRequirements(time.Now())
+	assert.NoError(t, err)
+}
+
+func TestMetadataPHPVersion(t *testing.T) {
+	metadata, err := Bundled()
+	require.NoError(t, err)
+
+	phpVersion, err := metadata.PHPVersion(mustConstraint(t, "~6.5.0 || ~6.6.0"))
+	require.NoError(t, err)
+	assert.Equal(t, "8.1", phpVersion)
+
+	_, err = metadata.PHPVersion(mustConstraint(t, "~5.0"))
+	assert.Error(t, err)
+}
+
+func TestMetadataLinesOf(t *testing.T) {
+	metadata := &Metadata{
+		Lines:    []Line{{Version: "6.5", EOL: "2025-07-01"}, {Version: "6.6"}},
+		Releases: []Release{{Version: "6.5.0.0"}, {Version: "6.6.0.0-RC1"}, {Version: "6.6.0.0"}},
+	}
+
+	lines := metadata.LinesOf(mustConstraint(t, "~6.5.0"))
+	assert.Equal(t, []Line{{Version: "6.5", EOL: "2025-07-01"}}, lines)
+	assert.True(t, lines[0].IsEOL(time.Date(2025, 7, 2, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, lines[0].IsEOL(time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, Line{Version: "6.6"}.IsEOL(time.Now()))
+
+	assert.Len(t, metadata.LinesOf(mustConstraint(t, ">=6.5")), 2)
+}
+
+func TestMetadataMinimumRequirements(t *testing.T) {
+	metadata := &Metadata{
+		Lines:    []Line{{Version: "6.5", EOL: "2025-07-01", Node: "18.0.0"}, {Version: "6.6", Node: "20.0.0"}, {Version: "6.7"}},
+		Releases: []Release{{Version: "6.5.0.0", PHP: "8.1"}, {Version: "6.6.0.0-RC1", PHP: "8.1"}, {Version: "6.6.0.0", PHP: "8.2"}, {Version: "6.7.0.0", PHP: "8.2"}},
+	}
+
+	phpVersion, nodeVersion, err := metadata.MinimumRequirements(time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "8.1", phpVersion)
+	assert.Equal(t, "18.0.0", nodeVersion)
+
+	phpVersion, nodeVersion, err = metadata.MinimumRequirements(time.Date(2025, 7, 2, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "8.2", phpVersion)
+	assert.Equal(t, "20.0.0", nodeVersion)
+
+	_, _, err = (&Metadata{Lines: []Line{{Version: "6.7"}}}).MinimumRequirements(time.Now())
+	assert.Error(t, err)
+}
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/core.json":
+			_, _ = w.Write([]byte(`{"packages": {"shopware/core": [{"version_normalized": "6.7.0.0"}, {"version_normalized": "6.6.0.0"}]}}`))
+		case "/php.json":
+			_, _ = w.Write([]byte(`{"6.7.0.0": "8.2"}`))
+		case "/eol.json":
+			_, _ = w.Write([]byte(`[{"cycle": "6.7", "releaseDate": "2025-06-04", "eol": false}, {"cycle": "6.6", "releaseDate": "2024-03-21", "eol": "2026-07-01"}]`))
+		}
+	}))
+	defer server.Close()
+
+	setTestURLs(t, server.URL+"/core.json", server.URL+"/php.json", server.URL+"/eol.json")
+
+	previous := &Metadata{Releases: []Release{{Version: "6.6.0.0", PHP: "8.2"}}, Lines: []Line{{Version: "6.6", Node: "20.0.0"}}}
+
+	metadata, err := Fetch(t.Context(), previous)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Release{{Version: "6.6.0.0", PHP: "8.2"}, {Version: "6.7.0.0", PHP: "8.2"}}, metadata.Releases)
+	assert.Equal(t, []Line{{Version: "6.6", Released: "2024-03-21", EOL: "2026-07-01", Node: "20.0.0"}, {Version: "6.7", Released: "2025-06-04"}}, metadata.Lines)
+}
+
+func TestLoadFallsBackToBundledVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	setTestURLs(t, server.URL, server.URL, server.URL)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	metadata, err := Load(t.Context())
+	require.NoError(t, err)
+
+	bundled, err := Bundled()
+	require.NoError(t, err)
+	assert.Equal(t, bundled.Versions(), metadata.Versions())
+
+	content, err := os.ReadFile(filepath.Join(os.Getenv("XDG_CACHE_HOME"), "shopware-cli", cacheFileName))
+	require.NoError(t, err)
+
+	var cached cachedMetadata
+	require.NoError(t, json.Unmarshal(content, &cached))
+	assert.WithinDuration(t, time.Now(), cached.CheckedAt, time.Minute)
+}
+
+func setTestURLs(t *testing.T, packagist, php, endOfLife string) {
+	t.Helper()
+
+	previousPackagist, previousPHP, previousEndOfLife := packagistURL, phpVersionURL, endOfLifeURL
+	packagistURL, phpVersionURL, endOfLifeURL = packagist, php, endOfLife
+	loaded = nil
+
+	t.Cleanup(func() {
+		packagistURL, phpVersionURL, endOfLifeURL = previousPackagist, previousPHP, previousEndOfLife
+		loaded = nil
+	})
+}
+
+func mustConstraint(t *testing.T, constraint string) *version.Constraints {
+	t.Helper()
+
+	c, err := version.NewConstraint(constraint)
+	require.NoError(t, err)
+
+	return &c
+}
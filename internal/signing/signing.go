@@ -0,0 +1,292 @@
+package signing
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ChecksumsFileName is the name of the checksum manifest written next to the signed files
+const ChecksumsFileName = "SHA256SUMS"
+
+// Key selects the GPG key used for signing. Without a private key the key of the default keyring or the agent is used.
+type Key struct {
+	// ID is passed as --local-user, empty uses the default key
+	ID string
+	// PrivateKey is an armored private key, it is imported into a temporary keyring
+	PrivateKey string
+	// Passphrase unlocks the private key without pinentry
+	Passphrase string
+}
+
+// KeyFromEnv reads the key from SHOPWARE_CLI_GPG_KEY_ID, SHOPWARE_CLI_GPG_PRIVATE_KEY and SHOPWARE_CLI_GPG_PASSPHRASE
+func KeyFromEnv() Key {
+	return Key{
+		ID:         os.Getenv("SHOPWARE_CLI_GPG_KEY_ID"),
+		PrivateKey: os.Getenv("SHOPWARE_CLI_GPG_PRIVATE_KEY"),
+		Passphrase: os.Getenv("SHOPWARE_CLI_GPG_PASSPHRASE"),
+	}
+}
+
+// Checksum is one line of the manifest
+type Checksum struct {
+	Name   string
+	SHA256 string
+}
+
+// FileResult is the verification result of one file of the manifest
+type FileResult struct {
+	Name string
+	// Err is nil when the file matches the checksum
+	Err error
+}
+
+// WriteChecksums adds the files to the manifest in their folder and returns its path. Existing entries of other files are kept,
+// so builds writing several zips into the same folder share one manifest. All files must be in the same folder.
+func WriteChecksums(files []string) (string, error) {
+	if len(files) == 0 {
+		return "", errors.New("no files to checksum")
+	}
+
+	dir := filepath.Dir(files[0])
+	manifest := filepath.Join(dir, ChecksumsFileName)
+
+	checksums, err := ReadChecksums(manifest)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	for _, file := range files {
+		if filepath.Dir(file) != dir {
+			return "", fmt.Errorf("%s is not in %s", file, dir)
+		}
+
+		sum, err := hashFile(file)
+		if err != nil {
+			return "", err
+		}
+
+		name := filepath.Base(file)
+
+		checksums = slices.DeleteFunc(checksums, func(c Checksum) bool { return c.Name == name })
+		checksums = append(checksums, Checksum{Name: name, SHA256: sum})
+	}
+
+	slices.SortFunc(checksums, func(a, b Checksum) int { return strings.Compare(a.Name, b.Name) })
+
+	var buf bytes.Buffer
+
+	// The format of sha256sum, so the manifest can be verified with sha256sum -c as well
+	for _, checksum := range checksums {
+		_, _ = fmt.Fprintf(&buf, "%s  %s\n", checksum.SHA256, checksum.Name)
+	}
+
+	if err := os.WriteFile(manifest, buf.Bytes(), 0o644); err != nil {
+		return "", err
+	}
+
+	return manifest, nil
+}
+
+// ReadChecksums parses a manifest in the format of sha256sum
+func ReadChecksums(manifest string) ([]Checksum, error) {
+	file, err := os.Open(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var checksums []Checksum
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		sum, name, found := strings.Cut(line, " ")
+		if !found || len(sum) != sha256.Size*2 {
+			return nil, fmt.Errorf("invalid line in %s: %q", manifest, line)
+		}
+
+		// sha256sum marks binary mode with an asterisk
+		checksums = append(checksums, Checksum{Name: strings.TrimPrefix(strings.TrimSpace(name), "*"), SHA256: strings.ToLower(sum)})
+	}
+
+	return checksums, scanner.Err()
+}
+
+// VerifyChecksums compares the files listed in the manifest with the files next to it
+func VerifyChecksums(manifest string) ([]FileResult, error) {
+	checksums, err := ReadChecksums(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]FileResult, 0, len(checksums))
+
+	for _, checksum := range checksums {
+		result := FileResult{Name: checksum.Name}
+
+		sum, err := hashFile(filepath.Join(filepath.Dir(manifest), checksum.Name))
+
+		switch {
+		case err != nil:
+			result.Err = err
+		case sum != checksum.SHA256:
+			result.Err = fmt.Errorf("checksum mismatch, expected %s got %s", checksum.SHA256, sum)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// Sign writes a detached armored signature of the file into file.asc
+func Sign(ctx context.Context, file string, key Key) (string, error) {
+	signature := file + ".asc"
+
+	gnupgHome, cleanup, err := keyring(ctx, key.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+
+	defer cleanup()
+
+	args := []string{"--batch", "--yes", "--armor", "--detach-sign", "--output", signature}
+
+	if key.ID != "" {
+		args = append(args, "--local-user", key.ID)
+	}
+
+	var stdin io.Reader
+
+	if key.Passphrase != "" {
+		args = append(args, "--pinentry-mode", "loopback", "--passphrase-fd", "0")
+		stdin = strings.NewReader(key.Passphrase)
+	}
+
+	if _, err := runGPG(ctx, gnupgHome, stdin, append(args, file)...); err != nil {
+		return "", fmt.Errorf("sign %s: %w", file, err)
+	}
+
+	return signature, nil
+}
+
+// VerifySignature checks the detached signature of the file and returns the fingerprint of the signing key.
+// With a public key the signature is only accepted from this key, otherwise the default keyring is used.
+func VerifySignature(ctx context.Context, file, signature, publicKey string) (string, error) {
+	gnupgHome, cleanup, err := keyring(ctx, publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	defer cleanup()
+
+	status, err := runGPG(ctx, gnupgHome, nil, "--batch", "--status-fd", "1", "--verify", signature, file)
+	if err != nil {
+		return "", fmt.Errorf("invalid signature %s: %w", signature, err)
+	}
+
+	fingerprint := parseValidSignature(status)
+	if fingerprint == "" {
+		return "", fmt.Errorf("invalid signature %s: gpg reported no valid signature", signature)
+	}
+
+	return fingerprint, nil
+}
+
+// parseValidSignature returns the fingerprint of the VALIDSIG status line of gpg
+func parseValidSignature(status string) string {
+	for _, line := range strings.Split(status, "\n") {
+		fields := strings.Fields(line)
+
+		if len(fields) >= 3 && fields[0] == "[GNUPG:]" && fields[1] == "VALIDSIG" {
+			return fields[2]
+		}
+	}
+
+	return ""
+}
+
+// keyring imports the armored key into a temporary GNUPGHOME, without a key the default home is used
+func keyring(ctx context.Context, armoredKey string) (string, func(), error) {
+	if armoredKey == "" {
+		return "", func() {}, nil
+	}
+
+	home, err := os.MkdirTemp("", "shopware-cli-gpg")
+	if err != nil {
+		return "", nil, err
+	}
+
+	cleanup := func() {
+		// Importing a secret key starts an agent for the home, it has to be stopped before the home is removed
+		killAgent := exec.Command("gpgconf", "--kill", "gpg-agent")
+		killAgent.Env = append(os.Environ(), "GNUPGHOME="+home)
+		_ = killAgent.Run()
+
+		_ = os.RemoveAll(home)
+	}
+
+	if _, err := runGPG(ctx, home, strings.NewReader(armoredKey), "--batch", "--import"); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("import gpg key: %w", err)
+	}
+
+	return home, cleanup, nil
+}
+
+func runGPG(ctx context.Context, gnupgHome string, stdin io.Reader, args ...string) (string, error) {
+	gpg := exec.CommandContext(ctx, "gpg", args...)
+	gpg.Stdin = stdin
+
+	if gnupgHome != "" {
+		gpg.Env = append(os.Environ(), "GNUPGHOME="+gnupgHome)
+	}
+
+	var stdout, stderr bytes.Buffer
+	gpg.Stdout = &stdout
+	gpg.Stderr = &stderr
+
+	if err := gpg.Run(); err != nil {
+		return stdout.String(), fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+func hashFile(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	hash := sha256.New()
+
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", fmt.Errorf("calculate checksum of %s: %w", file, err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,106 @@
+package signing
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteChecksumsMergesManifest(t *testing.T) {
+	dir := t.TempDir()
+
+	first := filepath.Join(dir, "FroshTools-sw6.6.zip")
+	second := filepath.Join(dir, "FroshTools-sw6.7.zip")
+
+	require.NoError(t, os.WriteFile(first, []byte("first"), 0o644))
+	require.NoError(t, os.WriteFile(second, []byte("second"), 0o644))
+
+	manifest, err := WriteChecksums([]string{second})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, ChecksumsFileName), manifest)
+
+	_, err = WriteChecksums([]string{first})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(manifest)
+	require.NoError(t, err)
+
+	assert.Equal(t, "a7937b64b8caa58f03721bb6bacf5c78cb235febe0e70b1b84cd99541461a08e  FroshTools-sw6.6.zip\n16367aacb67a4a017c8da8ab95682ccb390863780f7114dda0a0e0c55644c7c4  FroshTools-sw6.7.zip\n", string(content))
+
+	results, err := VerifyChecksums(manifest)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.NoError(t, results[0].Err)
+	assert.NoError(t, results[1].Err)
+
+	require.NoError(t, os.WriteFile(second, []byte("tampered"), 0o644))
+
+	results, err = VerifyChecksums(manifest)
+	require.NoError(t, err)
+	assert.NoError(t, results[0].Err)
+	assert.ErrorContains(t, results[1].Err, "checksum mismatch")
+}
+
+func TestReadChecksumsRejectsInvalidLines(t *testing.T) {
+	manifest := filepath.Join(t.TempDir(), ChecksumsFileName)
+	require.NoError(t, os.WriteFile(manifest, []byte("abc  FroshTools.zip\n"), 0o644))
+
+	_, err := ReadChecksums(manifest)
+	assert.ErrorContains(t, err, "invalid line")
+}
+
+func TestParseValidSignature(t *testing.T) {
+	status := "[GNUPG:] NEWSIG\n[GNUPG:] GOODSIG 0123456789ABCDEF Frosh\n[GNUPG:] VALIDSIG 0123456789ABCDEF0123456789ABCDEF01234567 2025-01-01 1735689600 0 4 0 22 10 00 0123456789ABCDEF0123456789ABCDEF01234567\n"
+
+	assert.Equal(t, "0123456789ABCDEF0123456789ABCDEF01234567", parseValidSignature(status))
+	assert.Empty(t, parseValidSignature("[GNUPG:] BADSIG 0123456789ABCDEF Frosh\n"))
+}
+
+func TestSignAndVerify(t *testing.T) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg is not installed")
+	}
+
+	home, err := os.MkdirTemp("", "gpg-test")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		killAgent := exec.Command("gpgconf", "--kill", "gpg-agent")
+		killAgent.Env = append(os.Environ(), "GNUPGHOME="+home)
+		_ = killAgent.Run()
+		_ = os.RemoveAll(home)
+	})
+
+	_, err = runGPG(t.Context(), home, nil, "--batch", "--passphrase", "", "--quick-gen-key", "Shopware CLI Test <test@example.com>", "ed25519", "sign", "never")
+	require.NoError(t, err)
+
+	privateKey, err := runGPG(t.Context(), home, nil, "--batch", "--armor", "--export-secret-keys")
+	require.NoError(t, err)
+
+	publicKey, err := runGPG(t.Context(), home, nil, "--batch", "--armor", "--export")
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	zipFile := filepath.Join(dir, "FroshTools.zip")
+	require.NoError(t, os.WriteFile(zipFile, []byte("zip"), 0o644))
+
+	manifest, err := WriteChecksums([]string{zipFile})
+	require.NoError(t, err)
+
+	signature, err := Sign(t.Context(), manifest, Key{PrivateKey: privateKey})
+	require.NoError(t, err)
+	assert.Equal(t, manifest+".asc", signature)
+
+	fingerprint, err := VerifySignature(t.Context(), manifest, signature, publicKey)
+	require.NoError(t, err)
+	assert.Len(t, fingerprint, 40)
+
+	require.NoError(t, os.WriteFile(manifest, []byte("tampered"), 0o644))
+
+	_, err = VerifySignature(t.Context(), manifest, signature, publicKey)
+	assert.ErrorContains(t, err, "invalid signature")
+}
@@ -48,6 +48,31 @@ func NewSpdxLicenses() (*SpdxLicenses, error) {
 	return s, nil
 }
 
+var licenseTokenRegExp = regexp.MustCompile(`[^\s()]+`)
+
+// Normalize returns the license expression with the identifiers written like in the SPDX list and upper case operators.
+// Unknown identifiers are kept as they are.
+func (s *SpdxLicenses) Normalize(license string) string {
+	return licenseTokenRegExp.ReplaceAllStringFunc(strings.TrimSpace(license), func(token string) string {
+		lowerToken := strings.ToLower(token)
+
+		switch lowerToken {
+		case "and", "or", "with":
+			return strings.ToUpper(lowerToken)
+		}
+
+		if identifier, ok := s.licenses[lowerToken]; ok {
+			return identifier[0].(string)
+		}
+
+		if exception, ok := s.exceptions[lowerToken]; ok {
+			return exception[0]
+		}
+
+		return token
+	})
+}
+
 // Validate checks if the provided license string or slice is a valid SPDX expression.
 func (s *SpdxLicenses) Validate(license interface{}) (bool, error) {
 	if license == nil {
@@ -33,3 +33,12 @@ func TestSpdxLicenses_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestSpdxLicenses_Normalize(t *testing.T) {
+	s, _ := NewSpdxLicenses()
+
+	assert.Equal(t, "MIT", s.Normalize(" mit "))
+	assert.Equal(t, "(LGPL-2.1-only OR GPL-3.0-or-later)", s.Normalize("(lgpl-2.1-only or gpl-3.0-or-later)"))
+	assert.Equal(t, "GPL-2.0-or-later WITH Classpath-exception-2.0", s.Normalize("gpl-2.0-or-later with classpath-exception-2.0"))
+	assert.Equal(t, "My-License", s.Normalize("My-License"))
+}
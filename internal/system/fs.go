@@ -3,7 +3,6 @@ package system
 import (
 	"fmt"
 	"io"
-	"io/fs"
 	"os"
 	"path/filepath"
 )
@@ -19,35 +18,34 @@ func CopyFiles(currentPath string, targetPath string) error {
 		return fmt.Errorf("failed to create target directory: %w", err)
 	}
 
-	// Walk through the current directory
-	return filepath.Walk(currentPath, func(path string, info fs.FileInfo, err error) error {
-		if err != nil {
-			return fmt.Errorf("failed to access path %q: %w", path, err)
-		}
-
-		// Get the relative path
-		relPath, err := filepath.Rel(currentPath, path)
-		if err != nil {
-			return fmt.Errorf("failed to get relative path for %q: %w", path, err)
-		}
-
+	entries, err := Walk(currentPath, WalkOptions{
 		// Skip development environment and VCS metadata folders
-		// (e.g., .devenv, .direnv, .git)
-		if info.IsDir() && (relPath == ".devenv" || relPath == ".direnv" || relPath == ".git") {
-			return filepath.SkipDir
-		}
+		ExcludePaths: []string{".devenv", ".direnv", ".git"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to walk %q: %w", currentPath, err)
+	}
 
+	for _, entry := range entries {
 		// Construct target path
-		targetFilePath := filepath.Join(targetPath, relPath)
+		targetFilePath := filepath.Join(targetPath, filepath.FromSlash(entry.Path))
 
 		// If it's a directory, create it in target
-		if info.IsDir() {
-			return os.MkdirAll(targetFilePath, 0o755)
+		if entry.Entry.IsDir() {
+			if err := os.MkdirAll(targetFilePath, 0o755); err != nil {
+				return err
+			}
+
+			continue
 		}
 
 		// Copy the file
-		return copyFile(path, targetFilePath)
-	})
+		if err := copyFile(filepath.Join(currentPath, filepath.FromSlash(entry.Path)), targetFilePath); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func copyFile(src, dst string) error {
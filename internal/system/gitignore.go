@@ -0,0 +1,185 @@
+package system
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// Gitignore matches slash separated paths relative to a root against patterns with the semantics of .gitignore
+type Gitignore struct {
+	rules []gitignoreRule
+}
+
+// NewGitignore parses the patterns like the lines of a .gitignore in the root, later patterns take precedence
+func NewGitignore(patterns []string) (*Gitignore, error) {
+	ignore := &Gitignore{}
+
+	for _, pattern := range patterns {
+		rule, ok := parseGitignoreLine(pattern, "")
+		if !ok {
+			continue
+		}
+
+		for _, segment := range strings.Split(rule.pattern, "/") {
+			if _, err := path.Match(segment, ""); err != nil {
+				return nil, fmt.Errorf("invalid gitignore pattern %q: %w", pattern, err)
+			}
+		}
+
+		ignore.rules = append(ignore.rules, rule)
+	}
+
+	return ignore, nil
+}
+
+// Match reports whether the path is ignored by the patterns. Unlike git, it does not check the parent directories.
+func (g *Gitignore) Match(rel string, isDir bool) bool {
+	return isGitignored(g.rules, rel, isDir)
+}
+
+// Empty reports whether there are no patterns
+func (g *Gitignore) Empty() bool {
+	return len(g.rules) == 0
+}
+
+type gitignoreRule struct {
+	// Directory of the .gitignore file relative to the walk root, empty for the root itself
+	base     string
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+func readGitignore(file, base string) ([]gitignoreRule, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	return parseGitignore(bufio.NewScanner(f), base)
+}
+
+func parseGitignore(scanner *bufio.Scanner, base string) ([]gitignoreRule, error) {
+	var rules []gitignoreRule
+
+	for scanner.Scan() {
+		if rule, ok := parseGitignoreLine(scanner.Text(), base); ok {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules, scanner.Err()
+}
+
+func parseGitignoreLine(line, base string) (gitignoreRule, bool) {
+	rule := gitignoreRule{base: base}
+
+	// Trailing spaces are ignored unless they are escaped
+	line = strings.TrimRight(line, " \t\r")
+	if strings.HasSuffix(line, "\\") {
+		line += " "
+	}
+
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule, false
+	}
+
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	}
+
+	line = strings.TrimPrefix(line, "\\")
+
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	// A slash at the beginning or in the middle anchors the pattern to the directory of the .gitignore
+	if strings.Contains(line, "/") {
+		rule.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+
+	if line == "" {
+		return rule, false
+	}
+
+	// Git negates a character class with [!...], path.Match with [^...]
+	rule.pattern = strings.ReplaceAll(line, "[!", "[^")
+
+	return rule, true
+}
+
+func (r gitignoreRule) matches(rel string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+
+	if r.base != "" {
+		if !strings.HasPrefix(rel, r.base+"/") {
+			return false
+		}
+
+		rel = rel[len(r.base)+1:]
+	}
+
+	if !r.anchored {
+		return matchGlob(strings.Split(r.pattern, "/"), []string{path.Base(rel)})
+	}
+
+	return matchGlob(strings.Split(r.pattern, "/"), strings.Split(rel, "/"))
+}
+
+// isGitignored reports whether the given slash separated path is ignored. Later rules take precedence like in git.
+func isGitignored(rules []gitignoreRule, rel string, isDir bool) bool {
+	ignored := false
+
+	for _, rule := range rules {
+		if rule.matches(rel, isDir) {
+			ignored = !rule.negate
+		}
+	}
+
+	return ignored
+}
+
+func matchGlob(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+
+	if pattern[0] == "**" {
+		// A trailing /** matches everything inside the directory, but not the directory itself
+		if len(pattern) == 1 {
+			return len(name) > 0
+		}
+
+		for i := 0; i <= len(name); i++ {
+			if matchGlob(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(name) == 0 {
+		return false
+	}
+
+	if ok, _ := path.Match(pattern[0], name[0]); !ok {
+		return false
+	}
+
+	return matchGlob(pattern[1:], name[1:])
+}
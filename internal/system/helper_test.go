@@ -0,0 +1,21 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestFiles creates the files below dir, the keys are slash separated paths relative to it
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for file, content := range files {
+		file = filepath.Join(dir, filepath.FromSlash(file))
+
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), os.ModePerm))
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o644))
+	}
+}
@@ -0,0 +1,137 @@
+package system
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type WalkOptions struct {
+	// Directory names which are skipped wherever they appear, like node_modules
+	ExcludeDirs []string
+	// Slash separated paths relative to the root which are skipped including their children
+	ExcludePaths []string
+	// Skip files and directories matched by .gitignore files found while walking
+	Gitignore bool
+}
+
+type WalkEntry struct {
+	// Slash separated path relative to the root
+	Path  string
+	Entry fs.DirEntry
+}
+
+// Walk lists all files and directories below root. Excluded directories are pruned before they are read
+// and directories are read in parallel. The entries are sorted by path.
+func Walk(root string, opts WalkOptions) ([]WalkEntry, error) {
+	w := &walker{
+		root: root,
+		opts: opts,
+		sem:  make(chan struct{}, runtime.NumCPU()*2),
+	}
+
+	w.wg.Add(1)
+	go w.readDir("", nil)
+	w.wg.Wait()
+
+	if w.err != nil {
+		return nil, w.err
+	}
+
+	sort.Slice(w.entries, func(i, j int) bool {
+		return w.entries[i].Path < w.entries[j].Path
+	})
+
+	return w.entries, nil
+}
+
+type walker struct {
+	root string
+	opts WalkOptions
+	sem  chan struct{}
+	wg   sync.WaitGroup
+
+	mu      sync.Mutex
+	entries []WalkEntry
+	err     error
+}
+
+func (w *walker) readDir(rel string, rules []gitignoreRule) {
+	defer w.wg.Done()
+
+	dir := filepath.Join(w.root, filepath.FromSlash(rel))
+
+	w.sem <- struct{}{}
+	dirEntries, err := os.ReadDir(dir)
+	<-w.sem
+
+	if err != nil {
+		w.fail(err)
+		return
+	}
+
+	if w.opts.Gitignore {
+		if _, err := os.Stat(filepath.Join(dir, ".gitignore")); err == nil {
+			dirRules, err := readGitignore(filepath.Join(dir, ".gitignore"), rel)
+			if err != nil {
+				w.fail(err)
+				return
+			}
+
+			// Clip so sibling directories never share the appended backing array
+			rules = append(slices.Clip(rules), dirRules...)
+		}
+	}
+
+	entries := make([]WalkEntry, 0, len(dirEntries))
+
+	for _, d := range dirEntries {
+		childRel := path.Join(rel, d.Name())
+
+		if w.excluded(childRel, d, rules) {
+			continue
+		}
+
+		entries = append(entries, WalkEntry{Path: childRel, Entry: d})
+
+		if d.IsDir() {
+			w.wg.Add(1)
+			go w.readDir(childRel, rules)
+		}
+	}
+
+	w.mu.Lock()
+	w.entries = append(w.entries, entries...)
+	w.mu.Unlock()
+}
+
+func (w *walker) excluded(rel string, d fs.DirEntry, rules []gitignoreRule) bool {
+	if d.IsDir() && slices.Contains(w.opts.ExcludeDirs, d.Name()) {
+		return true
+	}
+
+	for _, excluded := range w.opts.ExcludePaths {
+		excluded = strings.Trim(excluded, "/")
+
+		if rel == excluded || strings.HasPrefix(rel, excluded+"/") {
+			return true
+		}
+	}
+
+	return w.opts.Gitignore && isGitignored(rules, rel, d.IsDir())
+}
+
+func (w *walker) fail(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err == nil {
+		w.err = err
+	}
+}
@@ -0,0 +1,121 @@
+package system
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func walkPaths(t *testing.T, root string, opts WalkOptions) []string {
+	t.Helper()
+
+	entries, err := Walk(root, opts)
+	assert.NoError(t, err)
+
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		paths = append(paths, entry.Path)
+	}
+
+	return paths
+}
+
+func TestWalk(t *testing.T) {
+	root := t.TempDir()
+
+	writeTestFiles(t, root, map[string]string{
+		"composer.json":                       "{}",
+		"src/Plugin.php":                      "<?php",
+		"src/Resources/app/node_modules/a.js": "",
+		"vendor/autoload.php":                 "<?php",
+		"tests/PluginTest.php":                "<?php",
+	})
+
+	assert.Equal(t, []string{
+		"composer.json",
+		"src",
+		"src/Plugin.php",
+		"src/Resources",
+		"src/Resources/app",
+		"src/Resources/app/node_modules",
+		"src/Resources/app/node_modules/a.js",
+		"tests",
+		"tests/PluginTest.php",
+		"vendor",
+		"vendor/autoload.php",
+	}, walkPaths(t, root, WalkOptions{}))
+
+	assert.Equal(t, []string{
+		"composer.json",
+		"src",
+		"src/Plugin.php",
+		"src/Resources",
+		"src/Resources/app",
+	}, walkPaths(t, root, WalkOptions{ExcludeDirs: []string{"node_modules"}, ExcludePaths: []string{"vendor", "/tests/"}}))
+}
+
+func TestWalkGitignore(t *testing.T) {
+	root := t.TempDir()
+
+	writeTestFiles(t, root, map[string]string{
+		".gitignore":            "# comment\n/vendor\n*.log\nbuild/\n!keep.log\n",
+		"composer.json":         "{}",
+		"debug.log":             "",
+		"keep.log":              "",
+		"vendor/autoload.php":   "<?php",
+		"src/vendor/a.php":      "<?php",
+		"src/build/app.js":      "",
+		"src/.gitignore":        "generated/**/*.js\n",
+		"src/generated/a/b.js":  "",
+		"src/generated/a/b.css": "",
+	})
+
+	assert.Equal(t, []string{
+		".gitignore",
+		"composer.json",
+		"keep.log",
+		"src",
+		"src/.gitignore",
+		"src/generated",
+		"src/generated/a",
+		"src/generated/a/b.css",
+		"src/vendor",
+		"src/vendor/a.php",
+	}, walkPaths(t, root, WalkOptions{Gitignore: true}))
+}
+
+func TestGitignoreTrailingDoubleStar(t *testing.T) {
+	rules := []gitignoreRule{{pattern: "build/**", anchored: true}}
+
+	assert.False(t, isGitignored(rules, "build", true))
+	assert.True(t, isGitignored(rules, "build/app.js", false))
+	assert.True(t, isGitignored(rules, "build/js/app.js", false))
+
+	rules = []gitignoreRule{{pattern: "**/cache", anchored: true}}
+
+	assert.True(t, isGitignored(rules, "cache", true))
+	assert.True(t, isGitignored(rules, "var/cache", true))
+}
+
+func TestWalkMissingRoot(t *testing.T) {
+	_, err := Walk(filepath.Join(t.TempDir(), "missing"), WalkOptions{})
+	assert.Error(t, err)
+}
+
+func TestNewGitignore(t *testing.T) {
+	ignore, err := NewGitignore([]string{"# comment", "*.[!j]s", `trailing\ `, "", "!keep.ts"})
+	assert.NoError(t, err)
+
+	assert.True(t, ignore.Match("src/main.ts", false))
+	assert.False(t, ignore.Match("src/main.js", false))
+	assert.False(t, ignore.Match("keep.ts", false))
+	assert.True(t, ignore.Match("trailing ", false))
+
+	_, err = NewGitignore([]string{"src/[a-"})
+	assert.Error(t, err)
+
+	ignore, err = NewGitignore([]string{"# only a comment"})
+	assert.NoError(t, err)
+	assert.True(t, ignore.Empty())
+}
@@ -0,0 +1,124 @@
+package translation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// deepLProtectedRegExp matches the placeholders wrapped by protectPlaceholders
+var deepLProtectedRegExp = regexp.MustCompile(`<span translate="no">(.*?)</span>`)
+
+// deepLRegionalTargets are the target languages DeepL only accepts with a variant
+var deepLRegionalTargets = map[string]string{
+	"en":    "EN-GB",
+	"pt":    "PT-PT",
+	"en-us": "EN-US",
+	"en-gb": "EN-GB",
+	"pt-br": "PT-BR",
+	"pt-pt": "PT-PT",
+	"zh-cn": "ZH-HANS",
+	"zh-tw": "ZH-HANT",
+}
+
+type deepLTranslator struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+// newDeepLTranslator reads DEEPL_API_KEY, keys of the free plan end with :fx and use another host
+func newDeepLTranslator() (*deepLTranslator, error) {
+	apiKey := os.Getenv("DEEPL_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("DEEPL_API_KEY is not set")
+	}
+
+	url := os.Getenv("DEEPL_API_URL")
+	if url == "" {
+		url = "https://api.deepl.com"
+
+		if strings.HasSuffix(apiKey, ":fx") {
+			url = "https://api-free.deepl.com"
+		}
+	}
+
+	return &deepLTranslator{url: strings.TrimSuffix(url, "/"), apiKey: apiKey, client: &http.Client{Timeout: 60 * time.Second}}, nil
+}
+
+func (t *deepLTranslator) Translate(ctx context.Context, texts []string, sourceLocale, targetLocale string) ([]string, error) {
+	return inBatches(texts, func(batch []string) ([]string, error) {
+		return t.translateBatch(ctx, batch, sourceLocale, targetLocale)
+	})
+}
+
+func (t *deepLTranslator) translateBatch(ctx context.Context, texts []string, sourceLocale, targetLocale string) ([]string, error) {
+	protected := make([]string, len(texts))
+	for i, text := range texts {
+		protected[i] = placeholderRegExp.ReplaceAllString(text, `<span translate="no">$0</span>`)
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"text":         protected,
+		"source_lang":  strings.ToUpper(localeLanguage(sourceLocale)),
+		"target_lang":  deepLTargetLanguage(targetLocale),
+		"tag_handling": "html",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url+"/v2/translate", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create deepl request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+t.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("deepl: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("deepl: %s %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	var result struct {
+		Translations []struct {
+			Text string `json:"text"`
+		} `json:"translations"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("deepl: %w", err)
+	}
+
+	translations := make([]string, len(result.Translations))
+	for i, translation := range result.Translations {
+		translations[i] = deepLProtectedRegExp.ReplaceAllString(translation.Text, "$1")
+	}
+
+	return translations, nil
+}
+
+func deepLTargetLanguage(locale string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+
+	if target, ok := deepLRegionalTargets[normalized]; ok {
+		return target
+	}
+
+	return strings.ToUpper(localeLanguage(locale))
+}
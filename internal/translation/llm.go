@@ -0,0 +1,60 @@
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/shopware/shopware-cli/internal/llm"
+)
+
+const llmSystemPrompt = `You translate texts of a Shopware extension, like snippets of the Storefront and Administration and the store description.
+You receive a JSON object with the source and target locale and a JSON array of texts.
+Answer only with a JSON array of strings containing the translations in the same order, without any explanation or markdown.
+Keep placeholders like %name% and {name}, HTML tags, markdown and line breaks unchanged. Use the informal form of address unless the text is formal.`
+
+type llmTranslator struct {
+	client llm.LLMClient
+	model  string
+}
+
+func newLLMTranslator(model string) (*llmTranslator, error) {
+	client, err := llm.NewLLMClient(ProviderOpenAI)
+	if err != nil {
+		return nil, err
+	}
+
+	return &llmTranslator{client: client, model: model}, nil
+}
+
+func (t *llmTranslator) Translate(ctx context.Context, texts []string, sourceLocale, targetLocale string) ([]string, error) {
+	return inBatches(texts, func(batch []string) ([]string, error) {
+		prompt, err := json.Marshal(map[string]any{"source": sourceLocale, "target": targetLocale, "texts": batch})
+		if err != nil {
+			return nil, err
+		}
+
+		answer, err := t.client.Generate(ctx, string(prompt), &llm.LLMOptions{Model: t.model, SystemPrompt: llmSystemPrompt})
+		if err != nil {
+			return nil, err
+		}
+
+		return parseLLMTranslations(answer)
+	})
+}
+
+// parseLLMTranslations reads the JSON array, models sometimes wrap it in a markdown code block
+func parseLLMTranslations(answer string) ([]string, error) {
+	answer = strings.TrimSpace(answer)
+	answer = strings.TrimPrefix(answer, "```json")
+	answer = strings.TrimPrefix(answer, "```")
+	answer = strings.TrimSuffix(answer, "```")
+
+	var translations []string
+	if err := json.Unmarshal([]byte(strings.TrimSpace(answer)), &translations); err != nil {
+		return nil, fmt.Errorf("the model did not answer with a JSON array of translations: %w", err)
+	}
+
+	return translations, nil
+}
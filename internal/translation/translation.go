@@ -0,0 +1,65 @@
+// Package translation translates snippets and store texts with machine translation providers
+package translation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	ProviderDeepL  = "deepl"
+	ProviderOpenAI = "openai"
+)
+
+// batchSize is the amount of texts sent in one request, DeepL accepts at most 50
+const batchSize = 50
+
+// placeholderRegExp matches the placeholders of Storefront (%name%) and Administration ({name}) snippets
+var placeholderRegExp = regexp.MustCompile(`%[A-Za-z_][\w.\-]*%|\{[A-Za-z_][\w.\-]*\}`)
+
+type Translator interface {
+	// Translate returns the translations in the order of the texts, the locales are like en-GB
+	Translate(ctx context.Context, texts []string, sourceLocale, targetLocale string) ([]string, error)
+}
+
+// NewTranslator returns the provider configured by its environment variables, the model is only used by openai
+func NewTranslator(provider, model string) (Translator, error) {
+	switch provider {
+	case ProviderDeepL:
+		return newDeepLTranslator()
+	case ProviderOpenAI:
+		return newLLMTranslator(model)
+	}
+
+	return nil, fmt.Errorf("unknown translation provider %s, use %s or %s", provider, ProviderDeepL, ProviderOpenAI)
+}
+
+// inBatches calls translate for chunks of the texts and joins the results
+func inBatches(texts []string, translate func(batch []string) ([]string, error)) ([]string, error) {
+	translations := make([]string, 0, len(texts))
+
+	for start := 0; start < len(texts); start += batchSize {
+		batch := texts[start:min(start+batchSize, len(texts))]
+
+		translated, err := translate(batch)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(translated) != len(batch) {
+			return nil, fmt.Errorf("expected %d translations, got %d", len(batch), len(translated))
+		}
+
+		translations = append(translations, translated...)
+	}
+
+	return translations, nil
+}
+
+func localeLanguage(locale string) string {
+	language, _, _ := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+
+	return strings.ToLower(language)
+}
@@ -0,0 +1,90 @@
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/llm"
+)
+
+func TestDeepLTranslate(t *testing.T) {
+	var request map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/translate", r.URL.Path)
+		assert.Equal(t, "DeepL-Auth-Key test:fx", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+
+		_, _ = w.Write([]byte(`{"translations": [{"text": "Hallo <span translate=\"no\">%name%</span>"}, {"text": "Speichern"}]}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("DEEPL_API_KEY", "test:fx")
+	t.Setenv("DEEPL_API_URL", server.URL)
+
+	translator, err := NewTranslator(ProviderDeepL, "")
+	require.NoError(t, err)
+
+	translations, err := translator.Translate(t.Context(), []string{"Hello %name%", "Save"}, "en-GB", "de-DE")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Hallo %name%", "Speichern"}, translations)
+	assert.Equal(t, []any{`Hello <span translate="no">%name%</span>`, "Save"}, request["text"])
+	assert.Equal(t, "EN", request["source_lang"])
+	assert.Equal(t, "DE", request["target_lang"])
+}
+
+func TestDeepLRequiresAPIKey(t *testing.T) {
+	t.Setenv("DEEPL_API_KEY", "")
+
+	_, err := NewTranslator(ProviderDeepL, "")
+	assert.ErrorContains(t, err, "DEEPL_API_KEY")
+}
+
+func TestDeepLTargetLanguage(t *testing.T) {
+	assert.Equal(t, "DE", deepLTargetLanguage("de-DE"))
+	assert.Equal(t, "EN-US", deepLTargetLanguage("en-US"))
+	assert.Equal(t, "PT-BR", deepLTargetLanguage("pt_BR"))
+	assert.Equal(t, "ZH-HANS", deepLTargetLanguage("zh-CN"))
+}
+
+func TestUnknownProvider(t *testing.T) {
+	_, err := NewTranslator("google", "")
+	assert.ErrorContains(t, err, "unknown translation provider google")
+}
+
+type fakeLLMClient struct {
+	answer string
+	prompt string
+}
+
+func (c *fakeLLMClient) Generate(_ context.Context, prompt string, _ *llm.LLMOptions) (string, error) {
+	c.prompt = prompt
+
+	return c.answer, nil
+}
+
+func TestLLMTranslate(t *testing.T) {
+	client := &fakeLLMClient{answer: "```json\n[\"Hallo {name}\"]\n```"}
+	translator := &llmTranslator{client: client, model: "gpt-4o-mini"}
+
+	translations, err := translator.Translate(t.Context(), []string{"Hello {name}"}, "en-GB", "de-DE")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Hallo {name}"}, translations)
+	assert.True(t, strings.Contains(client.prompt, `"target":"de-DE"`))
+}
+
+func TestLLMTranslateChecksTheAmount(t *testing.T) {
+	translator := &llmTranslator{client: &fakeLLMClient{answer: `["Hallo", "Welt"]`}}
+
+	_, err := translator.Translate(t.Context(), []string{"Hello"}, "en-GB", "de-DE")
+	assert.ErrorContains(t, err, "expected 1 translations, got 2")
+}
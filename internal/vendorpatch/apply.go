@@ -0,0 +1,372 @@
+package vendorpatch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var hunkHeaderRegExp = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// FilePatch is the change of a single file of a unified diff
+type FilePatch struct {
+	// OldPath is empty for added files
+	OldPath string
+	// NewPath is empty for deleted files
+	NewPath string
+	Hunks   []Hunk
+}
+
+// Hunk is a block of changed lines, each line is prefixed with a space, - or +
+type Hunk struct {
+	OldStart int
+	NewStart int
+	Lines    []string
+	// OldNoNewline and NewNoNewline are set by a "\ No newline at end of file" marker after the last line of the side
+	OldNoNewline bool
+	NewNoNewline bool
+}
+
+// ParsePatch reads a unified diff like created by git diff or CreatePatch. The paths are stripped by one level like patch -p1.
+func ParsePatch(content string) ([]FilePatch, error) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	patches := []FilePatch{}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ") {
+			patches = append(patches, FilePatch{
+				OldPath: patchFilePath(line[4:]),
+				NewPath: patchFilePath(lines[i+1][4:]),
+			})
+			i++
+
+			continue
+		}
+
+		if !strings.HasPrefix(line, "@@ ") {
+			continue
+		}
+
+		if len(patches) == 0 {
+			return nil, fmt.Errorf("line %d: hunk without file header", i+1)
+		}
+
+		match := hunkHeaderRegExp.FindStringSubmatch(line)
+		if match == nil {
+			return nil, fmt.Errorf("line %d: invalid hunk header %s", i+1, line)
+		}
+
+		oldStart, _ := strconv.Atoi(match[1])
+		newStart, _ := strconv.Atoi(match[3])
+		oldCount := parseHunkCount(match[2])
+		newCount := parseHunkCount(match[4])
+
+		hunk := Hunk{OldStart: oldStart, NewStart: newStart}
+
+		for oldCount > 0 || newCount > 0 {
+			i++
+
+			// The last line is the empty string after the final newline
+			if i >= len(lines) || (i == len(lines)-1 && lines[i] == "") {
+				return nil, fmt.Errorf("unexpected end of patch in hunk %s", line)
+			}
+
+			hunkLine := lines[i]
+
+			// Some editors strip the trailing space of empty context lines
+			if hunkLine == "" {
+				hunkLine = " "
+			}
+
+			switch hunkLine[0] {
+			case ' ':
+				oldCount--
+				newCount--
+			case '-':
+				oldCount--
+			case '+':
+				newCount--
+			case '\\':
+				hunk.markNoNewline()
+				continue
+			default:
+				return nil, fmt.Errorf("line %d: unexpected line in hunk %s", i+1, line)
+			}
+
+			hunk.Lines = append(hunk.Lines, hunkLine)
+		}
+
+		// The marker of the last line follows after the counted lines
+		if i+1 < len(lines) && strings.HasPrefix(lines[i+1], "\\") {
+			i++
+			hunk.markNoNewline()
+		}
+
+		patches[len(patches)-1].Hunks = append(patches[len(patches)-1].Hunks, hunk)
+	}
+
+	return patches, nil
+}
+
+// markNoNewline applies a "\ No newline at end of file" marker to the side of the preceding line
+func (h *Hunk) markNoNewline() {
+	if len(h.Lines) == 0 {
+		return
+	}
+
+	switch h.Lines[len(h.Lines)-1][0] {
+	case '-':
+		h.OldNoNewline = true
+	case '+':
+		h.NewNoNewline = true
+	default:
+		h.OldNoNewline = true
+		h.NewNoNewline = true
+	}
+}
+
+func parseHunkCount(count string) int {
+	if count == "" {
+		return 1
+	}
+
+	parsed, _ := strconv.Atoi(count)
+
+	return parsed
+}
+
+func patchFilePath(header string) string {
+	// git and diff append a timestamp separated by a tab
+	file, _, _ := strings.Cut(header, "\t")
+	file = strings.TrimSpace(file)
+
+	if file == "/dev/null" {
+		return ""
+	}
+
+	if _, stripped, found := strings.Cut(file, "/"); found {
+		return stripped
+	}
+
+	return file
+}
+
+// Reverse returns the patches which undo the given patches
+func Reverse(patches []FilePatch) []FilePatch {
+	reversed := make([]FilePatch, 0, len(patches))
+
+	for _, patch := range patches {
+		hunks := make([]Hunk, 0, len(patch.Hunks))
+
+		for _, hunk := range patch.Hunks {
+			lines := make([]string, 0, len(hunk.Lines))
+
+			for _, line := range hunk.Lines {
+				switch line[0] {
+				case '-':
+					lines = append(lines, "+"+line[1:])
+				case '+':
+					lines = append(lines, "-"+line[1:])
+				default:
+					lines = append(lines, line)
+				}
+			}
+
+			hunks = append(hunks, Hunk{OldStart: hunk.NewStart, NewStart: hunk.OldStart, Lines: lines, OldNoNewline: hunk.NewNoNewline, NewNoNewline: hunk.OldNoNewline})
+		}
+
+		reversed = append(reversed, FilePatch{OldPath: patch.NewPath, NewPath: patch.OldPath, Hunks: hunks})
+	}
+
+	return reversed
+}
+
+// Check returns an error when the patches do not apply to the files inside dir
+func Check(dir string, patches []FilePatch) error {
+	_, _, err := patchFiles(dir, patches)
+
+	return err
+}
+
+// Apply applies the patches to the files inside dir. Nothing is written when a hunk does not apply.
+func Apply(dir string, patches []FilePatch) error {
+	contents, order, err := patchFiles(dir, patches)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range order {
+		target := filepath.Join(dir, filepath.FromSlash(file))
+
+		if contents[file] == nil {
+			if err := os.Remove(target); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(target, []byte(*contents[file]), os.ModePerm); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// patchFiles returns the patched content of the files in the order they appear in the patch, deleted files have no content
+func patchFiles(dir string, patches []FilePatch) (map[string]*string, []string, error) {
+	contents := map[string]*string{}
+	order := []string{}
+
+	for _, patch := range patches {
+		file := patch.OldPath
+		if file == "" {
+			file = patch.NewPath
+		}
+
+		current, known := contents[file]
+
+		if !known {
+			content, err := readPatchTarget(dir, file, patch.OldPath != "")
+			if err != nil {
+				return nil, nil, err
+			}
+
+			current = &content
+			order = append(order, file)
+		}
+
+		if current == nil {
+			return nil, nil, fmt.Errorf("%s was already deleted by the patch", file)
+		}
+
+		patched, err := applyHunks(*current, patch.Hunks, file)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if patch.NewPath == "" {
+			contents[file] = nil
+		} else {
+			contents[file] = &patched
+		}
+	}
+
+	return contents, order, nil
+}
+
+func readPatchTarget(dir, file string, exists bool) (string, error) {
+	content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file)))
+
+	if !exists {
+		if err == nil {
+			return "", fmt.Errorf("%s should be created by the patch, but already exists", file)
+		}
+
+		return "", nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("%s does not exist", file)
+	}
+
+	return string(content), nil
+}
+
+func applyHunks(content string, hunks []Hunk, file string) (string, error) {
+	lines := []string{}
+
+	// Keep the newline at the end of the file as it is, unless the patch changes it
+	trailingNewline := content == "" || strings.HasSuffix(content, "\n")
+
+	if content != "" {
+		lines = strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	}
+
+	delta := 0
+
+	for i, hunk := range hunks {
+		before, after := []string{}, []string{}
+
+		for _, line := range hunk.Lines {
+			if line[0] != '+' {
+				before = append(before, line[1:])
+			}
+
+			if line[0] != '-' {
+				after = append(after, line[1:])
+			}
+		}
+
+		expected := max(hunk.OldStart-1, 0) + delta
+		if len(before) == 0 {
+			// Hunks only adding lines insert after the given line
+			expected = hunk.OldStart + delta
+		}
+
+		position := findHunk(lines, before, expected)
+		if position == -1 {
+			return "", fmt.Errorf("hunk #%d does not apply to %s", i+1, file)
+		}
+
+		lines = append(lines[:position], append(after, lines[position+len(before):]...)...)
+		delta += position - expected + len(after) - len(before)
+
+		switch {
+		case hunk.NewNoNewline:
+			trailingNewline = false
+		case hunk.OldNoNewline:
+			trailingNewline = true
+		}
+	}
+
+	if len(lines) == 0 {
+		return "", nil
+	}
+
+	if !trailingNewline {
+		return strings.Join(lines, "\n"), nil
+	}
+
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// findHunk returns the position of the lines nearest to the expected position, as files may have changed around the hunk
+func findHunk(lines, search []string, expected int) int {
+	expected = min(max(expected, 0), len(lines))
+
+	for distance := 0; distance <= len(lines); distance++ {
+		for _, position := range []int{expected - distance, expected + distance} {
+			if position < 0 || position+len(search) > len(lines) {
+				continue
+			}
+
+			if matchesLines(lines[position:position+len(search)], search) {
+				return position
+			}
+		}
+	}
+
+	return -1
+}
+
+func matchesLines(lines, search []string) bool {
+	for i := range search {
+		if lines[i] != search[i] {
+			return false
+		}
+	}
+
+	return true
+}
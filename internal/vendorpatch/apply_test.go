@@ -0,0 +1,149 @@
+package vendorpatch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyCreatedPatch(t *testing.T) {
+	pristineDir := t.TempDir()
+	installedDir := t.TempDir()
+
+	writeFiles(t, pristineDir, map[string]string{
+		"Kernel.php":            "<?php\n\nclass Kernel\n{\n    public function boot()\n    {\n    }\n}\n",
+		"Framework/Removed.php": "<?php\n",
+	})
+
+	writeFiles(t, installedDir, map[string]string{
+		"Kernel.php":          "<?php\n\nclass Kernel\n{\n    public function boot()\n    {\n        // patched\n    }\n}\n",
+		"Framework/Added.php": "<?php\n\necho 1;\n",
+	})
+
+	changes, err := Compare("shopware/core", installedDir, pristineDir)
+	assert.NoError(t, err)
+
+	content, _, err := CreatePatch(installedDir, pristineDir, changes)
+	assert.NoError(t, err)
+
+	patches, err := ParsePatch(content)
+	assert.NoError(t, err)
+	assert.Len(t, patches, 3)
+
+	assert.NoError(t, Check(pristineDir, patches))
+	assert.Error(t, Check(pristineDir, Reverse(patches)))
+	assert.NoError(t, Apply(pristineDir, patches))
+
+	kernel, _ := os.ReadFile(filepath.Join(pristineDir, "Kernel.php"))
+	assert.Contains(t, string(kernel), "// patched")
+
+	added, _ := os.ReadFile(filepath.Join(pristineDir, "Framework", "Added.php"))
+	assert.Equal(t, "<?php\n\necho 1;\n", string(added))
+	assert.NoFileExists(t, filepath.Join(pristineDir, "Framework", "Removed.php"))
+
+	// An applied patch can be reversed, which is used to detect already applied patches
+	assert.Error(t, Check(pristineDir, patches))
+	assert.NoError(t, Check(pristineDir, Reverse(patches)))
+}
+
+func TestApplyWithOffset(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFiles(t, dir, map[string]string{
+		"file.txt": "new first line\na\nb\nc\nd\n",
+	})
+
+	patches, err := ParsePatch(`--- a/file.txt
++++ b/file.txt
+@@ -2,2 +2,2 @@
+ b
+-c
++changed
+`)
+	assert.NoError(t, err)
+	assert.NoError(t, Apply(dir, patches))
+
+	content, _ := os.ReadFile(filepath.Join(dir, "file.txt"))
+	assert.Equal(t, "new first line\na\nb\nchanged\nd\n", string(content))
+}
+
+func TestApplyDoesNotWriteOnFailure(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFiles(t, dir, map[string]string{
+		"a.txt": "a\n",
+		"b.txt": "b\n",
+	})
+
+	patches, err := ParsePatch(`diff --git a/a.txt b/a.txt
+--- a/a.txt
++++ b/a.txt
+@@ -1 +1 @@
+-a
++changed
+diff --git a/b.txt b/b.txt
+--- a/b.txt
++++ b/b.txt
+@@ -1 +1 @@
+-not b
++changed
+`)
+	assert.NoError(t, err)
+	assert.EqualError(t, Apply(dir, patches), "hunk #1 does not apply to b.txt")
+
+	content, _ := os.ReadFile(filepath.Join(dir, "a.txt"))
+	assert.Equal(t, "a\n", string(content))
+}
+
+func TestParsePatchInvalidHunk(t *testing.T) {
+	_, err := ParsePatch("--- a/file.txt\n+++ b/file.txt\n@@ -1,2 +1,2 @@\n a\n")
+	assert.Error(t, err)
+}
+
+func TestApplyKeepsNewlineAtEndOfFile(t *testing.T) {
+	pristineDir := t.TempDir()
+	installedDir := t.TempDir()
+
+	writeFiles(t, pristineDir, map[string]string{
+		"Kernel.php":    "<?php\n\nclass Kernel\n{\n}",
+		"Framework.php": "<?php\n\nclass Framework\n{\n}",
+	})
+
+	writeFiles(t, installedDir, map[string]string{
+		"Kernel.php":    "<?php\n\nclass Kernel\n{\n    // patched\n}",
+		"Framework.php": "<?php\n\nclass Framework\n{\n}\n",
+	})
+
+	changes, err := Compare("shopware/core", installedDir, pristineDir)
+	assert.NoError(t, err)
+
+	content, _, err := CreatePatch(installedDir, pristineDir, changes)
+	assert.NoError(t, err)
+	assert.Contains(t, content, "\n-}\n\\ No newline at end of file\n+}\n")
+
+	patches, err := ParsePatch(content)
+	assert.NoError(t, err)
+	assert.NoError(t, Apply(pristineDir, patches))
+
+	kernel, _ := os.ReadFile(filepath.Join(pristineDir, "Kernel.php"))
+	assert.Equal(t, "<?php\n\nclass Kernel\n{\n    // patched\n}", string(kernel))
+
+	framework, _ := os.ReadFile(filepath.Join(pristineDir, "Framework.php"))
+	assert.Equal(t, "<?php\n\nclass Framework\n{\n}\n", string(framework))
+
+	assert.NoError(t, Apply(pristineDir, Reverse(patches)))
+
+	framework, _ = os.ReadFile(filepath.Join(pristineDir, "Framework.php"))
+	assert.Equal(t, "<?php\n\nclass Framework\n{\n}", string(framework))
+}
+
+func TestApplyKeepsMissingNewlineOfUntouchedEnd(t *testing.T) {
+	patches, err := ParsePatch("--- a/Kernel.php\n+++ b/Kernel.php\n@@ -1,2 +1,2 @@\n-<?php\n+<?php // patched\n \n")
+	assert.NoError(t, err)
+
+	content, err := applyHunks("<?php\n\nclass Kernel {}", patches[0].Hunks, "Kernel.php")
+	assert.NoError(t, err)
+	assert.Equal(t, "<?php // patched\n\nclass Kernel {}", content)
+}
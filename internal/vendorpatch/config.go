@@ -0,0 +1,294 @@
+package vendorpatch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+
+	"github.com/shopware/shopware-cli/internal/packagist"
+	"github.com/shopware/shopware-cli/internal/system"
+)
+
+// PatchEntry is a patch of a package configured for cweagans/composer-patches
+type PatchEntry struct {
+	Package     string
+	Description string
+	// Source is a path relative to the project root or an URL
+	Source string
+}
+
+// PatchConfig are the patches of the project in the order composer-patches applies them
+type PatchConfig struct {
+	// File is the composer.json or the file configured with extra.patches-file
+	File    string
+	Patches []PatchEntry
+
+	projectRoot string
+}
+
+// ReadPatchConfig reads the patches from the composer.json or the file configured in extra.patches-file
+func ReadPatchConfig(projectRoot string) (*PatchConfig, error) {
+	config := &PatchConfig{File: filepath.Join(projectRoot, "composer.json"), projectRoot: projectRoot}
+
+	content, err := os.ReadFile(config.File)
+	if err != nil {
+		return nil, err
+	}
+
+	var composer struct {
+		Extra struct {
+			Patches     json.RawMessage `json:"patches"`
+			PatchesFile string          `json:"patches-file"`
+		} `json:"extra"`
+	}
+
+	if err := json.Unmarshal(content, &composer); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", config.File, err)
+	}
+
+	patches := composer.Extra.Patches
+
+	if composer.Extra.PatchesFile != "" {
+		config.File = filepath.Join(projectRoot, composer.Extra.PatchesFile)
+
+		var patchesFile struct {
+			Patches json.RawMessage `json:"patches"`
+		}
+
+		content, err := os.ReadFile(config.File)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		if err == nil {
+			if err := json.Unmarshal(content, &patchesFile); err != nil {
+				return nil, fmt.Errorf("cannot parse %s: %w", config.File, err)
+			}
+		}
+
+		patches = patchesFile.Patches
+	}
+
+	if len(patches) == 0 {
+		return config, nil
+	}
+
+	config.Patches, err = parsePatchEntries(patches)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the patches of %s: %w", config.File, err)
+	}
+
+	return config, nil
+}
+
+// parsePatchEntries keeps the order of the JSON object, as the patches of a package are applied in this order
+func parsePatchEntries(content json.RawMessage) ([]PatchEntry, error) {
+	packages := orderedmap.New[string, json.RawMessage]()
+
+	if err := json.Unmarshal(content, packages); err != nil {
+		return nil, err
+	}
+
+	entries := []PatchEntry{}
+
+	for pair := packages.Oldest(); pair != nil; pair = pair.Next() {
+		// composer-patches 2 also allows a list of patches with description and url
+		if bytes.HasPrefix(bytes.TrimSpace(pair.Value), []byte("[")) {
+			var list []struct {
+				Description string `json:"description"`
+				URL         string `json:"url"`
+			}
+
+			if err := json.Unmarshal(pair.Value, &list); err != nil {
+				return nil, err
+			}
+
+			for _, patch := range list {
+				entries = append(entries, PatchEntry{Package: pair.Key, Description: patch.Description, Source: patch.URL})
+			}
+
+			continue
+		}
+
+		patches := orderedmap.New[string, string]()
+
+		if err := json.Unmarshal(pair.Value, patches); err != nil {
+			return nil, err
+		}
+
+		for patch := patches.Oldest(); patch != nil; patch = patch.Next() {
+			entries = append(entries, PatchEntry{Package: pair.Key, Description: patch.Key, Source: patch.Value})
+		}
+	}
+
+	return entries, nil
+}
+
+// PackagePatches returns the patches configured for the package
+func (c *PatchConfig) PackagePatches(packageName string) []PatchEntry {
+	patches := []PatchEntry{}
+
+	for _, patch := range c.Patches {
+		if patch.Package == packageName {
+			patches = append(patches, patch)
+		}
+	}
+
+	return patches
+}
+
+// Add appends the patch and writes the configuration
+func (c *PatchConfig) Add(entry PatchEntry) error {
+	for _, patch := range c.PackagePatches(entry.Package) {
+		if patch.Description == entry.Description {
+			return fmt.Errorf("the package %s has already a patch with the description %q", entry.Package, entry.Description)
+		}
+	}
+
+	c.Patches = append(c.Patches, entry)
+
+	patches := orderedmap.New[string, *orderedmap.OrderedMap[string, string]]()
+
+	for _, patch := range c.Patches {
+		packagePatches, ok := patches.Get(patch.Package)
+		if !ok {
+			packagePatches = orderedmap.New[string, string]()
+			patches.Set(patch.Package, packagePatches)
+		}
+
+		description := patch.Description
+		if description == "" {
+			description = patch.Source
+		}
+
+		packagePatches.Set(description, patch.Source)
+	}
+
+	if filepath.Base(c.File) != "composer.json" {
+		content, err := json.MarshalIndent(map[string]any{"patches": patches}, "", "    ")
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(c.File, append(content, '\n'), os.ModePerm)
+	}
+
+	composerJson, err := packagist.ReadComposerJson(c.File)
+	if err != nil {
+		return err
+	}
+
+	composerJson.Extra["patches"] = patches
+
+	return composerJson.Save()
+}
+
+// ReadPatch returns the content of a local patch file or downloads it
+func (c *PatchConfig) ReadPatch(ctx context.Context, entry PatchEntry) (string, error) {
+	if !strings.HasPrefix(entry.Source, "http://") && !strings.HasPrefix(entry.Source, "https://") {
+		file := entry.Source
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(c.projectRoot, file)
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+
+		return string(content), nil
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, entry.Source, http.NoBody)
+	if err != nil {
+		return "", err
+	}
+
+	r.Header.Set("User-Agent", "Shopware CLI")
+
+	resp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("got status code %d from %s", resp.StatusCode, entry.Source)
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+// LockedPackage returns the package of the composer.lock, which needs a zip dist to compare against
+func LockedPackage(projectRoot, packageName string) (*packagist.ComposerLockPackage, error) {
+	lock, err := packagist.ReadComposerLock(filepath.Join(projectRoot, "composer.lock"))
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := lock.GetPackage(packageName)
+	if pkg == nil {
+		return nil, fmt.Errorf("the package %s is not installed", packageName)
+	}
+
+	if pkg.Dist == nil || pkg.Dist.Type != "zip" {
+		return nil, fmt.Errorf("the package %s is not installed from a zip archive", packageName)
+	}
+
+	return pkg, nil
+}
+
+// ParseEntry reads and parses the patch of the entry
+func (c *PatchConfig) ParseEntry(ctx context.Context, entry PatchEntry) ([]FilePatch, error) {
+	content, err := c.ReadPatch(ctx, entry)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read patch %s: %w", entry.Source, err)
+	}
+
+	patches, err := ParsePatch(content)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse patch %s: %w", entry.Source, err)
+	}
+
+	return patches, nil
+}
+
+// PrepareBaseline copies the released package to target and applies the configured patches of the package,
+// which is the state composer install creates in the vendor folder.
+func (c *PatchConfig) PrepareBaseline(ctx context.Context, pkg packagist.ComposerLockPackage, target string) error {
+	pristineDir, err := PristinePackage(ctx, c.projectRoot, pkg)
+	if err != nil {
+		return err
+	}
+
+	if err := system.CopyFiles(pristineDir, target); err != nil {
+		return err
+	}
+
+	for _, entry := range c.PackagePatches(pkg.Name) {
+		patches, err := c.ParseEntry(ctx, entry)
+		if err != nil {
+			return err
+		}
+
+		if err := Apply(target, patches); err != nil {
+			return fmt.Errorf("the patch %q of %s does not apply: %w", entry.Description, pkg.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package vendorpatch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadPatchConfigKeepsOrder(t *testing.T) {
+	projectRoot := t.TempDir()
+
+	writeFiles(t, projectRoot, map[string]string{
+		"composer.json": `{
+    "name": "shopware/production",
+    "extra": {
+        "patches": {
+            "shopware/core": {
+                "Fix checkout": "patches/shopware-core.patch",
+                "Add logging": "https://example.com/logging.patch"
+            },
+            "symfony/http-kernel": [
+                {"description": "Fix cache", "url": "patches/symfony-http-kernel.patch"}
+            ]
+        }
+    }
+}`,
+	})
+
+	config, err := ReadPatchConfig(projectRoot)
+	assert.NoError(t, err)
+	assert.Equal(t, []PatchEntry{
+		{Package: "shopware/core", Description: "Fix checkout", Source: "patches/shopware-core.patch"},
+		{Package: "shopware/core", Description: "Add logging", Source: "https://example.com/logging.patch"},
+		{Package: "symfony/http-kernel", Description: "Fix cache", Source: "patches/symfony-http-kernel.patch"},
+	}, config.Patches)
+	assert.Len(t, config.PackagePatches("shopware/core"), 2)
+}
+
+func TestPatchConfigAddToPatchesFile(t *testing.T) {
+	projectRoot := t.TempDir()
+
+	writeFiles(t, projectRoot, map[string]string{
+		"composer.json":         `{"name": "shopware/production", "extra": {"patches-file": "composer.patches.json"}}`,
+		"composer.patches.json": `{"patches": {"shopware/core": {"Z patch": "patches/z.patch"}}}`,
+	})
+
+	config, err := ReadPatchConfig(projectRoot)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(projectRoot, "composer.patches.json"), config.File)
+
+	assert.NoError(t, config.Add(PatchEntry{Package: "shopware/core", Description: "A patch", Source: "patches/a.patch"}))
+	assert.Error(t, config.Add(PatchEntry{Package: "shopware/core", Description: "A patch", Source: "patches/b.patch"}))
+
+	content, _ := os.ReadFile(config.File)
+	assert.Equal(t, `{
+    "patches": {
+        "shopware/core": {
+            "Z patch": "patches/z.patch",
+            "A patch": "patches/a.patch"
+        }
+    }
+}
+`, string(content))
+}
+
+func TestPatchConfigAddToComposerJson(t *testing.T) {
+	projectRoot := t.TempDir()
+
+	writeFiles(t, projectRoot, map[string]string{
+		"composer.json": `{"name": "shopware/production", "require": {"shopware/core": "6.6.0.0"}}`,
+	})
+
+	config, err := ReadPatchConfig(projectRoot)
+	assert.NoError(t, err)
+	assert.NoError(t, config.Add(PatchEntry{Package: "shopware/core", Description: "Local changes", Source: "patches/shopware-core.patch"}))
+
+	config, err = ReadPatchConfig(projectRoot)
+	assert.NoError(t, err)
+	assert.Equal(t, []PatchEntry{
+		{Package: "shopware/core", Description: "Local changes", Source: "patches/shopware-core.patch"},
+	}, config.Patches)
+}
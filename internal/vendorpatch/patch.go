@@ -0,0 +1,85 @@
+package vendorpatch
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// CreatePatch returns a patch of the changed files which applies with patch -p1 inside the package folder,
+// like cweagans/composer-patches applies them. Binary files cannot be patched and are returned as skipped.
+func CreatePatch(installedDir, pristineDir string, changes []ChangedFile) (string, []string, error) {
+	var patch bytes.Buffer
+
+	skipped := []string{}
+
+	for _, change := range changes {
+		before, err := readPatchFile(pristineDir, change.Path, change.Status != StatusAdded)
+		if err != nil {
+			return "", nil, err
+		}
+
+		after, err := readPatchFile(installedDir, change.Path, change.Status != StatusDeleted)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if !utf8.Valid(before) || !utf8.Valid(after) {
+			skipped = append(skipped, change.Path)
+			continue
+		}
+
+		diff := difflib.UnifiedDiff{
+			A:        splitPatchLines(string(before)),
+			B:        splitPatchLines(string(after)),
+			FromFile: "a/" + change.Path,
+			ToFile:   "b/" + change.Path,
+			Context:  3,
+		}
+
+		switch change.Status {
+		case StatusAdded:
+			diff.FromFile = "/dev/null"
+		case StatusDeleted:
+			diff.ToFile = "/dev/null"
+		}
+
+		patch.WriteString(fmt.Sprintf("diff --git a/%s b/%s\n", change.Path, change.Path))
+
+		if err := difflib.WriteUnifiedDiff(&patch, diff); err != nil {
+			return "", nil, err
+		}
+	}
+
+	return patch.String(), skipped, nil
+}
+
+func readPatchFile(dir, file string, exists bool) ([]byte, error) {
+	if !exists {
+		return []byte{}, nil
+	}
+
+	return os.ReadFile(filepath.Join(dir, filepath.FromSlash(file)))
+}
+
+func splitPatchLines(content string) []string {
+	if content == "" {
+		return []string{}
+	}
+
+	lines := strings.SplitAfter(content, "\n")
+
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+
+	// Like git, the marker makes the last line differ from the same line with a newline
+	lines[len(lines)-1] += "\n\\ No newline at end of file\n"
+
+	return lines
+}
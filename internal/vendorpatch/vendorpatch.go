@@ -0,0 +1,281 @@
+package vendorpatch
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha1" //nolint:gosec
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/internal/packagist"
+	"github.com/shopware/shopware-cli/internal/system"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+const (
+	StatusModified = "modified"
+	StatusAdded    = "added"
+	StatusDeleted  = "deleted"
+)
+
+// defaultIgnoredPaths are generated by the admin and storefront build inside the vendor folder and are no manual changes
+var defaultIgnoredPaths = []string{
+	"Resources/public/",
+	"Resources/app/administration/dist/",
+	"Resources/app/storefront/dist/",
+	"Resources/app/storefront/vendor/",
+}
+
+// ChangedFile is a file of an installed package which differs from the released package
+type ChangedFile struct {
+	Package string
+	Path    string
+	Status  string
+}
+
+// ShopwarePackages returns the packages of vendor/shopware installed from a dist archive
+func ShopwarePackages(projectRoot string) ([]packagist.ComposerLockPackage, error) {
+	lock, err := packagist.ReadComposerLock(path.Join(projectRoot, "composer.lock"))
+	if err != nil {
+		return nil, err
+	}
+
+	packages := []packagist.ComposerLockPackage{}
+
+	for _, pkg := range lock.Packages {
+		if !strings.HasPrefix(pkg.Name, "shopware/") || pkg.Dist == nil || pkg.Dist.Type != "zip" {
+			continue
+		}
+
+		if _, err := os.Stat(path.Join(projectRoot, "vendor", pkg.Name)); err != nil {
+			continue
+		}
+
+		packages = append(packages, pkg)
+	}
+
+	return packages, nil
+}
+
+// PristinePackage returns the path to a cached extraction of the released dist archive of the package.
+// The archive is downloaded once per reference with the credentials of the auth.json of the project and COMPOSER_AUTH,
+// and verified against the shasum of the composer.lock when it has one.
+func PristinePackage(ctx context.Context, projectRoot string, pkg packagist.ComposerLockPackage) (string, error) {
+	if pkg.Dist == nil {
+		return "", fmt.Errorf("package %s has no dist archive", pkg.Name)
+	}
+
+	cacheDir := path.Join(system.GetShopwareCliCacheDir(), "vendor-packages", pkg.Name, pkg.Dist.Reference)
+
+	if _, err := os.Stat(cacheDir); err == nil {
+		logging.FromContext(ctx).Debugf("Using cached package %s", cacheDir)
+		return cacheDir, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cacheDir), os.ModePerm); err != nil {
+		return "", err
+	}
+
+	// Extract into a temporary folder first, so an interrupted download does not leave a broken cache entry
+	tempDir, err := os.MkdirTemp(filepath.Dir(cacheDir), "download-*")
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = os.RemoveAll(tempDir) }()
+
+	auth, err := packagist.ReadComposerAuth(path.Join(projectRoot, "auth.json"))
+	if err != nil {
+		return "", fmt.Errorf("cannot read auth.json: %w", err)
+	}
+
+	logging.FromContext(ctx).Infof("Downloading %s %s", pkg.Name, pkg.Version)
+
+	archive := filepath.Join(tempDir, "package.zip")
+
+	shasum, err := downloadFile(ctx, auth, pkg.Dist.URL, archive)
+	if err != nil {
+		return "", fmt.Errorf("cannot download %s: %w", pkg.Name, err)
+	}
+
+	if pkg.Dist.Shasum != "" && !strings.EqualFold(pkg.Dist.Shasum, shasum) {
+		return "", fmt.Errorf("the archive of %s has the shasum %s, but the composer.lock expects %s", pkg.Name, shasum, pkg.Dist.Shasum)
+	}
+
+	reader, err := zip.OpenReader(archive)
+	if err != nil {
+		return "", fmt.Errorf("cannot open archive of %s: %w", pkg.Name, err)
+	}
+
+	extracted := filepath.Join(tempDir, "package")
+
+	err = extension.Unzip(&reader.Reader, extracted)
+	_ = reader.Close()
+
+	if err != nil {
+		return "", err
+	}
+
+	root, err := archiveRoot(extracted)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(root, cacheDir); err != nil {
+		return "", err
+	}
+
+	return cacheDir, nil
+}
+
+// archiveRoot returns the single top level folder of GitHub archives like shopware-core-abc123, otherwise the folder itself
+func archiveRoot(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	if len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(dir, entries[0].Name()), nil
+	}
+
+	return dir, nil
+}
+
+// downloadFile writes the url to target and returns the SHA-1 of the content like the shasum of composer
+func downloadFile(ctx context.Context, auth *packagist.ComposerAuth, url, target string) (string, error) {
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return "", err
+	}
+
+	r.Header.Set("User-Agent", "Shopware CLI")
+	auth.AuthorizeRequest(r)
+
+	resp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("got status code %d from %s", resp.StatusCode, url)
+	}
+
+	file, err := os.Create(target)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha1.New()
+
+	if _, err := io.Copy(io.MultiWriter(file, hash), resp.Body); err != nil {
+		_ = file.Close()
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), file.Close()
+}
+
+// Compare returns the files of the installed package which are modified, added or deleted compared to the pristine package
+func Compare(packageName, installedDir, pristineDir string) ([]ChangedFile, error) {
+	installed, err := listFiles(installedDir)
+	if err != nil {
+		return nil, err
+	}
+
+	pristine, err := listFiles(pristineDir)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []ChangedFile{}
+
+	for file := range pristine {
+		if !installed[file] {
+			changes = append(changes, ChangedFile{Package: packageName, Path: file, Status: StatusDeleted})
+			continue
+		}
+
+		installedChecksum, err := extension.ChecksumFile(filepath.Join(installedDir, file))
+		if err != nil {
+			return nil, err
+		}
+
+		pristineChecksum, err := extension.ChecksumFile(filepath.Join(pristineDir, file))
+		if err != nil {
+			return nil, err
+		}
+
+		if installedChecksum != pristineChecksum {
+			changes = append(changes, ChangedFile{Package: packageName, Path: file, Status: StatusModified})
+		}
+	}
+
+	for file := range installed {
+		if !pristine[file] {
+			changes = append(changes, ChangedFile{Package: packageName, Path: file, Status: StatusAdded})
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b ChangedFile) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return changes, nil
+}
+
+// listFiles returns the files of the folder relative to it, without node_modules and build output
+func listFiles(dir string) (map[string]bool, error) {
+	files := map[string]bool{}
+
+	err := filepath.WalkDir(dir, func(file string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if d.Name() == "node_modules" || d.Name() == ".git" || isIgnoredPath(rel+"/") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		files[rel] = true
+
+		return nil
+	})
+
+	return files, err
+}
+
+func isIgnoredPath(rel string) bool {
+	for _, ignored := range defaultIgnoredPaths {
+		if strings.HasPrefix(rel, ignored) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,134 @@
+package vendorpatch
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1" //nolint:gosec
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/packagist"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for file, content := range files {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, file)), os.ModePerm))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(content), os.ModePerm))
+	}
+}
+
+func TestCompareDetectsChangedFiles(t *testing.T) {
+	pristineDir := t.TempDir()
+	installedDir := t.TempDir()
+
+	writeFiles(t, pristineDir, map[string]string{
+		"Kernel.php":                      "<?php\n\nclass Kernel\n{\n}\n",
+		"Framework/Removed.php":           "<?php\n",
+		"Resources/public/static/app.js":  "console.log(1);\n",
+		"Framework/Unchanged/Service.php": "<?php\n",
+	})
+
+	writeFiles(t, installedDir, map[string]string{
+		"Kernel.php":                      "<?php\n\nclass Kernel\n{\n    // patched\n}\n",
+		"Framework/Added.php":             "<?php\n",
+		"Resources/public/static/app.js":  "console.log(2);\n",
+		"Resources/app/node_modules/a.js": "",
+		"Framework/Unchanged/Service.php": "<?php\n",
+	})
+
+	changes, err := Compare("shopware/core", installedDir, pristineDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []ChangedFile{
+		{Package: "shopware/core", Path: "Framework/Added.php", Status: StatusAdded},
+		{Package: "shopware/core", Path: "Framework/Removed.php", Status: StatusDeleted},
+		{Package: "shopware/core", Path: "Kernel.php", Status: StatusModified},
+	}, changes)
+
+	patch, skipped, err := CreatePatch(installedDir, pristineDir, changes)
+	assert.NoError(t, err)
+	assert.Empty(t, skipped)
+	assert.Equal(t, `diff --git a/Framework/Added.php b/Framework/Added.php
+--- /dev/null
++++ b/Framework/Added.php
+@@ -0,0 +1 @@
++<?php
+diff --git a/Framework/Removed.php b/Framework/Removed.php
+--- a/Framework/Removed.php
++++ /dev/null
+@@ -1 +0,0 @@
+-<?php
+diff --git a/Kernel.php b/Kernel.php
+--- a/Kernel.php
++++ b/Kernel.php
+@@ -2,4 +2,5 @@
+ 
+ class Kernel
+ {
++    // patched
+ }
+`, patch)
+}
+
+func TestArchiveRootUsesSingleFolder(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"shopware-core-abc123/composer.json": "{}"})
+
+	root, err := archiveRoot(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "shopware-core-abc123"), root)
+}
+
+func TestPristinePackageUsesAuthAndChecksShasum(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("COMPOSER_AUTH", "")
+	t.Setenv("SHOPWARE_PACKAGES_TOKEN", "")
+
+	var archive bytes.Buffer
+	writer := zip.NewWriter(&archive)
+	file, err := writer.Create("shopware-core-abc123/composer.json")
+	require.NoError(t, err)
+	_, _ = file.Write([]byte("{}"))
+	require.NoError(t, writer.Close())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = w.Write(archive.Bytes())
+	}))
+	defer server.Close()
+
+	projectRoot := t.TempDir()
+	writeFiles(t, projectRoot, map[string]string{"auth.json": `{"bearer": {"127.0.0.1": "secret"}}`})
+
+	hash := sha1.Sum(archive.Bytes()) //nolint:gosec
+
+	pkg := packagist.ComposerLockPackage{Name: "shopware/core", Version: "6.6.0.0", Dist: &packagist.ComposerLockPackageDist{
+		Type: "zip", URL: server.URL, Reference: "abc123", Shasum: "0000000000000000000000000000000000000000",
+	}}
+
+	_, err = PristinePackage(t.Context(), projectRoot, pkg)
+	assert.ErrorContains(t, err, "the archive of shopware/core has the shasum "+hex.EncodeToString(hash[:]))
+
+	pkg.Dist.Shasum = hex.EncodeToString(hash[:])
+
+	dir, err := PristinePackage(t.Context(), projectRoot, pkg)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "composer.json"))
+
+	pkg.Dist.Reference = "def456"
+
+	_, err = PristinePackage(t.Context(), t.TempDir(), pkg)
+	assert.ErrorContains(t, err, "got status code 401")
+}
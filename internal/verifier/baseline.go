@@ -0,0 +1,181 @@
+package verifier
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultBaselineFile is used when validation.baseline is not configured
+const DefaultBaselineFile = ".shopware-extension-baseline.yml"
+
+// baselineLineRegExp matches line numbers in messages like Resources/config/config.xml:12:, they change with every edit of the file
+var baselineLineRegExp = regexp.MustCompile(`(\.\w+):\d+(?::\d+)?\b`)
+
+// Baseline contains the accepted findings of an extension, which are not reported anymore
+type Baseline struct {
+	Findings []BaselineFinding `yaml:"findings"`
+}
+
+type BaselineFinding struct {
+	Identifier string `yaml:"identifier"`
+	Path       string `yaml:"path,omitempty"`
+	Message    string `yaml:"message"`
+	// How often the finding occurs, additional occurrences are reported
+	Count int `yaml:"count,omitempty"`
+	// Why the finding is accepted, it is required
+	Reason string `yaml:"reason"`
+}
+
+// NewBaseline creates a baseline of all findings of the check. The reasons of the previous baseline are kept for findings which are still present.
+func NewBaseline(check *Check, previous *Baseline) *Baseline {
+	baseline := &Baseline{Findings: []BaselineFinding{}}
+
+	for _, result := range check.Results {
+		message := normalizeBaselineMessage(result.Message)
+
+		index := slices.IndexFunc(baseline.Findings, func(f BaselineFinding) bool {
+			return f.Identifier == result.Identifier && f.Path == result.Path && f.Message == message
+		})
+
+		if index != -1 {
+			baseline.Findings[index].Count++
+			continue
+		}
+
+		baseline.Findings = append(baseline.Findings, BaselineFinding{
+			Identifier: result.Identifier,
+			Path:       result.Path,
+			Message:    message,
+			Count:      1,
+		})
+	}
+
+	if previous != nil {
+		for i, finding := range baseline.Findings {
+			for _, old := range previous.Findings {
+				if old.Identifier == finding.Identifier && old.Path == finding.Path && old.Message == finding.Message {
+					baseline.Findings[i].Reason = old.Reason
+					break
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(baseline.Findings, func(a, b BaselineFinding) int {
+		if c := strings.Compare(a.Identifier, b.Identifier); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return baseline
+}
+
+// ReadBaseline reads the baseline file, a missing file is an empty baseline
+func ReadBaseline(file string) (*Baseline, error) {
+	baseline := &Baseline{}
+
+	content, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return baseline, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(content, baseline); err != nil {
+		return nil, fmt.Errorf("cannot parse baseline %s: %w", file, err)
+	}
+
+	return baseline, nil
+}
+
+// Validate returns an error when findings are accepted without reason
+func (b *Baseline) Validate() error {
+	var missing []string
+
+	for _, finding := range b.Findings {
+		if strings.TrimSpace(finding.Reason) == "" {
+			missing = append(missing, fmt.Sprintf("%s (%s)", finding.Identifier, cmp.Or(finding.Path, finding.Message)))
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("the baseline accepts %d finding(s) without reason, add a reason to: %s", len(missing), strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// ValidateIgnoreReasons returns an error when findings are ignored without reason, like the findings of the baseline
+func ValidateIgnoreReasons(ignores []ToolConfigIgnore) error {
+	var missing []string
+
+	for _, ignore := range ignores {
+		if strings.TrimSpace(ignore.Reason) == "" {
+			missing = append(missing, cmp.Or(ignore.Identifier, ignore.Message))
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%d ignore(s) without reason, add a reason to: %s", len(missing), strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// Write saves the baseline to the file
+func (b *Baseline) Write(file string) error {
+	content, err := yaml.Marshal(b)
+	if err != nil {
+		return err
+	}
+
+	header := "# Accepted findings of extension validate, each one needs a reason. Regenerate this file with extension validate --generate-baseline, the reasons are kept\n"
+
+	return os.WriteFile(file, append([]byte(header), content...), os.ModePerm)
+}
+
+// RemoveBaselined removes the findings of the baseline, a finding is removed as often as the baseline counted it
+func (c *Check) RemoveBaselined(baseline *Baseline) *Check {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	remaining := make(map[BaselineFinding]int, len(baseline.Findings))
+
+	for _, finding := range baseline.Findings {
+		count := max(finding.Count, 1)
+		finding.Count = 0
+		finding.Reason = ""
+		remaining[finding] += count
+	}
+
+	filtered := make([]CheckResult, 0, len(c.Results))
+
+	for _, r := range c.Results {
+		key := BaselineFinding{Identifier: r.Identifier, Path: r.Path, Message: normalizeBaselineMessage(r.Message)}
+
+		if remaining[key] > 0 {
+			remaining[key]--
+			continue
+		}
+
+		filtered = append(filtered, r)
+	}
+
+	c.Results = filtered
+
+	return c
+}
+
+func normalizeBaselineMessage(message string) string {
+	return baselineLineRegExp.ReplaceAllString(message, "$1")
+}
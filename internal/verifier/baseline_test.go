@@ -0,0 +1,79 @@
+package verifier
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaselineRemovesAcceptedFindings(t *testing.T) {
+	check := NewCheck()
+	check.AddResult(CheckResult{Identifier: "twig.unknown_block", Message: "Resources/views/base.html.twig:12: the block foo does not exist", Severity: "warning"})
+	check.AddResult(CheckResult{Identifier: "phpstan/method.notFound", Path: "src/Service.php", Line: 3, Message: "Call to an undefined method", Severity: "error"})
+	check.AddResult(CheckResult{Identifier: "phpstan/method.notFound", Path: "src/Service.php", Line: 8, Message: "Call to an undefined method", Severity: "error"})
+
+	file := filepath.Join(t.TempDir(), DefaultBaselineFile)
+
+	assert.NoError(t, NewBaseline(check, nil).Write(file))
+
+	baseline, err := ReadBaseline(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []BaselineFinding{
+		{Identifier: "phpstan/method.notFound", Path: "src/Service.php", Message: "Call to an undefined method", Count: 2},
+		{Identifier: "twig.unknown_block", Message: "Resources/views/base.html.twig: the block foo does not exist", Count: 1},
+	}, baseline.Findings)
+
+	assert.EqualError(t, baseline.Validate(), "the baseline accepts 2 finding(s) without reason, add a reason to: phpstan/method.notFound (src/Service.php), twig.unknown_block (Resources/views/base.html.twig: the block foo does not exist)")
+
+	baseline.Findings[0].Reason = "The method is added by a decorator"
+	baseline.Findings[1].Reason = "The block exists in the theme"
+	assert.NoError(t, baseline.Validate())
+
+	// The lines changed and a new occurrence was added
+	next := NewCheck()
+	next.AddResult(CheckResult{Identifier: "twig.unknown_block", Message: "Resources/views/base.html.twig:15: the block foo does not exist", Severity: "warning"})
+	next.AddResult(CheckResult{Identifier: "phpstan/method.notFound", Path: "src/Service.php", Line: 4, Message: "Call to an undefined method", Severity: "error"})
+	next.AddResult(CheckResult{Identifier: "phpstan/method.notFound", Path: "src/Service.php", Line: 9, Message: "Call to an undefined method", Severity: "error"})
+	next.AddResult(CheckResult{Identifier: "phpstan/method.notFound", Path: "src/Service.php", Line: 20, Message: "Call to an undefined method", Severity: "error"})
+
+	next.RemoveBaselined(baseline)
+
+	assert.Equal(t, []CheckResult{
+		{Identifier: "phpstan/method.notFound", Path: "src/Service.php", Line: 20, Message: "Call to an undefined method", Severity: "error"},
+	}, next.Results)
+}
+
+func TestReadMissingBaseline(t *testing.T) {
+	baseline, err := ReadBaseline(filepath.Join(t.TempDir(), "missing.yml"))
+	assert.NoError(t, err)
+	assert.Empty(t, baseline.Findings)
+}
+
+func TestNewBaselineKeepsReasons(t *testing.T) {
+	previous := &Baseline{Findings: []BaselineFinding{
+		{Identifier: "phpstan/method.notFound", Path: "src/Service.php", Message: "Call to an undefined method", Count: 1, Reason: "The method is added by a decorator"},
+		{Identifier: "phpstan/class.notFound", Path: "src/Removed.php", Message: "Class not found", Count: 1, Reason: "Fixed already"},
+	}}
+
+	check := NewCheck()
+	check.AddResult(CheckResult{Identifier: "phpstan/method.notFound", Path: "src/Service.php", Line: 3, Message: "Call to an undefined method", Severity: "error"})
+	check.AddResult(CheckResult{Identifier: "phpstan/method.notFound", Path: "src/Service.php", Line: 8, Message: "Call to an undefined method", Severity: "error"})
+	check.AddResult(CheckResult{Identifier: "php.syntax", Path: "src/New.php", Message: "Syntax error", Severity: "error"})
+
+	assert.Equal(t, []BaselineFinding{
+		{Identifier: "php.syntax", Path: "src/New.php", Message: "Syntax error", Count: 1},
+		{Identifier: "phpstan/method.notFound", Path: "src/Service.php", Message: "Call to an undefined method", Count: 2, Reason: "The method is added by a decorator"},
+	}, NewBaseline(check, previous).Findings)
+}
+
+func TestValidateIgnoreReasons(t *testing.T) {
+	assert.NoError(t, ValidateIgnoreReasons([]ToolConfigIgnore{{Identifier: "metadata.setup", Reason: "The secret is set while zipping"}}))
+
+	err := ValidateIgnoreReasons([]ToolConfigIgnore{
+		{Identifier: "metadata.setup", Reason: "The secret is set while zipping"},
+		{Identifier: "phpstan/class.notFinal"},
+		{Message: "is deprecated", Reason: " "},
+	})
+	assert.EqualError(t, err, "2 ignore(s) without reason, add a reason to: phpstan/class.notFinal, is deprecated")
+}
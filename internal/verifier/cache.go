@@ -0,0 +1,255 @@
+package verifier
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shopware/shopware-cli/internal/system"
+)
+
+// resultCacheMaxAge is how long an entry is kept after it was last used
+const resultCacheMaxAge = 7 * 24 * time.Hour
+
+// toolCacheInputs lists the files a tool reads as patterns matched against the file name. The results of a tool are
+// only invalidated by changes to these files, tools without an entry depend on every file.
+var toolCacheInputs = map[string][]string{
+	"phpstan":           {"*.php", "*.neon", "*.neon.dist", "composer.json", "composer.lock", "installed.json"},
+	"phpstan-phar":      {"*.php", "*.neon", "*.neon.dist", "composer.json", "composer.lock", "installed.json"},
+	"rector":            {"*.php", "composer.json", "composer.lock", "installed.json"},
+	"php-cs-fixer":      {"*.php", ".php-cs-fixer*", "composer.json", "composer.lock", "installed.json"},
+	"container-compile": {"*.php", "*.xml", "*.yaml", "*.yml", "composer.json", "composer.lock", "installed.json"},
+	"eslint":            {"*.js", "*.mjs", "*.cjs", "*.ts", "*.vue", "*.json", ".eslintrc*"},
+	"stylelint":         {"*.css", "*.scss", "*.less", "*.json", ".stylelintrc*"},
+	"admin-twig":        {"*.twig"},
+}
+
+// cacheExcludeDirs are dependency folders, their content is defined by the composer and npm files
+var cacheExcludeDirs = []string{".git", "vendor", "node_modules"}
+
+// installedDependencyFiles list the installed versions of the excluded dependency folders, they are hashed instead of
+// the folders, so updated dependencies invalidate the results also without a lock file
+var installedDependencyFiles = []string{"vendor/composer/installed.json", "node_modules/.package-lock.json"}
+
+// ResultCache stores the results of a tool run keyed by the CLI version, the
+// tool configuration and the content of the files the tool reads below the root
+// directory. Any change to one of them results in a different key, so stale
+// entries are never read. Entries unused for a week are removed.
+type ResultCache struct {
+	dir   string
+	base  string
+	files []cachedFile
+}
+
+type cachedFile struct {
+	path string
+	sum  string
+}
+
+// NewResultCache hashes the files below the root directory. Development builds have the version dev, so the
+// binary itself is hashed instead, as the results change with every build.
+func NewResultCache(cacheDir string, cliVersion string, config ToolConfig) (*ResultCache, error) {
+	if cliVersion == "" || cliVersion == "dev" {
+		if executable, err := os.Executable(); err == nil {
+			if sum, err := hashFile(executable); err == nil {
+				cliVersion = "dev-" + sum
+			}
+		}
+	}
+
+	h := sha256.New()
+
+	_, _ = fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%t\x00%t\n", cliVersion, config.CheckAgainst, config.MinShopwareVersion, config.MaxShopwareVersion, config.InputWasDirectory, config.RunExternalValidators)
+
+	files, err := hashTree(config.RootDir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot hash files of %s: %w", config.RootDir, err)
+	}
+
+	pruneResultCache(cacheDir, time.Now().Add(-resultCacheMaxAge))
+
+	return &ResultCache{
+		dir:   cacheDir,
+		base:  hex.EncodeToString(h.Sum(nil)),
+		files: files,
+	}, nil
+}
+
+// Get returns the cached results of the given tool. The second return value is false if there is no entry.
+func (c *ResultCache) Get(tool Tool) ([]CheckResult, bool) {
+	file := c.file(tool)
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, false
+	}
+
+	var results []CheckResult
+
+	if err := json.Unmarshal(content, &results); err != nil {
+		return nil, false
+	}
+
+	// Used entries are kept by the pruning
+	now := time.Now()
+	_ = os.Chtimes(file, now, now)
+
+	return results, true
+}
+
+func (c *ResultCache) Set(tool Tool, results []CheckResult) error {
+	if results == nil {
+		results = []CheckResult{}
+	}
+
+	content, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(c.dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.file(tool), content, 0o644)
+}
+
+func (c *ResultCache) file(tool Tool) string {
+	return filepath.Join(c.dir, tool.Name()+"-"+c.key(tool)+".json")
+}
+
+// key hashes the configuration with the files the tool reads
+func (c *ResultCache) key(tool Tool) string {
+	h := sha256.New()
+
+	_, _ = io.WriteString(h, c.base+"\x00"+tool.Name()+"\n")
+
+	patterns, filtered := toolCacheInputs[tool.Name()]
+
+	for _, file := range c.files {
+		if filtered && !matchesCacheInput(file.path, patterns) {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(h, "%s\x00%s\n", file.path, file.sum)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func matchesCacheInput(file string, patterns []string) bool {
+	name := path.Base(file)
+
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pruneResultCache removes the entries last used before the given time
+func pruneResultCache(dir string, before time.Time) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.ModTime().Before(before) {
+			continue
+		}
+
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), ".json") {
+			_ = os.RemoveAll(filepath.Join(dir, entry.Name()))
+		}
+	}
+}
+
+func hashTree(root string) ([]cachedFile, error) {
+	entries, err := system.Walk(root, system.WalkOptions{ExcludeDirs: cacheExcludeDirs})
+	if err != nil {
+		return nil, err
+	}
+
+	var files []cachedFile
+
+	dirs := []string{"."}
+
+	for _, entry := range entries {
+		if entry.Entry.IsDir() {
+			dirs = append(dirs, entry.Path)
+			continue
+		}
+
+		sum, err := hashFile(filepath.Join(root, filepath.FromSlash(entry.Path)))
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, cachedFile{path: entry.Path, sum: sum})
+	}
+
+	for _, dir := range dirs {
+		for _, installed := range installedDependencyFiles {
+			file := path.Join(dir, installed)
+
+			sum, err := hashFile(filepath.Join(root, filepath.FromSlash(file)))
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			files = append(files, cachedFile{path: file, sum: sum})
+		}
+	}
+
+	return files, nil
+}
+
+func hashFile(file string) (string, error) {
+	info, err := os.Lstat(file)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(file)
+		if err != nil {
+			return "", err
+		}
+
+		_, _ = io.WriteString(h, "symlink:"+target)
+
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,175 @@
+package verifier
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingTool struct {
+	name  string
+	calls int
+}
+
+func (c *countingTool) Name() string {
+	if c.name != "" {
+		return c.name
+	}
+
+	return "counting"
+}
+
+func (c *countingTool) Check(_ context.Context, check *Check, _ ToolConfig) error {
+	c.calls++
+	check.AddResult(CheckResult{Path: "a.php", Line: 1, Message: "test", Severity: CheckSeverityError, Identifier: "TEST001"})
+
+	return nil
+}
+
+func (c *countingTool) Fix(_ context.Context, _ ToolConfig) error {
+	return nil
+}
+
+func (c *countingTool) Format(_ context.Context, _ ToolConfig, _ bool) error {
+	return nil
+}
+
+func TestResultCache(t *testing.T) {
+	rootDir := t.TempDir()
+	cacheDir := t.TempDir()
+
+	assert.NoError(t, os.WriteFile(filepath.Join(rootDir, "a.php"), []byte("<?php"), 0644))
+
+	tool := &countingTool{}
+	tools := ToolList{tool}
+	cfg := ToolConfig{RootDir: rootDir, CheckAgainst: "highest"}
+
+	run := func() *Check {
+		cache, err := NewResultCache(cacheDir, "1.0.0", cfg)
+		assert.NoError(t, err)
+
+		result, err := tools.Run(t.Context(), cfg, cache)
+		assert.NoError(t, err)
+
+		return result
+	}
+
+	first := run()
+	assert.Len(t, first.Results, 1)
+	assert.Equal(t, 1, tool.calls)
+
+	second := run()
+	assert.Equal(t, first.Results, second.Results)
+	assert.Equal(t, 1, tool.calls)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(rootDir, "a.php"), []byte("<?php echo 1;"), 0644))
+
+	run()
+	assert.Equal(t, 2, tool.calls)
+
+	cfg.CheckAgainst = "lowest"
+
+	run()
+	assert.Equal(t, 3, tool.calls)
+}
+
+func TestResultCacheOnlyHashesToolInputs(t *testing.T) {
+	rootDir := t.TempDir()
+	cacheDir := t.TempDir()
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(rootDir, "vendor", "foo"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(rootDir, "a.php"), []byte("<?php"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(rootDir, "main.js"), []byte("a"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(rootDir, "vendor", "foo", "b.php"), []byte("<?php"), 0644))
+
+	phpstan := &countingTool{name: "phpstan"}
+	other := &countingTool{}
+	cfg := ToolConfig{RootDir: rootDir}
+
+	run := func() {
+		cache, err := NewResultCache(cacheDir, "1.0.0", cfg)
+		assert.NoError(t, err)
+
+		_, err = ToolList{phpstan, other}.Run(t.Context(), cfg, cache)
+		assert.NoError(t, err)
+	}
+
+	run()
+	assert.Equal(t, 1, phpstan.calls)
+	assert.Equal(t, 1, other.calls)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(rootDir, "main.js"), []byte("b"), 0644))
+
+	run()
+	assert.Equal(t, 1, phpstan.calls)
+	assert.Equal(t, 2, other.calls)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(rootDir, "vendor", "foo", "b.php"), []byte("<?php echo 1;"), 0644))
+
+	run()
+	assert.Equal(t, 1, phpstan.calls)
+	assert.Equal(t, 2, other.calls)
+
+	// Installed dependency versions are part of the key, also without a lock file
+	assert.NoError(t, os.MkdirAll(filepath.Join(rootDir, "vendor", "composer"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(rootDir, "vendor", "composer", "installed.json"), []byte(`{"packages": []}`), 0644))
+
+	run()
+	assert.Equal(t, 2, phpstan.calls)
+	assert.Equal(t, 3, other.calls)
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(rootDir, "src", "node_modules"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(rootDir, "src", "node_modules", ".package-lock.json"), []byte(`{}`), 0644))
+
+	run()
+	assert.Equal(t, 2, phpstan.calls)
+	assert.Equal(t, 4, other.calls)
+}
+
+func TestResultCachePrunesUnusedEntries(t *testing.T) {
+	cacheDir := t.TempDir()
+	old := time.Now().Add(-2 * resultCacheMaxAge)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(cacheDir, "old.json"), []byte("[]"), 0644))
+	assert.NoError(t, os.Chtimes(filepath.Join(cacheDir, "old.json"), old, old))
+	assert.NoError(t, os.WriteFile(filepath.Join(cacheDir, "new.json"), []byte("[]"), 0644))
+
+	_, err := NewResultCache(cacheDir, "1.0.0", ToolConfig{RootDir: t.TempDir()})
+	assert.NoError(t, err)
+
+	assert.NoFileExists(t, filepath.Join(cacheDir, "old.json"))
+	assert.FileExists(t, filepath.Join(cacheDir, "new.json"))
+}
+
+func TestResultCacheHashesDevelopmentBuilds(t *testing.T) {
+	cfg := ToolConfig{RootDir: t.TempDir()}
+
+	dev, err := NewResultCache(t.TempDir(), "dev", cfg)
+	assert.NoError(t, err)
+
+	release, err := NewResultCache(t.TempDir(), "1.0.0", cfg)
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, release.base, dev.base)
+
+	devAgain, err := NewResultCache(t.TempDir(), "dev", cfg)
+	assert.NoError(t, err)
+
+	assert.Equal(t, dev.base, devAgain.base)
+}
+
+func TestToolListRunWithoutCache(t *testing.T) {
+	tool := &countingTool{}
+
+	_, err := ToolList{tool}.Run(t.Context(), ToolConfig{RootDir: t.TempDir()}, nil)
+	assert.NoError(t, err)
+
+	_, err = ToolList{tool}.Run(t.Context(), ToolConfig{RootDir: t.TempDir()}, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, tool.calls)
+}
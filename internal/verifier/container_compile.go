@@ -0,0 +1,157 @@
+package verifier
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// DefaultContainerCompileImage contains PHP with all extensions Shopware requires
+const DefaultContainerCompileImage = "ghcr.io/shopware/docker-base:8.3"
+
+const containerCompileMountPath = "/extension"
+
+//go:embed php/container-compile.php
+var containerCompileScript []byte
+
+// ContainerCompile boots a Shopware kernel with only this extension enabled and compiles the DI container in a
+// disposable Docker container without network. It finds errors static checks can't, like circular services or invalid tags.
+type ContainerCompile struct {
+	// Image is the Docker image with PHP, defaults to DefaultContainerCompileImage
+	Image string
+}
+
+type containerCompileOutput struct {
+	Success bool   `json:"success"`
+	Class   string `json:"class"`
+	Message string `json:"message"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+}
+
+func (c ContainerCompile) Name() string {
+	return "container-compile"
+}
+
+func (c ContainerCompile) Check(ctx context.Context, check *Check, config ToolConfig) error {
+	// Only plugins register services into the container
+	if config.Extension == nil || config.Extension.GetType() != extension.TypePlatformPlugin {
+		return nil
+	}
+
+	if _, err := exec.LookPath("docker"); err != nil {
+		return fmt.Errorf("compiling the container requires Docker: %w", err)
+	}
+
+	name, err := config.Extension.GetName()
+	if err != nil {
+		return err
+	}
+
+	// Installs shopware/core matching the composer constraint, the sandbox has no network access
+	if err := installComposerDeps(config.RootDir, config.CheckAgainst); err != nil {
+		return err
+	}
+
+	script, err := os.CreateTemp("", "container-compile-*.php")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.Remove(script.Name()) }()
+
+	if _, err := script.Write(containerCompileScript); err != nil {
+		_ = script.Close()
+		return err
+	}
+
+	if err := script.Close(); err != nil {
+		return err
+	}
+
+	image := c.Image
+	if image == "" {
+		image = DefaultContainerCompileImage
+	}
+
+	logging.FromContext(ctx).Infof("Compiling the container of %s in %s", name, image)
+
+	docker := exec.CommandContext(ctx, "docker", containerCompileArguments(image, config.RootDir, script.Name(), name)...)
+
+	var stderr bytes.Buffer
+	docker.Stderr = &stderr
+
+	output, runErr := docker.Output()
+
+	var result containerCompileOutput
+	if err := json.Unmarshal(output, &result); err != nil {
+		if runErr != nil {
+			return fmt.Errorf("compile container: %w: %s", runErr, strings.TrimSpace(stderr.String()))
+		}
+
+		return fmt.Errorf("compile container: unexpected output %s", strings.TrimSpace(string(output)))
+	}
+
+	if !result.Success {
+		check.AddResult(newContainerCompileResult(result))
+	}
+
+	return nil
+}
+
+func containerCompileArguments(image, rootDir, script, name string) []string {
+	arguments := []string{
+		"run", "--rm", "--network", "none",
+		"--volume", rootDir + ":" + containerCompileMountPath,
+		"--volume", script + ":/container-compile.php:ro",
+		"--workdir", containerCompileMountPath,
+		"--env", "SHOPWARE_CLI_PLUGIN_ROOT=" + containerCompileMountPath,
+		"--env", "SHOPWARE_CLI_PLUGIN_NAME=" + name,
+	}
+
+	// The kernel writes its cache into the mounted folder, which has to stay writable for the current user
+	if runtime.GOOS == "linux" {
+		arguments = append(arguments, "--user", fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid()))
+	}
+
+	return append(arguments, "--entrypoint", "php", image, "-d", "memory_limit=2G", "/container-compile.php")
+}
+
+// newContainerCompileResult points to the file of the extension, which caused the error. Errors of vendor files are
+// reported without location, as the message names the service.
+func newContainerCompileResult(output containerCompileOutput) CheckResult {
+	result := CheckResult{
+		Message:    fmt.Sprintf("The DI container cannot be compiled: %s (%s)", output.Message, path.Base(strings.ReplaceAll(output.Class, "\\", "/"))),
+		Severity:   CheckSeverityError,
+		Identifier: "container.compile",
+	}
+
+	relPath, err := filepath.Rel(containerCompileMountPath, output.File)
+	if err != nil || strings.HasPrefix(relPath, "..") || strings.HasPrefix(relPath, "vendor/") {
+		return result
+	}
+
+	result.Path = filepath.ToSlash(relPath)
+	result.Line = output.Line
+
+	return result
+}
+
+func (c ContainerCompile) Fix(ctx context.Context, config ToolConfig) error {
+	return nil
+}
+
+func (c ContainerCompile) Format(ctx context.Context, config ToolConfig, dryRun bool) error {
+	return nil
+}
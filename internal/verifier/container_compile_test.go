@@ -0,0 +1,45 @@
+package verifier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainerCompileArguments(t *testing.T) {
+	arguments := containerCompileArguments("php:8.3-cli", "/home/user/FroshTools", "/tmp/container-compile-1.php", "FroshTools")
+
+	assert.Equal(t, []string{"run", "--rm", "--network", "none"}, arguments[:4])
+	assert.Contains(t, arguments, "/home/user/FroshTools:/extension")
+	assert.Contains(t, arguments, "/tmp/container-compile-1.php:/container-compile.php:ro")
+	assert.Contains(t, arguments, "SHOPWARE_CLI_PLUGIN_NAME=FroshTools")
+	assert.Equal(t, []string{"--entrypoint", "php", "php:8.3-cli", "-d", "memory_limit=2G", "/container-compile.php"}, arguments[len(arguments)-6:])
+}
+
+func TestNewContainerCompileResultOfExtensionFile(t *testing.T) {
+	result := newContainerCompileResult(containerCompileOutput{
+		Class:   "Symfony\\Component\\DependencyInjection\\Exception\\ServiceCircularReferenceException",
+		Message: "Circular reference detected for service \"FroshTools\\Service\\A\", path: \"FroshTools\\Service\\A -> FroshTools\\Service\\B -> FroshTools\\Service\\A\".",
+		File:    "/extension/src/Resources/config/services.xml",
+		Line:    12,
+	})
+
+	assert.Equal(t, "container.compile", result.Identifier)
+	assert.Equal(t, CheckSeverityError, result.Severity)
+	assert.Equal(t, "src/Resources/config/services.xml", result.Path)
+	assert.Equal(t, 12, result.Line)
+	assert.Contains(t, result.Message, "(ServiceCircularReferenceException)")
+}
+
+func TestNewContainerCompileResultOfVendorFile(t *testing.T) {
+	result := newContainerCompileResult(containerCompileOutput{
+		Class:   "Symfony\\Component\\DependencyInjection\\Exception\\InvalidArgumentException",
+		Message: "The tag \"kernel.event_listener\" requires an \"event\" attribute.",
+		File:    "/extension/vendor/symfony/dependency-injection/Compiler/RegisterListenersPass.php",
+		Line:    80,
+	})
+
+	assert.Empty(t, result.Path)
+	assert.Zero(t, result.Line)
+	assert.Equal(t, "The DI container cannot be compiled: The tag \"kernel.event_listener\" requires an \"event\" attribute. (InvalidArgumentException)", result.Message)
+}
@@ -19,13 +19,27 @@ func ConvertExtensionToToolConfig(ext extension.Extension) (*ToolConfig, error)
 			Identifier: ignore.Identifier,
 			Path:       ignore.Path,
 			Message:    ignore.Message,
+			Reason:     ignore.Reason,
 		})
 	}
 
+	severities := map[string]string{}
+
+	for identifier, severity := range ext.GetExtensionConfig().Validation.Rules {
+		severities[identifier] = string(severity)
+	}
+
+	baselineFile := ext.GetExtensionConfig().Validation.Baseline
+	if baselineFile == "" {
+		baselineFile = DefaultBaselineFile
+	}
+
 	cfg := &ToolConfig{
 		ToolDirectory:         GetToolDirectory(),
 		Extension:             ext,
 		ValidationIgnores:     ignores,
+		RuleSeverities:        severities,
+		BaselineFile:          path.Join(ext.GetPath(), baselineFile),
 		RootDir:               ext.GetPath(),
 		SourceDirectories:     ext.GetSourceDirs(),
 		AdminDirectories:      getAdminFolders(ext),
@@ -0,0 +1,45 @@
+<?php declare(strict_types=1);
+
+// Boots a Shopware kernel with only the given plugin and compiles the DI container without a database.
+// The result is printed as JSON, so shopware-cli can report the error at the file of the extension.
+
+use Shopware\Core\DevOps\StaticAnalyze\StaticAnalyzeKernel;
+use Shopware\Core\Framework\Plugin\KernelPluginLoader\StaticKernelPluginLoader;
+
+$pluginRoot = (string) getenv('SHOPWARE_CLI_PLUGIN_ROOT');
+$classLoader = require $pluginRoot . '/vendor/autoload.php';
+
+$composerJson = json_decode((string) file_get_contents($pluginRoot . '/composer.json'), true, 512, \JSON_THROW_ON_ERROR);
+
+$pluginInfo = [
+    'name' => (string) getenv('SHOPWARE_CLI_PLUGIN_NAME'),
+    'baseClass' => $composerJson['extra']['shopware-plugin-class'],
+    'managedByComposer' => false,
+    'active' => true,
+    'path' => $pluginRoot,
+    'autoload' => $composerJson['autoload'],
+    'version' => $composerJson['version'] ?? '1.0.0',
+];
+
+try {
+    $pluginLoader = new StaticKernelPluginLoader($classLoader, null, [$pluginInfo]);
+    $kernel = new StaticAnalyzeKernel('dev', true, $pluginLoader, 'shopware-cli-container-compile');
+    $kernel->boot();
+
+    echo json_encode(['success' => true]);
+} catch (\Throwable $e) {
+    $previous = $e;
+    while ($previous->getPrevious() !== null) {
+        $previous = $previous->getPrevious();
+    }
+
+    echo json_encode([
+        'success' => false,
+        'class' => $e::class,
+        'message' => $e->getMessage(),
+        'file' => $previous->getFile(),
+        'line' => $previous->getLine(),
+    ]);
+
+    exit(1);
+}
@@ -78,42 +78,51 @@ func (p PhpStan) Check(ctx context.Context, check *Check, config ToolConfig) err
 
 		log, _ := phpstan.Output()
 
-		log = []byte(strings.ReplaceAll(string(log), "\"files\":[]", "\"files\":{}"))
+		if err := addPHPStanResults(check, log, stderr.String(), config.RootDir, CheckSeverityError); err != nil {
+			return err
+		}
+	}
 
-		var phpstanResult PhpStanOutput
+	return nil
+}
 
-		if err := json.Unmarshal(log, &phpstanResult); err != nil {
-			//nolint: forbidigo
-			fmt.Print(stderr.String())
-			//nolint: forbidigo
-			fmt.Print(string(log))
-			return fmt.Errorf("failed to unmarshal phpstan output: %w", err)
-		}
+// addPHPStanResults parses the JSON output of PHPStan and adds its findings with the given severity
+func addPHPStanResults(check *Check, log []byte, stderr string, rootDir string, severity string) error {
+	log = []byte(strings.ReplaceAll(string(log), "\"files\":[]", "\"files\":{}"))
 
-		for _, error := range phpstanResult.Errors {
-			check.AddResult(CheckResult{
-				Path:       "phpstan.neon",
-				Message:    error,
-				Severity:   "error",
-				Line:       0,
-				Identifier: "phpstan/error",
-			})
-		}
+	var phpstanResult PhpStanOutput
 
-		for fileName, file := range phpstanResult.Files {
-			for _, message := range file.Messages {
-				if strings.HasSuffix(message.Identifier, "deprecated") && p.isUselessDeprecation(message.Message) {
-					continue
-				}
-
-				check.AddResult(CheckResult{
-					Path:       strings.TrimPrefix(strings.TrimPrefix(fileName, "/private"), config.RootDir+"/"),
-					Line:       message.Line,
-					Message:    message.Message,
-					Severity:   "error",
-					Identifier: fmt.Sprintf("phpstan/%s", message.Identifier),
-				})
+	if err := json.Unmarshal(log, &phpstanResult); err != nil {
+		//nolint: forbidigo
+		fmt.Print(stderr)
+		//nolint: forbidigo
+		fmt.Print(string(log))
+		return fmt.Errorf("failed to unmarshal phpstan output: %w", err)
+	}
+
+	for _, error := range phpstanResult.Errors {
+		check.AddResult(CheckResult{
+			Path:       "phpstan.neon",
+			Message:    error,
+			Severity:   severity,
+			Line:       0,
+			Identifier: "phpstan/error",
+		})
+	}
+
+	for fileName, file := range phpstanResult.Files {
+		for _, message := range file.Messages {
+			if strings.HasSuffix(message.Identifier, "deprecated") && (PhpStan{}).isUselessDeprecation(message.Message) {
+				continue
 			}
+
+			check.AddResult(CheckResult{
+				Path:       strings.TrimPrefix(strings.TrimPrefix(fileName, "/private"), rootDir+"/"),
+				Line:       message.Line,
+				Message:    message.Message,
+				Severity:   severity,
+				Identifier: fmt.Sprintf("phpstan/%s", message.Identifier),
+			})
 		}
 	}
 
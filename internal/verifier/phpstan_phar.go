@@ -0,0 +1,245 @@
+package verifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/internal/system"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+const (
+	phpstanPharVersion  = "2.1.17"
+	phpstanDefaultLevel = 5
+)
+
+var (
+	phpstanPharURL = fmt.Sprintf("https://github.com/phpstan/phpstan/releases/download/%s/phpstan.phar", phpstanPharVersion)
+	// phpstanPharSHA256 is the checksum of the phar of the pinned release, update it together with the version.
+	// It has to be taken from the downloaded release asset, the phar is not run without a pinned checksum.
+	phpstanPharSHA256 = ""
+)
+
+// PhpStanPhar runs a pinned PHPStan phar, so extension validate can run PHPStan without setting up the full tool chain.
+// It is enabled with validation.phpstan in the .shopware-extension.yml.
+type PhpStanPhar struct{}
+
+func (p PhpStanPhar) Name() string {
+	return "phpstan-phar"
+}
+
+// IsPHPStanPharEnabled reports whether the extension enabled PHPStan for the validation
+func IsPHPStanPharEnabled(ext extension.Extension) bool {
+	if ext == nil || ext.GetExtensionConfig() == nil {
+		return false
+	}
+
+	return ext.GetExtensionConfig().Validation.PHPStan.Enabled
+}
+
+func (p PhpStanPhar) Check(ctx context.Context, check *Check, config ToolConfig) error {
+	if !IsPHPStanPharEnabled(config.Extension) {
+		return nil
+	}
+
+	// Apps don't have an composer.json file, skip them
+	if _, err := os.Stat(path.Join(config.RootDir, "composer.json")); err != nil {
+		//nolint: nilerr
+		return nil
+	}
+
+	phpstanConfig := config.Extension.GetExtensionConfig().Validation.PHPStan
+
+	severity := CheckSeverityError
+	if phpstanConfig.Severity == CheckSeverityWarn {
+		severity = CheckSeverityWarn
+	}
+
+	// Installs shopware/core matching the composer constraint, so PHPStan knows the Shopware classes
+	if err := installComposerDeps(config.RootDir, config.CheckAgainst); err != nil {
+		return err
+	}
+
+	phar, err := downloadPHPStanPhar(ctx, filepath.Join(system.GetShopwareCliCacheDir(), "tools"))
+	if err != nil {
+		return err
+	}
+
+	arguments := []string{"-dmemory_limit=2G", phar, "analyse", "--no-progress", "--no-interaction", "--error-format=json"}
+
+	if !(PhpStan{}).configExists(config.RootDir) {
+		neon, err := os.CreateTemp("", "phpstan-*.neon")
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = os.Remove(neon.Name()) }()
+
+		if _, err := neon.WriteString(generatePHPStanConfig(config, phpstanConfig)); err != nil {
+			_ = neon.Close()
+			return err
+		}
+
+		if err := neon.Close(); err != nil {
+			return err
+		}
+
+		arguments = append(arguments, "--configuration", neon.Name())
+	}
+
+	phpstan := exec.CommandContext(ctx, "php", arguments...)
+	phpstan.Dir = config.RootDir
+
+	var stderr bytes.Buffer
+	phpstan.Stderr = &stderr
+
+	log, _ := phpstan.Output()
+
+	return addPHPStanResults(check, log, stderr.String(), config.RootDir, severity)
+}
+
+func (p PhpStanPhar) Fix(ctx context.Context, config ToolConfig) error {
+	return nil
+}
+
+func (p PhpStanPhar) Format(ctx context.Context, config ToolConfig, dryRun bool) error {
+	return nil
+}
+
+// generatePHPStanConfig builds a PHPStan config for the source directories of the extension
+func generatePHPStanConfig(config ToolConfig, phpstanConfig extension.ConfigValidationPHPStan) string {
+	level := phpstanDefaultLevel
+	if phpstanConfig.Level != nil {
+		level = *phpstanConfig.Level
+	}
+
+	var builder strings.Builder
+
+	if phpstanConfig.Baseline != "" {
+		builder.WriteString("includes:\n")
+		builder.WriteString(fmt.Sprintf("    - %s\n\n", neonString(filepath.Join(config.RootDir, phpstanConfig.Baseline))))
+	}
+
+	builder.WriteString("parameters:\n")
+	builder.WriteString(fmt.Sprintf("    level: %d\n", level))
+	builder.WriteString("    tipsOfTheDay: false\n")
+	builder.WriteString("    reportUnmatchedIgnoredErrors: false\n")
+	builder.WriteString("    bootstrapFiles:\n")
+	builder.WriteString(fmt.Sprintf("        - %s\n", neonString(filepath.Join(config.RootDir, "vendor", "autoload.php"))))
+	builder.WriteString("    paths:\n")
+
+	for _, sourceDirectory := range config.SourceDirectories {
+		builder.WriteString(fmt.Sprintf("        - %s\n", neonString(sourceDirectory)))
+	}
+
+	builder.WriteString("    excludePaths:\n")
+
+	for _, exclude := range []string{"vendor", "vendor-bin", "tests/", "Test/", "autoload-dist/vendor"} {
+		builder.WriteString(fmt.Sprintf("        - %s (?)\n", neonString(filepath.Join(config.RootDir, exclude))))
+	}
+
+	return builder.String()
+}
+
+func neonString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// downloadPHPStanPhar downloads the pinned PHPStan phar once into the given directory
+func downloadPHPStanPhar(ctx context.Context, dir string) (string, error) {
+	phar := filepath.Join(dir, fmt.Sprintf("phpstan-%s.phar", phpstanPharVersion))
+
+	if phpstanPharSHA256 == "" {
+		return "", fmt.Errorf("no checksum is pinned for PHPStan %s", phpstanPharVersion)
+	}
+
+	// The cached phar is verified before every run too, so a modified file is never executed
+	if _, err := os.Stat(phar); err == nil {
+		if err := verifyPHPStanPhar(phar); err == nil {
+			return phar, nil
+		}
+
+		logging.FromContext(ctx).Warnf("The cached PHPStan phar does not match the pinned checksum, downloading it again")
+	}
+
+	logging.FromContext(ctx).Infof("Downloading PHPStan %s", phpstanPharVersion)
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, phpstanPharURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		return "", fmt.Errorf("cannot download PHPStan: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("cannot download PHPStan from %s: got status code %d", phpstanPharURL, resp.StatusCode)
+	}
+
+	// Download into a temporary file first, so an aborted download is never used
+	tmp, err := os.CreateTemp(dir, "phpstan-*.download")
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		_ = tmp.Close()
+		return "", fmt.Errorf("cannot download PHPStan: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	if err := verifyPHPStanPhar(tmp.Name()); err != nil {
+		return "", fmt.Errorf("cannot download PHPStan: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), phar); err != nil {
+		return "", err
+	}
+
+	return phar, nil
+}
+
+func verifyPHPStanPhar(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != phpstanPharSHA256 {
+		return fmt.Errorf("the checksum %s of the PHPStan phar does not match the pinned checksum %s", sum, phpstanPharSHA256)
+	}
+
+	return nil
+}
@@ -1,9 +1,16 @@
 package verifier
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/shopware/shopware-cli/extension"
 )
 
 func TestPhpStan_isUselessDeprecation(t *testing.T) {
@@ -67,3 +74,79 @@ func TestPhpStan_isUselessDeprecation(t *testing.T) {
 		})
 	}
 }
+
+func TestGeneratePHPStanConfig(t *testing.T) {
+	config := ToolConfig{
+		RootDir:           "/ext",
+		SourceDirectories: []string{"/ext/src"},
+	}
+
+	neon := generatePHPStanConfig(config, extension.ConfigValidationPHPStan{Enabled: true})
+
+	assert.Contains(t, neon, "level: 5\n")
+	assert.Contains(t, neon, "- '/ext/src'\n")
+	assert.Contains(t, neon, "- '/ext/vendor/autoload.php'\n")
+	assert.NotContains(t, neon, "includes:")
+
+	level := 8
+	neon = generatePHPStanConfig(config, extension.ConfigValidationPHPStan{Enabled: true, Level: &level, Baseline: "phpstan-baseline.neon"})
+
+	assert.Contains(t, neon, "level: 8\n")
+	assert.Contains(t, neon, "includes:\n    - '/ext/phpstan-baseline.neon'\n")
+
+	level = 0
+	neon = generatePHPStanConfig(config, extension.ConfigValidationPHPStan{Enabled: true, Level: &level})
+
+	assert.Contains(t, neon, "level: 0\n")
+}
+
+func TestAddPHPStanResultsSeverity(t *testing.T) {
+	check := NewCheck()
+
+	output := []byte(`{"totals":{"errors":0,"file_errors":1},"files":{"/ext/src/Foo.php":{"errors":1,"messages":[{"message":"Undefined variable $foo","line":3,"identifier":"variable.undefined"}]}},"errors":[]}`)
+
+	assert.NoError(t, addPHPStanResults(check, output, "", "/ext", CheckSeverityWarn))
+
+	assert.Len(t, check.Results, 1)
+	assert.Equal(t, "src/Foo.php", check.Results[0].Path)
+	assert.Equal(t, CheckSeverityWarn, check.Results[0].Severity)
+	assert.Equal(t, "phpstan/variable.undefined", check.Results[0].Identifier)
+}
+
+func TestDownloadPHPStanPharVerifiesChecksum(t *testing.T) {
+	content := "phar content"
+	sum := sha256.Sum256([]byte(content))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(content))
+	}))
+	t.Cleanup(server.Close)
+
+	originalURL, originalSum := phpstanPharURL, phpstanPharSHA256
+	t.Cleanup(func() {
+		phpstanPharURL, phpstanPharSHA256 = originalURL, originalSum
+	})
+
+	phpstanPharURL = server.URL
+	phpstanPharSHA256 = hex.EncodeToString(sum[:])
+
+	dir := t.TempDir()
+
+	phar, err := downloadPHPStanPhar(t.Context(), dir)
+	assert.NoError(t, err)
+
+	// A modified cached phar is downloaded again
+	assert.NoError(t, os.WriteFile(phar, []byte("modified"), 0o644))
+
+	phar, err = downloadPHPStanPhar(t.Context(), dir)
+	assert.NoError(t, err)
+
+	downloaded, err := os.ReadFile(phar)
+	assert.NoError(t, err)
+	assert.Equal(t, content, string(downloaded))
+
+	content = "tampered"
+
+	_, err = downloadPHPStanPhar(t.Context(), t.TempDir())
+	assert.ErrorContains(t, err, "does not match the pinned checksum")
+}
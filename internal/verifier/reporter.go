@@ -1,11 +1,21 @@
 package verifier
 
 import (
+	"cmp"
+	"context"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+
+	"github.com/shopware/shopware-cli/internal/ci"
+	"github.com/shopware/shopware-cli/internal/color"
+	"github.com/shopware/shopware-cli/logging"
 )
 
 func DetectDefaultReporter() string {
@@ -16,63 +26,214 @@ func DetectDefaultReporter() string {
 	return "summary"
 }
 
-func DoCheckReport(result *Check, reportingFormat string) error {
+// DoCheckReport prints the result in the given format, rootDir is used to show the source of findings
+func DoCheckReport(ctx context.Context, result *Check, reportingFormat string, rootDir string) error {
 	switch reportingFormat {
 	case "summary":
-		return doSummaryReport(result)
+		return doSummaryReport(result, rootDir)
 	case "json":
 		return doJSONReport(result)
+	case "sarif":
+		return doSarifReport(result)
 	case "github":
 		return doGitHubReport(result)
 	case "markdown":
 		return doMarkdownReport(result)
 	case "junit":
 		return doJUnitReport(result)
+	case "store":
+		return doStoreReport(result)
+	case "pr-comment":
+		return doPullRequestCommentReport(ctx, result, rootDir)
+	}
+
+	return nil
+}
+
+// summaryContextLines is the amount of source lines printed before and after a finding
+const summaryContextLines = 2
+
+func doSummaryReport(result *Check, rootDir string) error {
+	//nolint:forbidigo
+	fmt.Print(convertResultsToSummary(result.Results, rootDir))
+
+	if result.HasErrors() {
+		os.Exit(1)
 	}
 
 	return nil
 }
 
-func doSummaryReport(result *Check) error {
-	// Group results by file
+// convertResultsToSummary groups the results by file, prints the source around known lines and counts the problems by rule
+func convertResultsToSummary(results []CheckResult, rootDir string) string {
 	fileGroups := make(map[string][]CheckResult)
-	for _, r := range result.Results {
+	ruleCounts := make(map[string]int)
+	errorCount := 0
+	warningCount := 0
+
+	for _, r := range results {
 		if r.Path == "" {
 			r.Path = "general"
 		}
 
 		fileGroups[r.Path] = append(fileGroups[r.Path], r)
+		ruleCounts[r.Identifier]++
+
+		switch r.Severity {
+		case CheckSeverityError:
+			errorCount++
+		case CheckSeverityWarn:
+			warningCount++
+		}
 	}
 
-	// Print results grouped by file
-	totalProblems := 0
-	errorCount := 0
-	warningCount := 0
+	files := make([]string, 0, len(fileGroups))
+	for file := range fileGroups {
+		files = append(files, file)
+	}
+
+	// General findings first, then the files alphabetically
+	slices.SortFunc(files, func(a, b string) int {
+		switch {
+		case a == b:
+			return 0
+		case a == "general":
+			return -1
+		case b == "general":
+			return 1
+		}
+
+		return cmp.Compare(a, b)
+	})
+
+	var builder strings.Builder
+
+	for _, file := range files {
+		fileResults := fileGroups[file]
+
+		slices.SortStableFunc(fileResults, func(a, b CheckResult) int {
+			return cmp.Compare(a.Line, b.Line)
+		})
+
+		var source []string
+		if file != "general" {
+			source = readSourceLines(rootDir, file)
+		}
 
-	for file, results := range fileGroups {
-		//nolint:forbidigo
-		fmt.Printf("\n%s\n", file)
-		for _, r := range results {
-			totalProblems++
-			switch r.Severity {
-			case CheckSeverityError:
-				errorCount++
-			case CheckSeverityWarn:
-				warningCount++
+		builder.WriteString(fmt.Sprintf("\n%s\n", color.BoldText.Render(file)))
+
+		for _, r := range fileResults {
+			location := ""
+			if r.Line > 0 {
+				location = fmt.Sprintf("%d  ", r.Line)
 			}
-			//nolint:forbidigo
-			fmt.Printf("  %d  %-7s  %s  %s\n", r.Line, r.Severity, r.Message, r.Identifier)
+
+			builder.WriteString(fmt.Sprintf("  %s  %s%s  %s\n", renderSeverity(r.Severity), location, r.Message, color.GrayText.Render(r.Identifier)))
+			builder.WriteString(renderSourceContext(source, r.Line))
 		}
 	}
 
-	//nolint:forbidigo
-	fmt.Printf("\n✖ %d problems (%d errors, %d warnings)\n", totalProblems, errorCount, warningCount)
+	if len(ruleCounts) > 0 {
+		rules := make([]string, 0, len(ruleCounts))
+		for rule := range ruleCounts {
+			rules = append(rules, rule)
+		}
 
-	if result.HasErrors() {
-		os.Exit(1)
+		// Most frequent rules first
+		slices.SortFunc(rules, func(a, b string) int {
+			if c := cmp.Compare(ruleCounts[b], ruleCounts[a]); c != 0 {
+				return c
+			}
+
+			return cmp.Compare(a, b)
+		})
+
+		builder.WriteString(fmt.Sprintf("\n%s\n", color.BoldText.Render("Problems by rule")))
+
+		for _, rule := range rules {
+			name := rule
+			if name == "" {
+				name = "unknown"
+			}
+
+			builder.WriteString(fmt.Sprintf("  %5d  %s\n", ruleCounts[rule], name))
+		}
 	}
 
-	return nil
+	summary := fmt.Sprintf("✖ %d problems (%d errors, %d warnings)", len(results), errorCount, warningCount)
+
+	switch {
+	case errorCount > 0:
+		summary = color.RedText.Render(summary)
+	case warningCount > 0:
+		summary = color.YellowText.Render(summary)
+	default:
+		summary = color.GreenText.Render(summary)
+	}
+
+	builder.WriteString(fmt.Sprintf("\n%s\n", summary))
+
+	return builder.String()
+}
+
+func renderSeverity(severity string) string {
+	label := fmt.Sprintf("%-7s", severity)
+
+	switch severity {
+	case CheckSeverityError:
+		return color.RedText.Render(label)
+	case CheckSeverityWarn:
+		return color.YellowText.Render(label)
+	}
+
+	return label
+}
+
+// readSourceLines reads the file of a finding, the path is either absolute or relative to the root directory
+func readSourceLines(rootDir, file string) []string {
+	if !filepath.IsAbs(file) {
+		if rootDir == "" {
+			return nil
+		}
+
+		file = filepath.Join(rootDir, file)
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+
+	return strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+}
+
+func renderSourceContext(source []string, line int) string {
+	if line <= 0 || line > len(source) {
+		return ""
+	}
+
+	start := max(line-summaryContextLines, 1)
+	end := min(line+summaryContextLines, len(source))
+	width := len(strconv.Itoa(end))
+
+	var builder strings.Builder
+
+	for i := start; i <= end; i++ {
+		marker := " "
+		if i == line {
+			marker = ">"
+		}
+
+		row := fmt.Sprintf("    %s %*d | %s", marker, width, i, strings.ReplaceAll(source[i-1], "\t", "    "))
+
+		if i != line {
+			row = color.GrayText.Render(row)
+		}
+
+		builder.WriteString(row + "\n")
+	}
+
+	return builder.String()
 }
 
 func doJSONReport(result *Check) error {
@@ -92,6 +253,144 @@ func doJSONReport(result *Check) error {
 	return nil
 }
 
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool struct {
+		Driver struct {
+			Name           string      `json:"name"`
+			InformationURI string      `json:"informationUri"`
+			Rules          []sarifRule `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds"`
+	Results            []sarifResult                    `json:"results"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	ShortDescription *sarifMessage `json:"shortDescription,omitempty"`
+	Properties       struct {
+		Category string `json:"category,omitempty"`
+	} `json:"properties"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           *struct {
+			StartLine int `json:"startLine"`
+		} `json:"region,omitempty"`
+	} `json:"physicalLocation"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+func doSarifReport(result *Check) error {
+	rules, err := GetRules()
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(convertResultsToSarif(result.Results, rules)); err != nil {
+		return fmt.Errorf("failed to write SARIF output: %w", err)
+	}
+
+	if result.HasErrors() {
+		os.Exit(1)
+	}
+
+	return nil
+}
+
+// convertResultsToSarif creates a SARIF 2.1.0 log for code scanning, the paths are relative to the extension root
+func convertResultsToSarif(results []CheckResult, rules []Rule) sarifLog {
+	run := sarifRun{
+		OriginalURIBaseIDs: map[string]sarifArtifactLocation{"%SRCROOT%": {URI: "./"}},
+		Results:            make([]sarifResult, 0, len(results)),
+	}
+
+	run.Tool.Driver.Name = "shopware-cli"
+	run.Tool.Driver.InformationURI = "https://github.com/shopware/shopware-cli"
+	run.Tool.Driver.Rules = make([]sarifRule, 0)
+
+	documented := make(map[string]Rule, len(rules))
+	for _, rule := range rules {
+		documented[rule.ID] = rule
+	}
+
+	seenRules := make(map[string]bool)
+
+	for _, r := range results {
+		if !seenRules[r.Identifier] {
+			seenRules[r.Identifier] = true
+
+			rule := sarifRule{ID: r.Identifier}
+
+			if doc, ok := documented[r.Identifier]; ok {
+				rule.ShortDescription = &sarifMessage{Text: doc.Description}
+				rule.Properties.Category = doc.Category
+			}
+
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+		}
+
+		level := "note"
+
+		switch r.Severity {
+		case CheckSeverityError:
+			level = "error"
+		case CheckSeverityWarn:
+			level = "warning"
+		}
+
+		sarif := sarifResult{RuleID: r.Identifier, Level: level, Message: sarifMessage{Text: r.Message}}
+
+		if r.Path != "" {
+			var location sarifLocation
+
+			location.PhysicalLocation.ArtifactLocation = sarifArtifactLocation{URI: filepath.ToSlash(r.Path), URIBaseID: "%SRCROOT%"}
+
+			if r.Line > 0 {
+				location.PhysicalLocation.Region = &struct {
+					StartLine int `json:"startLine"`
+				}{StartLine: r.Line}
+			}
+
+			sarif.Locations = []sarifLocation{location}
+		}
+
+		run.Results = append(run.Results, sarif)
+	}
+
+	return sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+}
+
 func doGitHubReport(result *Check) error {
 	stepSummary := os.Getenv("GITHUB_STEP_SUMMARY")
 
@@ -239,3 +538,180 @@ func convertResultsToMarkdown(check []CheckResult) string {
 
 	return builder.String()
 }
+
+// doStoreReport prints the results grouped into sub checks like the automatic code review of the Shopware Store
+func doStoreReport(result *Check) error {
+	//nolint:forbidigo
+	fmt.Print(convertResultsToStoreReport(result.Results))
+
+	if result.HasErrors() {
+		os.Exit(1)
+	}
+
+	return nil
+}
+
+func convertResultsToStoreReport(results []CheckResult) string {
+	subChecks := append([]StoreSubCheck{}, StoreSubChecks...)
+	// Everything else, like the metadata validation, is reported as general sub check
+	subChecks = append(subChecks, StoreSubCheck{Name: "General"})
+
+	grouped := make(map[string][]CheckResult)
+
+	for _, r := range results {
+		key := ""
+
+		for _, subCheck := range StoreSubChecks {
+			if r.Identifier == subCheck.Identifier {
+				key = subCheck.Identifier
+			}
+		}
+
+		grouped[key] = append(grouped[key], r)
+	}
+
+	var builder strings.Builder
+
+	for _, subCheck := range subChecks {
+		status := "passed"
+		subResults := grouped[subCheck.Identifier]
+
+		for _, r := range subResults {
+			if r.Severity == CheckSeverityError {
+				status = "failed"
+				break
+			}
+
+			status = "passed with warnings"
+		}
+
+		builder.WriteString(fmt.Sprintf("=== %s ===\n", subCheck.Name))
+		builder.WriteString(fmt.Sprintf("Status: %s\n", status))
+
+		for _, r := range subResults {
+			location := r.Path
+			if r.Line > 0 {
+				location = fmt.Sprintf("%s:%d", r.Path, r.Line)
+			}
+
+			if location != "" {
+				location += ": "
+			}
+
+			builder.WriteString(fmt.Sprintf("- [%s] %s%s\n", r.Severity, location, r.Message))
+		}
+
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+// pullRequestCommentMarker identifies the comment of previous runs, so it is updated instead of posting a new one
+const pullRequestCommentMarker = "<!-- shopware-cli-validation -->"
+
+// pullRequestCommentMaxFindings keeps the comment below the size limits of GitHub and GitLab, the job log has all findings
+const pullRequestCommentMaxFindings = 50
+
+// doPullRequestCommentReport prints the summary for the job log and posts the results as comment on the pull request
+func doPullRequestCommentReport(ctx context.Context, result *Check, rootDir string) error {
+	//nolint:forbidigo
+	fmt.Print(convertResultsToSummary(result.Results, rootDir))
+
+	commenter, err := ci.NewPullRequestCommenter()
+
+	switch {
+	case errors.Is(err, ci.ErrNoPullRequest):
+		logging.FromContext(ctx).Warnf("Skipping the pull request comment: %v", err)
+	case err != nil:
+		return err
+	default:
+		// The summary is in the job log already, a failing comment must not hide the result of the validation
+		if err := commenter.UpsertComment(ctx, pullRequestCommentMarker, convertResultsToPullRequestComment(result.Results)); err != nil {
+			logging.FromContext(ctx).Warnf("Cannot comment on the pull request: %v", err)
+		}
+	}
+
+	if result.HasErrors() {
+		os.Exit(1)
+	}
+
+	return nil
+}
+
+// convertResultsToPullRequestComment summarizes the results with the review status, errors are listed before warnings
+func convertResultsToPullRequestComment(results []CheckResult) string {
+	errorCount := 0
+	warningCount := 0
+
+	for _, r := range results {
+		switch r.Severity {
+		case CheckSeverityError:
+			errorCount++
+		case CheckSeverityWarn:
+			warningCount++
+		}
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString(pullRequestCommentMarker + "\n")
+	builder.WriteString("## Shopware CLI validation\n\n")
+
+	switch {
+	case errorCount > 0:
+		builder.WriteString(fmt.Sprintf("❌ **Changes required**: %d errors and %d warnings\n", errorCount, warningCount))
+	case warningCount > 0:
+		builder.WriteString(fmt.Sprintf("⚠️ **Passed with warnings**: %d warnings\n", warningCount))
+	default:
+		builder.WriteString("✅ **Passed**: no problems found\n")
+	}
+
+	if len(results) == 0 {
+		return builder.String()
+	}
+
+	sorted := slices.Clone(results)
+	slices.SortStableFunc(sorted, func(a, b CheckResult) int {
+		return cmp.Compare(severityRank(a.Severity), severityRank(b.Severity))
+	})
+
+	builder.WriteString("\n| Severity | Rule | Location | Message |\n")
+	builder.WriteString("| --- | --- | --- | --- |\n")
+
+	for _, r := range sorted[:min(len(sorted), pullRequestCommentMaxFindings)] {
+		location := r.Path
+		if r.Line > 0 {
+			location = fmt.Sprintf("%s:%d", r.Path, r.Line)
+		}
+
+		if location != "" {
+			location = "`" + location + "`"
+		}
+
+		builder.WriteString(fmt.Sprintf("| %s | `%s` | %s | %s |\n", r.Severity, r.Identifier, location, escapeMarkdownTableCell(r.Message)))
+	}
+
+	if len(sorted) > pullRequestCommentMaxFindings {
+		builder.WriteString(fmt.Sprintf("\n%d more findings are listed in the job log.\n", len(sorted)-pullRequestCommentMaxFindings))
+	}
+
+	return builder.String()
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case CheckSeverityError:
+		return 0
+	case CheckSeverityWarn:
+		return 1
+	}
+
+	return 2
+}
+
+func escapeMarkdownTableCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,111 @@
+package verifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertResultsToSummary(t *testing.T) {
+	report := convertResultsToSummary([]CheckResult{
+		{Path: "Bar.php", Line: 9, Message: "Undefined variable $undefined", Severity: CheckSeverityError, Identifier: "phpstan/variable.undefined"},
+		{Path: "Bar.php", Line: 5, Message: "Class is not final", Severity: CheckSeverityWarn, Identifier: "phpstan/class.notFinal"},
+		{Path: "", Message: "Missing license", Severity: CheckSeverityError, Identifier: "metadata.license"},
+		{Path: "Missing.php", Line: 3, Message: "Undefined variable $foo", Severity: CheckSeverityError, Identifier: "phpstan/variable.undefined"},
+	}, "testdata/reporter")
+
+	// General findings first, then the files sorted
+	assert.Less(t, strings.Index(report, "general"), strings.Index(report, "Bar.php"))
+	assert.Less(t, strings.Index(report, "Bar.php"), strings.Index(report, "Missing.php"))
+
+	// Findings are sorted by line
+	assert.Less(t, strings.Index(report, "Class is not final"), strings.Index(report, "Undefined variable $undefined"))
+
+	assert.Contains(t, report, "    >  9 |         return $undefined;\n")
+	assert.Contains(t, report, "       7 |     public function baz()\n")
+	assert.Contains(t, report, "      11 | }\n")
+	assert.NotContains(t, report, "   12 |")
+
+	assert.Contains(t, report, "      2  phpstan/variable.undefined\n")
+	assert.Contains(t, report, "      1  metadata.license\n")
+	assert.Contains(t, report, "✖ 4 problems (3 errors, 1 warnings)")
+}
+
+func TestConvertResultsToSarif(t *testing.T) {
+	log := convertResultsToSarif([]CheckResult{
+		{Path: "src/Resources/config/services.xml", Line: 4, Message: "the class does not exist", Severity: CheckSeverityError, Identifier: "services.missing_class"},
+		{Path: "src/Resources/config/services.xml", Line: 8, Message: "the class does not exist", Severity: CheckSeverityError, Identifier: "services.missing_class"},
+		{Message: "Missing license", Severity: CheckSeverityWarn, Identifier: "metadata.license"},
+	}, []Rule{{ID: "services.missing_class", Category: "Services", Description: "A service class is missing"}})
+
+	assert.Equal(t, "2.1.0", log.Version)
+	assert.Len(t, log.Runs, 1)
+
+	run := log.Runs[0]
+
+	assert.Len(t, run.Tool.Driver.Rules, 2)
+	assert.Equal(t, "A service class is missing", run.Tool.Driver.Rules[0].ShortDescription.Text)
+	assert.Nil(t, run.Tool.Driver.Rules[1].ShortDescription)
+
+	assert.Len(t, run.Results, 3)
+	assert.Equal(t, "error", run.Results[0].Level)
+	assert.Equal(t, "src/Resources/config/services.xml", run.Results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 8, run.Results[1].Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal(t, "warning", run.Results[2].Level)
+	assert.Empty(t, run.Results[2].Locations)
+
+	content, err := json.Marshal(log)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `"$schema":"https://json.schemastore.org/sarif-2.1.0.json"`)
+}
+
+func TestConvertResultsToPullRequestComment(t *testing.T) {
+	comment := convertResultsToPullRequestComment([]CheckResult{
+		{Path: "src/Resources/config/services.xml", Line: 4, Message: "the class | does not exist", Severity: CheckSeverityWarn, Identifier: "services.missing_class"},
+		{Message: "Missing license", Severity: CheckSeverityError, Identifier: "metadata.license"},
+	})
+
+	assert.True(t, strings.HasPrefix(comment, pullRequestCommentMarker))
+	assert.Contains(t, comment, "❌ **Changes required**: 1 errors and 1 warnings")
+	assert.Less(t, strings.Index(comment, "metadata.license"), strings.Index(comment, "services.missing_class"))
+	assert.Contains(t, comment, "| warning | `services.missing_class` | `src/Resources/config/services.xml:4` | the class \\| does not exist |")
+
+	assert.Contains(t, convertResultsToPullRequestComment(nil), "✅ **Passed**: no problems found")
+}
+
+func TestConvertResultsToPullRequestCommentLimitsFindings(t *testing.T) {
+	results := make([]CheckResult, pullRequestCommentMaxFindings+3)
+	for i := range results {
+		results[i] = CheckResult{Message: "Missing license", Severity: CheckSeverityWarn, Identifier: "metadata.license"}
+	}
+
+	comment := convertResultsToPullRequestComment(results)
+
+	assert.Contains(t, comment, "⚠️ **Passed with warnings**: 53 warnings")
+	assert.Equal(t, pullRequestCommentMaxFindings, strings.Count(comment, "metadata.license"))
+	assert.Contains(t, comment, "3 more findings are listed in the job log.")
+}
+
+func TestPullRequestCommentReportKeepsResultWhenCommentFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	t.Setenv("GITLAB_CI", "")
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_EVENT_PATH", "")
+	t.Setenv("GITHUB_REF", "refs/pull/5/merge")
+	t.Setenv("GITHUB_TOKEN", "secret")
+	t.Setenv("GITHUB_REPOSITORY", "acme/plugin")
+	t.Setenv("GITHUB_API_URL", server.URL)
+
+	result := NewCheck()
+	result.AddResult(CheckResult{Message: "Class is not final", Severity: CheckSeverityWarn, Identifier: "phpstan/class.notFinal"})
+
+	assert.NoError(t, doPullRequestCommentReport(t.Context(), result, t.TempDir()))
+}
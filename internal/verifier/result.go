@@ -71,6 +71,49 @@ func (c *Check) RemoveByIdentifier(ignores []ToolConfigIgnore) *Check {
 	return c
 }
 
+// ApplyRuleSeverities changes the severity of the results by their identifier, severity off removes the result.
+// Identifiers ending with * match all identifiers with this prefix, the longest match wins.
+func (c *Check) ApplyRuleSeverities(severities map[string]string) *Check {
+	if len(severities) == 0 {
+		return c
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	filtered := make([]CheckResult, 0, len(c.Results))
+
+	for _, r := range c.Results {
+		severity, ok := severities[r.Identifier]
+
+		if !ok {
+			matched := ""
+
+			for pattern, patternSeverity := range severities {
+				prefix, isWildcard := strings.CutSuffix(pattern, "*")
+
+				if isWildcard && strings.HasPrefix(r.Identifier, prefix) && len(pattern) > len(matched) {
+					matched = pattern
+					severity = patternSeverity
+				}
+			}
+		}
+
+		switch severity {
+		case "off":
+			continue
+		case CheckSeverityError, CheckSeverityWarn:
+			r.Severity = severity
+		}
+
+		filtered = append(filtered, r)
+	}
+
+	c.Results = filtered
+
+	return c
+}
+
 type CheckResult struct {
 	// The path to the file that was checked
 	Path string `json:"path"`
@@ -256,3 +256,26 @@ func TestRemoveByMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyRuleSeverities(t *testing.T) {
+	check := NewCheck()
+	check.AddResult(CheckResult{Identifier: "snippet.unused", Severity: "warning"})
+	check.AddResult(CheckResult{Identifier: "metadata.description", Severity: "error"})
+	check.AddResult(CheckResult{Identifier: "eslint/no-unused-vars", Severity: "error"})
+	check.AddResult(CheckResult{Identifier: "eslint/vue/no-mutating-props", Severity: "error"})
+	check.AddResult(CheckResult{Identifier: "twig.syntax", Severity: "error"})
+
+	check.ApplyRuleSeverities(map[string]string{
+		"snippet.unused":       "off",
+		"metadata.description": "warning",
+		"eslint/*":             "warning",
+		"eslint/vue/*":         "off",
+	})
+
+	assert.Equal(t, []CheckResult{
+		{Identifier: "metadata.description", Severity: "warning"},
+		{Identifier: "eslint/no-unused-vars", Severity: "warning"},
+		{Identifier: "twig.syntax", Severity: "error"},
+	}, check.Results)
+	assert.True(t, check.HasErrors())
+}
@@ -0,0 +1,30 @@
+package verifier
+
+import (
+	_ "embed"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed rules.yaml
+var rulesDocumentation []byte
+
+// Rule describes a check result identifier, so it can be configured without reading the source code
+type Rule struct {
+	ID          string `yaml:"id" json:"id"`
+	Severity    string `yaml:"severity" json:"severity"`
+	Category    string `yaml:"category" json:"category"`
+	Description string `yaml:"description" json:"description"`
+}
+
+// GetRules returns the documented rules in the order of their categories
+func GetRules() ([]Rule, error) {
+	var rules []Rule
+
+	if err := yaml.Unmarshal(rulesDocumentation, &rules); err != nil {
+		return nil, fmt.Errorf("cannot parse rule documentation: %w", err)
+	}
+
+	return rules, nil
+}
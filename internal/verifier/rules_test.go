@@ -0,0 +1,60 @@
+package verifier
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRules(t *testing.T) {
+	rules, err := GetRules()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, rules)
+
+	seen := map[string]bool{}
+
+	for _, rule := range rules {
+		assert.False(t, seen[rule.ID], "rule %s is documented twice", rule.ID)
+		seen[rule.ID] = true
+
+		assert.Contains(t, []string{CheckSeverityError, CheckSeverityWarn}, rule.Severity, rule.ID)
+		assert.NotEmpty(t, rule.Category, rule.ID)
+		assert.NotEmpty(t, rule.Description, rule.ID)
+	}
+
+	for _, subCheck := range StoreSubChecks {
+		assert.True(t, seen[subCheck.Identifier], "store sub check %s is not documented", subCheck.Identifier)
+	}
+}
+
+func TestRulesDocumentExtensionValidation(t *testing.T) {
+	rules, err := GetRules()
+	assert.NoError(t, err)
+
+	documented := map[string]bool{}
+	for _, rule := range rules {
+		documented[rule.ID] = true
+	}
+
+	files, err := filepath.Glob(filepath.Join("..", "..", "extension", "*.go"))
+	assert.NoError(t, err)
+
+	identifierRegExp := regexp.MustCompile(`Add(?:Error|Warning)(?:At)?\("([^"]+)"`)
+
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+
+		content, err := os.ReadFile(file)
+		assert.NoError(t, err)
+
+		for _, match := range identifierRegExp.FindAllStringSubmatch(string(content), -1) {
+			assert.True(t, documented[match[1]], "%s reports %s which is not documented in rules.yaml", filepath.Base(file), match[1])
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/shopware/shopware-cli/internal/system"
+)
+
+// StoreSubCheck is a group of checks like the automatic code review of the Shopware Store reports them
+type StoreSubCheck struct {
+	Identifier string
+	Name       string
+}
+
+var (
+	storeSubCheckForbiddenFunctions = StoreSubCheck{Identifier: "store.forbidden_function", Name: "Forbidden functions"}
+	storeSubCheckLicense            = StoreSubCheck{Identifier: "store.license", Name: "License"}
+	storeSubCheckZipStructure       = StoreSubCheck{Identifier: "store.zip_structure", Name: "Zip structure"}
+	storeSubCheckIonCube            = StoreSubCheck{Identifier: "store.encoded_file", Name: "Encoded files (ionCube)"}
+	storeSubCheckGlobalNamespace    = StoreSubCheck{Identifier: "store.global_namespace", Name: "Global namespace"}
+)
+
+// StoreSubChecks lists the sub checks of the store rules in the order they are reported
+var StoreSubChecks = []StoreSubCheck{
+	storeSubCheckZipStructure,
+	storeSubCheckLicense,
+	storeSubCheckIonCube,
+	storeSubCheckForbiddenFunctions,
+	storeSubCheckGlobalNamespace,
+}
+
+var (
+	// Functions which let the review fail
+	storeForbiddenFunctions = []string{"eval", "exec", "shell_exec", "system", "passthru", "popen", "proc_open", "pcntl_exec", "create_function"}
+	// Debug output which should not be shipped
+	storeDebugFunctions = []string{"var_dump", "dump", "dd", "debug_zval_dump", "phpinfo"}
+
+	storeFunctionCallRegExp = regexp.MustCompile(`(?i)(\bfunction\s+&?|->\s*|::\s*|\$|\bnew\s+)?\\?\b(` + strings.Join(append(append([]string{}, storeForbiddenFunctions...), storeDebugFunctions...), "|") + `)\s*\(`)
+	storeNamespaceRegExp    = regexp.MustCompile(`(?m)^\s*namespace\s+[\w\\]+\s*[;{]`)
+	storeDeclarationRegExp  = regexp.MustCompile(`(?mi)^(?:\s*(?:abstract|final|readonly)\s+)*\s*(class|interface|trait|enum)\s+(\w+)|^(function)\s+&?(\w+)`)
+	storeEncodedMarkers     = []string{"ionCube Loader", "ioncube_loader", "sg_load(", "SourceGuardian"}
+	storeArchiveExtensions  = []string{".zip", ".tar", ".gz", ".tgz", ".rar", ".7z", ".phar"}
+)
+
+// StoreRules runs the classes of checks the automatic code review of the Shopware Store performs
+type StoreRules struct{}
+
+func (s StoreRules) Name() string {
+	return "store-rules"
+}
+
+func (s StoreRules) Check(ctx context.Context, check *Check, config ToolConfig) error {
+	if config.Extension != nil {
+		if err := s.checkLicense(check, config); err != nil {
+			return err
+		}
+
+		if !config.InputWasDirectory {
+			if err := s.checkZipRoot(check, config); err != nil {
+				return err
+			}
+		}
+	}
+
+	entries, err := system.Walk(config.RootDir, system.WalkOptions{ExcludeDirs: []string{".git", "node_modules"}})
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Entry.Name()
+
+		if entry.Entry.IsDir() {
+			if name == "__MACOSX" {
+				check.AddResult(storeResult(storeSubCheckZipStructure, entry.Path, 0, CheckSeverityError, "The folder __MACOSX must not be part of the zip"))
+			}
+
+			continue
+		}
+
+		if name == ".DS_Store" {
+			check.AddResult(storeResult(storeSubCheckZipStructure, entry.Path, 0, CheckSeverityError, "The file .DS_Store must not be part of the zip"))
+			continue
+		}
+
+		for _, ext := range storeArchiveExtensions {
+			if strings.HasSuffix(strings.ToLower(name), ext) {
+				check.AddResult(storeResult(storeSubCheckZipStructure, entry.Path, 0, CheckSeverityError, fmt.Sprintf("Archives like %s are not allowed inside the zip", name)))
+			}
+		}
+
+		if path.Ext(name) != ".php" || isVendorPath(entry.Path) {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(config.RootDir, filepath.FromSlash(entry.Path)))
+		if err != nil {
+			return err
+		}
+
+		checkStorePHPFile(check, entry.Path, string(content))
+	}
+
+	return nil
+}
+
+func (s StoreRules) Fix(ctx context.Context, config ToolConfig) error {
+	return nil
+}
+
+func (s StoreRules) Format(ctx context.Context, config ToolConfig, dryRun bool) error {
+	return nil
+}
+
+func (s StoreRules) checkLicense(check *Check, config ToolConfig) error {
+	license, err := config.Extension.GetLicense()
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(license) == "" {
+		check.AddResult(storeResult(storeSubCheckLicense, "", 0, CheckSeverityError, "The extension has no license, set it in the composer.json or manifest.xml"))
+	}
+
+	return nil
+}
+
+func (s StoreRules) checkZipRoot(check *Check, config ToolConfig) error {
+	name, err := config.Extension.GetName()
+	if err != nil {
+		return err
+	}
+
+	if filepath.Base(config.RootDir) != name {
+		check.AddResult(storeResult(storeSubCheckZipStructure, "", 0, CheckSeverityError, fmt.Sprintf("The root folder of the zip must be named like the extension %s, but is %s", name, filepath.Base(config.RootDir))))
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(config.RootDir))
+	if err != nil {
+		return err
+	}
+
+	if len(entries) != 1 {
+		check.AddResult(storeResult(storeSubCheckZipStructure, "", 0, CheckSeverityError, fmt.Sprintf("The zip must contain exactly one root folder, found %d entries", len(entries))))
+	}
+
+	return nil
+}
+
+func checkStorePHPFile(check *Check, file, content string) {
+	head := content
+	if len(head) > 2048 {
+		head = head[:2048]
+	}
+
+	for _, marker := range storeEncodedMarkers {
+		if strings.Contains(head, marker) {
+			check.AddResult(storeResult(storeSubCheckIonCube, file, 1, CheckSeverityError, "Encoded files are not allowed, the store requires readable source code"))
+			return
+		}
+	}
+
+	code := stripPHPCommentsAndStrings(content)
+
+	for _, match := range storeFunctionCallRegExp.FindAllStringSubmatchIndex(code, -1) {
+		// Method calls, variables and function declarations with the same name are fine
+		if match[2] != -1 {
+			continue
+		}
+
+		function := strings.ToLower(code[match[4]:match[5]])
+		line := strings.Count(code[:match[4]], "\n") + 1
+
+		if slices.Contains(storeDebugFunctions, function) {
+			check.AddResult(storeResult(storeSubCheckForbiddenFunctions, file, line, CheckSeverityWarn, fmt.Sprintf("Debug function %s() should not be shipped", function)))
+			continue
+		}
+
+		check.AddResult(storeResult(storeSubCheckForbiddenFunctions, file, line, CheckSeverityError, fmt.Sprintf("Usage of the forbidden function %s() is not allowed", function)))
+	}
+
+	if idx := strings.IndexByte(code, '`'); idx != -1 {
+		check.AddResult(storeResult(storeSubCheckForbiddenFunctions, file, strings.Count(code[:idx], "\n")+1, CheckSeverityError, "Shell execution with the backtick operator is not allowed"))
+	}
+
+	if storeNamespaceRegExp.MatchString(code) {
+		return
+	}
+
+	for _, match := range storeDeclarationRegExp.FindAllStringSubmatchIndex(code, -1) {
+		// Either the class like or the function groups matched
+		kindStart, kindEnd, nameStart, nameEnd := match[2], match[3], match[4], match[5]
+		if kindStart == -1 {
+			kindStart, kindEnd, nameStart, nameEnd = match[6], match[7], match[8], match[9]
+		}
+
+		line := strings.Count(code[:kindStart], "\n") + 1
+
+		check.AddResult(storeResult(storeSubCheckGlobalNamespace, file, line, CheckSeverityError, fmt.Sprintf("The %s %s is declared in the global namespace, add a namespace to the file", strings.ToLower(code[kindStart:kindEnd]), code[nameStart:nameEnd])))
+	}
+}
+
+func isVendorPath(p string) bool {
+	return p == "vendor" || strings.HasPrefix(p, "vendor/")
+}
+
+func storeResult(subCheck StoreSubCheck, file string, line int, severity, message string) CheckResult {
+	return CheckResult{
+		Path:       file,
+		Line:       line,
+		Message:    message,
+		Severity:   severity,
+		Identifier: subCheck.Identifier,
+	}
+}
+
+// stripPHPCommentsAndStrings blanks out comments and string literals, so only code is matched.
+// Newlines are kept to preserve line numbers. Backticks are shell execution in PHP and kept as code.
+func stripPHPCommentsAndStrings(content string) string {
+	out := []byte(content)
+
+	const (
+		stateCode = iota
+		stateLineComment
+		stateBlockComment
+		stateString
+	)
+
+	state := stateCode
+	var quote byte
+
+	for i := 0; i < len(out); i++ {
+		c := out[i]
+
+		switch state {
+		case stateCode:
+			switch {
+			case c == '/' && i+1 < len(out) && out[i+1] == '/':
+				state = stateLineComment
+			case c == '#' && (i+1 >= len(out) || out[i+1] != '['):
+				state = stateLineComment
+			case c == '/' && i+1 < len(out) && out[i+1] == '*':
+				state = stateBlockComment
+				out[i] = ' '
+				i++
+			case c == '\'' || c == '"':
+				state = stateString
+				quote = c
+				continue
+			default:
+				continue
+			}
+		case stateLineComment:
+			if c == '\n' {
+				state = stateCode
+				continue
+			}
+		case stateBlockComment:
+			if c == '*' && i+1 < len(out) && out[i+1] == '/' {
+				state = stateCode
+				out[i] = ' '
+				i++
+			}
+		case stateString:
+			if c == '\\' && i+1 < len(out) {
+				out[i] = ' '
+				i++
+
+				if out[i] == '\n' {
+					continue
+				}
+			} else if c == quote {
+				state = stateCode
+				continue
+			}
+		}
+
+		if out[i] != '\n' {
+			out[i] = ' '
+		}
+	}
+
+	return string(out)
+}
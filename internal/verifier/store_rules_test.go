@@ -0,0 +1,104 @@
+package verifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreRulesForbiddenFunctions(t *testing.T) {
+	check := NewCheck()
+
+	checkStorePHPFile(check, "src/Service.php", `<?php
+namespace Foo;
+
+class Service
+{
+    public function exec(): void
+    {
+        // exec('ls');
+        $this->exec();
+        $result = shell_exec('ls');
+        $text = "eval(";
+        \system('ls');
+        dump($result);
+        $output = `+"`ls`"+`;
+    }
+}
+`)
+
+	assert.Equal(t, []CheckResult{
+		{Path: "src/Service.php", Line: 10, Message: "Usage of the forbidden function shell_exec() is not allowed", Severity: CheckSeverityError, Identifier: "store.forbidden_function"},
+		{Path: "src/Service.php", Line: 12, Message: "Usage of the forbidden function system() is not allowed", Severity: CheckSeverityError, Identifier: "store.forbidden_function"},
+		{Path: "src/Service.php", Line: 13, Message: "Debug function dump() should not be shipped", Severity: CheckSeverityWarn, Identifier: "store.forbidden_function"},
+		{Path: "src/Service.php", Line: 14, Message: "Shell execution with the backtick operator is not allowed", Severity: CheckSeverityError, Identifier: "store.forbidden_function"},
+	}, check.Results)
+}
+
+func TestStoreRulesGlobalNamespace(t *testing.T) {
+	check := NewCheck()
+
+	checkStorePHPFile(check, "src/helper.php", `<?php
+
+final class Helper
+{
+    public function run(): void
+    {
+        $fn = function () {};
+    }
+}
+
+function helper() {}
+`)
+
+	assert.Len(t, check.Results, 2)
+	assert.Equal(t, "The class Helper is declared in the global namespace, add a namespace to the file", check.Results[0].Message)
+	assert.Equal(t, 3, check.Results[0].Line)
+	assert.Equal(t, "The function helper is declared in the global namespace, add a namespace to the file", check.Results[1].Message)
+	assert.Equal(t, 11, check.Results[1].Line)
+
+	check = NewCheck()
+	checkStorePHPFile(check, "src/Resources/config/routes.php", "<?php\n\nreturn static function ($routes) {};\n")
+	assert.Empty(t, check.Results)
+}
+
+func TestStoreRulesEncodedFiles(t *testing.T) {
+	check := NewCheck()
+
+	checkStorePHPFile(check, "src/Plugin.php", "<?php //0046a\nif(!extension_loaded('ionCube Loader')){die('The file needs the ionCube Loader');}")
+
+	assert.Len(t, check.Results, 1)
+	assert.Equal(t, "store.encoded_file", check.Results[0].Identifier)
+}
+
+func TestStoreRulesZipStructure(t *testing.T) {
+	root := t.TempDir()
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "__MACOSX"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, ".DS_Store"), []byte{}, 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "bundle.zip"), []byte{}, 0o644))
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "vendor", "lib"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "vendor", "lib", "functions.php"), []byte("<?php function lib() { eval('1'); }"), 0o644))
+
+	check := NewCheck()
+	assert.NoError(t, StoreRules{}.Check(t.Context(), check, ToolConfig{RootDir: root, InputWasDirectory: true}))
+
+	assert.Len(t, check.Results, 3)
+
+	for _, r := range check.Results {
+		assert.Equal(t, "store.zip_structure", r.Identifier)
+	}
+}
+
+func TestStoreReport(t *testing.T) {
+	report := convertResultsToStoreReport([]CheckResult{
+		{Path: "src/Service.php", Line: 10, Message: "Usage of the forbidden function exec() is not allowed", Severity: CheckSeverityError, Identifier: "store.forbidden_function"},
+		{Message: "The extension has no changelog", Severity: CheckSeverityWarn, Identifier: "metadata.changelog"},
+	})
+
+	assert.Contains(t, report, "=== Forbidden functions ===\nStatus: failed\n- [error] src/Service.php:10: Usage of the forbidden function exec() is not allowed\n")
+	assert.Contains(t, report, "=== License ===\nStatus: passed\n")
+	assert.Contains(t, report, "=== General ===\nStatus: passed with warnings\n- [warning] The extension has no changelog\n")
+}
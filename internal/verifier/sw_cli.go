@@ -2,8 +2,10 @@ package verifier
 
 import (
 	"context"
+	"path/filepath"
 
 	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/logging"
 )
 
 type SWCLI struct{}
@@ -17,7 +19,7 @@ func (s SWCLI) Check(ctx context.Context, check *Check, config ToolConfig) error
 		return nil
 	}
 
-	validationContext := extension.RunValidation(ctx, config.Extension)
+	validationContext := extension.RunValidation(ctx, config.Extension, extension.ValidationOptions{RunExternalValidators: config.RunExternalValidators})
 
 	if config.InputWasDirectory {
 		validationContext.ApplyIgnores([]extension.ConfigValidationIgnoreItem{
@@ -26,32 +28,73 @@ func (s SWCLI) Check(ctx context.Context, check *Check, config ToolConfig) error
 				Message:    ".gitignore is not allowed in the zip file",
 			},
 		})
+
+		// The zip command builds the assets, a folder does not need to contain them yet
+		if config.Extension.GetExtensionConfig().Build.Zip.Assets.Enabled {
+			validationContext.ApplyIgnores([]extension.ConfigValidationIgnoreItem{
+				{Identifier: "assets.not_built"},
+				{Identifier: "assets.outdated"},
+			})
+		}
 	}
 
 	for _, err := range validationContext.Errors() {
-		check.AddResult(CheckResult{
-			Path:       "",
-			Line:       0,
-			Message:    err.Message,
-			Identifier: err.Identifier,
-			Severity:   "error",
-		})
+		check.AddResult(newSwCliResult(config.RootDir, config.Extension.GetRootDir(), err, CheckSeverityError))
 	}
 
 	for _, err := range validationContext.Warnings() {
-		check.AddResult(CheckResult{
-			Path:       "",
-			Line:       0,
-			Message:    err.Message,
-			Identifier: err.Identifier,
-			Severity:   "warning",
-		})
+		check.AddResult(newSwCliResult(config.RootDir, config.Extension.GetRootDir(), err, CheckSeverityWarn))
 	}
 
 	return nil
 }
 
+// newSwCliResult takes the location of the message into path and line, so reporters can annotate the file. The message
+// keeps the location, ignores of the users match on it. The validators report paths relative to the root dir of the
+// extension, which is the src folder of plugins.
+func newSwCliResult(rootDir, extensionRootDir string, message extension.ValidationMessage, severity string) CheckResult {
+	result := CheckResult{
+		Message:    message.Message,
+		Identifier: message.Identifier,
+		Severity:   severity,
+	}
+
+	if message.File == "" {
+		return result
+	}
+
+	path := message.File
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(extensionRootDir, path)
+	}
+
+	if rootDir != "" {
+		if relPath, err := filepath.Rel(rootDir, path); err == nil {
+			path = relPath
+		}
+	}
+
+	result.Path = filepath.ToSlash(path)
+	result.Line = message.Line
+
+	return result
+}
+
 func (s SWCLI) Fix(ctx context.Context, config ToolConfig) error {
+	if config.Extension == nil {
+		return nil
+	}
+
+	// The other fixers rewrite files like the composer.json and the icon, they only run with validate --fix
+	results, err := extension.RunFixers(ctx, config.Extension, []extension.Fixer{extension.SnippetFixer{}})
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		logging.FromContext(ctx).Infof("%s: %s (%s)", result.Fixer, result.Change.Path, result.Change.Description)
+	}
+
 	return nil
 }
 
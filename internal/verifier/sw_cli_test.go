@@ -0,0 +1,29 @@
+package verifier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shopware/shopware-cli/extension"
+)
+
+func TestNewSwCliResult(t *testing.T) {
+	t.Run("location is moved into path and line", func(t *testing.T) {
+		result := newSwCliResult("/ext", "/ext/src", extension.ValidationMessage{Identifier: "config.invalid_field", Message: "Resources/config/config.xml:12: the field has no name", File: "Resources/config/config.xml", Line: 12}, CheckSeverityError)
+
+		assert.Equal(t, CheckResult{Path: "src/Resources/config/config.xml", Line: 12, Message: "Resources/config/config.xml:12: the field has no name", Identifier: "config.invalid_field", Severity: CheckSeverityError}, result)
+	})
+
+	t.Run("message without location", func(t *testing.T) {
+		result := newSwCliResult("/ext", "/ext/src", extension.ValidationMessage{Identifier: "metadata.license", Message: "The license is missing: composer.json"}, CheckSeverityWarn)
+
+		assert.Equal(t, CheckResult{Message: "The license is missing: composer.json", Identifier: "metadata.license", Severity: CheckSeverityWarn}, result)
+	})
+
+	t.Run("location is not parsed from the message", func(t *testing.T) {
+		result := newSwCliResult("/ext", "/ext/src", extension.ValidationMessage{Identifier: "metadata.license", Message: "composer.json:1: looks like a location"}, CheckSeverityWarn)
+
+		assert.Equal(t, CheckResult{Message: "composer.json:1: looks like a location", Identifier: "metadata.license", Severity: CheckSeverityWarn}, result)
+	})
+}
@@ -0,0 +1,11 @@
+<?php
+
+namespace Foo;
+
+class Bar
+{
+	public function baz()
+	{
+		return $undefined;
+	}
+}
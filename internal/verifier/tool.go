@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"strings"
 
+	"golang.org/x/sync/errgroup"
+
 	"github.com/shopware/shopware-cli/extension"
+	"github.com/shopware/shopware-cli/logging"
 )
 
 type ToolList []Tool
@@ -25,6 +28,8 @@ type ToolConfig struct {
 	ToolDirectory string
 
 	InputWasDirectory bool
+	// Run the commands of validation.external of the extension, only set for trusted input
+	RunExternalValidators bool
 
 	// The minimum version of Shopware that is supported
 	MinShopwareVersion string
@@ -38,6 +43,10 @@ type ToolConfig struct {
 	SourceDirectories []string
 	// Contains a list of identifiers that are ignored
 	ValidationIgnores []ToolConfigIgnore
+	// Changed severities of rules by identifier, off disables the rule
+	RuleSeverities map[string]string
+	// Path to the baseline file of accepted findings
+	BaselineFile string
 	// Contains a list of directories that are considered as admin code
 	AdminDirectories []string
 	// Contains a list of directories that are considered as storefront code
@@ -50,6 +59,8 @@ type ToolConfigIgnore struct {
 	Identifier string
 	Path       string
 	Message    string
+	// Why the findings are ignored, required for the ignores of the extension config
+	Reason string
 }
 
 type Tool interface {
@@ -95,3 +106,51 @@ func (tl ToolList) PossibleString() string {
 
 	return strings.Join(possibleTools, ",")
 }
+
+// Run executes all tools in parallel. When a cache is given, tools with a cached result for the same input are skipped.
+func (tl ToolList) Run(ctx context.Context, config ToolConfig, cache *ResultCache) (*Check, error) {
+	result := NewCheck()
+
+	var gr errgroup.Group
+
+	for _, tool := range tl {
+		tool := tool
+		gr.Go(func() error {
+			if cache != nil {
+				if cached, ok := cache.Get(tool); ok {
+					logging.FromContext(ctx).Debugf("Using cached results for %s", tool.Name())
+
+					for _, r := range cached {
+						result.AddResult(r)
+					}
+
+					return nil
+				}
+			}
+
+			toolResult := NewCheck()
+
+			if err := tool.Check(ctx, toolResult, config); err != nil {
+				return err
+			}
+
+			if cache != nil {
+				if err := cache.Set(tool, toolResult.Results); err != nil {
+					logging.FromContext(ctx).Warnf("Cannot store cached results for %s: %v", tool.Name(), err)
+				}
+			}
+
+			for _, r := range toolResult.Results {
+				result.AddResult(r)
+			}
+
+			return nil
+		})
+	}
+
+	if err := gr.Wait(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/shyim/go-version"
+)
+
+var (
+	hookNameRegExp    = regexp.MustCompile(`const\s+HOOK_NAME\s*=\s*'([^']+)'`)
+	serviceNameRegExp = regexp.MustCompile(`function\s+getName\(\)\s*:\s*string\s*\{\s*return\s+'([^']+)'`)
+	sinceRegExp       = regexp.MustCompile(`@since\s+(\d+\.\d+\.\d+\.\d+)`)
+	releaseTagRegExp  = regexp.MustCompile(`^v(6\.\d+\.\d+\.\d+)$`)
+)
+
+// Regenerates the app script hooks and services from a git clone of shopware/shopware with its tags:
+//
+//	git clone https://github.com/shopware/shopware.git /tmp/shopware
+//	SHOPWARE_DIR=/tmp/shopware go generate ./extension
+//
+// The first version providing a hook or service is the @since annotation of its class, otherwise the first release
+// containing the class.
+func main() {
+	output := flag.String("output", "extension/app-script-hooks.json", "File to write the hooks to")
+	shopwareDir := flag.String("shopware", os.Getenv("SHOPWARE_DIR"), "Git clone of shopware/shopware")
+	flag.Parse()
+
+	if *shopwareDir == "" {
+		panic("pass the git clone of shopware/shopware with -shopware or SHOPWARE_DIR")
+	}
+
+	hooks := map[string]string{}
+	services := map[string]string{}
+
+	err := filepath.WalkDir(filepath.Join(*shopwareDir, "src"), func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || !strings.HasSuffix(file, ".php") || strings.Contains(file, string(filepath.Separator)+"Test") {
+			return nil
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		if match := hookNameRegExp.FindSubmatch(content); match != nil {
+			hooks[string(match[1])] = since(*shopwareDir, file, content)
+		}
+
+		if strings.Contains(string(content), "extends HookServiceFactory") {
+			if match := serviceNameRegExp.FindSubmatch(content); match != nil {
+				services[string(match[1])] = since(*shopwareDir, file, content)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	if len(hooks) == 0 || len(services) == 0 {
+		panic(fmt.Sprintf("found %d hooks and %d services, is %s a clone of shopware/shopware?", len(hooks), len(services), *shopwareDir))
+	}
+
+	// One entry per line keeps the diffs of updates readable
+	var out strings.Builder
+
+	out.WriteString("{\n  \"hooks\": {\n")
+	writeEntries(&out, hooks)
+	out.WriteString("  },\n  \"services\": {\n")
+	writeEntries(&out, services)
+	out.WriteString("  }\n}\n")
+
+	if err := os.WriteFile(*output, []byte(out.String()), 0o644); err != nil {
+		panic(err)
+	}
+}
+
+func since(shopwareDir, file string, content []byte) string {
+	if match := sinceRegExp.FindSubmatch(content); match != nil {
+		return string(match[1])
+	}
+
+	rel, err := filepath.Rel(shopwareDir, file)
+	if err != nil {
+		panic(err)
+	}
+
+	added := git(shopwareDir, "log", "--follow", "--diff-filter=A", "--format=%H", "--", rel)
+	commits := strings.Fields(added)
+	if len(commits) == 0 {
+		panic(fmt.Sprintf("cannot find the commit adding %s", rel))
+	}
+
+	var first *version.Version
+
+	for _, tag := range strings.Fields(git(shopwareDir, "tag", "--contains", commits[len(commits)-1])) {
+		match := releaseTagRegExp.FindStringSubmatch(tag)
+		if match == nil {
+			continue
+		}
+
+		v := version.Must(version.NewVersion(match[1]))
+		if first == nil || v.LessThan(first) {
+			first = v
+		}
+	}
+
+	if first == nil {
+		panic(fmt.Sprintf("%s is not part of a release yet", rel))
+	}
+
+	return first.String()
+}
+
+func git(dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	out, err := cmd.Output()
+	if err != nil {
+		panic(fmt.Sprintf("git %s: %s", strings.Join(args, " "), err.Error()))
+	}
+
+	return string(out)
+}
+
+func writeEntries(out *strings.Builder, entries map[string]string) {
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for i, name := range names {
+		key, _ := json.Marshal(name)
+		value, _ := json.Marshal(entries[name])
+
+		separator := ","
+		if i == len(names)-1 {
+			separator = ""
+		}
+
+		fmt.Fprintf(out, "    %s: %s%s\n", key, value, separator)
+	}
+}
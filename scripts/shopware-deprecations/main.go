@@ -0,0 +1,340 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/shyim/go-version"
+)
+
+var (
+	releaseTagRegExp    = regexp.MustCompile(`^v(6\.\d+\.\d+\.0)$`)
+	namespaceRegExp     = regexp.MustCompile(`(?m)^namespace\s+([^;\s]+);`)
+	classRegExp         = regexp.MustCompile(`(?m)^(?:(?:final|abstract|readonly)\s+)*(?:class|interface|trait|enum)\s+(\w+)`)
+	deprecatedRegExp    = regexp.MustCompile(`@deprecated\s+tag:v[\d.]+\s*(?:-\s*)?([^\n*]*)`)
+	componentRegExp     = regexp.MustCompile(`Component\.(?:register|extend)\(\s*['"]([a-z0-9-]+)['"]`)
+	replacementRegExp   = regexp.MustCompile(`(?i)\buse\s+(?:the\s+)?(\S+?)\.?(?:\s+instead|\s*$)`)
+	upgradeInsteadRegEx = regexp.MustCompile("`([^`]+)`[^`\n]*\\binstead of\\b[^`\n]*`([^`]+)`")
+	upgradeReplaceRegEx = regexp.MustCompile("(?i)\\breplaced?\\s+`([^`]+)`[^`\n]*\\bwith\\b[^`\n]*`([^`]+)`")
+)
+
+const (
+	kindClass     = "class"
+	kindService   = "service"
+	kindComponent = "component"
+)
+
+type entry struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+type release struct {
+	version    string
+	added      []entry
+	deprecated []entry
+	removed    []entry
+}
+
+// api is the state of a Shopware API in one release, the replacement is only set when it is deprecated
+type api struct {
+	deprecated  bool
+	replacement string
+}
+
+// Regenerates the deprecation database from a git clone of shopware/shopware with its tags:
+//
+//	git clone https://github.com/shopware/shopware.git /tmp/shopware
+//	SHOPWARE_DIR=/tmp/shopware go generate ./internal/deprecation
+//
+// Every minor release is compared with the previous one. Classes come from src/, services from the DI XML files and
+// administration components from their Component.register calls. An API is deprecated with its @deprecated annotation
+// or <deprecated> element, the replacement is taken from the annotation or else from the UPGRADE files.
+func main() {
+	output := flag.String("output", "internal/deprecation/shopware-deprecations.json", "File to write the database to")
+	shopwareDir := flag.String("shopware", os.Getenv("SHOPWARE_DIR"), "Git clone of shopware/shopware")
+	since := flag.String("since", "6.4.0.0", "First release to compare against")
+	flag.Parse()
+
+	if *shopwareDir == "" {
+		panic("pass the git clone of shopware/shopware with -shopware or SHOPWARE_DIR")
+	}
+
+	tags := releaseTags(*shopwareDir, version.Must(version.NewVersion(*since)))
+	if len(tags) < 2 {
+		panic(fmt.Sprintf("found %d releases since %s, is %s a clone of shopware/shopware with tags?", len(tags), *since, *shopwareDir))
+	}
+
+	previous := apis(*shopwareDir, tags[0])
+
+	var releases []release
+
+	for _, tag := range tags[1:] {
+		current := apis(*shopwareDir, tag)
+		replacements := upgradeReplacements(*shopwareDir, tag)
+
+		r := release{version: strings.TrimPrefix(tag, "v")}
+
+		for key, state := range current {
+			kind, name, _ := strings.Cut(key, " ")
+
+			old, existed := previous[key]
+
+			if !existed {
+				r.added = append(r.added, entry{Type: kind, Name: name})
+			}
+
+			if state.deprecated && (!existed || !old.deprecated) {
+				replacement := state.replacement
+				if replacement == "" {
+					replacement = replacements[name]
+				}
+
+				r.deprecated = append(r.deprecated, entry{Type: kind, Name: name, Replacement: replacement})
+			}
+		}
+
+		for key, state := range previous {
+			if _, ok := current[key]; ok {
+				continue
+			}
+
+			kind, name, _ := strings.Cut(key, " ")
+
+			replacement := state.replacement
+			if replacement == "" {
+				replacement = replacements[name]
+			}
+
+			r.removed = append(r.removed, entry{Type: kind, Name: name, Replacement: replacement})
+		}
+
+		if len(r.added)+len(r.deprecated)+len(r.removed) > 0 {
+			releases = append(releases, r)
+		}
+
+		previous = current
+	}
+
+	if err := os.WriteFile(*output, []byte(render(releases)), 0o644); err != nil {
+		panic(err)
+	}
+}
+
+func releaseTags(shopwareDir string, since *version.Version) []string {
+	type tagVersion struct {
+		tag     string
+		version *version.Version
+	}
+
+	var found []tagVersion
+
+	for _, tag := range strings.Fields(git(shopwareDir, "tag", "--list", "v6.*")) {
+		match := releaseTagRegExp.FindStringSubmatch(tag)
+		if match == nil {
+			continue
+		}
+
+		v := version.Must(version.NewVersion(match[1]))
+		if v.LessThan(since) {
+			continue
+		}
+
+		found = append(found, tagVersion{tag: tag, version: v})
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].version.LessThan(found[j].version)
+	})
+
+	tags := make([]string, 0, len(found))
+	for _, f := range found {
+		tags = append(tags, f.tag)
+	}
+
+	return tags
+}
+
+// apis returns the public APIs of a release keyed by "<kind> <name>"
+func apis(shopwareDir, tag string) map[string]api {
+	result := map[string]api{}
+
+	for _, file := range strings.Fields(git(shopwareDir, "ls-tree", "-r", "--name-only", tag, "--", "src")) {
+		switch {
+		case strings.Contains(file, "/Test/") || strings.Contains(file, "/node_modules/"):
+			continue
+		case strings.HasSuffix(file, ".php") && !strings.Contains(file, "/Resources/"):
+			content := git(shopwareDir, "show", tag+":"+file)
+
+			namespace := namespaceRegExp.FindStringSubmatch(content)
+			class := classRegExp.FindStringSubmatchIndex(content)
+			if namespace == nil || class == nil {
+				continue
+			}
+
+			name := namespace[1] + `\` + content[class[2]:class[3]]
+			result[kindClass+" "+name] = docblockState(content[:class[0]])
+		case strings.HasSuffix(file, ".xml") && strings.Contains(file, "/DependencyInjection/"):
+			for id, state := range services(git(shopwareDir, "show", tag+":"+file)) {
+				result[kindService+" "+id] = state
+			}
+		case strings.Contains(file, "/administration/src/") && (strings.HasSuffix(file, "/index.js") || strings.HasSuffix(file, "/index.ts")):
+			content := git(shopwareDir, "show", tag+":"+file)
+
+			for _, match := range componentRegExp.FindAllStringSubmatch(content, -1) {
+				result[kindComponent+" "+match[1]] = docblockState(content)
+			}
+		}
+	}
+
+	return result
+}
+
+// docblockState reads the first @deprecated annotation of the given source
+func docblockState(content string) api {
+	match := deprecatedRegExp.FindStringSubmatch(content)
+	if match == nil {
+		return api{}
+	}
+
+	text := strings.TrimSpace(match[1])
+
+	if use := replacementRegExp.FindStringSubmatch(text); use != nil {
+		text = strings.Trim(use[1], "`\"'")
+	}
+
+	return api{deprecated: true, replacement: text}
+}
+
+func services(content string) map[string]api {
+	var file struct {
+		Services []struct {
+			ID         string `xml:"id,attr"`
+			Deprecated *struct {
+				Message string `xml:",chardata"`
+			} `xml:"deprecated"`
+		} `xml:"services>service"`
+	}
+
+	result := map[string]api{}
+
+	// Files which are no service definitions are skipped
+	if err := xml.Unmarshal([]byte(content), &file); err != nil {
+		return result
+	}
+
+	for _, service := range file.Services {
+		if service.ID == "" {
+			continue
+		}
+
+		state := api{}
+
+		if service.Deprecated != nil {
+			state = docblockState("@deprecated tag:v0 - " + service.Deprecated.Message)
+			state.deprecated = true
+		}
+
+		result[service.ID] = state
+	}
+
+	return result
+}
+
+// upgradeReplacements collects "use `new` instead of `old`" and "replaced `old` with `new`" notes of the UPGRADE files
+func upgradeReplacements(shopwareDir, tag string) map[string]string {
+	result := map[string]string{}
+
+	for _, file := range strings.Fields(git(shopwareDir, "ls-tree", "--name-only", tag)) {
+		if !strings.HasPrefix(file, "UPGRADE-") || !strings.HasSuffix(file, ".md") {
+			continue
+		}
+
+		content := git(shopwareDir, "show", tag+":"+file)
+
+		for _, match := range upgradeInsteadRegEx.FindAllStringSubmatch(content, -1) {
+			result[strings.TrimPrefix(match[2], `\`)] = strings.TrimPrefix(match[1], `\`)
+		}
+
+		for _, match := range upgradeReplaceRegEx.FindAllStringSubmatch(content, -1) {
+			result[strings.TrimPrefix(match[1], `\`)] = strings.TrimPrefix(match[2], `\`)
+		}
+	}
+
+	return result
+}
+
+func git(dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	out, err := cmd.Output()
+	if err != nil {
+		panic(fmt.Sprintf("git %s: %s", strings.Join(args, " "), err.Error()))
+	}
+
+	return string(out)
+}
+
+// render writes one entry per line to keep the diffs of updates readable
+func render(releases []release) string {
+	var out strings.Builder
+
+	out.WriteString("{\n  \"releases\": [\n")
+
+	for i, r := range releases {
+		fmt.Fprintf(&out, "    {\n      \"version\": %q", r.version)
+
+		for _, list := range []struct {
+			name    string
+			entries []entry
+		}{{"added", r.added}, {"deprecated", r.deprecated}, {"removed", r.removed}} {
+			if len(list.entries) == 0 {
+				continue
+			}
+
+			sort.Slice(list.entries, func(a, b int) bool {
+				if list.entries[a].Type != list.entries[b].Type {
+					return list.entries[a].Type < list.entries[b].Type
+				}
+
+				return list.entries[a].Name < list.entries[b].Name
+			})
+
+			fmt.Fprintf(&out, ",\n      %q: [\n", list.name)
+
+			for j, e := range list.entries {
+				line, _ := json.Marshal(e)
+				formatted := strings.NewReplacer(`":`, `": `, `","`, `", "`).Replace(string(line))
+
+				separator := ","
+				if j == len(list.entries)-1 {
+					separator = ""
+				}
+
+				fmt.Fprintf(&out, "        %s%s\n", formatted, separator)
+			}
+
+			out.WriteString("      ]")
+		}
+
+		separator := ","
+		if i == len(releases)-1 {
+			separator = ""
+		}
+
+		fmt.Fprintf(&out, "\n    }%s\n", separator)
+	}
+
+	out.WriteString("  ]\n}\n")
+
+	return out.String()
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shopware/shopware-cli/internal/shopwareversion"
+)
+
+// Regenerates the bundled Shopware versions with all releases of Packagist: go generate ./internal/shopwareversion
+func main() {
+	output := flag.String("output", "internal/shopwareversion/shopware-versions.json", "File to write the versions to")
+	flag.Parse()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	bundled, err := shopwareversion.Bundled()
+	if err != nil {
+		panic(err)
+	}
+
+	metadata, err := shopwareversion.Fetch(ctx, bundled)
+	if err != nil {
+		panic(err)
+	}
+
+	// Fetch falls back to the previous data for the optional sources, the bundled file has to be complete
+	for _, release := range metadata.Releases {
+		if release.PHP == "" {
+			panic(fmt.Sprintf("no PHP version known for Shopware %s, is the php-version.json of shopware-static-data reachable?", release.Version))
+		}
+	}
+
+	for _, line := range metadata.Lines {
+		if line.Released == "" {
+			panic(fmt.Sprintf("no release date known for Shopware %s, is endoflife.date reachable?", line.Version))
+		}
+	}
+
+	// One entry per line keeps the diffs of updates readable
+	var out strings.Builder
+
+	fmt.Fprintf(&out, "{\n  \"updatedAt\": %q,\n  \"lines\": [\n", metadata.UpdatedAt.Format(time.RFC3339))
+	writeEntries(&out, metadata.Lines)
+	out.WriteString("  ],\n  \"releases\": [\n")
+	writeEntries(&out, metadata.Releases)
+	out.WriteString("  ]\n}\n")
+
+	if err := os.WriteFile(*output, []byte(out.String()), 0o644); err != nil {
+		panic(err)
+	}
+}
+
+func writeEntries[T any](out *strings.Builder, entries []T) {
+	for i, entry := range entries {
+		content, err := json.Marshal(entry)
+		if err != nil {
+			panic(err)
+		}
+
+		separator := ","
+		if i == len(entries)-1 {
+			separator = ""
+		}
+
+		fmt.Fprintf(out, "    %s%s\n", strings.ReplaceAll(strings.ReplaceAll(string(content), "\":", "\": "), "\",\"", "\", \""), separator)
+	}
+}
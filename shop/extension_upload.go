@@ -0,0 +1,93 @@
+package shop
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	adminSdk "github.com/friendsofshopware/go-shopware-admin-api-sdk"
+
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// UploadExtensionZip uploads the zip of an extension and refreshes the extension list.
+// Cloud shops accept known extensions only as update.
+func UploadExtensionZip(ctx adminSdk.ApiContext, client *adminSdk.Client, name string, zip io.Reader) error {
+	shopInfo, _, err := client.Info.Info(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot get shop info: %w", err)
+	}
+
+	extensions, _, err := client.ExtensionManager.ListAvailableExtensions(ctx)
+	if err != nil {
+		return err
+	}
+
+	var uploadResponse *http.Response
+
+	if !shopInfo.IsCloudShop() || extensions.GetByName(name) == nil {
+		uploadResponse, err = client.ExtensionManager.UploadExtension(ctx, zip)
+	} else {
+		uploadResponse, err = client.ExtensionManager.UploadExtensionUpdateToCloud(ctx, name, zip)
+	}
+
+	if err != nil {
+		return fmt.Errorf("cannot upload extension: %w", err)
+	}
+
+	if uploadResponse.StatusCode != http.StatusNoContent {
+		str, err := io.ReadAll(uploadResponse.Body)
+		if err != nil {
+			return fmt.Errorf("cannot upload extension update: %w", err)
+		}
+
+		return fmt.Errorf("cannot upload extension update: %s", string(str))
+	}
+
+	if _, err := client.ExtensionManager.Refresh(ctx); err != nil {
+		return fmt.Errorf("cannot refresh extension list: %w", err)
+	}
+
+	logging.FromContext(ctx.Context).Infof("Refreshed extension list")
+
+	return nil
+}
+
+// ActivateUploadedExtension installs, activates and updates the extension, steps which happened already are skipped
+func ActivateUploadedExtension(ctx adminSdk.ApiContext, client *adminSdk.Client, name string) error {
+	extensions, _, err := client.ExtensionManager.ListAvailableExtensions(ctx)
+	if err != nil {
+		return err
+	}
+
+	remoteExtension := extensions.GetByName(name)
+	if remoteExtension == nil {
+		return fmt.Errorf("cannot find %s in the shop after uploading", name)
+	}
+
+	if remoteExtension.InstalledAt == nil {
+		if _, err := client.ExtensionManager.InstallExtension(ctx, remoteExtension.Type, remoteExtension.Name); err != nil {
+			return fmt.Errorf("cannot install extension: %w", err)
+		}
+
+		logging.FromContext(ctx.Context).Infof("Installed %s", name)
+	}
+
+	if !remoteExtension.Active {
+		if _, err := client.ExtensionManager.ActivateExtension(ctx, remoteExtension.Type, remoteExtension.Name); err != nil {
+			return fmt.Errorf("cannot activate extension: %w", err)
+		}
+
+		logging.FromContext(ctx.Context).Infof("Activated %s", name)
+	}
+
+	if remoteExtension.IsUpdateAble() {
+		if _, err := client.ExtensionManager.UpdateExtension(ctx, remoteExtension.Type, remoteExtension.Name); err != nil {
+			return fmt.Errorf("cannot update extension: %w", err)
+		}
+
+		logging.FromContext(ctx.Context).Infof("Updated %s from %s to %s", name, remoteExtension.Version, remoteExtension.LatestVersion)
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package shop
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	adminSdk "github.com/friendsofshopware/go-shopware-admin-api-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newExtensionTestServer(t *testing.T, installed []map[string]any) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var calls []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/oauth/token":
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "token", "expires_in": 3600})
+		case "/api/_info/config":
+			_ = json.NewEncoder(w).Encode(map[string]any{"version": "6.6.0.0"})
+		case "/api/_action/extension/installed":
+			_ = json.NewEncoder(w).Encode(installed)
+		default:
+			mu.Lock()
+			calls = append(calls, r.Method+" "+r.URL.Path)
+			mu.Unlock()
+
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return append([]string{}, calls...)
+	}
+}
+
+func TestActivateUploadedExtension(t *testing.T) {
+	server, calls := newExtensionTestServer(t, []map[string]any{
+		{"name": "FroshTools", "type": "plugin", "active": false, "installedAt": nil, "version": "1.0.0", "latestVersion": "1.0.0"},
+	})
+
+	t.Setenv("SHOPWARE_CLI_API_URL", server.URL)
+	t.Setenv("SHOPWARE_CLI_API_CLIENT_ID", "id")
+	t.Setenv("SHOPWARE_CLI_API_CLIENT_SECRET", "secret")
+
+	client, err := NewShopClient(t.Context(), &Config{})
+	require.NoError(t, err)
+
+	require.NoError(t, ActivateUploadedExtension(adminSdk.NewApiContext(t.Context()), client, "FroshTools"))
+
+	assert.Equal(t, []string{
+		"POST /api/_action/extension/install/plugin/FroshTools",
+		"PUT /api/_action/extension/activate/plugin/FroshTools",
+	}, calls())
+}
+
+func TestActivateUploadedExtensionNotFound(t *testing.T) {
+	server, _ := newExtensionTestServer(t, []map[string]any{})
+
+	t.Setenv("SHOPWARE_CLI_API_URL", server.URL)
+	t.Setenv("SHOPWARE_CLI_API_CLIENT_ID", "id")
+	t.Setenv("SHOPWARE_CLI_API_CLIENT_SECRET", "secret")
+
+	client, err := NewShopClient(t.Context(), &Config{})
+	require.NoError(t, err)
+
+	assert.Error(t, ActivateUploadedExtension(adminSdk.NewApiContext(t.Context()), client, "FroshTools"))
+}